@@ -0,0 +1,75 @@
+-- OPTIONAL: Append-only progress history for support investigations
+-- Apply this migration only in deployments that need GetProgressAsOf.
+-- Every INSERT/UPDATE/DELETE on user_goal_progress appends a snapshot row via trigger,
+-- so all write paths (single, batch, COPY, transactional) are covered without dual-writes.
+-- Keep the table bounded with PruneProgressHistory.
+CREATE TABLE IF NOT EXISTS user_goal_progress_history (
+    history_id BIGSERIAL PRIMARY KEY,
+
+    -- Snapshot of the user_goal_progress row after the change
+    user_id VARCHAR(100) NOT NULL,
+    goal_id VARCHAR(100) NOT NULL,
+    challenge_id VARCHAR(100) NOT NULL,
+    namespace VARCHAR(100) NOT NULL,
+    progress INT NOT NULL,
+    status VARCHAR(20) NOT NULL,
+    completed_at TIMESTAMP NULL,
+    claimed_at TIMESTAMP NULL,
+    created_at TIMESTAMP NOT NULL,
+    updated_at TIMESTAMP NOT NULL,
+    is_active BOOLEAN NOT NULL,
+    assigned_at TIMESTAMP NULL,
+    expires_at TIMESTAMP NULL,
+
+    -- 'I' = insert, 'U' = update, 'D' = delete (row no longer exists from valid_from)
+    operation CHAR(1) NOT NULL,
+
+    -- Time from which this snapshot describes the row (updated_at of the new row, NOW() for deletes)
+    valid_from TIMESTAMP NOT NULL,
+
+    CONSTRAINT check_history_operation CHECK (operation IN ('I', 'U', 'D'))
+);
+
+-- As-of lookups: latest snapshot at or before a timestamp for one user + goal
+CREATE INDEX IF NOT EXISTS idx_user_goal_progress_history_lookup
+ON user_goal_progress_history(user_id, goal_id, valid_from);
+
+-- Retention pruning by age
+CREATE INDEX IF NOT EXISTS idx_user_goal_progress_history_valid_from
+ON user_goal_progress_history(valid_from);
+
+CREATE OR REPLACE FUNCTION record_user_goal_progress_history() RETURNS TRIGGER AS $$
+BEGIN
+    IF TG_OP = 'DELETE' THEN
+        INSERT INTO user_goal_progress_history (
+            user_id, goal_id, challenge_id, namespace, progress, status,
+            completed_at, claimed_at, created_at, updated_at,
+            is_active, assigned_at, expires_at, operation, valid_from
+        ) VALUES (
+            OLD.user_id, OLD.goal_id, OLD.challenge_id, OLD.namespace, OLD.progress, OLD.status,
+            OLD.completed_at, OLD.claimed_at, OLD.created_at, OLD.updated_at,
+            OLD.is_active, OLD.assigned_at, OLD.expires_at, 'D', NOW()
+        );
+        RETURN OLD;
+    END IF;
+
+    INSERT INTO user_goal_progress_history (
+        user_id, goal_id, challenge_id, namespace, progress, status,
+        completed_at, claimed_at, created_at, updated_at,
+        is_active, assigned_at, expires_at, operation, valid_from
+    ) VALUES (
+        NEW.user_id, NEW.goal_id, NEW.challenge_id, NEW.namespace, NEW.progress, NEW.status,
+        NEW.completed_at, NEW.claimed_at, NEW.created_at, NEW.updated_at,
+        NEW.is_active, NEW.assigned_at, NEW.expires_at, LEFT(TG_OP, 1), NEW.updated_at
+    );
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+DROP TRIGGER IF EXISTS trg_user_goal_progress_history ON user_goal_progress;
+CREATE TRIGGER trg_user_goal_progress_history
+AFTER INSERT OR UPDATE OR DELETE ON user_goal_progress
+FOR EACH ROW EXECUTE FUNCTION record_user_goal_progress_history();
+
+COMMENT ON TABLE user_goal_progress_history IS 'Optional append-only snapshots of user_goal_progress for as-of reads';
+COMMENT ON COLUMN user_goal_progress_history.valid_from IS 'Time from which this snapshot describes the row';
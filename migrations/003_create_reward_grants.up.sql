@@ -0,0 +1,25 @@
+-- Create reward_grants table
+-- Records the intent to grant a goal's reward, written in the same transaction that marks
+-- the goal claimed (ClaimAndRecordGrant). A claimed goal therefore always has a grant record
+-- that the claim saga can complete or retry against the reward service.
+CREATE TABLE reward_grants (
+    grant_id BIGSERIAL PRIMARY KEY,
+    user_id VARCHAR(100) NOT NULL,
+    goal_id VARCHAR(100) NOT NULL,
+    namespace VARCHAR(100) NOT NULL,
+    reward_type VARCHAR(20) NOT NULL,
+    reward_id VARCHAR(100) NOT NULL,
+    quantity INT NOT NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'pending',
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP NOT NULL DEFAULT NOW(),
+
+    CONSTRAINT check_grant_status CHECK (status IN ('pending', 'granted', 'failed')),
+    CONSTRAINT check_grant_quantity_positive CHECK (quantity > 0)
+);
+
+-- Lookup of grants for a user's goal (claim saga follow-up, support)
+CREATE INDEX idx_reward_grants_user_goal ON reward_grants(user_id, goal_id);
+
+COMMENT ON TABLE reward_grants IS 'Reward grant intents recorded atomically with goal claims';
+COMMENT ON COLUMN reward_grants.status IS 'pending -> granted | failed';
@@ -0,0 +1,9 @@
+-- Track the UTC day last counted by daily increments
+-- Daily goals used DATE(updated_at) as the last counted day, which breaks when a late-arriving
+-- event for yesterday is written today: updated_at must record the write time, but the event
+-- belongs to yesterday's bucket. Batch increments with OccurredAt set this column to the
+-- event's day; rows where it is NULL fall back to DATE(updated_at AT TIME ZONE 'UTC').
+-- Required by the increment queries; apply before deploying this version.
+ALTER TABLE user_goal_progress ADD COLUMN IF NOT EXISTS last_daily_date DATE NULL;
+
+COMMENT ON COLUMN user_goal_progress.last_daily_date IS 'UTC day last counted by a daily increment (NULL = use DATE(updated_at))';
@@ -0,0 +1,6 @@
+-- Index for challenge-wide participant scans
+-- GetChallengeParticipants keyset-paginates every user's rows of one challenge by
+-- (user_id, goal_id). The existing (user_id, challenge_id) index leads with user_id and
+-- cannot serve a challenge_id-only filter, so without this index each page is a full scan.
+CREATE INDEX IF NOT EXISTS idx_user_goal_progress_challenge_participants
+ON user_goal_progress(challenge_id, user_id, goal_id);
@@ -0,0 +1,47 @@
+-- OPTIONAL: Publish progress changes on the 'goal_progress' NOTIFY channel
+-- Apply this migration only in deployments that consume notifications with
+-- pkg/repository/listen (e.g., websocket gateways pushing progress bars instead of polling).
+-- Rows are announced via trigger, so all write paths (single, batch, COPY, transactional)
+-- are covered. Notifications are delivered when the writing transaction commits; identical
+-- payloads within one transaction are collapsed by PostgreSQL.
+--
+-- Payload: {"user_id": ..., "goal_id": ..., "progress": ..., "status": ...}
+-- NOTIFY payloads must be shorter than 8000 bytes. A larger payload is replaced by
+-- {"user_id": ..., "goal_id": ..., "truncated": true}; consumers re-fetch the row.
+CREATE OR REPLACE FUNCTION notify_user_goal_progress() RETURNS TRIGGER AS $$
+DECLARE
+    payload TEXT;
+BEGIN
+    payload := json_build_object(
+        'user_id', NEW.user_id,
+        'goal_id', NEW.goal_id,
+        'progress', NEW.progress,
+        'status', NEW.status
+    )::TEXT;
+
+    IF octet_length(payload) >= 8000 THEN
+        payload := json_build_object(
+            'user_id', NEW.user_id,
+            'goal_id', NEW.goal_id,
+            'truncated', true
+        )::TEXT;
+    END IF;
+
+    PERFORM pg_notify('goal_progress', payload);
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+DROP TRIGGER IF EXISTS trg_user_goal_progress_notify_insert ON user_goal_progress;
+CREATE TRIGGER trg_user_goal_progress_notify_insert
+AFTER INSERT ON user_goal_progress
+FOR EACH ROW EXECUTE FUNCTION notify_user_goal_progress();
+
+-- Updates that leave progress and status unchanged (e.g., assignment toggles, same-day
+-- daily increments) are not announced
+DROP TRIGGER IF EXISTS trg_user_goal_progress_notify_update ON user_goal_progress;
+CREATE TRIGGER trg_user_goal_progress_notify_update
+AFTER UPDATE ON user_goal_progress
+FOR EACH ROW
+WHEN (OLD.progress IS DISTINCT FROM NEW.progress OR OLD.status IS DISTINCT FROM NEW.status)
+EXECUTE FUNCTION notify_user_goal_progress();
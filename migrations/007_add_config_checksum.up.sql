@@ -0,0 +1,9 @@
+-- Record which goal config last wrote each progress row
+-- Batch writes stamp the SHA-256 of the loaded goal config (InMemoryGoalCache.ConfigChecksum)
+-- when the repository is built with WithConfigChecksum, so support tooling can tell rows
+-- written under a previous config apart after a reload. Rows keep their value when a
+-- write has no checksum; NULL means no checksummed batch write has touched the row.
+-- Optional: repositories without WithConfigChecksum neither read nor write it.
+ALTER TABLE user_goal_progress ADD COLUMN IF NOT EXISTS config_checksum VARCHAR(64) NULL;
+
+COMMENT ON COLUMN user_goal_progress.config_checksum IS 'SHA-256 of the goal config that last batch-wrote the row (NULL = unknown)';
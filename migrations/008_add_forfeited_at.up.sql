@@ -0,0 +1,13 @@
+-- Forfeit rewards that were not claimed within their goal's claim deadline
+-- ForfeitExpiredClaims sets forfeited_at on completed rows whose completed_at is older than
+-- the goal's claim deadline; MarkAsClaimed and GetClaimEligibility then treat the row as no
+-- longer claimable. Forfeited rows keep status 'completed'.
+-- Breaking: see CHANGELOG.md.
+ALTER TABLE user_goal_progress ADD COLUMN IF NOT EXISTS forfeited_at TIMESTAMP NULL;
+
+COMMENT ON COLUMN user_goal_progress.forfeited_at IS 'When the claim deadline passed unclaimed (NULL = not forfeited)';
+
+-- Serves the forfeiture sweep, which scans each goal's unforfeited completed rows by completed_at
+CREATE INDEX IF NOT EXISTS idx_user_goal_progress_claim_deadline
+ON user_goal_progress(goal_id, completed_at)
+WHERE status = 'completed' AND forfeited_at IS NULL;
@@ -0,0 +1,6 @@
+-- Index for namespace enumeration (no-op)
+-- GetNamespaces lists the distinct namespaces for per-tenant maintenance jobs (expiry,
+-- export). It is served by idx_user_goal_progress_feed from migration 011, which leads
+-- with namespace, so this migration creates no index of its own. It is kept so the
+-- migration series stays contiguous.
+SELECT 1;
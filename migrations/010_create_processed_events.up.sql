@@ -0,0 +1,16 @@
+-- OPTIONAL: Processed event IDs for idempotent increments
+-- Apply this migration only in deployments that call IncrementProgressOnce.
+-- Each applied event ID is recorded in the same transaction as its increment, so a
+-- redelivered event (at-least-once delivery) is detected and not counted twice.
+-- Rows are only needed while a replay is possible; delete rows older than the event
+-- source's redelivery window by processed_at to keep the table bounded.
+CREATE TABLE IF NOT EXISTS processed_events (
+    event_id VARCHAR(255) PRIMARY KEY,
+    user_id VARCHAR(100) NOT NULL,
+    goal_id VARCHAR(100) NOT NULL,
+    processed_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+-- Retention deletes scan by age
+CREATE INDEX IF NOT EXISTS idx_processed_events_processed_at
+ON processed_events(processed_at);
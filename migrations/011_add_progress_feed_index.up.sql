@@ -0,0 +1,7 @@
+-- Index for the progress feed
+-- GetProgressUpdatedSince keyset-paginates one namespace by (updated_at, user_id,
+-- goal_id) for write-behind reconciliation and change feeds. Without this index each
+-- page sorts every row of the namespace changed since the start time. The index leads
+-- with namespace, so it also serves the DISTINCT of GetNamespaces.
+CREATE INDEX IF NOT EXISTS idx_user_goal_progress_feed
+ON user_goal_progress(namespace, updated_at, user_id, goal_id);
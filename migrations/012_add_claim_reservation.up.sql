@@ -0,0 +1,19 @@
+-- Two-phase claims: reserve a completed goal while its reward is granted externally
+-- ReserveClaim moves a completed row to 'claiming' until reserved_until. ConfirmClaim
+-- finalizes it to 'claimed' before reserved_until; CancelClaim, or ReleaseExpiredClaimReservations once
+-- reserved_until has passed, returns it to 'completed'. Progress writes skip 'claiming'
+-- rows the same way they skip 'claimed' rows.
+-- Required by the claim reservation methods; apply before deploying this version.
+ALTER TABLE user_goal_progress ADD COLUMN IF NOT EXISTS reserved_until TIMESTAMP NULL;
+
+ALTER TABLE user_goal_progress DROP CONSTRAINT IF EXISTS check_status;
+ALTER TABLE user_goal_progress ADD CONSTRAINT check_status
+    CHECK (status IN ('not_started', 'in_progress', 'completed', 'claiming', 'claimed'));
+
+COMMENT ON COLUMN user_goal_progress.status IS 'not_started -> in_progress -> completed -> [claiming ->] claimed';
+COMMENT ON COLUMN user_goal_progress.reserved_until IS 'When a claim reservation lapses (set only while status = claiming)';
+
+-- Serves the reservation sweep, which scans claiming rows by reserved_until
+CREATE INDEX IF NOT EXISTS idx_user_goal_progress_claim_reservation
+ON user_goal_progress(reserved_until)
+WHERE status = 'claiming';
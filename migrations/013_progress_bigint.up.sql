@@ -0,0 +1,11 @@
+-- OPTIONAL: Store progress as BIGINT for counters that exceed the INT limit
+-- Stats such as total damage dealt or meters traveled pass 2^31 - 1. The repository
+-- queries work with either column type; construct the repository with
+-- WithBigIntProgress(true) after applying this migration so increments clamp at the
+-- BIGINT limit instead of the INT one.
+-- Changing the column type rewrites the table under an ACCESS EXCLUSIVE lock; schedule it
+-- for a maintenance window on large tables.
+ALTER TABLE user_goal_progress ALTER COLUMN progress TYPE BIGINT;
+
+-- Keep the optional history table (migration 002) able to snapshot the wider values
+ALTER TABLE IF EXISTS user_goal_progress_history ALTER COLUMN progress TYPE BIGINT;
@@ -0,0 +1,7 @@
+-- Count attempts at a goal separately from its progress
+-- BatchIncrementProgress adds each entry's AttemptDelta to attempts, so events that do not
+-- advance progress (e.g., a lost match toward "win a ranked match") still count as a try.
+-- Breaking: see CHANGELOG.md.
+ALTER TABLE user_goal_progress ADD COLUMN IF NOT EXISTS attempts INT NOT NULL DEFAULT 0;
+
+COMMENT ON COLUMN user_goal_progress.attempts IS 'Number of attempts recorded through increments, independent of progress';
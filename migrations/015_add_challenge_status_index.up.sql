@@ -0,0 +1,7 @@
+-- Index for per-challenge status scans
+-- GetByChallengeAndStatus keyset-paginates the rows of one challenge in one status (e.g.,
+-- every claimed row, for reward reconciliation) by (user_id, goal_id). The challenge
+-- participants index (migration 005) would read every row of the challenge and filter
+-- the status, so this index leads with the equality columns.
+CREATE INDEX IF NOT EXISTS idx_user_goal_progress_challenge_status
+ON user_goal_progress(namespace, challenge_id, status, user_id, goal_id);
@@ -0,0 +1,7 @@
+-- Index for assignment audits
+-- GetAssignedBetween keyset-paginates a namespace's rows assigned within a window by
+-- (assigned_at, user_id, goal_id), e.g. to verify the onboarding job assigned goals in
+-- the last hour. Rows never assigned are left out of the index.
+CREATE INDEX IF NOT EXISTS idx_user_goal_progress_assigned
+ON user_goal_progress(namespace, assigned_at, user_id, goal_id)
+WHERE assigned_at IS NOT NULL;
@@ -0,0 +1,8 @@
+-- Carry the goal's designer-controlled display order onto each progress row
+-- Assignment writes copy Goal.Order into order_index so user and challenge reads can
+-- ORDER BY order_index without joining the goal config. created_at breaks ties.
+-- Rows assigned before this migration keep order 0 until they are re-inserted.
+-- Breaking: see CHANGELOG.md.
+ALTER TABLE user_goal_progress ADD COLUMN IF NOT EXISTS order_index INT NOT NULL DEFAULT 0;
+
+COMMENT ON COLUMN user_goal_progress.order_index IS 'Display order of the goal (Goal.Order) when the row was assigned';
@@ -0,0 +1,12 @@
+-- Count completions of repeatable goals
+-- IncrementRepeatableProgress adds one per completed repetition, carries the overflow into
+-- progress and re-arms the row, so a "win 3 matches, repeatable" goal completes again
+-- every 3 wins. Single-completion goals keep 0.
+-- Optional: only IncrementRepeatableProgress and repositories built with
+-- WithCompletions use it; apply it before configuring repeatable goals.
+ALTER TABLE user_goal_progress ADD COLUMN IF NOT EXISTS completions INT NOT NULL DEFAULT 0;
+
+ALTER TABLE user_goal_progress DROP CONSTRAINT IF EXISTS check_completions_non_negative;
+ALTER TABLE user_goal_progress ADD CONSTRAINT check_completions_non_negative CHECK (completions >= 0);
+
+COMMENT ON COLUMN user_goal_progress.completions IS 'Number of times a repeatable goal was completed';
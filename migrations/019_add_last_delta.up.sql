@@ -0,0 +1,7 @@
+-- Record the delta of the most recent increment for debugging
+-- With repository.WithLastDelta the increments store the delta they applied next to
+-- updated_at, so a sudden jump in progress can be traced to a single event. Optional:
+-- repositories without WithLastDelta neither read nor write it.
+ALTER TABLE user_goal_progress ADD COLUMN IF NOT EXISTS last_delta INT;
+
+COMMENT ON COLUMN user_goal_progress.last_delta IS 'Delta of the most recent increment that changed progress (diagnostic)';
@@ -0,0 +1,129 @@
+package cache
+
+import (
+	"time"
+	"unsafe"
+
+	"github.com/AccelByte/extend-challenge-common/pkg/config"
+	"github.com/AccelByte/extend-challenge-common/pkg/domain"
+)
+
+// stringInterner deduplicates equal strings while the cache is built, so values repeated
+// across goals (challenge IDs, stat codes, reward IDs, prerequisite IDs) share one
+// backing array instead of keeping the copy the JSON decoder made for every goal.
+// It is discarded once the build finishes.
+type stringInterner map[string]string
+
+// intern returns the canonical instance of v.
+func (s stringInterner) intern(v string) string {
+	if v == "" {
+		return ""
+	}
+	if canonical, ok := s[v]; ok {
+		return canonical
+	}
+	s[v] = v
+	return v
+}
+
+// internGoal replaces the goal's repeated string fields with their canonical instances.
+// Display text is left alone because it is rarely shared between goals; localization
+// keys are interned because templated goals often share them.
+func (s stringInterner) internGoal(goal *domain.Goal) {
+	goal.ID = s.intern(goal.ID)
+	goal.ChallengeID = s.intern(goal.ChallengeID)
+	goal.Type = domain.GoalType(s.intern(string(goal.Type)))
+	goal.EventSource = domain.EventSource(s.intern(string(goal.EventSource)))
+	goal.Requirement.StatCode = s.intern(goal.Requirement.StatCode)
+	goal.Requirement.Operator = s.intern(goal.Requirement.Operator)
+	goal.Reward.Type = s.intern(goal.Reward.Type)
+	goal.Reward.RewardID = s.intern(goal.Reward.RewardID)
+	for i := range goal.Rewards {
+		goal.Rewards[i].Type = s.intern(goal.Rewards[i].Type)
+		goal.Rewards[i].RewardID = s.intern(goal.Rewards[i].RewardID)
+	}
+	for i, prereqID := range goal.Prerequisites {
+		goal.Prerequisites[i] = s.intern(prereqID)
+	}
+	goal.NameKey = s.intern(goal.NameKey)
+	goal.DescriptionKey = s.intern(goal.DescriptionKey)
+	goal.RotationGroup = s.intern(goal.RotationGroup)
+	for i, tag := range goal.Tags {
+		goal.Tags[i] = s.intern(tag)
+	}
+}
+
+// CacheStats describes the configuration held by an InMemoryGoalCache.
+type CacheStats struct {
+	Challenges      int // Number of challenges
+	Goals           int // Number of goals, including disabled goals
+	EnabledGoals    int // Number of goals that receive events
+	StatCodes       int // Distinct stat codes in the stat code index
+	DefaultAssigned int // Goals assigned to new players (see GetGoalsWithDefaultAssigned)
+	RotationGroups  int // Distinct rotation groups
+	Tags            int // Distinct goal tags
+	InternedStrings int // Distinct strings shared across goals
+
+	// ApproxBytes estimates the memory held by the configuration and its indexes:
+	// struct, string and slice sizes plus a fixed per-entry cost for each index map.
+	// It is meant for comparing configs and spotting growth, not for exact accounting.
+	ApproxBytes int64
+
+	// LoadedAt is when this configuration was built. Together with the counts it shows
+	// whether a reload took effect and whether it loaded the expected volume (a sharp drop
+	// in Goals usually means a truncated config file).
+	LoadedAt time.Time
+}
+
+// mapEntryOverhead approximates the per-entry bookkeeping of a Go map beyond its key
+// and value (hash bits, bucket slack at the default load factor).
+const mapEntryOverhead = 16
+
+const (
+	goalSize      = int64(unsafe.Sizeof(domain.Goal{}))
+	challengeSize = int64(unsafe.Sizeof(domain.Challenge{}))
+	rewardSize    = int64(unsafe.Sizeof(domain.Reward{}))
+	stringSize    = int64(unsafe.Sizeof(""))
+	pointerSize   = int64(unsafe.Sizeof(uintptr(0)))
+)
+
+// approxCacheBytes estimates the memory held by cfg once interned into strs and indexed.
+// Interned strings are counted once; display text is counted per goal.
+func approxCacheBytes(cfg *config.Config, strs stringInterner, stats CacheStats) int64 {
+	var total int64
+	for s := range strs {
+		total += int64(len(s))
+	}
+
+	for _, challenge := range cfg.Challenges {
+		total += challengeSize + pointerSize*int64(len(challenge.Goals))
+		total += int64(len(challenge.Name) + len(challenge.Description) + len(challenge.NameKey) + len(challenge.DescriptionKey))
+
+		for _, goal := range challenge.Goals {
+			total += goalSize
+			total += int64(len(goal.Name) + len(goal.Description) + len(goal.NameKey) + len(goal.DescriptionKey))
+			total += rewardSize * int64(len(goal.Rewards))
+			total += stringSize * int64(len(goal.Prerequisites))
+			total += (stringSize + pointerSize) * int64(len(goal.Tags)) // tag strings and tag index entries
+		}
+	}
+
+	// goalsByID, challengeIDs, challengesByID and the stat code index entries
+	total += int64(stats.Goals) * (stringSize + pointerSize + mapEntryOverhead)
+	total += int64(stats.Goals) * (2*stringSize + mapEntryOverhead)
+	total += int64(stats.Challenges) * (stringSize + 2*pointerSize + mapEntryOverhead)
+	total += int64(stats.StatCodes)*(stringSize+3*pointerSize+mapEntryOverhead) + int64(stats.EnabledGoals)*pointerSize
+	total += int64(stats.Tags) * (stringSize + 3*pointerSize + mapEntryOverhead)
+
+	return total
+}
+
+// Stats reports the size of the loaded configuration and its indexes. The counts are taken
+// when the configuration is built, so Stats is a consistent snapshot during a reload.
+// Time complexity: O(1)
+func (c *InMemoryGoalCache) Stats() CacheStats {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	return c.stats
+}
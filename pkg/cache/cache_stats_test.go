@@ -0,0 +1,119 @@
+package cache
+
+import (
+	"io"
+	"log/slog"
+	"os"
+	"strings"
+	"testing"
+	"unsafe"
+)
+
+func TestInMemoryGoalCache_Stats(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	cfg := createTestConfig()
+	disabled := false
+	cfg.Challenges[0].Goals[1].Enabled = &disabled
+	cfg.Challenges[0].Goals[0].DefaultAssigned = true
+
+	stats := NewInMemoryGoalCache(cfg, "/path/to/config.json", logger).Stats()
+
+	if stats.Challenges != 2 || stats.Goals != 3 || stats.EnabledGoals != 2 {
+		t.Errorf("Stats() counts = %+v, want 2 challenges, 3 goals, 2 enabled", stats)
+	}
+	if stats.StatCodes != 1 {
+		t.Errorf("StatCodes = %d, want 1 (only stat_code_1 has enabled goals)", stats.StatCodes)
+	}
+	if stats.InternedStrings == 0 || stats.ApproxBytes <= 0 {
+		t.Errorf("Stats() = %+v, want interned strings and a positive size", stats)
+	}
+	if stats.DefaultAssigned != 1 {
+		t.Errorf("DefaultAssigned = %d, want 1", stats.DefaultAssigned)
+	}
+	if stats.LoadedAt.IsZero() {
+		t.Error("Stats().LoadedAt is zero, want the build time")
+	}
+}
+
+func TestInMemoryGoalCache_InternsRepeatedStrings(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	cfg := createTestConfig()
+
+	// Give each goal its own copy of the shared values, as JSON decoding does
+	for _, challenge := range cfg.Challenges {
+		for _, goal := range challenge.Goals {
+			goal.Requirement.StatCode = strings.Clone(goal.Requirement.StatCode)
+			goal.Requirement.Operator = strings.Clone(goal.Requirement.Operator)
+		}
+	}
+
+	cache := NewInMemoryGoalCache(cfg, "/path/to/config.json", logger)
+	goal1, goal3 := cache.GetGoalByID("goal-1"), cache.GetGoalByID("goal-3")
+
+	if unsafe.StringData(goal1.Requirement.StatCode) != unsafe.StringData(goal3.Requirement.StatCode) {
+		t.Error("equal stat codes do not share a backing array")
+	}
+	if unsafe.StringData(goal1.Requirement.Operator) != unsafe.StringData(goal3.Requirement.Operator) {
+		t.Error("equal operators do not share a backing array")
+	}
+}
+
+func TestInMemoryGoalCache_StatCodeSlicesAreFull(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	cache := NewInMemoryGoalCache(createTestConfig(), "/path/to/config.json", logger)
+
+	goals := cache.GetGoalsByStatCode("stat_code_1")
+	if len(goals) != 2 || cap(goals) != len(goals) {
+		t.Fatalf("GetGoalsByStatCode() len/cap = %d/%d, want 2/2", len(goals), cap(goals))
+	}
+
+	// Lookups share one backing array, and appending to it never writes into the cache
+	again := cache.GetGoalsByStatCode("stat_code_1")
+	if &goals[0] != &again[0] {
+		t.Error("GetGoalsByStatCode() returned a copy, want the shared slice")
+	}
+	_ = append(goals, cache.GetGoalByID("goal-2"))
+	if got := cache.GetGoalsByStatCode("stat_code_1"); len(got) != 2 {
+		t.Errorf("append by caller changed the index: %d goals", len(got))
+	}
+}
+
+func TestInMemoryGoalCache_Reload_SwapsStats(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	tmpFile := createTempConfigFile(t, `{
+		"challenges": [
+			{
+				"challengeId": "challenge-new",
+				"name": "New Challenge",
+				"description": "Description",
+				"goals": [
+					{
+						"goalId": "goal-new",
+						"name": "New Goal",
+						"description": "Description",
+						"type": "absolute",
+						"eventSource": "statistic",
+						"requirement": {"statCode": "new_stat", "operator": ">=", "targetValue": 100},
+						"reward": {"type": "ITEM", "rewardId": "new_item", "quantity": 1},
+						"prerequisites": []
+					}
+				]
+			}
+		]
+	}`)
+	defer func() { _ = os.Remove(tmpFile) }()
+
+	cache := NewInMemoryGoalCache(createTestConfig(), tmpFile, logger)
+	loadedAt := cache.Stats().LoadedAt
+	if err := cache.Reload(); err != nil {
+		t.Fatalf("Reload() unexpected error = %v", err)
+	}
+
+	stats := cache.Stats()
+	if stats.Challenges != 1 || stats.Goals != 1 || stats.StatCodes != 1 {
+		t.Errorf("Stats() after reload = %+v, want 1 challenge, 1 goal, 1 stat code", stats)
+	}
+	if stats.LoadedAt.Before(loadedAt) {
+		t.Errorf("Stats().LoadedAt = %v after reload, want at or after %v", stats.LoadedAt, loadedAt)
+	}
+}
@@ -0,0 +1,218 @@
+// Package debughttp serves a read-only JSON view of the goal configuration a cache is
+// currently serving, for answering "what config is this pod running?" without reading
+// the file on disk (which does not reflect a failed reload).
+//
+// Endpoints (GET only):
+//
+//	/config/version           version, checksum and last reload time/status
+//	/config/challenges        challenge summaries
+//	/config/goals/{id}        a goal as configured
+//	/config/stat-codes/{code} the goals routed for a stat code
+//	/cache                    counts of the loaded config, for alerting on a short reload
+//
+// Mount the handler on an internal port or behind authentication: it exposes the whole
+// goal configuration, including reward IDs. To serve it under a prefix, strip the prefix:
+//
+//	mux.Handle("/debug/", http.StripPrefix("/debug", debughttp.NewHandler(goalCache)))
+package debughttp
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/AccelByte/extend-challenge-common/pkg/cache"
+	"github.com/AccelByte/extend-challenge-common/pkg/domain"
+)
+
+// checksummer is implemented by caches that expose their config checksum.
+type checksummer interface {
+	ConfigChecksum() string
+}
+
+// reloadReporter is implemented by caches that track reloads (see cache.InMemoryGoalCache).
+type reloadReporter interface {
+	ReloadStatus() cache.ReloadStatus
+}
+
+// statsReporter is implemented by caches that count their configuration (see
+// cache.InMemoryGoalCache).
+type statsReporter interface {
+	Stats() cache.CacheStats
+}
+
+// Reload status values reported by /config/version.
+const (
+	reloadStatusNever  = "never"
+	reloadStatusOK     = "ok"
+	reloadStatusFailed = "failed"
+)
+
+// versionResponse is the body of /config/version. Fields a cache cannot report are omitted.
+type versionResponse struct {
+	Version          int        `json:"version,omitempty"`
+	Checksum         string     `json:"checksum,omitempty"`
+	LoadedAt         *time.Time `json:"loadedAt,omitempty"`
+	LastReloadAt     *time.Time `json:"lastReloadAt,omitempty"`
+	LastReloadStatus string     `json:"lastReloadStatus,omitempty"`
+	LastReloadError  string     `json:"lastReloadError,omitempty"`
+}
+
+// cacheStatsResponse is the body of /cache.
+type cacheStatsResponse struct {
+	Challenges      int        `json:"challenges"`
+	Goals           int        `json:"goals"`
+	EnabledGoals    int        `json:"enabledGoals"`
+	DefaultAssigned int        `json:"defaultAssigned"`
+	StatCodes       int        `json:"statCodes"`
+	ApproxBytes     int64      `json:"approxBytes"`
+	LoadedAt        *time.Time `json:"loadedAt,omitempty"`
+}
+
+// challengeSummary is one entry of /config/challenges.
+type challengeSummary struct {
+	ID           string   `json:"challengeId"`
+	Name         string   `json:"name"`
+	GoalCount    int      `json:"goalCount"`
+	EnabledGoals int      `json:"enabledGoals"`
+	GoalIDs      []string `json:"goalIds"`
+}
+
+// statCodeResponse is the body of /config/stat-codes/{code}.
+type statCodeResponse struct {
+	StatCode string         `json:"statCode"`
+	Goals    []*domain.Goal `json:"goals"`
+}
+
+// errorResponse is the body of every non-200 response.
+type errorResponse struct {
+	Error string `json:"error"`
+}
+
+type handler struct {
+	cache cache.GoalCache
+}
+
+// NewHandler returns a read-only handler for goalCache. Every request reads through the
+// cache's own accessors, so responses stay consistent while the cache reloads.
+func NewHandler(goalCache cache.GoalCache) http.Handler {
+	h := &handler{cache: goalCache}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /config/version", h.version)
+	mux.HandleFunc("GET /config/challenges", h.challenges)
+	mux.HandleFunc("GET /config/goals/{id}", h.goal)
+	mux.HandleFunc("GET /config/stat-codes/{code}", h.statCode)
+	mux.HandleFunc("GET /cache", h.stats)
+	mux.HandleFunc("GET /", func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, http.StatusNotFound, errorResponse{Error: fmt.Sprintf("no debug endpoint at %s", r.URL.Path)})
+	})
+
+	return mux
+}
+
+func (h *handler) version(w http.ResponseWriter, _ *http.Request) {
+	var resp versionResponse
+
+	if c, ok := h.cache.(checksummer); ok {
+		resp.Checksum = c.ConfigChecksum()
+	}
+	if c, ok := h.cache.(reloadReporter); ok {
+		status := c.ReloadStatus()
+		resp.Version = status.Version
+		resp.Checksum = status.Checksum
+		resp.LoadedAt = timePtr(status.LoadedAt)
+		resp.LastReloadAt = timePtr(status.LastReloadAt)
+		switch {
+		case status.LastReloadAt.IsZero():
+			resp.LastReloadStatus = reloadStatusNever
+		case status.LastReloadError != "":
+			resp.LastReloadStatus = reloadStatusFailed
+			resp.LastReloadError = status.LastReloadError
+		default:
+			resp.LastReloadStatus = reloadStatusOK
+		}
+	}
+
+	writeJSON(w, http.StatusOK, resp)
+}
+
+func (h *handler) challenges(w http.ResponseWriter, _ *http.Request) {
+	challenges := h.cache.GetAllChallenges()
+
+	summaries := make([]challengeSummary, 0, len(challenges))
+	for _, challenge := range challenges {
+		summary := challengeSummary{
+			ID:        challenge.ID,
+			Name:      challenge.Name,
+			GoalCount: len(challenge.Goals),
+			GoalIDs:   make([]string, 0, len(challenge.Goals)),
+		}
+		for _, goal := range challenge.Goals {
+			summary.GoalIDs = append(summary.GoalIDs, goal.ID)
+			if goal.IsEnabled() {
+				summary.EnabledGoals++
+			}
+		}
+		summaries = append(summaries, summary)
+	}
+
+	writeJSON(w, http.StatusOK, summaries)
+}
+
+func (h *handler) goal(w http.ResponseWriter, r *http.Request) {
+	id := r.PathValue("id")
+	goal := h.cache.GetGoalByID(id)
+	if goal == nil {
+		writeJSON(w, http.StatusNotFound, errorResponse{Error: fmt.Sprintf("goal '%s' not found", id)})
+		return
+	}
+
+	writeJSON(w, http.StatusOK, goal)
+}
+
+func (h *handler) statCode(w http.ResponseWriter, r *http.Request) {
+	code := r.PathValue("code")
+	goals := h.cache.GetGoalsByStatCode(code)
+	if len(goals) == 0 {
+		writeJSON(w, http.StatusNotFound, errorResponse{Error: fmt.Sprintf("no goals routed for stat code '%s'", code)})
+		return
+	}
+
+	writeJSON(w, http.StatusOK, statCodeResponse{StatCode: code, Goals: goals})
+}
+
+func (h *handler) stats(w http.ResponseWriter, _ *http.Request) {
+	c, ok := h.cache.(statsReporter)
+	if !ok {
+		writeJSON(w, http.StatusNotFound, errorResponse{Error: "cache does not report stats"})
+		return
+	}
+
+	stats := c.Stats()
+	writeJSON(w, http.StatusOK, cacheStatsResponse{
+		Challenges:      stats.Challenges,
+		Goals:           stats.Goals,
+		EnabledGoals:    stats.EnabledGoals,
+		DefaultAssigned: stats.DefaultAssigned,
+		StatCodes:       stats.StatCodes,
+		ApproxBytes:     stats.ApproxBytes,
+		LoadedAt:        timePtr(stats.LoadedAt),
+	})
+}
+
+// writeJSON writes body as the JSON response with the given status.
+func writeJSON(w http.ResponseWriter, status int, body interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(body)
+}
+
+// timePtr returns nil for the zero time so it is omitted from the response.
+func timePtr(t time.Time) *time.Time {
+	if t.IsZero() {
+		return nil
+	}
+	return &t
+}
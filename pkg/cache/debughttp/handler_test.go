@@ -0,0 +1,249 @@
+package debughttp
+
+import (
+	"encoding/json"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/AccelByte/extend-challenge-common/pkg/cache"
+	"github.com/AccelByte/extend-challenge-common/pkg/config"
+	"github.com/AccelByte/extend-challenge-common/pkg/domain"
+)
+
+// goalJSON returns a statistic goal of challengeID tracking statCode.
+func goalJSON(goalID, challengeID, statCode string) string {
+	return `{
+		"goalId": "` + goalID + `",
+		"name": "Goal ` + goalID + `",
+		"description": "Description",
+		"challengeId": "` + challengeID + `",
+		"type": "absolute",
+		"eventSource": "statistic",
+		"requirement": {"statCode": "` + statCode + `", "operator": ">=", "targetValue": 10},
+		"reward": {"type": "ITEM", "rewardId": "item_1", "quantity": 1},
+		"prerequisites": []
+	}`
+}
+
+// configJSON returns a single-challenge config with the given goals.
+func configJSON(challengeID string, goals ...string) string {
+	return `{"challenges": [{
+		"challengeId": "` + challengeID + `",
+		"name": "Challenge ` + challengeID + `",
+		"description": "Description",
+		"goals": [` + strings.Join(goals, ",") + `]
+	}]}`
+}
+
+func writeConfig(t *testing.T, path, content string) {
+	t.Helper()
+	if err := os.WriteFile(path, []byte(content), 0600); err != nil {
+		t.Fatalf("failed to write config: %v", err)
+	}
+}
+
+// newTestCache loads a cache from a temp config file and returns it with the file path.
+func newTestCache(t *testing.T) (*cache.InMemoryGoalCache, string) {
+	t.Helper()
+
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	path := filepath.Join(t.TempDir(), "challenges.json")
+	writeConfig(t, path, configJSON("winter",
+		goalJSON("kill-10", "winter", "kills"),
+		goalJSON("kill-50", "winter", "kills"),
+		goalJSON("win-5", "winter", "wins"),
+	))
+
+	cfg, err := config.NewConfigLoader(path, logger).LoadConfig()
+	if err != nil {
+		t.Fatalf("LoadConfig failed: %v", err)
+	}
+	return cache.NewInMemoryGoalCache(cfg, path, logger), path
+}
+
+// get serves path and decodes the JSON body into out.
+func get(t *testing.T, h http.Handler, path string, out interface{}) int {
+	t.Helper()
+
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, path, nil))
+	if ct := rec.Header().Get("Content-Type"); ct != "application/json" {
+		t.Errorf("GET %s Content-Type = %q, want application/json", path, ct)
+	}
+	if out != nil {
+		if err := json.Unmarshal(rec.Body.Bytes(), out); err != nil {
+			t.Fatalf("GET %s returned invalid JSON %q: %v", path, rec.Body.String(), err)
+		}
+	}
+	return rec.Code
+}
+
+func TestHandler_Version(t *testing.T) {
+	c, path := newTestCache(t)
+	h := NewHandler(c)
+
+	var resp versionResponse
+	if code := get(t, h, "/config/version", &resp); code != http.StatusOK {
+		t.Fatalf("status = %d, want 200", code)
+	}
+	if resp.Version != 1 || resp.Checksum != c.ConfigChecksum() || resp.LoadedAt == nil {
+		t.Errorf("version = %+v, want version 1 with the cache checksum", resp)
+	}
+	if resp.LastReloadStatus != reloadStatusNever || resp.LastReloadAt != nil {
+		t.Errorf("last reload = %q at %v, want never", resp.LastReloadStatus, resp.LastReloadAt)
+	}
+
+	// A failed reload is reported while the previous config keeps serving
+	writeConfig(t, path, `{"challenges": [`)
+	if err := c.Reload(); err == nil {
+		t.Fatal("Reload of invalid JSON should fail")
+	}
+	get(t, h, "/config/version", &resp)
+	if resp.LastReloadStatus != reloadStatusFailed || resp.LastReloadError == "" || resp.Version != 1 {
+		t.Errorf("after failed reload = %+v, want failed status with version 1", resp)
+	}
+}
+
+func TestHandler_Challenges(t *testing.T) {
+	c, _ := newTestCache(t)
+
+	var resp []challengeSummary
+	if code := get(t, NewHandler(c), "/config/challenges", &resp); code != http.StatusOK {
+		t.Fatalf("status = %d, want 200", code)
+	}
+	if len(resp) != 1 || resp[0].ID != "winter" || resp[0].GoalCount != 3 || resp[0].EnabledGoals != 3 {
+		t.Errorf("challenges = %+v", resp)
+	}
+}
+
+func TestHandler_Goal(t *testing.T) {
+	c, _ := newTestCache(t)
+	h := NewHandler(c)
+
+	var goal domain.Goal
+	if code := get(t, h, "/config/goals/kill-50", &goal); code != http.StatusOK {
+		t.Fatalf("status = %d, want 200", code)
+	}
+	if goal.ID != "kill-50" || goal.Requirement.StatCode != "kills" {
+		t.Errorf("goal = %+v", goal)
+	}
+
+	var errResp errorResponse
+	if code := get(t, h, "/config/goals/missing", &errResp); code != http.StatusNotFound {
+		t.Errorf("unknown goal status = %d, want 404", code)
+	}
+	if !strings.Contains(errResp.Error, "missing") {
+		t.Errorf("error = %q, want it to name the goal", errResp.Error)
+	}
+}
+
+func TestHandler_StatCode(t *testing.T) {
+	c, _ := newTestCache(t)
+	h := NewHandler(c)
+
+	var resp statCodeResponse
+	if code := get(t, h, "/config/stat-codes/kills", &resp); code != http.StatusOK {
+		t.Fatalf("status = %d, want 200", code)
+	}
+	if resp.StatCode != "kills" || len(resp.Goals) != 2 {
+		t.Errorf("stat code = %+v, want the two kills goals", resp)
+	}
+
+	var errResp errorResponse
+	if code := get(t, h, "/config/stat-codes/deaths", &errResp); code != http.StatusNotFound {
+		t.Errorf("unknown stat code status = %d, want 404", code)
+	}
+}
+
+func TestHandler_CacheStats(t *testing.T) {
+	c, _ := newTestCache(t)
+	h := NewHandler(c)
+
+	var resp cacheStatsResponse
+	if code := get(t, h, "/cache", &resp); code != http.StatusOK {
+		t.Fatalf("status = %d, want 200", code)
+	}
+	if resp.Challenges != 1 || resp.Goals != 3 || resp.EnabledGoals != 3 || resp.StatCodes != 2 || resp.DefaultAssigned != 0 {
+		t.Errorf("stats = %+v, want 1 challenge, 3 goals, 2 stat codes", resp)
+	}
+	if resp.LoadedAt == nil || !resp.LoadedAt.Equal(c.ReloadStatus().LoadedAt) {
+		t.Errorf("loadedAt = %v, want the cache load time %v", resp.LoadedAt, c.ReloadStatus().LoadedAt)
+	}
+}
+
+func TestHandler_ReadOnly(t *testing.T) {
+	c, _ := newTestCache(t)
+	h := NewHandler(c)
+
+	for _, method := range []string{http.MethodPost, http.MethodPut, http.MethodDelete} {
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, httptest.NewRequest(method, "/config/goals/kill-10", nil))
+		if rec.Code != http.StatusMethodNotAllowed {
+			t.Errorf("%s status = %d, want 405", method, rec.Code)
+		}
+	}
+
+	var errResp errorResponse
+	if code := get(t, h, "/config/reload", &errResp); code != http.StatusNotFound {
+		t.Errorf("unknown endpoint status = %d, want 404", code)
+	}
+}
+
+func TestHandler_AfterReload(t *testing.T) {
+	c, path := newTestCache(t)
+	h := NewHandler(c)
+	oldChecksum := c.ConfigChecksum()
+
+	writeConfig(t, path, configJSON("spring", goalJSON("bloom-3", "spring", "flowers")))
+	if err := c.Reload(); err != nil {
+		t.Fatalf("Reload failed: %v", err)
+	}
+
+	var goal domain.Goal
+	if code := get(t, h, "/config/goals/bloom-3", &goal); code != http.StatusOK || goal.ChallengeID != "spring" {
+		t.Errorf("reloaded goal = %d %+v", code, goal)
+	}
+	if code := get(t, h, "/config/goals/kill-10", nil); code != http.StatusNotFound {
+		t.Errorf("removed goal status = %d, want 404", code)
+	}
+	if code := get(t, h, "/config/stat-codes/kills", nil); code != http.StatusNotFound {
+		t.Errorf("removed stat code status = %d, want 404", code)
+	}
+
+	var resp versionResponse
+	get(t, h, "/config/version", &resp)
+	if resp.Version != 2 || resp.Checksum == oldChecksum || resp.LastReloadStatus != reloadStatusOK {
+		t.Errorf("version after reload = %+v", resp)
+	}
+}
+
+func TestHandler_ConcurrentReload(t *testing.T) {
+	c, _ := newTestCache(t)
+	h := NewHandler(c)
+
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for range 20 {
+			_ = c.Reload()
+		}
+	}()
+
+	paths := []string{"/config/version", "/config/challenges", "/config/goals/kill-10", "/config/stat-codes/kills", "/cache"}
+	for i := range 200 {
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, paths[i%len(paths)], nil))
+		if rec.Code != http.StatusOK {
+			t.Errorf("GET %s status = %d during reload", paths[i%len(paths)], rec.Code)
+		}
+	}
+	wg.Wait()
+}
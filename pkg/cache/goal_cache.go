@@ -11,17 +11,38 @@ type GoalCache interface {
 	// Time complexity: O(1)
 	GetGoalByID(goalID string) *domain.Goal
 
-	// GetGoalsByStatCode retrieves all goals that track a specific stat code.
+	// GetGoalsByStatCode retrieves all enabled goals that track a specific stat code.
 	// Multiple goals can track the same stat (e.g., multiple challenges tracking "login_count").
+	// Disabled goals are excluded so no new progress accrues for them.
 	// Returns empty slice if no goals track this stat.
 	// Time complexity: O(1)
 	GetGoalsByStatCode(statCode string) []*domain.Goal
 
+	// GetAllStatCodes retrieves the stat codes tracked by at least one enabled goal, sorted,
+	// e.g. to decide which statistic topics an event consumer subscribes to.
+	// Time complexity: O(n) where n is the number of stat codes
+	GetAllStatCodes() []string
+
+	// GetStatCodeSummary retrieves the goal count, highest target and daily flag of the
+	// enabled goals tracking each stat code, computed when the cache is built.
+	// Time complexity: O(n) where n is the number of stat codes
+	GetStatCodeSummary() map[string]StatCodeInfo
+
 	// GetChallengeByChallengeID retrieves a challenge by its unique ID.
 	// Returns nil if challenge does not exist.
 	// Time complexity: O(1)
 	GetChallengeByChallengeID(challengeID string) *domain.Challenge
 
+	// GetChallengeForGoal retrieves the challenge that contains a goal.
+	// Returns nil if the goal does not exist.
+	// Time complexity: O(1)
+	GetChallengeForGoal(goalID string) *domain.Challenge
+
+	// GetChallengeIDForGoal resolves the ID of the challenge that contains a goal,
+	// e.g. for a progress row. Returns false if the goal does not exist.
+	// Time complexity: O(1)
+	GetChallengeIDForGoal(goalID string) (string, bool)
+
 	// GetAllChallenges retrieves all configured challenges.
 	// Returns all challenges in the order they appear in the config file.
 	// Time complexity: O(1)
@@ -33,12 +54,58 @@ type GoalCache interface {
 	// Time complexity: O(n) where n is total number of goals
 	GetAllGoals() []*domain.Goal
 
-	// M3: GetGoalsWithDefaultAssigned retrieves all goals that have default_assigned = true.
+	// M3: GetGoalsWithDefaultAssigned retrieves all enabled goals that have default_assigned = true.
 	// Used by initialization endpoint to determine which goals to assign to new players.
 	// Returns empty slice if no goals are marked as default assigned.
 	// Time complexity: O(n) where n is total number of goals
 	GetGoalsWithDefaultAssigned() []*domain.Goal
 
+	// GetGoalsWithDefaultAssignedByDifficulty retrieves the same goals as
+	// GetGoalsWithDefaultAssigned ordered easy -> normal -> hard (config order within a
+	// tier), so onboarding can hand out easy goals first.
+	// Returns empty slice if no goals are marked as default assigned.
+	// Time complexity: O(1)
+	GetGoalsWithDefaultAssignedByDifficulty() []*domain.Goal
+
+	// IsGoalEnabled returns true if the goal exists and is not disabled.
+	// Disabled goals remain resolvable by GetGoalByID for hydration and reward lookups.
+	// Time complexity: O(1)
+	IsGoalEnabled(goalID string) bool
+
+	// M4: GetGoalsByRotationGroup retrieves all enabled goals of a rotation group across
+	// every week, ordered by week and then by config order.
+	// Returns empty slice if the group does not exist.
+	// Time complexity: O(n) where n is the number of goals in the group
+	GetGoalsByRotationGroup(group string) []*domain.Goal
+
+	// M4: GetGoalsForRotationWeek retrieves the enabled goals offered in one week of a
+	// rotation group (see domain.CurrentRotationWeek). These are the selection
+	// candidates for that week.
+	// Returns empty slice if the group or week has no goals.
+	// Time complexity: O(1)
+	GetGoalsForRotationWeek(group string, week int) []*domain.Goal
+
+	// GetGoalsByTag retrieves all goals carrying a tag (e.g., "pvp"), in config order,
+	// so clients can filter their board. Disabled goals are included; check IsGoalEnabled
+	// where only live goals should appear.
+	// Returns empty slice if no goal has the tag.
+	// Time complexity: O(1)
+	GetGoalsByTag(tag string) []*domain.Goal
+
+	// GetGoalsByRewardID retrieves all goals granting a reward ID (item code or currency
+	// code) through any element of their rewards, in config order. Disabled goals are
+	// included.
+	// Returns empty slice if no goal grants the reward.
+	// Time complexity: O(1)
+	GetGoalsByRewardID(rewardID string) []*domain.Goal
+
+	// GetGoalsByDifficulty retrieves the enabled goals of a difficulty tier ("easy",
+	// "normal" or "hard"), in config order. Goals without a difficulty count as "normal".
+	// Feed the result to domain.SelectRandomGoals for difficulty-weighted selection.
+	// Returns empty slice for an unknown tier or a tier without goals.
+	// Time complexity: O(1)
+	GetGoalsByDifficulty(d string) []*domain.Goal
+
 	// Reload reloads the cache from the config file.
 	// In M1, this requires application restart (config is baked into Docker image).
 	// Returns error if config file cannot be read or is invalid.
@@ -1,8 +1,14 @@
 package cache
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
 	"log/slog"
+	"slices"
+	"sort"
 	"sync"
+	"time"
 
 	"github.com/AccelByte/extend-challenge-common/pkg/config"
 	"github.com/AccelByte/extend-challenge-common/pkg/domain"
@@ -12,12 +18,27 @@ import (
 // All maps are built at startup and provide thread-safe read access.
 // This cache is immutable after construction (reload requires application restart in M1).
 type InMemoryGoalCache struct {
-	goalsByID       map[string]*domain.Goal      // "goal-id" -> Goal
-	goalsByStatCode map[string][]*domain.Goal    // "stat_code" -> [Goals]
-	challengesByID  map[string]*domain.Challenge // "challenge-id" -> Challenge
-	challenges      []*domain.Challenge          // All challenges (ordered)
-	configPath      string                       // Path to config file (for reload)
-	mu              sync.RWMutex                 // Protects all maps
+	goalsByID       map[string]*domain.Goal           // "goal-id" -> Goal
+	goalsByStatCode map[string][]*domain.Goal         // "stat_code" -> [Goals]
+	goalsByRotation map[string]map[int][]*domain.Goal // "rotation_group" -> week -> [Goals]
+	goalsByTag      map[string][]*domain.Goal         // "tag" -> [Goals], including disabled goals
+	goalsByTier     map[string][]*domain.Goal         // "difficulty" -> [Goals]
+	goalsByReward   map[string][]*domain.Goal         // "reward-id" -> [Goals], every reward of each goal
+	statCodeSummary map[string]StatCodeInfo           // "stat_code" -> aggregate of goalsByStatCode
+	statCodes       []string                          // Keys of goalsByStatCode, sorted
+	defaultAssigned []*domain.Goal                    // Default-assigned goals, easy -> normal -> hard
+	challengeIDs    map[string]string                 // "goal-id" -> "challenge-id"
+	challengesByID  map[string]*domain.Challenge      // "challenge-id" -> Challenge
+	challenges      []*domain.Challenge               // All challenges (ordered)
+	configPath      string                            // Path to config file (for reload)
+	checksum        string                            // SHA-256 of the loaded config (see ConfigChecksum)
+	stats           CacheStats                        // Sizes of the loaded config (see Stats)
+	version         int                               // Configurations built so far (see ReloadStatus)
+	loadedAt        time.Time                         // When the serving config was built
+	lastReloadAt    time.Time                         // When Reload last ran
+	lastReloadError string                            // Error of the last Reload, "" on success
+	reloadHooks     []func(ReloadSummary)             // Run after each successful Reload (see OnReload)
+	mu              sync.RWMutex                      // Protects all maps
 	logger          *slog.Logger
 }
 
@@ -35,6 +56,12 @@ func NewInMemoryGoalCache(cfg *config.Config, configPath string, logger *slog.Lo
 	cache := &InMemoryGoalCache{
 		goalsByID:       make(map[string]*domain.Goal),
 		goalsByStatCode: make(map[string][]*domain.Goal),
+		goalsByRotation: make(map[string]map[int][]*domain.Goal),
+		goalsByTag:      make(map[string][]*domain.Goal),
+		goalsByTier:     make(map[string][]*domain.Goal),
+		goalsByReward:   make(map[string][]*domain.Goal),
+		statCodeSummary: make(map[string]StatCodeInfo),
+		challengeIDs:    make(map[string]string),
 		challengesByID:  make(map[string]*domain.Challenge),
 		challenges:      make([]*domain.Challenge, 0, len(cfg.Challenges)),
 		configPath:      configPath,
@@ -48,40 +75,207 @@ func NewInMemoryGoalCache(cfg *config.Config, configPath string, logger *slog.Lo
 
 // buildCache constructs all cache indexes from the configuration.
 // This method is called during construction and reload.
-// It replaces all existing cache data.
+// The new indexes are built without holding the lock and swapped in together, so
+// readers see either the previous configuration or the new one, never a mix.
 func (c *InMemoryGoalCache) buildCache(cfg *config.Config) {
-	c.mu.Lock()
-	defer c.mu.Unlock()
+	goalCount := 0
+	for _, challenge := range cfg.Challenges {
+		goalCount += len(challenge.Goals)
+	}
 
-	// Clear existing cache
-	c.goalsByID = make(map[string]*domain.Goal)
-	c.goalsByStatCode = make(map[string][]*domain.Goal)
-	c.challengesByID = make(map[string]*domain.Challenge)
-	c.challenges = make([]*domain.Challenge, 0, len(cfg.Challenges))
+	goalsByID := make(map[string]*domain.Goal, goalCount)
+	goalsByRotation := make(map[string]map[int][]*domain.Goal)
+	goalsByTag := make(map[string][]*domain.Goal)
+	goalsByTier := make(map[string][]*domain.Goal, len(domain.Difficulties()))
+	goalsByReward := make(map[string][]*domain.Goal)
+	var defaultAssignedGoals []*domain.Goal
+	challengeIDs := make(map[string]string, goalCount)
+	challengesByID := make(map[string]*domain.Challenge, len(cfg.Challenges))
+	challenges := make([]*domain.Challenge, 0, len(cfg.Challenges))
+	strs := make(stringInterner)
+
+	// Count goals per stat code first so every index slice is allocated once at its
+	// final size, leaving no spare capacity for a caller's append to write into
+	statCodeCounts := make(map[string]int)
+	for _, challenge := range cfg.Challenges {
+		for _, goal := range challenge.Goals {
+			if goal.IsEnabled() {
+				statCodeCounts[goal.Requirement.StatCode]++
+			}
+		}
+	}
+	goalsByStatCode := make(map[string][]*domain.Goal, len(statCodeCounts))
+	for statCode, count := range statCodeCounts {
+		goalsByStatCode[strs.intern(statCode)] = make([]*domain.Goal, 0, count)
+	}
 
 	// Build indexes
+	disabled := 0
 	for _, challenge := range cfg.Challenges {
+		challenge.ID = strs.intern(challenge.ID)
+
 		// Index challenge by ID
-		c.challengesByID[challenge.ID] = challenge
-		c.challenges = append(c.challenges, challenge)
+		challengesByID[challenge.ID] = challenge
+		challenges = append(challenges, challenge)
 
+		defaultAssigned := 0
 		for _, goal := range challenge.Goals {
+			// Normalize ChallengeID to the parent challenge so goal -> challenge lookups never diverge
+			goal.ChallengeID = challenge.ID
+			strs.internGoal(goal)
+
 			// Index goal by ID
-			c.goalsByID[goal.ID] = goal
+			goalsByID[goal.ID] = goal
+			challengeIDs[goal.ID] = challenge.ID
+
+			// Index goal by tag. Disabled goals are kept so boards that still show them
+			// (e.g., with earlier progress) can filter them too.
+			for i, tag := range goal.Tags {
+				if !slices.Contains(goal.Tags[:i], tag) {
+					goalsByTag[tag] = append(goalsByTag[tag], goal)
+				}
+			}
+
+			// Index goal by every reward it grants, once per reward ID. Disabled goals are
+			// kept like in the tag index.
+			rewards := goal.AllRewards()
+			for i, reward := range rewards {
+				if !slices.ContainsFunc(rewards[:i], func(r domain.Reward) bool { return r.RewardID == reward.RewardID }) {
+					goalsByReward[reward.RewardID] = append(goalsByReward[reward.RewardID], goal)
+				}
+			}
 
-			// Index goal by stat code (multiple goals can track same stat)
+			// Index goal by stat code (multiple goals can track same stat).
+			// Disabled goals stay in goalsByID but receive no new events.
+			if !goal.IsEnabled() {
+				disabled++
+				continue
+			}
 			statCode := goal.Requirement.StatCode
-			c.goalsByStatCode[statCode] = append(c.goalsByStatCode[statCode], goal)
+			goalsByStatCode[statCode] = append(goalsByStatCode[statCode], goal)
+
+			if goal.RotationGroup != "" {
+				weeks := goalsByRotation[goal.RotationGroup]
+				if weeks == nil {
+					weeks = make(map[int][]*domain.Goal)
+					goalsByRotation[goal.RotationGroup] = weeks
+				}
+				weeks[goal.RotationWeek] = append(weeks[goal.RotationWeek], goal)
+			}
+
+			difficulty := goal.EffectiveDifficulty()
+			goalsByTier[difficulty] = append(goalsByTier[difficulty], goal)
+
+			if goal.DefaultAssigned {
+				defaultAssigned++
+				defaultAssignedGoals = append(defaultAssignedGoals, goal)
+			}
 		}
+
+		// The validator caps default-assigned goals per challenge; a config built without
+		// it (or with a raised cap) would make GetGoalsWithDefaultAssigned unbounded
+		if defaultAssigned > config.DefaultMaxDefaultAssignedPerChallenge {
+			c.logger.Warn("Challenge exceeds default-assigned goal cap",
+				"challenge_id", challenge.ID,
+				"default_assigned_goals", defaultAssigned,
+				"cap", config.DefaultMaxDefaultAssignedPerChallenge,
+			)
+		}
+	}
+
+	// Rotation weeks and tags are built by append; drop their spare capacity like the stat code slices
+	for _, weeks := range goalsByRotation {
+		for week, goals := range weeks {
+			weeks[week] = slices.Clip(goals)
+		}
+	}
+	for tag, goals := range goalsByTag {
+		goalsByTag[tag] = slices.Clip(goals)
+	}
+	for difficulty, goals := range goalsByTier {
+		goalsByTier[difficulty] = slices.Clip(goals)
+	}
+	for rewardID, goals := range goalsByReward {
+		goalsByReward[rewardID] = slices.Clip(goals)
+	}
+
+	// Easy goals first for onboarding; the stable sort keeps config order within a tier
+	sort.SliceStable(defaultAssignedGoals, func(i, j int) bool {
+		return domain.DifficultyRank(defaultAssignedGoals[i].Difficulty) < domain.DifficultyRank(defaultAssignedGoals[j].Difficulty)
+	})
+	defaultAssignedGoals = slices.Clip(defaultAssignedGoals)
+
+	statCodeSummary, statCodes := buildStatCodeSummary(goalsByStatCode)
+
+	checksum := c.configChecksum(cfg)
+	stats := CacheStats{
+		Challenges:      len(challenges),
+		Goals:           len(goalsByID),
+		EnabledGoals:    len(goalsByID) - disabled,
+		StatCodes:       len(goalsByStatCode),
+		DefaultAssigned: len(defaultAssignedGoals),
+		RotationGroups:  len(goalsByRotation),
+		Tags:            len(goalsByTag),
+		InternedStrings: len(strs),
 	}
+	stats.ApproxBytes = approxCacheBytes(cfg, strs, stats)
+	stats.LoadedAt = time.Now()
+
+	c.mu.Lock()
+	c.goalsByID = goalsByID
+	c.goalsByStatCode = goalsByStatCode
+	c.goalsByRotation = goalsByRotation
+	c.goalsByTag = goalsByTag
+	c.goalsByTier = goalsByTier
+	c.goalsByReward = goalsByReward
+	c.statCodeSummary = statCodeSummary
+	c.statCodes = statCodes
+	c.defaultAssigned = defaultAssignedGoals
+	c.challengeIDs = challengeIDs
+	c.challengesByID = challengesByID
+	c.challenges = challenges
+	c.checksum = checksum
+	c.stats = stats
+	c.version++
+	c.loadedAt = stats.LoadedAt
+	c.mu.Unlock()
 
 	c.logger.Info("Cache built successfully",
-		"challenges", len(c.challenges),
-		"goals", len(c.goalsByID),
-		"stat_codes", len(c.goalsByStatCode),
+		"challenges", stats.Challenges,
+		"goals", stats.Goals,
+		"disabled_goals", disabled,
+		"stat_codes", stats.StatCodes,
+		"rotation_groups", stats.RotationGroups,
+		"tags", stats.Tags,
+		"approx_bytes", stats.ApproxBytes,
+		"config_checksum", checksum,
 	)
 }
 
+// configChecksum hashes the canonical JSON encoding of cfg. Struct fields encode in
+// declaration order and map keys sorted, so formatting and key order in the config
+// file do not change the checksum. Returns "" if cfg cannot be encoded.
+func (c *InMemoryGoalCache) configChecksum(cfg *config.Config) string {
+	data, err := json.Marshal(cfg)
+	if err != nil {
+		c.logger.Warn("Failed to compute config checksum", "error", err)
+		return ""
+	}
+
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+// ConfigChecksum returns the hex-encoded SHA-256 of the loaded configuration.
+// It changes whenever a reload loads a different configuration, so progress rows
+// stamped with it (see repository.WithConfigChecksum) reveal which config wrote them.
+func (c *InMemoryGoalCache) ConfigChecksum() string {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	return c.checksum
+}
+
 // GetGoalByID retrieves a goal by its unique ID.
 // Returns nil if the goal does not exist.
 // Time complexity: O(1)
@@ -92,7 +286,7 @@ func (c *InMemoryGoalCache) GetGoalByID(goalID string) *domain.Goal {
 	return c.goalsByID[goalID]
 }
 
-// GetGoalsByStatCode retrieves all goals that track a specific stat code.
+// GetGoalsByStatCode retrieves all enabled goals that track a specific stat code.
 // Multiple goals can track the same stat (e.g., multiple challenges tracking "login_count").
 // Returns an empty slice if no goals track this stat.
 // Time complexity: O(1)
@@ -100,16 +294,26 @@ func (c *InMemoryGoalCache) GetGoalsByStatCode(statCode string) []*domain.Goal {
 	c.mu.RLock()
 	defer c.mu.RUnlock()
 
-	// Return a copy to prevent external modification
 	goals := c.goalsByStatCode[statCode]
 	if goals == nil {
 		return []*domain.Goal{}
 	}
 
-	// Return the slice directly - it's safe because Goals are immutable
+	// Return the shared slice without copying: Goals are immutable, and the slice has
+	// no spare capacity, so a caller's append reallocates instead of writing into it
 	return goals
 }
 
+// IsGoalEnabled returns true if the goal exists and is not disabled.
+// Time complexity: O(1)
+func (c *InMemoryGoalCache) IsGoalEnabled(goalID string) bool {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	goal := c.goalsByID[goalID]
+	return goal != nil && goal.IsEnabled()
+}
+
 // GetChallengeByChallengeID retrieves a challenge by its unique ID.
 // Returns nil if the challenge does not exist.
 // Time complexity: O(1)
@@ -120,6 +324,32 @@ func (c *InMemoryGoalCache) GetChallengeByChallengeID(challengeID string) *domai
 	return c.challengesByID[challengeID]
 }
 
+// GetChallengeForGoal retrieves the challenge that contains a goal.
+// Returns nil if the goal does not exist.
+// Time complexity: O(1)
+func (c *InMemoryGoalCache) GetChallengeForGoal(goalID string) *domain.Challenge {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	goal := c.goalsByID[goalID]
+	if goal == nil {
+		return nil
+	}
+
+	return c.challengesByID[goal.ChallengeID]
+}
+
+// GetChallengeIDForGoal resolves the ID of the challenge that contains a goal.
+// Disabled goals resolve too. Returns false if the goal does not exist.
+// Time complexity: O(1)
+func (c *InMemoryGoalCache) GetChallengeIDForGoal(goalID string) (string, bool) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	challengeID, ok := c.challengeIDs[goalID]
+	return challengeID, ok
+}
+
 // GetAllChallenges retrieves all configured challenges.
 // Returns all challenges in the order they appear in the config file.
 // Time complexity: O(1)
@@ -148,7 +378,7 @@ func (c *InMemoryGoalCache) GetAllGoals() []*domain.Goal {
 	return allGoals
 }
 
-// GetGoalsWithDefaultAssigned retrieves all goals that have default_assigned = true.
+// GetGoalsWithDefaultAssigned retrieves all enabled goals that have default_assigned = true.
 // Used by initialization endpoint to determine which goals to assign to new players.
 // Returns empty slice if no goals are marked as default assigned.
 // Time complexity: O(n) where n is total number of goals
@@ -159,7 +389,7 @@ func (c *InMemoryGoalCache) GetGoalsWithDefaultAssigned() []*domain.Goal {
 	// Filter goals by DefaultAssigned flag
 	defaultGoals := make([]*domain.Goal, 0)
 	for _, goal := range c.goalsByID {
-		if goal.DefaultAssigned {
+		if goal.DefaultAssigned && goal.IsEnabled() {
 			defaultGoals = append(defaultGoals, goal)
 		}
 	}
@@ -167,6 +397,114 @@ func (c *InMemoryGoalCache) GetGoalsWithDefaultAssigned() []*domain.Goal {
 	return defaultGoals
 }
 
+// GetGoalsWithDefaultAssignedByDifficulty retrieves the same goals as
+// GetGoalsWithDefaultAssigned ordered easy -> normal -> hard, and by config order within
+// a tier, so onboarding can hand out easy goals first.
+// Returns an empty slice if no goals are marked as default assigned.
+// Time complexity: O(1)
+func (c *InMemoryGoalCache) GetGoalsWithDefaultAssignedByDifficulty() []*domain.Goal {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	if c.defaultAssigned == nil {
+		return []*domain.Goal{}
+	}
+
+	// Return the slice directly - it's safe because Goals are immutable
+	return c.defaultAssigned
+}
+
+// GetGoalsByRotationGroup retrieves all enabled goals of a rotation group across every
+// week, ordered by week and then by config order.
+// Returns an empty slice if the group does not exist.
+// Time complexity: O(n) where n is the number of goals in the group
+func (c *InMemoryGoalCache) GetGoalsByRotationGroup(group string) []*domain.Goal {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	weeks := c.goalsByRotation[group]
+	weekNumbers := make([]int, 0, len(weeks))
+	for week := range weeks {
+		weekNumbers = append(weekNumbers, week)
+	}
+	sort.Ints(weekNumbers)
+
+	goals := make([]*domain.Goal, 0)
+	for _, week := range weekNumbers {
+		goals = append(goals, weeks[week]...)
+	}
+
+	return goals
+}
+
+// GetGoalsForRotationWeek retrieves the enabled goals offered in one week of a rotation
+// group, in config order. Use domain.CurrentRotationWeek to compute the week.
+// Returns an empty slice if the group does not exist or has no goals in that week.
+// Time complexity: O(1)
+func (c *InMemoryGoalCache) GetGoalsForRotationWeek(group string, week int) []*domain.Goal {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	goals := c.goalsByRotation[group][week]
+	if goals == nil {
+		return []*domain.Goal{}
+	}
+
+	// Return the slice directly - it's safe because Goals are immutable
+	return goals
+}
+
+// GetGoalsByTag retrieves all goals carrying a tag, in config order. Disabled goals are
+// included; use IsGoalEnabled to drop them where only live goals should appear.
+// Returns an empty slice if no goal has the tag.
+// Time complexity: O(1)
+func (c *InMemoryGoalCache) GetGoalsByTag(tag string) []*domain.Goal {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	goals := c.goalsByTag[tag]
+	if goals == nil {
+		return []*domain.Goal{}
+	}
+
+	// Return the slice directly - it's safe because Goals are immutable
+	return goals
+}
+
+// GetGoalsByRewardID retrieves all goals that grant a reward ID through any of their
+// rewards, in config order. Disabled goals are included.
+// Returns an empty slice if no goal grants the reward.
+// Time complexity: O(1)
+func (c *InMemoryGoalCache) GetGoalsByRewardID(rewardID string) []*domain.Goal {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	goals := c.goalsByReward[rewardID]
+	if goals == nil {
+		return []*domain.Goal{}
+	}
+
+	// Return the slice directly - it's safe because Goals are immutable
+	return goals
+}
+
+// GetGoalsByDifficulty retrieves the enabled goals of a difficulty tier, in config order.
+// Goals without a difficulty are listed under domain.DifficultyNormal.
+// Returns an empty slice for an unknown tier or a tier without goals.
+// Time complexity: O(1)
+func (c *InMemoryGoalCache) GetGoalsByDifficulty(d string) []*domain.Goal {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	goals := c.goalsByTier[d]
+	if goals == nil {
+		return []*domain.Goal{}
+	}
+
+	// Return the slice directly - it's safe because Goals are immutable
+	return goals
+}
+
 // Reload reloads the cache from the config file.
 // In M1, this requires application restart (config is baked into Docker image).
 // This method is provided for future use when hot-reload is supported.
@@ -175,16 +513,24 @@ func (c *InMemoryGoalCache) GetGoalsWithDefaultAssigned() []*domain.Goal {
 //   - error: If config file cannot be read or validation fails
 func (c *InMemoryGoalCache) Reload() error {
 	// Load config from file
-	loader := config.NewConfigLoader(c.configPath, c.logger)
+	// Strict key checking catches hand-edited configs before they replace a working cache
+	loader := config.NewConfigLoader(c.configPath, c.logger, config.WithStrictDuplicateKeys(true))
 	newConfig, err := loader.LoadConfig()
 	if err != nil {
+		c.recordReload(err)
 		return err
 	}
 
+	c.mu.RLock()
+	previous, previousStatCodes := c.goalsByID, c.statCodes
+	c.mu.RUnlock()
+
 	// Rebuild cache
 	c.buildCache(newConfig)
+	c.recordReload(nil)
 
 	c.logger.Info("Cache reloaded successfully")
+	c.notifyReload(previous, previousStatCodes)
 
 	return nil
 }
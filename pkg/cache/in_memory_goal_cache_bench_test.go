@@ -0,0 +1,120 @@
+package cache
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"runtime"
+	"testing"
+
+	"github.com/AccelByte/extend-challenge-common/pkg/config"
+	"github.com/AccelByte/extend-challenge-common/pkg/domain"
+)
+
+// Shape of the large config used by the cache benchmarks: 8,000 goals sharing a small set
+// of stat codes and reward IDs, like a live-ops config with many similar goals.
+const (
+	benchChallenges        = 100
+	benchGoalsPerChallenge = 80
+	benchStatCodes         = 50
+	benchRewardIDs         = 20
+)
+
+// largeConfigJSON encodes the benchmark config. Decoding it gives every goal its own
+// copy of each repeated string, as loading a real config file does.
+func largeConfigJSON(b *testing.B) []byte {
+	b.Helper()
+
+	cfg := &config.Config{}
+	n := 0
+	for c := 0; c < benchChallenges; c++ {
+		challenge := &domain.Challenge{ID: fmt.Sprintf("season-challenge-%03d", c), Name: "Challenge"}
+		for g := 0; g < benchGoalsPerChallenge; g++ {
+			challenge.Goals = append(challenge.Goals, &domain.Goal{
+				ID:          fmt.Sprintf("goal-%05d", n),
+				Name:        "Goal",
+				ChallengeID: challenge.ID,
+				Type:        domain.GoalTypeIncrement,
+				EventSource: domain.EventSourceStatistic,
+				Requirement: domain.Requirement{
+					StatCode:    fmt.Sprintf("stat_code_%02d", n%benchStatCodes),
+					Operator:    ">=",
+					TargetValue: 10,
+				},
+				Reward: domain.Reward{Type: "ITEM", RewardID: fmt.Sprintf("reward_item_%02d", n%benchRewardIDs), Quantity: 1},
+			})
+			n++
+		}
+		cfg.Challenges = append(cfg.Challenges, challenge)
+	}
+
+	data, err := json.Marshal(cfg)
+	if err != nil {
+		b.Fatalf("marshal config: %v", err)
+	}
+	return data
+}
+
+func decodeConfig(b *testing.B, data []byte) *config.Config {
+	b.Helper()
+	cfg := &config.Config{}
+	if err := json.Unmarshal(data, cfg); err != nil {
+		b.Fatalf("unmarshal config: %v", err)
+	}
+	return cfg
+}
+
+func heapInUse() uint64 {
+	runtime.GC()
+	var m runtime.MemStats
+	runtime.ReadMemStats(&m)
+	return m.HeapAlloc
+}
+
+// BenchmarkCacheBuild measures building the cache from a freshly decoded large config.
+// retained-B/op is the heap still held once the cache is built (config plus indexes).
+func BenchmarkCacheBuild(b *testing.B) {
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	data := largeConfigJSON(b)
+
+	before := heapInUse()
+	cache := NewInMemoryGoalCache(decodeConfig(b, data), "", logger)
+	retained := heapInUse() - before
+	runtime.KeepAlive(cache)
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		b.StopTimer()
+		cfg := decodeConfig(b, data)
+		b.StartTimer()
+
+		NewInMemoryGoalCache(cfg, "", logger)
+	}
+	b.ReportMetric(float64(retained), "retained-B/op")
+}
+
+// BenchmarkGetGoalsByStatCode measures the hot event-processing lookup.
+func BenchmarkGetGoalsByStatCode(b *testing.B) {
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	cache := NewInMemoryGoalCache(decodeConfig(b, largeConfigJSON(b)), "", logger)
+
+	b.Run("hit", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			if len(cache.GetGoalsByStatCode("stat_code_07")) == 0 {
+				b.Fatal("expected goals")
+			}
+		}
+	})
+
+	b.Run("miss", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			if len(cache.GetGoalsByStatCode("unknown_stat")) != 0 {
+				b.Fatal("expected no goals")
+			}
+		}
+	})
+}
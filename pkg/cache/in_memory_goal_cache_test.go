@@ -1,9 +1,12 @@
 package cache
 
 import (
+	"bytes"
+	"fmt"
 	"log/slog"
 	"os"
 	"path/filepath"
+	"strings"
 	"sync"
 	"testing"
 
@@ -56,6 +59,21 @@ func TestInMemoryGoalCache_GetGoalByID(t *testing.T) {
 		}
 	})
 
+	t.Run("localization keys are returned unchanged", func(t *testing.T) {
+		cfg := createTestConfig()
+		cfg.Challenges[0].Goals[0].NameKey = "goal.one.name"
+		cfg.Challenges[0].Goals[0].DescriptionKey = "goal.one.description"
+		cache := NewInMemoryGoalCache(cfg, "/path/to/config.json", logger)
+
+		goal := cache.GetGoalByID(cfg.Challenges[0].Goals[0].ID)
+		if goal == nil {
+			t.Fatal("GetGoalByID() returned nil for existing goal")
+		}
+		if goal.NameKey != "goal.one.name" || goal.DescriptionKey != "goal.one.description" {
+			t.Errorf("keys = %q, %q, want goal.one.name, goal.one.description", goal.NameKey, goal.DescriptionKey)
+		}
+	})
+
 	t.Run("non-existing goal", func(t *testing.T) {
 		goal := cache.GetGoalByID("nonexistent")
 
@@ -143,6 +161,84 @@ func TestInMemoryGoalCache_GetChallengeByChallengeID(t *testing.T) {
 	})
 }
 
+func TestInMemoryGoalCache_GetChallengeForGoal(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(os.Stdout, nil))
+	cfg := createTestConfig()
+	cache := NewInMemoryGoalCache(cfg, "/path/to/config.json", logger)
+
+	t.Run("goal in first challenge", func(t *testing.T) {
+		challenge := cache.GetChallengeForGoal("goal-2")
+
+		if challenge == nil {
+			t.Fatal("GetChallengeForGoal() returned nil for existing goal")
+		}
+
+		if challenge.ID != "challenge-1" {
+			t.Errorf("expected challenge ID 'challenge-1', got %q", challenge.ID)
+		}
+	})
+
+	t.Run("goal in second challenge", func(t *testing.T) {
+		challenge := cache.GetChallengeForGoal("goal-3")
+
+		if challenge == nil || challenge.ID != "challenge-2" {
+			t.Errorf("expected challenge 'challenge-2', got %v", challenge)
+		}
+	})
+
+	t.Run("non-existing goal", func(t *testing.T) {
+		challenge := cache.GetChallengeForGoal("nonexistent")
+
+		if challenge != nil {
+			t.Errorf("GetChallengeForGoal() expected nil for non-existing goal, got %v", challenge)
+		}
+	})
+}
+
+func TestInMemoryGoalCache_GetChallengeIDForGoal(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(os.Stdout, nil))
+	cfg := createTestConfig()
+	cache := NewInMemoryGoalCache(cfg, "/path/to/config.json", logger)
+
+	for _, challenge := range cfg.Challenges {
+		for _, goal := range challenge.Goals {
+			if got, ok := cache.GetChallengeIDForGoal(goal.ID); !ok || got != challenge.ID {
+				t.Errorf("GetChallengeIDForGoal(%q) = %q, %v; want %q, true", goal.ID, got, ok, challenge.ID)
+			}
+		}
+	}
+
+	if got, ok := cache.GetChallengeIDForGoal("nonexistent"); ok || got != "" {
+		t.Errorf("GetChallengeIDForGoal(nonexistent) = %q, %v; want \"\", false", got, ok)
+	}
+}
+
+func TestInMemoryGoalCache_NormalizesGoalChallengeID(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(os.Stdout, nil))
+	cfg := createTestConfig()
+
+	// Simulate a config built without the loader (ChallengeID not populated)
+	for _, challenge := range cfg.Challenges {
+		for _, goal := range challenge.Goals {
+			goal.ChallengeID = ""
+		}
+	}
+
+	cache := NewInMemoryGoalCache(cfg, "/path/to/config.json", logger)
+
+	for _, challenge := range cfg.Challenges {
+		for _, goal := range challenge.Goals {
+			if got := cache.GetGoalByID(goal.ID).ChallengeID; got != challenge.ID {
+				t.Errorf("goal %q: expected ChallengeID %q, got %q", goal.ID, challenge.ID, got)
+			}
+		}
+	}
+
+	if challenge := cache.GetChallengeForGoal("goal-1"); challenge == nil || challenge.ID != "challenge-1" {
+		t.Errorf("expected GetChallengeForGoal to resolve normalized goal, got %v", challenge)
+	}
+}
+
 func TestInMemoryGoalCache_GetAllChallenges(t *testing.T) {
 	logger := slog.New(slog.NewTextHandler(os.Stdout, nil))
 	cfg := createTestConfig()
@@ -258,6 +354,13 @@ func TestInMemoryGoalCache_Reload(t *testing.T) {
 			t.Errorf("expected goal name 'New Goal', got %q", goal.Name)
 		}
 
+		if challengeID, ok := cache.GetChallengeIDForGoal("goal-new"); !ok || challengeID != "challenge-new" {
+			t.Errorf("GetChallengeIDForGoal(goal-new) = %q, %v; want challenge-new, true", challengeID, ok)
+		}
+		if _, ok := cache.GetChallengeIDForGoal("goal-1"); ok {
+			t.Error("goal-1 should not resolve after reload")
+		}
+
 		// Verify old goals are gone
 		if cache.GetGoalByID("goal-1") != nil {
 			t.Error("goal-1 should not exist after reload")
@@ -298,6 +401,61 @@ func TestInMemoryGoalCache_Reload(t *testing.T) {
 	})
 }
 
+func TestInMemoryGoalCache_ConfigChecksum(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(os.Stdout, nil))
+
+	cache := NewInMemoryGoalCache(createTestConfig(), "/path/to/config.json", logger)
+	initial := cache.ConfigChecksum()
+	if len(initial) != 64 {
+		t.Fatalf("ConfigChecksum() = %q, want 64 hex characters", initial)
+	}
+
+	// The same config always yields the same checksum
+	if again := NewInMemoryGoalCache(createTestConfig(), "/path/to/config.json", logger).ConfigChecksum(); again != initial {
+		t.Errorf("ConfigChecksum() = %q for an identical config, want %q", again, initial)
+	}
+
+	tmpFile := createTempConfigFile(t, `{
+		"challenges": [
+			{
+				"challengeId": "challenge-new",
+				"name": "New Challenge",
+				"description": "Description",
+				"goals": [
+					{
+						"goalId": "goal-new",
+						"name": "New Goal",
+						"description": "Description",
+						"type": "absolute",
+						"eventSource": "statistic",
+						"requirement": {"statCode": "new_stat", "operator": ">=", "targetValue": 100},
+						"reward": {"type": "ITEM", "rewardId": "new_item", "quantity": 1},
+						"prerequisites": []
+					}
+				]
+			}
+		]
+	}`)
+	defer func() { _ = os.Remove(tmpFile) }()
+
+	cache.configPath = tmpFile
+	if err := cache.Reload(); err != nil {
+		t.Fatalf("Reload() unexpected error = %v", err)
+	}
+	reloaded := cache.ConfigChecksum()
+	if reloaded == initial {
+		t.Error("ConfigChecksum() unchanged after reloading a different config")
+	}
+
+	// Reloading the same file keeps the checksum
+	if err := cache.Reload(); err != nil {
+		t.Fatalf("Reload() unexpected error = %v", err)
+	}
+	if got := cache.ConfigChecksum(); got != reloaded {
+		t.Errorf("ConfigChecksum() = %q after reloading the same file, want %q", got, reloaded)
+	}
+}
+
 func TestInMemoryGoalCache_ThreadSafety(t *testing.T) {
 	logger := slog.New(slog.NewTextHandler(os.Stdout, nil))
 	cfg := createTestConfig()
@@ -595,3 +753,432 @@ func TestInMemoryGoalCache_GetGoalsWithDefaultAssigned(t *testing.T) {
 		}
 	})
 }
+
+func TestInMemoryGoalCache_DefaultAssignedCapWarning(t *testing.T) {
+	newConfig := func(defaultAssigned int) *config.Config {
+		goals := make([]*domain.Goal, defaultAssigned)
+		for i := range goals {
+			goals[i] = &domain.Goal{
+				ID:              fmt.Sprintf("goal-%d", i),
+				Name:            "Goal",
+				DefaultAssigned: true,
+				Requirement:     domain.Requirement{StatCode: "kills", Operator: ">=", TargetValue: 1},
+			}
+		}
+		return &config.Config{Challenges: []*domain.Challenge{{ID: "challenge-1", Name: "Challenge", Goals: goals}}}
+	}
+
+	tests := []struct {
+		name        string
+		goals       int
+		wantWarning bool
+	}{
+		{"at cap", config.DefaultMaxDefaultAssignedPerChallenge, false},
+		{"over cap", config.DefaultMaxDefaultAssignedPerChallenge + 1, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			cache := NewInMemoryGoalCache(newConfig(tt.goals), "", slog.New(slog.NewTextHandler(&buf, nil)))
+
+			if got := len(cache.GetGoalsWithDefaultAssigned()); got != tt.goals {
+				t.Errorf("GetGoalsWithDefaultAssigned() returned %d goals, want %d", got, tt.goals)
+			}
+			if got := strings.Contains(buf.String(), "Challenge exceeds default-assigned goal cap"); got != tt.wantWarning {
+				t.Errorf("warning logged = %v, want %v (log: %s)", got, tt.wantWarning, buf.String())
+			}
+		})
+	}
+}
+
+func TestInMemoryGoalCache_DisabledGoals(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(os.Stdout, nil))
+
+	// configJSON builds a config with an always-enabled goal and a toggled goal sharing a stat code
+	configJSON := func(enabled string) string {
+		return `{
+			"challenges": [{
+				"challengeId": "challenge-1",
+				"name": "Challenge",
+				"description": "Description",
+				"goals": [
+					{
+						"goalId": "goal-on",
+						"name": "Enabled Goal",
+						"type": "absolute",
+						"eventSource": "statistic",
+						"defaultAssigned": true,
+						"requirement": {"statCode": "kills", "operator": ">=", "targetValue": 10},
+						"reward": {"type": "ITEM", "rewardId": "sword", "quantity": 1}
+					},
+					{
+						"goalId": "goal-toggle",
+						"name": "Toggled Goal",
+						"type": "absolute",
+						"eventSource": "statistic",
+						"defaultAssigned": true,
+						"enabled": ` + enabled + `,
+						"requirement": {"statCode": "kills", "operator": ">=", "targetValue": 20},
+						"reward": {"type": "ITEM", "rewardId": "shield", "quantity": 1}
+					}
+				]
+			}]
+		}`
+	}
+
+	tmpFile := createTempConfigFile(t, configJSON("false"))
+	defer func() { _ = os.Remove(tmpFile) }()
+
+	cfg, err := config.NewConfigLoader(tmpFile, logger).LoadConfig()
+	if err != nil {
+		t.Fatalf("LoadConfig() unexpected error = %v", err)
+	}
+	cache := NewInMemoryGoalCache(cfg, tmpFile, logger)
+
+	t.Run("disabled goal still resolvable by ID", func(t *testing.T) {
+		goal := cache.GetGoalByID("goal-toggle")
+		if goal == nil || goal.Reward.RewardID != "shield" {
+			t.Errorf("Expected disabled goal with its reward, got %+v", goal)
+		}
+		if cache.IsGoalEnabled("goal-toggle") {
+			t.Error("IsGoalEnabled(goal-toggle) = true, want false")
+		}
+		if !cache.IsGoalEnabled("goal-on") {
+			t.Error("IsGoalEnabled(goal-on) = false, want true (omitted field defaults to enabled)")
+		}
+		if cache.IsGoalEnabled("missing") {
+			t.Error("IsGoalEnabled(missing) = true, want false")
+		}
+	})
+
+	t.Run("disabled goal absent from stat-code routing", func(t *testing.T) {
+		goals := cache.GetGoalsByStatCode("kills")
+		if len(goals) != 1 || goals[0].ID != "goal-on" {
+			t.Errorf("Expected only goal-on for stat 'kills', got %d goals", len(goals))
+		}
+	})
+
+	t.Run("disabled goal excluded from default assignment", func(t *testing.T) {
+		goals := cache.GetGoalsWithDefaultAssigned()
+		if len(goals) != 1 || goals[0].ID != "goal-on" {
+			t.Errorf("Expected only goal-on to be default-assigned, got %d goals", len(goals))
+		}
+	})
+
+	t.Run("reload enabling the goal updates routing", func(t *testing.T) {
+		if err := os.WriteFile(tmpFile, []byte(configJSON("true")), 0600); err != nil {
+			t.Fatalf("Failed to rewrite config: %v", err)
+		}
+		if err := cache.Reload(); err != nil {
+			t.Fatalf("Reload() unexpected error = %v", err)
+		}
+
+		if !cache.IsGoalEnabled("goal-toggle") {
+			t.Error("Expected goal-toggle to be enabled after reload")
+		}
+		if got := len(cache.GetGoalsByStatCode("kills")); got != 2 {
+			t.Errorf("Expected 2 goals for stat 'kills' after reload, got %d", got)
+		}
+		if got := len(cache.GetGoalsWithDefaultAssigned()); got != 2 {
+			t.Errorf("Expected 2 default-assigned goals after reload, got %d", got)
+		}
+	})
+}
+
+func TestInMemoryGoalCache_RotationGroups(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(os.Stdout, nil))
+
+	// rotationGoal builds a goal JSON object in the "weekly" rotation group
+	rotationGoal := func(id string, week int, extra string) string {
+		return fmt.Sprintf(`{
+			"goalId": %q,
+			"name": "Goal",
+			"type": "absolute",
+			"eventSource": "statistic",
+			"rotationGroup": "weekly",
+			"rotationWeek": %d,%s
+			"requirement": {"statCode": "kills", "operator": ">=", "targetValue": 10},
+			"reward": {"type": "ITEM", "rewardId": "sword", "quantity": 1}
+		}`, id, week, extra)
+	}
+	configJSON := func(goals ...string) string {
+		return `{"challenges": [{"challengeId": "challenge-1", "name": "Challenge", "description": "Description", "goals": [` +
+			strings.Join(goals, ",") + `]}]}`
+	}
+
+	tmpFile := createTempConfigFile(t, configJSON(
+		rotationGoal("week0-a", 0, ""),
+		rotationGoal("week1-a", 1, ""),
+		rotationGoal("week0-b", 0, ""),
+		rotationGoal("week1-disabled", 1, `"enabled": false,`),
+	))
+	defer func() { _ = os.Remove(tmpFile) }()
+
+	cfg, err := config.NewConfigLoader(tmpFile, logger).LoadConfig()
+	if err != nil {
+		t.Fatalf("LoadConfig() unexpected error = %v", err)
+	}
+	cache := NewInMemoryGoalCache(cfg, tmpFile, logger)
+
+	goalIDs := func(goals []*domain.Goal) string {
+		ids := make([]string, len(goals))
+		for i, goal := range goals {
+			ids[i] = goal.ID
+		}
+		return strings.Join(ids, ",")
+	}
+
+	t.Run("group lists enabled goals by week", func(t *testing.T) {
+		if got := goalIDs(cache.GetGoalsByRotationGroup("weekly")); got != "week0-a,week0-b,week1-a" {
+			t.Errorf("GetGoalsByRotationGroup() = %s, want week0-a,week0-b,week1-a", got)
+		}
+	})
+
+	t.Run("week lookup", func(t *testing.T) {
+		if got := goalIDs(cache.GetGoalsForRotationWeek("weekly", 0)); got != "week0-a,week0-b" {
+			t.Errorf("GetGoalsForRotationWeek(0) = %s, want week0-a,week0-b", got)
+		}
+		if got := goalIDs(cache.GetGoalsForRotationWeek("weekly", 1)); got != "week1-a" {
+			t.Errorf("GetGoalsForRotationWeek(1) = %s, want week1-a", got)
+		}
+	})
+
+	t.Run("out of range week is empty", func(t *testing.T) {
+		for _, week := range []int{-1, 2} {
+			goals := cache.GetGoalsForRotationWeek("weekly", week)
+			if goals == nil || len(goals) != 0 {
+				t.Errorf("GetGoalsForRotationWeek(%d) = %v, want empty slice", week, goals)
+			}
+		}
+	})
+
+	t.Run("unknown group is empty", func(t *testing.T) {
+		if goals := cache.GetGoalsByRotationGroup("monthly"); goals == nil || len(goals) != 0 {
+			t.Errorf("GetGoalsByRotationGroup() = %v, want empty slice", goals)
+		}
+		if goals := cache.GetGoalsForRotationWeek("monthly", 0); goals == nil || len(goals) != 0 {
+			t.Errorf("GetGoalsForRotationWeek() = %v, want empty slice", goals)
+		}
+	})
+
+	t.Run("reload rebuilds the rotation index", func(t *testing.T) {
+		if err := os.WriteFile(tmpFile, []byte(configJSON(rotationGoal("week2-a", 2, ""))), 0600); err != nil {
+			t.Fatalf("WriteFile failed: %v", err)
+		}
+		if err := cache.Reload(); err != nil {
+			t.Fatalf("Reload() unexpected error = %v", err)
+		}
+
+		if got := goalIDs(cache.GetGoalsByRotationGroup("weekly")); got != "week2-a" {
+			t.Errorf("GetGoalsByRotationGroup() after reload = %s, want week2-a", got)
+		}
+		if goals := cache.GetGoalsForRotationWeek("weekly", 0); len(goals) != 0 {
+			t.Errorf("GetGoalsForRotationWeek(0) after reload = %s, want empty", goalIDs(goals))
+		}
+	})
+}
+
+func TestInMemoryGoalCache_Tags(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(os.Stdout, nil))
+
+	// taggedGoal builds a goal JSON object with the given tags field
+	taggedGoal := func(id, extra string) string {
+		return fmt.Sprintf(`{
+			"goalId": %q,
+			"name": "Goal",
+			"type": "absolute",
+			"eventSource": "statistic",%s
+			"requirement": {"statCode": "kills", "operator": ">=", "targetValue": 10},
+			"reward": {"type": "ITEM", "rewardId": "sword", "quantity": 1}
+		}`, id, extra)
+	}
+	configJSON := func(goals ...string) string {
+		return `{"challenges": [{"challengeId": "challenge-1", "name": "Challenge", "description": "Description", "goals": [` +
+			strings.Join(goals, ",") + `]}]}`
+	}
+
+	tmpFile := createTempConfigFile(t, configJSON(
+		taggedGoal("arena-wins", `"tags": ["pvp", "daily"],`),
+		taggedGoal("invite-friends", `"tags": ["social"],`),
+		taggedGoal("untagged", ""),
+		taggedGoal("retired-duel", `"tags": ["pvp", "pvp"], "enabled": false,`),
+	))
+	defer func() { _ = os.Remove(tmpFile) }()
+
+	cfg, err := config.NewConfigLoader(tmpFile, logger).LoadConfig()
+	if err != nil {
+		t.Fatalf("LoadConfig() unexpected error = %v", err)
+	}
+	cache := NewInMemoryGoalCache(cfg, tmpFile, logger)
+
+	goalIDs := func(goals []*domain.Goal) string {
+		ids := make([]string, len(goals))
+		for i, goal := range goals {
+			ids[i] = goal.ID
+		}
+		return strings.Join(ids, ",")
+	}
+
+	t.Run("tag lists goals in config order", func(t *testing.T) {
+		// The duplicate tag on retired-duel is indexed once; disabled goals are included
+		if got := goalIDs(cache.GetGoalsByTag("pvp")); got != "arena-wins,retired-duel" {
+			t.Errorf("GetGoalsByTag(pvp) = %s, want arena-wins,retired-duel", got)
+		}
+		if got := goalIDs(cache.GetGoalsByTag("social")); got != "invite-friends" {
+			t.Errorf("GetGoalsByTag(social) = %s, want invite-friends", got)
+		}
+		if got := cache.Stats().Tags; got != 3 {
+			t.Errorf("Stats().Tags = %d, want 3", got)
+		}
+	})
+
+	t.Run("unknown tag is empty", func(t *testing.T) {
+		for _, tag := range []string{"pve", ""} {
+			if goals := cache.GetGoalsByTag(tag); goals == nil || len(goals) != 0 {
+				t.Errorf("GetGoalsByTag(%q) = %v, want empty slice", tag, goals)
+			}
+		}
+	})
+
+	t.Run("reload rebuilds the tag index", func(t *testing.T) {
+		if err := os.WriteFile(tmpFile, []byte(configJSON(taggedGoal("guild-raid", `"tags": ["social"],`))), 0600); err != nil {
+			t.Fatalf("WriteFile failed: %v", err)
+		}
+		if err := cache.Reload(); err != nil {
+			t.Fatalf("Reload() unexpected error = %v", err)
+		}
+
+		if got := goalIDs(cache.GetGoalsByTag("social")); got != "guild-raid" {
+			t.Errorf("GetGoalsByTag(social) after reload = %s, want guild-raid", got)
+		}
+		if goals := cache.GetGoalsByTag("pvp"); len(goals) != 0 {
+			t.Errorf("GetGoalsByTag(pvp) after reload = %s, want empty", goalIDs(goals))
+		}
+	})
+}
+
+func TestInMemoryGoalCache_GetGoalsByRewardID(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(os.Stdout, nil))
+
+	tmpFile := createTempConfigFile(t, `{"challenges": [{"challengeId": "challenge-1", "name": "Challenge", "description": "Description", "goals": [
+		{
+			"goalId": "starter-pack",
+			"name": "Starter Pack",
+			"eventSource": "statistic",
+			"requirement": {"statCode": "logins", "operator": ">=", "targetValue": 1},
+			"rewards": [
+				{"type": "ITEM", "rewardId": "sword", "quantity": 1},
+				{"type": "WALLET", "rewardId": "GOLD", "quantity": 100},
+				{"type": "ITEM", "rewardId": "sword", "quantity": 1}
+			]
+		},
+		{
+			"goalId": "gold-rush",
+			"name": "Gold Rush",
+			"eventSource": "statistic",
+			"requirement": {"statCode": "kills", "operator": ">=", "targetValue": 10},
+			"reward": {"type": "WALLET", "rewardId": "GOLD", "quantity": 50}
+		}
+	]}]}`)
+	defer func() { _ = os.Remove(tmpFile) }()
+
+	cfg, err := config.NewConfigLoader(tmpFile, logger).LoadConfig()
+	if err != nil {
+		t.Fatalf("LoadConfig() unexpected error = %v", err)
+	}
+	cache := NewInMemoryGoalCache(cfg, tmpFile, logger)
+
+	t.Run("second reward of a bundle is indexed", func(t *testing.T) {
+		goals := cache.GetGoalsByRewardID("GOLD")
+		if len(goals) != 2 || goals[0].ID != "starter-pack" || goals[1].ID != "gold-rush" {
+			t.Errorf("GetGoalsByRewardID(GOLD) = %v, want starter-pack and gold-rush", goals)
+		}
+	})
+
+	t.Run("repeated reward is indexed once", func(t *testing.T) {
+		goals := cache.GetGoalsByRewardID("sword")
+		if len(goals) != 1 || goals[0].ID != "starter-pack" {
+			t.Errorf("GetGoalsByRewardID(sword) = %v, want only starter-pack", goals)
+		}
+	})
+
+	t.Run("unknown reward is empty", func(t *testing.T) {
+		if goals := cache.GetGoalsByRewardID("shield"); goals == nil || len(goals) != 0 {
+			t.Errorf("GetGoalsByRewardID(shield) = %v, want empty slice", goals)
+		}
+	})
+}
+
+func TestInMemoryGoalCache_Difficulty(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(os.Stdout, nil))
+
+	// tieredGoal builds a goal JSON object with the given difficulty and extra fields
+	tieredGoal := func(id, difficulty, extra string) string {
+		return fmt.Sprintf(`{
+			"goalId": %q,
+			"name": "Goal",
+			"type": "absolute",
+			"eventSource": "statistic",
+			"difficulty": %q,%s
+			"requirement": {"statCode": "kills", "operator": ">=", "targetValue": 10},
+			"reward": {"type": "ITEM", "rewardId": "sword", "quantity": 1}
+		}`, id, difficulty, extra)
+	}
+	configJSON := `{"challenges": [{"challengeId": "challenge-1", "name": "Challenge", "description": "Description", "goals": [` +
+		strings.Join([]string{
+			tieredGoal("boss-rush", "hard", `"defaultAssigned": true,`),
+			tieredGoal("daily-match", "", `"defaultAssigned": true,`),
+			tieredGoal("first-kill", "easy", `"defaultAssigned": true,`),
+			tieredGoal("ranked-climb", "normal", `"defaultAssigned": true,`),
+			tieredGoal("tutorial", "easy", `"defaultAssigned": true,`),
+			tieredGoal("retired-easy", "easy", `"enabled": false,`),
+			tieredGoal("speedrun", "hard", ""),
+		}, ",") + `]}]}`
+
+	tmpFile := createTempConfigFile(t, configJSON)
+	defer func() { _ = os.Remove(tmpFile) }()
+
+	cfg, err := config.NewConfigLoader(tmpFile, logger).LoadConfig()
+	if err != nil {
+		t.Fatalf("LoadConfig() unexpected error = %v", err)
+	}
+	cache := NewInMemoryGoalCache(cfg, tmpFile, logger)
+
+	goalIDs := func(goals []*domain.Goal) string {
+		ids := make([]string, len(goals))
+		for i, goal := range goals {
+			ids[i] = goal.ID
+		}
+		return strings.Join(ids, ",")
+	}
+
+	t.Run("default-assigned goals ordered easy to hard", func(t *testing.T) {
+		got := goalIDs(cache.GetGoalsWithDefaultAssignedByDifficulty())
+		if want := "first-kill,tutorial,daily-match,ranked-climb,boss-rush"; got != want {
+			t.Errorf("GetGoalsWithDefaultAssignedByDifficulty() = %s, want %s", got, want)
+		}
+		if n := len(cache.GetGoalsWithDefaultAssigned()); n != 5 {
+			t.Errorf("GetGoalsWithDefaultAssigned() returned %d goals, want 5", n)
+		}
+	})
+
+	t.Run("goals by difficulty", func(t *testing.T) {
+		tests := map[string]string{
+			domain.DifficultyEasy:   "first-kill,tutorial",
+			domain.DifficultyNormal: "daily-match,ranked-climb",
+			domain.DifficultyHard:   "boss-rush,speedrun",
+			"extreme":               "",
+			"":                      "",
+		}
+		for difficulty, want := range tests {
+			goals := cache.GetGoalsByDifficulty(difficulty)
+			if goals == nil {
+				t.Errorf("GetGoalsByDifficulty(%q) = nil, want empty slice", difficulty)
+			}
+			if got := goalIDs(goals); got != want {
+				t.Errorf("GetGoalsByDifficulty(%q) = %s, want %s", difficulty, got, want)
+			}
+		}
+	})
+}
@@ -0,0 +1,271 @@
+package cache
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"sort"
+
+	"github.com/AccelByte/extend-challenge-common/pkg/config"
+	"github.com/AccelByte/extend-challenge-common/pkg/domain"
+)
+
+// NamespaceSource is the configuration of a single namespace in a MultiNamespaceGoalCache.
+type NamespaceSource struct {
+	Config     *config.Config // Validated configuration for the namespace
+	ConfigPath string         // Path to the namespace's config file (used for reload)
+}
+
+// MultiNamespaceGoalCache serves goal configurations for multiple game namespaces from one
+// service instance. Each namespace has its own isolated GoalCache, so goal and challenge IDs
+// may overlap across namespaces.
+//
+// The namespace set is fixed at construction and the namespace map is never mutated,
+// so it is read without locking. Each namespace cache guards its own data, which lets
+// one namespace reload while others are being read.
+type MultiNamespaceGoalCache struct {
+	caches map[string]GoalCache // "namespace" -> GoalCache
+	logger *slog.Logger
+}
+
+// NewMultiNamespaceGoalCache creates a cache with one InMemoryGoalCache per namespace.
+//
+// Parameters:
+//   - sources: Validated configuration and config path per namespace
+//   - logger: Structured logger; each namespace logs with a "namespace" attribute
+func NewMultiNamespaceGoalCache(sources map[string]NamespaceSource, logger *slog.Logger) *MultiNamespaceGoalCache {
+	caches := make(map[string]GoalCache, len(sources))
+	for namespace, source := range sources {
+		caches[namespace] = NewInMemoryGoalCache(source.Config, source.ConfigPath, logger.With("namespace", namespace))
+	}
+
+	return &MultiNamespaceGoalCache{
+		caches: caches,
+		logger: logger,
+	}
+}
+
+// LoadMultiNamespaceGoalCache loads and validates each namespace's config file and builds the cache.
+// Returns an error naming the first namespace whose config fails to load.
+func LoadMultiNamespaceGoalCache(configPaths map[string]string, logger *slog.Logger) (*MultiNamespaceGoalCache, error) {
+	sources := make(map[string]NamespaceSource, len(configPaths))
+	for namespace, path := range configPaths {
+		cfg, err := config.NewConfigLoader(path, logger.With("namespace", namespace)).LoadConfig()
+		if err != nil {
+			return nil, fmt.Errorf("namespace '%s': %w", namespace, err)
+		}
+		sources[namespace] = NamespaceSource{Config: cfg, ConfigPath: path}
+	}
+
+	return NewMultiNamespaceGoalCache(sources, logger), nil
+}
+
+// Namespace returns the cache for a namespace, or nil if the namespace is not configured.
+func (m *MultiNamespaceGoalCache) Namespace(namespace string) GoalCache {
+	return m.caches[namespace]
+}
+
+// Namespaces returns the configured namespaces in sorted order.
+func (m *MultiNamespaceGoalCache) Namespaces() []string {
+	namespaces := make([]string, 0, len(m.caches))
+	for namespace := range m.caches {
+		namespaces = append(namespaces, namespace)
+	}
+	sort.Strings(namespaces)
+	return namespaces
+}
+
+// GetGoalByID retrieves a goal by ID within a namespace.
+// Returns nil if the namespace or goal does not exist.
+func (m *MultiNamespaceGoalCache) GetGoalByID(namespace, goalID string) *domain.Goal {
+	if c := m.caches[namespace]; c != nil {
+		return c.GetGoalByID(goalID)
+	}
+	return nil
+}
+
+// GetGoalsByStatCode retrieves all goals tracking a stat code within a namespace.
+// Returns an empty slice if the namespace does not exist or no goals track the stat.
+func (m *MultiNamespaceGoalCache) GetGoalsByStatCode(namespace, statCode string) []*domain.Goal {
+	if c := m.caches[namespace]; c != nil {
+		return c.GetGoalsByStatCode(statCode)
+	}
+	return []*domain.Goal{}
+}
+
+// GetAllStatCodes retrieves the stat codes tracked within a namespace, sorted.
+// Returns nil if the namespace does not exist.
+func (m *MultiNamespaceGoalCache) GetAllStatCodes(namespace string) []string {
+	if c := m.caches[namespace]; c != nil {
+		return c.GetAllStatCodes()
+	}
+	return nil
+}
+
+// GetStatCodeSummary retrieves the per-stat-code goal aggregates within a namespace.
+// Returns nil if the namespace does not exist.
+func (m *MultiNamespaceGoalCache) GetStatCodeSummary(namespace string) map[string]StatCodeInfo {
+	if c := m.caches[namespace]; c != nil {
+		return c.GetStatCodeSummary()
+	}
+	return nil
+}
+
+// GetChallengeByChallengeID retrieves a challenge by ID within a namespace.
+// Returns nil if the namespace or challenge does not exist.
+func (m *MultiNamespaceGoalCache) GetChallengeByChallengeID(namespace, challengeID string) *domain.Challenge {
+	if c := m.caches[namespace]; c != nil {
+		return c.GetChallengeByChallengeID(challengeID)
+	}
+	return nil
+}
+
+// GetChallengeForGoal retrieves the challenge containing a goal within a namespace.
+// Returns nil if the namespace or goal does not exist.
+func (m *MultiNamespaceGoalCache) GetChallengeForGoal(namespace, goalID string) *domain.Challenge {
+	if c := m.caches[namespace]; c != nil {
+		return c.GetChallengeForGoal(goalID)
+	}
+	return nil
+}
+
+// GetChallengeIDForGoal resolves the challenge ID of a goal within a namespace.
+// Returns false if the namespace or goal does not exist.
+func (m *MultiNamespaceGoalCache) GetChallengeIDForGoal(namespace, goalID string) (string, bool) {
+	if c := m.caches[namespace]; c != nil {
+		return c.GetChallengeIDForGoal(goalID)
+	}
+	return "", false
+}
+
+// GetAllChallenges retrieves all challenges of a namespace in config order.
+// Returns an empty slice if the namespace does not exist.
+func (m *MultiNamespaceGoalCache) GetAllChallenges(namespace string) []*domain.Challenge {
+	if c := m.caches[namespace]; c != nil {
+		return c.GetAllChallenges()
+	}
+	return []*domain.Challenge{}
+}
+
+// GetAllGoals retrieves all goals of a namespace.
+// Returns an empty slice if the namespace does not exist.
+func (m *MultiNamespaceGoalCache) GetAllGoals(namespace string) []*domain.Goal {
+	if c := m.caches[namespace]; c != nil {
+		return c.GetAllGoals()
+	}
+	return []*domain.Goal{}
+}
+
+// GetGoalsWithDefaultAssigned retrieves the default-assigned goals of a namespace.
+// Returns an empty slice if the namespace does not exist.
+func (m *MultiNamespaceGoalCache) GetGoalsWithDefaultAssigned(namespace string) []*domain.Goal {
+	if c := m.caches[namespace]; c != nil {
+		return c.GetGoalsWithDefaultAssigned()
+	}
+	return []*domain.Goal{}
+}
+
+// GetGoalsWithDefaultAssignedByDifficulty retrieves the default-assigned goals of a
+// namespace ordered easy -> normal -> hard.
+// Returns an empty slice if the namespace does not exist.
+func (m *MultiNamespaceGoalCache) GetGoalsWithDefaultAssignedByDifficulty(namespace string) []*domain.Goal {
+	if c := m.caches[namespace]; c != nil {
+		return c.GetGoalsWithDefaultAssignedByDifficulty()
+	}
+	return []*domain.Goal{}
+}
+
+// IsGoalEnabled returns true if the goal exists in the namespace and is not disabled.
+func (m *MultiNamespaceGoalCache) IsGoalEnabled(namespace, goalID string) bool {
+	if c := m.caches[namespace]; c != nil {
+		return c.IsGoalEnabled(goalID)
+	}
+	return false
+}
+
+// GetGoalsByRotationGroup retrieves the enabled goals of a rotation group within a namespace.
+// Returns an empty slice if the namespace or group does not exist.
+func (m *MultiNamespaceGoalCache) GetGoalsByRotationGroup(namespace, group string) []*domain.Goal {
+	if c := m.caches[namespace]; c != nil {
+		return c.GetGoalsByRotationGroup(group)
+	}
+	return []*domain.Goal{}
+}
+
+// GetGoalsForRotationWeek retrieves the enabled goals of one rotation week within a namespace.
+// Returns an empty slice if the namespace, group or week has no goals.
+func (m *MultiNamespaceGoalCache) GetGoalsForRotationWeek(namespace, group string, week int) []*domain.Goal {
+	if c := m.caches[namespace]; c != nil {
+		return c.GetGoalsForRotationWeek(group, week)
+	}
+	return []*domain.Goal{}
+}
+
+// GetGoalsByTag retrieves the goals carrying a tag within a namespace.
+// Returns an empty slice if the namespace does not exist or no goal has the tag.
+func (m *MultiNamespaceGoalCache) GetGoalsByTag(namespace, tag string) []*domain.Goal {
+	if c := m.caches[namespace]; c != nil {
+		return c.GetGoalsByTag(tag)
+	}
+	return []*domain.Goal{}
+}
+
+// GetGoalsByRewardID retrieves the goals granting a reward ID within a namespace.
+// Returns an empty slice if the namespace does not exist or no goal grants the reward.
+func (m *MultiNamespaceGoalCache) GetGoalsByRewardID(namespace, rewardID string) []*domain.Goal {
+	if c := m.caches[namespace]; c != nil {
+		return c.GetGoalsByRewardID(rewardID)
+	}
+	return []*domain.Goal{}
+}
+
+// GetGoalsByDifficulty retrieves the enabled goals of a difficulty tier within a namespace.
+// Returns an empty slice if the namespace does not exist or the tier has no goals.
+func (m *MultiNamespaceGoalCache) GetGoalsByDifficulty(namespace, d string) []*domain.Goal {
+	if c := m.caches[namespace]; c != nil {
+		return c.GetGoalsByDifficulty(d)
+	}
+	return []*domain.Goal{}
+}
+
+// Reload reloads a single namespace from its config file. Other namespaces are unaffected.
+// On failure the namespace keeps serving its previous configuration.
+func (m *MultiNamespaceGoalCache) Reload(namespace string) error {
+	c := m.caches[namespace]
+	if c == nil {
+		return fmt.Errorf("unknown namespace: %s", namespace)
+	}
+
+	if err := c.Reload(); err != nil {
+		return fmt.Errorf("namespace '%s': %w", namespace, err)
+	}
+
+	return nil
+}
+
+// OnReload registers fn to run after every successful reload of any namespace, with the
+// namespace and its ReloadSummary (see InMemoryGoalCache.OnReload).
+func (m *MultiNamespaceGoalCache) OnReload(fn func(namespace string, summary ReloadSummary)) {
+	for namespace, c := range m.caches {
+		if notifier, ok := c.(interface{ OnReload(func(ReloadSummary)) }); ok {
+			notifier.OnReload(func(summary ReloadSummary) { fn(namespace, summary) })
+		}
+	}
+}
+
+// ReloadAll reloads every namespace. A failure in one namespace does not stop the others;
+// all failures are returned joined together.
+func (m *MultiNamespaceGoalCache) ReloadAll() error {
+	var errs []error
+	for _, namespace := range m.Namespaces() {
+		if err := m.Reload(namespace); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	if len(errs) > 0 {
+		m.logger.Error("Namespace reload failed", "failed", len(errs), "namespaces", len(m.caches))
+	}
+
+	return errors.Join(errs...)
+}
@@ -0,0 +1,229 @@
+package cache
+
+import (
+	"fmt"
+	"log/slog"
+	"os"
+	"sync"
+	"testing"
+)
+
+// namespaceConfigJSON returns a one-challenge config whose goal "shared-goal" has the given
+// name and stat code, so namespaces can reuse the same IDs with different contents.
+func namespaceConfigJSON(goalName, statCode string) string {
+	return fmt.Sprintf(`{
+		"challenges": [
+			{
+				"challengeId": "shared-challenge",
+				"name": "Shared Challenge",
+				"description": "Description",
+				"goals": [
+					{
+						"goalId": "shared-goal",
+						"name": %q,
+						"description": "Description",
+						"type": "absolute",
+						"eventSource": "statistic",
+						"defaultAssigned": true,
+						"requirement": {
+							"statCode": %q,
+							"operator": ">=",
+							"targetValue": 10
+						},
+						"reward": {
+							"type": "ITEM",
+							"rewardId": "item_1",
+							"quantity": 1
+						},
+						"prerequisites": []
+					}
+				]
+			}
+		]
+	}`, goalName, statCode)
+}
+
+func newTestMultiNamespaceCache(t *testing.T) (*MultiNamespaceGoalCache, map[string]string) {
+	t.Helper()
+
+	paths := map[string]string{
+		"game-a": createTempConfigFile(t, namespaceConfigJSON("Goal A", "kills")),
+		"game-b": createTempConfigFile(t, namespaceConfigJSON("Goal B", "wins")),
+	}
+
+	logger := slog.New(slog.NewTextHandler(os.Stdout, nil))
+	cache, err := LoadMultiNamespaceGoalCache(paths, logger)
+	if err != nil {
+		t.Fatalf("LoadMultiNamespaceGoalCache() unexpected error = %v", err)
+	}
+
+	return cache, paths
+}
+
+func TestMultiNamespaceGoalCache_Isolation(t *testing.T) {
+	cache, _ := newTestMultiNamespaceCache(t)
+
+	if got := cache.Namespaces(); len(got) != 2 || got[0] != "game-a" || got[1] != "game-b" {
+		t.Errorf("expected namespaces [game-a game-b], got %v", got)
+	}
+
+	t.Run("overlapping goal IDs resolve per namespace", func(t *testing.T) {
+		goalA := cache.GetGoalByID("game-a", "shared-goal")
+		goalB := cache.GetGoalByID("game-b", "shared-goal")
+
+		if goalA == nil || goalA.Name != "Goal A" {
+			t.Errorf("expected 'Goal A' in game-a, got %v", goalA)
+		}
+		if goalB == nil || goalB.Name != "Goal B" {
+			t.Errorf("expected 'Goal B' in game-b, got %v", goalB)
+		}
+	})
+
+	t.Run("stat codes are scoped to namespace", func(t *testing.T) {
+		if got := cache.GetGoalsByStatCode("game-a", "kills"); len(got) != 1 {
+			t.Errorf("expected 1 goal for 'kills' in game-a, got %d", len(got))
+		}
+		if got := cache.GetGoalsByStatCode("game-b", "kills"); len(got) != 0 {
+			t.Errorf("expected no goals for 'kills' in game-b, got %d", len(got))
+		}
+	})
+
+	t.Run("challenge lookups", func(t *testing.T) {
+		challenge := cache.GetChallengeForGoal("game-b", "shared-goal")
+		if challenge == nil || challenge.Goals[0].Name != "Goal B" {
+			t.Errorf("expected game-b challenge, got %v", challenge)
+		}
+		if id, ok := cache.GetChallengeIDForGoal("game-b", "shared-goal"); !ok || id != "shared-challenge" {
+			t.Errorf("expected shared-challenge for shared-goal in game-b, got %q, %v", id, ok)
+		}
+		if cache.GetChallengeByChallengeID("game-a", "shared-challenge") == nil {
+			t.Error("expected shared-challenge in game-a")
+		}
+		if len(cache.GetAllChallenges("game-a")) != 1 || len(cache.GetAllGoals("game-a")) != 1 {
+			t.Error("expected one challenge and one goal in game-a")
+		}
+		if len(cache.GetGoalsWithDefaultAssigned("game-b")) != 1 {
+			t.Error("expected one default-assigned goal in game-b")
+		}
+	})
+
+	t.Run("unknown namespace returns nil or empty", func(t *testing.T) {
+		if cache.Namespace("game-z") != nil {
+			t.Error("expected nil cache for unknown namespace")
+		}
+		if cache.GetGoalByID("game-z", "shared-goal") != nil {
+			t.Error("expected nil goal for unknown namespace")
+		}
+		if cache.GetChallengeByChallengeID("game-z", "shared-challenge") != nil {
+			t.Error("expected nil challenge for unknown namespace")
+		}
+		if cache.GetChallengeForGoal("game-z", "shared-goal") != nil {
+			t.Error("expected nil challenge for unknown namespace")
+		}
+		if _, ok := cache.GetChallengeIDForGoal("game-z", "shared-goal"); ok {
+			t.Error("expected no challenge ID for unknown namespace")
+		}
+		if got := cache.GetGoalsByStatCode("game-z", "kills"); got == nil || len(got) != 0 {
+			t.Errorf("expected empty slice, got %v", got)
+		}
+		if got := cache.GetAllChallenges("game-z"); got == nil || len(got) != 0 {
+			t.Errorf("expected empty slice, got %v", got)
+		}
+		if got := cache.GetAllGoals("game-z"); got == nil || len(got) != 0 {
+			t.Errorf("expected empty slice, got %v", got)
+		}
+		if got := cache.GetGoalsWithDefaultAssigned("game-z"); got == nil || len(got) != 0 {
+			t.Errorf("expected empty slice, got %v", got)
+		}
+		if err := cache.Reload("game-z"); err == nil {
+			t.Error("expected error reloading unknown namespace")
+		}
+	})
+}
+
+func TestMultiNamespaceGoalCache_Reload(t *testing.T) {
+	t.Run("reload of one namespace leaves the other untouched", func(t *testing.T) {
+		cache, paths := newTestMultiNamespaceCache(t)
+
+		if err := os.WriteFile(paths["game-a"], []byte(namespaceConfigJSON("Goal A v2", "kills")), 0o600); err != nil {
+			t.Fatalf("failed to rewrite config: %v", err)
+		}
+		if err := os.WriteFile(paths["game-b"], []byte(namespaceConfigJSON("Goal B v2", "wins")), 0o600); err != nil {
+			t.Fatalf("failed to rewrite config: %v", err)
+		}
+
+		if err := cache.Reload("game-a"); err != nil {
+			t.Fatalf("Reload() unexpected error = %v", err)
+		}
+
+		if got := cache.GetGoalByID("game-a", "shared-goal").Name; got != "Goal A v2" {
+			t.Errorf("expected reloaded 'Goal A v2', got %q", got)
+		}
+		if got := cache.GetGoalByID("game-b", "shared-goal").Name; got != "Goal B" {
+			t.Errorf("expected game-b to keep 'Goal B', got %q", got)
+		}
+
+		if err := cache.ReloadAll(); err != nil {
+			t.Fatalf("ReloadAll() unexpected error = %v", err)
+		}
+		if got := cache.GetGoalByID("game-b", "shared-goal").Name; got != "Goal B v2" {
+			t.Errorf("expected 'Goal B v2' after ReloadAll, got %q", got)
+		}
+	})
+
+	t.Run("ReloadAll reports failures and keeps reloading others", func(t *testing.T) {
+		cache, paths := newTestMultiNamespaceCache(t)
+
+		if err := os.WriteFile(paths["game-a"], []byte(`{invalid json}`), 0o600); err != nil {
+			t.Fatalf("failed to rewrite config: %v", err)
+		}
+		if err := os.WriteFile(paths["game-b"], []byte(namespaceConfigJSON("Goal B v2", "wins")), 0o600); err != nil {
+			t.Fatalf("failed to rewrite config: %v", err)
+		}
+
+		if err := cache.ReloadAll(); err == nil {
+			t.Error("ReloadAll() expected error for invalid game-a config")
+		}
+
+		if got := cache.GetGoalByID("game-a", "shared-goal").Name; got != "Goal A" {
+			t.Errorf("expected game-a to keep 'Goal A' after failed reload, got %q", got)
+		}
+		if got := cache.GetGoalByID("game-b", "shared-goal").Name; got != "Goal B v2" {
+			t.Errorf("expected game-b to reload to 'Goal B v2', got %q", got)
+		}
+	})
+}
+
+func TestMultiNamespaceGoalCache_ConcurrentReloadAndRead(t *testing.T) {
+	cache, _ := newTestMultiNamespaceCache(t)
+
+	var wg sync.WaitGroup
+
+	// Reload game-a repeatedly while game-b (and game-a) are being read
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for i := 0; i < 50; i++ {
+			if err := cache.Reload("game-a"); err != nil {
+				t.Errorf("Reload() unexpected error = %v", err)
+				return
+			}
+		}
+	}()
+
+	for r := 0; r < 10; r++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < 200; i++ {
+				if goal := cache.GetGoalByID("game-b", "shared-goal"); goal == nil || goal.Name != "Goal B" {
+					t.Errorf("unexpected game-b goal during reload: %v", goal)
+					return
+				}
+				_ = cache.GetGoalsByStatCode("game-a", "kills")
+			}
+		}()
+	}
+
+	wg.Wait()
+}
@@ -0,0 +1,41 @@
+package cache
+
+import "time"
+
+// ReloadStatus describes the configuration an InMemoryGoalCache is serving and the outcome
+// of its most recent Reload. A failed reload keeps the previous configuration, so Version
+// and LoadedAt only move when a reload succeeds.
+type ReloadStatus struct {
+	Version         int       // Configurations loaded so far; 1 after construction
+	Checksum        string    // ConfigChecksum of the serving configuration
+	LoadedAt        time.Time // When the serving configuration was built
+	LastReloadAt    time.Time // When Reload last ran; zero if it never ran
+	LastReloadError string    // Error of the last Reload; empty if it succeeded or never ran
+}
+
+// ReloadStatus reports the serving configuration and the result of the last Reload.
+// Time complexity: O(1)
+func (c *InMemoryGoalCache) ReloadStatus() ReloadStatus {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	return ReloadStatus{
+		Version:         c.version,
+		Checksum:        c.checksum,
+		LoadedAt:        c.loadedAt,
+		LastReloadAt:    c.lastReloadAt,
+		LastReloadError: c.lastReloadError,
+	}
+}
+
+// recordReload stores the outcome of a Reload attempt.
+func (c *InMemoryGoalCache) recordReload(err error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.lastReloadAt = time.Now()
+	c.lastReloadError = ""
+	if err != nil {
+		c.lastReloadError = err.Error()
+	}
+}
@@ -0,0 +1,31 @@
+package cache
+
+import (
+	"io"
+	"log/slog"
+	"testing"
+)
+
+func TestInMemoryGoalCache_ReloadStatus(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	cache := NewInMemoryGoalCache(createTestConfig(), "/nonexistent/challenges.json", logger)
+
+	status := cache.ReloadStatus()
+	if status.Version != 1 || status.LoadedAt.IsZero() || status.Checksum != cache.ConfigChecksum() {
+		t.Errorf("initial status = %+v, want version 1 with load time and checksum", status)
+	}
+	if !status.LastReloadAt.IsZero() || status.LastReloadError != "" {
+		t.Errorf("initial status = %+v, want no reload recorded", status)
+	}
+
+	if err := cache.Reload(); err == nil {
+		t.Fatal("Reload() expected error for non-existent file, got nil")
+	}
+	failed := cache.ReloadStatus()
+	if failed.LastReloadAt.IsZero() || failed.LastReloadError == "" {
+		t.Errorf("status after failed reload = %+v, want the error recorded", failed)
+	}
+	if failed.Version != 1 || !failed.LoadedAt.Equal(status.LoadedAt) {
+		t.Errorf("failed reload changed the serving config: %+v", failed)
+	}
+}
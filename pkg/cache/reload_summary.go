@@ -0,0 +1,112 @@
+package cache
+
+import (
+	"slices"
+	"strings"
+
+	"github.com/AccelByte/extend-challenge-common/pkg/domain"
+)
+
+// TargetChange is a goal whose requirement TargetValue differs between the previous
+// configuration and the reloaded one.
+type TargetChange struct {
+	GoalID     string
+	OldTarget  int
+	NewTarget  int
+	Repeatable bool // Whether the reloaded goal is repeatable
+}
+
+// Lowered reports whether the target went down, which can leave stored progress at or
+// past the new target without the row being completed.
+func (t TargetChange) Lowered() bool {
+	return t.NewTarget < t.OldTarget
+}
+
+// ReloadSummary describes what a successful Reload changed. Goal IDs and stat codes are
+// sorted.
+type ReloadSummary struct {
+	Version       int            // ReloadStatus.Version of the new configuration
+	AddedGoals    []string       // Goals only in the new configuration
+	RemovedGoals  []string       // Goals only in the previous configuration
+	TargetChanges []TargetChange // Goals in both whose TargetValue changed
+
+	// Stat codes that gained or lost their last enabled goal (see GetAllStatCodes), so an
+	// event consumer can adjust its topic subscriptions instead of resubscribing
+	AddedStatCodes   []string
+	RemovedStatCodes []string
+}
+
+// LoweredTargets returns the new target of every non-repeatable goal whose target went
+// down, in the form repository.CompletionReevaluator.ReevaluateCompletion takes. Raised
+// targets are left out: rows completed under the old target stay completed. Returns nil
+// if no target was lowered.
+func (s ReloadSummary) LoweredTargets() map[string]int {
+	var targets map[string]int
+	for _, change := range s.TargetChanges {
+		if !change.Lowered() || change.Repeatable {
+			continue
+		}
+		if targets == nil {
+			targets = make(map[string]int)
+		}
+		targets[change.GoalID] = change.NewTarget
+	}
+	return targets
+}
+
+// OnReload registers fn to run after every successful Reload with a summary of the
+// changes, for example to call ReevaluateCompletion when targets were lowered. Hooks run
+// in registration order on the goroutine that called Reload, after the new configuration
+// is being served, so they see it through the cache. A failed Reload runs no hooks.
+func (c *InMemoryGoalCache) OnReload(fn func(ReloadSummary)) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.reloadHooks = append(c.reloadHooks, fn)
+}
+
+// notifyReload diffs the previous goals and stat codes against the serving configuration
+// and runs the OnReload hooks with the result.
+func (c *InMemoryGoalCache) notifyReload(previous map[string]*domain.Goal, previousStatCodes []string) {
+	c.mu.RLock()
+	current, currentStatCodes := c.goalsByID, c.statCodes
+	summary := ReloadSummary{Version: c.version}
+	hooks := slices.Clip(c.reloadHooks)
+	c.mu.RUnlock()
+
+	if len(hooks) == 0 {
+		return
+	}
+
+	summary.AddedStatCodes, summary.RemovedStatCodes = diffStatCodes(previousStatCodes, currentStatCodes)
+
+	for goalID, goal := range current {
+		old, ok := previous[goalID]
+		if !ok {
+			summary.AddedGoals = append(summary.AddedGoals, goalID)
+			continue
+		}
+		if old.Requirement.TargetValue != goal.Requirement.TargetValue {
+			summary.TargetChanges = append(summary.TargetChanges, TargetChange{
+				GoalID:     goalID,
+				OldTarget:  old.Requirement.TargetValue,
+				NewTarget:  goal.Requirement.TargetValue,
+				Repeatable: goal.Repeatable,
+			})
+		}
+	}
+	for goalID := range previous {
+		if _, ok := current[goalID]; !ok {
+			summary.RemovedGoals = append(summary.RemovedGoals, goalID)
+		}
+	}
+	slices.Sort(summary.AddedGoals)
+	slices.Sort(summary.RemovedGoals)
+	slices.SortFunc(summary.TargetChanges, func(a, b TargetChange) int {
+		return strings.Compare(a.GoalID, b.GoalID)
+	})
+
+	for _, hook := range hooks {
+		hook(summary)
+	}
+}
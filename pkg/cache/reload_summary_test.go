@@ -0,0 +1,103 @@
+package cache
+
+import (
+	"encoding/json"
+	"io"
+	"log/slog"
+	"maps"
+	"reflect"
+	"testing"
+)
+
+func TestInMemoryGoalCache_OnReload(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+
+	// Reloaded config: goal-1 lowered 10 -> 5, goal-2 raised 20 -> 40, goal-3 removed
+	// with its challenge, goal-new added
+	next := createTestConfig()
+	next.Challenges = next.Challenges[:1]
+	next.Challenges[0].Goals[0].Requirement.TargetValue = 5
+	next.Challenges[0].Goals[1].Requirement.TargetValue = 40
+	added := *next.Challenges[0].Goals[0]
+	added.ID = "goal-new"
+	next.Challenges[0].Goals = append(next.Challenges[0].Goals, &added)
+	data, err := json.Marshal(next)
+	if err != nil {
+		t.Fatalf("Marshal failed: %v", err)
+	}
+
+	cache := NewInMemoryGoalCache(createTestConfig(), createTempConfigFile(t, string(data)), logger)
+
+	var summaries []ReloadSummary
+	cache.OnReload(func(s ReloadSummary) {
+		// The new configuration is already served when hooks run
+		if goal := cache.GetGoalByID("goal-1"); goal == nil || goal.Requirement.TargetValue != 5 {
+			t.Errorf("hook saw goal-1 = %+v, want the reloaded target", goal)
+		}
+		summaries = append(summaries, s)
+	})
+
+	if err := cache.Reload(); err != nil {
+		t.Fatalf("Reload() unexpected error = %v", err)
+	}
+	if len(summaries) != 1 {
+		t.Fatalf("hook ran %d times, want 1", len(summaries))
+	}
+
+	want := ReloadSummary{
+		Version:      2,
+		AddedGoals:   []string{"goal-new"},
+		RemovedGoals: []string{"goal-3"},
+		TargetChanges: []TargetChange{
+			{GoalID: "goal-1", OldTarget: 10, NewTarget: 5},
+			{GoalID: "goal-2", OldTarget: 20, NewTarget: 40},
+		},
+	}
+	if !reflect.DeepEqual(summaries[0], want) {
+		t.Errorf("summary = %+v, want %+v", summaries[0], want)
+	}
+	if got := summaries[0].LoweredTargets(); !maps.Equal(got, map[string]int{"goal-1": 5}) {
+		t.Errorf("LoweredTargets() = %v, want only goal-1 at 5", got)
+	}
+
+	t.Run("failed reload runs no hooks", func(t *testing.T) {
+		cache.configPath = "/nonexistent/challenges.json"
+		if err := cache.Reload(); err == nil {
+			t.Fatal("Reload() expected error for non-existent file, got nil")
+		}
+		if len(summaries) != 1 {
+			t.Errorf("hook ran after a failed reload")
+		}
+	})
+}
+
+func TestReloadSummary_LoweredTargets(t *testing.T) {
+	summary := ReloadSummary{TargetChanges: []TargetChange{
+		{GoalID: "lowered", OldTarget: 20, NewTarget: 10},
+		{GoalID: "raised", OldTarget: 10, NewTarget: 20},
+		{GoalID: "repeatable", OldTarget: 20, NewTarget: 10, Repeatable: true},
+	}}
+
+	if got := summary.LoweredTargets(); !maps.Equal(got, map[string]int{"lowered": 10}) {
+		t.Errorf("LoweredTargets() = %v, want only the lowered non-repeatable goal", got)
+	}
+	if got := (ReloadSummary{}).LoweredTargets(); got != nil {
+		t.Errorf("LoweredTargets() of an empty summary = %v, want nil", got)
+	}
+}
+
+func TestMultiNamespaceGoalCache_OnReload(t *testing.T) {
+	cache, _ := newTestMultiNamespaceCache(t)
+
+	var reloaded []string
+	cache.OnReload(func(namespace string, s ReloadSummary) {
+		reloaded = append(reloaded, namespace)
+	})
+
+	if err := cache.Reload("game-b"); err != nil {
+		t.Fatalf("Reload() unexpected error = %v", err)
+	}
+	if !reflect.DeepEqual(reloaded, []string{"game-b"}) {
+		t.Errorf("hooks ran for %v, want only game-b", reloaded)
+	}
+}
@@ -0,0 +1,68 @@
+package cache
+
+import (
+	"maps"
+	"slices"
+
+	"github.com/AccelByte/extend-challenge-common/pkg/domain"
+)
+
+// StatCodeInfo aggregates the enabled goals tracking one stat code, e.g. for an event
+// consumer deciding which statistic topics to subscribe to.
+type StatCodeInfo struct {
+	GoalCount      int  // Enabled goals tracking the stat code
+	MaxTargetValue int  // Highest Requirement.TargetValue among them
+	HasDailyGoals  bool // Whether any of them counts once per day (daily type, or increment with Daily)
+}
+
+// buildStatCodeSummary aggregates the stat code index. Returns the summary and its stat
+// codes, sorted.
+func buildStatCodeSummary(goalsByStatCode map[string][]*domain.Goal) (map[string]StatCodeInfo, []string) {
+	summary := make(map[string]StatCodeInfo, len(goalsByStatCode))
+	for statCode, goals := range goalsByStatCode {
+		var info StatCodeInfo
+		for _, goal := range goals {
+			info.GoalCount++
+			info.MaxTargetValue = max(info.MaxTargetValue, goal.Requirement.TargetValue)
+			info.HasDailyGoals = info.HasDailyGoals || goal.EffectiveType() == domain.GoalTypeDaily || goal.Daily
+		}
+		summary[statCode] = info
+	}
+	return summary, slices.Sorted(maps.Keys(summary))
+}
+
+// GetAllStatCodes returns the stat codes tracked by at least one enabled goal, sorted.
+// Time complexity: O(n) where n is the number of stat codes
+func (c *InMemoryGoalCache) GetAllStatCodes() []string {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	// Copied: unlike Goals, a caller may reasonably sort or filter the result in place
+	return slices.Clone(c.statCodes)
+}
+
+// GetStatCodeSummary returns the aggregate of the enabled goals tracking each stat code,
+// keyed by stat code. The map is a copy the caller may modify.
+// Time complexity: O(n) where n is the number of stat codes
+func (c *InMemoryGoalCache) GetStatCodeSummary() map[string]StatCodeInfo {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	return maps.Clone(c.statCodeSummary)
+}
+
+// diffStatCodes returns the stat codes only in current and only in previous, both sorted
+// inputs. Results are nil when empty.
+func diffStatCodes(previous, current []string) (added, removed []string) {
+	for _, statCode := range current {
+		if _, found := slices.BinarySearch(previous, statCode); !found {
+			added = append(added, statCode)
+		}
+	}
+	for _, statCode := range previous {
+		if _, found := slices.BinarySearch(current, statCode); !found {
+			removed = append(removed, statCode)
+		}
+	}
+	return added, removed
+}
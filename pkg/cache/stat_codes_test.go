@@ -0,0 +1,94 @@
+package cache
+
+import (
+	"encoding/json"
+	"io"
+	"log/slog"
+	"maps"
+	"reflect"
+	"slices"
+	"testing"
+
+	"github.com/AccelByte/extend-challenge-common/pkg/domain"
+)
+
+func TestInMemoryGoalCache_GetStatCodeSummary(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+
+	cache := NewInMemoryGoalCache(createTestConfig(), "", logger)
+
+	if got := cache.GetAllStatCodes(); !slices.Equal(got, []string{"stat_code_1", "stat_code_2"}) {
+		t.Errorf("GetAllStatCodes() = %v, want [stat_code_1 stat_code_2]", got)
+	}
+	want := map[string]StatCodeInfo{
+		"stat_code_1": {GoalCount: 2, MaxTargetValue: 30},
+		"stat_code_2": {GoalCount: 1, MaxTargetValue: 20},
+	}
+	if got := cache.GetStatCodeSummary(); !maps.Equal(got, want) {
+		t.Errorf("GetStatCodeSummary() = %v, want %v", got, want)
+	}
+
+	t.Run("daily and disabled goals", func(t *testing.T) {
+		cfg := createTestConfig()
+		goals := cfg.Challenges[0].Goals
+		goals[0].Type, goals[0].Daily = domain.GoalTypeIncrement, true
+		disabled := false
+		goals[1].Enabled = &disabled
+
+		cache := NewInMemoryGoalCache(cfg, "", logger)
+
+		// stat_code_2 loses its only goal; goal-1 makes stat_code_1 daily
+		if got := cache.GetAllStatCodes(); !slices.Equal(got, []string{"stat_code_1"}) {
+			t.Errorf("GetAllStatCodes() = %v, want [stat_code_1]", got)
+		}
+		if got := cache.GetStatCodeSummary()["stat_code_1"]; got != (StatCodeInfo{GoalCount: 2, MaxTargetValue: 30, HasDailyGoals: true}) {
+			t.Errorf("stat_code_1 summary = %+v, want 2 goals, max 30, daily", got)
+		}
+	})
+
+	t.Run("results are copies", func(t *testing.T) {
+		cache.GetAllStatCodes()[0] = "mutated"
+		delete(cache.GetStatCodeSummary(), "stat_code_1")
+
+		if got := cache.GetAllStatCodes(); got[0] != "stat_code_1" {
+			t.Errorf("GetAllStatCodes() = %v after mutating an earlier result", got)
+		}
+		if _, ok := cache.GetStatCodeSummary()["stat_code_1"]; !ok {
+			t.Error("GetStatCodeSummary() lost stat_code_1 after mutating an earlier result")
+		}
+	})
+}
+
+func TestInMemoryGoalCache_OnReload_StatCodes(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+
+	// Reloaded config: goal-2 moves from stat_code_2 to stat_code_3
+	next := createTestConfig()
+	next.Challenges[0].Goals[1].Requirement.StatCode = "stat_code_3"
+	data, err := json.Marshal(next)
+	if err != nil {
+		t.Fatalf("Marshal failed: %v", err)
+	}
+
+	cache := NewInMemoryGoalCache(createTestConfig(), createTempConfigFile(t, string(data)), logger)
+
+	var summary ReloadSummary
+	cache.OnReload(func(s ReloadSummary) { summary = s })
+
+	if err := cache.Reload(); err != nil {
+		t.Fatalf("Reload() unexpected error = %v", err)
+	}
+
+	if !reflect.DeepEqual(summary.AddedStatCodes, []string{"stat_code_3"}) {
+		t.Errorf("AddedStatCodes = %v, want [stat_code_3]", summary.AddedStatCodes)
+	}
+	if !reflect.DeepEqual(summary.RemovedStatCodes, []string{"stat_code_2"}) {
+		t.Errorf("RemovedStatCodes = %v, want [stat_code_2]", summary.RemovedStatCodes)
+	}
+	if got := cache.GetAllStatCodes(); !slices.Equal(got, []string{"stat_code_1", "stat_code_3"}) {
+		t.Errorf("GetAllStatCodes() after reload = %v, want [stat_code_1 stat_code_3]", got)
+	}
+	if got := cache.GetStatCodeSummary()["stat_code_3"]; got != (StatCodeInfo{GoalCount: 1, MaxTargetValue: 20}) {
+		t.Errorf("stat_code_3 summary = %+v, want 1 goal, max 20", got)
+	}
+}
@@ -0,0 +1,68 @@
+package config
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+)
+
+// checkDuplicateKeys scans a JSON document and returns an error for the first object key
+// that appears twice in the same object. json.Unmarshal silently keeps the last value for
+// duplicate keys, which can hide a mis-edited config (e.g., a second "reward" block).
+//
+// The error names the JSON path of the duplicate, e.g. "challenges[2].goals[0].reward".
+func checkDuplicateKeys(data []byte) error {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber() // Skip float parsing; values are only walked, not used
+
+	return walkJSONValue(dec, "")
+}
+
+// walkJSONValue consumes one JSON value from dec, recursing into objects and arrays.
+func walkJSONValue(dec *json.Decoder, path string) error {
+	tok, err := dec.Token()
+	if err != nil {
+		return err
+	}
+
+	delim, ok := tok.(json.Delim)
+	if !ok {
+		return nil // Scalar value
+	}
+
+	switch delim {
+	case '{':
+		seen := make(map[string]struct{})
+		for dec.More() {
+			keyTok, err := dec.Token()
+			if err != nil {
+				return err
+			}
+			key := keyTok.(string) // Object keys are always strings
+
+			keyPath := key
+			if path != "" {
+				keyPath = path + "." + key
+			}
+
+			if _, dup := seen[key]; dup {
+				return fmt.Errorf("duplicate key %q at %s", key, keyPath)
+			}
+			seen[key] = struct{}{}
+
+			if err := walkJSONValue(dec, keyPath); err != nil {
+				return err
+			}
+		}
+	case '[':
+		for i := 0; dec.More(); i++ {
+			if err := walkJSONValue(dec, fmt.Sprintf("%s[%d]", path, i)); err != nil {
+				return err
+			}
+		}
+	}
+
+	// Consume the closing delimiter
+	_, err = dec.Token()
+	return err
+}
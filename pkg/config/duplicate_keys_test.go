@@ -0,0 +1,126 @@
+package config
+
+import (
+	"fmt"
+	"log/slog"
+	"os"
+	"strings"
+	"testing"
+)
+
+func TestCheckDuplicateKeys(t *testing.T) {
+	tests := []struct {
+		name    string
+		json    string
+		wantErr string
+	}{
+		{
+			name: "clean config",
+			json: `{"challenges": [{"id": "c1", "goals": [{"id": "g1", "reward": {"type": "ITEM", "quantity": 1}}]}]}`,
+		},
+		{
+			name:    "duplicated scalar key",
+			json:    `{"challenges": [{"id": "c1", "name": "A", "name": "B", "goals": []}]}`,
+			wantErr: `duplicate key "name" at challenges[0].name`,
+		},
+		{
+			name: "duplicated nested object key",
+			json: `{"challenges": [
+				{"id": "c1", "goals": []},
+				{"id": "c2", "goals": []},
+				{"id": "c3", "goals": [{"id": "g1", "reward": {"quantity": 1}, "reward": {"quantity": 2}}]}
+			]}`,
+			wantErr: `duplicate key "reward" at challenges[2].goals[0].reward`,
+		},
+		{
+			name:    "duplicated top-level key",
+			json:    `{"challenges": [], "challenges": []}`,
+			wantErr: `duplicate key "challenges" at challenges`,
+		},
+		{
+			name: "same key in sibling objects is allowed",
+			json: `{"challenges": [{"id": "c1"}, {"id": "c2"}]}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := checkDuplicateKeys([]byte(tt.json))
+
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("Expected no error, got %v", err)
+				}
+				return
+			}
+			if err == nil || err.Error() != tt.wantErr {
+				t.Errorf("Expected error %q, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestConfigLoader_StrictDuplicateKeys(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(os.Stdout, nil))
+	configPath := createTempConfigFile(t, `{
+		"challenges": [{
+			"challengeId": "c1",
+			"name": "Challenge",
+			"description": "Test",
+			"goals": [{
+				"goalId": "g1",
+				"name": "Goal",
+				"description": "Test",
+				"type": "absolute",
+				"eventSource": "statistic",
+				"requirement": {"statCode": "kills", "operator": ">=", "targetValue": 10},
+				"reward": {"type": "ITEM", "rewardId": "sword", "quantity": 1},
+				"reward": {"type": "ITEM", "rewardId": "shield", "quantity": 1}
+			}]
+		}]
+	}`)
+
+	t.Run("lenient by default", func(t *testing.T) {
+		if _, err := NewConfigLoader(configPath, logger).LoadConfig(); err != nil {
+			t.Errorf("Expected default loader to accept duplicate keys, got %v", err)
+		}
+	})
+
+	t.Run("strict rejects duplicate", func(t *testing.T) {
+		_, err := NewConfigLoader(configPath, logger, WithStrictDuplicateKeys(true)).LoadConfig()
+		if err == nil || !strings.Contains(err.Error(), "challenges[0].goals[0].reward") {
+			t.Errorf("Expected duplicate key error with path, got %v", err)
+		}
+	})
+}
+
+// BenchmarkCheckDuplicateKeys measures the strict-mode scan on a ~1MB config.
+func BenchmarkCheckDuplicateKeys(b *testing.B) {
+	var sb strings.Builder
+	sb.WriteString(`{"challenges": [`)
+	for i := 0; sb.Len() < 1<<20; i++ {
+		if i > 0 {
+			sb.WriteString(",")
+		}
+		fmt.Fprintf(&sb, `{"id": "challenge-%d", "name": "Challenge %d", "description": "Benchmark", "goals": [`, i, i)
+		for j := 0; j < 10; j++ {
+			if j > 0 {
+				sb.WriteString(",")
+			}
+			fmt.Fprintf(&sb, `{"id": "goal-%d-%d", "name": "Goal", "type": "absolute", "event_source": "statistic", `+
+				`"requirement": {"stat_code": "kills", "operator": ">=", "target_value": %d}, `+
+				`"reward": {"type": "ITEM", "reward_id": "sword", "quantity": 1}}`, i, j, j+1)
+		}
+		sb.WriteString("]}")
+	}
+	sb.WriteString("]}")
+	data := []byte(sb.String())
+
+	b.SetBytes(int64(len(data)))
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if err := checkDuplicateKeys(data); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
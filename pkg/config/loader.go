@@ -5,14 +5,46 @@ import (
 	"fmt"
 	"log/slog"
 	"os"
+
+	"github.com/AccelByte/extend-challenge-common/pkg/config/schema"
 )
 
 // ConfigLoader loads and validates challenge configuration from a JSON file.
 // It performs file reading, JSON parsing, and comprehensive validation.
 type ConfigLoader struct {
-	configPath string
-	validator  *Validator
-	logger     *slog.Logger
+	configPath   string
+	validator    *Validator
+	logger       *slog.Logger
+	strictKeys   bool // Reject duplicate JSON object keys
+	strictSchema bool // Validate raw JSON against the generated JSON Schema
+}
+
+// LoaderOption configures a ConfigLoader.
+type LoaderOption func(*ConfigLoader)
+
+// WithStrictDuplicateKeys rejects configs that define the same object key twice
+// (e.g., two "reward" blocks in one goal) instead of silently keeping the last value.
+// Off by default; the goal cache enables it for Reload.
+func WithStrictDuplicateKeys(strict bool) LoaderOption {
+	return func(l *ConfigLoader) {
+		l.strictKeys = strict
+	}
+}
+
+// WithStrictSchema validates the raw config against the JSON Schema from schema.SchemaJSON
+// before parsing, so unknown properties, enum violations and wrong types are reported with
+// the JSON pointer of the offending value. Off by default.
+func WithStrictSchema(strict bool) LoaderOption {
+	return func(l *ConfigLoader) {
+		l.strictSchema = strict
+	}
+}
+
+// WithValidatorOptions enables optional validation rules (e.g., required localization keys).
+func WithValidatorOptions(opts ValidatorOptions) LoaderOption {
+	return func(l *ConfigLoader) {
+		l.validator = NewValidatorWithOptions(opts)
+	}
 }
 
 // NewConfigLoader creates a new ConfigLoader instance.
@@ -20,19 +52,25 @@ type ConfigLoader struct {
 // Parameters:
 //   - configPath: Path to the challenges.json file
 //   - logger: Structured logger for operational logging
-func NewConfigLoader(configPath string, logger *slog.Logger) *ConfigLoader {
-	return &ConfigLoader{
+//   - opts: Optional loader settings (e.g., WithStrictDuplicateKeys)
+func NewConfigLoader(configPath string, logger *slog.Logger, opts ...LoaderOption) *ConfigLoader {
+	l := &ConfigLoader{
 		configPath: configPath,
 		validator:  NewValidator(),
 		logger:     logger,
 	}
+	for _, opt := range opts {
+		opt(l)
+	}
+	return l
 }
 
 // LoadConfig loads the configuration file and returns a validated Config.
-// This method performs three steps:
+// This method performs four steps:
 // 1. Read the config file from disk
-// 2. Parse JSON into Config struct
-// 3. Validate all business rules
+// 2. Expand goal templates (see expandGoalTemplates)
+// 3. Parse JSON into Config struct
+// 4. Validate all business rules
 //
 // If any step fails, returns an error and the application should exit.
 // This is a "fail fast" operation - invalid config prevents startup.
@@ -47,7 +85,23 @@ func (l *ConfigLoader) LoadConfig() (*Config, error) {
 		return nil, fmt.Errorf("failed to read config file: %w", err)
 	}
 
-	// Step 2: Parse JSON
+	// Step 2: Parse JSON (strict mode rejects duplicate keys and schema violations first).
+	// Templates are expanded before the schema check, which only knows plain goals.
+	if l.strictKeys {
+		if err := checkDuplicateKeys(data); err != nil {
+			return nil, fmt.Errorf("failed to parse config JSON: %w", err)
+		}
+	}
+	data, err = expandGoalTemplates(data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to expand goal templates: %w", err)
+	}
+	if l.strictSchema {
+		if err := schema.ValidateAgainstSchema(data); err != nil {
+			return nil, fmt.Errorf("config does not match schema: %w", err)
+		}
+	}
+
 	var config Config
 	if err := json.Unmarshal(data, &config); err != nil {
 		return nil, fmt.Errorf("failed to parse config JSON: %w", err)
@@ -55,21 +109,29 @@ func (l *ConfigLoader) LoadConfig() (*Config, error) {
 
 	// Step 3: Populate ChallengeID and set default Type for each Goal
 	// This links each goal to its parent challenge for easier lookups
-	// and provides backward compatibility for configs without explicit type
+	// and provides backward compatibility for configs without explicit type.
+	// An explicit ChallengeID is kept so the validator can reject mismatches.
+	// A single "reward" is copied into Rewards; a goal that also sets "rewards" is left
+	// as is so the validator rejects it.
 	for _, challenge := range config.Challenges {
 		for _, goal := range challenge.Goals {
-			goal.ChallengeID = challenge.ID
-			// Backward compatibility: default to "absolute" if type is empty
-			if goal.Type == "" {
-				goal.Type = "absolute"
+			if goal.ChallengeID == "" {
+				goal.ChallengeID = challenge.ID
 			}
+			// Backward compatibility: default to "absolute" if type is empty
+			goal.Type = goal.EffectiveType()
+			goal.NormalizeRewards()
 		}
 	}
 
 	// Step 4: Validate
-	if err := l.validator.Validate(&config); err != nil {
+	warnings, err := l.validator.ValidateWithWarnings(&config)
+	if err != nil {
 		return nil, fmt.Errorf("config validation failed: %w", err)
 	}
+	for _, warning := range warnings {
+		l.logger.Warn("Config validation warning", "warning", warning, "config_path", l.configPath)
+	}
 
 	// Log success
 	totalGoals := l.countGoals(&config)
@@ -452,3 +452,100 @@ func createTempConfigFile(t *testing.T, content string) string {
 
 	return tmpFile
 }
+
+func TestConfigLoader_StrictSchema(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(os.Stdout, nil))
+	configPath := createTempConfigFile(t, `{
+		"challenges": [{
+			"challengeId": "c1",
+			"name": "Challenge",
+			"goals": [{
+				"goalId": "g1",
+				"name": "Goal",
+				"eventSource": "statistic",
+				"requirement": {"statCode": "kills", "operator": ">=", "targetValue": 10},
+				"reward": {"type": "ITEM", "rewardId": "sword", "quantity": 1},
+				"rewardz": []
+			}]
+		}]
+	}`)
+
+	t.Run("lenient by default", func(t *testing.T) {
+		if _, err := NewConfigLoader(configPath, logger).LoadConfig(); err != nil {
+			t.Errorf("Expected default loader to ignore unknown properties, got %v", err)
+		}
+	})
+
+	t.Run("strict rejects unknown property", func(t *testing.T) {
+		_, err := NewConfigLoader(configPath, logger, WithStrictSchema(true)).LoadConfig()
+		want := "config does not match schema: /challenges/0/goals/0/rewardz: unknown property"
+		if err == nil || err.Error() != want {
+			t.Errorf("Expected error %q, got %v", want, err)
+		}
+	})
+}
+
+func TestConfigLoader_RewardFormats(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(os.Stdout, nil))
+	configWithReward := func(reward string) string {
+		return `{
+			"challenges": [{
+				"challengeId": "c1",
+				"name": "Challenge",
+				"goals": [{
+					"goalId": "g1",
+					"name": "Goal",
+					"eventSource": "statistic",
+					"requirement": {"statCode": "kills", "operator": ">=", "targetValue": 10},
+					` + reward + `
+				}]
+			}]
+		}`
+	}
+
+	t.Run("single reward is normalized", func(t *testing.T) {
+		configPath := createTempConfigFile(t, configWithReward(`"reward": {"type": "ITEM", "rewardId": "sword", "quantity": 1}`))
+		cfg, err := NewConfigLoader(configPath, logger).LoadConfig()
+		if err != nil {
+			t.Fatalf("LoadConfig() unexpected error = %v", err)
+		}
+
+		goal := cfg.Challenges[0].Goals[0]
+		want := domain.Reward{Type: "ITEM", RewardID: "sword", Quantity: 1}
+		if len(goal.Rewards) != 1 || goal.Rewards[0] != want {
+			t.Errorf("Rewards = %v, want [%v]", goal.Rewards, want)
+		}
+		if goal.Reward != want {
+			t.Errorf("Reward = %v, want %v (kept for old readers)", goal.Reward, want)
+		}
+	})
+
+	t.Run("reward bundle loads as is", func(t *testing.T) {
+		configPath := createTempConfigFile(t, configWithReward(`"rewards": [
+			{"type": "ITEM", "rewardId": "sword", "quantity": 1},
+			{"type": "WALLET", "rewardId": "GOLD", "quantity": 100},
+			{"type": "ITEM", "rewardId": "shield", "quantity": 1}
+		]`))
+		cfg, err := NewConfigLoader(configPath, logger).LoadConfig()
+		if err != nil {
+			t.Fatalf("LoadConfig() unexpected error = %v", err)
+		}
+
+		goal := cfg.Challenges[0].Goals[0]
+		if len(goal.Rewards) != 3 {
+			t.Errorf("Rewards = %v, want 3 rewards", goal.Rewards)
+		}
+		if goal.Reward != (domain.Reward{}) {
+			t.Errorf("Reward = %v, want it left empty", goal.Reward)
+		}
+	})
+
+	t.Run("both reward and rewards are rejected", func(t *testing.T) {
+		configPath := createTempConfigFile(t, configWithReward(`"reward": {"type": "ITEM", "rewardId": "sword", "quantity": 1},
+			"rewards": [{"type": "WALLET", "rewardId": "GOLD", "quantity": 100}]`))
+		_, err := NewConfigLoader(configPath, logger).LoadConfig()
+		if err == nil || !strings.Contains(err.Error(), "set either reward or rewards, not both") {
+			t.Errorf("Expected mixed reward error, got %v", err)
+		}
+	})
+}
@@ -0,0 +1,196 @@
+// Package schema describes challenges.json as a JSON Schema (draft 2020-12) for external
+// tooling such as config editors, and validates raw config documents against it.
+//
+// The schema is generated from the domain structs, so field names and types follow the
+// JSON tags the loader decodes. Value rules that editors can check locally (enums,
+// minimums, required fields) are added per field; cross-field rules such as prerequisite
+// references stay in config.Validator.
+package schema
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+
+	"github.com/AccelByte/extend-challenge-common/pkg/domain"
+)
+
+// Draft is the JSON Schema dialect of the generated schema.
+const Draft = "https://json-schema.org/draft/2020-12/schema"
+
+// durationPattern matches the Go duration strings accepted by domain.Duration.
+const durationPattern = `^(0|([0-9]+(\.[0-9]*)?(ns|us|µs|ms|s|m|h))+)$`
+
+// Schema is the subset of JSON Schema used to describe the config.
+type Schema struct {
+	Schema               string             `json:"$schema,omitempty"`
+	Title                string             `json:"title,omitempty"`
+	Ref                  string             `json:"$ref,omitempty"`
+	Type                 Types              `json:"type,omitempty"`
+	Enum                 []string           `json:"enum,omitempty"`
+	Pattern              string             `json:"pattern,omitempty"`
+	MinLength            *int               `json:"minLength,omitempty"`
+	Minimum              *int64             `json:"minimum,omitempty"`
+	Items                *Schema            `json:"items,omitempty"`
+	MinItems             *int               `json:"minItems,omitempty"`
+	Properties           map[string]*Schema `json:"properties,omitempty"`
+	Required             []string           `json:"required,omitempty"`
+	AdditionalProperties *bool              `json:"additionalProperties,omitempty"`
+	Defs                 map[string]*Schema `json:"$defs,omitempty"`
+}
+
+// Types is the "type" keyword. A single type is written as a string, several as an array.
+type Types []string
+
+// MarshalJSON writes a single type as a plain string.
+func (t Types) MarshalJSON() ([]byte, error) {
+	if len(t) == 1 {
+		return json.Marshal(t[0])
+	}
+	return json.Marshal([]string(t))
+}
+
+// fieldRule adds value constraints to one generated field schema.
+type fieldRule func(*Schema)
+
+func minLength(n int) fieldRule { return func(s *Schema) { s.MinLength = &n } }
+func minimum(n int64) fieldRule { return func(s *Schema) { s.Minimum = &n } }
+func minItems(n int) fieldRule  { return func(s *Schema) { s.MinItems = &n } }
+func itemMinLength(n int) fieldRule {
+	return func(s *Schema) { s.Items.MinLength = &n }
+}
+func enum(values ...string) fieldRule {
+	return func(s *Schema) { s.Enum = values }
+}
+
+// fieldRules mirror the per-field checks of config.Validator, keyed by "Type.jsonName".
+// Rules that depend on other fields (e.g., daily only for increment goals) are not here.
+func fieldRules() map[string][]fieldRule {
+	eventSources := domain.EventSources()
+	sources := make([]string, len(eventSources))
+	for i, e := range eventSources {
+		sources[i] = string(e)
+	}
+
+	return map[string][]fieldRule{
+		"Challenge.challengeId": {minLength(1)},
+		"Challenge.name":        {minLength(1)},
+		"Challenge.goals":       {minItems(1)},
+
+		"Goal.goalId":       {minLength(1)},
+		"Goal.name":         {minLength(1)},
+		"Goal.type":         {enum(string(domain.GoalTypeAbsolute), string(domain.GoalTypeIncrement), string(domain.GoalTypeDaily))},
+		"Goal.eventSource":  {enum(sources...)},
+		"Goal.rotationWeek": {minimum(0)},
+		"Goal.tags":         {itemMinLength(1)},
+		"Goal.difficulty":   {enum(domain.Difficulties()...)},
+		"Goal.order":        {minimum(0)},
+
+		"Requirement.statCode":    {minLength(1)},
+		"Requirement.operator":    {enum(">=")},
+		"Requirement.targetValue": {minimum(1)},
+
+		"Reward.type":     {enum(string(domain.RewardTypeItem), string(domain.RewardTypeWallet))},
+		"Reward.rewardId": {minLength(1)},
+		"Reward.quantity": {minimum(1)},
+	}
+}
+
+// requiredFields lists the properties each object must set, keyed by type name.
+var requiredFields = map[string][]string{
+	"Challenge":   {"challengeId", "name", "goals"},
+	"Goal":        {"goalId", "name", "eventSource", "requirement"},
+	"Requirement": {"statCode", "operator", "targetValue"},
+	"Reward":      {"type", "rewardId", "quantity"},
+}
+
+var durationType = reflect.TypeOf(domain.Duration(0))
+
+// Generate builds the config schema. Event sources added with domain.RegisterEventSource
+// before the call are included in the eventSource enum.
+func Generate() *Schema {
+	g := &generator{defs: make(map[string]*Schema), rules: fieldRules()}
+	closed := false
+
+	return &Schema{
+		Schema: Draft,
+		Title:  "Challenge configuration",
+		Type:   Types{"object"},
+		Properties: map[string]*Schema{
+			"challenges": {Type: Types{"array"}, Items: g.schemaFor(reflect.TypeOf(domain.Challenge{}), "")},
+		},
+		Required:             []string{"challenges"},
+		AdditionalProperties: &closed,
+		Defs:                 g.defs,
+	}
+}
+
+// SchemaJSON returns the generated schema as indented JSON.
+func SchemaJSON() []byte {
+	data, err := json.MarshalIndent(Generate(), "", "  ")
+	if err != nil {
+		panic("schema: marshal generated schema: " + err.Error()) // Schema holds only plain values
+	}
+	return append(data, '\n')
+}
+
+type generator struct {
+	defs  map[string]*Schema
+	rules map[string][]fieldRule
+}
+
+// schemaFor returns the schema of a value of type t; rulesKey selects its field rules.
+// Structs are emitted once under $defs and referenced.
+func (g *generator) schemaFor(t reflect.Type, rulesKey string) *Schema {
+	if t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+
+	var s *Schema
+	switch {
+	case t == durationType:
+		// domain.Duration accepts a Go duration string or a number of seconds
+		s = &Schema{Type: Types{"string", "number"}, Pattern: durationPattern, Minimum: new(int64)}
+	case t.Kind() == reflect.Struct:
+		g.defineStruct(t)
+		s = &Schema{Ref: "#/$defs/" + t.Name()}
+	case t.Kind() == reflect.Slice:
+		s = &Schema{Type: Types{"array"}, Items: g.schemaFor(t.Elem(), "")}
+	case t.Kind() == reflect.String:
+		s = &Schema{Type: Types{"string"}}
+	case t.Kind() == reflect.Bool:
+		s = &Schema{Type: Types{"boolean"}}
+	default:
+		s = &Schema{Type: Types{"integer"}}
+	}
+
+	for _, rule := range g.rules[rulesKey] {
+		rule(s)
+	}
+	return s
+}
+
+// defineStruct adds the object schema of t to $defs.
+func (g *generator) defineStruct(t reflect.Type) {
+	if _, ok := g.defs[t.Name()]; ok {
+		return
+	}
+
+	closed := false
+	def := &Schema{
+		Type:                 Types{"object"},
+		Properties:           make(map[string]*Schema),
+		Required:             requiredFields[t.Name()],
+		AdditionalProperties: &closed,
+	}
+	g.defs[t.Name()] = def
+
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+		if !field.IsExported() || name == "" || name == "-" {
+			continue
+		}
+		def.Properties[name] = g.schemaFor(field.Type, t.Name()+"."+name)
+	}
+}
@@ -0,0 +1,75 @@
+package schema
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"os"
+	"testing"
+)
+
+var update = flag.Bool("update", false, "rewrite challenges.schema.json from the generator")
+
+const goldenFile = "challenges.schema.json"
+
+func TestSchemaJSON_Golden(t *testing.T) {
+	got := SchemaJSON()
+
+	if *update {
+		if err := os.WriteFile(goldenFile, got, 0o644); err != nil {
+			t.Fatalf("Failed to update %s: %v", goldenFile, err)
+		}
+	}
+
+	want, err := os.ReadFile(goldenFile)
+	if err != nil {
+		t.Fatalf("Failed to read %s: %v", goldenFile, err)
+	}
+	if !bytes.Equal(got, want) {
+		t.Errorf("%s is out of date; run go test ./pkg/config/schema -update", goldenFile)
+	}
+}
+
+func TestGenerate(t *testing.T) {
+	s := Generate()
+
+	if s.Schema != Draft {
+		t.Errorf("$schema = %q, want %q", s.Schema, Draft)
+	}
+	for _, name := range []string{"Challenge", "Goal", "Requirement", "Reward"} {
+		if s.Defs[name] == nil {
+			t.Errorf("$defs is missing %s", name)
+		}
+	}
+
+	goal := s.Defs["Goal"]
+	if got := goal.Properties["eventSource"].Enum; len(got) < 2 || got[0] != "statistic" || got[1] != "login" {
+		t.Errorf("eventSource enum = %v, want built-in sources first", got)
+	}
+	if got := goal.Properties["rewards"]; got.Items == nil || got.Items.Ref != "#/$defs/Reward" {
+		t.Errorf("rewards = %+v, want an array of Reward", got)
+	}
+	if got := goal.Properties["claimDeadline"].Type; len(got) != 2 {
+		t.Errorf("claimDeadline type = %v, want string or number", got)
+	}
+}
+
+func TestTypes_MarshalJSON(t *testing.T) {
+	tests := []struct {
+		types Types
+		want  string
+	}{
+		{Types{"string"}, `"string"`},
+		{Types{"string", "number"}, `["string","number"]`},
+	}
+
+	for _, tt := range tests {
+		got, err := json.Marshal(tt.types)
+		if err != nil {
+			t.Fatalf("Marshal(%v) failed: %v", tt.types, err)
+		}
+		if string(got) != tt.want {
+			t.Errorf("Marshal(%v) = %s, want %s", tt.types, got, tt.want)
+		}
+	}
+}
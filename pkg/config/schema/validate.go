@@ -0,0 +1,220 @@
+package schema
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Violation is a single schema rule a config document breaks.
+type Violation struct {
+	Pointer string // JSON pointer (RFC 6901) to the offending value, e.g. "/challenges/0/goals/2"
+	Message string
+}
+
+// String formats the violation as "pointer: message".
+func (v Violation) String() string {
+	return v.Pointer + ": " + v.Message
+}
+
+// ValidationError lists every violation found in a document. Object properties are
+// visited in sorted order and array items by index, so the order is deterministic.
+type ValidationError struct {
+	Violations []Violation
+}
+
+// Error joins the violations with "; ".
+func (e *ValidationError) Error() string {
+	parts := make([]string, len(e.Violations))
+	for i, v := range e.Violations {
+		parts[i] = v.String()
+	}
+	return strings.Join(parts, "; ")
+}
+
+// ValidateAgainstSchema checks raw config JSON against the generated schema. It returns a
+// *ValidationError listing every violation, or a parse error if raw is not valid JSON.
+func ValidateAgainstSchema(raw []byte) error {
+	dec := json.NewDecoder(bytes.NewReader(raw))
+	dec.UseNumber()
+
+	var doc interface{}
+	if err := dec.Decode(&doc); err != nil {
+		return fmt.Errorf("invalid JSON: %w", err)
+	}
+
+	root := Generate()
+	v := &validator{defs: root.Defs, patterns: make(map[string]*regexp.Regexp)}
+	v.validate(root, doc, "")
+
+	if len(v.violations) > 0 {
+		return &ValidationError{Violations: v.violations}
+	}
+	return nil
+}
+
+type validator struct {
+	defs       map[string]*Schema
+	patterns   map[string]*regexp.Regexp
+	violations []Violation
+}
+
+func (v *validator) fail(pointer, format string, args ...interface{}) {
+	if pointer == "" {
+		pointer = "/"
+	}
+	v.violations = append(v.violations, Violation{Pointer: pointer, Message: fmt.Sprintf(format, args...)})
+}
+
+// validate checks value against s; pointer locates value in the document.
+func (v *validator) validate(s *Schema, value interface{}, pointer string) {
+	if s.Ref != "" {
+		s = v.defs[strings.TrimPrefix(s.Ref, "#/$defs/")]
+	}
+
+	kind, ok := v.matchType(s.Type, value)
+	if !ok {
+		v.fail(pointer, "must be %s", strings.Join(s.Type, " or "))
+		return
+	}
+
+	switch kind {
+	case "object":
+		v.validateObject(s, value.(map[string]interface{}), pointer)
+	case "array":
+		v.validateArray(s, value.([]interface{}), pointer)
+	case "string":
+		v.validateString(s, value.(string), pointer)
+	case "integer", "number":
+		v.validateNumber(s, value.(json.Number), pointer)
+	}
+}
+
+// matchType returns the schema type value satisfies; ok is false if it satisfies none.
+func (v *validator) matchType(types Types, value interface{}) (string, bool) {
+	for _, t := range types {
+		switch value.(type) {
+		case map[string]interface{}:
+			if t == "object" {
+				return t, true
+			}
+		case []interface{}:
+			if t == "array" {
+				return t, true
+			}
+		case string:
+			if t == "string" {
+				return t, true
+			}
+		case bool:
+			if t == "boolean" {
+				return t, true
+			}
+		case json.Number:
+			if t == "number" {
+				return t, true
+			}
+			if _, err := value.(json.Number).Int64(); t == "integer" && err == nil {
+				return t, true
+			}
+		}
+	}
+	return "", false
+}
+
+func (v *validator) validateObject(s *Schema, obj map[string]interface{}, pointer string) {
+	for _, name := range s.Required {
+		if _, ok := obj[name]; !ok {
+			v.fail(pointer+"/"+escapeToken(name), "is required")
+		}
+	}
+
+	names := make([]string, 0, len(obj))
+	for name := range obj {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		child := pointer + "/" + escapeToken(name)
+		prop, ok := s.Properties[name]
+		if !ok {
+			if s.AdditionalProperties != nil && !*s.AdditionalProperties {
+				v.fail(child, "unknown property")
+			}
+			continue
+		}
+		v.validate(prop, obj[name], child)
+	}
+}
+
+func (v *validator) validateArray(s *Schema, items []interface{}, pointer string) {
+	if s.MinItems != nil && len(items) < *s.MinItems {
+		v.fail(pointer, "must contain at least %d item(s)", *s.MinItems)
+	}
+	if s.Items == nil {
+		return
+	}
+	for i, item := range items {
+		v.validate(s.Items, item, pointer+"/"+strconv.Itoa(i))
+	}
+}
+
+func (v *validator) validateString(s *Schema, value, pointer string) {
+	if len(s.Enum) > 0 && !contains(s.Enum, value) {
+		quoted := make([]string, len(s.Enum))
+		for i, e := range s.Enum {
+			quoted[i] = strconv.Quote(e)
+		}
+		v.fail(pointer, "must be one of %s", strings.Join(quoted, ", "))
+		return
+	}
+	if s.MinLength != nil && len([]rune(value)) < *s.MinLength {
+		if *s.MinLength == 1 {
+			v.fail(pointer, "must not be empty")
+		} else {
+			v.fail(pointer, "must be at least %d characters", *s.MinLength)
+		}
+		return
+	}
+	if s.Pattern != "" && !v.pattern(s.Pattern).MatchString(value) {
+		v.fail(pointer, "must match pattern %s", s.Pattern)
+	}
+}
+
+func (v *validator) validateNumber(s *Schema, value json.Number, pointer string) {
+	if s.Minimum == nil {
+		return
+	}
+	if n, err := value.Float64(); err == nil && n < float64(*s.Minimum) {
+		v.fail(pointer, "must be >= %d", *s.Minimum)
+	}
+}
+
+// pattern compiles and caches a schema pattern.
+func (v *validator) pattern(expr string) *regexp.Regexp {
+	re, ok := v.patterns[expr]
+	if !ok {
+		re = regexp.MustCompile(expr)
+		v.patterns[expr] = re
+	}
+	return re
+}
+
+// escapeToken escapes a property name for use in a JSON pointer.
+func escapeToken(name string) string {
+	return strings.NewReplacer("~", "~0", "/", "~1").Replace(name)
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,134 @@
+package schema
+
+import (
+	stderrors "errors"
+	"os"
+	"strings"
+	"testing"
+)
+
+// validGoal is a goal fragment that passes the schema; tests override single fields.
+const validGoal = `{
+	"goalId": "g1",
+	"name": "Goal",
+	"eventSource": "statistic",
+	"requirement": {"statCode": "kills", "operator": ">=", "targetValue": 10},
+	"reward": {"type": "ITEM", "rewardId": "sword", "quantity": 1}
+}`
+
+// configWithGoals wraps goal fragments in a single-challenge config.
+func configWithGoals(goals ...string) []byte {
+	return []byte(`{"challenges": [{"challengeId": "c1", "name": "Challenge", "goals": [` + strings.Join(goals, ",") + `]}]}`)
+}
+
+func TestValidateAgainstSchema_ValidFixtures(t *testing.T) {
+	data, err := os.ReadFile("testdata/valid.json")
+	if err != nil {
+		t.Fatalf("Failed to read fixture: %v", err)
+	}
+	if err := ValidateAgainstSchema(data); err != nil {
+		t.Errorf("valid.json: %v", err)
+	}
+	if err := ValidateAgainstSchema(configWithGoals(validGoal)); err != nil {
+		t.Errorf("minimal config: %v", err)
+	}
+}
+
+func TestValidateAgainstSchema_Violations(t *testing.T) {
+	tests := []struct {
+		name string
+		raw  []byte
+		want []string
+	}{
+		{
+			name: "reward quantity below minimum",
+			raw: configWithGoals(validGoal, validGoal, `{
+				"goalId": "g3", "name": "Goal", "eventSource": "statistic",
+				"requirement": {"statCode": "kills", "operator": ">=", "targetValue": 10},
+				"reward": {"type": "ITEM", "rewardId": "sword", "quantity": 0}
+			}`),
+			want: []string{"/challenges/0/goals/2/reward/quantity: must be >= 1"},
+		},
+		{
+			name: "enum violations",
+			raw: configWithGoals(`{
+				"goalId": "g1", "name": "Goal", "type": "weekly", "eventSource": "statistic",
+				"requirement": {"statCode": "kills", "operator": ">", "targetValue": 10},
+				"rewards": [{"type": "ITEM", "rewardId": "sword", "quantity": 1}, {"type": "GEMS", "rewardId": "gem", "quantity": 1}]
+			}`),
+			want: []string{
+				`/challenges/0/goals/0/requirement/operator: must be one of ">="`,
+				`/challenges/0/goals/0/rewards/1/type: must be one of "ITEM", "WALLET"`,
+				`/challenges/0/goals/0/type: must be one of "absolute", "increment", "daily"`,
+			},
+		},
+		{
+			name: "unknown event source",
+			raw:  configWithGoals(strings.Replace(validGoal, `"statistic"`, `"purchase"`, 1)),
+			want: []string{`/challenges/0/goals/0/eventSource: must be one of "statistic", "login", "match", "iap"`},
+		},
+		{
+			name: "missing and unknown properties",
+			raw:  []byte(`{"challenges": [{"challengeId": "c1", "goals": [], "tags": []}], "version": 2}`),
+			want: []string{
+				"/challenges/0/name: is required",
+				"/challenges/0/goals: must contain at least 1 item(s)",
+				"/challenges/0/tags: unknown property",
+				"/version: unknown property",
+			},
+		},
+		{
+			name: "wrong types",
+			raw: configWithGoals(`{
+				"goalId": "g1", "name": "", "eventSource": "statistic", "daily": "yes", "cooldown": "soon",
+				"requirement": {"statCode": "kills", "operator": ">=", "targetValue": 2.5},
+				"reward": {"type": "ITEM", "rewardId": "sword", "quantity": 1}
+			}`),
+			want: []string{
+				"/challenges/0/goals/0/cooldown: must match pattern " + durationPattern,
+				"/challenges/0/goals/0/daily: must be boolean",
+				"/challenges/0/goals/0/name: must not be empty",
+				"/challenges/0/goals/0/requirement/targetValue: must be integer",
+			},
+		},
+		{
+			name: "not an object",
+			raw:  []byte(`[]`),
+			want: []string{"/: must be object"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateAgainstSchema(tt.raw)
+
+			var verr *ValidationError
+			if !stderrors.As(err, &verr) {
+				t.Fatalf("ValidateAgainstSchema() error = %v, want *ValidationError", err)
+			}
+			if len(verr.Violations) != len(tt.want) {
+				t.Fatalf("got %d violations (%v), want %d", len(verr.Violations), err, len(tt.want))
+			}
+			for i, want := range tt.want {
+				if got := verr.Violations[i].String(); got != want {
+					t.Errorf("violation %d = %q, want %q", i, got, want)
+				}
+			}
+		})
+	}
+}
+
+func TestValidateAgainstSchema_InvalidJSON(t *testing.T) {
+	err := ValidateAgainstSchema([]byte(`{"challenges": [`))
+
+	var verr *ValidationError
+	if err == nil || stderrors.As(err, &verr) {
+		t.Errorf("ValidateAgainstSchema() error = %v, want a parse error", err)
+	}
+}
+
+func TestEscapeToken(t *testing.T) {
+	if got := escapeToken("a/b~c"); got != "a~1b~0c" {
+		t.Errorf("escapeToken() = %q, want %q", got, "a~1b~0c")
+	}
+}
@@ -0,0 +1,198 @@
+package config
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"sort"
+)
+
+// Keys of the goal template syntax. They are consumed by expandGoalTemplates and never
+// reach the Config struct.
+const (
+	goalTemplatesKey = "goalTemplates" // Top-level object of named templates
+	templateKey      = "template"      // Goal field naming the template to start from
+	extendsKey       = "extends"       // Template field naming a base template
+)
+
+// templateImmutableKeys are goal fields a template may not set: they identify a single
+// goal, so sharing them across every goal built from the template is always a mistake.
+var templateImmutableKeys = []string{"goalId", "challengeId"}
+
+// expandGoalTemplates rewrites a config that uses goal templates into the plain format.
+//
+// A template is a partial goal under the top-level "goalTemplates" object, e.g. the type,
+// event source, operator and reward shared by every "win N matches on map X" goal. A goal
+// that sets "template" starts from the named template, and its own fields override the
+// template's: nested objects (requirement, reward) are merged key by key, while arrays and
+// scalars are replaced. A template may extend one other template with "extends"; the base
+// template may not extend a third one.
+//
+// Configs without templates are returned unchanged. Malformed JSON is also returned
+// unchanged so the caller reports the parse error.
+func expandGoalTemplates(data []byte) ([]byte, error) {
+	if !bytes.Contains(data, []byte(`"`+goalTemplatesKey+`"`)) && !bytes.Contains(data, []byte(`"`+templateKey+`"`)) {
+		return data, nil
+	}
+
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber() // Keep numbers exactly as written
+	var doc map[string]interface{}
+	if err := dec.Decode(&doc); err != nil {
+		return data, nil
+	}
+
+	templates, err := resolveGoalTemplates(doc[goalTemplatesKey])
+	if err != nil {
+		return nil, err
+	}
+	delete(doc, goalTemplatesKey)
+
+	challenges, _ := doc["challenges"].([]interface{})
+	for _, c := range challenges {
+		challenge, ok := c.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		goals, _ := challenge["goals"].([]interface{})
+		for i, g := range goals {
+			goal, ok := g.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			ref, ok := goal[templateKey]
+			if !ok {
+				continue
+			}
+
+			name, ok := ref.(string)
+			if !ok {
+				return nil, fmt.Errorf("goal '%v' in challenge '%v': template must be a string", goal["goalId"], challenge["challengeId"])
+			}
+			template, ok := templates[name]
+			if !ok {
+				return nil, fmt.Errorf("goal '%v' in challenge '%v': unknown template '%s'", goal["goalId"], challenge["challengeId"], name)
+			}
+
+			delete(goal, templateKey)
+			goals[i] = mergeJSONObjects(template, goal)
+		}
+	}
+
+	return json.Marshal(doc)
+}
+
+// resolveGoalTemplates checks the "goalTemplates" section and returns every template with
+// its base template (if any) merged in.
+func resolveGoalTemplates(section interface{}) (map[string]map[string]interface{}, error) {
+	if section == nil {
+		return map[string]map[string]interface{}{}, nil
+	}
+	raw, ok := section.(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("%s must be an object of named templates", goalTemplatesKey)
+	}
+
+	// Check templates in name order so the reported error does not depend on map order
+	names := make([]string, 0, len(raw))
+	templates := make(map[string]map[string]interface{}, len(raw))
+	for name, t := range raw {
+		template, ok := t.(map[string]interface{})
+		if !ok {
+			return nil, fmt.Errorf("goal template '%s' must be an object", name)
+		}
+		templates[name] = template
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		template := templates[name]
+		for _, key := range templateImmutableKeys {
+			if _, ok := template[key]; ok {
+				return nil, fmt.Errorf("goal template '%s' cannot set %s (each goal must set its own)", name, key)
+			}
+		}
+		if _, ok := template[templateKey]; ok {
+			return nil, fmt.Errorf("goal template '%s' cannot use %q; use %q to build on another template", name, templateKey, extendsKey)
+		}
+	}
+
+	resolved := make(map[string]map[string]interface{}, len(templates))
+	for _, name := range names {
+		template := templates[name]
+		base, err := baseTemplate(name, template, templates)
+		if err != nil {
+			return nil, err
+		}
+
+		own := withoutKey(template, extendsKey)
+		if base == nil {
+			resolved[name] = own
+			continue
+		}
+		resolved[name] = mergeJSONObjects(withoutKey(base, extendsKey), own)
+	}
+
+	return resolved, nil
+}
+
+// baseTemplate returns the template that template extends, or nil if it extends none.
+func baseTemplate(name string, template map[string]interface{}, templates map[string]map[string]interface{}) (map[string]interface{}, error) {
+	ref, ok := template[extendsKey]
+	if !ok {
+		return nil, nil
+	}
+
+	baseName, ok := ref.(string)
+	if !ok {
+		return nil, fmt.Errorf("goal template '%s': %s must be a string", name, extendsKey)
+	}
+	if baseName == name {
+		return nil, fmt.Errorf("goal template '%s' extends itself", name)
+	}
+	base, ok := templates[baseName]
+	if !ok {
+		return nil, fmt.Errorf("goal template '%s' extends unknown template '%s'", name, baseName)
+	}
+
+	if next, ok := base[extendsKey]; ok {
+		if next == name {
+			return nil, fmt.Errorf("goal templates '%s' and '%s' extend each other", name, baseName)
+		}
+		return nil, fmt.Errorf("goal template '%s' extends '%s', which extends '%v' (only one level of inheritance is allowed)", name, baseName, next)
+	}
+
+	return base, nil
+}
+
+// mergeJSONObjects returns base overlaid with override. Keys present in both are merged
+// recursively when both values are objects; otherwise the override value wins. Neither
+// input is modified.
+func mergeJSONObjects(base, override map[string]interface{}) map[string]interface{} {
+	merged := make(map[string]interface{}, len(base)+len(override))
+	for k, v := range base {
+		merged[k] = v
+	}
+	for k, v := range override {
+		baseObj, baseIsObj := merged[k].(map[string]interface{})
+		overrideObj, overrideIsObj := v.(map[string]interface{})
+		if baseIsObj && overrideIsObj {
+			merged[k] = mergeJSONObjects(baseObj, overrideObj)
+			continue
+		}
+		merged[k] = v
+	}
+	return merged
+}
+
+// withoutKey returns a shallow copy of obj without key.
+func withoutKey(obj map[string]interface{}, key string) map[string]interface{} {
+	copied := make(map[string]interface{}, len(obj))
+	for k, v := range obj {
+		if k != key {
+			copied[k] = v
+		}
+	}
+	return copied
+}
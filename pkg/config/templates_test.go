@@ -0,0 +1,199 @@
+package config
+
+import (
+	"log/slog"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/AccelByte/extend-challenge-common/pkg/domain"
+)
+
+// templatedConfig wraps goal templates and goals into a one-challenge config.
+func templatedConfig(templates, goals string) string {
+	return `{
+		"goalTemplates": {` + templates + `},
+		"challenges": [{
+			"challengeId": "ranked",
+			"name": "Ranked",
+			"goals": [` + goals + `]
+		}]
+	}`
+}
+
+const winOnMapTemplates = `
+	"match-win": {
+		"type": "increment",
+		"eventSource": "statistic",
+		"requirement": {"statCode": "match_wins", "operator": ">=", "targetValue": 5},
+		"reward": {"type": "ITEM", "rewardId": "map-badge", "quantity": 1},
+		"tags": ["pvp"]
+	},
+	"win-on-map": {
+		"extends": "match-win",
+		"difficulty": "normal",
+		"reward": {"quantity": 2}
+	}`
+
+func TestConfigLoader_GoalTemplates(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(os.Stdout, nil))
+
+	configPath := createTempConfigFile(t, templatedConfig(winOnMapTemplates, `
+		{"goalId": "win-dust", "name": "Win on Dust", "template": "win-on-map",
+		 "requirement": {"statCode": "match_wins_dust", "targetValue": 10}},
+		{"goalId": "win-harbor", "name": "Win on Harbor", "template": "win-on-map",
+		 "reward": {"rewardId": "harbor-badge"}, "tags": ["pvp", "harbor"], "difficulty": "hard"},
+		{"goalId": "first-win", "name": "First Win", "template": "match-win",
+		 "requirement": {"targetValue": 1}},
+		{"goalId": "plain", "name": "Plain", "eventSource": "statistic",
+		 "requirement": {"statCode": "kills", "operator": ">=", "targetValue": 3},
+		 "reward": {"type": "WALLET", "rewardId": "GOLD", "quantity": 50}}
+	`))
+
+	// Strict schema checks the expanded goals, which no longer carry template keys
+	cfg, err := NewConfigLoader(configPath, logger, WithStrictDuplicateKeys(true), WithStrictSchema(true)).LoadConfig()
+	if err != nil {
+		t.Fatalf("LoadConfig() unexpected error = %v", err)
+	}
+	goals := cfg.Challenges[0].Goals
+	if len(goals) != 4 {
+		t.Fatalf("Expected 4 goals, got %d", len(goals))
+	}
+
+	t.Run("goal fields override the template", func(t *testing.T) {
+		dust := goals[0]
+		if dust.Requirement != (domain.Requirement{StatCode: "match_wins_dust", Operator: ">=", TargetValue: 10}) {
+			t.Errorf("win-dust requirement = %+v", dust.Requirement)
+		}
+		if dust.Type != domain.GoalTypeIncrement || dust.EventSource != domain.EventSourceStatistic {
+			t.Errorf("win-dust type/eventSource = %s/%s, want the template's", dust.Type, dust.EventSource)
+		}
+		if dust.ChallengeID != "ranked" {
+			t.Errorf("win-dust challengeId = %q, want ranked", dust.ChallengeID)
+		}
+	})
+
+	t.Run("extending template overrides its base", func(t *testing.T) {
+		dust := goals[0]
+		if dust.Reward != (domain.Reward{Type: "ITEM", RewardID: "map-badge", Quantity: 2}) {
+			t.Errorf("win-dust reward = %+v, want the base reward with quantity 2", dust.Reward)
+		}
+		if dust.Difficulty != domain.DifficultyNormal {
+			t.Errorf("win-dust difficulty = %q, want normal", dust.Difficulty)
+		}
+	})
+
+	t.Run("nested objects merge, arrays and scalars replace", func(t *testing.T) {
+		harbor := goals[1]
+		if harbor.Reward != (domain.Reward{Type: "ITEM", RewardID: "harbor-badge", Quantity: 2}) {
+			t.Errorf("win-harbor reward = %+v", harbor.Reward)
+		}
+		if strings.Join(harbor.Tags, ",") != "pvp,harbor" {
+			t.Errorf("win-harbor tags = %v, want [pvp harbor]", harbor.Tags)
+		}
+		if harbor.Difficulty != domain.DifficultyHard {
+			t.Errorf("win-harbor difficulty = %q, want hard", harbor.Difficulty)
+		}
+	})
+
+	t.Run("base template used directly", func(t *testing.T) {
+		first := goals[2]
+		if first.Requirement.TargetValue != 1 || first.Requirement.StatCode != "match_wins" {
+			t.Errorf("first-win requirement = %+v", first.Requirement)
+		}
+		if first.Reward.Quantity != 1 || first.Difficulty != "" {
+			t.Errorf("first-win = reward %+v, difficulty %q; want the base template's", first.Reward, first.Difficulty)
+		}
+	})
+
+	t.Run("goals without a template are untouched", func(t *testing.T) {
+		if plain := goals[3]; plain.Reward.Type != "WALLET" || plain.Type != domain.GoalTypeAbsolute {
+			t.Errorf("plain = %+v", plain)
+		}
+	})
+}
+
+func TestConfigLoader_GoalTemplateErrors(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(os.Stdout, nil))
+	goal := func(template string) string {
+		return `{"goalId": "g1", "name": "Goal", "template": "` + template + `"}`
+	}
+
+	tests := []struct {
+		name      string
+		templates string
+		goals     string
+		wantErr   string
+	}{
+		{
+			name:      "unknown template",
+			templates: winOnMapTemplates,
+			goals:     goal("win-on-moon"),
+			wantErr:   "goal 'g1' in challenge 'ranked': unknown template 'win-on-moon'",
+		},
+		{
+			name:    "empty templates section",
+			goals:   goal("match-win"),
+			wantErr: "unknown template 'match-win'",
+		},
+		{
+			name:      "template sets goal ID",
+			templates: `"shared": {"goalId": "shared-goal", "eventSource": "statistic"}`,
+			goals:     goal("shared"),
+			wantErr:   "goal template 'shared' cannot set goalId",
+		},
+		{
+			name:      "template extends itself",
+			templates: `"loop": {"extends": "loop"}`,
+			goals:     goal("loop"),
+			wantErr:   "goal template 'loop' extends itself",
+		},
+		{
+			name:      "templates extend each other",
+			templates: `"a": {"extends": "b"}, "b": {"extends": "a"}`,
+			goals:     goal("a"),
+			wantErr:   "goal templates 'a' and 'b' extend each other",
+		},
+		{
+			name:      "two levels of inheritance",
+			templates: `"a": {"eventSource": "statistic"}, "b": {"extends": "a"}, "c": {"extends": "b"}`,
+			goals:     goal("c"),
+			wantErr:   "goal template 'c' extends 'b', which extends 'a' (only one level of inheritance is allowed)",
+		},
+		{
+			name:      "extends unknown template",
+			templates: `"a": {"extends": "missing"}`,
+			goals:     goal("a"),
+			wantErr:   "goal template 'a' extends unknown template 'missing'",
+		},
+		{
+			name:      "expanded goal still validated",
+			templates: `"no-reward": {"eventSource": "statistic", "requirement": {"statCode": "kills", "operator": ">=", "targetValue": 1}}`,
+			goals:     goal("no-reward"),
+			wantErr:   "config validation failed",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			configPath := createTempConfigFile(t, templatedConfig(tt.templates, tt.goals))
+
+			_, err := NewConfigLoader(configPath, logger).LoadConfig()
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("LoadConfig() error = %v, want error containing %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestExpandGoalTemplates_NoTemplates(t *testing.T) {
+	data := []byte(`{"challenges": [{"challengeId": "c1", "goals": [{"goalId": "g1"}]}]}`)
+
+	got, err := expandGoalTemplates(data)
+	if err != nil {
+		t.Fatalf("expandGoalTemplates() unexpected error = %v", err)
+	}
+	if string(got) != string(data) {
+		t.Errorf("expandGoalTemplates() rewrote a config without templates: %s", got)
+	}
+}
@@ -3,31 +3,131 @@ package config
 import (
 	"errors"
 	"fmt"
+	"strings"
+	"unicode/utf8"
 
 	"github.com/AccelByte/extend-challenge-common/pkg/domain"
 )
 
+// Default limits on default-assigned goals. Every enabled DefaultAssigned goal is inserted
+// for each new player on first login, so large counts make initialization slow.
+const (
+	// DefaultMaxDefaultAssignedPerChallenge is the per-challenge cap used when
+	// ValidatorOptions.MaxDefaultAssignedPerChallenge is zero.
+	DefaultMaxDefaultAssignedPerChallenge = 20
+
+	// DefaultMaxDefaultAssignedTotal is the soft limit used when
+	// ValidatorOptions.MaxDefaultAssignedTotal is zero.
+	DefaultMaxDefaultAssignedTotal = 100
+)
+
+// ValidatorOptions configures optional validation rules.
+type ValidatorOptions struct {
+	// RequireLocalizationKeys rejects challenges and goals without NameKey and DescriptionKey.
+	RequireLocalizationKeys bool
+
+	// MaxNameLength rejects challenge and goal names longer than this many characters.
+	// 0 = no limit.
+	MaxNameLength int
+
+	// RequireDescription rejects challenges and goals with an empty description.
+	RequireDescription bool
+
+	// MaxDefaultAssignedPerChallenge rejects challenges with more enabled default_assigned
+	// goals than this. 0 = DefaultMaxDefaultAssignedPerChallenge, negative = no limit.
+	MaxDefaultAssignedPerChallenge int
+
+	// MaxDefaultAssignedTotal is a soft limit on enabled default_assigned goals across all
+	// challenges; exceeding it adds a warning, not an error.
+	// 0 = DefaultMaxDefaultAssignedTotal, negative = no limit.
+	MaxDefaultAssignedTotal int
+
+	// WarnOnGatedDefaultAssigned reports a default_assigned goal with prerequisites as a
+	// warning instead of rejecting the config.
+	WarnOnGatedDefaultAssigned bool
+}
+
+// defaultAssignedLimit resolves a default-assigned limit option: 0 selects the default
+// and a negative value disables the limit (returned as 0).
+func defaultAssignedLimit(value, defaultValue int) int {
+	if value == 0 {
+		return defaultValue
+	}
+	if value < 0 {
+		return 0
+	}
+	return value
+}
+
 // Validator validates challenge configuration files.
 // It ensures all business rules are met before the application starts.
-type Validator struct{}
+type Validator struct {
+	opts ValidatorOptions
+}
 
-// NewValidator creates a new Validator instance.
+// NewValidator creates a new Validator instance with default options.
 func NewValidator() *Validator {
 	return &Validator{}
 }
 
+// NewValidatorWithOptions creates a new Validator instance with the given options.
+func NewValidatorWithOptions(opts ValidatorOptions) *Validator {
+	return &Validator{opts: opts}
+}
+
 // Validate performs comprehensive validation of the configuration.
 // It checks for:
 // - At least one challenge exists
 // - All challenge IDs are unique
 // - All goal IDs are globally unique
+// - Explicit goal challenge IDs match their enclosing challenge
 // - All prerequisites reference valid goals
 // - All requirements and rewards are valid
+// - No challenge has more default-assigned goals than the configured cap
+// - Default-assigned goals have no prerequisites
 //
 // Returns an error describing the first validation failure encountered.
 func (v *Validator) Validate(config *Config) error {
+	_, err := v.ValidateWithWarnings(config)
+	return err
+}
+
+// ValidateWithWarnings runs the same checks as Validate and also returns the non-fatal
+// diagnostics found along the way (e.g., a localization key that looks like display text).
+// Warnings are collected per call, so a Validator can be shared between goroutines.
+func (v *Validator) ValidateWithWarnings(config *Config) (warnings []string, err error) {
+	warnings = v.validate(config, func(e error) bool {
+		err = e
+		return false
+	})
+	return warnings, err
+}
+
+// ValidateAll runs the same checks as Validate without stopping at the first failure and
+// returns every error found, in config order, or nil if the config is valid. A challenge
+// or goal contributes at most one error of its own (the first rule it breaks, as reported
+// by Validate); duplicate IDs, challenge ID mismatches, default-assigned caps and unknown
+// prerequisites are reported for every occurrence. Intended for config CI, where listing
+// all problems in one run beats a fix-one-rerun loop.
+func (v *Validator) ValidateAll(config *Config) []error {
+	var errs []error
+	_ = v.validate(config, func(err error) bool {
+		errs = append(errs, err)
+		return true
+	})
+	return errs
+}
+
+// validate walks the configuration and passes each failure to report, stopping as soon as
+// report returns false. It returns the warnings collected up to that point.
+func (v *Validator) validate(config *Config, report func(err error) bool) (warnings []string) {
+	warn := func(msg string) {
+		warnings = append(warnings, msg)
+	}
+
 	if len(config.Challenges) == 0 {
-		return errors.New("config must have at least one challenge")
+		report(errors.New("config must have at least one challenge"))
+		return
 	}
 
 	// Track unique IDs
@@ -35,69 +135,164 @@ func (v *Validator) Validate(config *Config) error {
 	goalIDs := make(map[string]bool)
 	allGoals := make(map[string]*domain.Goal)
 
+	maxPerChallenge := defaultAssignedLimit(v.opts.MaxDefaultAssignedPerChallenge, DefaultMaxDefaultAssignedPerChallenge)
+	maxTotal := defaultAssignedLimit(v.opts.MaxDefaultAssignedTotal, DefaultMaxDefaultAssignedTotal)
+	totalDefaultAssigned := 0
+
 	// First pass: collect all IDs and goals
 	for _, challenge := range config.Challenges {
 		// Validate challenge
-		if err := v.validateChallenge(challenge); err != nil {
-			return fmt.Errorf("invalid challenge '%s': %w", challenge.ID, err)
+		if err := v.validateChallenge(challenge, warn); err != nil {
+			if !report(fmt.Errorf("invalid challenge '%s': %w", challenge.ID, err)) {
+				return
+			}
 		}
 
-		// Check duplicate challenge ID
-		if challengeIDs[challenge.ID] {
-			return fmt.Errorf("duplicate challenge ID: %s", challenge.ID)
+		// Check duplicate challenge ID (an empty ID was reported above)
+		if challenge.ID != "" && challengeIDs[challenge.ID] {
+			if !report(fmt.Errorf("duplicate challenge ID: %s", challenge.ID)) {
+				return
+			}
 		}
 		challengeIDs[challenge.ID] = true
 
 		// Validate goals
 		for _, goal := range challenge.Goals {
-			if err := v.validateGoal(goal); err != nil {
-				return fmt.Errorf("invalid goal '%s' in challenge '%s': %w", goal.ID, challenge.ID, err)
+			if err := v.validateGoal(goal, warn); err != nil {
+				if !report(fmt.Errorf("invalid goal '%s' in challenge '%s': %w", goal.ID, challenge.ID, err)) {
+					return
+				}
 			}
 
-			// Check duplicate goal ID
-			if goalIDs[goal.ID] {
-				return fmt.Errorf("duplicate goal ID: %s", goal.ID)
+			// Explicit ChallengeID must match the enclosing challenge
+			if goal.ChallengeID != "" && goal.ChallengeID != challenge.ID {
+				if !report(fmt.Errorf("goal '%s' declares challengeId '%s' but is defined in challenge '%s'", goal.ID, goal.ChallengeID, challenge.ID)) {
+					return
+				}
+			}
+
+			// Check duplicate goal ID (an empty ID was reported above)
+			if goal.ID != "" && goalIDs[goal.ID] {
+				if !report(fmt.Errorf("duplicate goal ID: %s", goal.ID)) {
+					return
+				}
 			}
 			goalIDs[goal.ID] = true
 
 			allGoals[goal.ID] = goal
 		}
+
+		// Default-assigned goals are materialized for every new player on first login
+		defaultAssigned := countDefaultAssigned(challenge)
+		if maxPerChallenge > 0 && defaultAssigned > maxPerChallenge {
+			if !report(fmt.Errorf("challenge '%s' has %d default-assigned goals (max %d)", challenge.ID, defaultAssigned, maxPerChallenge)) {
+				return
+			}
+		}
+		totalDefaultAssigned += defaultAssigned
+	}
+
+	if maxTotal > 0 && totalDefaultAssigned > maxTotal {
+		warn(fmt.Sprintf("%d default-assigned goals across all challenges exceeds the soft limit of %d; first-login initialization may be slow", totalDefaultAssigned, maxTotal))
 	}
 
-	// Second pass: validate prerequisites
-	for _, goal := range allGoals {
-		for _, prereqID := range goal.Prerequisites {
-			if _, exists := allGoals[prereqID]; !exists {
-				return fmt.Errorf("goal '%s' has invalid prerequisite: '%s' does not exist", goal.ID, prereqID)
+	// Second pass: validate prerequisites, in config order
+	for _, challenge := range config.Challenges {
+		for _, goal := range challenge.Goals {
+			for _, prereqID := range goal.Prerequisites {
+				if _, exists := allGoals[prereqID]; !exists {
+					if !report(fmt.Errorf("goal '%s' has invalid prerequisite: '%s' does not exist", goal.ID, prereqID)) {
+						return
+					}
+				}
 			}
 		}
 	}
+	return
+}
 
-	return nil
+// countDefaultAssigned returns the number of enabled default_assigned goals in a challenge,
+// matching what GetGoalsWithDefaultAssigned returns for it.
+func countDefaultAssigned(challenge *domain.Challenge) int {
+	count := 0
+	for _, goal := range challenge.Goals {
+		if goal.DefaultAssigned && goal.IsEnabled() {
+			count++
+		}
+	}
+	return count
 }
 
 // validateChallenge validates a single challenge.
-func (v *Validator) validateChallenge(challenge *domain.Challenge) error {
+func (v *Validator) validateChallenge(challenge *domain.Challenge, warn func(string)) error {
 	if challenge.ID == "" {
 		return errors.New("challenge ID cannot be empty")
 	}
 	if challenge.Name == "" {
 		return errors.New("challenge name cannot be empty")
 	}
+	if err := v.validateDisplayText("challenge", challenge.Name, challenge.Description); err != nil {
+		return err
+	}
 	if len(challenge.Goals) == 0 {
 		return errors.New("challenge must have at least one goal")
 	}
+	return v.validateLocalizationKeys("challenge '"+challenge.ID+"'", challenge.NameKey, challenge.DescriptionKey, warn)
+}
+
+// validateDisplayText applies the optional MaxNameLength and RequireDescription rules
+// to a challenge or goal. Length is counted in characters, not bytes.
+func (v *Validator) validateDisplayText(kind, name, description string) error {
+	if v.opts.MaxNameLength > 0 {
+		if n := utf8.RuneCountInString(name); n > v.opts.MaxNameLength {
+			return fmt.Errorf("%s name exceeds %d characters (got %d)", kind, v.opts.MaxNameLength, n)
+		}
+	}
+	if v.opts.RequireDescription && strings.TrimSpace(description) == "" {
+		return fmt.Errorf("%s description cannot be empty when descriptions are required", kind)
+	}
+	return nil
+}
+
+// validateLocalizationKeys checks the optional display-string keys of a challenge or goal.
+// Missing keys are an error only when RequireLocalizationKeys is set, but a key that is set
+// must not be blank. A key containing spaces is reported as a warning: it usually means
+// the English text was pasted into it.
+func (v *Validator) validateLocalizationKeys(owner, nameKey, descriptionKey string, warn func(string)) error {
+	if v.opts.RequireLocalizationKeys {
+		if nameKey == "" {
+			return errors.New("nameKey cannot be empty when localization keys are required")
+		}
+		if descriptionKey == "" {
+			return errors.New("descriptionKey cannot be empty when localization keys are required")
+		}
+	}
+
+	for _, key := range []struct{ field, value string }{
+		{"nameKey", nameKey},
+		{"descriptionKey", descriptionKey},
+	} {
+		if key.value != "" && strings.TrimSpace(key.value) == "" {
+			return fmt.Errorf("%s cannot be blank when set", key.field)
+		}
+		if strings.Contains(key.value, " ") {
+			warn(fmt.Sprintf("%s: %s '%s' contains spaces and looks like display text, not a localization key", owner, key.field, key.value))
+		}
+	}
 	return nil
 }
 
 // validateGoal validates a single goal.
-func (v *Validator) validateGoal(goal *domain.Goal) error {
+func (v *Validator) validateGoal(goal *domain.Goal, warn func(string)) error {
 	if goal.ID == "" {
 		return errors.New("goal ID cannot be empty")
 	}
 	if goal.Name == "" {
 		return errors.New("goal name cannot be empty")
 	}
+	if err := v.validateDisplayText("goal", goal.Name, goal.Description); err != nil {
+		return err
+	}
 
 	// Validate goal type
 	if goal.Type != "" && !goal.Type.IsValid() {
@@ -109,12 +304,80 @@ func (v *Validator) validateGoal(goal *domain.Goal) error {
 		return errors.New("event_source cannot be empty")
 	}
 	if !goal.EventSource.IsValid() {
-		return fmt.Errorf("invalid event_source '%s' (must be 'login' or 'statistic')", goal.EventSource)
+		return fmt.Errorf("invalid event_source '%s' (must be one of: %s)", goal.EventSource, joinEventSources(domain.EventSources()))
+	}
+
+	// Login events carry no stat value, so login goals must count occurrences.
+	// An empty type resolves to 'absolute' and is rejected here too.
+	if goal.EventSource == domain.EventSourceLogin && !goal.IsIncrementLike() {
+		return fmt.Errorf("event_source 'login' requires goal type 'increment' or 'daily' (current type: '%s')", goal.EffectiveType())
 	}
 
 	// Validate daily flag (only valid for increment type)
-	if goal.Daily && goal.Type != domain.GoalTypeIncrement {
-		return fmt.Errorf("daily flag can only be true for increment-type goals (current type: '%s')", goal.Type)
+	if goal.Daily && goal.EffectiveType() != domain.GoalTypeIncrement {
+		return fmt.Errorf("daily flag can only be true for increment-type goals (current type: '%s')", goal.EffectiveType())
+	}
+
+	// Validate cooldown (only valid for increment type, like the daily flag)
+	if goal.Cooldown < 0 {
+		return errors.New("cooldown cannot be negative")
+	}
+	if goal.Cooldown > 0 && goal.EffectiveType() != domain.GoalTypeIncrement {
+		return fmt.Errorf("cooldown can only be set for increment-type goals (current type: '%s')", goal.EffectiveType())
+	}
+	if goal.Cooldown > 0 && goal.Daily {
+		return errors.New("cooldown cannot be combined with the daily flag")
+	}
+
+	// Validate repetition: only counted goals can complete again, and every event counts
+	if goal.Repeatable && goal.EffectiveType() != domain.GoalTypeIncrement {
+		return fmt.Errorf("repeatable can only be set for increment-type goals (current type: '%s')", goal.EffectiveType())
+	}
+	if goal.Repeatable && goal.Daily {
+		return errors.New("repeatable cannot be combined with the daily flag")
+	}
+	if goal.MaxRepeats < 0 {
+		return errors.New("max_repeats cannot be negative")
+	}
+	if goal.MaxRepeats > 0 && !goal.Repeatable {
+		return errors.New("max_repeats requires repeatable")
+	}
+
+	// A goal handed out at onboarding cannot also be gated behind other goals
+	if goal.DefaultAssigned && len(goal.Prerequisites) > 0 {
+		msg := fmt.Sprintf("default_assigned goal cannot have prerequisites (has: %s)", strings.Join(goal.Prerequisites, ", "))
+		if !v.opts.WarnOnGatedDefaultAssigned {
+			return errors.New(msg)
+		}
+		warn(fmt.Sprintf("goal '%s': %s", goal.ID, msg))
+	}
+
+	if goal.ClaimDeadline < 0 {
+		return errors.New("claim_deadline cannot be negative")
+	}
+
+	// Validate rotation: week 0 is the first week, so only a later week implies a group
+	if goal.RotationWeek < 0 {
+		return errors.New("rotation_week cannot be negative")
+	}
+	if goal.RotationWeek > 0 && goal.RotationGroup == "" {
+		return errors.New("rotation_week requires a rotation_group")
+	}
+
+	if goal.Order < 0 {
+		return errors.New("order cannot be negative")
+	}
+
+	// Validate difficulty (empty resolves to normal)
+	if goal.Difficulty != "" && !domain.IsValidDifficulty(goal.Difficulty) {
+		return fmt.Errorf("invalid difficulty '%s' (must be 'easy', 'normal', or 'hard')", goal.Difficulty)
+	}
+
+	// Validate tags
+	for i, tag := range goal.Tags {
+		if strings.TrimSpace(tag) == "" {
+			return fmt.Errorf("tags[%d] cannot be empty", i)
+		}
 	}
 
 	// Validate requirement
@@ -128,16 +391,46 @@ func (v *Validator) validateGoal(goal *domain.Goal) error {
 		return errors.New("target_value must be positive")
 	}
 
-	// Validate reward
-	if goal.Reward.Type != "ITEM" && goal.Reward.Type != "WALLET" {
-		return fmt.Errorf("unsupported reward type '%s' (only 'ITEM' or 'WALLET' allowed)", goal.Reward.Type)
+	// Validate reward(s): a goal sets either the single reward or the rewards bundle
+	if goal.HasMixedRewards() {
+		return errors.New("set either reward or rewards, not both")
+	}
+	if len(goal.AllRewards()) == 0 {
+		return errors.New("at least one reward is required (set reward or rewards)")
+	}
+	if len(goal.Rewards) == 0 {
+		if err := validateReward(goal.Reward); err != nil {
+			return err
+		}
+	}
+	for i, reward := range goal.Rewards {
+		if err := validateReward(reward); err != nil {
+			return fmt.Errorf("rewards[%d]: %w", i, err)
+		}
+	}
+
+	return v.validateLocalizationKeys("goal '"+goal.ID+"'", goal.NameKey, goal.DescriptionKey, warn)
+}
+
+// validateReward validates a single reward entry.
+func validateReward(reward domain.Reward) error {
+	if reward.Type != "ITEM" && reward.Type != "WALLET" {
+		return fmt.Errorf("unsupported reward type '%s' (only 'ITEM' or 'WALLET' allowed)", reward.Type)
 	}
-	if goal.Reward.RewardID == "" {
+	if reward.RewardID == "" {
 		return errors.New("reward_id cannot be empty")
 	}
-	if goal.Reward.Quantity <= 0 {
+	if reward.Quantity <= 0 {
 		return errors.New("reward quantity must be positive")
 	}
-
 	return nil
 }
+
+// joinEventSources formats event sources as a quoted, comma-separated list for messages.
+func joinEventSources(sources []domain.EventSource) string {
+	quoted := make([]string, len(sources))
+	for i, s := range sources {
+		quoted[i] = "'" + string(s) + "'"
+	}
+	return strings.Join(quoted, ", ")
+}
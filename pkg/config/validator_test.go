@@ -1,8 +1,11 @@
 package config
 
 import (
+	"fmt"
 	"strings"
+	"sync"
 	"testing"
+	"time"
 
 	"github.com/AccelByte/extend-challenge-common/pkg/domain"
 )
@@ -1211,3 +1214,967 @@ func TestValidator_Validate(t *testing.T) {
 		})
 	}
 }
+
+// newValidTestGoal returns a goal that passes validation, for tests that change one field.
+func newValidTestGoal() *domain.Goal {
+	return &domain.Goal{
+		ID:          "goal-1",
+		Name:        "Goal 1",
+		Type:        domain.GoalTypeIncrement,
+		EventSource: domain.EventSourceStatistic,
+		Requirement: domain.Requirement{
+			StatCode:    "matches_won",
+			Operator:    ">=",
+			TargetValue: 3,
+		},
+		Reward: domain.Reward{
+			Type:     "ITEM",
+			RewardID: "item_1",
+			Quantity: 1,
+		},
+	}
+}
+
+// newTestConfigWithGoals wraps goals into a single-challenge config.
+func newTestConfigWithGoals(goals ...*domain.Goal) *Config {
+	return &Config{
+		Challenges: []*domain.Challenge{
+			{
+				ID:    "challenge-1",
+				Name:  "Challenge 1",
+				Goals: goals,
+			},
+		},
+	}
+}
+
+func TestValidator_Validate_Cooldown(t *testing.T) {
+	tests := []struct {
+		name    string
+		mutate  func(g *domain.Goal)
+		wantErr string
+	}{
+		{
+			name:   "increment with cooldown",
+			mutate: func(g *domain.Goal) { g.Cooldown = domain.Duration(time.Hour) },
+		},
+		{
+			name: "absolute with cooldown",
+			mutate: func(g *domain.Goal) {
+				g.Type = domain.GoalTypeAbsolute
+				g.Cooldown = domain.Duration(time.Hour)
+			},
+			wantErr: "cooldown can only be set for increment-type goals",
+		},
+		{
+			name: "daily type with cooldown",
+			mutate: func(g *domain.Goal) {
+				g.Type = domain.GoalTypeDaily
+				g.EventSource = domain.EventSourceLogin
+				g.Cooldown = domain.Duration(time.Hour)
+			},
+			wantErr: "cooldown can only be set for increment-type goals",
+		},
+		{
+			name:    "negative cooldown",
+			mutate:  func(g *domain.Goal) { g.Cooldown = domain.Duration(-time.Minute) },
+			wantErr: "cooldown cannot be negative",
+		},
+		{
+			name: "cooldown combined with daily flag",
+			mutate: func(g *domain.Goal) {
+				g.Daily = true
+				g.Cooldown = domain.Duration(time.Hour)
+			},
+			wantErr: "cooldown cannot be combined with the daily flag",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			goal := newValidTestGoal()
+			tt.mutate(goal)
+
+			err := NewValidator().Validate(newTestConfigWithGoals(goal))
+
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("Validate() unexpected error = %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("Validate() error = %v, want error containing %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestValidator_Validate_Repeatable(t *testing.T) {
+	tests := []struct {
+		name    string
+		mutate  func(g *domain.Goal)
+		wantErr string
+	}{
+		{
+			name:   "repeatable increment",
+			mutate: func(g *domain.Goal) { g.Repeatable = true },
+		},
+		{
+			name: "repeatable increment with max repeats",
+			mutate: func(g *domain.Goal) {
+				g.Repeatable = true
+				g.MaxRepeats = 5
+			},
+		},
+		{
+			name: "repeatable absolute",
+			mutate: func(g *domain.Goal) {
+				g.Type = domain.GoalTypeAbsolute
+				g.Repeatable = true
+			},
+			wantErr: "repeatable can only be set for increment-type goals (current type: 'absolute')",
+		},
+		{
+			name: "repeatable daily type",
+			mutate: func(g *domain.Goal) {
+				g.Type = domain.GoalTypeDaily
+				g.EventSource = domain.EventSourceLogin
+				g.Repeatable = true
+			},
+			wantErr: "repeatable can only be set for increment-type goals (current type: 'daily')",
+		},
+		{
+			name: "repeatable with daily flag",
+			mutate: func(g *domain.Goal) {
+				g.Daily = true
+				g.Repeatable = true
+			},
+			wantErr: "repeatable cannot be combined with the daily flag",
+		},
+		{
+			name: "negative max repeats",
+			mutate: func(g *domain.Goal) {
+				g.Repeatable = true
+				g.MaxRepeats = -1
+			},
+			wantErr: "max_repeats cannot be negative",
+		},
+		{
+			name:    "max repeats without repeatable",
+			mutate:  func(g *domain.Goal) { g.MaxRepeats = 3 },
+			wantErr: "max_repeats requires repeatable",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			goal := newValidTestGoal()
+			tt.mutate(goal)
+
+			err := NewValidator().Validate(newTestConfigWithGoals(goal))
+
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("Validate() unexpected error = %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("Validate() error = %v, want error containing %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestValidator_Validate_ClaimDeadline(t *testing.T) {
+	tests := []struct {
+		name     string
+		deadline domain.Duration
+		wantErr  string
+	}{
+		{name: "no deadline", deadline: 0},
+		{name: "seven days", deadline: domain.Duration(7 * 24 * time.Hour)},
+		{name: "negative deadline", deadline: domain.Duration(-time.Hour), wantErr: "claim_deadline cannot be negative"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			goal := newValidTestGoal()
+			goal.ClaimDeadline = tt.deadline
+
+			err := NewValidator().Validate(newTestConfigWithGoals(goal))
+
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("Validate() unexpected error = %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("Validate() error = %v, want error containing %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestValidator_Validate_Rotation(t *testing.T) {
+	tests := []struct {
+		name    string
+		group   string
+		week    int
+		wantErr string
+	}{
+		{name: "no rotation"},
+		{name: "first week of group", group: "weekly-pool"},
+		{name: "later week of group", group: "weekly-pool", week: 3},
+		{name: "negative week", group: "weekly-pool", week: -1, wantErr: "rotation_week cannot be negative"},
+		{name: "week without group", week: 2, wantErr: "rotation_week requires a rotation_group"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			goal := newValidTestGoal()
+			goal.RotationGroup = tt.group
+			goal.RotationWeek = tt.week
+
+			err := NewValidator().Validate(newTestConfigWithGoals(goal))
+
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("Validate() unexpected error = %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("Validate() error = %v, want error containing %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestValidator_Validate_Order(t *testing.T) {
+	tests := []struct {
+		name    string
+		order   int
+		wantErr string
+	}{
+		{name: "unset"},
+		{name: "positive", order: 10},
+		{name: "negative", order: -1, wantErr: "order cannot be negative"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			goal := newValidTestGoal()
+			goal.Order = tt.order
+
+			err := NewValidator().Validate(newTestConfigWithGoals(goal))
+
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("Validate() unexpected error = %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("Validate() error = %v, want error containing %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestValidator_Validate_Difficulty(t *testing.T) {
+	tests := []struct {
+		name       string
+		difficulty string
+		wantErr    string
+	}{
+		{name: "empty defaults to normal"},
+		{name: "easy", difficulty: "easy"},
+		{name: "normal", difficulty: "normal"},
+		{name: "hard", difficulty: "hard"},
+		{name: "unknown tier", difficulty: "extreme", wantErr: "invalid difficulty 'extreme'"},
+		{name: "uppercase", difficulty: "Easy", wantErr: "invalid difficulty 'Easy'"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			goal := newValidTestGoal()
+			goal.Difficulty = tt.difficulty
+
+			err := NewValidator().Validate(newTestConfigWithGoals(goal))
+
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("Validate() unexpected error = %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("Validate() error = %v, want error containing %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestValidator_Validate_Tags(t *testing.T) {
+	tests := []struct {
+		name    string
+		tags    []string
+		wantErr string
+	}{
+		{name: "no tags"},
+		{name: "tags", tags: []string{"pvp", "social"}},
+		{name: "empty tag", tags: []string{"pvp", ""}, wantErr: "tags[1] cannot be empty"},
+		{name: "blank tag", tags: []string{"  "}, wantErr: "tags[0] cannot be empty"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			goal := newValidTestGoal()
+			goal.Tags = tt.tags
+
+			err := NewValidator().Validate(newTestConfigWithGoals(goal))
+
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("Validate() unexpected error = %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("Validate() error = %v, want error containing %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestValidator_Validate_GoalChallengeID(t *testing.T) {
+	tests := []struct {
+		name        string
+		challengeID string
+		wantErr     string
+	}{
+		{name: "empty challengeId", challengeID: ""},
+		{name: "matching challengeId", challengeID: "challenge-1"},
+		{
+			name:        "mismatched challengeId",
+			challengeID: "challenge-2",
+			wantErr:     "declares challengeId 'challenge-2' but is defined in challenge 'challenge-1'",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			goal := newValidTestGoal()
+			goal.ChallengeID = tt.challengeID
+
+			err := NewValidator().Validate(newTestConfigWithGoals(goal))
+
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("Validate() unexpected error = %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("Validate() error = %v, want error containing %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestValidator_Validate_EventSource(t *testing.T) {
+	tests := []struct {
+		name    string
+		mutate  func(g *domain.Goal)
+		wantErr string
+	}{
+		{
+			name:   "statistic source with increment type",
+			mutate: func(g *domain.Goal) {},
+		},
+		{
+			name: "statistic source with absolute type",
+			mutate: func(g *domain.Goal) {
+				g.Type = domain.GoalTypeAbsolute
+			},
+		},
+		{
+			name: "login source with increment type",
+			mutate: func(g *domain.Goal) {
+				g.EventSource = domain.EventSourceLogin
+			},
+		},
+		{
+			name: "login source with daily type",
+			mutate: func(g *domain.Goal) {
+				g.EventSource = domain.EventSourceLogin
+				g.Type = domain.GoalTypeDaily
+			},
+		},
+		{
+			name: "unknown source (typo)",
+			mutate: func(g *domain.Goal) {
+				g.EventSource = "statistics"
+			},
+			wantErr: "invalid event_source 'statistics' (must be one of: 'statistic', 'login', 'match', 'iap'",
+		},
+		{
+			name: "match source with absolute type",
+			mutate: func(g *domain.Goal) {
+				g.EventSource = domain.EventSourceMatch
+				g.Type = domain.GoalTypeAbsolute
+			},
+		},
+		{
+			name: "iap source with increment type",
+			mutate: func(g *domain.Goal) {
+				g.EventSource = domain.EventSourceIAP
+			},
+		},
+		{
+			name: "registered custom source",
+			mutate: func(g *domain.Goal) {
+				g.EventSource = "validator-test-tournament"
+			},
+		},
+		{
+			name: "empty source",
+			mutate: func(g *domain.Goal) {
+				g.EventSource = ""
+			},
+			wantErr: "event_source cannot be empty",
+		},
+		{
+			name: "login source with absolute type",
+			mutate: func(g *domain.Goal) {
+				g.EventSource = domain.EventSourceLogin
+				g.Type = domain.GoalTypeAbsolute
+			},
+			wantErr: "event_source 'login' requires goal type 'increment' or 'daily'",
+		},
+		{
+			name: "login source with empty type",
+			mutate: func(g *domain.Goal) {
+				g.EventSource = domain.EventSourceLogin
+				g.Type = ""
+			},
+			wantErr: "event_source 'login' requires goal type 'increment' or 'daily'",
+		},
+	}
+
+	domain.RegisterEventSource("validator-test-tournament")
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			goal := newValidTestGoal()
+			tt.mutate(goal)
+
+			err := NewValidator().Validate(newTestConfigWithGoals(goal))
+
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("Validate() unexpected error = %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("Validate() error = %v, want error containing %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestValidator_Validate_LocalizationKeys(t *testing.T) {
+	withKeys := func(c *Config) {
+		c.Challenges[0].NameKey = "challenge.one.name"
+		c.Challenges[0].DescriptionKey = "challenge.one.description"
+		for _, g := range c.Challenges[0].Goals {
+			g.NameKey = "goal.one.name"
+			g.DescriptionKey = "goal.one.description"
+		}
+	}
+
+	tests := []struct {
+		name         string
+		opts         ValidatorOptions
+		mutate       func(c *Config)
+		wantErr      string
+		wantWarnings int
+	}{
+		{
+			name:   "keys optional by default",
+			mutate: func(c *Config) {},
+		},
+		{
+			name:   "required keys present",
+			opts:   ValidatorOptions{RequireLocalizationKeys: true},
+			mutate: withKeys,
+		},
+		{
+			name: "required challenge nameKey missing",
+			opts: ValidatorOptions{RequireLocalizationKeys: true},
+			mutate: func(c *Config) {
+				withKeys(c)
+				c.Challenges[0].NameKey = ""
+			},
+			wantErr: "invalid challenge 'challenge-1': nameKey cannot be empty",
+		},
+		{
+			name: "required goal descriptionKey missing",
+			opts: ValidatorOptions{RequireLocalizationKeys: true},
+			mutate: func(c *Config) {
+				withKeys(c)
+				c.Challenges[0].Goals[0].DescriptionKey = ""
+			},
+			wantErr: "invalid goal 'goal-1' in challenge 'challenge-1': descriptionKey cannot be empty",
+		},
+		{
+			name: "blank challenge nameKey",
+			mutate: func(c *Config) {
+				c.Challenges[0].NameKey = "   "
+			},
+			wantErr: "invalid challenge 'challenge-1': nameKey cannot be blank",
+		},
+		{
+			name: "blank goal descriptionKey",
+			mutate: func(c *Config) {
+				c.Challenges[0].Goals[0].DescriptionKey = "\t"
+			},
+			wantErr: "invalid goal 'goal-1' in challenge 'challenge-1': descriptionKey cannot be blank",
+		},
+		{
+			name: "prose in keys warns but passes",
+			mutate: func(c *Config) {
+				c.Challenges[0].NameKey = "Challenge One"
+				c.Challenges[0].Goals[0].DescriptionKey = "Win 3 matches"
+			},
+			wantWarnings: 2,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := newTestConfigWithGoals(newValidTestGoal())
+			tt.mutate(config)
+
+			v := NewValidatorWithOptions(tt.opts)
+			warnings, err := v.ValidateWithWarnings(config)
+
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("Validate() unexpected error = %v", err)
+				}
+			} else if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("Validate() error = %v, want error containing %q", err, tt.wantErr)
+			}
+
+			if got := len(warnings); got != tt.wantWarnings {
+				t.Errorf("ValidateWithWarnings() warnings = %v, want %d warnings", warnings, tt.wantWarnings)
+			}
+		})
+	}
+
+	t.Run("concurrent runs keep their own warnings", func(t *testing.T) {
+		prose := newTestConfigWithGoals(newValidTestGoal())
+		prose.Challenges[0].NameKey = "Challenge One"
+		clean := newTestConfigWithGoals(newValidTestGoal())
+		clean.Challenges[0].NameKey = "challenge.one.name"
+
+		v := NewValidator()
+		var wg sync.WaitGroup
+		for i := 0; i < 50; i++ {
+			config, want := clean, 0
+			if i%2 == 0 {
+				config, want = prose, 1
+			}
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				warnings, err := v.ValidateWithWarnings(config)
+				if err != nil {
+					t.Errorf("ValidateWithWarnings() unexpected error = %v", err)
+				}
+				if len(warnings) != want {
+					t.Errorf("ValidateWithWarnings() warnings = %v, want %d warnings", warnings, want)
+				}
+			}()
+		}
+		wg.Wait()
+	})
+}
+
+func TestValidator_Validate_DisplayText(t *testing.T) {
+	withDescriptions := func(c *Config) {
+		c.Challenges[0].Description = "First challenge"
+		for _, g := range c.Challenges[0].Goals {
+			g.Description = "Win 3 matches"
+		}
+	}
+
+	tests := []struct {
+		name    string
+		opts    ValidatorOptions
+		mutate  func(c *Config)
+		wantErr string
+	}{
+		{
+			name: "defaults allow long names and no description",
+			mutate: func(c *Config) {
+				c.Challenges[0].Name = strings.Repeat("x", 500)
+			},
+		},
+		{
+			name:   "names within limit",
+			opts:   ValidatorOptions{MaxNameLength: 11},
+			mutate: func(c *Config) {},
+		},
+		{
+			name: "over-length challenge name",
+			opts: ValidatorOptions{MaxNameLength: 10},
+			mutate: func(c *Config) {
+				c.Challenges[0].Name = "Challenge One"
+			},
+			wantErr: "invalid challenge 'challenge-1': challenge name exceeds 10 characters (got 13)",
+		},
+		{
+			name: "over-length goal name",
+			opts: ValidatorOptions{MaxNameLength: 12},
+			mutate: func(c *Config) {
+				c.Challenges[0].Goals[0].Name = "Win three matches"
+			},
+			wantErr: "invalid goal 'goal-1' in challenge 'challenge-1': goal name exceeds 12 characters (got 17)",
+		},
+		{
+			name: "length counts characters not bytes",
+			opts: ValidatorOptions{MaxNameLength: 11},
+			mutate: func(c *Config) {
+				c.Challenges[0].Name = "チャレンジ"
+			},
+		},
+		{
+			name:    "missing challenge description when required",
+			opts:    ValidatorOptions{RequireDescription: true},
+			mutate:  func(c *Config) {},
+			wantErr: "invalid challenge 'challenge-1': challenge description cannot be empty",
+		},
+		{
+			name: "blank goal description when required",
+			opts: ValidatorOptions{RequireDescription: true},
+			mutate: func(c *Config) {
+				withDescriptions(c)
+				c.Challenges[0].Goals[0].Description = "   "
+			},
+			wantErr: "invalid goal 'goal-1' in challenge 'challenge-1': goal description cannot be empty",
+		},
+		{
+			name:   "descriptions present when required",
+			opts:   ValidatorOptions{RequireDescription: true},
+			mutate: withDescriptions,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := newTestConfigWithGoals(newValidTestGoal())
+			tt.mutate(config)
+
+			err := NewValidatorWithOptions(tt.opts).Validate(config)
+
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("Validate() unexpected error = %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("Validate() error = %v, want error containing %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestValidator_Validate_DefaultAssignedLimits(t *testing.T) {
+	// newConfig builds challenges with the given number of default-assigned goals each
+	newConfig := func(perChallenge ...int) *Config {
+		config := &Config{}
+		for c, count := range perChallenge {
+			challenge := &domain.Challenge{ID: fmt.Sprintf("challenge-%d", c), Name: "Challenge"}
+			for i := 0; i < count; i++ {
+				goal := newValidTestGoal()
+				goal.ID = fmt.Sprintf("goal-%d-%d", c, i)
+				goal.DefaultAssigned = true
+				challenge.Goals = append(challenge.Goals, goal)
+			}
+			// One manual goal so every challenge has at least one goal
+			manual := newValidTestGoal()
+			manual.ID = fmt.Sprintf("goal-%d-manual", c)
+			challenge.Goals = append(challenge.Goals, manual)
+			config.Challenges = append(config.Challenges, challenge)
+		}
+		return config
+	}
+
+	tests := []struct {
+		name         string
+		opts         ValidatorOptions
+		config       *Config
+		wantErr      string
+		wantWarnings int
+	}{
+		{
+			name:   "default cap reached",
+			config: newConfig(DefaultMaxDefaultAssignedPerChallenge),
+		},
+		{
+			name:    "default cap exceeded",
+			config:  newConfig(DefaultMaxDefaultAssignedPerChallenge + 1),
+			wantErr: "challenge 'challenge-0' has 21 default-assigned goals (max 20)",
+		},
+		{
+			name:   "custom cap reached",
+			opts:   ValidatorOptions{MaxDefaultAssignedPerChallenge: 3},
+			config: newConfig(3),
+		},
+		{
+			name:    "custom cap exceeded",
+			opts:    ValidatorOptions{MaxDefaultAssignedPerChallenge: 3},
+			config:  newConfig(1, 4),
+			wantErr: "challenge 'challenge-1' has 4 default-assigned goals (max 3)",
+		},
+		{
+			name:   "negative cap disables the limit",
+			opts:   ValidatorOptions{MaxDefaultAssignedPerChallenge: -1},
+			config: newConfig(50),
+		},
+		{
+			name: "disabled goals are not counted",
+			opts: ValidatorOptions{MaxDefaultAssignedPerChallenge: 1},
+			config: func() *Config {
+				config := newConfig(2)
+				disabled := false
+				config.Challenges[0].Goals[0].Enabled = &disabled
+				return config
+			}(),
+		},
+		{
+			name:   "total at soft limit",
+			opts:   ValidatorOptions{MaxDefaultAssignedTotal: 4},
+			config: newConfig(2, 2),
+		},
+		{
+			name:         "total over soft limit warns",
+			opts:         ValidatorOptions{MaxDefaultAssignedTotal: 4},
+			config:       newConfig(2, 3),
+			wantWarnings: 1,
+		},
+		{
+			name:         "default soft limit",
+			config:       newConfig(20, 20, 20, 20, 20, 1),
+			wantWarnings: 1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			v := NewValidatorWithOptions(tt.opts)
+			warnings, err := v.ValidateWithWarnings(tt.config)
+
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("Validate() unexpected error = %v", err)
+				}
+			} else if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("Validate() error = %v, want error containing %q", err, tt.wantErr)
+			}
+
+			if got := len(warnings); got != tt.wantWarnings {
+				t.Errorf("ValidateWithWarnings() warnings = %v, want %d warnings", warnings, tt.wantWarnings)
+			}
+		})
+	}
+}
+
+func TestValidator_Validate_GatedDefaultAssigned(t *testing.T) {
+	newConfig := func(defaultAssigned bool, prerequisites ...string) *Config {
+		prereq := newValidTestGoal()
+		prereq.ID = "prereq-goal"
+		goal := newValidTestGoal()
+		goal.ID = "gated-goal"
+		goal.DefaultAssigned = defaultAssigned
+		goal.Prerequisites = prerequisites
+		return newTestConfigWithGoals(prereq, goal)
+	}
+
+	tests := []struct {
+		name         string
+		opts         ValidatorOptions
+		config       *Config
+		wantErr      string
+		wantWarnings int
+	}{
+		{
+			name:   "default assigned without prerequisites",
+			config: newConfig(true),
+		},
+		{
+			name:   "prerequisites without default assigned",
+			config: newConfig(false, "prereq-goal"),
+		},
+		{
+			name:    "default assigned with prerequisites",
+			config:  newConfig(true, "prereq-goal"),
+			wantErr: "invalid goal 'gated-goal' in challenge 'challenge-1': default_assigned goal cannot have prerequisites (has: prereq-goal)",
+		},
+		{
+			name:         "warn instead of error",
+			opts:         ValidatorOptions{WarnOnGatedDefaultAssigned: true},
+			config:       newConfig(true, "prereq-goal"),
+			wantWarnings: 1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			v := NewValidatorWithOptions(tt.opts)
+			warnings, err := v.ValidateWithWarnings(tt.config)
+
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("Validate() unexpected error = %v", err)
+				}
+			} else if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("Validate() error = %v, want error containing %q", err, tt.wantErr)
+			}
+
+			if got := len(warnings); got != tt.wantWarnings {
+				t.Errorf("ValidateWithWarnings() warnings = %v, want %d warnings", warnings, tt.wantWarnings)
+			}
+		})
+	}
+}
+
+func TestValidator_Validate_RewardBundle(t *testing.T) {
+	gold := domain.Reward{Type: "WALLET", RewardID: "GOLD", Quantity: 100}
+	gem := domain.Reward{Type: "ITEM", RewardID: "gem", Quantity: 2}
+
+	tests := []struct {
+		name    string
+		mutate  func(g *domain.Goal)
+		wantErr string
+	}{
+		{
+			name: "bundle without single reward",
+			mutate: func(g *domain.Goal) {
+				g.Rewards = []domain.Reward{g.Reward, gold}
+				g.Reward = domain.Reward{}
+			},
+		},
+		{
+			name: "bundle of three rewards",
+			mutate: func(g *domain.Goal) {
+				g.Rewards = []domain.Reward{g.Reward, gem, gold}
+				g.Reward = domain.Reward{}
+			},
+		},
+		{
+			name:   "normalized single reward",
+			mutate: func(g *domain.Goal) { g.NormalizeRewards() },
+		},
+		{
+			name: "invalid bundle entry reports index",
+			mutate: func(g *domain.Goal) {
+				g.Rewards = []domain.Reward{gold, {Type: "ITEM", RewardID: "sword", Quantity: 0}}
+				g.Reward = domain.Reward{}
+			},
+			wantErr: "rewards[1]: reward quantity must be positive",
+		},
+		{
+			name: "both set is rejected",
+			mutate: func(g *domain.Goal) {
+				g.Rewards = []domain.Reward{gold}
+			},
+			wantErr: "set either reward or rewards, not both",
+		},
+		{
+			name: "neither set",
+			mutate: func(g *domain.Goal) {
+				g.Reward = domain.Reward{}
+			},
+			wantErr: "at least one reward is required",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			goal := newValidTestGoal()
+			tt.mutate(goal)
+
+			err := NewValidator().Validate(newTestConfigWithGoals(goal))
+
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("Validate() unexpected error = %v", err)
+				}
+			} else if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("Validate() error = %v, want error containing %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestValidator_ValidateAll(t *testing.T) {
+	goal := func(id string, mutate func(g *domain.Goal)) *domain.Goal {
+		g := newValidTestGoal()
+		g.ID = id
+		mutate(g)
+		return g
+	}
+
+	config := newTestConfigWithGoals(
+		goal("", func(g *domain.Goal) {}),
+		goal("bad-operator", func(g *domain.Goal) { g.Requirement.Operator = ">" }),
+		goal("bad-type", func(g *domain.Goal) { g.Type = "weekly" }),
+		goal("missing-prereq", func(g *domain.Goal) { g.Prerequisites = []string{"ghost"} }),
+		goal("bad-operator", func(g *domain.Goal) { g.Requirement.Operator = ">" }),
+	)
+	config.Challenges = append(config.Challenges, &domain.Challenge{
+		ID:    "challenge-1",
+		Name:  "Challenge 1 again",
+		Goals: []*domain.Goal{goal("ok", func(g *domain.Goal) {})},
+	})
+
+	errs := NewValidator().ValidateAll(config)
+
+	want := []string{
+		"invalid goal '' in challenge 'challenge-1': goal ID cannot be empty",
+		"invalid goal 'bad-operator' in challenge 'challenge-1': unsupported operator '>'",
+		"invalid goal 'bad-type' in challenge 'challenge-1': invalid goal type 'weekly'",
+		"invalid goal 'bad-operator' in challenge 'challenge-1': unsupported operator '>'",
+		"duplicate goal ID: bad-operator",
+		"duplicate challenge ID: challenge-1",
+		"goal 'missing-prereq' has invalid prerequisite: 'ghost' does not exist",
+	}
+	if len(errs) != len(want) {
+		t.Fatalf("ValidateAll() returned %d errors, want %d: %v", len(errs), len(want), errs)
+	}
+	for i, err := range errs {
+		if !strings.Contains(err.Error(), want[i]) {
+			t.Errorf("errs[%d] = %v, want error containing %q", i, err, want[i])
+		}
+	}
+
+	// Validate stops at the first of them
+	if err := NewValidator().Validate(config); err == nil || err.Error() != errs[0].Error() {
+		t.Errorf("Validate() error = %v, want %v", err, errs[0])
+	}
+
+	if errs := NewValidator().ValidateAll(newTestConfigWithGoals(newValidTestGoal())); errs != nil {
+		t.Errorf("ValidateAll() of a valid config = %v, want nil", errs)
+	}
+	if errs := NewValidator().ValidateAll(&Config{}); len(errs) != 1 {
+		t.Errorf("ValidateAll() of an empty config = %v, want the single no-challenges error", errs)
+	}
+}
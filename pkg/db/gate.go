@@ -0,0 +1,136 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"sync"
+
+	"github.com/AccelByte/extend-challenge-common/pkg/errors"
+)
+
+// Gate tracks in-flight database work so a shutdown can wait for it before closing the pool.
+// database/sql has no way to stop handing out connections without closing the pool, so
+// callers register work explicitly: Acquire before starting, Release when done.
+type Gate struct {
+	mu       sync.Mutex
+	closing  bool
+	inFlight int
+	drained  chan struct{} // Closed when closing and inFlight reaches zero
+}
+
+// NewGate creates an open Gate.
+func NewGate() *Gate {
+	return &Gate{drained: make(chan struct{})}
+}
+
+// Acquire registers one unit of in-flight work.
+// Returns an errors.ErrCodeShuttingDown error once draining has started.
+func (g *Gate) Acquire() error {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	if g.closing {
+		return errors.ErrShuttingDown()
+	}
+	g.inFlight++
+	return nil
+}
+
+// Release marks one unit of work acquired with Acquire as finished.
+func (g *Gate) Release() {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	g.inFlight--
+	if g.closing && g.inFlight == 0 {
+		close(g.drained)
+	}
+}
+
+// Drain rejects new work and waits until all in-flight work is released or ctx is done.
+// Safe to call more than once.
+func (g *Gate) Drain(ctx context.Context) error {
+	g.mu.Lock()
+	if !g.closing {
+		g.closing = true
+		if g.inFlight == 0 {
+			close(g.drained)
+		}
+	}
+	g.mu.Unlock()
+
+	select {
+	case <-g.drained:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// GatedDB is a *sql.DB paired with the Gate its repositories use.
+// Pass Gate() to repository.WithGate so batch writes and transactions are drained
+// by Shutdown instead of failing with "sql: database is closed".
+type GatedDB struct {
+	*sql.DB
+	gate *Gate
+}
+
+// gates maps each wrapped *sql.DB to its Gate so Shutdown can be called with the plain pool.
+var gates sync.Map // map[*sql.DB]*Gate
+
+// NewGatedDB wraps an open pool with a new Gate.
+func NewGatedDB(db *sql.DB) *GatedDB {
+	gate := NewGate()
+	gates.Store(db, gate)
+	return &GatedDB{DB: db, gate: gate}
+}
+
+// ConnectGated is Connect followed by NewGatedDB.
+func ConnectGated(cfg *Config) (*GatedDB, error) {
+	db, err := Connect(cfg)
+	if err != nil {
+		return nil, err
+	}
+	return NewGatedDB(db), nil
+}
+
+// Gate returns the gate guarding this pool.
+func (g *GatedDB) Gate() *Gate {
+	return g.gate
+}
+
+// Shutdown drains in-flight work and closes the pool. See Shutdown.
+func (g *GatedDB) Shutdown(ctx context.Context) error {
+	gates.Delete(g.DB)
+	return shutdown(ctx, g.DB, g.gate)
+}
+
+// Shutdown closes db after in-flight work has finished.
+//
+// If db was wrapped with NewGatedDB, new work is rejected immediately and Shutdown waits
+// for work already in progress, up to the ctx deadline. The pool is closed either way;
+// when the deadline expires first the returned error wraps ctx.Err(). For pools without
+// a gate Shutdown simply closes the pool.
+func Shutdown(ctx context.Context, db *sql.DB) error {
+	var gate *Gate
+	if g, ok := gates.LoadAndDelete(db); ok {
+		gate = g.(*Gate)
+	}
+	return shutdown(ctx, db, gate)
+}
+
+// shutdown drains gate (if any) and closes db.
+func shutdown(ctx context.Context, db *sql.DB, gate *Gate) error {
+	var drainErr error
+	if gate != nil {
+		if err := gate.Drain(ctx); err != nil {
+			drainErr = fmt.Errorf("in-flight database work did not finish before shutdown deadline: %w", err)
+		}
+	}
+
+	if err := db.Close(); err != nil {
+		return fmt.Errorf("failed to close database: %w", err)
+	}
+	return drainErr
+}
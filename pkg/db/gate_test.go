@@ -0,0 +1,101 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	customerrors "github.com/AccelByte/extend-challenge-common/pkg/errors"
+)
+
+func requireShuttingDown(t *testing.T, err error) {
+	t.Helper()
+
+	var ce *customerrors.ChallengeError
+	require.True(t, errors.As(err, &ce), "expected ChallengeError, got %v", err)
+	assert.Equal(t, customerrors.ErrCodeShuttingDown, ce.Code)
+}
+
+func TestGate_DrainWaitsForInFlight(t *testing.T) {
+	gate := NewGate()
+	require.NoError(t, gate.Acquire())
+
+	done := make(chan error, 1)
+	go func() { done <- gate.Drain(context.Background()) }()
+
+	select {
+	case <-done:
+		t.Fatal("Drain returned while work was in flight")
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	// New work is rejected while draining
+	requireShuttingDown(t, gate.Acquire())
+
+	gate.Release()
+	assert.NoError(t, <-done)
+
+	// Draining again is a no-op
+	assert.NoError(t, gate.Drain(context.Background()))
+}
+
+func TestGate_DrainDeadline(t *testing.T) {
+	gate := NewGate()
+	require.NoError(t, gate.Acquire())
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+
+	assert.ErrorIs(t, gate.Drain(ctx), context.DeadlineExceeded)
+}
+
+func TestShutdown_ClosesAfterDrain(t *testing.T) {
+	// sql.Open does not connect, so no database is needed
+	sqlDB, err := sql.Open("postgres", "host=localhost sslmode=disable")
+	require.NoError(t, err)
+
+	gated := NewGatedDB(sqlDB)
+	require.NoError(t, gated.Gate().Acquire())
+
+	done := make(chan error, 1)
+	go func() { done <- Shutdown(context.Background(), sqlDB) }()
+
+	select {
+	case <-done:
+		t.Fatal("Shutdown returned while work was in flight")
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	gated.Gate().Release()
+	require.NoError(t, <-done)
+
+	// Pool is closed
+	assert.Error(t, sqlDB.Ping())
+}
+
+func TestShutdown_DeadlineStillCloses(t *testing.T) {
+	sqlDB, err := sql.Open("postgres", "host=localhost sslmode=disable")
+	require.NoError(t, err)
+
+	gated := NewGatedDB(sqlDB)
+	require.NoError(t, gated.Gate().Acquire())
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+
+	err = gated.Shutdown(ctx)
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.Error(t, sqlDB.Ping())
+}
+
+func TestShutdown_UngatedPool(t *testing.T) {
+	sqlDB, err := sql.Open("postgres", "host=localhost sslmode=disable")
+	require.NoError(t, err)
+
+	assert.NoError(t, Shutdown(context.Background(), sqlDB))
+}
@@ -4,6 +4,9 @@ import (
 	"context"
 	"database/sql"
 	"fmt"
+	"math/rand/v2"
+	"net"
+	"net/url"
 	"os"
 	"strconv"
 	"time"
@@ -19,10 +22,18 @@ type Config struct {
 	User            string
 	Password        string
 	SSLMode         string
+	SSLRootCert     string // CA certificate file; required for verify-ca and verify-full
+	SSLCert         string // Client certificate file; set together with SSLKey
+	SSLKey          string // Client private key file
 	MaxOpenConns    int
 	MaxIdleConns    int
 	ConnMaxLifetime time.Duration
 	ConnMaxIdleTime time.Duration
+
+	// ConnMaxLifetimeJitter spreads ConnMaxLifetime by up to ±jitter per process (see
+	// JitterLifetime), so pods started together do not recycle their connections in
+	// lockstep. 0 disables jitter.
+	ConnMaxLifetimeJitter time.Duration
 }
 
 // NewConfigFromEnv creates database config from environment variables
@@ -34,21 +45,90 @@ func NewConfigFromEnv() *Config {
 		User:            getEnv("DB_USER", "postgres"),
 		Password:        getEnv("DB_PASSWORD", ""),
 		SSLMode:         getEnv("DB_SSLMODE", "disable"),
+		SSLRootCert:     getEnv("DB_SSLROOTCERT", ""),
+		SSLCert:         getEnv("DB_SSLCERT", ""),
+		SSLKey:          getEnv("DB_SSLKEY", ""),
 		MaxOpenConns:    getEnvAsInt("DB_MAX_OPEN_CONNS", 25),
 		MaxIdleConns:    getEnvAsInt("DB_MAX_IDLE_CONNS", 5),
 		ConnMaxLifetime: time.Duration(getEnvAsInt("DB_CONN_MAX_LIFETIME", 300)) * time.Second,
 		ConnMaxIdleTime: time.Duration(getEnvAsInt("DB_CONN_MAX_IDLE_TIME", 300)) * time.Second,
+
+		ConnMaxLifetimeJitter: time.Duration(getEnvAsInt("DB_CONN_MAX_LIFETIME_JITTER", 0)) * time.Second,
 	}
 }
 
+// sslModes are the sslmode values PostgreSQL documents for libpq connection strings
+var sslModes = map[string]bool{
+	"disable":     true,
+	"allow":       true,
+	"prefer":      true,
+	"require":     true,
+	"verify-ca":   true,
+	"verify-full": true,
+}
+
+// DSN builds a postgres:// connection URL from the config.
+// User, password and database are URL-escaped, so secrets containing characters such
+// as '@', ':' or '/' are safe. sslmode and the certificate paths (sslrootcert, sslcert,
+// sslkey) are omitted when empty.
+func (c *Config) DSN() string {
+	u := url.URL{
+		Scheme:  "postgres",
+		Host:    net.JoinHostPort(c.Host, strconv.Itoa(c.Port)),
+		Path:    "/" + c.Database,
+		RawPath: "/" + url.PathEscape(c.Database),
+	}
+
+	if c.Password != "" {
+		u.User = url.UserPassword(c.User, c.Password)
+	} else if c.User != "" {
+		u.User = url.User(c.User)
+	}
+
+	query := url.Values{}
+	for key, value := range map[string]string{
+		"sslmode":     c.SSLMode,
+		"sslrootcert": c.SSLRootCert,
+		"sslcert":     c.SSLCert,
+		"sslkey":      c.SSLKey,
+	} {
+		if value != "" {
+			query.Set(key, value)
+		}
+	}
+	u.RawQuery = query.Encode() // Sorted by key
+
+	return u.String()
+}
+
+// Validate checks that the config can form a usable DSN: non-empty host and database,
+// a port in 1-65535 and a known SSL mode. verify-ca and verify-full require SSLRootCert,
+// and a client certificate needs both SSLCert and SSLKey.
+func (c *Config) Validate() error {
+	if c.Host == "" {
+		return fmt.Errorf("database host cannot be empty")
+	}
+	if c.Port < 1 || c.Port > 65535 {
+		return fmt.Errorf("database port must be between 1 and 65535 (got %d)", c.Port)
+	}
+	if c.Database == "" {
+		return fmt.Errorf("database name cannot be empty")
+	}
+	if !sslModes[c.SSLMode] {
+		return fmt.Errorf("unknown database SSL mode %q", c.SSLMode)
+	}
+	if (c.SSLMode == "verify-ca" || c.SSLMode == "verify-full") && c.SSLRootCert == "" {
+		return fmt.Errorf("database SSL mode %q requires a root certificate (DB_SSLROOTCERT)", c.SSLMode)
+	}
+	if (c.SSLCert == "") != (c.SSLKey == "") {
+		return fmt.Errorf("database client certificate and key must be set together")
+	}
+	return nil
+}
+
 // Connect establishes a database connection with the provided configuration
 func Connect(cfg *Config) (*sql.DB, error) {
-	dsn := fmt.Sprintf(
-		"host=%s port=%d user=%s password=%s dbname=%s sslmode=%s",
-		cfg.Host, cfg.Port, cfg.User, cfg.Password, cfg.Database, cfg.SSLMode,
-	)
-
-	db, err := sql.Open("postgres", dsn)
+	db, err := sql.Open("postgres", cfg.DSN())
 	if err != nil {
 		return nil, fmt.Errorf("failed to open database: %w", err)
 	}
@@ -56,7 +136,7 @@ func Connect(cfg *Config) (*sql.DB, error) {
 	// Configure connection pool
 	db.SetMaxOpenConns(cfg.MaxOpenConns)
 	db.SetMaxIdleConns(cfg.MaxIdleConns)
-	db.SetConnMaxLifetime(cfg.ConnMaxLifetime)
+	db.SetConnMaxLifetime(JitterLifetime(cfg.ConnMaxLifetime, cfg.ConnMaxLifetimeJitter))
 	db.SetConnMaxIdleTime(cfg.ConnMaxIdleTime)
 
 	// Verify connection
@@ -67,6 +147,28 @@ func Connect(cfg *Config) (*sql.DB, error) {
 	return db, nil
 }
 
+// JitterLifetime returns lifetime shifted by a random offset in [-jitter, +jitter].
+// Calling it once when the pool is configured gives each process its own connection
+// lifetime, which breaks up the synchronized reconnect storms (and latency spikes) that a
+// fleet sharing one lifetime sees every time its connections expire together.
+// Jitter is capped at half the lifetime so the result stays positive; lifetime is
+// returned unchanged when either value is <= 0 (a zero lifetime means unlimited).
+func JitterLifetime(lifetime, jitter time.Duration) time.Duration {
+	return jitterLifetime(lifetime, jitter, rand.Int64N)
+}
+
+// jitterLifetime is JitterLifetime with the random source injected; int64n returns a
+// value in [0, n).
+func jitterLifetime(lifetime, jitter time.Duration, int64n func(n int64) int64) time.Duration {
+	if lifetime <= 0 || jitter <= 0 {
+		return lifetime
+	}
+	jitter = min(jitter, lifetime/2)
+
+	offset := time.Duration(int64n(2*int64(jitter)+1)) - jitter
+	return lifetime + offset
+}
+
 // Health checks database connectivity (for /healthz endpoint)
 func Health(db *sql.DB) error {
 	if db == nil {
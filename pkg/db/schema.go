@@ -0,0 +1,124 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// progressTable is the table whose columns VerifySchema checks.
+const progressTable = "user_goal_progress"
+
+// requiredColumn is a user_goal_progress column the repository reads or writes, with the
+// information_schema data types it accepts and the migration that adds it.
+type requiredColumn struct {
+	name      string
+	dataTypes []string
+	migration string
+}
+
+// requiredColumns lists the user_goal_progress columns the repository depends on. Keep it
+// in sync when a migration adds a column that queries use.
+var requiredColumns = []requiredColumn{
+	{"user_id", []string{"character varying"}, "001"},
+	{"goal_id", []string{"character varying"}, "001"},
+	{"challenge_id", []string{"character varying"}, "001"},
+	{"namespace", []string{"character varying"}, "001"},
+	{"progress", []string{"integer", "bigint"}, "001"}, // BIGINT after optional migration 013
+	{"status", []string{"character varying"}, "001"},
+	{"completed_at", []string{"timestamp without time zone"}, "001"},
+	{"claimed_at", []string{"timestamp without time zone"}, "001"},
+	{"created_at", []string{"timestamp without time zone"}, "001"},
+	{"updated_at", []string{"timestamp without time zone"}, "001"},
+	{"is_active", []string{"boolean"}, "001"},
+	{"assigned_at", []string{"timestamp without time zone"}, "001"},
+	{"expires_at", []string{"timestamp without time zone"}, "001"},
+	{"last_daily_date", []string{"date"}, "004"},
+	{"forfeited_at", []string{"timestamp without time zone"}, "008"},
+	{"reserved_until", []string{"timestamp without time zone"}, "012"},
+	{"attempts", []string{"integer"}, "014"},
+	{"order_index", []string{"integer"}, "017"},
+}
+
+// optionalColumns lists the user_goal_progress columns the repository only uses when
+// configured to (e.g. config_checksum with repository.WithConfigChecksum) or for opt-in
+// goal features (completions for repeatable goals, last_delta with
+// repository.WithLastDelta). They may be missing, but must have a compatible type when
+// present.
+var optionalColumns = []requiredColumn{
+	{"config_checksum", []string{"character varying"}, "007"},
+	{"completions", []string{"integer"}, "018"},
+	{"last_delta", []string{"integer"}, "019"},
+}
+
+// VerifySchema checks that user_goal_progress has every column the repository uses, with
+// a compatible type, so a half-applied migration fails the readiness probe instead of
+// surfacing later as a scan error. Only schemas on the search_path are considered.
+// The returned error lists each missing or mistyped column and the migration that adds it.
+func VerifySchema(ctx context.Context, db *sql.DB) error {
+	if db == nil {
+		return fmt.Errorf("database connection is nil")
+	}
+
+	rows, err := db.QueryContext(ctx, `
+		SELECT column_name, data_type
+		FROM information_schema.columns
+		WHERE table_schema = ANY(current_schemas(false))
+		  AND table_name = $1
+	`, progressTable)
+	if err != nil {
+		return fmt.Errorf("failed to read %s columns: %w", progressTable, err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	existing := make(map[string]string)
+	for rows.Next() {
+		var name, dataType string
+		if err := rows.Scan(&name, &dataType); err != nil {
+			return fmt.Errorf("failed to scan column: %w", err)
+		}
+		existing[name] = dataType
+	}
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("failed to read %s columns: %w", progressTable, err)
+	}
+
+	return checkColumns(existing)
+}
+
+// checkColumns compares the columns found in the database (name -> data type) with
+// requiredColumns.
+func checkColumns(existing map[string]string) error {
+	if len(existing) == 0 {
+		return fmt.Errorf("schema mismatch: table %s not found", progressTable)
+	}
+
+	var problems []string
+	for _, col := range requiredColumns {
+		dataType, ok := existing[col.name]
+		switch {
+		case !ok:
+			problems = append(problems, fmt.Sprintf("missing column %s (migration %s)", col.name, col.migration))
+		case !slices.Contains(col.dataTypes, dataType):
+			problems = append(problems, mistypedColumn(col, dataType))
+		}
+	}
+	for _, col := range optionalColumns {
+		if dataType, ok := existing[col.name]; ok && !slices.Contains(col.dataTypes, dataType) {
+			problems = append(problems, mistypedColumn(col, dataType))
+		}
+	}
+	if len(problems) > 0 {
+		return fmt.Errorf("schema mismatch in %s: %s", progressTable, strings.Join(problems, "; "))
+	}
+
+	return nil
+}
+
+// mistypedColumn describes a column whose data type col does not accept.
+func mistypedColumn(col requiredColumn, dataType string) string {
+	return fmt.Sprintf("column %s has type %s, want %s (migration %s)",
+		col.name, dataType, strings.Join(col.dataTypes, " or "), col.migration)
+}
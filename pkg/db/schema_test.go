@@ -0,0 +1,122 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fullSchema returns the columns of a fully migrated user_goal_progress table.
+func fullSchema() map[string]string {
+	columns := make(map[string]string, len(requiredColumns))
+	for _, col := range requiredColumns {
+		columns[col.name] = col.dataTypes[0]
+	}
+	return columns
+}
+
+func TestCheckColumns(t *testing.T) {
+	t.Run("fully migrated", func(t *testing.T) {
+		assert.NoError(t, checkColumns(fullSchema()))
+	})
+
+	t.Run("bigint progress", func(t *testing.T) {
+		columns := fullSchema()
+		columns["progress"] = "bigint"
+		assert.NoError(t, checkColumns(columns))
+	})
+
+	t.Run("extra columns are ignored", func(t *testing.T) {
+		columns := fullSchema()
+		columns["future_column"] = "text"
+		assert.NoError(t, checkColumns(columns))
+	})
+
+	t.Run("missing columns are listed", func(t *testing.T) {
+		columns := fullSchema()
+		delete(columns, "assigned_at")
+		delete(columns, "expires_at")
+
+		err := checkColumns(columns)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "missing column assigned_at (migration 001)")
+		assert.Contains(t, err.Error(), "missing column expires_at (migration 001)")
+		assert.NotContains(t, err.Error(), "is_active")
+	})
+
+	t.Run("wrong type", func(t *testing.T) {
+		columns := fullSchema()
+		columns["is_active"] = "integer"
+
+		err := checkColumns(columns)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "column is_active has type integer, want boolean")
+	})
+
+	t.Run("optional columns may be missing", func(t *testing.T) {
+		columns := fullSchema()
+		assert.NotContains(t, columns, "config_checksum")
+		assert.NoError(t, checkColumns(columns))
+	})
+
+	t.Run("optional column with wrong type", func(t *testing.T) {
+		columns := fullSchema()
+		columns["config_checksum"] = "integer"
+
+		err := checkColumns(columns)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "column config_checksum has type integer, want character varying (migration 007)")
+	})
+
+	t.Run("last_delta with its type", func(t *testing.T) {
+		columns := fullSchema()
+		columns["last_delta"] = "integer"
+		assert.NoError(t, checkColumns(columns))
+	})
+
+	t.Run("last_delta with wrong type", func(t *testing.T) {
+		columns := fullSchema()
+		columns["last_delta"] = "bigint"
+
+		err := checkColumns(columns)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "column last_delta has type bigint, want integer (migration 019)")
+	})
+
+	t.Run("missing table", func(t *testing.T) {
+		err := checkColumns(map[string]string{})
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "table user_goal_progress not found")
+	})
+}
+
+func TestVerifySchema_NilDB(t *testing.T) {
+	err := VerifySchema(context.Background(), nil)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "nil")
+}
+
+func TestVerifySchema_ClosedDB(t *testing.T) {
+	db, err := sql.Open("postgres", "postgres://localhost:1/none?sslmode=disable")
+	require.NoError(t, err)
+	require.NoError(t, db.Close())
+
+	assert.Error(t, VerifySchema(context.Background(), db))
+}
+
+func TestVerifySchema_Success(t *testing.T) {
+	if os.Getenv("DB_HOST") == "" {
+		t.Skip("Skipping integration test: DB_HOST not set")
+	}
+
+	db, err := Connect(NewConfigFromEnv())
+	require.NoError(t, err)
+	defer func() { _ = db.Close() }()
+
+	// Assumes the migrations have been applied to the test database
+	assert.NoError(t, VerifySchema(context.Background(), db))
+}
@@ -0,0 +1,29 @@
+package domain
+
+import "time"
+
+// NextDailyReset returns the start of the next local day after lastUpdate in tz.
+// This is the moment a daily goal last updated at lastUpdate can advance again, and
+// matches the server's same-day check (DATE(updated_at AT TIME ZONE 'UTC')) when tz is UTC.
+// A nil tz is treated as UTC.
+//
+// In zones where a DST transition skips local midnight, the next day starts at the
+// transition instant (e.g., 01:00) rather than a non-existent 00:00.
+func NextDailyReset(lastUpdate time.Time, tz *time.Location) time.Time {
+	if tz == nil {
+		tz = time.UTC
+	}
+
+	local := lastUpdate.In(tz)
+	year, month, day := local.Date()
+	next := time.Date(year, month, day+1, 0, 0, 0, 0, tz)
+
+	// time.Date may resolve a skipped midnight to the previous day; the next day then
+	// begins when the current zone period ends. Noon always exists, so it identifies the day.
+	if next.Day() != time.Date(year, month, day+1, 12, 0, 0, 0, tz).Day() {
+		_, end := next.ZoneBounds()
+		next = end
+	}
+
+	return next
+}
@@ -0,0 +1,114 @@
+package domain
+
+import (
+	"testing"
+	"time"
+	_ "time/tzdata" // Embedded zone database so DST tests do not depend on the host
+)
+
+func mustLoadLocation(t *testing.T, name string) *time.Location {
+	t.Helper()
+
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		t.Fatalf("LoadLocation(%q) failed: %v", name, err)
+	}
+	return loc
+}
+
+func TestNextDailyReset(t *testing.T) {
+	newYork := mustLoadLocation(t, "America/New_York")
+	santiago := mustLoadLocation(t, "America/Santiago")
+
+	tests := []struct {
+		name       string
+		lastUpdate time.Time
+		tz         *time.Location
+		want       time.Time
+	}{
+		{
+			name:       "UTC mid-day",
+			lastUpdate: time.Date(2025, 3, 10, 15, 30, 0, 0, time.UTC),
+			tz:         time.UTC,
+			want:       time.Date(2025, 3, 11, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			name:       "nil location is UTC",
+			lastUpdate: time.Date(2025, 3, 10, 23, 59, 59, 0, time.UTC),
+			tz:         nil,
+			want:       time.Date(2025, 3, 11, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			name:       "exactly midnight advances a full day",
+			lastUpdate: time.Date(2025, 3, 10, 0, 0, 0, 0, time.UTC),
+			tz:         time.UTC,
+			want:       time.Date(2025, 3, 11, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			name:       "end of month",
+			lastUpdate: time.Date(2025, 1, 31, 8, 0, 0, 0, time.UTC),
+			tz:         time.UTC,
+			want:       time.Date(2025, 2, 1, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			name:       "UTC instant on previous local day",
+			lastUpdate: time.Date(2025, 3, 10, 2, 0, 0, 0, time.UTC), // 22:00 on Mar 9 in New York
+			tz:         newYork,
+			want:       time.Date(2025, 3, 10, 0, 0, 0, 0, newYork),
+		},
+		{
+			name:       "New York day before spring forward (23h day)",
+			lastUpdate: time.Date(2025, 3, 8, 12, 0, 0, 0, newYork),
+			tz:         newYork,
+			want:       time.Date(2025, 3, 9, 0, 0, 0, 0, newYork),
+		},
+		{
+			name:       "New York spring forward day",
+			lastUpdate: time.Date(2025, 3, 9, 1, 30, 0, 0, newYork),
+			tz:         newYork,
+			want:       time.Date(2025, 3, 10, 0, 0, 0, 0, newYork),
+		},
+		{
+			name:       "New York fall back day (25h day)",
+			lastUpdate: time.Date(2025, 11, 2, 1, 30, 0, 0, newYork),
+			tz:         newYork,
+			want:       time.Date(2025, 11, 3, 0, 0, 0, 0, newYork),
+		},
+		{
+			name:       "Santiago DST skips midnight",
+			lastUpdate: time.Date(2024, 9, 7, 20, 0, 0, 0, santiago),
+			tz:         santiago,
+			want:       time.Date(2024, 9, 8, 4, 0, 0, 0, time.UTC), // 01:00 -03, first instant of Sep 8
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := NextDailyReset(tt.lastUpdate, tt.tz)
+			if !got.Equal(tt.want) {
+				t.Errorf("NextDailyReset() = %v, want %v", got, tt.want)
+			}
+			if !got.After(tt.lastUpdate) {
+				t.Errorf("NextDailyReset() = %v is not after lastUpdate %v", got, tt.lastUpdate)
+			}
+		})
+	}
+}
+
+func TestNextDailyReset_Hourly(t *testing.T) {
+	// Every hour across the New York DST transitions resets at the next local midnight
+	newYork := mustLoadLocation(t, "America/New_York")
+
+	for _, start := range []time.Time{
+		time.Date(2025, 3, 8, 0, 0, 0, 0, newYork),
+		time.Date(2025, 11, 1, 0, 0, 0, 0, newYork),
+	} {
+		for ts := start; ts.Before(start.Add(72 * time.Hour)); ts = ts.Add(time.Hour) {
+			got := NextDailyReset(ts, newYork).In(newYork)
+			y, m, d := ts.In(newYork).Date()
+			if got.Hour() != 0 || got.Minute() != 0 || got.Day() != time.Date(y, m, d+1, 12, 0, 0, 0, newYork).Day() {
+				t.Errorf("NextDailyReset(%v) = %v, want next local midnight", ts.In(newYork), got)
+			}
+		}
+	}
+}
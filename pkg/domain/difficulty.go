@@ -0,0 +1,113 @@
+package domain
+
+import (
+	"math"
+	"math/rand/v2"
+	"sort"
+)
+
+// Goal difficulty tiers. An empty Difficulty resolves to DifficultyNormal.
+const (
+	DifficultyEasy   = "easy"
+	DifficultyNormal = "normal"
+	DifficultyHard   = "hard"
+)
+
+// Difficulties returns the difficulty tiers from easiest to hardest.
+func Difficulties() []string {
+	return []string{DifficultyEasy, DifficultyNormal, DifficultyHard}
+}
+
+// IsValidDifficulty reports whether d is a known difficulty tier. The empty string is
+// not a tier; callers accept it separately as "use the default".
+func IsValidDifficulty(d string) bool {
+	switch d {
+	case DifficultyEasy, DifficultyNormal, DifficultyHard:
+		return true
+	default:
+		return false
+	}
+}
+
+// DifficultyRank orders difficulty tiers from easiest (0) to hardest. An empty or
+// unknown difficulty ranks as DifficultyNormal.
+func DifficultyRank(d string) int {
+	switch d {
+	case DifficultyEasy:
+		return 0
+	case DifficultyHard:
+		return 2
+	default:
+		return 1
+	}
+}
+
+// EffectiveDifficulty returns the goal's difficulty tier, resolving an empty Difficulty
+// to the documented default (DifficultyNormal).
+func (g *Goal) EffectiveDifficulty() string {
+	if g.Difficulty == "" {
+		return DifficultyNormal
+	}
+	return g.Difficulty
+}
+
+// DifficultyWeights are the per-tier selection multipliers used by SelectRandomGoals.
+// A goal's chance of being drawn is proportional to the weight of its tier; a tier
+// with a weight of zero or less is never selected.
+type DifficultyWeights struct {
+	Easy   float64
+	Normal float64
+	Hard   float64
+}
+
+// DefaultDifficultyWeights favor easy goals 3:2:1 over normal and hard ones.
+var DefaultDifficultyWeights = DifficultyWeights{Easy: 3, Normal: 2, Hard: 1}
+
+// Weight returns the multiplier of a goal's effective difficulty.
+func (w DifficultyWeights) Weight(g *Goal) float64 {
+	switch g.EffectiveDifficulty() {
+	case DifficultyEasy:
+		return w.Easy
+	case DifficultyHard:
+		return w.Hard
+	default:
+		return w.Normal
+	}
+}
+
+// SelectRandomGoals draws up to n distinct goals from candidates, weighted by the
+// difficulty multipliers in weights. Goals whose tier has no positive weight are never
+// drawn, so fewer than n goals are returned when too few candidates remain.
+//
+// rng supplies the randomness; pass a seeded generator for reproducible draws.
+// Selected goals are returned in draw order. candidates is not modified.
+func SelectRandomGoals(candidates []*Goal, n int, weights DifficultyWeights, rng *rand.Rand) []*Goal {
+	// Weighted sampling without replacement (Efraimidis-Spirakis): each goal gets the key
+	// u^(1/w) for a uniform u, and the n largest keys win
+	type keyed struct {
+		goal *Goal
+		key  float64
+	}
+	pool := make([]keyed, 0, len(candidates))
+	for _, g := range candidates {
+		w := weights.Weight(g)
+		if w <= 0 {
+			continue
+		}
+		pool = append(pool, keyed{goal: g, key: math.Pow(rng.Float64(), 1/w)})
+	}
+
+	sort.SliceStable(pool, func(i, j int) bool { return pool[i].key > pool[j].key })
+	if n < 0 {
+		n = 0
+	}
+	if n > len(pool) {
+		n = len(pool)
+	}
+
+	selected := make([]*Goal, n)
+	for i := range selected {
+		selected[i] = pool[i].goal
+	}
+	return selected
+}
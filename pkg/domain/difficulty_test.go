@@ -0,0 +1,113 @@
+package domain
+
+import (
+	"math"
+	"math/rand/v2"
+	"testing"
+)
+
+func TestGoal_EffectiveDifficulty(t *testing.T) {
+	tests := []struct {
+		difficulty string
+		want       string
+	}{
+		{"", DifficultyNormal},
+		{DifficultyEasy, DifficultyEasy},
+		{DifficultyHard, DifficultyHard},
+	}
+
+	for _, tt := range tests {
+		g := &Goal{Difficulty: tt.difficulty}
+		if got := g.EffectiveDifficulty(); got != tt.want {
+			t.Errorf("EffectiveDifficulty(%q) = %q, want %q", tt.difficulty, got, tt.want)
+		}
+	}
+}
+
+// tieredGoals returns two goals per tier; one normal goal leaves Difficulty empty.
+func tieredGoals() []*Goal {
+	return []*Goal{
+		{ID: "easy-1", Difficulty: DifficultyEasy},
+		{ID: "easy-2", Difficulty: DifficultyEasy},
+		{ID: "normal-1", Difficulty: DifficultyNormal},
+		{ID: "normal-2"},
+		{ID: "hard-1", Difficulty: DifficultyHard},
+		{ID: "hard-2", Difficulty: DifficultyHard},
+	}
+}
+
+func TestSelectRandomGoals_DefaultWeightsFavorEasy(t *testing.T) {
+	rng := rand.New(rand.NewPCG(1, 2))
+	candidates := tieredGoals()
+
+	const draws = 30000
+	counts := make(map[string]int)
+	for range draws {
+		selected := SelectRandomGoals(candidates, 1, DefaultDifficultyWeights, rng)
+		if len(selected) != 1 {
+			t.Fatalf("SelectRandomGoals returned %d goals, want 1", len(selected))
+		}
+		counts[selected[0].EffectiveDifficulty()]++
+	}
+
+	// With two goals per tier the expected shares are 3:2:1, i.e. 1/2, 1/3 and 1/6
+	want := map[string]float64{DifficultyEasy: 1.0 / 2, DifficultyNormal: 1.0 / 3, DifficultyHard: 1.0 / 6}
+	for tier, share := range want {
+		if got := float64(counts[tier]) / draws; math.Abs(got-share) > 0.02 {
+			t.Errorf("%s share = %.3f, want %.3f (counts %v)", tier, got, share, counts)
+		}
+	}
+	if !(counts[DifficultyEasy] > counts[DifficultyNormal] && counts[DifficultyNormal] > counts[DifficultyHard]) {
+		t.Errorf("counts = %v, want easy > normal > hard", counts)
+	}
+}
+
+func TestSelectRandomGoals(t *testing.T) {
+	t.Run("draws distinct goals", func(t *testing.T) {
+		rng := rand.New(rand.NewPCG(7, 7))
+		selected := SelectRandomGoals(tieredGoals(), 4, DefaultDifficultyWeights, rng)
+
+		seen := make(map[string]bool)
+		for _, g := range selected {
+			if seen[g.ID] {
+				t.Errorf("goal %s selected twice", g.ID)
+			}
+			seen[g.ID] = true
+		}
+		if len(selected) != 4 {
+			t.Errorf("selected %d goals, want 4", len(selected))
+		}
+	})
+
+	t.Run("zero weight tier is never drawn", func(t *testing.T) {
+		rng := rand.New(rand.NewPCG(3, 4))
+		weights := DifficultyWeights{Easy: 1, Normal: 1}
+
+		selected := SelectRandomGoals(tieredGoals(), 10, weights, rng)
+		if len(selected) != 4 {
+			t.Errorf("selected %d goals, want the 4 easy and normal goals", len(selected))
+		}
+		for _, g := range selected {
+			if g.Difficulty == DifficultyHard {
+				t.Errorf("hard goal %s selected with a zero weight", g.ID)
+			}
+		}
+	})
+
+	t.Run("same seed, same draw", func(t *testing.T) {
+		a := SelectRandomGoals(tieredGoals(), 3, DefaultDifficultyWeights, rand.New(rand.NewPCG(9, 9)))
+		b := SelectRandomGoals(tieredGoals(), 3, DefaultDifficultyWeights, rand.New(rand.NewPCG(9, 9)))
+		for i := range a {
+			if a[i].ID != b[i].ID {
+				t.Fatalf("draws differ at %d: %s vs %s", i, a[i].ID, b[i].ID)
+			}
+		}
+	})
+
+	t.Run("non-positive count", func(t *testing.T) {
+		rng := rand.New(rand.NewPCG(1, 1))
+		if got := SelectRandomGoals(tieredGoals(), -1, DefaultDifficultyWeights, rng); len(got) != 0 {
+			t.Errorf("selected %d goals, want none", len(got))
+		}
+	})
+}
@@ -0,0 +1,62 @@
+package domain
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+)
+
+func TestDuration_UnmarshalJSON(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		want    time.Duration
+		wantErr bool
+	}{
+		{name: "duration string", input: `"1h30m"`, want: 90 * time.Minute},
+		{name: "seconds number", input: `3600`, want: time.Hour},
+		{name: "zero", input: `"0s"`, want: 0},
+		{name: "invalid string", input: `"soon"`, wantErr: true},
+		{name: "invalid type", input: `true`, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var d Duration
+			err := json.Unmarshal([]byte(tt.input), &d)
+
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("UnmarshalJSON(%s) expected error, got nil", tt.input)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("UnmarshalJSON(%s) unexpected error: %v", tt.input, err)
+			}
+			if d.Std() != tt.want {
+				t.Errorf("UnmarshalJSON(%s) = %v, want %v", tt.input, d.Std(), tt.want)
+			}
+		})
+	}
+}
+
+func TestDuration_MarshalJSON(t *testing.T) {
+	data, err := json.Marshal(Duration(time.Hour))
+	if err != nil {
+		t.Fatalf("MarshalJSON failed: %v", err)
+	}
+	if string(data) != `"1h0m0s"` {
+		t.Errorf("MarshalJSON = %s, want \"1h0m0s\"", data)
+	}
+}
+
+func TestGoal_CooldownJSON(t *testing.T) {
+	var goal Goal
+	if err := json.Unmarshal([]byte(`{"goalId": "g1", "type": "increment", "cooldown": "1h"}`), &goal); err != nil {
+		t.Fatalf("Unmarshal failed: %v", err)
+	}
+	if goal.Cooldown.Std() != time.Hour {
+		t.Errorf("Cooldown = %v, want 1h", goal.Cooldown.Std())
+	}
+}
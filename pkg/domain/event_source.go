@@ -0,0 +1,67 @@
+package domain
+
+import (
+	"sort"
+	"sync"
+)
+
+// builtinEventSources are the event sources known to this module, in display order.
+var builtinEventSources = []EventSource{EventSourceStatistic, EventSourceLogin, EventSourceMatch, EventSourceIAP}
+
+// customEventSources holds sources added with RegisterEventSource.
+var (
+	customEventSourcesMu sync.RWMutex
+	customEventSources   = make(map[EventSource]struct{})
+)
+
+// RegisterEventSource adds a custom event source so goals using it pass validation.
+// Embedding services call it during initialization, before loading challenge config.
+// Registering a built-in or already registered source is a no-op. Panics if name is
+// empty, since an empty source is never valid.
+func RegisterEventSource(name string) {
+	if name == "" {
+		panic("domain: RegisterEventSource called with an empty name")
+	}
+
+	e := EventSource(name)
+	if isBuiltinEventSource(e) {
+		return
+	}
+
+	customEventSourcesMu.Lock()
+	defer customEventSourcesMu.Unlock()
+	customEventSources[e] = struct{}{}
+}
+
+// isRegisteredEventSource reports whether e was added with RegisterEventSource.
+func isRegisteredEventSource(e EventSource) bool {
+	customEventSourcesMu.RLock()
+	defer customEventSourcesMu.RUnlock()
+	_, ok := customEventSources[e]
+	return ok
+}
+
+// EventSources returns every valid event source: the built-in sources followed by the
+// registered custom sources in sorted order.
+func EventSources() []EventSource {
+	customEventSourcesMu.RLock()
+	custom := make([]EventSource, 0, len(customEventSources))
+	for e := range customEventSources {
+		custom = append(custom, e)
+	}
+	customEventSourcesMu.RUnlock()
+
+	sort.Slice(custom, func(i, j int) bool { return custom[i] < custom[j] })
+
+	return append(append([]EventSource{}, builtinEventSources...), custom...)
+}
+
+// isBuiltinEventSource reports whether e is one of the built-in sources.
+func isBuiltinEventSource(e EventSource) bool {
+	for _, b := range builtinEventSources {
+		if e == b {
+			return true
+		}
+	}
+	return false
+}
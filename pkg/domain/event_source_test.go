@@ -0,0 +1,45 @@
+package domain
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestRegisterEventSource(t *testing.T) {
+	custom := EventSource("domain-test-tournament")
+	if custom.IsValid() {
+		t.Fatalf("%q is valid before registration", custom)
+	}
+
+	RegisterEventSource(string(custom))
+	RegisterEventSource(string(custom))
+	RegisterEventSource(string(EventSourceLogin))
+
+	if !custom.IsValid() {
+		t.Errorf("%q is not valid after registration", custom)
+	}
+
+	sources := EventSources()
+	if want := builtinEventSources; !reflect.DeepEqual(sources[:len(want)], want) {
+		t.Errorf("EventSources() = %v, want built-ins %v first", sources, want)
+	}
+
+	count := 0
+	for _, s := range sources {
+		if s == custom {
+			count++
+		}
+	}
+	if count != 1 {
+		t.Errorf("EventSources() lists %q %d times, want once", custom, count)
+	}
+
+	t.Run("empty name panics", func(t *testing.T) {
+		defer func() {
+			if recover() == nil {
+				t.Error("RegisterEventSource(\"\") did not panic")
+			}
+		}()
+		RegisterEventSource("")
+	})
+}
@@ -9,6 +9,20 @@ type Challenge struct {
 	Name        string  `json:"name"`
 	Description string  `json:"description"`
 	Goals       []*Goal `json:"goals"`
+
+	// Optional localization keys; DisplayName/DisplayDescription prefer these over Name/Description
+	NameKey        string `json:"nameKey,omitempty"`
+	DescriptionKey string `json:"descriptionKey,omitempty"`
+}
+
+// DisplayName returns the localization key for the challenge name if set, else the raw Name.
+func (c *Challenge) DisplayName() string {
+	return displayString(c.NameKey, c.Name)
+}
+
+// DisplayDescription returns the localization key for the description if set, else the raw Description.
+func (c *Challenge) DisplayDescription() string {
+	return displayString(c.DescriptionKey, c.Description)
 }
 
 // EventSource defines which event stream triggers progress updates for a goal.
@@ -24,15 +38,24 @@ const (
 	// Event: {namespace}.social.statistic.v1.statItemUpdated
 	// Use cases: Kills, wins, score, level, etc.
 	EventSourceStatistic EventSource = "statistic"
+
+	// EventSourceMatch indicates the goal is triggered by match result events.
+	// Use cases: Matches played, matches won
+	EventSourceMatch EventSource = "match"
+
+	// EventSourceIAP indicates the goal is triggered by in-app purchase events.
+	// Use cases: First purchase, purchases made
+	EventSourceIAP EventSource = "iap"
 )
 
-// IsValid returns true if the event source is a valid type.
+// IsValid returns true if the event source is a built-in source or was added with
+// RegisterEventSource.
 func (e EventSource) IsValid() bool {
 	switch e {
-	case EventSourceLogin, EventSourceStatistic:
+	case EventSourceLogin, EventSourceStatistic, EventSourceMatch, EventSourceIAP:
 		return true
 	default:
-		return false
+		return isRegisteredEventSource(e)
 	}
 }
 
@@ -83,14 +106,107 @@ type Goal struct {
 	ID              string      `json:"goalId"`
 	Name            string      `json:"name"`
 	Description     string      `json:"description"`
-	ChallengeID     string      `json:"challengeId"`     // Parent challenge ID
-	Type            GoalType    `json:"type"`            // How progress is tracked (absolute, increment, daily)
-	EventSource     EventSource `json:"eventSource"`     // Which event stream triggers this goal (login, statistic)
-	Daily           bool        `json:"daily"`           // For increment type: true = count once per day, false = count every occurrence
-	DefaultAssigned bool        `json:"defaultAssigned"` // M3: Whether goal is assigned by default to new players
+	ChallengeID     string      `json:"challengeId"`             // Parent challenge ID
+	Type            GoalType    `json:"type"`                    // How progress is tracked (absolute, increment, daily)
+	EventSource     EventSource `json:"eventSource"`             // Which event stream triggers this goal (login, statistic)
+	Daily           bool        `json:"daily"`                   // For increment type: true = count once per day, false = count every occurrence
+	Cooldown        Duration    `json:"cooldown,omitempty"`      // For increment type: minimum time between counted events (0 = no cooldown)
+	ClaimDeadline   Duration    `json:"claimDeadline,omitempty"` // Time after completion within which the reward must be claimed (0 = no deadline)
+	Repeatable      bool        `json:"repeatable,omitempty"`    // For increment type: reaching the target counts a completion and re-arms the goal
+	MaxRepeats      int         `json:"maxRepeats,omitempty"`    // For repeatable goals: cap on completions (0 = unlimited)
+	DefaultAssigned bool        `json:"defaultAssigned"`         // M3: Whether goal is assigned by default to new players
+	Enabled         *bool       `json:"enabled,omitempty"`       // Nil means enabled; false keeps the definition but stops new progress
 	Requirement     Requirement `json:"requirement"`
 	Reward          Reward      `json:"reward"`
-	Prerequisites   []string    `json:"prerequisites"` // Goal IDs that must be completed first
+	Rewards         []Reward    `json:"rewards,omitempty"` // Reward bundle; the loader fills it from Reward (see NormalizeRewards)
+	Prerequisites   []string    `json:"prerequisites"`     // Goal IDs that must be completed first
+	Tags            []string    `json:"tags,omitempty"`    // Designer labels (e.g., "pvp", "social") for filtering boards
+	Difficulty      string      `json:"difficulty"`        // easy, normal or hard; empty means normal (see EffectiveDifficulty)
+	Order           int         `json:"order,omitempty"`   // Display priority on the board, ascending; copied to UserGoalProgress.OrderIndex
+
+	// Optional localization keys; DisplayName/DisplayDescription prefer these over Name/Description
+	NameKey        string `json:"nameKey,omitempty"`
+	DescriptionKey string `json:"descriptionKey,omitempty"`
+
+	// M4: Optional weekly rotation. Goals sharing a RotationGroup are offered in the
+	// zero-based RotationWeek of that group (see CurrentRotationWeek).
+	RotationGroup string `json:"rotationGroup,omitempty"`
+	RotationWeek  int    `json:"rotationWeek,omitempty"`
+}
+
+// DisplayName returns the localization key for the goal name if set, else the raw Name.
+// DTO conversion should use this so clients receive keys once configs provide them.
+func (g *Goal) DisplayName() string {
+	return displayString(g.NameKey, g.Name)
+}
+
+// DisplayDescription returns the localization key for the description if set, else the raw Description.
+func (g *Goal) DisplayDescription() string {
+	return displayString(g.DescriptionKey, g.Description)
+}
+
+// displayString prefers a localization key over the raw display text.
+func displayString(key, text string) string {
+	if key != "" {
+		return key
+	}
+	return text
+}
+
+// AllRewards returns the rewards granted when the goal is claimed.
+// Rewards takes precedence when non-empty (Reward is then ignored); otherwise the
+// single Reward is returned if it is set. Returns nil for a goal without rewards.
+func (g *Goal) AllRewards() []Reward {
+	if len(g.Rewards) > 0 {
+		return g.Rewards
+	}
+	if g.Reward != (Reward{}) {
+		return []Reward{g.Reward}
+	}
+	return nil
+}
+
+// NormalizeRewards fills Rewards with the single Reward when only Reward is set, so code
+// can range over Rewards for both config formats. Reward is kept for readers of the
+// singular field. Calling it again is a no-op.
+func (g *Goal) NormalizeRewards() {
+	if len(g.Rewards) == 0 && g.Reward != (Reward{}) {
+		g.Rewards = []Reward{g.Reward}
+	}
+}
+
+// HasMixedRewards reports whether both Reward and Rewards are set, other than in the
+// normalized form written by NormalizeRewards (Rewards holding just Reward).
+func (g *Goal) HasMixedRewards() bool {
+	if g.Reward == (Reward{}) || len(g.Rewards) == 0 {
+		return false
+	}
+	return len(g.Rewards) != 1 || g.Rewards[0] != g.Reward
+}
+
+// IsEnabled returns true unless the goal is explicitly disabled (Enabled = false).
+func (g *Goal) IsEnabled() bool {
+	return g.Enabled == nil || *g.Enabled
+}
+
+// EffectiveType returns the goal's tracking type, resolving an empty Type to the
+// documented default (GoalTypeAbsolute). Use this instead of reading Type directly.
+func (g *Goal) EffectiveType() GoalType {
+	if g.Type == "" {
+		return GoalTypeAbsolute
+	}
+	return g.Type
+}
+
+// IsIncrementLike returns true if the goal counts occurrences (increment or daily)
+// rather than mirroring an absolute stat value.
+func (g *Goal) IsIncrementLike() bool {
+	switch g.EffectiveType() {
+	case GoalTypeIncrement, GoalTypeDaily:
+		return true
+	default:
+		return false
+	}
 }
 
 // Requirement defines the condition that must be met to complete a goal.
@@ -125,7 +241,7 @@ type UserGoalProgress struct {
 	GoalID      string     `json:"goalId" db:"goal_id"`
 	ChallengeID string     `json:"challengeId" db:"challenge_id"`
 	Namespace   string     `json:"namespace" db:"namespace"`
-	Progress    int        `json:"progress" db:"progress"`
+	Progress    int64      `json:"progress" db:"progress"`
 	Status      GoalStatus `json:"status" db:"status"`
 	CompletedAt *time.Time `json:"completedAt,omitempty" db:"completed_at"`
 	ClaimedAt   *time.Time `json:"claimedAt,omitempty" db:"claimed_at"`
@@ -138,6 +254,35 @@ type UserGoalProgress struct {
 
 	// M5: System rotation control (added now for forward compatibility)
 	ExpiresAt *time.Time `json:"expiresAt,omitempty" db:"expires_at"`
+
+	// ConfigChecksum identifies the goal config that last wrote the row through a batch
+	// path (nil if none did). Read-only: writes stamp the repository's configured checksum.
+	// Only read by repositories built with WithConfigChecksum; nil otherwise.
+	ConfigChecksum *string `json:"configChecksum,omitempty" db:"config_checksum"`
+
+	// ForfeitedAt is set when the claim deadline passed before the reward was claimed
+	// (see repository ForfeitExpiredClaims). A forfeited goal stays completed but can no
+	// longer be claimed.
+	ForfeitedAt *time.Time `json:"forfeitedAt,omitempty" db:"forfeited_at"`
+
+	// Attempts counts tries at the goal recorded through ProgressIncrement.AttemptDelta,
+	// including events that did not advance Progress (e.g., lost matches).
+	Attempts int `json:"attempts" db:"attempts"`
+
+	// OrderIndex is the goal's Order when the row was assigned; user and challenge reads
+	// return rows in ascending OrderIndex. Set it from Goal.Order when building rows for
+	// BulkInsert, EnsureAssigned or UpsertGoalActive; existing rows keep their value.
+	OrderIndex int `json:"orderIndex" db:"order_index"`
+
+	// Completions counts the completed repetitions of a repeatable goal (see
+	// Goal.Repeatable). Always 0 for single-completion goals, and for repositories not
+	// built with WithCompletions.
+	Completions int `json:"completions" db:"completions"`
+
+	// LastDelta is the delta of the most recent increment that changed Progress, applied
+	// at UpdatedAt. A diagnostic aid, only populated by single-row repository reads with
+	// the optional last_delta column enabled; nil otherwise.
+	LastDelta *int `json:"lastDelta,omitempty" db:"last_delta"`
 }
 
 // GoalStatus represents the current state of a user's progress on a goal.
@@ -153,6 +298,11 @@ const (
 	// GoalStatusCompleted indicates the goal requirement has been met but reward not claimed.
 	GoalStatusCompleted GoalStatus = "completed"
 
+	// GoalStatusClaiming indicates the reward is being granted under a claim reservation
+	// (see repository ReserveClaim). Progress updates skip the row until the claim is
+	// confirmed, cancelled, or its reservation expires.
+	GoalStatusClaiming GoalStatus = "claiming"
+
 	// GoalStatusClaimed indicates the goal is completed and reward has been granted.
 	GoalStatusClaimed GoalStatus = "claimed"
 )
@@ -160,16 +310,21 @@ const (
 // IsValid returns true if the status is a valid goal status.
 func (s GoalStatus) IsValid() bool {
 	switch s {
-	case GoalStatusNotStarted, GoalStatusInProgress, GoalStatusCompleted, GoalStatusClaimed:
+	case GoalStatusNotStarted, GoalStatusInProgress, GoalStatusCompleted, GoalStatusClaiming, GoalStatusClaimed:
 		return true
 	default:
 		return false
 	}
 }
 
-// IsCompleted returns true if the goal is in completed or claimed status.
+// IsCompleted returns true if the goal is in completed, claiming or claimed status.
 func (p *UserGoalProgress) IsCompleted() bool {
-	return p.Status == GoalStatusCompleted || p.Status == GoalStatusClaimed
+	return p.Status == GoalStatusCompleted || p.Status == GoalStatusClaiming || p.Status == GoalStatusClaimed
+}
+
+// IsClaiming returns true if the goal is reserved for a claim that has not finished.
+func (p *UserGoalProgress) IsClaiming() bool {
+	return p.Status == GoalStatusClaiming
 }
 
 // IsClaimed returns true if the reward has been claimed.
@@ -177,17 +332,61 @@ func (p *UserGoalProgress) IsClaimed() bool {
 	return p.Status == GoalStatusClaimed
 }
 
+// IsForfeited returns true if the claim window passed and the reward was forfeited.
+func (p *UserGoalProgress) IsForfeited() bool {
+	return p.ForfeitedAt != nil
+}
+
 // CanClaim returns true if the goal can be claimed (completed but not yet claimed).
 // M3 Phase 6: Goal must be active and completed to claim.
+// Forfeited goals cannot be claimed.
 func (p *UserGoalProgress) CanClaim() bool {
-	return p.IsActive && p.Status == GoalStatusCompleted
+	return p.IsActive && p.Status == GoalStatusCompleted && !p.IsForfeited()
+}
+
+// CanReset reports whether a recurring-goal reset may return the row to not_started.
+// Rows reserved by an unfinished claim are never reset. Claimed rows keep their claim
+// history when preserveClaimed is true; passing false allows the claimed -> not_started
+// transition for rewards that can be earned again every period.
+func (p *UserGoalProgress) CanReset(preserveClaimed bool) bool {
+	switch p.Status {
+	case GoalStatusClaiming:
+		return false
+	case GoalStatusClaimed:
+		return !preserveClaimed
+	default:
+		return true
+	}
 }
 
 // MeetsRequirement returns true if the current progress meets the goal's requirement.
 func (p *UserGoalProgress) MeetsRequirement(requirement Requirement) bool {
 	// In M1, only ">=" operator is supported
 	if requirement.Operator == ">=" {
-		return p.Progress >= requirement.TargetValue
+		return p.Progress >= int64(requirement.TargetValue)
 	}
 	return false
 }
+
+// SumClaimableRewards totals the rewards of the given goals, merging entries with the same
+// Type and RewardID. The result keeps the order in which each reward was first seen.
+func SumClaimableRewards(goals []*Goal) []Reward {
+	type rewardKey struct{ rewardType, rewardID string }
+
+	var totals []Reward
+	index := make(map[rewardKey]int)
+
+	for _, goal := range goals {
+		for _, reward := range goal.AllRewards() {
+			key := rewardKey{reward.Type, reward.RewardID}
+			if i, ok := index[key]; ok {
+				totals[i].Quantity += reward.Quantity
+				continue
+			}
+			index[key] = len(totals)
+			totals = append(totals, reward)
+		}
+	}
+
+	return totals
+}
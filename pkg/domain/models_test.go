@@ -21,6 +21,16 @@ func TestEventSource_IsValid(t *testing.T) {
 			source: EventSourceStatistic,
 			want:   true,
 		},
+		{
+			name:   "match is valid",
+			source: EventSourceMatch,
+			want:   true,
+		},
+		{
+			name:   "iap is valid",
+			source: EventSourceIAP,
+			want:   true,
+		},
 		{
 			name:   "invalid source",
 			source: EventSource("invalid"),
@@ -114,6 +124,239 @@ func TestGoalType_IsValid(t *testing.T) {
 	}
 }
 
+func TestGoal_EffectiveType(t *testing.T) {
+	tests := []struct {
+		name              string
+		goalType          GoalType
+		wantType          GoalType
+		wantIncrementLike bool
+	}{
+		{
+			name:     "empty defaults to absolute",
+			goalType: "",
+			wantType: GoalTypeAbsolute,
+		},
+		{
+			name:     "absolute",
+			goalType: GoalTypeAbsolute,
+			wantType: GoalTypeAbsolute,
+		},
+		{
+			name:              "increment",
+			goalType:          GoalTypeIncrement,
+			wantType:          GoalTypeIncrement,
+			wantIncrementLike: true,
+		},
+		{
+			name:              "daily",
+			goalType:          GoalTypeDaily,
+			wantType:          GoalTypeDaily,
+			wantIncrementLike: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			goal := &Goal{Type: tt.goalType}
+
+			if got := goal.EffectiveType(); got != tt.wantType {
+				t.Errorf("Goal.EffectiveType() = %q, want %q", got, tt.wantType)
+			}
+			if got := goal.IsIncrementLike(); got != tt.wantIncrementLike {
+				t.Errorf("Goal.IsIncrementLike() = %v, want %v", got, tt.wantIncrementLike)
+			}
+		})
+	}
+}
+
+func TestDisplayStrings(t *testing.T) {
+	tests := []struct {
+		name            string
+		nameText        string
+		nameKey         string
+		descriptionText string
+		descriptionKey  string
+		wantName        string
+		wantDescription string
+	}{
+		{
+			name:            "keys preferred when present",
+			nameText:        "Win 3 Matches",
+			nameKey:         "goal.win3.name",
+			descriptionText: "Win three matches",
+			descriptionKey:  "goal.win3.description",
+			wantName:        "goal.win3.name",
+			wantDescription: "goal.win3.description",
+		},
+		{
+			name:            "raw text without keys",
+			nameText:        "Win 3 Matches",
+			descriptionText: "Win three matches",
+			wantName:        "Win 3 Matches",
+			wantDescription: "Win three matches",
+		},
+		{
+			name:            "mixed",
+			nameText:        "Win 3 Matches",
+			nameKey:         "goal.win3.name",
+			descriptionText: "Win three matches",
+			wantName:        "goal.win3.name",
+			wantDescription: "Win three matches",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			goal := &Goal{Name: tt.nameText, NameKey: tt.nameKey, Description: tt.descriptionText, DescriptionKey: tt.descriptionKey}
+			challenge := &Challenge{Name: tt.nameText, NameKey: tt.nameKey, Description: tt.descriptionText, DescriptionKey: tt.descriptionKey}
+
+			if got := goal.DisplayName(); got != tt.wantName {
+				t.Errorf("Goal.DisplayName() = %q, want %q", got, tt.wantName)
+			}
+			if got := goal.DisplayDescription(); got != tt.wantDescription {
+				t.Errorf("Goal.DisplayDescription() = %q, want %q", got, tt.wantDescription)
+			}
+			if got := challenge.DisplayName(); got != tt.wantName {
+				t.Errorf("Challenge.DisplayName() = %q, want %q", got, tt.wantName)
+			}
+			if got := challenge.DisplayDescription(); got != tt.wantDescription {
+				t.Errorf("Challenge.DisplayDescription() = %q, want %q", got, tt.wantDescription)
+			}
+		})
+	}
+}
+
+func TestGoal_AllRewards(t *testing.T) {
+	item := Reward{Type: "ITEM", RewardID: "sword", Quantity: 1}
+	gold := Reward{Type: "WALLET", RewardID: "GOLD", Quantity: 100}
+
+	tests := []struct {
+		name string
+		goal *Goal
+		want []Reward
+	}{
+		{
+			name: "single reward only",
+			goal: &Goal{Reward: item},
+			want: []Reward{item},
+		},
+		{
+			name: "bundle only",
+			goal: &Goal{Rewards: []Reward{item, gold}},
+			want: []Reward{item, gold},
+		},
+		{
+			name: "bundle takes precedence over single reward",
+			goal: &Goal{Reward: Reward{Type: "ITEM", RewardID: "ignored", Quantity: 1}, Rewards: []Reward{gold}},
+			want: []Reward{gold},
+		},
+		{
+			name: "no rewards",
+			goal: &Goal{},
+			want: nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := tt.goal.AllRewards()
+			if len(got) != len(tt.want) {
+				t.Fatalf("Goal.AllRewards() = %v, want %v", got, tt.want)
+			}
+			for i := range got {
+				if got[i] != tt.want[i] {
+					t.Errorf("Goal.AllRewards()[%d] = %v, want %v", i, got[i], tt.want[i])
+				}
+			}
+		})
+	}
+}
+
+func TestGoal_NormalizeRewards(t *testing.T) {
+	item := Reward{Type: "ITEM", RewardID: "sword", Quantity: 1}
+	gold := Reward{Type: "WALLET", RewardID: "GOLD", Quantity: 100}
+
+	t.Run("single reward is copied into rewards", func(t *testing.T) {
+		g := &Goal{Reward: item}
+		g.NormalizeRewards()
+		g.NormalizeRewards()
+		if len(g.Rewards) != 1 || g.Rewards[0] != item || g.Reward != item {
+			t.Errorf("after NormalizeRewards: Reward = %v, Rewards = %v, want both %v", g.Reward, g.Rewards, item)
+		}
+		if g.HasMixedRewards() {
+			t.Error("HasMixedRewards() = true for a normalized goal, want false")
+		}
+	})
+
+	t.Run("bundle is left unchanged", func(t *testing.T) {
+		g := &Goal{Rewards: []Reward{item, gold}}
+		g.NormalizeRewards()
+		if len(g.Rewards) != 2 || g.Reward != (Reward{}) {
+			t.Errorf("after NormalizeRewards: Reward = %v, Rewards = %v", g.Reward, g.Rewards)
+		}
+	})
+
+	t.Run("no rewards stays empty", func(t *testing.T) {
+		g := &Goal{}
+		g.NormalizeRewards()
+		if g.Rewards != nil {
+			t.Errorf("Rewards = %v, want nil", g.Rewards)
+		}
+	})
+}
+
+func TestGoal_HasMixedRewards(t *testing.T) {
+	item := Reward{Type: "ITEM", RewardID: "sword", Quantity: 1}
+	gold := Reward{Type: "WALLET", RewardID: "GOLD", Quantity: 100}
+
+	tests := []struct {
+		name string
+		goal *Goal
+		want bool
+	}{
+		{"reward only", &Goal{Reward: item}, false},
+		{"rewards only", &Goal{Rewards: []Reward{item, gold}}, false},
+		{"normalized", &Goal{Reward: item, Rewards: []Reward{item}}, false},
+		{"different single reward", &Goal{Reward: item, Rewards: []Reward{gold}}, true},
+		{"reward plus bundle", &Goal{Reward: item, Rewards: []Reward{item, gold}}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.goal.HasMixedRewards(); got != tt.want {
+				t.Errorf("HasMixedRewards() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSumClaimableRewards(t *testing.T) {
+	goals := []*Goal{
+		{Reward: Reward{Type: "WALLET", RewardID: "GOLD", Quantity: 50}},
+		{Rewards: []Reward{
+			{Type: "ITEM", RewardID: "sword", Quantity: 1},
+			{Type: "WALLET", RewardID: "GOLD", Quantity: 100},
+		}},
+		{Rewards: []Reward{{Type: "ITEM", RewardID: "GOLD", Quantity: 2}}}, // Same ID, different type
+	}
+
+	want := []Reward{
+		{Type: "WALLET", RewardID: "GOLD", Quantity: 150},
+		{Type: "ITEM", RewardID: "sword", Quantity: 1},
+		{Type: "ITEM", RewardID: "GOLD", Quantity: 2},
+	}
+
+	got := SumClaimableRewards(goals)
+	if len(got) != len(want) {
+		t.Fatalf("SumClaimableRewards() = %v, want %v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("SumClaimableRewards()[%d] = %v, want %v", i, got[i], want[i])
+		}
+	}
+}
+
 func TestGoalStatus_IsValid(t *testing.T) {
 	tests := []struct {
 		name   string
@@ -135,6 +378,11 @@ func TestGoalStatus_IsValid(t *testing.T) {
 			status: GoalStatusCompleted,
 			want:   true,
 		},
+		{
+			name:   "claiming is valid",
+			status: GoalStatusClaiming,
+			want:   true,
+		},
 		{
 			name:   "claimed is valid",
 			status: GoalStatusClaimed,
@@ -188,6 +436,13 @@ func TestUserGoalProgress_IsCompleted(t *testing.T) {
 			},
 			want: true,
 		},
+		{
+			name: "claiming is completed",
+			progress: &UserGoalProgress{
+				Status: GoalStatusClaiming,
+			},
+			want: true,
+		},
 		{
 			name: "claimed is completed",
 			progress: &UserGoalProgress{
@@ -233,6 +488,13 @@ func TestUserGoalProgress_IsClaimed(t *testing.T) {
 			},
 			want: false,
 		},
+		{
+			name: "claiming is not claimed",
+			progress: &UserGoalProgress{
+				Status: GoalStatusClaiming,
+			},
+			want: false,
+		},
 		{
 			name: "claimed is claimed",
 			progress: &UserGoalProgress{
@@ -306,6 +568,15 @@ func TestUserGoalProgress_CanClaim(t *testing.T) {
 			},
 			want: false,
 		},
+		{
+			name: "completed but forfeited cannot claim",
+			progress: &UserGoalProgress{
+				Status:      GoalStatusCompleted,
+				IsActive:    true,
+				ForfeitedAt: &time.Time{},
+			},
+			want: false,
+		},
 	}
 
 	for _, tt := range tests {
@@ -317,6 +588,32 @@ func TestUserGoalProgress_CanClaim(t *testing.T) {
 	}
 }
 
+func TestUserGoalProgress_CanReset(t *testing.T) {
+	tests := []struct {
+		status              GoalStatus
+		wantPreserveClaimed bool
+		wantRepeatable      bool
+	}{
+		{GoalStatusNotStarted, true, true},
+		{GoalStatusInProgress, true, true},
+		{GoalStatusCompleted, true, true},
+		{GoalStatusClaiming, false, false},
+		{GoalStatusClaimed, false, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.status), func(t *testing.T) {
+			p := &UserGoalProgress{Status: tt.status}
+			if got := p.CanReset(true); got != tt.wantPreserveClaimed {
+				t.Errorf("CanReset(true) = %v, want %v", got, tt.wantPreserveClaimed)
+			}
+			if got := p.CanReset(false); got != tt.wantRepeatable {
+				t.Errorf("CanReset(false) = %v, want %v", got, tt.wantRepeatable)
+			}
+		})
+	}
+}
+
 func TestUserGoalProgress_MeetsRequirement(t *testing.T) {
 	tests := []struct {
 		name        string
@@ -0,0 +1,92 @@
+package domain
+
+import (
+	"fmt"
+	"time"
+)
+
+// dailyLatenessSlackDays is how many days before a row's creation a late-arriving daily
+// event may legitimately be counted (the repository's default max event lateness is 48h).
+const dailyLatenessSlackDays = 2
+
+// ValidateProgress checks that a stored progress row is internally consistent with its
+// goal definition and returns a description of each inconsistency found. An empty result
+// means the row is consistent. It backs data-integrity tooling and does not modify p.
+//
+// Checks cover identity (goal and challenge IDs), status vs progress against the target,
+// completed_at/claimed_at vs status, and for once-per-day goals a progress value that
+// cannot have been reached in the days the row has existed.
+func ValidateProgress(p *UserGoalProgress, g *Goal) []string {
+	var issues []string
+	addf := func(format string, args ...interface{}) {
+		issues = append(issues, fmt.Sprintf(format, args...))
+	}
+
+	if p.GoalID != g.ID {
+		addf("goal_id '%s' does not match goal '%s'", p.GoalID, g.ID)
+	}
+	if g.ChallengeID != "" && p.ChallengeID != g.ChallengeID {
+		addf("challenge_id '%s' does not match the goal's challenge '%s'", p.ChallengeID, g.ChallengeID)
+	}
+
+	if !p.Status.IsValid() {
+		addf("unknown status '%s'", p.Status)
+	}
+	if p.Progress < 0 {
+		addf("progress %d is negative", p.Progress)
+	}
+
+	// Daily goals complete per day and do not compare progress to the target
+	target := g.Requirement.TargetValue
+	if g.EffectiveType() != GoalTypeDaily {
+		switch p.Status {
+		case GoalStatusNotStarted, GoalStatusInProgress:
+			if p.MeetsRequirement(g.Requirement) {
+				addf("status '%s' but progress %d meets target %d", p.Status, p.Progress, target)
+			}
+		case GoalStatusCompleted, GoalStatusClaiming, GoalStatusClaimed:
+			if !p.MeetsRequirement(g.Requirement) {
+				addf("status '%s' but progress %d is below target %d", p.Status, p.Progress, target)
+			}
+		}
+	}
+	if p.Status == GoalStatusNotStarted && p.Progress > 0 {
+		addf("status 'not_started' but progress is %d", p.Progress)
+	}
+
+	switch {
+	case p.IsCompleted() && p.CompletedAt == nil:
+		addf("status '%s' but completed_at is not set", p.Status)
+	case !p.IsCompleted() && p.CompletedAt != nil:
+		addf("status '%s' but completed_at is set", p.Status)
+	}
+	switch {
+	case p.IsClaimed() && p.ClaimedAt == nil:
+		addf("status 'claimed' but claimed_at is not set")
+	case !p.IsClaimed() && p.ClaimedAt != nil:
+		addf("status '%s' but claimed_at is set", p.Status)
+	}
+	if p.CompletedAt != nil && p.ClaimedAt != nil && p.ClaimedAt.Before(*p.CompletedAt) {
+		addf("claimed_at %s is before completed_at %s", p.ClaimedAt.Format(time.RFC3339), p.CompletedAt.Format(time.RFC3339))
+	}
+
+	// Once-per-day goals advance by at most one per UTC day
+	countsDaily := g.EffectiveType() == GoalTypeDaily || (g.EffectiveType() == GoalTypeIncrement && g.Daily)
+	if countsDaily && !p.CreatedAt.IsZero() && !p.UpdatedAt.IsZero() {
+		if maxProgress := int64(utcDaysBetween(p.CreatedAt, p.UpdatedAt) + 1 + dailyLatenessSlackDays); p.Progress > maxProgress {
+			addf("daily progress %d exceeds the %d days it can have been counted since %s",
+				p.Progress, maxProgress, p.CreatedAt.UTC().Format(time.DateOnly))
+		}
+	}
+
+	return issues
+}
+
+// utcDaysBetween returns the number of UTC calendar days from from to to (0 on the same day).
+func utcDaysBetween(from, to time.Time) int {
+	fy, fm, fd := from.UTC().Date()
+	ty, tm, td := to.UTC().Date()
+	start := time.Date(fy, fm, fd, 0, 0, 0, 0, time.UTC)
+	end := time.Date(ty, tm, td, 0, 0, 0, 0, time.UTC)
+	return int(end.Sub(start).Hours() / 24)
+}
@@ -0,0 +1,148 @@
+package domain
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestValidateProgress(t *testing.T) {
+	created := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	completed := created.Add(48 * time.Hour)
+	claimed := completed.Add(time.Hour)
+
+	goal := func(mutate func(g *Goal)) *Goal {
+		g := &Goal{
+			ID:          "goal-1",
+			ChallengeID: "challenge-1",
+			Type:        GoalTypeIncrement,
+			Requirement: Requirement{StatCode: "kills", Operator: ">=", TargetValue: 10},
+		}
+		if mutate != nil {
+			mutate(g)
+		}
+		return g
+	}
+	row := func(mutate func(p *UserGoalProgress)) *UserGoalProgress {
+		p := &UserGoalProgress{
+			UserID:      "user-1",
+			GoalID:      "goal-1",
+			ChallengeID: "challenge-1",
+			Progress:    5,
+			Status:      GoalStatusInProgress,
+			CreatedAt:   created,
+			UpdatedAt:   created.Add(time.Hour),
+		}
+		if mutate != nil {
+			mutate(p)
+		}
+		return p
+	}
+	completedRow := func(p *UserGoalProgress) {
+		p.Progress = 10
+		p.Status = GoalStatusCompleted
+		p.CompletedAt = &completed
+	}
+	claimedRow := func(p *UserGoalProgress) {
+		completedRow(p)
+		p.Status = GoalStatusClaimed
+		p.ClaimedAt = &claimed
+	}
+
+	tests := []struct {
+		name string
+		p    *UserGoalProgress
+		g    *Goal
+		want []string // substrings, one per expected issue
+	}{
+		{name: "in progress", p: row(nil), g: goal(nil)},
+		{name: "not started", p: row(func(p *UserGoalProgress) { p.Progress = 0; p.Status = GoalStatusNotStarted }), g: goal(nil)},
+		{name: "completed", p: row(completedRow), g: goal(nil)},
+		{name: "claimed", p: row(claimedRow), g: goal(nil)},
+		{
+			name: "daily goal within day count",
+			p:    row(func(p *UserGoalProgress) { p.Progress = 3; p.UpdatedAt = created.Add(24 * time.Hour) }),
+			g:    goal(func(g *Goal) { g.Daily = true }),
+		},
+		{
+			name: "daily type ignores progress vs target",
+			p:    row(func(p *UserGoalProgress) { p.Progress = 1; p.Status = GoalStatusCompleted; p.CompletedAt = &completed }),
+			g:    goal(func(g *Goal) { g.Type = GoalTypeDaily }),
+		},
+		{
+			name: "wrong goal and challenge",
+			p:    row(func(p *UserGoalProgress) { p.GoalID = "goal-2"; p.ChallengeID = "challenge-2" }),
+			g:    goal(nil),
+			want: []string{"goal_id 'goal-2' does not match goal 'goal-1'", "challenge_id 'challenge-2'"},
+		},
+		{
+			name: "unknown status and negative progress",
+			p:    row(func(p *UserGoalProgress) { p.Status = "done"; p.Progress = -1 }),
+			g:    goal(nil),
+			want: []string{"unknown status 'done'", "progress -1 is negative"},
+		},
+		{
+			name: "in progress at target",
+			p:    row(func(p *UserGoalProgress) { p.Progress = 12 }),
+			g:    goal(nil),
+			want: []string{"status 'in_progress' but progress 12 meets target 10"},
+		},
+		{
+			name: "completed below target",
+			p:    row(func(p *UserGoalProgress) { completedRow(p); p.Progress = 9 }),
+			g:    goal(nil),
+			want: []string{"status 'completed' but progress 9 is below target 10"},
+		},
+		{
+			name: "not started with progress",
+			p:    row(func(p *UserGoalProgress) { p.Status = GoalStatusNotStarted }),
+			g:    goal(nil),
+			want: []string{"status 'not_started' but progress is 5"},
+		},
+		{
+			name: "completed without completed_at",
+			p:    row(func(p *UserGoalProgress) { completedRow(p); p.CompletedAt = nil }),
+			g:    goal(nil),
+			want: []string{"status 'completed' but completed_at is not set"},
+		},
+		{
+			name: "in progress with completed_at and claimed_at",
+			p:    row(func(p *UserGoalProgress) { p.CompletedAt = &completed; p.ClaimedAt = &claimed }),
+			g:    goal(nil),
+			want: []string{"status 'in_progress' but completed_at is set", "status 'in_progress' but claimed_at is set"},
+		},
+		{
+			name: "claimed without claimed_at",
+			p:    row(func(p *UserGoalProgress) { claimedRow(p); p.ClaimedAt = nil }),
+			g:    goal(nil),
+			want: []string{"status 'claimed' but claimed_at is not set"},
+		},
+		{
+			name: "claimed before completed",
+			p:    row(func(p *UserGoalProgress) { claimedRow(p); p.ClaimedAt = &created }),
+			g:    goal(nil),
+			want: []string{"is before completed_at"},
+		},
+		{
+			name: "daily progress beyond possible days",
+			p:    row(func(p *UserGoalProgress) { p.Progress = 9; p.UpdatedAt = created.Add(24 * time.Hour) }),
+			g:    goal(func(g *Goal) { g.Daily = true }),
+			want: []string{"daily progress 9 exceeds the 4 days it can have been counted since 2026-03-01"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			issues := ValidateProgress(tt.p, tt.g)
+
+			if len(issues) != len(tt.want) {
+				t.Fatalf("ValidateProgress() = %q, want %d issues", issues, len(tt.want))
+			}
+			for i, want := range tt.want {
+				if !strings.Contains(issues[i], want) {
+					t.Errorf("issue[%d] = %q, want it to contain %q", i, issues[i], want)
+				}
+			}
+		})
+	}
+}
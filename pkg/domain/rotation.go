@@ -0,0 +1,22 @@
+package domain
+
+import "time"
+
+// CurrentRotationWeek returns the zero-based rotation period containing now for a
+// rotation that started at start and advances every period (e.g., 7 days for weekly
+// rotations). Period boundaries are exact durations from start, so they do not move
+// with DST or the caller's time zone.
+//
+// Returns -1 before start (no rotation is active yet) and 0 when period is not positive.
+// The result is not wrapped; services that cycle through a fixed number of weeks apply
+// the modulo themselves.
+func CurrentRotationWeek(start, now time.Time, period time.Duration) int {
+	if now.Before(start) {
+		return -1
+	}
+	if period <= 0 {
+		return 0
+	}
+
+	return int(now.Sub(start) / period)
+}
@@ -0,0 +1,34 @@
+package domain
+
+import (
+	"testing"
+	"time"
+)
+
+func TestCurrentRotationWeek(t *testing.T) {
+	start := time.Date(2025, 3, 3, 0, 0, 0, 0, time.UTC) // A Monday
+	week := 7 * 24 * time.Hour
+
+	tests := []struct {
+		name   string
+		now    time.Time
+		period time.Duration
+		want   int
+	}{
+		{name: "at start", now: start, period: week, want: 0},
+		{name: "end of first week", now: start.Add(week - time.Nanosecond), period: week, want: 0},
+		{name: "start of second week", now: start.Add(week), period: week, want: 1},
+		{name: "tenth week", now: start.Add(9*week + 36*time.Hour), period: week, want: 9},
+		{name: "before start", now: start.Add(-time.Second), period: week, want: -1},
+		{name: "zero period", now: start.Add(3 * week), period: 0, want: 0},
+		{name: "other time zone for now", now: start.Add(week).In(time.FixedZone("UTC-5", -5*3600)), period: week, want: 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := CurrentRotationWeek(start, tt.now, tt.period); got != tt.want {
+				t.Errorf("CurrentRotationWeek() = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
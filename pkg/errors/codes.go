@@ -0,0 +1,130 @@
+package errors
+
+import (
+	stderrors "errors"
+	"net/http"
+)
+
+// Code is a stable, machine-readable error code carried by ChallengeError.
+type Code string
+
+// gRPC status codes, numbered as in google.golang.org/grpc/codes. They are declared here
+// so the package does not depend on gRPC; see Code.GRPCCode.
+const (
+	grpcUnknown            uint32 = 2
+	grpcInvalidArgument    uint32 = 3
+	grpcNotFound           uint32 = 5
+	grpcAlreadyExists      uint32 = 6
+	grpcPermissionDenied   uint32 = 7
+	grpcResourceExhausted  uint32 = 8
+	grpcFailedPrecondition uint32 = 9
+	grpcAborted            uint32 = 10
+	grpcOutOfRange         uint32 = 11
+	grpcInternal           uint32 = 13
+	grpcUnavailable        uint32 = 14
+	grpcUnauthenticated    uint32 = 16
+)
+
+// codeTransport is the recommended transport mapping of a code. HTTP statuses follow the
+// gRPC-gateway mapping of the gRPC code, so both transports agree.
+type codeTransport struct {
+	code       Code
+	httpStatus int
+	grpcCode   uint32
+}
+
+// codeTable lists every code in declaration order with its transport mapping.
+var codeTable = []codeTransport{
+	{ErrCodeGoalNotFound, http.StatusNotFound, grpcNotFound},
+	{ErrCodeChallengeNotFound, http.StatusNotFound, grpcNotFound},
+	{ErrCodeGoalAlreadyClaimed, http.StatusConflict, grpcAlreadyExists},
+	{ErrCodeGoalNotCompleted, http.StatusBadRequest, grpcFailedPrecondition},
+	{ErrCodeInvalidStatus, http.StatusBadRequest, grpcInvalidArgument},
+	{ErrCodeClaimWindowExpired, http.StatusBadRequest, grpcFailedPrecondition},
+	{ErrCodeClaimInProgress, http.StatusConflict, grpcAborted},
+	{ErrCodeClaimNotReserved, http.StatusBadRequest, grpcFailedPrecondition},
+	{ErrCodeProgressNotFound, http.StatusNotFound, grpcNotFound},
+	{ErrCodeNotAllClaimable, http.StatusBadRequest, grpcFailedPrecondition},
+
+	{ErrCodeDatabaseError, http.StatusInternalServerError, grpcInternal},
+	{ErrCodeTransactionFailed, http.StatusInternalServerError, grpcInternal},
+
+	{ErrCodeNegativeProgress, http.StatusBadRequest, grpcInvalidArgument},
+	{ErrCodeClaimWithoutCompletion, http.StatusBadRequest, grpcFailedPrecondition},
+	{ErrCodeDuplicateKey, http.StatusConflict, grpcAlreadyExists},
+
+	{ErrCodeConfigInvalid, http.StatusInternalServerError, grpcInternal},
+	{ErrCodeConfigNotFound, http.StatusInternalServerError, grpcInternal},
+
+	{ErrCodeRewardGrantFailed, http.StatusServiceUnavailable, grpcUnavailable},
+	{ErrCodeAuthFailed, http.StatusUnauthorized, grpcUnauthenticated},
+
+	{ErrCodeValidationFailed, http.StatusBadRequest, grpcInvalidArgument},
+	{ErrCodeInvalidInput, http.StatusBadRequest, grpcInvalidArgument},
+	{ErrCodeInvalidCursor, http.StatusBadRequest, grpcInvalidArgument},
+	{ErrCodeInvalidIncrement, http.StatusBadRequest, grpcInvalidArgument},
+	{ErrCodeProgressOverflow, http.StatusBadRequest, grpcOutOfRange},
+
+	{ErrCodeOperationNotAllowed, http.StatusForbidden, grpcPermissionDenied},
+	{ErrCodeShuttingDown, http.StatusServiceUnavailable, grpcUnavailable},
+	{ErrCodeRateLimited, http.StatusTooManyRequests, grpcResourceExhausted},
+
+	{ErrCodeInsufficientGoals, http.StatusBadRequest, grpcFailedPrecondition},
+}
+
+// codeIndex looks up codeTable entries by code.
+var codeIndex = func() map[Code]codeTransport {
+	index := make(map[Code]codeTransport, len(codeTable))
+	for _, entry := range codeTable {
+		index[entry.code] = entry
+	}
+	return index
+}()
+
+// Codes returns every error code, in declaration order.
+func Codes() []Code {
+	codes := make([]Code, len(codeTable))
+	for i, entry := range codeTable {
+		codes[i] = entry.code
+	}
+	return codes
+}
+
+// String returns the code as sent to clients.
+func (c Code) String() string {
+	return string(c)
+}
+
+// IsValid reports whether c is one of the codes listed by Codes.
+func (c Code) IsValid() bool {
+	_, ok := codeIndex[c]
+	return ok
+}
+
+// HTTPStatus returns the recommended HTTP status for c, or 500 for an unknown code.
+func (c Code) HTTPStatus() int {
+	if entry, ok := codeIndex[c]; ok {
+		return entry.httpStatus
+	}
+	return http.StatusInternalServerError
+}
+
+// GRPCCode returns the recommended gRPC status code for c as its numeric value, or
+// Unknown (2) for an unknown code. Convert it with codes.Code(c.GRPCCode()).
+func (c Code) GRPCCode() uint32 {
+	if entry, ok := codeIndex[c]; ok {
+		return entry.grpcCode
+	}
+	return grpcUnknown
+}
+
+// CodeOf returns the code of the first ChallengeError in err's chain, following
+// fmt.Errorf("%w") and other Unwrap wrappers. Returns "" if err is nil or carries no
+// ChallengeError.
+func CodeOf(err error) Code {
+	var ce *ChallengeError
+	if stderrors.As(err, &ce) {
+		return ce.Code
+	}
+	return ""
+}
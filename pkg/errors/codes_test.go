@@ -0,0 +1,94 @@
+package errors
+
+import (
+	"bytes"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"testing"
+)
+
+var update = flag.Bool("update", false, "rewrite testdata/codes.golden from codeTable")
+
+const codesGoldenFile = "testdata/codes.golden"
+
+// TestCodes_Golden pins every code's string and transport mapping. Codes are matched by
+// clients, so the golden file may only grow: a diff that changes or drops an existing line
+// renames a code or changes its status, and needs a deliberate, announced migration.
+func TestCodes_Golden(t *testing.T) {
+	var got bytes.Buffer
+	fmt.Fprintln(&got, "# code http grpc")
+	for _, code := range Codes() {
+		fmt.Fprintf(&got, "%s %d %d\n", code, code.HTTPStatus(), code.GRPCCode())
+	}
+
+	if *update {
+		if err := os.WriteFile(codesGoldenFile, got.Bytes(), 0o644); err != nil {
+			t.Fatalf("Failed to update %s: %v", codesGoldenFile, err)
+		}
+	}
+
+	want, err := os.ReadFile(codesGoldenFile)
+	if err != nil {
+		t.Fatalf("Failed to read %s: %v", codesGoldenFile, err)
+	}
+	if !bytes.Equal(got.Bytes(), want) {
+		t.Errorf("%s does not match codeTable; run go test ./pkg/errors -update after an additive change\ngot:\n%s", codesGoldenFile, got.String())
+	}
+}
+
+func TestCodes(t *testing.T) {
+	seen := make(map[Code]bool)
+	for _, code := range Codes() {
+		if seen[code] {
+			t.Errorf("%s listed twice", code)
+		}
+		seen[code] = true
+
+		if !code.IsValid() {
+			t.Errorf("%s.IsValid() = false", code)
+		}
+		if code.String() != string(code) {
+			t.Errorf("%s.String() = %q", code, code.String())
+		}
+	}
+
+	unknown := Code("NOT_A_CODE")
+	if unknown.IsValid() {
+		t.Error("unknown code IsValid() = true")
+	}
+	if got := unknown.HTTPStatus(); got != 500 {
+		t.Errorf("unknown code HTTPStatus() = %d, want 500", got)
+	}
+	if got := unknown.GRPCCode(); got != 2 {
+		t.Errorf("unknown code GRPCCode() = %d, want 2 (Unknown)", got)
+	}
+}
+
+func TestCodeOf(t *testing.T) {
+	notFound := ErrGoalNotFound("goal-1")
+
+	tests := []struct {
+		name string
+		err  error
+		want Code
+	}{
+		{"nil", nil, ""},
+		{"plain error", errors.New("boom"), ""},
+		{"challenge error", notFound, ErrCodeGoalNotFound},
+		{"wrapped once", fmt.Errorf("claim: %w", notFound), ErrCodeGoalNotFound},
+		{"wrapped twice", fmt.Errorf("handler: %w", fmt.Errorf("claim: %w", notFound)), ErrCodeGoalNotFound},
+		{"joined", errors.Join(errors.New("first"), notFound), ErrCodeGoalNotFound},
+		{"outermost challenge error wins", fmt.Errorf("tx: %w", ErrTransactionFailed("commit", notFound)), ErrCodeTransactionFailed},
+		{"formatted without %w", fmt.Errorf("claim: %v", notFound), ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := CodeOf(tt.err); got != tt.want {
+				t.Errorf("CodeOf() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
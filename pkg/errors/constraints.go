@@ -0,0 +1,57 @@
+package errors
+
+import (
+	stderrors "errors"
+	"fmt"
+	"regexp"
+
+	"github.com/lib/pq"
+)
+
+// constraintViolation describes a known database constraint.
+type constraintViolation struct {
+	code        Code
+	description string
+}
+
+// knownConstraints maps constraint names from the migrations to typed error codes, so a
+// violation surfaces as e.g. NEGATIVE_PROGRESS instead of a generic DATABASE_ERROR.
+var knownConstraints = map[string]constraintViolation{
+	"check_progress_non_negative":     {ErrCodeNegativeProgress, "progress would become negative"},
+	"check_claimed_implies_completed": {ErrCodeClaimWithoutCompletion, "claimed_at set without completed_at"},
+	"check_status":                    {ErrCodeInvalidStatus, "status not allowed"},
+	"user_goal_progress_pkey":         {ErrCodeDuplicateKey, "duplicate key"},
+}
+
+// keyDetail matches the key part of a unique violation's detail, e.g.
+// "Key (user_id, goal_id)=(u1, g1) already exists."
+var keyDetail = regexp.MustCompile(`^Key (\(.*\)=\(.*\))`)
+
+// constraintError returns the typed error for a violation of a known constraint, or nil
+// if err is not one. The message names the constraint, its table and, for key
+// violations, the key values from the error detail.
+func constraintError(operation string, err error) *ChallengeError {
+	var pqErr *pq.Error
+	if !stderrors.As(err, &pqErr) || pqErr.Constraint == "" {
+		return nil
+	}
+	violation, ok := knownConstraints[pqErr.Constraint]
+	if !ok {
+		return nil
+	}
+
+	message := fmt.Sprintf("database error during %s: %s (constraint %s", operation, violation.description, pqErr.Constraint)
+	if pqErr.Table != "" {
+		message += " on " + pqErr.Table
+	}
+	message += ")"
+	if m := keyDetail.FindStringSubmatch(pqErr.Detail); m != nil {
+		message += ": " + m[1]
+	}
+
+	return &ChallengeError{
+		Code:    violation.code,
+		Message: message,
+		Err:     err,
+	}
+}
@@ -1,39 +1,62 @@
 package errors
 
-import "fmt"
+import (
+	"fmt"
+	"strings"
+)
 
-// Error codes for the challenge service.
+// Error codes for the challenge service. Codes are part of the public API: clients and
+// downstream services match on them, so a code is never renamed or removed (see
+// TestCodes_Golden). Add new codes to codeTable in codes.go as well.
 const (
 	// Domain errors
-	ErrCodeGoalNotFound       = "GOAL_NOT_FOUND"
-	ErrCodeChallengeNotFound  = "CHALLENGE_NOT_FOUND"
-	ErrCodeGoalAlreadyClaimed = "GOAL_ALREADY_CLAIMED"
-	ErrCodeGoalNotCompleted   = "GOAL_NOT_COMPLETED"
-	ErrCodeInvalidStatus      = "INVALID_STATUS"
+	ErrCodeGoalNotFound       Code = "GOAL_NOT_FOUND"
+	ErrCodeChallengeNotFound  Code = "CHALLENGE_NOT_FOUND"
+	ErrCodeGoalAlreadyClaimed Code = "GOAL_ALREADY_CLAIMED"
+	ErrCodeGoalNotCompleted   Code = "GOAL_NOT_COMPLETED"
+	ErrCodeInvalidStatus      Code = "INVALID_STATUS"
+	ErrCodeClaimWindowExpired Code = "CLAIM_WINDOW_EXPIRED"
+	ErrCodeClaimInProgress    Code = "CLAIM_IN_PROGRESS"
+	ErrCodeClaimNotReserved   Code = "CLAIM_NOT_RESERVED"
+	ErrCodeProgressNotFound   Code = "PROGRESS_NOT_FOUND"
+	ErrCodeNotAllClaimable    Code = "NOT_ALL_CLAIMABLE"
 
 	// Database errors
-	ErrCodeDatabaseError     = "DATABASE_ERROR"
-	ErrCodeTransactionFailed = "TRANSACTION_FAILED"
+	ErrCodeDatabaseError     Code = "DATABASE_ERROR"
+	ErrCodeTransactionFailed Code = "TRANSACTION_FAILED"
+
+	// Constraint violations (see ErrDatabaseError)
+	ErrCodeNegativeProgress       Code = "NEGATIVE_PROGRESS"
+	ErrCodeClaimWithoutCompletion Code = "CLAIM_WITHOUT_COMPLETION"
+	ErrCodeDuplicateKey           Code = "DUPLICATE_KEY"
 
 	// Config errors
-	ErrCodeConfigInvalid  = "CONFIG_INVALID"
-	ErrCodeConfigNotFound = "CONFIG_NOT_FOUND"
+	ErrCodeConfigInvalid  Code = "CONFIG_INVALID"
+	ErrCodeConfigNotFound Code = "CONFIG_NOT_FOUND"
 
 	// AGS integration errors
-	ErrCodeRewardGrantFailed = "REWARD_GRANT_FAILED"
-	ErrCodeAuthFailed        = "AUTH_FAILED"
+	ErrCodeRewardGrantFailed Code = "REWARD_GRANT_FAILED"
+	ErrCodeAuthFailed        Code = "AUTH_FAILED"
 
 	// Validation errors
-	ErrCodeValidationFailed = "VALIDATION_FAILED"
-	ErrCodeInvalidInput     = "INVALID_INPUT"
+	ErrCodeValidationFailed Code = "VALIDATION_FAILED"
+	ErrCodeInvalidInput     Code = "INVALID_INPUT"
+	ErrCodeInvalidCursor    Code = "INVALID_CURSOR"
+	ErrCodeInvalidIncrement Code = "INVALID_INCREMENT"
+	ErrCodeProgressOverflow Code = "PROGRESS_OVERFLOW"
+
+	// Operation errors
+	ErrCodeOperationNotAllowed Code = "OPERATION_NOT_ALLOWED"
+	ErrCodeShuttingDown        Code = "SHUTTING_DOWN"
+	ErrCodeRateLimited         Code = "RATE_LIMITED"
 
 	// M4: Goal selection errors
-	ErrCodeInsufficientGoals = "INSUFFICIENT_GOALS"
+	ErrCodeInsufficientGoals Code = "INSUFFICIENT_GOALS"
 )
 
 // ChallengeError represents an error in the challenge service.
 type ChallengeError struct {
-	Code    string
+	Code    Code
 	Message string
 	Err     error
 }
@@ -50,7 +73,7 @@ func (e *ChallengeError) Unwrap() error {
 }
 
 // NewChallengeError creates a new ChallengeError.
-func NewChallengeError(code, message string, err error) *ChallengeError {
+func NewChallengeError(code Code, message string, err error) *ChallengeError {
 	return &ChallengeError{
 		Code:    code,
 		Message: message,
@@ -69,6 +92,16 @@ func ErrGoalNotFound(goalID string) *ChallengeError {
 	}
 }
 
+// ErrProgressNotFound returns an error when a user has no progress row for a goal that
+// the caller expected to exist (see GoalRepository.GetProgressRequired).
+func ErrProgressNotFound(userID, goalID string) *ChallengeError {
+	return &ChallengeError{
+		Code:    ErrCodeProgressNotFound,
+		Message: fmt.Sprintf("progress not found: user %s, goal %s", userID, goalID),
+		Err:     nil,
+	}
+}
+
 // ErrChallengeNotFound returns an error when a challenge is not found.
 func ErrChallengeNotFound(challengeID string) *ChallengeError {
 	return &ChallengeError{
@@ -96,8 +129,64 @@ func ErrGoalNotCompleted(goalID string) *ChallengeError {
 	}
 }
 
+// ErrInvalidStatus returns an error when a goal status is not one the operation accepts.
+func ErrInvalidStatus(status string) *ChallengeError {
+	return &ChallengeError{
+		Code:    ErrCodeInvalidStatus,
+		Message: fmt.Sprintf("invalid goal status: %s", status),
+		Err:     nil,
+	}
+}
+
+// ErrClaimWindowExpired returns an error when a completed goal's claim deadline has
+// passed (or the goal was forfeited), so its reward can no longer be claimed.
+func ErrClaimWindowExpired(goalID string) *ChallengeError {
+	return &ChallengeError{
+		Code:    ErrCodeClaimWindowExpired,
+		Message: fmt.Sprintf("claim window expired: %s", goalID),
+		Err:     nil,
+	}
+}
+
+// ErrNotAllClaimable returns an error when an all-or-nothing claim is rejected because
+// some goals cannot be claimed. Each offender is described as "goalID: reason".
+func ErrNotAllClaimable(offenders []string) *ChallengeError {
+	return &ChallengeError{
+		Code:    ErrCodeNotAllClaimable,
+		Message: fmt.Sprintf("not all goals are claimable (%d rejected): %s", len(offenders), strings.Join(offenders, "; ")),
+		Err:     nil,
+	}
+}
+
+// ErrClaimInProgress returns an error when a goal is already reserved by another claim.
+func ErrClaimInProgress(goalID string) *ChallengeError {
+	return &ChallengeError{
+		Code:    ErrCodeClaimInProgress,
+		Message: fmt.Sprintf("claim already in progress: %s", goalID),
+		Err:     nil,
+	}
+}
+
+// ErrClaimNotReserved returns an error when confirming or cancelling a claim for a goal
+// that holds no claim reservation (never reserved, already finalized, released, or, for
+// a confirm, lapsed).
+func ErrClaimNotReserved(goalID string) *ChallengeError {
+	return &ChallengeError{
+		Code:    ErrCodeClaimNotReserved,
+		Message: fmt.Sprintf("no claim reservation: %s", goalID),
+		Err:     nil,
+	}
+}
+
 // ErrDatabaseError wraps database errors.
+// Violations of known constraints get their own code instead of ErrCodeDatabaseError:
+// check_progress_non_negative (ErrCodeNegativeProgress), check_claimed_implies_completed
+// (ErrCodeClaimWithoutCompletion), check_status (ErrCodeInvalidStatus) and the
+// user_goal_progress primary key (ErrCodeDuplicateKey).
 func ErrDatabaseError(operation string, err error) *ChallengeError {
+	if ce := constraintError(operation, err); ce != nil {
+		return ce
+	}
 	return &ChallengeError{
 		Code:    ErrCodeDatabaseError,
 		Message: fmt.Sprintf("database error during %s", operation),
@@ -105,6 +194,15 @@ func ErrDatabaseError(operation string, err error) *ChallengeError {
 	}
 }
 
+// ErrTransactionFailed wraps a failure to begin, commit or roll back a transaction.
+func ErrTransactionFailed(operation string, err error) *ChallengeError {
+	return &ChallengeError{
+		Code:    ErrCodeTransactionFailed,
+		Message: fmt.Sprintf("transaction failed during %s", operation),
+		Err:     err,
+	}
+}
+
 // ErrConfigInvalid returns an error for invalid configuration.
 func ErrConfigInvalid(reason string) *ChallengeError {
 	return &ChallengeError{
@@ -114,6 +212,15 @@ func ErrConfigInvalid(reason string) *ChallengeError {
 	}
 }
 
+// ErrConfigNotFound returns an error when the configuration file cannot be read.
+func ErrConfigNotFound(path string, err error) *ChallengeError {
+	return &ChallengeError{
+		Code:    ErrCodeConfigNotFound,
+		Message: fmt.Sprintf("configuration not found: %s", path),
+		Err:     err,
+	}
+}
+
 // ErrRewardGrantFailed returns an error when reward grant fails.
 func ErrRewardGrantFailed(rewardType, rewardID string, err error) *ChallengeError {
 	return &ChallengeError{
@@ -123,6 +230,15 @@ func ErrRewardGrantFailed(rewardType, rewardID string, err error) *ChallengeErro
 	}
 }
 
+// ErrAuthFailed returns an error when authenticating with AGS fails.
+func ErrAuthFailed(reason string, err error) *ChallengeError {
+	return &ChallengeError{
+		Code:    ErrCodeAuthFailed,
+		Message: fmt.Sprintf("authentication failed: %s", reason),
+		Err:     err,
+	}
+}
+
 // ErrValidationFailed returns a validation error.
 func ErrValidationFailed(field, reason string) *ChallengeError {
 	return &ChallengeError{
@@ -132,6 +248,15 @@ func ErrValidationFailed(field, reason string) *ChallengeError {
 	}
 }
 
+// ErrInvalidInput returns an error when a request argument is malformed.
+func ErrInvalidInput(field, reason string) *ChallengeError {
+	return &ChallengeError{
+		Code:    ErrCodeInvalidInput,
+		Message: fmt.Sprintf("invalid input for %s: %s", field, reason),
+		Err:     nil,
+	}
+}
+
 // ErrInsufficientGoals returns an error when not enough goals are available for selection.
 func ErrInsufficientGoals(available, requested int) *ChallengeError {
 	return &ChallengeError{
@@ -140,3 +265,61 @@ func ErrInsufficientGoals(available, requested int) *ChallengeError {
 		Err:     nil,
 	}
 }
+
+// ErrInvalidCursor returns an error when a pagination cursor cannot be used for the requested page.
+func ErrInvalidCursor(reason string) *ChallengeError {
+	return &ChallengeError{
+		Code:    ErrCodeInvalidCursor,
+		Message: fmt.Sprintf("invalid pagination cursor: %s", reason),
+		Err:     nil,
+	}
+}
+
+// ErrInvalidIncrement returns an error when one or more progress increments are rejected
+// before reaching the database. Each entry describes one offending increment.
+func ErrInvalidIncrement(entries []string) *ChallengeError {
+	return &ChallengeError{
+		Code:    ErrCodeInvalidIncrement,
+		Message: fmt.Sprintf("invalid progress increment (%d rejected): %s", len(entries), strings.Join(entries, "; ")),
+		Err:     nil,
+	}
+}
+
+// ErrProgressOverflow returns an error when an increment was not applied because it would
+// push progress past the safe ceiling. Each entry names the offending user/goal.
+func ErrProgressOverflow(entries []string) *ChallengeError {
+	return &ChallengeError{
+		Code:    ErrCodeProgressOverflow,
+		Message: fmt.Sprintf("progress would exceed safe ceiling (%d rejected): %s", len(entries), strings.Join(entries, "; ")),
+		Err:     nil,
+	}
+}
+
+// ErrOperationNotAllowed returns an error when an operation is disabled by configuration.
+func ErrOperationNotAllowed(operation, reason string) *ChallengeError {
+	return &ChallengeError{
+		Code:    ErrCodeOperationNotAllowed,
+		Message: fmt.Sprintf("operation not allowed: %s: %s", operation, reason),
+		Err:     nil,
+	}
+}
+
+// ErrShuttingDown returns an error when new database work is rejected because the
+// connection pool is draining for shutdown.
+func ErrShuttingDown() *ChallengeError {
+	return &ChallengeError{
+		Code:    ErrCodeShuttingDown,
+		Message: "database is shutting down; no new work is accepted",
+		Err:     nil,
+	}
+}
+
+// ErrRateLimited returns an error when a repository write is denied by the embedding
+// service's limiter for a namespace. err is the limiter's reason.
+func ErrRateLimited(namespace, operation string, err error) *ChallengeError {
+	return &ChallengeError{
+		Code:    ErrCodeRateLimited,
+		Message: fmt.Sprintf("rate limited: %s in namespace '%s'", operation, namespace),
+		Err:     err,
+	}
+}
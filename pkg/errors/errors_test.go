@@ -2,8 +2,11 @@ package errors
 
 import (
 	"errors"
+	"fmt"
 	"strings"
 	"testing"
+
+	"github.com/lib/pq"
 )
 
 func TestChallengeError_Error(t *testing.T) {
@@ -69,6 +72,18 @@ func TestErrGoalNotFound(t *testing.T) {
 	}
 }
 
+func TestErrProgressNotFound(t *testing.T) {
+	err := ErrProgressNotFound("user-1", "goal-1")
+
+	if err.Code != ErrCodeProgressNotFound {
+		t.Errorf("Code = %v, want %v", err.Code, ErrCodeProgressNotFound)
+	}
+
+	if !strings.Contains(err.Message, "user-1") || !strings.Contains(err.Message, "goal-1") {
+		t.Errorf("Message should contain user and goal IDs, got %v", err.Message)
+	}
+}
+
 func TestErrChallengeNotFound(t *testing.T) {
 	challengeID := "test-challenge-456"
 	err := ErrChallengeNotFound(challengeID)
@@ -108,6 +123,36 @@ func TestErrGoalNotCompleted(t *testing.T) {
 	}
 }
 
+func TestErrClaimWindowExpired(t *testing.T) {
+	goalID := "expired-goal"
+	err := ErrClaimWindowExpired(goalID)
+
+	if err.Code != ErrCodeClaimWindowExpired {
+		t.Errorf("Code = %v, want %v", err.Code, ErrCodeClaimWindowExpired)
+	}
+
+	if !strings.Contains(err.Message, goalID) {
+		t.Errorf("Message should contain goal ID %v, got %v", goalID, err.Message)
+	}
+}
+
+func TestErrClaimReservation(t *testing.T) {
+	goalID := "reserved-goal"
+
+	for _, err := range []*ChallengeError{ErrClaimInProgress(goalID), ErrClaimNotReserved(goalID)} {
+		if !strings.Contains(err.Message, goalID) {
+			t.Errorf("Message should contain goal ID %v, got %v", goalID, err.Message)
+		}
+	}
+
+	if code := ErrClaimInProgress(goalID).Code; code != ErrCodeClaimInProgress {
+		t.Errorf("Code = %v, want %v", code, ErrCodeClaimInProgress)
+	}
+	if code := ErrClaimNotReserved(goalID).Code; code != ErrCodeClaimNotReserved {
+		t.Errorf("Code = %v, want %v", code, ErrCodeClaimNotReserved)
+	}
+}
+
 func TestErrDatabaseError(t *testing.T) {
 	operation := "batch upsert"
 	originalErr := errors.New("connection lost")
@@ -180,8 +225,123 @@ func TestErrValidationFailed(t *testing.T) {
 	}
 }
 
+func TestErrInvalidCursor(t *testing.T) {
+	err := ErrInvalidCursor("ordering mismatch")
+
+	if err.Code != ErrCodeInvalidCursor {
+		t.Errorf("Code = %v, want %v", err.Code, ErrCodeInvalidCursor)
+	}
+
+	if !strings.Contains(err.Message, "ordering mismatch") {
+		t.Errorf("Message should contain reason, got %v", err.Message)
+	}
+}
+
+func TestErrInvalidIncrement(t *testing.T) {
+	entries := []string{"user1/goal1: target value must be positive", "user2/goal2: delta exceeds cap"}
+	err := ErrInvalidIncrement(entries)
+
+	if err.Code != ErrCodeInvalidIncrement {
+		t.Errorf("Code = %v, want %v", err.Code, ErrCodeInvalidIncrement)
+	}
+
+	for _, entry := range entries {
+		if !strings.Contains(err.Message, entry) {
+			t.Errorf("Message should contain entry %q, got %v", entry, err.Message)
+		}
+	}
+}
+
+func TestErrNotAllClaimable(t *testing.T) {
+	err := ErrNotAllClaimable([]string{"goal-2: not completed (status in_progress)", "goal-3: no progress"})
+
+	if err.Code != ErrCodeNotAllClaimable {
+		t.Errorf("Code = %v, want %v", err.Code, ErrCodeNotAllClaimable)
+	}
+	for _, want := range []string{"2 rejected", "goal-2: not completed", "goal-3: no progress"} {
+		if !strings.Contains(err.Message, want) {
+			t.Errorf("Message should contain %q, got %v", want, err.Message)
+		}
+	}
+}
+
+func TestErrProgressOverflow(t *testing.T) {
+	entries := []string{"user1/goal1: progress 2146483640 + delta 100 exceeds ceiling 2146483647"}
+	err := ErrProgressOverflow(entries)
+
+	if err.Code != ErrCodeProgressOverflow {
+		t.Errorf("Code = %v, want %v", err.Code, ErrCodeProgressOverflow)
+	}
+	if !strings.Contains(err.Message, "user1/goal1") {
+		t.Errorf("Message should name the goal, got %v", err.Message)
+	}
+}
+
+func TestErrOperationNotAllowed(t *testing.T) {
+	err := ErrOperationNotAllowed("delete challenge progress", "bulk delete is disabled")
+
+	if err.Code != ErrCodeOperationNotAllowed {
+		t.Errorf("Code = %v, want %v", err.Code, ErrCodeOperationNotAllowed)
+	}
+
+	if !strings.Contains(err.Message, "delete challenge progress") {
+		t.Errorf("Message should contain operation, got %v", err.Message)
+	}
+}
+
+func TestErrShuttingDown(t *testing.T) {
+	err := ErrShuttingDown()
+
+	if err.Code != ErrCodeShuttingDown {
+		t.Errorf("Code = %v, want %v", err.Code, ErrCodeShuttingDown)
+	}
+}
+
+func TestErrRateLimited(t *testing.T) {
+	reason := errors.New("bucket empty")
+	err := ErrRateLimited("game-a", "batch increment progress", reason)
+
+	if err.Code != ErrCodeRateLimited {
+		t.Errorf("Code = %v, want %v", err.Code, ErrCodeRateLimited)
+	}
+	if !strings.Contains(err.Message, "game-a") {
+		t.Errorf("Message should contain namespace, got %v", err.Message)
+	}
+	if !errors.Is(err, reason) {
+		t.Errorf("Expected error to wrap the limiter's reason")
+	}
+}
+
+func TestCodeConstructors(t *testing.T) {
+	cause := errors.New("cause")
+
+	tests := []struct {
+		name    string
+		err     *ChallengeError
+		code    Code
+		message string
+	}{
+		{"invalid status", ErrInvalidStatus("paused"), ErrCodeInvalidStatus, "invalid goal status: paused"},
+		{"transaction failed", ErrTransactionFailed("commit", cause), ErrCodeTransactionFailed, "transaction failed during commit"},
+		{"config not found", ErrConfigNotFound("/etc/challenges.json", cause), ErrCodeConfigNotFound, "configuration not found: /etc/challenges.json"},
+		{"auth failed", ErrAuthFailed("token expired", cause), ErrCodeAuthFailed, "authentication failed: token expired"},
+		{"invalid input", ErrInvalidInput("userId", "cannot be empty"), ErrCodeInvalidInput, "invalid input for userId: cannot be empty"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.err.Code != tt.code || tt.err.Message != tt.message {
+				t.Errorf("got %s %q, want %s %q", tt.err.Code, tt.err.Message, tt.code, tt.message)
+			}
+			if tt.err.Err != nil && !errors.Is(tt.err, cause) {
+				t.Errorf("error should wrap its cause")
+			}
+		})
+	}
+}
+
 func TestNewChallengeError(t *testing.T) {
-	code := "TEST_CODE"
+	code := Code("TEST_CODE")
 	message := "test message"
 	originalErr := errors.New("wrapped error")
 
@@ -223,3 +383,75 @@ func TestErrorWrapping(t *testing.T) {
 		t.Error("Should be able to unwrap to original error")
 	}
 }
+
+func TestErrDatabaseError_Constraints(t *testing.T) {
+	tests := []struct {
+		name       string
+		pqErr      *pq.Error
+		wantCode   Code
+		wantInMsgs []string
+	}{
+		{
+			name:       "negative progress",
+			pqErr:      &pq.Error{Code: "23514", Constraint: "check_progress_non_negative", Table: "user_goal_progress"},
+			wantCode:   ErrCodeNegativeProgress,
+			wantInMsgs: []string{"check_progress_non_negative", "user_goal_progress"},
+		},
+		{
+			name:       "claimed without completion",
+			pqErr:      &pq.Error{Code: "23514", Constraint: "check_claimed_implies_completed", Table: "user_goal_progress"},
+			wantCode:   ErrCodeClaimWithoutCompletion,
+			wantInMsgs: []string{"check_claimed_implies_completed"},
+		},
+		{
+			name:       "invalid status",
+			pqErr:      &pq.Error{Code: "23514", Constraint: "check_status", Table: "user_goal_progress"},
+			wantCode:   ErrCodeInvalidStatus,
+			wantInMsgs: []string{"check_status"},
+		},
+		{
+			name: "duplicate key",
+			pqErr: &pq.Error{
+				Code:       "23505",
+				Constraint: "user_goal_progress_pkey",
+				Table:      "user_goal_progress",
+				Detail:     "Key (user_id, goal_id)=(u1, kills) already exists.",
+			},
+			wantCode:   ErrCodeDuplicateKey,
+			wantInMsgs: []string{"user_goal_progress_pkey", "(user_id, goal_id)=(u1, kills)"},
+		},
+		{
+			name:       "unknown constraint",
+			pqErr:      &pq.Error{Code: "23514", Constraint: "check_something_else", Table: "user_goal_progress"},
+			wantCode:   ErrCodeDatabaseError,
+			wantInMsgs: []string{"upsert progress"},
+		},
+		{
+			name:       "no constraint",
+			pqErr:      &pq.Error{Code: "57014", Message: "canceling statement due to statement timeout"},
+			wantCode:   ErrCodeDatabaseError,
+			wantInMsgs: []string{"upsert progress"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Wrap once more, as drivers and callers often do
+			wrapped := fmt.Errorf("exec: %w", tt.pqErr)
+			err := ErrDatabaseError("upsert progress", wrapped)
+
+			if err.Code != tt.wantCode {
+				t.Errorf("Code = %v, want %v", err.Code, tt.wantCode)
+			}
+			for _, want := range append(tt.wantInMsgs, "upsert progress") {
+				if !strings.Contains(err.Message, want) {
+					t.Errorf("Message %q should contain %q", err.Message, want)
+				}
+			}
+			var pqErr *pq.Error
+			if !errors.As(err, &pqErr) || pqErr != tt.pqErr {
+				t.Errorf("errors.As should find the original pq.Error in %v", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,90 @@
+package grpc
+
+import (
+	"time"
+
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"github.com/AccelByte/extend-challenge-common/pkg/domain"
+	"github.com/AccelByte/extend-challenge-common/pkg/grpc/progresspb"
+	"github.com/AccelByte/extend-challenge-common/pkg/repository"
+)
+
+// toGoalProgress converts a progress row to its message. Returns nil for a nil row.
+func toGoalProgress(p *domain.UserGoalProgress) *progresspb.GoalProgress {
+	if p == nil {
+		return nil
+	}
+	return &progresspb.GoalProgress{
+		UserId:      p.UserID,
+		GoalId:      p.GoalID,
+		ChallengeId: p.ChallengeID,
+		Namespace:   p.Namespace,
+		Progress:    p.Progress,
+		Status:      string(p.Status),
+		IsActive:    p.IsActive,
+		Attempts:    int32(p.Attempts),
+		Completions: int32(p.Completions),
+		CompletedAt: toTimestamp(p.CompletedAt),
+		ClaimedAt:   toTimestamp(p.ClaimedAt),
+		ForfeitedAt: toTimestamp(p.ForfeitedAt),
+		AssignedAt:  toTimestamp(p.AssignedAt),
+		ExpiresAt:   toTimestamp(p.ExpiresAt),
+		CreatedAt:   timestamppb.New(p.CreatedAt),
+		UpdatedAt:   timestamppb.New(p.UpdatedAt),
+	}
+}
+
+// toGoalProgressList converts a page of progress rows.
+func toGoalProgressList(rows []*domain.UserGoalProgress) []*progresspb.GoalProgress {
+	out := make([]*progresspb.GoalProgress, 0, len(rows))
+	for _, row := range rows {
+		out = append(out, toGoalProgress(row))
+	}
+	return out
+}
+
+// toTimestamp converts an optional time. Returns nil (unset) for a nil time.
+func toTimestamp(t *time.Time) *timestamppb.Timestamp {
+	if t == nil {
+		return nil
+	}
+	return timestamppb.New(*t)
+}
+
+// fromTimestamp converts an optional timestamp. Returns nil for an unset one.
+func fromTimestamp(ts *timestamppb.Timestamp) *time.Time {
+	if ts == nil {
+		return nil
+	}
+	t := ts.AsTime()
+	return &t
+}
+
+// fromInt32 converts an optional int32 field.
+func fromInt32(v *int32) *int {
+	if v == nil {
+		return nil
+	}
+	n := int(*v)
+	return &n
+}
+
+// toProgressQuery converts a search request to its repository filter. Validation is left
+// to the repository, which rejects contradictory filters with ErrValidationFailed.
+func toProgressQuery(req *progresspb.SearchProgressRequest) repository.ProgressQuery {
+	statuses := make([]domain.GoalStatus, 0, len(req.GetStatuses()))
+	for _, s := range req.GetStatuses() {
+		statuses = append(statuses, domain.GoalStatus(s))
+	}
+	return repository.ProgressQuery{
+		Namespace:     req.GetNamespace(),
+		Statuses:      statuses,
+		MinProgress:   fromInt32(req.MinProgress),
+		MaxProgress:   fromInt32(req.MaxProgress),
+		UpdatedAfter:  fromTimestamp(req.GetUpdatedAfter()),
+		UpdatedBefore: fromTimestamp(req.GetUpdatedBefore()),
+		IsActive:      req.IsActive,
+		GoalIDPrefix:  req.GetGoalIdPrefix(),
+	}
+}
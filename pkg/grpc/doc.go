@@ -0,0 +1,17 @@
+// Package grpc serves goal progress over gRPC (see progress.proto) for tools that should
+// not link the repository package directly, such as the admin console and the analytics
+// sampler.
+//
+// The generated message and service code is in progresspb. NewServer adapts a
+// repository.GoalRepository and a cache.GoalCache to progresspb.ProgressServiceServer:
+//
+//	srv := grpc.NewServer(repo, goalCache, grpc.WithTimeout(2*time.Second))
+//	progresspb.RegisterProgressServiceServer(grpcServer, srv)
+//
+// Repository errors are returned as gRPC statuses whose code is the ChallengeError code's
+// errors.Code.GRPCCode(), so clients can branch on status codes (e.g. ALREADY_EXISTS for an
+// already claimed goal) and read the error code string from the status message.
+package grpc
+
+// Regenerating needs buf, protoc-gen-go and protoc-gen-go-grpc on PATH.
+//go:generate buf generate .
@@ -0,0 +1,277 @@
+package grpc
+
+import (
+	"context"
+	stderrors "errors"
+	"log/slog"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/AccelByte/extend-challenge-common/pkg/cache"
+	"github.com/AccelByte/extend-challenge-common/pkg/domain"
+	"github.com/AccelByte/extend-challenge-common/pkg/errors"
+	"github.com/AccelByte/extend-challenge-common/pkg/grpc/progresspb"
+	"github.com/AccelByte/extend-challenge-common/pkg/repository"
+)
+
+// DefaultTimeout is the default upper bound on the time one RPC may spend in the
+// repository (see WithTimeout).
+const DefaultTimeout = 5 * time.Second
+
+// ServerMetrics receives one event per handled RPC (see WithMetrics). Calls are
+// synchronous, so implementations should be cheap.
+type ServerMetrics interface {
+	// RPCHandled is called when an RPC returns. method is the RPC name (e.g.
+	// "MarkAsClaimed"), code the status code returned to the client (codes.OK on success)
+	// and elapsed the time spent in the handler.
+	RPCHandled(method string, code codes.Code, elapsed time.Duration)
+}
+
+// ServerOption configures a Server.
+type ServerOption func(*Server)
+
+// WithTimeout sets the deadline applied to each RPC. A client deadline that ends sooner
+// is kept; one that ends later, or none, is shortened to timeout. Defaults to
+// DefaultTimeout; 0 or less leaves the client's deadline as the only bound.
+func WithTimeout(timeout time.Duration) ServerOption {
+	return func(s *Server) {
+		s.timeout = timeout
+	}
+}
+
+// WithMetrics sets the hook that counts handled RPCs by status code and latency.
+func WithMetrics(metrics ServerMetrics) ServerOption {
+	return func(s *Server) {
+		s.metrics = metrics
+	}
+}
+
+// WithLogger sets the logger for RPCs that fail with an INTERNAL or UNKNOWN status.
+// Defaults to slog.Default().
+func WithLogger(logger *slog.Logger) ServerOption {
+	return func(s *Server) {
+		s.logger = logger
+	}
+}
+
+// progressSearcher is the part of repository.ProgressFeedRepository SearchProgress needs.
+type progressSearcher interface {
+	SearchProgress(ctx context.Context, q repository.ProgressQuery, limit int, cursor string) ([]*domain.UserGoalProgress, string, error)
+}
+
+// Server implements progresspb.ProgressServiceServer on top of a repository and a goal
+// cache. It is safe for concurrent use when its dependencies are.
+type Server struct {
+	progresspb.UnimplementedProgressServiceServer
+
+	repo    repository.GoalRepository
+	goals   cache.GoalCache
+	timeout time.Duration
+	metrics ServerMetrics
+	logger  *slog.Logger
+}
+
+// NewServer creates a Server reading progress from repo and goal definitions from goals.
+// Register it with progresspb.RegisterProgressServiceServer.
+//
+// SearchProgress needs a repository that also implements
+// repository.ProgressFeedRepository, such as *repository.PostgresGoalRepository; with any
+// other repository it returns UNIMPLEMENTED.
+func NewServer(repo repository.GoalRepository, goals cache.GoalCache, opts ...ServerOption) *Server {
+	s := &Server{
+		repo:    repo,
+		goals:   goals,
+		timeout: DefaultTimeout,
+		logger:  slog.Default(),
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
+}
+
+// handle runs fn under the RPC deadline, converts its error to a status and reports the
+// outcome to the metrics hook.
+func handle[T any](ctx context.Context, s *Server, method string, fn func(ctx context.Context) (T, error)) (T, error) {
+	start := time.Now()
+	if s.timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, s.timeout)
+		defer cancel()
+	}
+
+	resp, err := fn(ctx)
+	err = toStatus(err)
+
+	code := status.Code(err)
+	if code == codes.Internal || code == codes.Unknown {
+		s.logger.Error("progress RPC failed", "method", method, "error", err)
+	}
+	if s.metrics != nil {
+		s.metrics.RPCHandled(method, code, time.Since(start))
+	}
+	return resp, err
+}
+
+// requireIDs rejects an empty user or goal/challenge ID before any repository call.
+func requireIDs(fields ...string) error {
+	for i := 0; i+1 < len(fields); i += 2 {
+		if fields[i+1] == "" {
+			return errors.ErrInvalidInput(fields[i], "is required")
+		}
+	}
+	return nil
+}
+
+// GetProgress returns one user's progress on one goal.
+func (s *Server) GetProgress(ctx context.Context, req *progresspb.GetProgressRequest) (*progresspb.GoalProgress, error) {
+	return handle(ctx, s, "GetProgress", func(ctx context.Context) (*progresspb.GoalProgress, error) {
+		if err := requireIDs("user_id", req.GetUserId(), "goal_id", req.GetGoalId()); err != nil {
+			return nil, err
+		}
+
+		progress, err := s.repo.GetProgressRequired(ctx, req.GetUserId(), req.GetGoalId())
+		if err != nil {
+			return nil, err
+		}
+		return toGoalProgress(progress), nil
+	})
+}
+
+// GetUserProgress returns a page of a user's progress, optionally within one challenge.
+func (s *Server) GetUserProgress(ctx context.Context, req *progresspb.GetUserProgressRequest) (*progresspb.GetUserProgressResponse, error) {
+	return handle(ctx, s, "GetUserProgress", func(ctx context.Context) (*progresspb.GetUserProgressResponse, error) {
+		if err := requireIDs("user_id", req.GetUserId()); err != nil {
+			return nil, err
+		}
+
+		opts := repository.PageOptions{
+			Limit:      int(req.GetLimit()),
+			Cursor:     req.GetCursor(),
+			OrderBy:    repository.ProgressOrder(req.GetOrderBy()),
+			ActiveOnly: req.GetActiveOnly(),
+		}
+		var (
+			rows []*domain.UserGoalProgress
+			next string
+			err  error
+		)
+		if req.GetChallengeId() != "" {
+			rows, next, err = s.repo.GetChallengeProgressPage(ctx, req.GetUserId(), req.GetChallengeId(), opts)
+		} else {
+			rows, next, err = s.repo.GetUserProgressPage(ctx, req.GetUserId(), opts)
+		}
+		if err != nil {
+			return nil, err
+		}
+		return &progresspb.GetUserProgressResponse{Progress: toGoalProgressList(rows), NextCursor: next}, nil
+	})
+}
+
+// GetChallengeSummary returns a configured challenge with the user's progress on each goal.
+func (s *Server) GetChallengeSummary(ctx context.Context, req *progresspb.GetChallengeSummaryRequest) (*progresspb.ChallengeSummary, error) {
+	return handle(ctx, s, "GetChallengeSummary", func(ctx context.Context) (*progresspb.ChallengeSummary, error) {
+		if err := requireIDs("user_id", req.GetUserId(), "challenge_id", req.GetChallengeId()); err != nil {
+			return nil, err
+		}
+
+		challenge := s.goals.GetChallengeByChallengeID(req.GetChallengeId())
+		if challenge == nil {
+			return nil, errors.ErrChallengeNotFound(req.GetChallengeId())
+		}
+
+		rows, err := s.repo.GetChallengeProgress(ctx, req.GetUserId(), challenge.ID, false)
+		if err != nil {
+			return nil, err
+		}
+		byGoal := make(map[string]*domain.UserGoalProgress, len(rows))
+		for _, row := range rows {
+			byGoal[row.GoalID] = row
+		}
+
+		summary := &progresspb.ChallengeSummary{
+			ChallengeId: challenge.ID,
+			Name:        challenge.Name,
+			NameKey:     challenge.NameKey,
+			Goals:       make([]*progresspb.GoalSummary, 0, len(challenge.Goals)),
+		}
+		for _, goal := range challenge.Goals {
+			row := byGoal[goal.ID]
+			summary.Goals = append(summary.Goals, &progresspb.GoalSummary{
+				GoalId:      goal.ID,
+				Name:        goal.Name,
+				NameKey:     goal.NameKey,
+				TargetValue: int32(goal.Requirement.TargetValue),
+				Progress:    toGoalProgress(row),
+			})
+			if row == nil {
+				continue
+			}
+			if row.IsCompleted() {
+				summary.CompletedGoals++
+			}
+			if row.IsClaimed() {
+				summary.ClaimedGoals++
+			}
+		}
+		return summary, nil
+	})
+}
+
+// MarkAsClaimed claims a completed goal with the goal's configured claim deadline.
+func (s *Server) MarkAsClaimed(ctx context.Context, req *progresspb.MarkAsClaimedRequest) (*progresspb.MarkAsClaimedResponse, error) {
+	return handle(ctx, s, "MarkAsClaimed", func(ctx context.Context) (*progresspb.MarkAsClaimedResponse, error) {
+		if err := requireIDs("user_id", req.GetUserId(), "goal_id", req.GetGoalId()); err != nil {
+			return nil, err
+		}
+
+		goal := s.goals.GetGoalByID(req.GetGoalId())
+		if goal == nil {
+			return nil, errors.ErrGoalNotFound(req.GetGoalId())
+		}
+
+		if err := s.repo.MarkAsClaimedWithDeadline(ctx, req.GetUserId(), goal.ID, goal.ClaimDeadline.Std()); err != nil {
+			return nil, s.claimFailure(ctx, req.GetUserId(), goal.ID, err)
+		}
+		return &progresspb.MarkAsClaimedResponse{}, nil
+	})
+}
+
+// claimFailure narrows the ErrGoalNotCompleted the repository returns for any row it
+// could not claim (missing, not completed or already claimed) by reading the row, so
+// clients get NOT_FOUND or ALREADY_EXISTS instead of FAILED_PRECONDITION for all three.
+func (s *Server) claimFailure(ctx context.Context, userID, goalID string, err error) error {
+	var ce *errors.ChallengeError
+	if !stderrors.As(err, &ce) || ce.Code != errors.ErrCodeGoalNotCompleted {
+		return err
+	}
+
+	row, readErr := s.repo.GetProgress(ctx, userID, goalID)
+	switch {
+	case readErr != nil:
+		return readErr
+	case row == nil:
+		return errors.ErrProgressNotFound(userID, goalID)
+	case row.IsClaimed():
+		return errors.ErrGoalAlreadyClaimed(goalID)
+	}
+	return err
+}
+
+// SearchProgress returns a page of rows matching a filter across users.
+func (s *Server) SearchProgress(ctx context.Context, req *progresspb.SearchProgressRequest) (*progresspb.SearchProgressResponse, error) {
+	return handle(ctx, s, "SearchProgress", func(ctx context.Context) (*progresspb.SearchProgressResponse, error) {
+		searcher, ok := s.repo.(progressSearcher)
+		if !ok {
+			return nil, status.Error(codes.Unimplemented, "repository does not support SearchProgress")
+		}
+
+		rows, next, err := searcher.SearchProgress(ctx, toProgressQuery(req), int(req.GetLimit()), req.GetCursor())
+		if err != nil {
+			return nil, err
+		}
+		return &progresspb.SearchProgressResponse{Progress: toGoalProgressList(rows), NextCursor: next}, nil
+	})
+}
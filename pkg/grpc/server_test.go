@@ -0,0 +1,465 @@
+package grpc
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"net"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	grpclib "google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+
+	"github.com/AccelByte/extend-challenge-common/pkg/cache"
+	"github.com/AccelByte/extend-challenge-common/pkg/config"
+	"github.com/AccelByte/extend-challenge-common/pkg/domain"
+	"github.com/AccelByte/extend-challenge-common/pkg/errors"
+	"github.com/AccelByte/extend-challenge-common/pkg/grpc/progresspb"
+	"github.com/AccelByte/extend-challenge-common/pkg/repository"
+)
+
+// memRepository is an in-memory repository holding progress rows in insertion order.
+// Methods the server does not use fall through to the nil embedded interface and panic.
+type memRepository struct {
+	repository.GoalRepository
+
+	mu        sync.Mutex
+	rows      []*domain.UserGoalProgress
+	deadlines map[string]time.Duration // goal -> claim deadline passed to the last claim
+	query     repository.ProgressQuery // Last SearchProgress filter
+	block     bool                     // Reads wait for the context to end
+	sawCtx    context.Context          // Context of the last blocked read
+}
+
+func newMemRepository(rows ...*domain.UserGoalProgress) *memRepository {
+	return &memRepository{rows: rows, deadlines: make(map[string]time.Duration)}
+}
+
+func (m *memRepository) find(userID, goalID string) *domain.UserGoalProgress {
+	for _, row := range m.rows {
+		if row.UserID == userID && row.GoalID == goalID {
+			return row
+		}
+	}
+	return nil
+}
+
+// page returns the rows matching keep, Limit at a time, with the offset as the cursor.
+func (m *memRepository) page(opts repository.PageOptions, keep func(*domain.UserGoalProgress) bool) ([]*domain.UserGoalProgress, string, error) {
+	var matched []*domain.UserGoalProgress
+	for _, row := range m.rows {
+		if keep(row) && (!opts.ActiveOnly || row.IsActive) {
+			matched = append(matched, row)
+		}
+	}
+
+	offset := 0
+	if opts.Cursor != "" {
+		var err error
+		if offset, err = strconv.Atoi(opts.Cursor); err != nil {
+			return nil, "", errors.ErrInvalidCursor("not an offset")
+		}
+	}
+	limit := opts.Limit
+	if limit == 0 {
+		limit = repository.DefaultPageLimit
+	}
+
+	end := min(offset+limit, len(matched))
+	next := ""
+	if end < len(matched) {
+		next = strconv.Itoa(end)
+	}
+	return matched[offset:end], next, nil
+}
+
+func (m *memRepository) GetProgress(_ context.Context, userID, goalID string) (*domain.UserGoalProgress, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.find(userID, goalID), nil
+}
+
+func (m *memRepository) GetProgressRequired(ctx context.Context, userID, goalID string) (*domain.UserGoalProgress, error) {
+	m.mu.Lock()
+	block := m.block
+	m.mu.Unlock()
+	if block {
+		m.mu.Lock()
+		m.sawCtx = ctx
+		m.mu.Unlock()
+		<-ctx.Done()
+		return nil, errors.ErrDatabaseError("get progress", ctx.Err())
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if row := m.find(userID, goalID); row != nil {
+		return row, nil
+	}
+	return nil, errors.ErrProgressNotFound(userID, goalID)
+}
+
+func (m *memRepository) GetUserProgressPage(_ context.Context, userID string, opts repository.PageOptions) ([]*domain.UserGoalProgress, string, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.page(opts, func(row *domain.UserGoalProgress) bool { return row.UserID == userID })
+}
+
+func (m *memRepository) GetChallengeProgressPage(_ context.Context, userID, challengeID string, opts repository.PageOptions) ([]*domain.UserGoalProgress, string, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.page(opts, func(row *domain.UserGoalProgress) bool {
+		return row.UserID == userID && row.ChallengeID == challengeID
+	})
+}
+
+func (m *memRepository) GetChallengeProgress(_ context.Context, userID, challengeID string, _ bool) ([]*domain.UserGoalProgress, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	rows, _, err := m.page(repository.PageOptions{Limit: len(m.rows) + 1}, func(row *domain.UserGoalProgress) bool {
+		return row.UserID == userID && row.ChallengeID == challengeID
+	})
+	return rows, err
+}
+
+func (m *memRepository) MarkAsClaimedWithDeadline(_ context.Context, userID, goalID string, claimDeadline time.Duration) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.deadlines[goalID] = claimDeadline
+
+	// Like the Postgres repository, every unclaimable row fails with ErrGoalNotCompleted
+	row := m.find(userID, goalID)
+	if row == nil || row.Status != domain.GoalStatusCompleted {
+		return errors.ErrGoalNotCompleted(goalID)
+	}
+	row.Status = domain.GoalStatusClaimed
+	return nil
+}
+
+func (m *memRepository) SearchProgress(_ context.Context, q repository.ProgressQuery, limit int, cursor string) ([]*domain.UserGoalProgress, string, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.query = q
+	return m.page(repository.PageOptions{Limit: limit, Cursor: cursor}, func(row *domain.UserGoalProgress) bool {
+		if len(q.Statuses) == 0 {
+			return true
+		}
+		for _, s := range q.Statuses {
+			if row.Status == s {
+				return true
+			}
+		}
+		return false
+	})
+}
+
+// fakeServerMetrics records RPCHandled calls.
+type fakeServerMetrics struct {
+	mu    sync.Mutex
+	calls []string
+}
+
+func (f *fakeServerMetrics) RPCHandled(method string, code codes.Code, elapsed time.Duration) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.calls = append(f.calls, method+" "+code.String())
+}
+
+func testGoalCache() cache.GoalCache {
+	cfg := &config.Config{Challenges: []*domain.Challenge{{
+		ID: "challenge-1", Name: "Season 1", NameKey: "season_1",
+		Goals: []*domain.Goal{
+			{
+				ID: "kills", Name: "Kills", ChallengeID: "challenge-1", Type: domain.GoalTypeIncrement, EventSource: domain.EventSourceStatistic,
+				Requirement:   domain.Requirement{StatCode: "kills", Operator: ">=", TargetValue: 10},
+				ClaimDeadline: domain.Duration(time.Hour),
+			},
+			{
+				ID: "wins", Name: "Wins", ChallengeID: "challenge-1", Type: domain.GoalTypeIncrement, EventSource: domain.EventSourceStatistic,
+				Requirement: domain.Requirement{StatCode: "wins", Operator: ">=", TargetValue: 3},
+			},
+			{
+				ID: "logins", Name: "Logins", ChallengeID: "challenge-1", Type: domain.GoalTypeIncrement, EventSource: domain.EventSourceLogin,
+				Requirement: domain.Requirement{Operator: ">=", TargetValue: 5},
+			},
+		},
+	}}}
+	return cache.NewInMemoryGoalCache(cfg, "", slog.New(slog.NewTextHandler(io.Discard, nil)))
+}
+
+func testRows() []*domain.UserGoalProgress {
+	created := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	completed := created.Add(time.Hour)
+	return []*domain.UserGoalProgress{
+		{UserID: "user-1", GoalID: "kills", ChallengeID: "challenge-1", Namespace: "ns", Progress: 10, Status: domain.GoalStatusCompleted, IsActive: true, CompletedAt: &completed, CreatedAt: created, UpdatedAt: completed},
+		{UserID: "user-1", GoalID: "wins", ChallengeID: "challenge-1", Namespace: "ns", Progress: 3, Status: domain.GoalStatusClaimed, IsActive: true, CreatedAt: created, UpdatedAt: created},
+		{UserID: "user-1", GoalID: "other", ChallengeID: "challenge-2", Namespace: "ns", Progress: 1, Status: domain.GoalStatusInProgress, CreatedAt: created, UpdatedAt: created},
+		{UserID: "user-2", GoalID: "kills", ChallengeID: "challenge-1", Namespace: "ns", Progress: 4, Status: domain.GoalStatusInProgress, IsActive: true, CreatedAt: created, UpdatedAt: created},
+	}
+}
+
+// newTestClient serves a Server over an in-memory connection and returns a client for it.
+func newTestClient(t *testing.T, repo repository.GoalRepository, opts ...ServerOption) progresspb.ProgressServiceClient {
+	t.Helper()
+
+	opts = append([]ServerOption{WithLogger(slog.New(slog.NewTextHandler(io.Discard, nil)))}, opts...)
+	listener := bufconn.Listen(1 << 20)
+	server := grpclib.NewServer()
+	progresspb.RegisterProgressServiceServer(server, NewServer(repo, testGoalCache(), opts...))
+	go func() { _ = server.Serve(listener) }()
+	t.Cleanup(server.Stop)
+
+	conn, err := grpclib.NewClient("passthrough:///bufnet",
+		grpclib.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) { return listener.DialContext(ctx) }),
+		grpclib.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		t.Fatalf("NewClient failed: %v", err)
+	}
+	t.Cleanup(func() { _ = conn.Close() })
+	return progresspb.NewProgressServiceClient(conn)
+}
+
+// assertStatus checks the status code of err and that its message starts with the
+// ChallengeError code, if one is given.
+func assertStatus(t *testing.T, err error, code codes.Code, errCode errors.Code) {
+	t.Helper()
+	st, _ := status.FromError(err)
+	if st.Code() != code {
+		t.Errorf("status = %v (%q), want %v", st.Code(), st.Message(), code)
+	}
+	if errCode != "" && !strings.HasPrefix(st.Message(), string(errCode)+": ") {
+		t.Errorf("status message = %q, want the %s prefix", st.Message(), errCode)
+	}
+}
+
+func TestServer_GetProgress(t *testing.T) {
+	client := newTestClient(t, newMemRepository(testRows()...))
+	ctx := context.Background()
+
+	got, err := client.GetProgress(ctx, &progresspb.GetProgressRequest{UserId: "user-1", GoalId: "kills"})
+	if err != nil {
+		t.Fatalf("GetProgress failed: %v", err)
+	}
+	if got.GetProgress() != 10 || got.GetStatus() != "completed" || got.GetChallengeId() != "challenge-1" || !got.GetIsActive() {
+		t.Errorf("GetProgress = %+v, want the completed kills row", got)
+	}
+	if got.GetCompletedAt() == nil || !got.GetCompletedAt().AsTime().Equal(got.GetCreatedAt().AsTime().Add(time.Hour)) {
+		t.Errorf("completed_at = %v, want an hour after created_at", got.GetCompletedAt())
+	}
+	if got.GetClaimedAt() != nil {
+		t.Errorf("claimed_at = %v, want unset", got.GetClaimedAt())
+	}
+
+	_, err = client.GetProgress(ctx, &progresspb.GetProgressRequest{UserId: "user-1", GoalId: "missing"})
+	assertStatus(t, err, codes.NotFound, errors.ErrCodeProgressNotFound)
+
+	_, err = client.GetProgress(ctx, &progresspb.GetProgressRequest{GoalId: "kills"})
+	assertStatus(t, err, codes.InvalidArgument, errors.ErrCodeInvalidInput)
+}
+
+func TestServer_GetUserProgress(t *testing.T) {
+	client := newTestClient(t, newMemRepository(testRows()...))
+	ctx := context.Background()
+
+	t.Run("pages through a user's rows", func(t *testing.T) {
+		var goals []string
+		req := &progresspb.GetUserProgressRequest{UserId: "user-1", Limit: 2}
+		for pages := 0; ; pages++ {
+			if pages > 3 {
+				t.Fatal("pagination did not end")
+			}
+			resp, err := client.GetUserProgress(ctx, req)
+			if err != nil {
+				t.Fatalf("GetUserProgress failed: %v", err)
+			}
+			for _, p := range resp.GetProgress() {
+				goals = append(goals, p.GetGoalId())
+			}
+			if resp.GetNextCursor() == "" {
+				break
+			}
+			req.Cursor = resp.GetNextCursor()
+		}
+		if got := strings.Join(goals, ","); got != "kills,wins,other" {
+			t.Errorf("goals = %s, want kills,wins,other", got)
+		}
+	})
+
+	t.Run("challenge and active filters", func(t *testing.T) {
+		resp, err := client.GetUserProgress(ctx, &progresspb.GetUserProgressRequest{UserId: "user-1", ChallengeId: "challenge-2"})
+		if err != nil {
+			t.Fatalf("GetUserProgress failed: %v", err)
+		}
+		if len(resp.GetProgress()) != 1 || resp.GetProgress()[0].GetGoalId() != "other" {
+			t.Errorf("challenge-2 page = %v, want only goal other", resp.GetProgress())
+		}
+
+		resp, err = client.GetUserProgress(ctx, &progresspb.GetUserProgressRequest{UserId: "user-1", ActiveOnly: true})
+		if err != nil {
+			t.Fatalf("GetUserProgress failed: %v", err)
+		}
+		if len(resp.GetProgress()) != 2 {
+			t.Errorf("active page has %d rows, want 2", len(resp.GetProgress()))
+		}
+	})
+
+	t.Run("bad cursor", func(t *testing.T) {
+		_, err := client.GetUserProgress(ctx, &progresspb.GetUserProgressRequest{UserId: "user-1", Cursor: "x"})
+		assertStatus(t, err, codes.InvalidArgument, errors.ErrCodeInvalidCursor)
+	})
+}
+
+func TestServer_GetChallengeSummary(t *testing.T) {
+	client := newTestClient(t, newMemRepository(testRows()...))
+	ctx := context.Background()
+
+	summary, err := client.GetChallengeSummary(ctx, &progresspb.GetChallengeSummaryRequest{UserId: "user-1", ChallengeId: "challenge-1"})
+	if err != nil {
+		t.Fatalf("GetChallengeSummary failed: %v", err)
+	}
+	if summary.GetName() != "Season 1" || summary.GetNameKey() != "season_1" {
+		t.Errorf("summary = %q/%q, want Season 1/season_1", summary.GetName(), summary.GetNameKey())
+	}
+	if summary.GetCompletedGoals() != 2 || summary.GetClaimedGoals() != 1 {
+		t.Errorf("completed/claimed = %d/%d, want 2/1", summary.GetCompletedGoals(), summary.GetClaimedGoals())
+	}
+
+	goals := summary.GetGoals()
+	if len(goals) != 3 || goals[0].GetGoalId() != "kills" || goals[1].GetGoalId() != "wins" || goals[2].GetGoalId() != "logins" {
+		t.Fatalf("goals = %v, want kills, wins, logins in config order", goals)
+	}
+	if goals[0].GetTargetValue() != 10 || goals[0].GetProgress().GetProgress() != 10 {
+		t.Errorf("kills = %+v, want target 10 and progress 10", goals[0])
+	}
+	if goals[2].GetProgress() != nil {
+		t.Errorf("logins progress = %v, want unset without a row", goals[2].GetProgress())
+	}
+
+	_, err = client.GetChallengeSummary(ctx, &progresspb.GetChallengeSummaryRequest{UserId: "user-1", ChallengeId: "missing"})
+	assertStatus(t, err, codes.NotFound, errors.ErrCodeChallengeNotFound)
+}
+
+func TestServer_MarkAsClaimed(t *testing.T) {
+	repo := newMemRepository(testRows()...)
+	client := newTestClient(t, repo)
+	ctx := context.Background()
+
+	if _, err := client.MarkAsClaimed(ctx, &progresspb.MarkAsClaimedRequest{UserId: "user-1", GoalId: "kills"}); err != nil {
+		t.Fatalf("MarkAsClaimed failed: %v", err)
+	}
+	if got := repo.deadlines["kills"]; got != time.Hour {
+		t.Errorf("claim deadline = %v, want the goal's 1h", got)
+	}
+
+	tests := []struct {
+		name    string
+		userID  string
+		goalID  string
+		code    codes.Code
+		errCode errors.Code
+	}{
+		{"already claimed", "user-1", "kills", codes.AlreadyExists, errors.ErrCodeGoalAlreadyClaimed},
+		{"not completed", "user-2", "kills", codes.FailedPrecondition, errors.ErrCodeGoalNotCompleted},
+		{"no progress", "user-9", "kills", codes.NotFound, errors.ErrCodeProgressNotFound},
+		{"unknown goal", "user-1", "missing", codes.NotFound, errors.ErrCodeGoalNotFound},
+		{"missing user", "", "kills", codes.InvalidArgument, errors.ErrCodeInvalidInput},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := client.MarkAsClaimed(ctx, &progresspb.MarkAsClaimedRequest{UserId: tt.userID, GoalId: tt.goalID})
+			assertStatus(t, err, tt.code, tt.errCode)
+		})
+	}
+}
+
+func TestServer_SearchProgress(t *testing.T) {
+	repo := newMemRepository(testRows()...)
+	client := newTestClient(t, repo)
+	ctx := context.Background()
+
+	minProgress, active := int32(2), true
+	resp, err := client.SearchProgress(ctx, &progresspb.SearchProgressRequest{
+		Namespace:    "ns",
+		Statuses:     []string{"completed", "claimed"},
+		MinProgress:  &minProgress,
+		IsActive:     &active,
+		GoalIdPrefix: "ki",
+		Limit:        1,
+	})
+	if err != nil {
+		t.Fatalf("SearchProgress failed: %v", err)
+	}
+	if len(resp.GetProgress()) != 1 || resp.GetNextCursor() == "" {
+		t.Errorf("page = %d rows, next %q, want 1 row and a next cursor", len(resp.GetProgress()), resp.GetNextCursor())
+	}
+
+	q := repo.query
+	if q.Namespace != "ns" || len(q.Statuses) != 2 || q.GoalIDPrefix != "ki" {
+		t.Errorf("query = %+v, want the request's filters", q)
+	}
+	if q.MinProgress == nil || *q.MinProgress != 2 || q.MaxProgress != nil {
+		t.Errorf("progress bounds = %v/%v, want min 2 and no max", q.MinProgress, q.MaxProgress)
+	}
+	if q.IsActive == nil || !*q.IsActive || q.UpdatedAfter != nil {
+		t.Errorf("is_active = %v, updated_after = %v, want true and unset", q.IsActive, q.UpdatedAfter)
+	}
+
+	t.Run("repository without search", func(t *testing.T) {
+		client := newTestClient(t, struct{ repository.GoalRepository }{repo})
+		_, err := client.SearchProgress(ctx, &progresspb.SearchProgressRequest{})
+		assertStatus(t, err, codes.Unimplemented, "")
+	})
+}
+
+func TestServer_Deadlines(t *testing.T) {
+	t.Run("server timeout bounds the RPC", func(t *testing.T) {
+		repo := newMemRepository(testRows()...)
+		repo.block = true
+		client := newTestClient(t, repo, WithTimeout(20*time.Millisecond))
+
+		_, err := client.GetProgress(context.Background(), &progresspb.GetProgressRequest{UserId: "user-1", GoalId: "kills"})
+		assertStatus(t, err, codes.DeadlineExceeded, "")
+	})
+
+	t.Run("client deadline reaches the repository", func(t *testing.T) {
+		repo := newMemRepository(testRows()...)
+		repo.block = true
+		client := newTestClient(t, repo, WithTimeout(time.Minute))
+
+		ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+		defer cancel()
+		clientDeadline, _ := ctx.Deadline()
+
+		_, err := client.GetProgress(ctx, &progresspb.GetProgressRequest{UserId: "user-1", GoalId: "kills"})
+		assertStatus(t, err, codes.DeadlineExceeded, "")
+
+		repo.mu.Lock()
+		defer repo.mu.Unlock()
+		deadline, ok := repo.sawCtx.Deadline()
+		if !ok || deadline.After(clientDeadline.Add(time.Second)) {
+			t.Errorf("repository deadline = %v (set %v), want the client's %v", deadline, ok, clientDeadline)
+		}
+	})
+}
+
+func TestServer_Metrics(t *testing.T) {
+	metrics := &fakeServerMetrics{}
+	client := newTestClient(t, newMemRepository(testRows()...), WithMetrics(metrics))
+	ctx := context.Background()
+
+	_, _ = client.GetProgress(ctx, &progresspb.GetProgressRequest{UserId: "user-1", GoalId: "kills"})
+	_, _ = client.MarkAsClaimed(ctx, &progresspb.MarkAsClaimedRequest{UserId: "user-1", GoalId: "wins"})
+
+	metrics.mu.Lock()
+	defer metrics.mu.Unlock()
+	if got := strings.Join(metrics.calls, ", "); got != "GetProgress OK, MarkAsClaimed AlreadyExists" {
+		t.Errorf("metrics = %s, want GetProgress OK, MarkAsClaimed AlreadyExists", got)
+	}
+}
@@ -0,0 +1,42 @@
+package grpc
+
+import (
+	"context"
+	stderrors "errors"
+	"fmt"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/AccelByte/extend-challenge-common/pkg/errors"
+)
+
+// toStatus converts an error returned by the repository or the adapter into a gRPC status
+// error.
+//
+// A ChallengeError keeps its code: the status code is Code.GRPCCode() and the message is
+// "<CODE>: <message>". The wrapped cause is left out, so database details do not reach
+// clients. Context errors become DEADLINE_EXCEEDED or CANCELLED, including when the
+// repository wrapped them in a DATABASE_ERROR, so a client can tell a slow call from a
+// failed one. Anything else is INTERNAL.
+func toStatus(err error) error {
+	if err == nil {
+		return nil
+	}
+	if _, ok := status.FromError(err); ok {
+		return err
+	}
+
+	switch {
+	case stderrors.Is(err, context.DeadlineExceeded):
+		return status.Error(codes.DeadlineExceeded, context.DeadlineExceeded.Error())
+	case stderrors.Is(err, context.Canceled):
+		return status.Error(codes.Canceled, context.Canceled.Error())
+	}
+
+	var ce *errors.ChallengeError
+	if stderrors.As(err, &ce) {
+		return status.Error(codes.Code(ce.Code.GRPCCode()), fmt.Sprintf("%s: %s", ce.Code, ce.Message))
+	}
+	return status.Error(codes.Internal, "internal error")
+}
@@ -0,0 +1,42 @@
+package grpc
+
+import (
+	"context"
+	stderrors "errors"
+	"fmt"
+	"testing"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/AccelByte/extend-challenge-common/pkg/errors"
+)
+
+func TestToStatus(t *testing.T) {
+	tests := []struct {
+		name    string
+		err     error
+		code    codes.Code
+		message string
+	}{
+		{"nil", nil, codes.OK, ""},
+		{"challenge error", errors.ErrGoalAlreadyClaimed("kills"), codes.AlreadyExists, "GOAL_ALREADY_CLAIMED: goal already claimed: kills"},
+		{"wrapped challenge error", fmt.Errorf("claim: %w", errors.ErrRateLimited("ns", "claim", nil)), codes.ResourceExhausted, ""},
+		{"cause is not leaked", errors.ErrDatabaseError("get progress", stderrors.New("dial tcp 10.0.0.1:5432")), codes.Internal, "DATABASE_ERROR: database error during get progress"},
+		{"deadline inside a database error", errors.ErrDatabaseError("get progress", context.DeadlineExceeded), codes.DeadlineExceeded, ""},
+		{"cancelled", context.Canceled, codes.Canceled, ""},
+		{"status passes through", status.Error(codes.Unimplemented, "no"), codes.Unimplemented, "no"},
+		{"plain error", stderrors.New("boom"), codes.Internal, "internal error"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			st := status.Convert(toStatus(tt.err))
+			if st.Code() != tt.code {
+				t.Errorf("code = %v, want %v", st.Code(), tt.code)
+			}
+			if tt.message != "" && st.Message() != tt.message {
+				t.Errorf("message = %q, want %q", st.Message(), tt.message)
+			}
+		})
+	}
+}
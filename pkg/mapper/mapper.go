@@ -0,0 +1,164 @@
+// Package mapper converts external AGS events into repository progress increments.
+//
+// The mapping from a goal definition to ProgressIncrement fields (delta, target value,
+// daily flag, cooldown, event time) lives here so consumers do not re-derive it. Absolute
+// goals are not mapped: they mirror a stat value and are written with BatchUpsertProgress.
+//
+// Disabled goals are always skipped. Rotating goals (domain.Goal.RotationGroup) are only
+// skipped outside their week when the rotation schedule is passed with WithRotation, since
+// the goal config records a goal's week but not when week 0 starts or how long a week is.
+package mapper
+
+import (
+	"time"
+
+	"github.com/AccelByte/extend-challenge-common/pkg/cache"
+	"github.com/AccelByte/extend-challenge-common/pkg/domain"
+	"github.com/AccelByte/extend-challenge-common/pkg/repository"
+)
+
+// StatEvent is a statistic update for one user (e.g., statItemUpdated).
+type StatEvent struct {
+	UserID    string
+	Namespace string
+	StatCode  string
+
+	// Delta is the change in the stat value carried by the event
+	Delta int
+
+	// OccurredAt is when the event happened; zero means unknown (bucketed at write time)
+	OccurredAt time.Time
+}
+
+// LoginEvent is a user login (e.g., iam.account.v1.userLoggedIn).
+type LoginEvent struct {
+	UserID    string
+	Namespace string
+
+	// OccurredAt is when the login happened; zero means unknown (bucketed at write time)
+	OccurredAt time.Time
+}
+
+// RotationSchedule places rotating goals in time: week 0 of every rotation group starts at
+// Start and a new week begins every Period. With Weeks > 0 the groups cycle through weeks
+// 0 to Weeks-1.
+type RotationSchedule struct {
+	Start  time.Time
+	Period time.Duration
+	Weeks  int
+}
+
+// week returns the rotation week containing t, or -1 before Start.
+func (r RotationSchedule) week(t time.Time) int {
+	week := domain.CurrentRotationWeek(r.Start, t, r.Period)
+	if week > 0 && r.Weeks > 0 {
+		week %= r.Weeks
+	}
+	return week
+}
+
+// Option configures BuildIncrements and BuildLoginIncrements.
+type Option func(*options)
+
+type options struct {
+	rotation *RotationSchedule
+	now      func() time.Time
+}
+
+// WithRotation skips rotating goals outside the current week of schedule. The week is
+// taken at the event's OccurredAt, or at now() when the event time is unknown; nil now
+// means time.Now. Without it, rotating goals are mapped in every week.
+func WithRotation(schedule RotationSchedule, now func() time.Time) Option {
+	return func(o *options) {
+		o.rotation = &schedule
+		o.now = now
+	}
+}
+
+func newOptions(opts []Option) options {
+	o := options{now: time.Now}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	if o.now == nil {
+		o.now = time.Now
+	}
+	return o
+}
+
+// BuildIncrements returns one increment per enabled, increment-like goal that tracks the
+// event's stat code from the statistic event source. Events with Delta <= 0 produce none.
+func BuildIncrements(goalCache cache.GoalCache, event StatEvent, opts ...Option) []repository.ProgressIncrement {
+	if event.Delta <= 0 {
+		return nil
+	}
+
+	o := newOptions(opts)
+	var increments []repository.ProgressIncrement
+	for _, goal := range goalCache.GetGoalsByStatCode(event.StatCode) {
+		if goal.EventSource != domain.EventSourceStatistic || !o.mappable(goal, event.OccurredAt) {
+			continue
+		}
+		increments = append(increments, newIncrement(goal, event.UserID, event.Namespace, event.Delta, event.OccurredAt))
+	}
+
+	return increments
+}
+
+// BuildLoginIncrements returns one increment per enabled, increment-like goal driven by
+// the login event source. Each login counts as 1.
+func BuildLoginIncrements(goalCache cache.GoalCache, event LoginEvent, opts ...Option) []repository.ProgressIncrement {
+	o := newOptions(opts)
+	var increments []repository.ProgressIncrement
+	for _, goal := range goalCache.GetAllGoals() {
+		if goal.EventSource != domain.EventSourceLogin || !o.mappable(goal, event.OccurredAt) {
+			continue
+		}
+		increments = append(increments, newIncrement(goal, event.UserID, event.Namespace, 1, event.OccurredAt))
+	}
+
+	return increments
+}
+
+// mappable reports whether an event that occurred at occurredAt (zero if unknown) should
+// produce an increment for goal. Disabled goals keep their progress but receive no new
+// events; with WithRotation, neither do rotating goals outside their week.
+func (o options) mappable(goal *domain.Goal, occurredAt time.Time) bool {
+	if !goal.IsEnabled() || !goal.IsIncrementLike() {
+		return false
+	}
+	if o.rotation == nil || goal.RotationGroup == "" {
+		return true
+	}
+
+	if occurredAt.IsZero() {
+		occurredAt = o.now()
+	}
+	return o.rotation.week(occurredAt) == goal.RotationWeek
+}
+
+// newIncrement derives a ProgressIncrement from the goal definition.
+// Goals counted once per day (daily type, or increment with Daily) add 1 per day
+// regardless of the event's delta.
+func newIncrement(goal *domain.Goal, userID, namespace string, delta int, occurredAt time.Time) repository.ProgressIncrement {
+	isDaily := goal.EffectiveType() == domain.GoalTypeDaily || goal.Daily
+	if isDaily {
+		delta = 1
+	}
+
+	inc := repository.ProgressIncrement{
+		UserID:           userID,
+		GoalID:           goal.ID,
+		ChallengeID:      goal.ChallengeID,
+		Namespace:        namespace,
+		Delta:            delta,
+		TargetValue:      goal.Requirement.TargetValue,
+		IsDailyIncrement: isDaily,
+		Cooldown:         goal.Cooldown.Std(),
+	}
+	if !occurredAt.IsZero() {
+		inc.OccurredAt = &occurredAt
+	}
+
+	return inc
+}
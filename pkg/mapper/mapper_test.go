@@ -0,0 +1,215 @@
+package mapper
+
+import (
+	"io"
+	"log/slog"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/AccelByte/extend-challenge-common/pkg/cache"
+	"github.com/AccelByte/extend-challenge-common/pkg/config"
+	"github.com/AccelByte/extend-challenge-common/pkg/domain"
+	"github.com/AccelByte/extend-challenge-common/pkg/repository"
+)
+
+func newTestCache(goals ...*domain.Goal) cache.GoalCache {
+	cfg := &config.Config{
+		Challenges: []*domain.Challenge{{ID: "challenge-1", Name: "Challenge 1", Goals: goals}},
+	}
+	return cache.NewInMemoryGoalCache(cfg, "", slog.New(slog.NewTextHandler(io.Discard, nil)))
+}
+
+func statGoal(id string, goalType domain.GoalType, daily bool, statCode string) *domain.Goal {
+	return &domain.Goal{
+		ID:          id,
+		Type:        goalType,
+		EventSource: domain.EventSourceStatistic,
+		Daily:       daily,
+		Requirement: domain.Requirement{StatCode: statCode, Operator: ">=", TargetValue: 10},
+	}
+}
+
+// byGoalID indexes increments by goal ID for order-independent assertions.
+func byGoalID(increments []repository.ProgressIncrement) map[string]repository.ProgressIncrement {
+	m := make(map[string]repository.ProgressIncrement, len(increments))
+	for _, inc := range increments {
+		m[inc.GoalID] = inc
+	}
+	return m
+}
+
+func TestBuildIncrements(t *testing.T) {
+	disabled := false
+	cooldownGoal := statGoal("kills-cooldown", domain.GoalTypeIncrement, false, "kills")
+	cooldownGoal.Cooldown = domain.Duration(time.Hour)
+	disabledGoal := statGoal("kills-disabled", domain.GoalTypeIncrement, false, "kills")
+	disabledGoal.Enabled = &disabled
+
+	goalCache := newTestCache(
+		statGoal("kills-absolute", domain.GoalTypeAbsolute, false, "kills"),
+		statGoal("kills-increment", domain.GoalTypeIncrement, false, "kills"),
+		statGoal("kills-daily-flag", domain.GoalTypeIncrement, true, "kills"),
+		statGoal("kills-daily", domain.GoalTypeDaily, false, "kills"),
+		cooldownGoal,
+		disabledGoal,
+		statGoal("wins-increment", domain.GoalTypeIncrement, false, "wins"),
+	)
+
+	occurredAt := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	increments := BuildIncrements(goalCache, StatEvent{
+		UserID: "user-1", Namespace: "ns", StatCode: "kills", Delta: 5, OccurredAt: occurredAt,
+	})
+	got := byGoalID(increments)
+
+	tests := []struct {
+		goalID    string
+		wantDelta int
+		wantDaily bool
+	}{
+		{"kills-increment", 5, false},
+		{"kills-daily-flag", 1, true},
+		{"kills-daily", 1, true},
+		{"kills-cooldown", 5, false},
+	}
+
+	if len(increments) != len(tests) {
+		t.Fatalf("expected %d increments, got %d: %+v", len(tests), len(increments), increments)
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.goalID, func(t *testing.T) {
+			inc, ok := got[tt.goalID]
+			if !ok {
+				t.Fatalf("expected an increment for %s", tt.goalID)
+			}
+			if inc.Delta != tt.wantDelta {
+				t.Errorf("Delta = %d, want %d", inc.Delta, tt.wantDelta)
+			}
+			if inc.IsDailyIncrement != tt.wantDaily {
+				t.Errorf("IsDailyIncrement = %v, want %v", inc.IsDailyIncrement, tt.wantDaily)
+			}
+			if inc.UserID != "user-1" || inc.Namespace != "ns" || inc.ChallengeID != "challenge-1" || inc.TargetValue != 10 {
+				t.Errorf("unexpected identity fields: %+v", inc)
+			}
+			if inc.OccurredAt == nil || !inc.OccurredAt.Equal(occurredAt) {
+				t.Errorf("OccurredAt = %v, want %v", inc.OccurredAt, occurredAt)
+			}
+		})
+	}
+
+	if got["kills-cooldown"].Cooldown != time.Hour {
+		t.Errorf("expected cooldown from goal, got %v", got["kills-cooldown"].Cooldown)
+	}
+	if _, ok := got["kills-absolute"]; ok {
+		t.Error("absolute goals should not produce increments")
+	}
+	if _, ok := got["kills-disabled"]; ok {
+		t.Error("disabled goals should be skipped")
+	}
+
+	t.Run("non-positive delta", func(t *testing.T) {
+		increments := BuildIncrements(goalCache, StatEvent{UserID: "user-1", StatCode: "kills", Delta: 0})
+		if len(increments) != 0 {
+			t.Errorf("expected no increments, got %+v", increments)
+		}
+	})
+
+	t.Run("zero OccurredAt", func(t *testing.T) {
+		increments := BuildIncrements(goalCache, StatEvent{UserID: "user-1", StatCode: "wins", Delta: 1})
+		if len(increments) != 1 || increments[0].OccurredAt != nil {
+			t.Errorf("expected one increment without OccurredAt, got %+v", increments)
+		}
+	})
+}
+
+func TestBuildLoginIncrements(t *testing.T) {
+	disabled := false
+	loginGoal := func(id string, goalType domain.GoalType, daily bool) *domain.Goal {
+		return &domain.Goal{
+			ID:          id,
+			Type:        goalType,
+			EventSource: domain.EventSourceLogin,
+			Daily:       daily,
+			Requirement: domain.Requirement{StatCode: "login_count", Operator: ">=", TargetValue: 7},
+		}
+	}
+	disabledGoal := loginGoal("login-disabled", domain.GoalTypeIncrement, false)
+	disabledGoal.Enabled = &disabled
+
+	goalCache := newTestCache(
+		loginGoal("login-total", domain.GoalTypeIncrement, false),
+		loginGoal("login-days", domain.GoalTypeIncrement, true),
+		loginGoal("login-daily", domain.GoalTypeDaily, false),
+		disabledGoal,
+		statGoal("kills-increment", domain.GoalTypeIncrement, false, "kills"),
+	)
+
+	got := byGoalID(BuildLoginIncrements(goalCache, LoginEvent{UserID: "user-1", Namespace: "ns"}))
+
+	if len(got) != 3 {
+		t.Fatalf("expected 3 increments, got %+v", got)
+	}
+	if inc := got["login-total"]; inc.Delta != 1 || inc.IsDailyIncrement {
+		t.Errorf("login-total: expected delta 1 every login, got %+v", inc)
+	}
+	if inc := got["login-days"]; inc.Delta != 1 || !inc.IsDailyIncrement {
+		t.Errorf("login-days: expected daily increment, got %+v", inc)
+	}
+	if inc := got["login-daily"]; !inc.IsDailyIncrement {
+		t.Errorf("login-daily: expected daily increment, got %+v", inc)
+	}
+	if _, ok := got["login-disabled"]; ok {
+		t.Error("disabled goals should be skipped")
+	}
+}
+
+func TestBuildIncrements_Rotation(t *testing.T) {
+	rotating := func(id string, week int) *domain.Goal {
+		goal := statGoal(id, domain.GoalTypeIncrement, false, "kills")
+		goal.RotationGroup = "weekly"
+		goal.RotationWeek = week
+		return goal
+	}
+	goalCache := newTestCache(
+		rotating("week-0", 0),
+		rotating("week-1", 1),
+		statGoal("always", domain.GoalTypeIncrement, false, "kills"),
+	)
+
+	start := time.Date(2026, 1, 5, 0, 0, 0, 0, time.UTC)
+	schedule := RotationSchedule{Start: start, Period: 7 * 24 * time.Hour, Weeks: 2}
+	clock := func() time.Time { return start.Add(24 * time.Hour) } // week 0
+	week := func(n int) time.Time { return start.Add(time.Duration(n)*7*24*time.Hour + time.Hour) }
+
+	tests := []struct {
+		name       string
+		occurredAt time.Time
+		opts       []Option
+		want       string
+	}{
+		{"without schedule", week(1), nil, "always,week-0,week-1"},
+		{"event in week 1", week(1), []Option{WithRotation(schedule, clock)}, "always,week-1"},
+		{"weeks wrap", week(2), []Option{WithRotation(schedule, clock)}, "always,week-0"},
+		{"before start", start.Add(-time.Hour), []Option{WithRotation(schedule, clock)}, "always"},
+		{"unknown event time uses the clock", time.Time{}, []Option{WithRotation(schedule, clock)}, "always,week-0"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			increments := BuildIncrements(goalCache, StatEvent{
+				UserID: "user-1", Namespace: "ns", StatCode: "kills", Delta: 1, OccurredAt: tt.occurredAt,
+			}, tt.opts...)
+
+			ids := make([]string, 0, len(increments))
+			for _, inc := range increments {
+				ids = append(ids, inc.GoalID)
+			}
+			sort.Strings(ids)
+			if got := strings.Join(ids, ","); got != tt.want {
+				t.Errorf("goals = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
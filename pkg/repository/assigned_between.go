@@ -0,0 +1,80 @@
+package repository
+
+import (
+	"context"
+	"strconv"
+	"time"
+
+	"github.com/AccelByte/extend-challenge-common/pkg/domain"
+	"github.com/AccelByte/extend-challenge-common/pkg/errors"
+)
+
+// assignedCursor is the decoded form of a GetAssignedBetween cursor.
+type assignedCursor struct {
+	AssignedAt time.Time `json:"t"`
+	UserID     string    `json:"u"`
+	GoalID     string    `json:"g"`
+}
+
+// encodeAssignedCursor builds the opaque cursor for the last row of a page.
+func encodeAssignedCursor(last *domain.UserGoalProgress) string {
+	c := assignedCursor{UserID: last.UserID, GoalID: last.GoalID}
+	if last.AssignedAt != nil {
+		c.AssignedAt = last.AssignedAt.UTC()
+	}
+	return encodeCursor(c)
+}
+
+// decodeAssignedCursor parses a cursor produced by encodeAssignedCursor.
+func decodeAssignedCursor(cursor string) (assignedCursor, error) {
+	c, err := decodeCursor[assignedCursor](cursor)
+	if err != nil {
+		return c, err
+	}
+	if c.AssignedAt.IsZero() || c.UserID == "" || c.GoalID == "" {
+		return c, errors.ErrInvalidCursor("missing key fields")
+	}
+
+	return c, nil
+}
+
+// GetAssignedBetween returns one page of the namespace's rows assigned within [from, to],
+// ordered by (assigned_at, user_id, goal_id). A window with from after to fails with
+// ErrValidationFailed. Served by idx_user_goal_progress_assigned (migration 016).
+func (r *PostgresGoalRepository) GetAssignedBetween(ctx context.Context, namespace string, from, to time.Time, limit int, cursor string) ([]*domain.UserGoalProgress, string, error) {
+	if from.After(to) {
+		return nil, "", errors.ErrValidationFailed("from", "must not be after to")
+	}
+
+	query := "SELECT " + r.progressColumns() + " FROM user_goal_progress" +
+		" WHERE namespace = $1 AND assigned_at BETWEEN $2 AND $3 AND assigned_at IS NOT NULL"
+	args := []interface{}{namespace, from, to}
+
+	if cursor != "" {
+		c, err := decodeAssignedCursor(cursor)
+		if err != nil {
+			return nil, "", err
+		}
+		query += " AND (assigned_at, user_id, goal_id) > ($4, $5, $6)"
+		args = append(args, c.AssignedAt, c.UserID, c.GoalID)
+	}
+
+	// Fetch one extra row to know whether another page exists
+	limit = pageLimit(limit)
+	query += " ORDER BY assigned_at ASC, user_id ASC, goal_id ASC LIMIT $" + strconv.Itoa(len(args)+1)
+	args = append(args, limit+1)
+
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, "", errors.ErrDatabaseError("get assigned between", err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	results, err := r.scanProgressRows(rows)
+	if err != nil {
+		return nil, "", err
+	}
+
+	results, next := pageTail(results, limit, encodeAssignedCursor)
+	return results, next, nil
+}
@@ -0,0 +1,116 @@
+package repository
+
+import (
+	"context"
+	"encoding/base64"
+	"testing"
+	"time"
+
+	"github.com/AccelByte/extend-challenge-common/pkg/domain"
+	customerrors "github.com/AccelByte/extend-challenge-common/pkg/errors"
+)
+
+func TestDecodeAssignedCursor(t *testing.T) {
+	assignedAt := time.Date(2025, 6, 1, 12, 0, 0, 123000, time.UTC)
+	valid := encodeAssignedCursor(&domain.UserGoalProgress{UserID: "user-1", GoalID: "goal-1", AssignedAt: &assignedAt})
+
+	c, err := decodeAssignedCursor(valid)
+	if err != nil {
+		t.Fatalf("decodeAssignedCursor failed: %v", err)
+	}
+	if !c.AssignedAt.Equal(assignedAt) || c.UserID != "user-1" || c.GoalID != "goal-1" {
+		t.Errorf("Round trip = %+v", c)
+	}
+
+	for _, bad := range []string{
+		"!!!",
+		base64.RawURLEncoding.EncodeToString([]byte(`{"u":"user-1","g":"goal-1"}`)), // no assigned_at
+		base64.RawURLEncoding.EncodeToString([]byte(`[1]`)),
+	} {
+		_, err := decodeAssignedCursor(bad)
+		assertErrorCode(t, err, customerrors.ErrCodeInvalidCursor)
+	}
+}
+
+func TestGetAssignedBetween_InvertedWindow(t *testing.T) {
+	// nil *sql.DB: any SQL would panic, so the window must be rejected first
+	repo := NewPostgresGoalRepository(nil)
+	now := time.Now()
+
+	_, _, err := repo.GetAssignedBetween(context.Background(), "test", now, now.Add(-time.Hour), 100, "")
+	assertErrorCode(t, err, customerrors.ErrCodeValidationFailed)
+}
+
+func TestPostgresGoalRepository_GetAssignedBetween(t *testing.T) {
+	db := setupTestDB(t)
+	if db == nil {
+		return
+	}
+	defer cleanupTestDB(t, db)
+
+	repo := NewPostgresGoalRepository(db)
+	ctx := context.Background()
+
+	now := time.Now().UTC().Truncate(time.Microsecond)
+	minutesAgo := func(minutes int) *time.Time {
+		ts := now.Add(-time.Duration(minutes) * time.Minute)
+		return &ts
+	}
+	row := func(userID, goalID, namespace string, assignedAt *time.Time) *domain.UserGoalProgress {
+		return &domain.UserGoalProgress{UserID: userID, GoalID: goalID, ChallengeID: "c1", Namespace: namespace,
+			Status: domain.GoalStatusNotStarted, IsActive: true, AssignedAt: assignedAt}
+	}
+	if err := repo.BulkInsert(ctx, []*domain.UserGoalProgress{
+		row("u2", "goal-a", "test", minutesAgo(10)),
+		row("u1", "goal-b", "test", minutesAgo(10)), // shares assigned_at with u2/goal-a
+		row("u1", "goal-a", "test", minutesAgo(30)),
+		row("u3", "goal-a", "test", minutesAgo(59)),
+		row("u4", "goal-a", "test", minutesAgo(90)),  // outside the window
+		row("u5", "goal-a", "test", nil),             // never assigned
+		row("u6", "goal-a", "other", minutesAgo(10)), // other namespace
+	}); err != nil {
+		t.Fatalf("BulkInsert failed: %v", err)
+	}
+
+	want := []string{"u3/goal-a", "u1/goal-a", "u1/goal-b", "u2/goal-a"}
+
+	t.Run("stable pagination", func(t *testing.T) {
+		var all []*domain.UserGoalProgress
+		cursor := ""
+		for pages := 0; ; pages++ {
+			if pages > len(want) {
+				t.Fatal("Pagination did not terminate")
+			}
+			page, next, err := repo.GetAssignedBetween(ctx, "test", *minutesAgo(60), now, 1, cursor)
+			if err != nil {
+				t.Fatalf("GetAssignedBetween failed: %v", err)
+			}
+			all = append(all, page...)
+			if next == "" {
+				break
+			}
+			cursor = next
+		}
+		assertParticipantRows(t, all, want)
+	})
+
+	t.Run("window bounds are inclusive", func(t *testing.T) {
+		page, next, err := repo.GetAssignedBetween(ctx, "test", *minutesAgo(30), *minutesAgo(30), 100, "")
+		if err != nil || next != "" {
+			t.Fatalf("GetAssignedBetween = next %q, err %v", next, err)
+		}
+		assertParticipantRows(t, page, []string{"u1/goal-a"})
+	})
+
+	t.Run("empty window", func(t *testing.T) {
+		page, next, err := repo.GetAssignedBetween(ctx, "test", now.Add(time.Hour), now.Add(2*time.Hour), 100, "")
+		if err != nil || page == nil || len(page) != 0 || next != "" {
+			t.Errorf("Expected empty result, got %v, next %q, err %v", page, next, err)
+		}
+	})
+
+	t.Run("invalid cursor", func(t *testing.T) {
+		_, _, err := repo.GetAssignedBetween(ctx, "test", *minutesAgo(60), now, 100, "!!!")
+		assertErrorCode(t, err, customerrors.ErrCodeInvalidCursor)
+	})
+}
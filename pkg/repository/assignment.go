@@ -0,0 +1,78 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/AccelByte/extend-challenge-common/pkg/domain"
+)
+
+// ActivationOptions controls how UpsertGoalActiveWithOptions treats assigned_at.
+type ActivationOptions struct {
+	// UpdateAssignedAt resets assigned_at to NOW() when the goal is activated, even if it
+	// is already active (a re-assignment that refreshes the window). When false, an
+	// existing assigned_at is kept and only a missing one is set. Deactivation never
+	// changes assigned_at.
+	UpdateAssignedAt bool
+}
+
+// ensureAssignedQuery builds the INSERT used by EnsureAssigned.
+// Only identity columns, expires_at and order_index are taken from the input; new rows
+// always start active, not_started, with assigned_at = NOW(). Existing rows are left untouched.
+func ensureAssignedQuery(progresses []*domain.UserGoalProgress) (string, []interface{}) {
+	// Build values for bulk insert (6 parameters per row)
+	valueStrings := make([]string, 0, len(progresses))
+	valueArgs := make([]interface{}, 0, len(progresses)*6)
+
+	for i, p := range progresses {
+		valueStrings = append(valueStrings, fmt.Sprintf(
+			"($%d, $%d, $%d, $%d, 0, 'not_started', NOW(), NOW(), true, NOW(), $%d::TIMESTAMP, $%d::INT)",
+			i*6+1, i*6+2, i*6+3, i*6+4, i*6+5, i*6+6,
+		))
+
+		valueArgs = append(valueArgs,
+			p.UserID,
+			p.GoalID,
+			p.ChallengeID,
+			p.Namespace,
+			p.ExpiresAt,
+			p.OrderIndex,
+		)
+	}
+
+	//nolint:gosec // Safe: valueStrings contains only parameterized placeholders like "($1, $2, $3)", not user input
+	query := fmt.Sprintf(`
+		INSERT INTO user_goal_progress (
+			user_id, goal_id, challenge_id, namespace,
+			progress, status,
+			created_at, updated_at,
+			is_active, assigned_at, expires_at, order_index
+		) VALUES %s
+		ON CONFLICT (user_id, goal_id) DO NOTHING
+	`, strings.Join(valueStrings, ","))
+
+	return query, valueArgs
+}
+
+// UpsertGoalActiveWithOptions creates or updates a goal's is_active status, setting
+// assigned_at as opts specifies.
+func (r *PostgresGoalRepository) UpsertGoalActiveWithOptions(ctx context.Context, progress *domain.UserGoalProgress, opts ActivationOptions) error {
+	return r.exec().upsertGoalActive(ctx, progress, opts)
+}
+
+// UpsertGoalActiveWithOptions creates or updates a goal's is_active status within a
+// transaction, setting assigned_at as opts specifies.
+func (r *PostgresTxRepository) UpsertGoalActiveWithOptions(ctx context.Context, progress *domain.UserGoalProgress, opts ActivationOptions) error {
+	return r.exec().upsertGoalActive(ctx, progress, opts)
+}
+
+// EnsureAssigned inserts missing goal assignments and leaves existing rows untouched.
+func (r *PostgresGoalRepository) EnsureAssigned(ctx context.Context, progresses []*domain.UserGoalProgress) (int64, error) {
+	return r.exec().ensureAssigned(ctx, progresses)
+}
+
+// EnsureAssigned inserts missing goal assignments within a transaction.
+func (r *PostgresTxRepository) EnsureAssigned(ctx context.Context, progresses []*domain.UserGoalProgress) (int64, error) {
+	return r.exec().ensureAssigned(ctx, progresses)
+}
@@ -0,0 +1,222 @@
+package repository
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/AccelByte/extend-challenge-common/pkg/domain"
+)
+
+func TestEnsureAssignedQuery(t *testing.T) {
+	query, args := ensureAssignedQuery([]*domain.UserGoalProgress{
+		{UserID: "u1", GoalID: "g1", ChallengeID: "c1", Namespace: "ns"},
+		{UserID: "u1", GoalID: "g2", ChallengeID: "c1", Namespace: "ns"},
+	})
+
+	if len(args) != 12 {
+		t.Errorf("Expected 12 args (6 per row), got %d", len(args))
+	}
+	if !strings.Contains(query, "$11::TIMESTAMP, $12::INT") {
+		t.Errorf("Expected placeholders up to $12, got %s", query)
+	}
+	if !strings.Contains(query, "ON CONFLICT (user_id, goal_id) DO NOTHING") {
+		t.Error("Expected existing rows to be left untouched")
+	}
+}
+
+func TestPostgresGoalRepository_EnsureAssigned(t *testing.T) {
+	db := setupTestDB(t)
+	if db == nil {
+		return
+	}
+	defer cleanupTestDB(t, db)
+
+	repo := NewPostgresGoalRepository(db)
+	ctx := context.Background()
+
+	defaults := func() []*domain.UserGoalProgress {
+		return []*domain.UserGoalProgress{
+			{UserID: "assign-user", GoalID: "default-1", ChallengeID: "c1", Namespace: "test"},
+			{UserID: "assign-user", GoalID: "default-2", ChallengeID: "c1", Namespace: "test"},
+		}
+	}
+
+	t.Run("first run assigns missing goals", func(t *testing.T) {
+		inserted, err := repo.EnsureAssigned(ctx, defaults())
+		if err != nil {
+			t.Fatalf("EnsureAssigned failed: %v", err)
+		}
+		if inserted != 2 {
+			t.Errorf("Expected 2 newly assigned, got %d", inserted)
+		}
+
+		p, _ := repo.GetProgress(ctx, "assign-user", "default-1")
+		if p == nil || !p.IsActive || p.AssignedAt == nil || p.Status != domain.GoalStatusNotStarted {
+			t.Errorf("Expected active not_started row with assigned_at, got %+v", p)
+		}
+	})
+
+	t.Run("second run leaves existing rows untouched", func(t *testing.T) {
+		// Simulate progress and an old assignment on one row, and deactivation on the other
+		oldAssigned := time.Now().Add(-48 * time.Hour).UTC().Truncate(time.Microsecond)
+		_, err := db.ExecContext(ctx, `
+			UPDATE user_goal_progress SET progress = 4, status = 'in_progress', assigned_at = $1
+			WHERE user_id = 'assign-user' AND goal_id = 'default-1'
+		`, oldAssigned)
+		if err != nil {
+			t.Fatalf("Setup update failed: %v", err)
+		}
+		_, err = db.ExecContext(ctx, `
+			UPDATE user_goal_progress SET is_active = false
+			WHERE user_id = 'assign-user' AND goal_id = 'default-2'
+		`)
+		if err != nil {
+			t.Fatalf("Setup update failed: %v", err)
+		}
+
+		inserted, err := repo.EnsureAssigned(ctx, append(defaults(),
+			&domain.UserGoalProgress{UserID: "assign-user", GoalID: "default-3", ChallengeID: "c1", Namespace: "test"},
+		))
+		if err != nil {
+			t.Fatalf("EnsureAssigned failed: %v", err)
+		}
+		if inserted != 1 {
+			t.Errorf("Expected only default-3 newly assigned, got %d", inserted)
+		}
+
+		p, _ := repo.GetProgress(ctx, "assign-user", "default-1")
+		if p.Progress != 4 || p.Status != domain.GoalStatusInProgress {
+			t.Errorf("Expected progress preserved, got progress=%d status=%s", p.Progress, p.Status)
+		}
+		if p.AssignedAt == nil || !p.AssignedAt.Equal(oldAssigned) {
+			t.Errorf("Expected assigned_at preserved as %v, got %v", oldAssigned, p.AssignedAt)
+		}
+
+		p, _ = repo.GetProgress(ctx, "assign-user", "default-2")
+		if p.IsActive {
+			t.Error("Expected deactivated row to stay inactive")
+		}
+	})
+
+	t.Run("within transaction", func(t *testing.T) {
+		tx, err := repo.BeginTx(ctx)
+		if err != nil {
+			t.Fatalf("BeginTx failed: %v", err)
+		}
+		defer func() { _ = tx.Rollback() }()
+
+		inserted, err := tx.EnsureAssigned(ctx, defaults())
+		if err != nil {
+			t.Fatalf("EnsureAssigned in transaction failed: %v", err)
+		}
+		if inserted != 0 {
+			t.Errorf("Expected 0 newly assigned, got %d", inserted)
+		}
+	})
+}
+
+func TestPostgresGoalRepository_UpsertGoalActiveWithOptions(t *testing.T) {
+	db := setupTestDB(t)
+	if db == nil {
+		return
+	}
+	defer cleanupTestDB(t, db)
+
+	repo := NewPostgresGoalRepository(db)
+	ctx := context.Background()
+
+	goal := func(goalID string, active bool) *domain.UserGoalProgress {
+		return &domain.UserGoalProgress{UserID: "activate-user", GoalID: goalID, ChallengeID: "c1", Namespace: "test", Status: domain.GoalStatusNotStarted, IsActive: active}
+	}
+	assignedAt := func(goalID string) *time.Time {
+		t.Helper()
+		p, err := repo.GetProgress(ctx, "activate-user", goalID)
+		if err != nil || p == nil {
+			t.Fatalf("GetProgress(%s) = %v, %v", goalID, p, err)
+		}
+		return p.AssignedAt
+	}
+
+	oldAssigned := time.Now().Add(-48 * time.Hour).UTC().Truncate(time.Microsecond)
+	backdate := func(goalID string) {
+		t.Helper()
+		_, err := db.ExecContext(ctx, `
+			UPDATE user_goal_progress SET assigned_at = $1 WHERE user_id = 'activate-user' AND goal_id = $2
+		`, oldAssigned, goalID)
+		if err != nil {
+			t.Fatalf("Backdate failed: %v", err)
+		}
+	}
+
+	t.Run("new row is assigned without UpdateAssignedAt", func(t *testing.T) {
+		if err := repo.UpsertGoalActiveWithOptions(ctx, goal("keep", true), ActivationOptions{}); err != nil {
+			t.Fatalf("UpsertGoalActiveWithOptions failed: %v", err)
+		}
+		if assignedAt("keep") == nil {
+			t.Error("Expected assigned_at on a newly activated row")
+		}
+	})
+
+	t.Run("re-activation keeps assigned_at", func(t *testing.T) {
+		backdate("keep")
+		if err := repo.UpsertGoalActiveWithOptions(ctx, goal("keep", true), ActivationOptions{}); err != nil {
+			t.Fatalf("UpsertGoalActiveWithOptions failed: %v", err)
+		}
+		if got := assignedAt("keep"); got == nil || !got.Equal(oldAssigned) {
+			t.Errorf("Expected assigned_at preserved as %v, got %v", oldAssigned, got)
+		}
+
+		// Deactivate and activate again: still preserved
+		if err := repo.UpsertGoalActiveWithOptions(ctx, goal("keep", false), ActivationOptions{}); err != nil {
+			t.Fatalf("UpsertGoalActiveWithOptions failed: %v", err)
+		}
+		if err := repo.UpsertGoalActiveWithOptions(ctx, goal("keep", true), ActivationOptions{}); err != nil {
+			t.Fatalf("UpsertGoalActiveWithOptions failed: %v", err)
+		}
+		if got := assignedAt("keep"); got == nil || !got.Equal(oldAssigned) {
+			t.Errorf("Expected assigned_at preserved as %v, got %v", oldAssigned, got)
+		}
+	})
+
+	t.Run("missing assigned_at is set on activation", func(t *testing.T) {
+		if err := repo.BulkInsert(ctx, []*domain.UserGoalProgress{goal("unassigned", false)}); err != nil {
+			t.Fatalf("BulkInsert failed: %v", err)
+		}
+		if err := repo.UpsertGoalActiveWithOptions(ctx, goal("unassigned", true), ActivationOptions{}); err != nil {
+			t.Fatalf("UpsertGoalActiveWithOptions failed: %v", err)
+		}
+		if assignedAt("unassigned") == nil {
+			t.Error("Expected assigned_at set when none was recorded")
+		}
+	})
+
+	t.Run("UpdateAssignedAt refreshes an active goal", func(t *testing.T) {
+		backdate("keep")
+		tx, err := repo.BeginTx(ctx)
+		if err != nil {
+			t.Fatalf("BeginTx failed: %v", err)
+		}
+		if err := tx.UpsertGoalActiveWithOptions(ctx, goal("keep", true), ActivationOptions{UpdateAssignedAt: true}); err != nil {
+			_ = tx.Rollback()
+			t.Fatalf("UpsertGoalActiveWithOptions in transaction failed: %v", err)
+		}
+		if err := tx.Commit(); err != nil {
+			t.Fatalf("Commit failed: %v", err)
+		}
+		if got := assignedAt("keep"); got == nil || !got.After(oldAssigned) {
+			t.Errorf("Expected assigned_at refreshed after %v, got %v", oldAssigned, got)
+		}
+	})
+
+	t.Run("UpsertGoalActive always refreshes", func(t *testing.T) {
+		backdate("keep")
+		if err := repo.UpsertGoalActive(ctx, goal("keep", true)); err != nil {
+			t.Fatalf("UpsertGoalActive failed: %v", err)
+		}
+		if got := assignedAt("keep"); got == nil || !got.After(oldAssigned) {
+			t.Errorf("Expected assigned_at refreshed after %v, got %v", oldAssigned, got)
+		}
+	})
+}
@@ -0,0 +1,121 @@
+package repository
+
+import (
+	"context"
+	"testing"
+
+	"github.com/AccelByte/extend-challenge-common/pkg/domain"
+)
+
+func TestFilterIncrements_NegativeAttemptDelta(t *testing.T) {
+	repo := NewPostgresGoalRepository(nil)
+
+	_, err := repo.filterIncrements([]ProgressIncrement{
+		{UserID: "user1", GoalID: "ranked-win", Delta: 0, TargetValue: 5, AttemptDelta: -1},
+	})
+	assertInvalidIncrement(t, err)
+}
+
+func TestPostgresGoalRepository_Attempts(t *testing.T) {
+	db := setupTestDB(t)
+	if db == nil {
+		return
+	}
+	defer cleanupTestDB(t, db)
+
+	ctx := context.Background()
+	repo := NewPostgresGoalRepository(db)
+
+	if err := repo.BulkInsert(ctx, []*domain.UserGoalProgress{
+		{UserID: "attempt-user", GoalID: "ranked-win", ChallengeID: "c1", Namespace: "test", Progress: 2, Status: domain.GoalStatusInProgress, IsActive: true},
+		{UserID: "attempt-user", GoalID: "ranked-play", ChallengeID: "c1", Namespace: "test", Status: domain.GoalStatusNotStarted, IsActive: true},
+		{UserID: "attempt-user", GoalID: "ranked-done", ChallengeID: "c1", Namespace: "test", Progress: 5, Status: domain.GoalStatusClaimed, IsActive: true},
+		{UserID: "attempt-user", GoalID: "ranked-off", ChallengeID: "c1", Namespace: "test", Progress: 1, Status: domain.GoalStatusInProgress, IsActive: false},
+		// Written today, so a daily increment is already counted for today
+		{UserID: "attempt-user", GoalID: "daily-ranked", ChallengeID: "c1", Namespace: "test", Progress: 1, Status: domain.GoalStatusInProgress, IsActive: true},
+	}); err != nil {
+		t.Fatalf("BulkInsert failed: %v", err)
+	}
+
+	loss := func(goalID string) ProgressIncrement {
+		return ProgressIncrement{UserID: "attempt-user", GoalID: goalID, ChallengeID: "c1", Namespace: "test", Delta: 0, TargetValue: 5, AttemptDelta: 1}
+	}
+	win := func(goalID string) ProgressIncrement {
+		inc := loss(goalID)
+		inc.Delta = 1
+		return inc
+	}
+	daily := win("daily-ranked")
+	daily.IsDailyIncrement = true
+
+	batches := [][]ProgressIncrement{
+		{loss("ranked-win"), loss("ranked-play"), win("ranked-done"), loss("ranked-off"), daily},
+		{loss("ranked-win"), loss("ranked-play"), loss("ranked-done"), win("ranked-off"), daily},
+		{win("ranked-win")},
+	}
+	for i, batch := range batches {
+		if err := repo.BatchIncrementProgress(ctx, batch); err != nil {
+			t.Fatalf("BatchIncrementProgress batch %d failed: %v", i, err)
+		}
+	}
+
+	tests := []struct {
+		goalID       string
+		wantProgress int64
+		wantStatus   domain.GoalStatus
+		wantAttempts int
+	}{
+		{"ranked-win", 3, domain.GoalStatusInProgress, 3},
+		{"ranked-play", 0, domain.GoalStatusNotStarted, 2},
+		{"ranked-done", 5, domain.GoalStatusClaimed, 0},
+		{"ranked-off", 1, domain.GoalStatusInProgress, 0},
+		{"daily-ranked", 1, domain.GoalStatusInProgress, 2},
+	}
+	for _, tt := range tests {
+		t.Run(tt.goalID, func(t *testing.T) {
+			got, err := repo.GetProgress(ctx, "attempt-user", tt.goalID)
+			if err != nil || got == nil {
+				t.Fatalf("GetProgress = %v, %v", got, err)
+			}
+			if got.Progress != tt.wantProgress || got.Status != tt.wantStatus || got.Attempts != tt.wantAttempts {
+				t.Errorf("got progress %d, status %s, attempts %d; want %d, %s, %d",
+					got.Progress, got.Status, got.Attempts, tt.wantProgress, tt.wantStatus, tt.wantAttempts)
+			}
+			if got.CompletedAt != nil && tt.wantStatus != domain.GoalStatusClaimed {
+				t.Errorf("completed_at = %v, want nil", got.CompletedAt)
+			}
+		})
+	}
+
+	t.Run("in transaction", func(t *testing.T) {
+		tx, err := repo.BeginTx(ctx)
+		if err != nil {
+			t.Fatalf("BeginTx failed: %v", err)
+		}
+		defer func() { _ = tx.Rollback() }()
+
+		// The transactional statement inserts missing rows: a loss creates an unstarted row
+		if err := tx.BatchIncrementProgress(ctx, []ProgressIncrement{loss("ranked-new")}); err != nil {
+			t.Fatalf("BatchIncrementProgress in transaction failed: %v", err)
+		}
+		if err := tx.BatchIncrementProgress(ctx, []ProgressIncrement{loss("ranked-new")}); err != nil {
+			t.Fatalf("BatchIncrementProgress in transaction failed: %v", err)
+		}
+		if err := tx.BatchIncrementProgress(ctx, []ProgressIncrement{loss("ranked-done")}); err != nil {
+			t.Fatalf("BatchIncrementProgress in transaction failed: %v", err)
+		}
+
+		got, err := tx.GetProgress(ctx, "attempt-user", "ranked-new")
+		if err != nil || got == nil {
+			t.Fatalf("GetProgress in transaction = %v, %v", got, err)
+		}
+		if got.Progress != 0 || got.Status != domain.GoalStatusNotStarted || got.Attempts != 2 {
+			t.Errorf("new row = progress %d, status %s, attempts %d; want 0, not_started, 2", got.Progress, got.Status, got.Attempts)
+		}
+
+		claimed, err := tx.GetProgress(ctx, "attempt-user", "ranked-done")
+		if err != nil || claimed == nil || claimed.Attempts != 0 {
+			t.Errorf("claimed row = %+v, %v; want 0 attempts", claimed, err)
+		}
+	})
+}
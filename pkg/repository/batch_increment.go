@@ -0,0 +1,390 @@
+package repository
+
+import (
+	"database/sql"
+
+	"github.com/AccelByte/extend-challenge-common/pkg/domain"
+	"github.com/AccelByte/extend-challenge-common/pkg/errors"
+
+	"github.com/lib/pq"
+)
+
+// CompletionResult identifies a progress row that is in 'completed' status after a batch increment.
+// Returned by BatchIncrementProgressReturning so callers can fan out completion events
+// without re-reading the affected rows.
+type CompletionResult struct {
+	UserID string
+	GoalID string
+	Status domain.GoalStatus
+
+	// NewlyCompleted is true when the row transitioned to 'completed' in this batch. Rows
+	// that were already completed before the batch and received further increments report
+	// false, including rows completed by an earlier statement of the same transaction.
+	NewlyCompleted bool
+}
+
+// BatchIncrementResult is returned by BatchIncrementProgressWithResult.
+type BatchIncrementResult struct {
+	// Completions lists rows in 'completed' status after the batch (see CompletionResult).
+	Completions []CompletionResult
+
+	// SkippedLate counts entries dropped because OccurredAt was older than the max lateness.
+	SkippedLate int
+
+	// SkippedUnassigned counts entries dropped because the user has no active row for the
+	// goal. Only counted with WithRequireAssignmentForIncrements; always 0 otherwise.
+	SkippedUnassigned int
+}
+
+// Placeholders of the delta array and the progress ceiling in the batch increment
+// statements, used by the ceiling skip reports that wrap them.
+const (
+	batchDeltaParam     = "$3"
+	batchCeilingParam   = "$10"
+	txBatchDeltaParam   = "$5"
+	txBatchCeilingParam = "$12"
+)
+
+// batchIncrementProgressQuery is the UPDATE-only batch increment used by PostgresGoalRepository.
+// M3 Phase 9: Changed from UPSERT to UPDATE-only for lazy materialization.
+// Arguments are built by batchIncrementArgs, followed by the per-entry progress caps ($9,
+// see OverflowPolicy), the progress ceiling ($10) and, for
+// batchIncrementProgressChecksumQuery, the config checksum ($11, NULL keeps the row's value).
+//
+// Daily entries bucket by the UTC day of t.event_at (OccurredAt capped at NOW()) and only
+// apply when that day is after the last counted day. last_daily_date records the counted
+// day; rows written before it existed fall back to DATE(updated_at).
+//
+// attempts grows by the entry's attempt delta on every row the statement updates, including
+// daily entries for a day already counted. Entries with a zero delta only count attempts.
+var batchIncrementProgressQuery = `
+		UPDATE user_goal_progress
+		SET
+			progress = CASE
+				-- Daily increment: check if the event's day (UTC) was already counted
+				WHEN t.is_daily = true
+				     AND COALESCE(user_goal_progress.last_daily_date, DATE(user_goal_progress.updated_at AT TIME ZONE 'UTC')) >= DATE(t.event_at AT TIME ZONE 'UTC')
+					THEN user_goal_progress.progress  -- Same day, no increment
+				ELSE
+					-- Different day or regular increment, clamped at the entry's cap (the column clamp when
+					-- unbounded); a row already above the cap is not lowered
+					LEAST(user_goal_progress.progress::BIGINT + t.delta, GREATEST(t.progress_cap, user_goal_progress.progress))
+			END,
+			status = CASE
+				-- Attempt only: progress is unchanged, so is status
+				WHEN t.delta = 0 THEN user_goal_progress.status
+				-- Calculate based on new progress value
+				WHEN t.is_daily = true
+				     AND COALESCE(user_goal_progress.last_daily_date, DATE(user_goal_progress.updated_at AT TIME ZONE 'UTC')) >= DATE(t.event_at AT TIME ZONE 'UTC') THEN
+					-- Same day: status based on current progress
+					CASE WHEN user_goal_progress.progress >= t.target_value THEN 'completed' ELSE 'in_progress' END
+				ELSE
+					-- New day or regular: status based on incremented progress
+					CASE WHEN user_goal_progress.progress::BIGINT + t.delta >= t.target_value THEN 'completed' ELSE 'in_progress' END
+			END,
+			completed_at = CASE
+				WHEN t.delta = 0 THEN user_goal_progress.completed_at  -- Attempt only
+				WHEN t.is_daily = true
+				     AND COALESCE(user_goal_progress.last_daily_date, DATE(user_goal_progress.updated_at AT TIME ZONE 'UTC')) >= DATE(t.event_at AT TIME ZONE 'UTC') THEN
+					user_goal_progress.completed_at  -- Same day, keep existing
+				WHEN user_goal_progress.progress::BIGINT + t.delta >= t.target_value
+				     AND user_goal_progress.completed_at IS NULL THEN
+					NOW()  -- Just completed
+				ELSE
+					user_goal_progress.completed_at  -- Keep existing
+			END,
+			last_daily_date = CASE
+				WHEN t.is_daily = true AND t.delta != 0
+				     AND COALESCE(user_goal_progress.last_daily_date, DATE(user_goal_progress.updated_at AT TIME ZONE 'UTC')) < DATE(t.event_at AT TIME ZONE 'UTC') THEN
+					DATE(t.event_at AT TIME ZONE 'UTC')  -- New day counted
+				ELSE
+					user_goal_progress.last_daily_date
+			END,
+			attempts = user_goal_progress.attempts + t.attempt_delta,
+			updated_at = NOW()
+		FROM (
+			SELECT
+				user_id,
+				goal_id,
+				delta,
+				target_value,
+				is_daily,
+				cooldown_us,
+				LEAST(occurred_at, NOW()) AS event_at,  -- LEAST ignores NULL: no OccurredAt means NOW()
+				attempt_delta,
+				progress_cap
+			FROM UNNEST(
+				$1::VARCHAR(100)[],  -- user_ids
+				$2::VARCHAR(100)[],  -- goal_ids
+				$3::BIGINT[],        -- deltas
+				$4::BIGINT[],        -- target_values
+				$5::BOOLEAN[],       -- is_daily_increment flags
+				$6::BIGINT[],        -- cooldowns in microseconds (0 = no cooldown)
+				$7::TIMESTAMPTZ[],   -- occurred_at (NULL = NOW())
+				$8::INT[],           -- attempt deltas
+				$9::BIGINT[]         -- progress caps
+			) AS t(user_id, goal_id, delta, target_value, is_daily, cooldown_us, occurred_at, attempt_delta, progress_cap)
+		) AS t
+		WHERE user_goal_progress.user_id = t.user_id
+		  AND user_goal_progress.goal_id = t.goal_id
+		  AND user_goal_progress.is_active = true
+		  AND ` + protectedStatusGuard("user_goal_progress.status") + `
+		  -- Cooldown: skip rows still inside the window (row untouched, updated_at not extended)
+		  AND NOT (
+			t.cooldown_us > 0
+			AND user_goal_progress.status != 'not_started'
+			AND user_goal_progress.updated_at > NOW() - t.cooldown_us * INTERVAL '1 microsecond'
+		  )
+		  -- Ceiling: skip rows the delta would push past the progress ceiling
+		  AND ` + progressCeilingGuard("user_goal_progress.progress", "t.delta", batchCeilingParam+"::BIGINT") + `
+	`
+
+// txBatchEntry returns a subquery that reads column from the array argument param for the
+// batch entry of the row being updated on conflict. Entries are matched on (user_id,
+// goal_id): a transactional batch can carry entries of several users for the same goal.
+func txBatchEntry(column, param string) string {
+	return "(SELECT " + column + " FROM UNNEST(" + param + ", $1::VARCHAR(100)[], $2::VARCHAR(100)[]) AS u(" + column + ", uid, gid)" +
+		" WHERE (u.uid, u.gid) = (user_goal_progress.user_id, user_goal_progress.goal_id) LIMIT 1)"
+}
+
+// txBatchIncrementProgressQuery is the upsert-based batch increment used by PostgresTxRepository.
+// Arguments are built by txBatchIncrementArgs, followed by the per-entry progress caps ($11),
+// the progress ceiling ($12) and, for txBatchIncrementProgressChecksumQuery, the config
+// checksum ($13).
+//
+// Inserted daily rows carry the event's UTC day in last_daily_date, so on conflict
+// EXCLUDED.last_daily_date is the event day for daily entries and NULL otherwise; entries
+// with a zero delta only count attempts (EXCLUDED.attempts) and never claim the day.
+var txBatchIncrementProgressQuery = `
+		INSERT INTO user_goal_progress (
+			user_id,
+			goal_id,
+			challenge_id,
+			namespace,
+			progress,
+			status,
+			completed_at,
+			last_daily_date,
+			updated_at,
+			attempts
+		)
+		SELECT
+			t.user_id,
+			t.goal_id,
+			t.challenge_id,
+			t.namespace,
+			LEAST(t.delta, t.progress_cap),
+			initial.status,
+			initial.completed_at,
+			CASE WHEN t.is_daily AND t.delta != 0 THEN DATE(LEAST(t.occurred_at, NOW()) AT TIME ZONE 'UTC') END,
+			NOW(),
+			t.attempt_delta
+		FROM UNNEST(
+			$1::VARCHAR(100)[],
+			$2::VARCHAR(100)[],
+			$3::VARCHAR(100)[],
+			$4::VARCHAR(100)[],
+			$5::BIGINT[],
+			$6::BIGINT[],
+			$7::BOOLEAN[],
+			$8::BIGINT[],
+			$9::TIMESTAMPTZ[],
+			$10::INT[],
+			$11::BIGINT[]
+		) AS t(user_id, goal_id, challenge_id, namespace, delta, target_value, is_daily, cooldown_us, occurred_at, attempt_delta, progress_cap)
+		CROSS JOIN LATERAL (
+			SELECT
+				CASE WHEN t.delta >= t.target_value THEN 'completed' WHEN t.delta = 0 THEN 'not_started' ELSE 'in_progress' END as status,
+				CASE WHEN t.delta >= t.target_value THEN NOW() ELSE NULL END as completed_at
+		) AS initial
+		ON CONFLICT (user_id, goal_id) DO UPDATE SET
+			progress = CASE
+				WHEN ` + txBatchEntry("is_daily", "$7::BOOLEAN[]") + ` = true
+				     AND COALESCE(user_goal_progress.last_daily_date, DATE(user_goal_progress.updated_at AT TIME ZONE 'UTC')) >= EXCLUDED.last_daily_date
+					THEN user_goal_progress.progress
+				ELSE
+					LEAST(user_goal_progress.progress::BIGINT + ` + txBatchEntry("delta", "$5::BIGINT[]") + `, GREATEST(` + txBatchEntry("progress_cap", "$11::BIGINT[]") + `, user_goal_progress.progress))
+			END,
+			status = CASE
+				-- Attempt only: progress is unchanged, so is status
+				WHEN ` + txBatchEntry("delta", "$5::BIGINT[]") + ` = 0
+					THEN user_goal_progress.status
+				WHEN ` + txBatchEntry("is_daily", "$7::BOOLEAN[]") + ` = true
+				     AND COALESCE(user_goal_progress.last_daily_date, DATE(user_goal_progress.updated_at AT TIME ZONE 'UTC')) >= EXCLUDED.last_daily_date THEN
+					CASE WHEN user_goal_progress.progress >= ` + txBatchEntry("target_value", "$6::BIGINT[]") + ` THEN 'completed' ELSE 'in_progress' END
+				ELSE
+					CASE WHEN user_goal_progress.progress::BIGINT + ` + txBatchEntry("delta", "$5::BIGINT[]") + ` >= ` + txBatchEntry("target_value", "$6::BIGINT[]") + ` THEN 'completed' ELSE 'in_progress' END
+			END,
+			completed_at = CASE
+				WHEN ` + txBatchEntry("delta", "$5::BIGINT[]") + ` = 0
+					THEN user_goal_progress.completed_at  -- Attempt only
+				WHEN ` + txBatchEntry("is_daily", "$7::BOOLEAN[]") + ` = true
+				     AND COALESCE(user_goal_progress.last_daily_date, DATE(user_goal_progress.updated_at AT TIME ZONE 'UTC')) >= EXCLUDED.last_daily_date THEN
+					user_goal_progress.completed_at
+				WHEN user_goal_progress.progress::BIGINT + ` + txBatchEntry("delta", "$5::BIGINT[]") + ` >= ` + txBatchEntry("target_value", "$6::BIGINT[]") + ` AND user_goal_progress.completed_at IS NULL THEN
+					NOW()
+				ELSE
+					user_goal_progress.completed_at
+			END,
+			last_daily_date = CASE
+				WHEN COALESCE(user_goal_progress.last_daily_date, DATE(user_goal_progress.updated_at AT TIME ZONE 'UTC')) < EXCLUDED.last_daily_date
+					THEN EXCLUDED.last_daily_date  -- New day counted (NULL for non-daily entries)
+				ELSE user_goal_progress.last_daily_date
+			END,
+			attempts = user_goal_progress.attempts + EXCLUDED.attempts,
+			updated_at = NOW()
+		WHERE ` + protectedStatusGuard("user_goal_progress.status") + `
+		  -- Cooldown: skip rows still inside the window (row untouched, updated_at not extended)
+		  AND NOT (
+			user_goal_progress.status != 'not_started'
+			AND user_goal_progress.updated_at > NOW() - ` + txBatchEntry("cooldown_us", "$8::BIGINT[]") + ` * INTERVAL '1 microsecond'
+		  )
+		  -- Ceiling: skip rows the delta would push past the progress ceiling
+		  AND ` + progressCeilingGuard("user_goal_progress.progress", txBatchEntry("delta", "$5::BIGINT[]"), txBatchCeilingParam+"::BIGINT") + `
+	`
+
+// completionReturningQuery wraps a batch increment statement in a CTE that returns the
+// rows left in 'completed' status and, like ceilingSkipsQuery, the entries the progress
+// ceiling guard skipped; skipped rows carry their progress and delta, completed rows NULL.
+// A row is newly completed when it was not 'completed' before the statement (previous
+// reads the statement's snapshot, so it sees the effects of earlier statements in the same
+// transaction but not this one) and the statement stamped its completed_at. Both batch
+// increments take user IDs in $1 and goal IDs in $2.
+//
+// NOW() alone is not enough: it is fixed for the whole transaction, so a row completed
+// by an earlier statement of the same transaction also carries completed_at = NOW(). The
+// status alone is not either: a concurrent transaction that completes the row after the
+// snapshot leaves its own completed_at, which this statement keeps.
+func completionReturningQuery(incrementQuery, deltaParam, ceilingParam string) string {
+	return `
+		WITH previous AS (
+			SELECT user_id, goal_id, status, progress
+			FROM user_goal_progress
+			WHERE (user_id, goal_id) IN (SELECT * FROM UNNEST($1::VARCHAR(100)[], $2::VARCHAR(100)[]))
+		), incremented AS (` + incrementQuery + `
+			RETURNING user_goal_progress.user_id, user_goal_progress.goal_id,
+			          user_goal_progress.status, user_goal_progress.completed_at
+		)
+		SELECT i.user_id, i.goal_id, i.status,
+		       previous.status IS DISTINCT FROM 'completed' AND COALESCE(i.completed_at = NOW(), false),
+		       NULL::BIGINT, NULL::BIGINT
+		FROM incremented AS i
+		LEFT JOIN previous ON previous.user_id = i.user_id AND previous.goal_id = i.goal_id
+		WHERE i.status = 'completed'
+		UNION ALL
+		SELECT skipped.user_id, skipped.goal_id, NULL, false, skipped.progress, skipped.delta
+		FROM (` + ceilingSkips("previous", deltaParam, ceilingParam) + `
+		) AS skipped(user_id, goal_id, progress, delta)
+	`
+}
+
+// batchIncrementArgs builds the UNNEST array arguments for batchIncrementProgressQuery.
+func batchIncrementArgs(increments []ProgressIncrement) []interface{} {
+	userIDs := make([]string, len(increments))
+	goalIDs := make([]string, len(increments))
+	deltas := make([]int, len(increments))
+	targetValues := make([]int, len(increments))
+	isDailyFlags := make([]bool, len(increments))
+	cooldowns := make([]int64, len(increments))
+	occurredAts := make([]sql.NullTime, len(increments))
+	attemptDeltas := make([]int, len(increments))
+
+	for i, inc := range increments {
+		userIDs[i] = inc.UserID
+		goalIDs[i] = inc.GoalID
+		deltas[i] = inc.Delta
+		targetValues[i] = inc.TargetValue
+		isDailyFlags[i] = inc.IsDailyIncrement
+		cooldowns[i] = inc.Cooldown.Microseconds()
+		if inc.OccurredAt != nil {
+			occurredAts[i] = sql.NullTime{Time: *inc.OccurredAt, Valid: true}
+		}
+		attemptDeltas[i] = inc.AttemptDelta
+	}
+
+	return []interface{}{
+		pq.Array(userIDs),
+		pq.Array(goalIDs),
+		pq.Array(deltas),
+		pq.Array(targetValues),
+		pq.Array(isDailyFlags),
+		pq.Array(cooldowns),
+		pq.Array(occurredAts),
+		pq.Array(attemptDeltas),
+	}
+}
+
+// txBatchIncrementArgs builds the UNNEST array arguments for txBatchIncrementProgressQuery.
+func txBatchIncrementArgs(increments []ProgressIncrement) []interface{} {
+	userIDs := make([]string, len(increments))
+	goalIDs := make([]string, len(increments))
+	challengeIDs := make([]string, len(increments))
+	namespaces := make([]string, len(increments))
+	deltas := make([]int, len(increments))
+	targetValues := make([]int, len(increments))
+	isDailyFlags := make([]bool, len(increments))
+	cooldowns := make([]int64, len(increments))
+	occurredAts := make([]sql.NullTime, len(increments))
+	attemptDeltas := make([]int, len(increments))
+
+	for i, inc := range increments {
+		userIDs[i] = inc.UserID
+		goalIDs[i] = inc.GoalID
+		challengeIDs[i] = inc.ChallengeID
+		namespaces[i] = inc.Namespace
+		deltas[i] = inc.Delta
+		targetValues[i] = inc.TargetValue
+		isDailyFlags[i] = inc.IsDailyIncrement
+		cooldowns[i] = inc.Cooldown.Microseconds()
+		if inc.OccurredAt != nil {
+			occurredAts[i] = sql.NullTime{Time: *inc.OccurredAt, Valid: true}
+		}
+		attemptDeltas[i] = inc.AttemptDelta
+	}
+
+	return []interface{}{
+		pq.Array(userIDs),
+		pq.Array(goalIDs),
+		pq.Array(challengeIDs),
+		pq.Array(namespaces),
+		pq.Array(deltas),
+		pq.Array(targetValues),
+		pq.Array(isDailyFlags),
+		pq.Array(cooldowns),
+		pq.Array(occurredAts),
+		pq.Array(attemptDeltas),
+	}
+}
+
+// scanCompletionResults scans rows produced by completionReturningQuery into the
+// completed rows and descriptions of the entries skipped at the progress ceiling.
+func (r *PostgresGoalRepository) scanCompletionResults(rows *sql.Rows) ([]CompletionResult, []string, error) {
+	results := []CompletionResult{}
+	var skipped []string
+
+	for rows.Next() {
+		var userID, goalID string
+		var status sql.NullString
+		var newlyCompleted bool
+		var progress, delta sql.NullInt64
+		if err := rows.Scan(&userID, &goalID, &status, &newlyCompleted, &progress, &delta); err != nil {
+			return nil, nil, errors.ErrDatabaseError("scan completion result", err)
+		}
+		if progress.Valid {
+			skipped = append(skipped, ceilingViolation(userID, goalID, progress.Int64, delta.Int64, r.progressCeiling))
+			continue
+		}
+		results = append(results, CompletionResult{
+			UserID:         userID,
+			GoalID:         goalID,
+			Status:         domain.GoalStatus(status.String),
+			NewlyCompleted: newlyCompleted,
+		})
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, nil, errors.ErrDatabaseError("iterate completion results", err)
+	}
+
+	return results, skipped, nil
+}
@@ -0,0 +1,108 @@
+package repository
+
+import (
+	"context"
+	"strconv"
+
+	"github.com/AccelByte/extend-challenge-common/pkg/domain"
+	"github.com/AccelByte/extend-challenge-common/pkg/errors"
+)
+
+// participantCursor is the decoded form of a GetChallengeParticipants cursor.
+type participantCursor struct {
+	UserID string `json:"u"`
+	GoalID string `json:"g"`
+}
+
+// encodeParticipantCursor builds the opaque cursor for the last row of a page.
+func encodeParticipantCursor(last *domain.UserGoalProgress) string {
+	return encodeCursor(participantCursor{UserID: last.UserID, GoalID: last.GoalID})
+}
+
+// decodeParticipantCursor parses a cursor produced by encodeParticipantCursor.
+func decodeParticipantCursor(cursor string) (participantCursor, error) {
+	c, err := decodeCursor[participantCursor](cursor)
+	if err != nil {
+		return c, err
+	}
+	if c.UserID == "" || c.GoalID == "" {
+		return c, errors.ErrInvalidCursor("missing key fields")
+	}
+
+	return c, nil
+}
+
+// GetChallengeParticipants returns one page of all users' progress rows for a challenge,
+// ordered by (user_id, goal_id). Served by idx_user_goal_progress_challenge_participants.
+func (r *PostgresGoalRepository) GetChallengeParticipants(ctx context.Context, challengeID string, limit int, cursor string) ([]*domain.UserGoalProgress, string, error) {
+	query := "SELECT " + r.progressColumns() + " FROM user_goal_progress WHERE challenge_id = $1"
+	args := []interface{}{challengeID}
+
+	if cursor != "" {
+		c, err := decodeParticipantCursor(cursor)
+		if err != nil {
+			return nil, "", err
+		}
+		query += " AND (user_id, goal_id) > ($2, $3)"
+		args = append(args, c.UserID, c.GoalID)
+	}
+
+	// Fetch one extra row to know whether another page exists
+	limit = pageLimit(limit)
+	query += " ORDER BY user_id ASC, goal_id ASC LIMIT $" + strconv.Itoa(len(args)+1)
+	args = append(args, limit+1)
+
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, "", errors.ErrDatabaseError("get challenge participants", err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	results, err := r.scanProgressRows(rows)
+	if err != nil {
+		return nil, "", err
+	}
+
+	results, next := pageTail(results, limit, encodeParticipantCursor)
+	return results, next, nil
+}
+
+// GetByChallengeAndStatus returns one page of a challenge's rows in the given status,
+// ordered by (user_id, goal_id) with the same cursor as GetChallengeParticipants.
+// Served by idx_user_goal_progress_challenge_status.
+func (r *PostgresGoalRepository) GetByChallengeAndStatus(ctx context.Context, namespace, challengeID string, status domain.GoalStatus, limit int, cursor string) ([]*domain.UserGoalProgress, string, error) {
+	if !status.IsValid() {
+		return nil, "", errors.ErrValidationFailed("status", "unknown goal status '"+string(status)+"'")
+	}
+
+	query := "SELECT " + r.progressColumns() + " FROM user_goal_progress WHERE namespace = $1 AND challenge_id = $2 AND status = $3"
+	args := []interface{}{namespace, challengeID, string(status)}
+
+	if cursor != "" {
+		c, err := decodeParticipantCursor(cursor)
+		if err != nil {
+			return nil, "", err
+		}
+		query += " AND (user_id, goal_id) > ($4, $5)"
+		args = append(args, c.UserID, c.GoalID)
+	}
+
+	// Fetch one extra row to know whether another page exists
+	limit = pageLimit(limit)
+	query += " ORDER BY user_id ASC, goal_id ASC LIMIT $" + strconv.Itoa(len(args)+1)
+	args = append(args, limit+1)
+
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, "", errors.ErrDatabaseError("get by challenge and status", err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	results, err := r.scanProgressRows(rows)
+	if err != nil {
+		return nil, "", err
+	}
+
+	results, next := pageTail(results, limit, encodeParticipantCursor)
+	return results, next, nil
+}
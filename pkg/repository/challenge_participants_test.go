@@ -0,0 +1,185 @@
+package repository
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/AccelByte/extend-challenge-common/pkg/domain"
+	customerrors "github.com/AccelByte/extend-challenge-common/pkg/errors"
+)
+
+func TestDecodeParticipantCursor(t *testing.T) {
+	valid := encodeParticipantCursor(&domain.UserGoalProgress{UserID: "user-1", GoalID: "goal-1"})
+
+	c, err := decodeParticipantCursor(valid)
+	if err != nil {
+		t.Fatalf("decodeParticipantCursor failed: %v", err)
+	}
+	if c.UserID != "user-1" || c.GoalID != "goal-1" {
+		t.Errorf("Round trip = %+v", c)
+	}
+
+	for _, bad := range []string{"!!!", base64.RawURLEncoding.EncodeToString([]byte(`{"u":"user-1"}`))} {
+		_, err := decodeParticipantCursor(bad)
+		var ce *customerrors.ChallengeError
+		if !errors.As(err, &ce) || ce.Code != customerrors.ErrCodeInvalidCursor {
+			t.Errorf("Expected ErrCodeInvalidCursor for %q, got %v", bad, err)
+		}
+	}
+}
+
+func TestPostgresGoalRepository_GetChallengeParticipants(t *testing.T) {
+	db := setupTestDB(t)
+	if db == nil {
+		return
+	}
+	defer cleanupTestDB(t, db)
+
+	repo := NewPostgresGoalRepository(db)
+	ctx := context.Background()
+
+	row := func(userID, goalID, challengeID string, active bool) *domain.UserGoalProgress {
+		return &domain.UserGoalProgress{UserID: userID, GoalID: goalID, ChallengeID: challengeID, Namespace: "test", Progress: 1, Status: domain.GoalStatusInProgress, IsActive: active}
+	}
+	seed := []*domain.UserGoalProgress{
+		row("u2", "goal-b", "c1", true),
+		row("u1", "goal-b", "c1", true),
+		row("u1", "goal-a", "c1", true),
+		row("u3", "goal-a", "c1", false), // inactive rows are included
+		row("u1", "goal-x", "c2", true),  // other challenge
+	}
+	if err := repo.BulkInsert(ctx, seed); err != nil {
+		t.Fatalf("BulkInsert failed: %v", err)
+	}
+
+	want := []string{"u1/goal-a", "u1/goal-b", "u2/goal-b", "u3/goal-a"}
+
+	t.Run("single page", func(t *testing.T) {
+		page, next, err := repo.GetChallengeParticipants(ctx, "c1", 100, "")
+		if err != nil {
+			t.Fatalf("GetChallengeParticipants failed: %v", err)
+		}
+		if next != "" {
+			t.Errorf("Expected no next cursor, got %q", next)
+		}
+		assertParticipantRows(t, page, want)
+	})
+
+	t.Run("stable pagination", func(t *testing.T) {
+		var all []*domain.UserGoalProgress
+		cursor := ""
+		for pages := 0; ; pages++ {
+			if pages > len(want) {
+				t.Fatal("Pagination did not terminate")
+			}
+			page, next, err := repo.GetChallengeParticipants(ctx, "c1", 3, cursor)
+			if err != nil {
+				t.Fatalf("GetChallengeParticipants failed: %v", err)
+			}
+			all = append(all, page...)
+			if next == "" {
+				break
+			}
+			cursor = next
+		}
+		assertParticipantRows(t, all, want)
+	})
+
+	t.Run("unknown challenge", func(t *testing.T) {
+		page, next, err := repo.GetChallengeParticipants(ctx, "missing", 100, "")
+		if err != nil || len(page) != 0 || next != "" {
+			t.Errorf("Expected empty result, got %d rows, next %q, err %v", len(page), next, err)
+		}
+	})
+}
+
+func TestGetByChallengeAndStatus_UnknownStatus(t *testing.T) {
+	// nil *sql.DB: any SQL would panic, so an invalid status must be rejected first
+	repo := NewPostgresGoalRepository(nil)
+
+	_, _, err := repo.GetByChallengeAndStatus(context.Background(), "test", "c1", "archived", 100, "")
+	assertErrorCode(t, err, customerrors.ErrCodeValidationFailed)
+}
+
+func TestPostgresGoalRepository_GetByChallengeAndStatus(t *testing.T) {
+	db := setupTestDB(t)
+	if db == nil {
+		return
+	}
+	defer cleanupTestDB(t, db)
+
+	repo := NewPostgresGoalRepository(db)
+	ctx := context.Background()
+
+	row := func(userID, goalID, challengeID, namespace string, status domain.GoalStatus) *domain.UserGoalProgress {
+		return &domain.UserGoalProgress{UserID: userID, GoalID: goalID, ChallengeID: challengeID, Namespace: namespace, Progress: 5, Status: status, IsActive: true}
+	}
+	seed := []*domain.UserGoalProgress{
+		row("u3", "goal-a", "c1", "test", domain.GoalStatusClaimed),
+		row("u1", "goal-b", "c1", "test", domain.GoalStatusClaimed),
+		row("u1", "goal-a", "c1", "test", domain.GoalStatusClaimed),
+		row("u2", "goal-a", "c1", "test", domain.GoalStatusCompleted), // other status
+		row("u2", "goal-b", "c2", "test", domain.GoalStatusClaimed),   // other challenge
+		row("u4", "goal-a", "c1", "other", domain.GoalStatusClaimed),  // other namespace
+	}
+	if err := repo.BulkInsert(ctx, seed); err != nil {
+		t.Fatalf("BulkInsert failed: %v", err)
+	}
+
+	want := []string{"u1/goal-a", "u1/goal-b", "u3/goal-a"}
+
+	t.Run("stable pagination", func(t *testing.T) {
+		var all []*domain.UserGoalProgress
+		cursor := ""
+		for pages := 0; ; pages++ {
+			if pages > len(want) {
+				t.Fatal("Pagination did not terminate")
+			}
+			page, next, err := repo.GetByChallengeAndStatus(ctx, "test", "c1", domain.GoalStatusClaimed, 2, cursor)
+			if err != nil {
+				t.Fatalf("GetByChallengeAndStatus failed: %v", err)
+			}
+			all = append(all, page...)
+			if next == "" {
+				break
+			}
+			cursor = next
+		}
+		assertParticipantRows(t, all, want)
+	})
+
+	t.Run("other status", func(t *testing.T) {
+		page, next, err := repo.GetByChallengeAndStatus(ctx, "test", "c1", domain.GoalStatusCompleted, 100, "")
+		if err != nil || next != "" {
+			t.Fatalf("GetByChallengeAndStatus = next %q, err %v", next, err)
+		}
+		assertParticipantRows(t, page, []string{"u2/goal-a"})
+	})
+
+	t.Run("no matches", func(t *testing.T) {
+		page, next, err := repo.GetByChallengeAndStatus(ctx, "test", "c1", domain.GoalStatusClaiming, 100, "")
+		if err != nil || page == nil || len(page) != 0 || next != "" {
+			t.Errorf("Expected empty result, got %v, next %q, err %v", page, next, err)
+		}
+	})
+
+	t.Run("invalid cursor", func(t *testing.T) {
+		_, _, err := repo.GetByChallengeAndStatus(ctx, "test", "c1", domain.GoalStatusClaimed, 100, "!!!")
+		assertErrorCode(t, err, customerrors.ErrCodeInvalidCursor)
+	})
+}
+
+func assertParticipantRows(t *testing.T, rows []*domain.UserGoalProgress, want []string) {
+	t.Helper()
+
+	got := make([]string, len(rows))
+	for i, p := range rows {
+		got[i] = p.UserID + "/" + p.GoalID
+	}
+	if fmt.Sprint(got) != fmt.Sprint(want) {
+		t.Errorf("Rows = %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,128 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/AccelByte/extend-challenge-common/pkg/domain"
+	"github.com/AccelByte/extend-challenge-common/pkg/errors"
+
+	"github.com/lib/pq"
+)
+
+// lockClaimCandidatesQuery locks the user's rows for the requested goals so their
+// eligibility cannot change between the check and the claim.
+const lockClaimCandidatesQuery = `
+	SELECT goal_id, status, claimed_at IS NOT NULL, is_active, forfeited_at IS NOT NULL
+	FROM user_goal_progress
+	WHERE user_id = $1 AND goal_id = ANY($2)
+	FOR UPDATE
+`
+
+// claimAllQuery claims the rows checked by ClaimAllOrNothing.
+const claimAllQuery = `
+	UPDATE user_goal_progress
+	SET status = 'claimed',
+		claimed_at = NOW(),
+		updated_at = NOW()
+	WHERE user_id = $1 AND goal_id = ANY($2)
+	  AND status = 'completed'
+	  AND claimed_at IS NULL
+	  AND is_active = true
+	  AND forfeited_at IS NULL
+`
+
+// claimCandidate is a locked row checked by ClaimAllOrNothing.
+type claimCandidate struct {
+	status    domain.GoalStatus
+	claimed   bool
+	active    bool
+	forfeited bool
+}
+
+// notClaimableReason returns why the row cannot be claimed, or "" if it can.
+func (c claimCandidate) notClaimableReason() string {
+	switch {
+	case c.claimed || c.status == domain.GoalStatusClaimed:
+		return "already claimed"
+	case c.status == domain.GoalStatusClaiming:
+		return "claim in progress"
+	case c.status != domain.GoalStatusCompleted:
+		return fmt.Sprintf("not completed (status %s)", c.status)
+	case !c.active:
+		return "not active"
+	case c.forfeited:
+		return "forfeited"
+	default:
+		return ""
+	}
+}
+
+// ClaimAllOrNothing claims every goal in goalIDs or none of them, for bundled rewards that
+// require several goals together. The rows are locked (FOR UPDATE) and each must be
+// completed, unclaimed, active and not forfeited. If any is not, nothing is written and an
+// errors.ErrNotAllClaimable error lists every offender with its reason; goals without a
+// progress row are offenders too. Duplicate IDs are claimed once.
+//
+// An empty goalIDs fails with errors.ErrValidationFailed.
+func (r *PostgresTxRepository) ClaimAllOrNothing(ctx context.Context, userID string, goalIDs []string) error {
+	if len(goalIDs) == 0 {
+		return errors.ErrValidationFailed("goalIDs", "at least one goal is required")
+	}
+
+	rows, err := r.tx.QueryContext(ctx, lockClaimCandidatesQuery, userID, pq.Array(goalIDs))
+	if err != nil {
+		return errors.ErrDatabaseError("lock claim candidates in transaction", err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	candidates := make(map[string]claimCandidate, len(goalIDs))
+	for rows.Next() {
+		var goalID string
+		var c claimCandidate
+		if err := rows.Scan(&goalID, &c.status, &c.claimed, &c.active, &c.forfeited); err != nil {
+			return errors.ErrDatabaseError("scan claim candidate", err)
+		}
+		candidates[goalID] = c
+	}
+	if err := rows.Err(); err != nil {
+		return errors.ErrDatabaseError("iterate claim candidates", err)
+	}
+
+	var offenders []string
+	distinct := make(map[string]bool, len(goalIDs))
+	for _, goalID := range goalIDs {
+		if distinct[goalID] {
+			continue
+		}
+		distinct[goalID] = true
+
+		c, ok := candidates[goalID]
+		if !ok {
+			offenders = append(offenders, goalID+": no progress")
+			continue
+		}
+		if reason := c.notClaimableReason(); reason != "" {
+			offenders = append(offenders, goalID+": "+reason)
+		}
+	}
+	if len(offenders) > 0 {
+		return errors.ErrNotAllClaimable(offenders)
+	}
+
+	result, err := r.tx.ExecContext(ctx, claimAllQuery, userID, pq.Array(goalIDs))
+	if err != nil {
+		return errors.ErrDatabaseError("claim all or nothing in transaction", err)
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return errors.ErrDatabaseError("check rows affected", err)
+	}
+	if rowsAffected != int64(len(distinct)) {
+		// Unreachable while the rows stay locked; report it rather than claim a partial bundle
+		return errors.ErrDatabaseError("claim all or nothing in transaction",
+			fmt.Errorf("claimed %d of %d goals", rowsAffected, len(distinct)))
+	}
+
+	return nil
+}
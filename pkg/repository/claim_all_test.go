@@ -0,0 +1,136 @@
+package repository
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/AccelByte/extend-challenge-common/pkg/domain"
+	customerrors "github.com/AccelByte/extend-challenge-common/pkg/errors"
+)
+
+func TestClaimCandidate_NotClaimableReason(t *testing.T) {
+	completed := claimCandidate{status: domain.GoalStatusCompleted, active: true}
+
+	tests := []struct {
+		name      string
+		candidate claimCandidate
+		want      string
+	}{
+		{"claimable", completed, ""},
+		{"claimed", claimCandidate{status: domain.GoalStatusClaimed, claimed: true, active: true}, "already claimed"},
+		{"claiming", claimCandidate{status: domain.GoalStatusClaiming, active: true}, "claim in progress"},
+		{"in progress", claimCandidate{status: domain.GoalStatusInProgress, active: true}, "not completed (status in_progress)"},
+		{"inactive", claimCandidate{status: domain.GoalStatusCompleted}, "not active"},
+		{"forfeited", claimCandidate{status: domain.GoalStatusCompleted, active: true, forfeited: true}, "forfeited"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.candidate.notClaimableReason(); got != tt.want {
+				t.Errorf("notClaimableReason() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPostgresTxRepository_ClaimAllOrNothing(t *testing.T) {
+	db := setupTestDB(t)
+	if db == nil {
+		return
+	}
+	defer cleanupTestDB(t, db)
+
+	repo := NewPostgresGoalRepository(db)
+	ctx := context.Background()
+
+	err := repo.BulkInsert(ctx, []*domain.UserGoalProgress{
+		{UserID: "bundle-user", GoalID: "done-1", ChallengeID: "c1", Namespace: "test", Progress: 10, Status: domain.GoalStatusCompleted, IsActive: true},
+		{UserID: "bundle-user", GoalID: "done-2", ChallengeID: "c1", Namespace: "test", Progress: 10, Status: domain.GoalStatusCompleted, IsActive: true},
+		{UserID: "bundle-user", GoalID: "open", ChallengeID: "c1", Namespace: "test", Progress: 3, Status: domain.GoalStatusInProgress, IsActive: true},
+		{UserID: "bundle-user", GoalID: "done-inactive", ChallengeID: "c1", Namespace: "test", Progress: 10, Status: domain.GoalStatusCompleted, IsActive: false},
+	})
+	if err != nil {
+		t.Fatalf("BulkInsert failed: %v", err)
+	}
+	if _, err := db.Exec(`UPDATE user_goal_progress SET completed_at = NOW() WHERE status = 'completed'`); err != nil {
+		t.Fatalf("Set completed_at failed: %v", err)
+	}
+
+	t.Run("mixed eligibility claims nothing", func(t *testing.T) {
+		tx, err := repo.BeginTx(ctx)
+		if err != nil {
+			t.Fatalf("BeginTx failed: %v", err)
+		}
+
+		err = tx.ClaimAllOrNothing(ctx, "bundle-user", []string{"done-1", "open", "done-inactive", "missing"})
+		_ = tx.Rollback()
+		if err == nil {
+			t.Fatal("Expected ClaimAllOrNothing to fail")
+		}
+		assertErrorCode(t, err, customerrors.ErrCodeNotAllClaimable)
+		for _, want := range []string{"open: not completed", "done-inactive: not active", "missing: no progress"} {
+			if !strings.Contains(err.Error(), want) {
+				t.Errorf("error %v should contain %q", err, want)
+			}
+		}
+		if strings.Contains(err.Error(), "done-1") {
+			t.Errorf("error %v should not list the claimable goal", err)
+		}
+
+		assertStatus(t, repo, "bundle-user", "done-1", domain.GoalStatusCompleted)
+	})
+
+	t.Run("all eligible claims every goal", func(t *testing.T) {
+		tx, err := repo.BeginTx(ctx)
+		if err != nil {
+			t.Fatalf("BeginTx failed: %v", err)
+		}
+		if err := tx.ClaimAllOrNothing(ctx, "bundle-user", []string{"done-1", "done-2", "done-1"}); err != nil {
+			_ = tx.Rollback()
+			t.Fatalf("ClaimAllOrNothing failed: %v", err)
+		}
+		if err := tx.Commit(); err != nil {
+			t.Fatalf("Commit failed: %v", err)
+		}
+
+		assertStatus(t, repo, "bundle-user", "done-1", domain.GoalStatusClaimed)
+		assertStatus(t, repo, "bundle-user", "done-2", domain.GoalStatusClaimed)
+	})
+
+	t.Run("claimed goals cannot be claimed again", func(t *testing.T) {
+		tx, err := repo.BeginTx(ctx)
+		if err != nil {
+			t.Fatalf("BeginTx failed: %v", err)
+		}
+		defer func() { _ = tx.Rollback() }()
+
+		err = tx.ClaimAllOrNothing(ctx, "bundle-user", []string{"done-1"})
+		assertErrorCode(t, err, customerrors.ErrCodeNotAllClaimable)
+	})
+
+	t.Run("empty list", func(t *testing.T) {
+		tx, err := repo.BeginTx(ctx)
+		if err != nil {
+			t.Fatalf("BeginTx failed: %v", err)
+		}
+		defer func() { _ = tx.Rollback() }()
+
+		assertErrorCode(t, tx.ClaimAllOrNothing(ctx, "bundle-user", nil), customerrors.ErrCodeValidationFailed)
+	})
+}
+
+func TestDualWriteGoalRepository_ClaimAllOrNothing(t *testing.T) {
+	ctx := context.Background()
+	repo, _, _, _, log := newDualWriteFixture(t, WriteBothReadOld)
+
+	tx, err := repo.BeginTx(ctx)
+	if err != nil {
+		t.Fatalf("BeginTx failed: %v", err)
+	}
+	if err := tx.ClaimAllOrNothing(ctx, "u1", []string{"kills", "wins"}); err != nil {
+		t.Fatalf("ClaimAllOrNothing failed: %v", err)
+	}
+
+	assertCalls(t, log.calls, "old BeginTx", "new BeginTx", "old ClaimAllOrNothing", "new ClaimAllOrNothing")
+}
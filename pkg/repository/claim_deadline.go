@@ -0,0 +1,111 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/AccelByte/extend-challenge-common/pkg/errors"
+
+	"github.com/lib/pq"
+)
+
+// markAsClaimedQuery claims a completed goal that is neither claimed nor forfeited.
+// $3 is the claim deadline in microseconds (<= 0 = no deadline): the claim must happen
+// within it of completed_at. A row without completed_at is never treated as expired.
+const markAsClaimedQuery = `
+	UPDATE user_goal_progress
+	SET status = 'claimed',
+		claimed_at = NOW(),
+		updated_at = NOW()
+	WHERE user_id = $1 AND goal_id = $2
+	AND status = 'completed'
+	AND claimed_at IS NULL
+	AND forfeited_at IS NULL
+	AND ($3::BIGINT <= 0 OR COALESCE(completed_at + $3::BIGINT * INTERVAL '1 microsecond' >= NOW(), true))
+`
+
+// claimWindowExpiredQuery reports whether a failed claim was rejected because the goal
+// was forfeited or its claim deadline ($3, microseconds) has passed.
+const claimWindowExpiredQuery = `
+	SELECT EXISTS (
+		SELECT 1 FROM user_goal_progress
+		WHERE user_id = $1 AND goal_id = $2
+		  AND status = 'completed'
+		  AND (forfeited_at IS NOT NULL
+		       OR ($3::BIGINT > 0 AND completed_at + $3::BIGINT * INTERVAL '1 microsecond' < NOW()))
+	)
+`
+
+// forfeitExpiredClaimsQuery marks up to $3 completed rows whose claim deadline has passed.
+// Rows locked by a concurrent claim are skipped and picked up by a later sweep.
+const forfeitExpiredClaimsQuery = `
+	WITH expired AS (
+		SELECT p.user_id, p.goal_id
+		FROM user_goal_progress p
+		JOIN UNNEST($1::VARCHAR(100)[], $2::BIGINT[]) AS d(goal_id, deadline_us)
+		  ON p.goal_id = d.goal_id
+		WHERE p.status = 'completed'
+		  AND p.forfeited_at IS NULL
+		  AND p.completed_at + d.deadline_us * INTERVAL '1 microsecond' < NOW()
+		LIMIT $3
+		FOR UPDATE OF p SKIP LOCKED
+	)
+	UPDATE user_goal_progress
+	SET forfeited_at = NOW(),
+		updated_at = NOW()
+	FROM expired
+	WHERE user_goal_progress.user_id = expired.user_id
+	  AND user_goal_progress.goal_id = expired.goal_id
+`
+
+// ClaimForfeitRepository enforces claim deadlines across all users. It is a background
+// job API and is only available on the connection pool.
+type ClaimForfeitRepository interface {
+	// ForfeitExpiredClaims sets forfeited_at on completed, unclaimed rows whose goal has a
+	// deadline in goalDeadlines and whose completed_at is older than that deadline, so
+	// they stop being claimable. Goals missing from the map or with a deadline <= 0 are
+	// not touched. Rows are updated batchSize at a time, each batch in its own statement,
+	// until none are left. Returns the number of rows forfeited; re-running is a no-op.
+	ForfeitExpiredClaims(ctx context.Context, goalDeadlines map[string]time.Duration, batchSize int) (int64, error)
+}
+
+// ForfeitExpiredClaims forfeits completed goals whose claim deadline has passed.
+func (r *PostgresGoalRepository) ForfeitExpiredClaims(ctx context.Context, goalDeadlines map[string]time.Duration, batchSize int) (int64, error) {
+	if batchSize <= 0 {
+		return 0, errors.ErrValidationFailed("batchSize", "must be positive")
+	}
+
+	goalIDs := make([]string, 0, len(goalDeadlines))
+	deadlines := make([]int64, 0, len(goalDeadlines))
+	for goalID, deadline := range goalDeadlines {
+		if deadline > 0 {
+			goalIDs = append(goalIDs, goalID)
+			deadlines = append(deadlines, deadline.Microseconds())
+		}
+	}
+	if len(goalIDs) == 0 {
+		return 0, nil
+	}
+
+	if err := r.acquireGate(); err != nil {
+		return 0, err
+	}
+	defer r.releaseGate()
+
+	var total int64
+	for {
+		result, err := r.db.ExecContext(ctx, forfeitExpiredClaimsQuery, pq.Array(goalIDs), pq.Array(deadlines), batchSize)
+		if err != nil {
+			return total, errors.ErrDatabaseError("forfeit expired claims", err)
+		}
+		affected, err := result.RowsAffected()
+		if err != nil {
+			return total, errors.ErrDatabaseError("check rows affected", err)
+		}
+
+		total += affected
+		if affected < int64(batchSize) {
+			return total, nil
+		}
+	}
+}
@@ -0,0 +1,199 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/AccelByte/extend-challenge-common/pkg/domain"
+	customerrors "github.com/AccelByte/extend-challenge-common/pkg/errors"
+)
+
+const claimWindow = 7 * 24 * time.Hour
+
+// insertCompletedAgo inserts completed rows for user and backdates completed_at by ago.
+func insertCompletedAgo(t *testing.T, db *sql.DB, repo *PostgresGoalRepository, userID string, ago time.Duration, goalIDs ...string) {
+	t.Helper()
+	ctx := context.Background()
+
+	rows := make([]*domain.UserGoalProgress, len(goalIDs))
+	for i, goalID := range goalIDs {
+		rows[i] = &domain.UserGoalProgress{UserID: userID, GoalID: goalID, ChallengeID: "c1", Namespace: "test", Progress: 10, Status: domain.GoalStatusCompleted, IsActive: true}
+	}
+	if err := repo.BulkInsert(ctx, rows); err != nil {
+		t.Fatalf("BulkInsert failed: %v", err)
+	}
+
+	for _, goalID := range goalIDs {
+		_, err := db.ExecContext(ctx, `
+			UPDATE user_goal_progress SET completed_at = NOW() - $3::BIGINT * INTERVAL '1 microsecond'
+			WHERE user_id = $1 AND goal_id = $2
+		`, userID, goalID, ago.Microseconds())
+		if err != nil {
+			t.Fatalf("Backdate failed: %v", err)
+		}
+	}
+}
+
+func assertClaimWindowExpired(t *testing.T, err error) {
+	t.Helper()
+	var ce *customerrors.ChallengeError
+	if !errors.As(err, &ce) || ce.Code != customerrors.ErrCodeClaimWindowExpired {
+		t.Errorf("Expected ErrCodeClaimWindowExpired, got %v", err)
+	}
+}
+
+func TestPostgresGoalRepository_MarkAsClaimedWithDeadline(t *testing.T) {
+	db := setupTestDB(t)
+	if db == nil {
+		return
+	}
+	defer cleanupTestDB(t, db)
+
+	ctx := context.Background()
+	repo := NewPostgresGoalRepository(db)
+
+	insertCompletedAgo(t, db, repo, "deadline-user", claimWindow-time.Minute, "inside")
+	insertCompletedAgo(t, db, repo, "deadline-user", claimWindow+time.Minute, "outside", "no-deadline")
+
+	t.Run("just inside the window", func(t *testing.T) {
+		if err := repo.MarkAsClaimedWithDeadline(ctx, "deadline-user", "inside", claimWindow); err != nil {
+			t.Fatalf("MarkAsClaimedWithDeadline failed: %v", err)
+		}
+	})
+
+	t.Run("just outside the window", func(t *testing.T) {
+		assertClaimWindowExpired(t, repo.MarkAsClaimedWithDeadline(ctx, "deadline-user", "outside", claimWindow))
+
+		progress, err := repo.GetProgress(ctx, "deadline-user", "outside")
+		if err != nil {
+			t.Fatalf("GetProgress failed: %v", err)
+		}
+		if progress.Status != domain.GoalStatusCompleted || progress.ClaimedAt != nil {
+			t.Errorf("Expired claim changed the row: %+v", progress)
+		}
+	})
+
+	t.Run("goal without deadline", func(t *testing.T) {
+		if err := repo.MarkAsClaimed(ctx, "deadline-user", "no-deadline"); err != nil {
+			t.Fatalf("MarkAsClaimed failed: %v", err)
+		}
+	})
+
+	t.Run("already claimed is not reported as expired", func(t *testing.T) {
+		err := repo.MarkAsClaimedWithDeadline(ctx, "deadline-user", "no-deadline", claimWindow)
+		var ce *customerrors.ChallengeError
+		if !errors.As(err, &ce) || ce.Code != customerrors.ErrCodeGoalNotCompleted {
+			t.Errorf("Expected ErrCodeGoalNotCompleted, got %v", err)
+		}
+	})
+
+	t.Run("in transaction", func(t *testing.T) {
+		tx, err := repo.BeginTx(ctx)
+		if err != nil {
+			t.Fatalf("BeginTx failed: %v", err)
+		}
+		defer func() { _ = tx.Rollback() }()
+
+		assertClaimWindowExpired(t, tx.MarkAsClaimedWithDeadline(ctx, "deadline-user", "outside", claimWindow))
+	})
+}
+
+func TestPostgresGoalRepository_ForfeitExpiredClaims(t *testing.T) {
+	db := setupTestDB(t)
+	if db == nil {
+		return
+	}
+	defer cleanupTestDB(t, db)
+
+	ctx := context.Background()
+	repo := NewPostgresGoalRepository(db)
+
+	insertCompletedAgo(t, db, repo, "forfeit-user", claimWindow+time.Hour, "weekly", "short")
+	insertCompletedAgo(t, db, repo, "forfeit-user-2", claimWindow+time.Hour, "weekly", "no-deadline")
+	insertCompletedAgo(t, db, repo, "forfeit-user-3", claimWindow+time.Hour, "weekly")
+	insertCompletedAgo(t, db, repo, "forfeit-fresh", time.Hour, "weekly")
+	insertCompletedAgo(t, db, repo, "forfeit-claimed", claimWindow+time.Hour, "weekly")
+	if _, err := db.ExecContext(ctx, `
+		UPDATE user_goal_progress SET status = 'claimed', claimed_at = NOW() WHERE user_id = 'forfeit-claimed'
+	`); err != nil {
+		t.Fatalf("Claim setup failed: %v", err)
+	}
+
+	deadlines := map[string]time.Duration{
+		"weekly":      claimWindow,
+		"short":       time.Minute,
+		"no-deadline": 0,
+	}
+
+	// Batches of 2 cover the 4 expired rows in more than one statement
+	forfeited, err := repo.ForfeitExpiredClaims(ctx, deadlines, 2)
+	if err != nil {
+		t.Fatalf("ForfeitExpiredClaims failed: %v", err)
+	}
+	if forfeited != 4 {
+		t.Errorf("Forfeited %d rows, want 4", forfeited)
+	}
+
+	again, err := repo.ForfeitExpiredClaims(ctx, deadlines, 2)
+	if err != nil {
+		t.Fatalf("ForfeitExpiredClaims rerun failed: %v", err)
+	}
+	if again != 0 {
+		t.Errorf("Rerun forfeited %d rows, want 0", again)
+	}
+
+	for _, tc := range []struct {
+		userID, goalID string
+		wantForfeited  bool
+	}{
+		{"forfeit-user", "weekly", true},
+		{"forfeit-user", "short", true},
+		{"forfeit-user-2", "weekly", true},
+		{"forfeit-user-3", "weekly", true},
+		{"forfeit-user-2", "no-deadline", false},
+		{"forfeit-fresh", "weekly", false},
+		{"forfeit-claimed", "weekly", false},
+	} {
+		progress, err := repo.GetProgress(ctx, tc.userID, tc.goalID)
+		if err != nil {
+			t.Fatalf("GetProgress failed: %v", err)
+		}
+		if progress.IsForfeited() != tc.wantForfeited {
+			t.Errorf("%s/%s forfeited = %v, want %v", tc.userID, tc.goalID, progress.IsForfeited(), tc.wantForfeited)
+		}
+	}
+
+	// Forfeited rows are no longer claimable, even without a deadline passed in
+	assertClaimWindowExpired(t, repo.MarkAsClaimed(ctx, "forfeit-user", "weekly"))
+
+	eligibility, err := repo.GetClaimEligibility(ctx, "forfeit-user", "weekly", nil)
+	if err != nil {
+		t.Fatalf("GetClaimEligibility failed: %v", err)
+	}
+	if eligibility.Eligible || eligibility.Reason != ClaimReasonForfeited {
+		t.Errorf("Eligibility = %v/%q, want ineligible/%q", eligibility.Eligible, eligibility.Reason, ClaimReasonForfeited)
+	}
+
+	if err := repo.MarkAsClaimed(ctx, "forfeit-user-2", "no-deadline"); err != nil {
+		t.Errorf("MarkAsClaimed for goal without deadline failed: %v", err)
+	}
+}
+
+func TestPostgresGoalRepository_ForfeitExpiredClaims_Validation(t *testing.T) {
+	repo := NewPostgresGoalRepository(nil)
+
+	_, err := repo.ForfeitExpiredClaims(context.Background(), map[string]time.Duration{"g": time.Hour}, 0)
+	var ce *customerrors.ChallengeError
+	if !errors.As(err, &ce) || ce.Code != customerrors.ErrCodeValidationFailed {
+		t.Errorf("Expected ErrCodeValidationFailed, got %v", err)
+	}
+
+	// No goal has a deadline: nothing to do, and no database access
+	n, err := repo.ForfeitExpiredClaims(context.Background(), map[string]time.Duration{"g": 0}, 10)
+	if err != nil || n != 0 {
+		t.Errorf("ForfeitExpiredClaims() = %d, %v; want 0, nil", n, err)
+	}
+}
@@ -0,0 +1,138 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/AccelByte/extend-challenge-common/pkg/domain"
+	"github.com/AccelByte/extend-challenge-common/pkg/errors"
+
+	"github.com/lib/pq"
+)
+
+// ClaimIneligibleReason explains why a goal cannot be claimed.
+type ClaimIneligibleReason string
+
+const (
+	// ClaimReasonNone is used when the goal is eligible.
+	ClaimReasonNone ClaimIneligibleReason = ""
+
+	// ClaimReasonNotFound means the user has no progress row for the goal.
+	ClaimReasonNotFound ClaimIneligibleReason = "not_found"
+
+	// ClaimReasonAlreadyClaimed means the goal's reward was already claimed.
+	ClaimReasonAlreadyClaimed ClaimIneligibleReason = "already_claimed"
+
+	// ClaimReasonClaimInProgress means the goal is reserved by a claim that has not finished.
+	ClaimReasonClaimInProgress ClaimIneligibleReason = "claim_in_progress"
+
+	// ClaimReasonNotCompleted means the goal has not met its requirement yet.
+	ClaimReasonNotCompleted ClaimIneligibleReason = "not_completed"
+
+	// ClaimReasonForfeited means the claim deadline passed and the reward was forfeited.
+	ClaimReasonForfeited ClaimIneligibleReason = "forfeited"
+
+	// ClaimReasonPrereqIncomplete means at least one prerequisite is not completed, claiming
+	// or claimed.
+	ClaimReasonPrereqIncomplete ClaimIneligibleReason = "prereq_incomplete"
+)
+
+// ClaimEligibility is the result of a claim precheck for one goal.
+type ClaimEligibility struct {
+	// Progress is the goal's progress row, or nil if it does not exist.
+	Progress *domain.UserGoalProgress
+
+	// PrerequisiteStatuses maps each requested prerequisite goal ID to its status.
+	// Prerequisites without a progress row are reported as not_started.
+	PrerequisiteStatuses map[string]domain.GoalStatus
+
+	// Eligible is true when the goal is completed, unclaimed, not forfeited, and every
+	// prerequisite is completed or claimed. Reason is ClaimReasonNone in that case.
+	Eligible bool
+	Reason   ClaimIneligibleReason
+}
+
+// getClaimEligibility fetches the goal and its prerequisites in one query and evaluates eligibility.
+// When forUpdate is true the fetched rows are locked for the rest of the transaction.
+func (r *PostgresGoalRepository) getClaimEligibility(ctx context.Context, q queryer, operation string, forUpdate bool, userID, goalID string, prerequisiteGoalIDs []string) (*ClaimEligibility, error) {
+	goalIDs := make([]string, 0, len(prerequisiteGoalIDs)+1)
+	goalIDs = append(goalIDs, goalID)
+	goalIDs = append(goalIDs, prerequisiteGoalIDs...)
+
+	query := "SELECT " + r.progressColumns() + " FROM user_goal_progress WHERE user_id = $1 AND goal_id = ANY($2)"
+	if forUpdate {
+		query += " FOR UPDATE"
+	}
+
+	rows, err := q.QueryContext(ctx, query, userID, pq.Array(goalIDs))
+	if err != nil {
+		return nil, errors.ErrDatabaseError(operation, err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	progresses, err := r.scanProgressRows(rows)
+	if err != nil {
+		return nil, err
+	}
+
+	byGoalID := make(map[string]*domain.UserGoalProgress, len(progresses))
+	for _, p := range progresses {
+		byGoalID[p.GoalID] = p
+	}
+
+	return evaluateClaimEligibility(byGoalID, goalID, prerequisiteGoalIDs), nil
+}
+
+// evaluateClaimEligibility computes eligibility from the fetched rows.
+// Reasons are checked in order: not found, already claimed, claim in progress, not
+// completed, forfeited, prerequisites.
+func evaluateClaimEligibility(byGoalID map[string]*domain.UserGoalProgress, goalID string, prerequisiteGoalIDs []string) *ClaimEligibility {
+	result := &ClaimEligibility{
+		Progress:             byGoalID[goalID],
+		PrerequisiteStatuses: make(map[string]domain.GoalStatus, len(prerequisiteGoalIDs)),
+	}
+
+	prereqsMet := true
+	for _, prereqID := range prerequisiteGoalIDs {
+		status := domain.GoalStatusNotStarted
+		if p := byGoalID[prereqID]; p != nil {
+			status = p.Status
+		}
+		result.PrerequisiteStatuses[prereqID] = status
+
+		if status != domain.GoalStatusCompleted && status != domain.GoalStatusClaiming && status != domain.GoalStatusClaimed {
+			prereqsMet = false
+		}
+	}
+
+	switch {
+	case result.Progress == nil:
+		result.Reason = ClaimReasonNotFound
+	case result.Progress.IsClaimed():
+		result.Reason = ClaimReasonAlreadyClaimed
+	case result.Progress.IsClaiming():
+		result.Reason = ClaimReasonClaimInProgress
+	case !result.Progress.IsCompleted():
+		result.Reason = ClaimReasonNotCompleted
+	case result.Progress.IsForfeited():
+		result.Reason = ClaimReasonForfeited
+	case !prereqsMet:
+		result.Reason = ClaimReasonPrereqIncomplete
+	default:
+		result.Eligible = true
+	}
+
+	return result
+}
+
+// GetClaimEligibility checks whether a goal can be claimed, fetching the goal and its
+// prerequisites in a single query.
+func (r *PostgresGoalRepository) GetClaimEligibility(ctx context.Context, userID, goalID string, prerequisiteGoalIDs []string) (*ClaimEligibility, error) {
+	return r.getClaimEligibility(ctx, r.db, "get claim eligibility", false, userID, goalID, prerequisiteGoalIDs)
+}
+
+// GetClaimEligibility checks whether a goal can be claimed within a transaction.
+// The goal and prerequisite rows are locked (SELECT ... FOR UPDATE) so the claim can
+// proceed in the same transaction without racing concurrent claims.
+func (r *PostgresTxRepository) GetClaimEligibility(ctx context.Context, userID, goalID string, prerequisiteGoalIDs []string) (*ClaimEligibility, error) {
+	return r.parent.getClaimEligibility(ctx, r.tx, "get claim eligibility in transaction", true, userID, goalID, prerequisiteGoalIDs)
+}
@@ -0,0 +1,176 @@
+package repository
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/AccelByte/extend-challenge-common/pkg/domain"
+)
+
+func TestEvaluateClaimEligibility(t *testing.T) {
+	prereqs := []string{"prereq-1", "prereq-2", "prereq-3"}
+
+	rows := func(target domain.GoalStatus, prereqStatuses ...domain.GoalStatus) map[string]*domain.UserGoalProgress {
+		byGoalID := map[string]*domain.UserGoalProgress{}
+		if target != "" {
+			byGoalID["goal"] = &domain.UserGoalProgress{GoalID: "goal", Status: target}
+		}
+		for i, status := range prereqStatuses {
+			if status != "" {
+				byGoalID[prereqs[i]] = &domain.UserGoalProgress{GoalID: prereqs[i], Status: status}
+			}
+		}
+		return byGoalID
+	}
+
+	tests := []struct {
+		name       string
+		byGoalID   map[string]*domain.UserGoalProgress
+		wantOK     bool
+		wantReason ClaimIneligibleReason
+	}{
+		{
+			name:     "eligible with three prerequisites",
+			byGoalID: rows(domain.GoalStatusCompleted, domain.GoalStatusCompleted, domain.GoalStatusClaimed, domain.GoalStatusCompleted),
+			wantOK:   true,
+		},
+		{
+			name:       "goal not found",
+			byGoalID:   rows("", domain.GoalStatusCompleted, domain.GoalStatusCompleted, domain.GoalStatusCompleted),
+			wantReason: ClaimReasonNotFound,
+		},
+		{
+			name:       "goal already claimed",
+			byGoalID:   rows(domain.GoalStatusClaimed, domain.GoalStatusCompleted, domain.GoalStatusCompleted, domain.GoalStatusCompleted),
+			wantReason: ClaimReasonAlreadyClaimed,
+		},
+		{
+			name:       "goal claim in progress",
+			byGoalID:   rows(domain.GoalStatusClaiming, domain.GoalStatusCompleted, domain.GoalStatusCompleted, domain.GoalStatusCompleted),
+			wantReason: ClaimReasonClaimInProgress,
+		},
+		{
+			name:     "claiming prerequisite counts as met",
+			byGoalID: rows(domain.GoalStatusCompleted, domain.GoalStatusClaiming, domain.GoalStatusCompleted, domain.GoalStatusCompleted),
+			wantOK:   true,
+		},
+		{
+			name:       "goal not completed",
+			byGoalID:   rows(domain.GoalStatusInProgress, domain.GoalStatusCompleted, domain.GoalStatusCompleted, domain.GoalStatusCompleted),
+			wantReason: ClaimReasonNotCompleted,
+		},
+		{
+			name: "goal forfeited",
+			byGoalID: func() map[string]*domain.UserGoalProgress {
+				byGoalID := rows(domain.GoalStatusCompleted, domain.GoalStatusCompleted, domain.GoalStatusCompleted, domain.GoalStatusCompleted)
+				byGoalID["goal"].ForfeitedAt = &time.Time{}
+				return byGoalID
+			}(),
+			wantReason: ClaimReasonForfeited,
+		},
+		{
+			name:       "prerequisite in progress",
+			byGoalID:   rows(domain.GoalStatusCompleted, domain.GoalStatusCompleted, domain.GoalStatusInProgress, domain.GoalStatusCompleted),
+			wantReason: ClaimReasonPrereqIncomplete,
+		},
+		{
+			name:       "prerequisite without progress row",
+			byGoalID:   rows(domain.GoalStatusCompleted, domain.GoalStatusCompleted, domain.GoalStatusCompleted, ""),
+			wantReason: ClaimReasonPrereqIncomplete,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := evaluateClaimEligibility(tt.byGoalID, "goal", prereqs)
+
+			if got.Eligible != tt.wantOK {
+				t.Errorf("Eligible = %v, want %v", got.Eligible, tt.wantOK)
+			}
+			if got.Reason != tt.wantReason {
+				t.Errorf("Reason = %q, want %q", got.Reason, tt.wantReason)
+			}
+			if len(got.PrerequisiteStatuses) != len(prereqs) {
+				t.Errorf("Expected %d prerequisite statuses, got %d", len(prereqs), len(got.PrerequisiteStatuses))
+			}
+		})
+	}
+
+	t.Run("missing prerequisite reported as not_started", func(t *testing.T) {
+		got := evaluateClaimEligibility(rows(domain.GoalStatusCompleted), "goal", prereqs)
+
+		if got.PrerequisiteStatuses["prereq-2"] != domain.GoalStatusNotStarted {
+			t.Errorf("Expected not_started, got %q", got.PrerequisiteStatuses["prereq-2"])
+		}
+	})
+
+	t.Run("no prerequisites", func(t *testing.T) {
+		got := evaluateClaimEligibility(rows(domain.GoalStatusCompleted), "goal", nil)
+
+		if !got.Eligible {
+			t.Errorf("Expected eligible without prerequisites, got reason %q", got.Reason)
+		}
+	})
+}
+
+func TestPostgresGoalRepository_GetClaimEligibility(t *testing.T) {
+	db := setupTestDB(t)
+	if db == nil {
+		return
+	}
+	defer cleanupTestDB(t, db)
+
+	repo := NewPostgresGoalRepository(db)
+	ctx := context.Background()
+
+	err := repo.BulkInsert(ctx, []*domain.UserGoalProgress{
+		{UserID: "elig-user", GoalID: "goal", ChallengeID: "c1", Namespace: "test", Progress: 10, Status: domain.GoalStatusCompleted, IsActive: true},
+		{UserID: "elig-user", GoalID: "prereq-1", ChallengeID: "c1", Namespace: "test", Progress: 10, Status: domain.GoalStatusCompleted, IsActive: true},
+		{UserID: "elig-user", GoalID: "prereq-2", ChallengeID: "c1", Namespace: "test", Progress: 10, Status: domain.GoalStatusCompleted, IsActive: true},
+		{UserID: "elig-user", GoalID: "prereq-3", ChallengeID: "c1", Namespace: "test", Progress: 2, Status: domain.GoalStatusInProgress, IsActive: true},
+		{UserID: "other-user", GoalID: "prereq-3", ChallengeID: "c1", Namespace: "test", Progress: 10, Status: domain.GoalStatusCompleted, IsActive: true},
+	})
+	if err != nil {
+		t.Fatalf("BulkInsert failed: %v", err)
+	}
+
+	t.Run("pool: happy path with completed prerequisites", func(t *testing.T) {
+		got, err := repo.GetClaimEligibility(ctx, "elig-user", "goal", []string{"prereq-1", "prereq-2"})
+		if err != nil {
+			t.Fatalf("GetClaimEligibility failed: %v", err)
+		}
+		if !got.Eligible || got.Progress == nil || got.Progress.GoalID != "goal" {
+			t.Errorf("Expected eligible with target row, got %+v", got)
+		}
+	})
+
+	t.Run("tx: incomplete prerequisite", func(t *testing.T) {
+		tx, err := repo.BeginTx(ctx)
+		if err != nil {
+			t.Fatalf("BeginTx failed: %v", err)
+		}
+		defer func() { _ = tx.Rollback() }()
+
+		got, err := tx.GetClaimEligibility(ctx, "elig-user", "goal", []string{"prereq-1", "prereq-2", "prereq-3"})
+		if err != nil {
+			t.Fatalf("GetClaimEligibility in transaction failed: %v", err)
+		}
+		if got.Eligible || got.Reason != ClaimReasonPrereqIncomplete {
+			t.Errorf("Expected prereq_incomplete, got eligible=%v reason=%q", got.Eligible, got.Reason)
+		}
+		if got.PrerequisiteStatuses["prereq-3"] != domain.GoalStatusInProgress {
+			t.Errorf("Expected prereq-3 in_progress (not another user's row), got %q", got.PrerequisiteStatuses["prereq-3"])
+		}
+	})
+
+	t.Run("pool: goal not found", func(t *testing.T) {
+		got, err := repo.GetClaimEligibility(ctx, "elig-user", "missing-goal", nil)
+		if err != nil {
+			t.Fatalf("GetClaimEligibility failed: %v", err)
+		}
+		if got.Reason != ClaimReasonNotFound || got.Progress != nil {
+			t.Errorf("Expected not_found, got %+v", got)
+		}
+	})
+}
@@ -0,0 +1,200 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/AccelByte/extend-challenge-common/pkg/errors"
+)
+
+// DefaultClaimReservationTTL is how long ReserveClaim holds a goal by default.
+const DefaultClaimReservationTTL = 5 * time.Minute
+
+// reserveClaimQuery moves a claimable completed goal to 'claiming' for $4 microseconds.
+// $3 is the claim deadline in microseconds, checked as in markAsClaimedQuery. The status
+// guard makes concurrent reservations of one row yield exactly one winner.
+const reserveClaimQuery = `
+	UPDATE user_goal_progress
+	SET status = 'claiming',
+		reserved_until = NOW() + $4::BIGINT * INTERVAL '1 microsecond',
+		updated_at = NOW()
+	WHERE user_id = $1 AND goal_id = $2
+	AND status = 'completed'
+	AND claimed_at IS NULL
+	AND forfeited_at IS NULL
+	AND ($3::BIGINT <= 0 OR COALESCE(completed_at + $3::BIGINT * INTERVAL '1 microsecond' >= NOW(), true))
+`
+
+// claimInProgressQuery reports whether a failed reservation lost to an existing one.
+const claimInProgressQuery = `
+	SELECT EXISTS (
+		SELECT 1 FROM user_goal_progress
+		WHERE user_id = $1 AND goal_id = $2 AND status = 'claiming'
+	)
+`
+
+// confirmClaimQuery finalizes a goal whose reservation has not lapsed.
+const confirmClaimQuery = `
+	UPDATE user_goal_progress
+	SET status = 'claimed',
+		claimed_at = NOW(),
+		reserved_until = NULL,
+		updated_at = NOW()
+	WHERE user_id = $1 AND goal_id = $2
+	AND status = 'claiming'
+	AND reserved_until >= NOW()
+`
+
+// cancelClaimQuery returns a reserved goal to 'completed' so it can be claimed again.
+const cancelClaimQuery = `
+	UPDATE user_goal_progress
+	SET status = 'completed',
+		reserved_until = NULL,
+		updated_at = NOW()
+	WHERE user_id = $1 AND goal_id = $2
+	AND status = 'claiming'
+`
+
+// releaseExpiredClaimReservationsQuery returns up to $1 lapsed reservations to 'completed'.
+// Rows locked by a concurrent confirm or cancel are skipped and picked up by a later sweep.
+const releaseExpiredClaimReservationsQuery = `
+	WITH expired AS (
+		SELECT user_id, goal_id
+		FROM user_goal_progress
+		WHERE status = 'claiming'
+		  AND reserved_until < NOW()
+		LIMIT $1
+		FOR UPDATE SKIP LOCKED
+	)
+	UPDATE user_goal_progress
+	SET status = 'completed',
+		reserved_until = NULL,
+		updated_at = NOW()
+	FROM expired
+	WHERE user_goal_progress.user_id = expired.user_id
+	  AND user_goal_progress.goal_id = expired.goal_id
+	  AND user_goal_progress.status = 'claiming'
+`
+
+// ClaimReservationSweeper releases claim reservations whose holder never confirmed or
+// cancelled them (e.g. a crashed worker). It is a background job API and is only
+// available on the connection pool.
+type ClaimReservationSweeper interface {
+	// ReleaseExpiredClaimReservations returns 'claiming' rows whose reservation has lapsed
+	// to 'completed', batchSize rows per statement until none are left, so they can be
+	// claimed again. Returns the number of rows released; re-running is a no-op.
+	ReleaseExpiredClaimReservations(ctx context.Context, batchSize int) (int64, error)
+}
+
+func (e executor) reserveClaim(ctx context.Context, userID, goalID string, claimDeadline time.Duration) error {
+	deadlineUs := claimDeadline.Microseconds()
+	rowsAffected, err := e.execRowsAffected(ctx, e.op("reserve claim"), reserveClaimQuery,
+		userID, goalID, deadlineUs, e.repo.claimReservationTTL.Microseconds())
+	if err != nil {
+		return err
+	}
+	if rowsAffected == 1 {
+		return nil
+	}
+
+	var inProgress bool
+	if err := e.q.QueryRowContext(ctx, claimInProgressQuery, userID, goalID).Scan(&inProgress); err != nil {
+		return errors.ErrDatabaseError(e.op("check claim reservation"), err)
+	}
+	if inProgress {
+		return errors.ErrClaimInProgress(goalID)
+	}
+
+	var expired bool
+	if err := e.q.QueryRowContext(ctx, claimWindowExpiredQuery, userID, goalID, deadlineUs).Scan(&expired); err != nil {
+		return errors.ErrDatabaseError(e.op("check claim window"), err)
+	}
+	if expired {
+		return errors.ErrClaimWindowExpired(goalID)
+	}
+
+	// Missing, not completed, or already claimed, as in markAsClaimed
+	return errors.ErrGoalNotCompleted(goalID)
+}
+
+// finishClaim runs a confirm or cancel transition and reports a missing reservation.
+func (e executor) finishClaim(ctx context.Context, operation, query, userID, goalID string) error {
+	rowsAffected, err := e.execRowsAffected(ctx, e.op(operation), query, userID, goalID)
+	if err != nil {
+		return err
+	}
+	if rowsAffected == 0 {
+		return errors.ErrClaimNotReserved(goalID)
+	}
+	return nil
+}
+
+// ReserveClaim reserves a completed goal for a claim.
+func (r *PostgresGoalRepository) ReserveClaim(ctx context.Context, userID, goalID string) error {
+	return r.exec().reserveClaim(ctx, userID, goalID, 0)
+}
+
+// ReserveClaimWithDeadline reserves a goal completed within claimDeadline for a claim.
+func (r *PostgresGoalRepository) ReserveClaimWithDeadline(ctx context.Context, userID, goalID string, claimDeadline time.Duration) error {
+	return r.exec().reserveClaim(ctx, userID, goalID, claimDeadline)
+}
+
+// ReserveClaim reserves a completed goal for a claim within a transaction.
+func (r *PostgresTxRepository) ReserveClaim(ctx context.Context, userID, goalID string) error {
+	return r.exec().reserveClaim(ctx, userID, goalID, 0)
+}
+
+// ReserveClaimWithDeadline reserves a goal completed within claimDeadline for a claim
+// within a transaction.
+func (r *PostgresTxRepository) ReserveClaimWithDeadline(ctx context.Context, userID, goalID string, claimDeadline time.Duration) error {
+	return r.exec().reserveClaim(ctx, userID, goalID, claimDeadline)
+}
+
+// ConfirmClaim finalizes a reserved claim to 'claimed'.
+func (r *PostgresGoalRepository) ConfirmClaim(ctx context.Context, userID, goalID string) error {
+	return r.exec().finishClaim(ctx, "confirm claim", confirmClaimQuery, userID, goalID)
+}
+
+// ConfirmClaim finalizes a reserved claim to 'claimed' within a transaction.
+func (r *PostgresTxRepository) ConfirmClaim(ctx context.Context, userID, goalID string) error {
+	return r.exec().finishClaim(ctx, "confirm claim", confirmClaimQuery, userID, goalID)
+}
+
+// CancelClaim returns a reserved goal to 'completed'.
+func (r *PostgresGoalRepository) CancelClaim(ctx context.Context, userID, goalID string) error {
+	return r.exec().finishClaim(ctx, "cancel claim", cancelClaimQuery, userID, goalID)
+}
+
+// CancelClaim returns a reserved goal to 'completed' within a transaction.
+func (r *PostgresTxRepository) CancelClaim(ctx context.Context, userID, goalID string) error {
+	return r.exec().finishClaim(ctx, "cancel claim", cancelClaimQuery, userID, goalID)
+}
+
+// ReleaseExpiredClaimReservations returns lapsed claim reservations to 'completed'.
+func (r *PostgresGoalRepository) ReleaseExpiredClaimReservations(ctx context.Context, batchSize int) (int64, error) {
+	if batchSize <= 0 {
+		return 0, errors.ErrValidationFailed("batchSize", "must be positive")
+	}
+
+	if err := r.acquireGate(); err != nil {
+		return 0, err
+	}
+	defer r.releaseGate()
+
+	var total int64
+	for {
+		result, err := r.db.ExecContext(ctx, releaseExpiredClaimReservationsQuery, batchSize)
+		if err != nil {
+			return total, errors.ErrDatabaseError("release expired claim reservations", err)
+		}
+		affected, err := result.RowsAffected()
+		if err != nil {
+			return total, errors.ErrDatabaseError("check rows affected", err)
+		}
+
+		total += affected
+		if affected < int64(batchSize) {
+			return total, nil
+		}
+	}
+}
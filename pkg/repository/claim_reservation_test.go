@@ -0,0 +1,287 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/AccelByte/extend-challenge-common/pkg/domain"
+	customerrors "github.com/AccelByte/extend-challenge-common/pkg/errors"
+)
+
+func assertErrorCode(t *testing.T, err error, code customerrors.Code) {
+	t.Helper()
+	var ce *customerrors.ChallengeError
+	if !errors.As(err, &ce) || ce.Code != code {
+		t.Errorf("Expected %s, got %v", code, err)
+	}
+}
+
+func assertStatus(t *testing.T, repo *PostgresGoalRepository, userID, goalID string, want domain.GoalStatus) *domain.UserGoalProgress {
+	t.Helper()
+	progress, err := repo.GetProgress(context.Background(), userID, goalID)
+	if err != nil {
+		t.Fatalf("GetProgress failed: %v", err)
+	}
+	if progress.Status != want {
+		t.Errorf("%s/%s status = %q, want %q", userID, goalID, progress.Status, want)
+	}
+	return progress
+}
+
+func TestPostgresGoalRepository_ClaimReservation(t *testing.T) {
+	db := setupTestDB(t)
+	if db == nil {
+		return
+	}
+	defer cleanupTestDB(t, db)
+
+	ctx := context.Background()
+	repo := NewPostgresGoalRepository(db)
+
+	insertCompletedAgo(t, db, repo, "reserve-user", time.Hour, "confirm", "cancel", "expired-window", "lapsed")
+	if err := repo.BulkInsert(ctx, []*domain.UserGoalProgress{
+		{UserID: "reserve-user", GoalID: "in-progress", ChallengeID: "c1", Namespace: "test", Progress: 1, Status: domain.GoalStatusInProgress, IsActive: true},
+	}); err != nil {
+		t.Fatalf("BulkInsert failed: %v", err)
+	}
+
+	t.Run("reserve then confirm", func(t *testing.T) {
+		if err := repo.ReserveClaim(ctx, "reserve-user", "confirm"); err != nil {
+			t.Fatalf("ReserveClaim failed: %v", err)
+		}
+		assertStatus(t, repo, "reserve-user", "confirm", domain.GoalStatusClaiming)
+
+		assertErrorCode(t, repo.ReserveClaim(ctx, "reserve-user", "confirm"), customerrors.ErrCodeClaimInProgress)
+		assertErrorCode(t, repo.MarkAsClaimed(ctx, "reserve-user", "confirm"), customerrors.ErrCodeGoalNotCompleted)
+
+		if err := repo.ConfirmClaim(ctx, "reserve-user", "confirm"); err != nil {
+			t.Fatalf("ConfirmClaim failed: %v", err)
+		}
+		progress := assertStatus(t, repo, "reserve-user", "confirm", domain.GoalStatusClaimed)
+		if progress.ClaimedAt == nil {
+			t.Error("ConfirmClaim did not set claimed_at")
+		}
+
+		assertErrorCode(t, repo.ConfirmClaim(ctx, "reserve-user", "confirm"), customerrors.ErrCodeClaimNotReserved)
+		assertErrorCode(t, repo.CancelClaim(ctx, "reserve-user", "confirm"), customerrors.ErrCodeClaimNotReserved)
+	})
+
+	t.Run("reserve then cancel", func(t *testing.T) {
+		if err := repo.ReserveClaim(ctx, "reserve-user", "cancel"); err != nil {
+			t.Fatalf("ReserveClaim failed: %v", err)
+		}
+		if err := repo.CancelClaim(ctx, "reserve-user", "cancel"); err != nil {
+			t.Fatalf("CancelClaim failed: %v", err)
+		}
+		progress := assertStatus(t, repo, "reserve-user", "cancel", domain.GoalStatusCompleted)
+		if progress.ClaimedAt != nil {
+			t.Error("CancelClaim set claimed_at")
+		}
+
+		// The goal can be claimed again
+		if err := repo.ReserveClaim(ctx, "reserve-user", "cancel"); err != nil {
+			t.Errorf("ReserveClaim after cancel failed: %v", err)
+		}
+	})
+
+	t.Run("rejected reservations", func(t *testing.T) {
+		assertErrorCode(t, repo.ReserveClaimWithDeadline(ctx, "reserve-user", "expired-window", time.Minute), customerrors.ErrCodeClaimWindowExpired)
+		assertErrorCode(t, repo.ReserveClaim(ctx, "reserve-user", "in-progress"), customerrors.ErrCodeGoalNotCompleted)
+		assertErrorCode(t, repo.ReserveClaim(ctx, "reserve-user", "missing"), customerrors.ErrCodeGoalNotCompleted)
+		assertErrorCode(t, repo.ConfirmClaim(ctx, "reserve-user", "in-progress"), customerrors.ErrCodeClaimNotReserved)
+	})
+
+	t.Run("confirm after the reservation lapsed", func(t *testing.T) {
+		if err := repo.ReserveClaim(ctx, "reserve-user", "lapsed"); err != nil {
+			t.Fatalf("ReserveClaim failed: %v", err)
+		}
+		if _, err := db.ExecContext(ctx, `
+			UPDATE user_goal_progress SET reserved_until = NOW() - INTERVAL '1 second'
+			WHERE user_id = 'reserve-user' AND goal_id = 'lapsed'
+		`); err != nil {
+			t.Fatalf("Backdate failed: %v", err)
+		}
+
+		// Not yet released by the sweeper, but too late to finalize
+		assertErrorCode(t, repo.ConfirmClaim(ctx, "reserve-user", "lapsed"), customerrors.ErrCodeClaimNotReserved)
+		progress := assertStatus(t, repo, "reserve-user", "lapsed", domain.GoalStatusClaiming)
+		if progress.ClaimedAt != nil {
+			t.Error("Confirm after expiry set claimed_at")
+		}
+
+		// The holder can still hand the goal back
+		if err := repo.CancelClaim(ctx, "reserve-user", "lapsed"); err != nil {
+			t.Fatalf("CancelClaim after expiry failed: %v", err)
+		}
+		assertStatus(t, repo, "reserve-user", "lapsed", domain.GoalStatusCompleted)
+	})
+
+	t.Run("eligibility reports claim in progress", func(t *testing.T) {
+		eligibility, err := repo.GetClaimEligibility(ctx, "reserve-user", "cancel", nil)
+		if err != nil {
+			t.Fatalf("GetClaimEligibility failed: %v", err)
+		}
+		if eligibility.Eligible || eligibility.Reason != ClaimReasonClaimInProgress {
+			t.Errorf("Eligibility = %v/%q, want ineligible/%q", eligibility.Eligible, eligibility.Reason, ClaimReasonClaimInProgress)
+		}
+	})
+
+	t.Run("in transaction", func(t *testing.T) {
+		tx, err := repo.BeginTx(ctx)
+		if err != nil {
+			t.Fatalf("BeginTx failed: %v", err)
+		}
+		defer func() { _ = tx.Rollback() }()
+
+		if err := tx.ConfirmClaim(ctx, "reserve-user", "cancel"); err != nil {
+			t.Fatalf("ConfirmClaim in transaction failed: %v", err)
+		}
+		assertErrorCode(t, tx.ReserveClaim(ctx, "reserve-user", "cancel"), customerrors.ErrCodeGoalNotCompleted)
+	})
+}
+
+func TestPostgresGoalRepository_ReserveClaim_Concurrent(t *testing.T) {
+	db := setupTestDB(t)
+	if db == nil {
+		return
+	}
+	defer cleanupTestDB(t, db)
+
+	ctx := context.Background()
+	repo := NewPostgresGoalRepository(db)
+	insertCompletedAgo(t, db, repo, "race-user", time.Hour, "race-goal")
+
+	const workers = 10
+	errs := make([]error, workers)
+	var wg sync.WaitGroup
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			errs[i] = repo.ReserveClaim(ctx, "race-user", "race-goal")
+		}(i)
+	}
+	wg.Wait()
+
+	winners := 0
+	for _, err := range errs {
+		if err == nil {
+			winners++
+			continue
+		}
+		assertErrorCode(t, err, customerrors.ErrCodeClaimInProgress)
+	}
+	if winners != 1 {
+		t.Errorf("%d workers reserved the goal, want exactly 1", winners)
+	}
+}
+
+func TestPostgresGoalRepository_ReleaseExpiredClaimReservations(t *testing.T) {
+	db := setupTestDB(t)
+	if db == nil {
+		return
+	}
+	defer cleanupTestDB(t, db)
+
+	ctx := context.Background()
+	repo := NewPostgresGoalRepository(db)
+
+	insertCompletedAgo(t, db, repo, "sweep-user", time.Hour, "lapsed-1", "lapsed-2", "lapsed-3", "held")
+	for _, goalID := range []string{"lapsed-1", "lapsed-2", "lapsed-3", "held"} {
+		if err := repo.ReserveClaim(ctx, "sweep-user", goalID); err != nil {
+			t.Fatalf("ReserveClaim(%s) failed: %v", goalID, err)
+		}
+	}
+	if _, err := db.ExecContext(ctx, `
+		UPDATE user_goal_progress SET reserved_until = NOW() - INTERVAL '1 second'
+		WHERE user_id = 'sweep-user' AND goal_id LIKE 'lapsed-%'
+	`); err != nil {
+		t.Fatalf("Backdate failed: %v", err)
+	}
+
+	// Batches of 2 cover the 3 lapsed rows in more than one statement
+	released, err := repo.ReleaseExpiredClaimReservations(ctx, 2)
+	if err != nil {
+		t.Fatalf("ReleaseExpiredClaimReservations failed: %v", err)
+	}
+	if released != 3 {
+		t.Errorf("Released %d rows, want 3", released)
+	}
+
+	again, err := repo.ReleaseExpiredClaimReservations(ctx, 2)
+	if err != nil {
+		t.Fatalf("ReleaseExpiredClaimReservations rerun failed: %v", err)
+	}
+	if again != 0 {
+		t.Errorf("Rerun released %d rows, want 0", again)
+	}
+
+	assertStatus(t, repo, "sweep-user", "lapsed-1", domain.GoalStatusCompleted)
+	assertStatus(t, repo, "sweep-user", "held", domain.GoalStatusClaiming)
+	assertErrorCode(t, repo.ConfirmClaim(ctx, "sweep-user", "lapsed-2"), customerrors.ErrCodeClaimNotReserved)
+
+	_, err = repo.ReleaseExpiredClaimReservations(ctx, 0)
+	assertErrorCode(t, err, customerrors.ErrCodeValidationFailed)
+}
+
+func TestPostgresGoalRepository_ClaimingRowsIgnoreProgressWrites(t *testing.T) {
+	db := setupTestDB(t)
+	if db == nil {
+		return
+	}
+	defer cleanupTestDB(t, db)
+
+	ctx := context.Background()
+	repo := NewPostgresGoalRepository(db)
+
+	insertCompletedAgo(t, db, repo, "frozen-user", time.Hour, "frozen")
+	if err := repo.ReserveClaim(ctx, "frozen-user", "frozen"); err != nil {
+		t.Fatalf("ReserveClaim failed: %v", err)
+	}
+
+	writes := map[string]func() error{
+		"IncrementProgress": func() error {
+			return repo.IncrementProgress(ctx, "frozen-user", "frozen", "c1", "test", 5, 10, false)
+		},
+		"BatchIncrementProgress": func() error {
+			return repo.BatchIncrementProgress(ctx, []ProgressIncrement{
+				{UserID: "frozen-user", GoalID: "frozen", ChallengeID: "c1", Namespace: "test", Delta: 5, TargetValue: 10},
+			})
+		},
+		"UpsertProgress": func() error {
+			return repo.UpsertProgress(ctx, &domain.UserGoalProgress{
+				UserID: "frozen-user", GoalID: "frozen", ChallengeID: "c1", Namespace: "test",
+				Progress: 0, Status: domain.GoalStatusInProgress, IsActive: true,
+			})
+		},
+		"BatchUpsertProgressWithCOPY": func() error {
+			return repo.BatchUpsertProgressWithCOPY(ctx, []*domain.UserGoalProgress{{
+				UserID: "frozen-user", GoalID: "frozen", ChallengeID: "c1", Namespace: "test",
+				Progress: 0, Status: domain.GoalStatusInProgress,
+			}})
+		},
+	}
+
+	for name, write := range writes {
+		t.Run(name, func(t *testing.T) {
+			if err := write(); err != nil {
+				t.Fatalf("%s failed: %v", name, err)
+			}
+			progress := assertStatus(t, repo, "frozen-user", "frozen", domain.GoalStatusClaiming)
+			if progress.Progress != 10 {
+				t.Errorf("Progress = %d, want 10 (unchanged)", progress.Progress)
+			}
+		})
+	}
+
+	preview, err := repo.PreviewBatchIncrement(ctx, []ProgressIncrement{{UserID: "frozen-user", GoalID: "frozen", Delta: 1}})
+	if err != nil {
+		t.Fatalf("PreviewBatchIncrement failed: %v", err)
+	}
+	if preview.BlockedClaimed != 1 {
+		t.Errorf("BlockedClaimed = %d, want 1", preview.BlockedClaimed)
+	}
+}
@@ -0,0 +1,61 @@
+package repository
+
+// CoalesceIncrements merges increments for the same (UserID, GoalID) into one entry whose
+// Delta is the sum of the merged deltas, so a popular goal receiving many events inside a
+// flush window costs one row update instead of one contended update per event.
+// AttemptDelta is summed the same way.
+// Call it on the buffered window before BatchIncrementProgress.
+//
+// Only plain entries are merged: entries with IsDailyIncrement, Cooldown, OccurredAt,
+// MaxDeltaPerEvent or OverflowPolicy set depend on per-event semantics and are passed
+// through unchanged.
+// A merged entry keeps the position, ChallengeID and Namespace of the first entry for
+// its key and the TargetValue of the last. Summed deltas are clamped to
+// ±DefaultMaxIncrementDelta so the merged entry passes the default validation;
+// repositories configured with a lower WithMaxIncrementDelta or WithMaxDeltaPerEvent
+// apply those caps to the merged entry.
+//
+// Merging also matters for correctness: a batch statement applies at most one entry per
+// row, so duplicate plain keys within one BatchIncrementProgress call would otherwise
+// lose increments. The caller's slice is not modified.
+func CoalesceIncrements(increments []ProgressIncrement) []ProgressIncrement {
+	type key struct{ userID, goalID string }
+
+	merged := make([]ProgressIncrement, 0, len(increments))
+	sums := make(map[key]int64)
+	positions := make(map[key]int)
+
+	for _, inc := range increments {
+		if inc.IsDailyIncrement || inc.Cooldown != 0 || inc.OccurredAt != nil || inc.MaxDeltaPerEvent != 0 || inc.OverflowPolicy != (OverflowPolicy{}) {
+			merged = append(merged, inc)
+			continue
+		}
+
+		k := key{inc.UserID, inc.GoalID}
+		sums[k] += int64(inc.Delta)
+
+		pos, ok := positions[k]
+		if !ok {
+			positions[k] = len(merged)
+			merged = append(merged, inc)
+			continue
+		}
+		merged[pos].Delta = clampCoalescedDelta(sums[k])
+		merged[pos].AttemptDelta += inc.AttemptDelta
+		merged[pos].TargetValue = inc.TargetValue
+	}
+
+	return merged
+}
+
+// clampCoalescedDelta limits a summed delta to ±DefaultMaxIncrementDelta.
+func clampCoalescedDelta(sum int64) int {
+	switch {
+	case sum > DefaultMaxIncrementDelta:
+		return DefaultMaxIncrementDelta
+	case sum < -DefaultMaxIncrementDelta:
+		return -DefaultMaxIncrementDelta
+	default:
+		return int(sum)
+	}
+}
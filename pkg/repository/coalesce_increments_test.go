@@ -0,0 +1,105 @@
+package repository
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/AccelByte/extend-challenge-common/pkg/domain"
+)
+
+func TestCoalesceIncrements(t *testing.T) {
+	occurred := time.Now()
+
+	input := []ProgressIncrement{
+		{UserID: "u1", GoalID: "g1", ChallengeID: "c1", Namespace: "ns", Delta: 2, TargetValue: 10, AttemptDelta: 1},
+		{UserID: "u1", GoalID: "g2", Delta: 1, TargetValue: 5},
+		{UserID: "u1", GoalID: "g1", ChallengeID: "c2", Delta: 3, TargetValue: 20, AttemptDelta: 1},
+		{UserID: "u2", GoalID: "g1", Delta: 4, TargetValue: 10},
+		{UserID: "u1", GoalID: "g1", Delta: -1, TargetValue: 20},
+		{UserID: "u1", GoalID: "g1", Delta: 1, TargetValue: 10, IsDailyIncrement: true},
+		{UserID: "u1", GoalID: "g1", Delta: 1, TargetValue: 10, Cooldown: time.Minute},
+		{UserID: "u1", GoalID: "g1", Delta: 1, TargetValue: 10, OccurredAt: &occurred},
+		{UserID: "u1", GoalID: "g1", Delta: 1, TargetValue: 10, MaxDeltaPerEvent: 1},
+	}
+	original := append([]ProgressIncrement(nil), input...)
+
+	got := CoalesceIncrements(input)
+
+	want := []ProgressIncrement{
+		{UserID: "u1", GoalID: "g1", ChallengeID: "c1", Namespace: "ns", Delta: 4, TargetValue: 20, AttemptDelta: 2},
+		{UserID: "u1", GoalID: "g2", Delta: 1, TargetValue: 5},
+		{UserID: "u2", GoalID: "g1", Delta: 4, TargetValue: 10},
+		input[5], input[6], input[7], input[8],
+	}
+	if len(got) != len(want) {
+		t.Fatalf("CoalesceIncrements() returned %d entries, want %d: %+v", len(got), len(want), got)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("entry %d = %+v, want %+v", i, got[i], want[i])
+		}
+	}
+
+	for i := range input {
+		if input[i] != original[i] {
+			t.Errorf("input entry %d modified: %+v", i, input[i])
+		}
+	}
+}
+
+func TestCoalesceIncrements_ClampsSum(t *testing.T) {
+	inc := ProgressIncrement{UserID: "u1", GoalID: "g1", Delta: DefaultMaxIncrementDelta, TargetValue: 10}
+
+	got := CoalesceIncrements([]ProgressIncrement{inc, inc, inc})
+	if len(got) != 1 || got[0].Delta != DefaultMaxIncrementDelta {
+		t.Errorf("CoalesceIncrements() = %+v, want one entry with Delta %d", got, DefaultMaxIncrementDelta)
+	}
+
+	inc.Delta = -DefaultMaxIncrementDelta
+	got = CoalesceIncrements([]ProgressIncrement{inc, inc})
+	if len(got) != 1 || got[0].Delta != -DefaultMaxIncrementDelta {
+		t.Errorf("CoalesceIncrements() = %+v, want one entry with Delta %d", got, -DefaultMaxIncrementDelta)
+	}
+}
+
+func TestCoalesceIncrements_Empty(t *testing.T) {
+	if got := CoalesceIncrements(nil); len(got) != 0 {
+		t.Errorf("CoalesceIncrements(nil) = %+v, want empty", got)
+	}
+}
+
+func TestPostgresGoalRepository_BatchIncrementProgress_Coalesced(t *testing.T) {
+	db := setupTestDB(t)
+	if db == nil {
+		return
+	}
+	defer cleanupTestDB(t, db)
+
+	ctx := context.Background()
+	repo := NewPostgresGoalRepository(db)
+
+	err := repo.BulkInsert(ctx, []*domain.UserGoalProgress{
+		{UserID: "coalesce-user", GoalID: "hot-goal", ChallengeID: "c1", Namespace: "test", Status: domain.GoalStatusNotStarted, IsActive: true},
+	})
+	if err != nil {
+		t.Fatalf("BulkInsert failed: %v", err)
+	}
+
+	increments := make([]ProgressIncrement, 5)
+	for i := range increments {
+		increments[i] = ProgressIncrement{UserID: "coalesce-user", GoalID: "hot-goal", ChallengeID: "c1", Namespace: "test", Delta: 2, TargetValue: 100}
+	}
+
+	if err := repo.BatchIncrementProgress(ctx, CoalesceIncrements(increments)); err != nil {
+		t.Fatalf("BatchIncrementProgress failed: %v", err)
+	}
+
+	progress, err := repo.GetProgress(ctx, "coalesce-user", "hot-goal")
+	if err != nil {
+		t.Fatalf("GetProgress failed: %v", err)
+	}
+	if progress.Progress != 10 {
+		t.Errorf("Progress = %d, want 10 (every increment applied)", progress.Progress)
+	}
+}
@@ -0,0 +1,33 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/AccelByte/extend-challenge-common/pkg/domain"
+)
+
+// getCompletedBetweenQuery selects a user's goals completed within an inclusive window.
+// Claimed goals keep their completed_at, so they are included alongside completed ones.
+// The column list is added at call time.
+const getCompletedBetweenQuery = `
+	FROM user_goal_progress
+	WHERE user_id = $1
+	  AND status IN ('completed', 'claimed')
+	  AND completed_at BETWEEN $2 AND $3
+	ORDER BY completed_at DESC
+`
+
+func (e executor) getCompletedBetween(ctx context.Context, userID string, from, to time.Time) ([]*domain.UserGoalProgress, error) {
+	return e.queryProgress(ctx, e.op("get completed between"), "SELECT "+e.repo.progressColumns()+getCompletedBetweenQuery, userID, from, to)
+}
+
+// GetCompletedBetween retrieves goals a user completed within [from, to], newest first.
+func (r *PostgresGoalRepository) GetCompletedBetween(ctx context.Context, userID string, from, to time.Time) ([]*domain.UserGoalProgress, error) {
+	return r.exec().getCompletedBetween(ctx, userID, from, to)
+}
+
+// GetCompletedBetween retrieves goals a user completed within [from, to] within a transaction.
+func (r *PostgresTxRepository) GetCompletedBetween(ctx context.Context, userID string, from, to time.Time) ([]*domain.UserGoalProgress, error) {
+	return r.exec().getCompletedBetween(ctx, userID, from, to)
+}
@@ -0,0 +1,83 @@
+package repository
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/AccelByte/extend-challenge-common/pkg/domain"
+)
+
+func TestPostgresGoalRepository_GetCompletedBetween(t *testing.T) {
+	db := setupTestDB(t)
+	if db == nil {
+		return
+	}
+	defer cleanupTestDB(t, db)
+
+	repo := NewPostgresGoalRepository(db)
+	ctx := context.Background()
+
+	now := time.Now().UTC().Truncate(time.Microsecond)
+	daysAgo := func(days int) *time.Time {
+		ts := now.Add(-time.Duration(days) * 24 * time.Hour)
+		return &ts
+	}
+
+	err := repo.BulkInsert(ctx, []*domain.UserGoalProgress{
+		{UserID: "recap-user", GoalID: "completed-1d", ChallengeID: "c1", Namespace: "test", Progress: 10, Status: domain.GoalStatusCompleted, CompletedAt: daysAgo(1), IsActive: true},
+		{UserID: "recap-user", GoalID: "claimed-3d", ChallengeID: "c1", Namespace: "test", Progress: 10, Status: domain.GoalStatusClaimed, CompletedAt: daysAgo(3), ClaimedAt: daysAgo(2), IsActive: true},
+		{UserID: "recap-user", GoalID: "completed-10d", ChallengeID: "c1", Namespace: "test", Progress: 10, Status: domain.GoalStatusCompleted, CompletedAt: daysAgo(10), IsActive: true},
+		{UserID: "recap-user", GoalID: "in-progress", ChallengeID: "c1", Namespace: "test", Progress: 5, Status: domain.GoalStatusInProgress, IsActive: true},
+		{UserID: "other-user", GoalID: "completed-1d", ChallengeID: "c1", Namespace: "test", Progress: 10, Status: domain.GoalStatusCompleted, CompletedAt: daysAgo(1), IsActive: true},
+	})
+	if err != nil {
+		t.Fatalf("BulkInsert failed: %v", err)
+	}
+
+	t.Run("returns goals in window newest first", func(t *testing.T) {
+		results, err := repo.GetCompletedBetween(ctx, "recap-user", *daysAgo(7), now)
+		if err != nil {
+			t.Fatalf("GetCompletedBetween failed: %v", err)
+		}
+		if len(results) != 2 || results[0].GoalID != "completed-1d" || results[1].GoalID != "claimed-3d" {
+			t.Errorf("Expected [completed-1d claimed-3d], got %+v", results)
+		}
+	})
+
+	t.Run("window bounds are inclusive", func(t *testing.T) {
+		results, err := repo.GetCompletedBetween(ctx, "recap-user", *daysAgo(10), *daysAgo(10))
+		if err != nil {
+			t.Fatalf("GetCompletedBetween failed: %v", err)
+		}
+		if len(results) != 1 || results[0].GoalID != "completed-10d" {
+			t.Errorf("Expected [completed-10d], got %+v", results)
+		}
+	})
+
+	t.Run("empty window", func(t *testing.T) {
+		results, err := repo.GetCompletedBetween(ctx, "recap-user", now.Add(time.Hour), now.Add(2*time.Hour))
+		if err != nil {
+			t.Fatalf("GetCompletedBetween failed: %v", err)
+		}
+		if len(results) != 0 {
+			t.Errorf("Expected no results, got %d", len(results))
+		}
+	})
+
+	t.Run("within transaction", func(t *testing.T) {
+		tx, err := repo.BeginTx(ctx)
+		if err != nil {
+			t.Fatalf("BeginTx failed: %v", err)
+		}
+		defer func() { _ = tx.Rollback() }()
+
+		results, err := tx.GetCompletedBetween(ctx, "recap-user", *daysAgo(7), now)
+		if err != nil {
+			t.Fatalf("GetCompletedBetween in transaction failed: %v", err)
+		}
+		if len(results) != 2 {
+			t.Errorf("Expected 2 results, got %d", len(results))
+		}
+	})
+}
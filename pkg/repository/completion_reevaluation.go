@@ -0,0 +1,93 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/lib/pq"
+
+	"github.com/AccelByte/extend-challenge-common/pkg/errors"
+)
+
+// reevaluateCompletionQuery completes up to $3 active 'in_progress' rows whose progress
+// has reached the new target of their goal ($1 goal IDs, $2 targets). Completed rows leave
+// the filter, so each batch picks up where the previous one stopped. Rows locked by a
+// concurrent write are skipped: that write either re-applies the target itself or leaves
+// the row for the next run.
+const reevaluateCompletionQuery = `
+	WITH reached AS (
+		SELECT p.user_id, p.goal_id
+		FROM user_goal_progress p
+		JOIN UNNEST($1::VARCHAR(100)[], $2::BIGINT[]) AS t(goal_id, target)
+		  ON p.goal_id = t.goal_id
+		WHERE p.status = 'in_progress'
+		  AND p.is_active = true
+		  AND p.progress >= t.target
+		LIMIT $3
+		FOR UPDATE OF p SKIP LOCKED
+	)
+	UPDATE user_goal_progress
+	SET status = 'completed',
+		completed_at = NOW(),
+		updated_at = NOW()
+	FROM reached
+	WHERE user_goal_progress.user_id = reached.user_id
+	  AND user_goal_progress.goal_id = reached.goal_id
+`
+
+// CompletionReevaluator re-applies goal targets to stored progress after a config change.
+// It is a background job API and is only available on the connection pool.
+type CompletionReevaluator interface {
+	// ReevaluateCompletion moves active 'in_progress' rows of the goals in goalTargets to
+	// 'completed', setting completed_at, when their progress has reached the goal's target
+	// in the map. Use it after a reload lowers a TargetValue (see cache.ReloadSummary), so
+	// players already past the new target do not wait for their next event. Goals with a
+	// target <= 0 are ignored. Rows are updated batchSize at a time, each batch in its own
+	// statement, until none are left. Returns the number of rows completed; re-running is
+	// a no-op.
+	//
+	// Status only moves forward: completed, claiming and claimed rows are never touched, so
+	// raising a target leaves rows that completed under the old target completed (and
+	// claimable). Repeatable goals count completions on increment and should be left out.
+	ReevaluateCompletion(ctx context.Context, goalTargets map[string]int, batchSize int) (int64, error)
+}
+
+// ReevaluateCompletion completes in-progress rows that have reached their goal's target.
+func (r *PostgresGoalRepository) ReevaluateCompletion(ctx context.Context, goalTargets map[string]int, batchSize int) (int64, error) {
+	if batchSize <= 0 {
+		return 0, errors.ErrValidationFailed("batchSize", "must be positive")
+	}
+
+	goalIDs := make([]string, 0, len(goalTargets))
+	targets := make([]int64, 0, len(goalTargets))
+	for goalID, target := range goalTargets {
+		if target > 0 {
+			goalIDs = append(goalIDs, goalID)
+			targets = append(targets, int64(target))
+		}
+	}
+	if len(goalIDs) == 0 {
+		return 0, nil
+	}
+
+	if err := r.acquireGate(); err != nil {
+		return 0, err
+	}
+	defer r.releaseGate()
+
+	var total int64
+	for {
+		result, err := r.db.ExecContext(ctx, reevaluateCompletionQuery, pq.Array(goalIDs), pq.Array(targets), batchSize)
+		if err != nil {
+			return total, errors.ErrDatabaseError("reevaluate completion", err)
+		}
+		affected, err := result.RowsAffected()
+		if err != nil {
+			return total, errors.ErrDatabaseError("check rows affected", err)
+		}
+
+		total += affected
+		if affected < int64(batchSize) {
+			return total, nil
+		}
+	}
+}
@@ -0,0 +1,148 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/AccelByte/extend-challenge-common/pkg/domain"
+	customerrors "github.com/AccelByte/extend-challenge-common/pkg/errors"
+)
+
+func TestPostgresGoalRepository_ReevaluateCompletion(t *testing.T) {
+	db := setupTestDB(t)
+	if db == nil {
+		return
+	}
+	defer cleanupTestDB(t, db)
+
+	ctx := context.Background()
+	repo := NewPostgresGoalRepository(db)
+
+	row := func(userID, goalID string, progress int64, status domain.GoalStatus) *domain.UserGoalProgress {
+		return &domain.UserGoalProgress{UserID: userID, GoalID: goalID, ChallengeID: "c1", Namespace: "test", Progress: progress, Status: status, IsActive: true}
+	}
+	assertStatus := func(t *testing.T, userID, goalID string, want domain.GoalStatus) *domain.UserGoalProgress {
+		t.Helper()
+		progress, err := repo.GetProgressRequired(ctx, userID, goalID)
+		if err != nil {
+			t.Fatalf("GetProgressRequired failed: %v", err)
+		}
+		if progress.Status != want {
+			t.Errorf("%s/%s status = %s, want %s", userID, goalID, progress.Status, want)
+		}
+		return progress
+	}
+
+	t.Run("lowered target completes rows past it", func(t *testing.T) {
+		inactive := row("lower-inactive", "kills", 15, domain.GoalStatusInProgress)
+		inactive.IsActive = false
+		if err := repo.BulkInsert(ctx, []*domain.UserGoalProgress{
+			row("lower-above", "kills", 15, domain.GoalStatusInProgress),
+			row("lower-exact", "kills", 10, domain.GoalStatusInProgress),
+			row("lower-below", "kills", 9, domain.GoalStatusInProgress),
+			row("lower-claimed", "kills", 20, domain.GoalStatusClaimed),
+			row("lower-above", "other", 15, domain.GoalStatusInProgress),
+			inactive,
+		}); err != nil {
+			t.Fatalf("BulkInsert failed: %v", err)
+		}
+
+		// Target lowered from 20 to 10
+		completed, err := repo.ReevaluateCompletion(ctx, map[string]int{"kills": 10}, 100)
+		if err != nil {
+			t.Fatalf("ReevaluateCompletion failed: %v", err)
+		}
+		if completed != 2 {
+			t.Errorf("Completed %d rows, want 2", completed)
+		}
+
+		for _, userID := range []string{"lower-above", "lower-exact"} {
+			if progress := assertStatus(t, userID, "kills", domain.GoalStatusCompleted); progress.CompletedAt == nil {
+				t.Errorf("%s/kills CompletedAt = nil, want the re-evaluation time", userID)
+			}
+		}
+		assertStatus(t, "lower-below", "kills", domain.GoalStatusInProgress)
+		assertStatus(t, "lower-inactive", "kills", domain.GoalStatusInProgress)
+		assertStatus(t, "lower-above", "other", domain.GoalStatusInProgress)
+		if progress := assertStatus(t, "lower-claimed", "kills", domain.GoalStatusClaimed); progress.CompletedAt != nil {
+			t.Errorf("Claimed row CompletedAt = %v, want it untouched", progress.CompletedAt)
+		}
+
+		again, err := repo.ReevaluateCompletion(ctx, map[string]int{"kills": 10}, 100)
+		if err != nil || again != 0 {
+			t.Errorf("Rerun = %d, %v; want 0, nil", again, err)
+		}
+	})
+
+	t.Run("raised target changes nothing", func(t *testing.T) {
+		if err := repo.BulkInsert(ctx, []*domain.UserGoalProgress{
+			row("raise-completed", "wins", 10, domain.GoalStatusCompleted),
+			row("raise-progress", "wins", 8, domain.GoalStatusInProgress),
+			row("raise-claimed", "wins", 10, domain.GoalStatusClaimed),
+		}); err != nil {
+			t.Fatalf("BulkInsert failed: %v", err)
+		}
+
+		// Target raised from 10 to 20: completed rows must not be demoted
+		completed, err := repo.ReevaluateCompletion(ctx, map[string]int{"wins": 20}, 100)
+		if err != nil {
+			t.Fatalf("ReevaluateCompletion failed: %v", err)
+		}
+		if completed != 0 {
+			t.Errorf("Completed %d rows, want 0", completed)
+		}
+
+		assertStatus(t, "raise-completed", "wins", domain.GoalStatusCompleted)
+		assertStatus(t, "raise-progress", "wins", domain.GoalStatusInProgress)
+		assertStatus(t, "raise-claimed", "wins", domain.GoalStatusClaimed)
+	})
+
+	t.Run("batches over several thousand rows", func(t *testing.T) {
+		const users = 4500
+		rows := make([]*domain.UserGoalProgress, 0, users)
+		for i := range users {
+			// Every third row stays below the new target of 5
+			rows = append(rows, row(fmt.Sprintf("batch-user-%04d", i), "bulk", int64(4+i%3), domain.GoalStatusInProgress))
+		}
+		if err := repo.BulkInsert(ctx, rows); err != nil {
+			t.Fatalf("BulkInsert failed: %v", err)
+		}
+
+		completed, err := repo.ReevaluateCompletion(ctx, map[string]int{"bulk": 5}, 1000)
+		if err != nil {
+			t.Fatalf("ReevaluateCompletion failed: %v", err)
+		}
+		if want := int64(users - users/3); completed != want {
+			t.Errorf("Completed %d rows, want %d", completed, want)
+		}
+
+		var remaining int
+		if err := db.QueryRowContext(ctx, `
+			SELECT COUNT(*) FROM user_goal_progress
+			WHERE goal_id = 'bulk' AND status = 'in_progress' AND progress >= 5
+		`).Scan(&remaining); err != nil {
+			t.Fatalf("Count failed: %v", err)
+		}
+		if remaining != 0 {
+			t.Errorf("%d rows past the target left in progress, want 0", remaining)
+		}
+	})
+}
+
+func TestPostgresGoalRepository_ReevaluateCompletion_Validation(t *testing.T) {
+	repo := NewPostgresGoalRepository(nil)
+
+	_, err := repo.ReevaluateCompletion(context.Background(), map[string]int{"g": 10}, 0)
+	var ce *customerrors.ChallengeError
+	if !errors.As(err, &ce) || ce.Code != customerrors.ErrCodeValidationFailed {
+		t.Errorf("Expected ErrCodeValidationFailed, got %v", err)
+	}
+
+	// No goal has a positive target: nothing to do, and no database access
+	n, err := repo.ReevaluateCompletion(context.Background(), map[string]int{"g": 0}, 10)
+	if err != nil || n != 0 {
+		t.Errorf("ReevaluateCompletion() = %d, %v; want 0, nil", n, err)
+	}
+}
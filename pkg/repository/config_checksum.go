@@ -0,0 +1,68 @@
+package repository
+
+import (
+	"database/sql"
+	"strings"
+	"unicode"
+)
+
+// The config_checksum column (migration 007) records which goal config last wrote a row
+// through a batch path. It is optional: reads and writes only reference it with
+// WithConfigChecksum, so schemas without the column keep using the plain queries.
+
+// Batch write queries that also stamp config_checksum, selected with WithConfigChecksum.
+var (
+	batchUpsertProgressChecksumQuery = withConfigChecksum(batchUpsertProgressQuery, "")
+	mergeTempProgressChecksumQuery   = withConfigChecksum(mergeTempProgressQuery, "temp.config_checksum")
+	txMergeTempProgressChecksumQuery = withConfigChecksum(txMergeTempProgressQuery, "config_checksum")
+
+	batchIncrementProgressChecksumQuery            = withConfigChecksum(batchIncrementProgressQuery, "$11::VARCHAR(64)")
+	batchIncrementProgressChecksumLastDeltaQuery   = withLastDelta(batchIncrementProgressChecksumQuery, batchIncrementLastDelta)
+	txBatchIncrementProgressChecksumQuery          = withConfigChecksum(txBatchIncrementProgressQuery, "$13::VARCHAR(64)")
+	txBatchIncrementProgressChecksumLastDeltaQuery = withLastDelta(txBatchIncrementProgressChecksumQuery, txBatchIncrementLastDelta)
+)
+
+// withConfigChecksum returns the variant of a batch write query that stamps
+// config_checksum with value, keeping the row's checksum when value is NULL.
+//
+// An UPDATE assigns value in its SET list. An upsert appends the column to its INSERT
+// column list and value to the SELECT list that feeds it (an empty value leaves the row
+// source alone, for VALUES lists built at call time), and its ON CONFLICT branch takes
+// EXCLUDED.config_checksum. Like withLastDelta, it panics on a query it cannot rewrite.
+func withConfigChecksum(query, value string) string {
+	insert := strings.Index(query, "INSERT INTO user_goal_progress (")
+	if insert < 0 {
+		return withAssignment(query, "config_checksum = COALESCE("+value+", user_goal_progress.config_checksum)")
+	}
+
+	end := insert + strings.Index(query[insert:], ")")
+	query = appendListItem(query, end, "config_checksum")
+	if value != "" {
+		from := strings.Index(query[end:], "FROM")
+		if from < 0 {
+			panic("repository: upsert query has no SELECT list for config_checksum")
+		}
+		query = appendListItem(query, end+from, value)
+	}
+	return withAssignment(query, "config_checksum = COALESCE(EXCLUDED.config_checksum, user_goal_progress.config_checksum)")
+}
+
+// appendListItem appends item to the comma-separated list that ends before query[at:],
+// right after its last element.
+func appendListItem(query string, at int, item string) string {
+	at = strings.LastIndexFunc(query[:at], func(r rune) bool { return !unicode.IsSpace(r) }) + 1
+	return query[:at] + ", " + item + query[at:]
+}
+
+// configChecksumArg returns the checksum stamped by batch writes as a query argument.
+// It is NULL when the checksum is empty, and the write statements keep the row's
+// existing config_checksum in that case (COALESCE). Only the WithConfigChecksum
+// variants of the queries take it.
+func (r *PostgresGoalRepository) configChecksumArg() sql.NullString {
+	if r.configChecksum == nil {
+		return sql.NullString{}
+	}
+
+	checksum := r.configChecksum()
+	return sql.NullString{String: checksum, Valid: checksum != ""}
+}
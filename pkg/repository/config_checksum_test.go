@@ -0,0 +1,193 @@
+package repository
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/AccelByte/extend-challenge-common/pkg/domain"
+)
+
+func TestConfigChecksumArg(t *testing.T) {
+	if got := NewPostgresGoalRepository(nil).configChecksumArg(); got.Valid {
+		t.Errorf("configChecksumArg() without option = %+v, want NULL", got)
+	}
+
+	empty := NewPostgresGoalRepository(nil, WithConfigChecksum(func() string { return "" }))
+	if got := empty.configChecksumArg(); got.Valid {
+		t.Errorf("configChecksumArg() with empty checksum = %+v, want NULL", got)
+	}
+
+	set := NewPostgresGoalRepository(nil, WithConfigChecksum(func() string { return "abc" }))
+	if got := set.configChecksumArg(); !got.Valid || got.String != "abc" {
+		t.Errorf("configChecksumArg() = %+v, want abc", got)
+	}
+}
+
+func TestWithConfigChecksum(t *testing.T) {
+	variants := map[string]struct{ plain, withChecksum string }{
+		"batch upsert":              {batchUpsertProgressQuery, batchUpsertProgressChecksumQuery},
+		"merge temp":                {mergeTempProgressQuery, mergeTempProgressChecksumQuery},
+		"tx merge temp":             {txMergeTempProgressQuery, txMergeTempProgressChecksumQuery},
+		"batch increment":           {batchIncrementProgressQuery, batchIncrementProgressChecksumQuery},
+		"batch increment, delta":    {batchIncrementProgressLastDeltaQuery, batchIncrementProgressChecksumLastDeltaQuery},
+		"tx batch increment":        {txBatchIncrementProgressQuery, txBatchIncrementProgressChecksumQuery},
+		"tx batch increment, delta": {txBatchIncrementProgressLastDeltaQuery, txBatchIncrementProgressChecksumLastDeltaQuery},
+	}
+	for name, v := range variants {
+		if strings.Contains(v.plain, "config_checksum") {
+			t.Errorf("%s: plain query references config_checksum", name)
+		}
+		if !strings.Contains(v.withChecksum, "config_checksum = COALESCE(") {
+			t.Errorf("%s: checksum variant does not stamp config_checksum", name)
+		}
+	}
+
+	// Upserts insert the column with the value their row source provides
+	for query, value := range map[string]string{
+		txMergeTempProgressChecksumQuery:      "NOW(), config_checksum\n",
+		txBatchIncrementProgressChecksumQuery: "t.attempt_delta, $13::VARCHAR(64)\n",
+	} {
+		if !strings.Contains(query, "attempts, config_checksum\n") && !strings.Contains(query, "updated_at, config_checksum\n") {
+			t.Errorf("config_checksum missing from the INSERT column list:\n%s", query)
+		}
+		if !strings.Contains(query, value) {
+			t.Errorf("SELECT list does not end with %q:\n%s", value, query)
+		}
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Error("withConfigChecksum on an upsert without a SELECT list did not panic")
+		}
+	}()
+	withConfigChecksum("INSERT INTO user_goal_progress (user_id) VALUES ($1) ON CONFLICT DO UPDATE SET updated_at = NOW()", "$2")
+}
+
+func TestConfigChecksum_OptionalColumn(t *testing.T) {
+	increments := []ProgressIncrement{{UserID: "u1", GoalID: "g1", Delta: 1, TargetValue: 10}}
+
+	tests := []struct {
+		name      string
+		opts      []Option
+		wantQuery string
+		wantArgs  int
+	}{
+		{"without option", nil, batchIncrementProgressQuery, 10},
+		{"with checksum", []Option{WithConfigChecksum(func() string { return "abc" })}, batchIncrementProgressChecksumQuery, 11},
+		{"with checksum and last delta", []Option{WithConfigChecksum(func() string { return "abc" }), WithLastDelta(true)}, batchIncrementProgressChecksumLastDeltaQuery, 11},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := NewPostgresGoalRepository(nil, tt.opts...)
+
+			query, buildArgs := executor{repo: repo}.batchIncrementStatement(false)
+			if query != ceilingSkipsQuery(tt.wantQuery, batchDeltaParam, batchCeilingParam) {
+				t.Error("batchIncrementStatement() chose the wrong statement")
+			}
+			if got := len(buildArgs(increments)); got != tt.wantArgs {
+				t.Errorf("batch increment args = %d, want %d", got, tt.wantArgs)
+			}
+
+			columns := strings.Split(repo.progressColumns(), ",")
+			if got, want := len(repo.progressScanDest(&domain.UserGoalProgress{})), len(columns); got != want {
+				t.Errorf("progressScanDest() has %d destinations for %d columns", got, want)
+			}
+			if reads := strings.Contains(repo.progressColumns(), "config_checksum"); reads != (repo.configChecksum != nil) {
+				t.Errorf("progressColumns() reads config_checksum = %v, want %v", reads, repo.configChecksum != nil)
+			}
+		})
+	}
+}
+
+func TestPostgresGoalRepository_ConfigChecksumStamping(t *testing.T) {
+	db := setupTestDB(t)
+	if db == nil {
+		return
+	}
+	defer cleanupTestDB(t, db)
+
+	ctx := context.Background()
+	checksum := "v1"
+	repo := NewPostgresGoalRepository(db, WithConfigChecksum(func() string { return checksum }))
+
+	err := repo.BulkInsert(ctx, []*domain.UserGoalProgress{
+		{UserID: "checksum-user", GoalID: "incremented", ChallengeID: "c1", Namespace: "test", Status: domain.GoalStatusNotStarted, IsActive: true},
+		{UserID: "checksum-user", GoalID: "upserted", ChallengeID: "c1", Namespace: "test", Status: domain.GoalStatusNotStarted, IsActive: true},
+		{UserID: "checksum-user", GoalID: "copied", ChallengeID: "c1", Namespace: "test", Status: domain.GoalStatusNotStarted, IsActive: true},
+	})
+	if err != nil {
+		t.Fatalf("BulkInsert failed: %v", err)
+	}
+
+	assertChecksum := func(t *testing.T, goalID string, want *string) {
+		t.Helper()
+		progress, err := repo.GetProgress(ctx, "checksum-user", goalID)
+		if err != nil {
+			t.Fatalf("GetProgress(%s) failed: %v", goalID, err)
+		}
+		switch {
+		case want == nil && progress.ConfigChecksum != nil:
+			t.Errorf("%s: ConfigChecksum = %q, want nil", goalID, *progress.ConfigChecksum)
+		case want != nil && (progress.ConfigChecksum == nil || *progress.ConfigChecksum != *want):
+			t.Errorf("%s: ConfigChecksum = %v, want %q", goalID, progress.ConfigChecksum, *want)
+		}
+	}
+	v1, v2 := "v1", "v2"
+
+	// BulkInsert is not a batch progress path: rows start without a checksum
+	assertChecksum(t, "incremented", nil)
+
+	err = repo.BatchIncrementProgress(ctx, []ProgressIncrement{
+		{UserID: "checksum-user", GoalID: "incremented", ChallengeID: "c1", Namespace: "test", Delta: 1, TargetValue: 10},
+	})
+	if err != nil {
+		t.Fatalf("BatchIncrementProgress failed: %v", err)
+	}
+	assertChecksum(t, "incremented", &v1)
+
+	// A reload changes the checksum: new writes carry it, older rows keep theirs
+	checksum = "v2"
+	err = repo.BatchUpsertProgress(ctx, []*domain.UserGoalProgress{
+		{UserID: "checksum-user", GoalID: "upserted", ChallengeID: "c1", Namespace: "test", Progress: 2, Status: domain.GoalStatusInProgress},
+	})
+	if err != nil {
+		t.Fatalf("BatchUpsertProgress failed: %v", err)
+	}
+	err = repo.BatchUpsertProgressWithCOPY(ctx, []*domain.UserGoalProgress{
+		{UserID: "checksum-user", GoalID: "copied", ChallengeID: "c1", Namespace: "test", Progress: 3, Status: domain.GoalStatusInProgress},
+	})
+	if err != nil {
+		t.Fatalf("BatchUpsertProgressWithCOPY failed: %v", err)
+	}
+	assertChecksum(t, "incremented", &v1)
+	assertChecksum(t, "upserted", &v2)
+	assertChecksum(t, "copied", &v2)
+
+	// Transactions stamp the same checksum
+	tx, err := repo.BeginTx(ctx)
+	if err != nil {
+		t.Fatalf("BeginTx failed: %v", err)
+	}
+	err = tx.BatchIncrementProgress(ctx, []ProgressIncrement{
+		{UserID: "checksum-user", GoalID: "incremented", ChallengeID: "c1", Namespace: "test", Delta: 1, TargetValue: 10},
+	})
+	if err != nil {
+		_ = tx.Rollback()
+		t.Fatalf("BatchIncrementProgress in transaction failed: %v", err)
+	}
+	if err := tx.Commit(); err != nil {
+		t.Fatalf("Commit failed: %v", err)
+	}
+	assertChecksum(t, "incremented", &v2)
+
+	// Without a checksum the stored value is kept
+	checksum = ""
+	err = repo.BatchIncrementProgress(ctx, []ProgressIncrement{
+		{UserID: "checksum-user", GoalID: "upserted", ChallengeID: "c1", Namespace: "test", Delta: 1, TargetValue: 10},
+	})
+	if err != nil {
+		t.Fatalf("BatchIncrementProgress failed: %v", err)
+	}
+	assertChecksum(t, "upserted", &v2)
+}
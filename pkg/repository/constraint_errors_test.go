@@ -0,0 +1,48 @@
+package repository
+
+import (
+	"context"
+	"testing"
+
+	"github.com/AccelByte/extend-challenge-common/pkg/domain"
+	customerrors "github.com/AccelByte/extend-challenge-common/pkg/errors"
+)
+
+func TestPostgresGoalRepository_ConstraintErrors(t *testing.T) {
+	db := setupTestDB(t)
+	if db == nil {
+		return
+	}
+	defer cleanupTestDB(t, db)
+
+	repo := NewPostgresGoalRepository(db)
+	ctx := context.Background()
+
+	t.Run("claimed without completion", func(t *testing.T) {
+		// No repository method writes claimed_at without completed_at, so craft the upsert
+		_, err := db.ExecContext(ctx, `
+			INSERT INTO user_goal_progress (user_id, goal_id, challenge_id, namespace, progress, status, claimed_at, updated_at)
+			VALUES ('constraint-user', 'kills', 'c1', 'test', 5, 'claimed', NOW(), NOW())
+			ON CONFLICT (user_id, goal_id) DO UPDATE SET claimed_at = EXCLUDED.claimed_at`)
+		if err == nil {
+			t.Fatal("Expected check_claimed_implies_completed violation")
+		}
+		assertErrorCode(t, customerrors.ErrDatabaseError("upsert progress", err), customerrors.ErrCodeClaimWithoutCompletion)
+	})
+
+	t.Run("negative progress", func(t *testing.T) {
+		err := repo.UpsertProgress(ctx, &domain.UserGoalProgress{
+			UserID: "constraint-user", GoalID: "wins", ChallengeID: "c1", Namespace: "test",
+			Progress: -1, Status: domain.GoalStatusInProgress,
+		})
+		assertErrorCode(t, err, customerrors.ErrCodeNegativeProgress)
+	})
+
+	t.Run("invalid status", func(t *testing.T) {
+		err := repo.UpsertProgress(ctx, &domain.UserGoalProgress{
+			UserID: "constraint-user", GoalID: "score", ChallengeID: "c1", Namespace: "test",
+			Status: domain.GoalStatus("bogus"),
+		})
+		assertErrorCode(t, err, customerrors.ErrCodeInvalidStatus)
+	})
+}
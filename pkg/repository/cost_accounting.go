@@ -0,0 +1,110 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/AccelByte/extend-challenge-common/pkg/errors"
+)
+
+// CostReporter receives the cost of repository batch writes so multi-tenant deployments
+// can attribute database load per namespace (see WithCostReporter).
+type CostReporter interface {
+	// ReportCost is called after a batch write that reached the database, whether it
+	// succeeded or not. op names the write (e.g., "batch increment progress"), rows is
+	// the number of batch entries sent and duration is the wall time of the write,
+	// including lock waits. A batch spanning several namespaces is reported once per
+	// namespace with that namespace's rows and a share of the duration proportional
+	// to them. Calls are synchronous, so implementations should be cheap.
+	ReportCost(ctx context.Context, op string, namespace string, rows int, duration time.Duration)
+}
+
+// Limiter admits repository batch writes per namespace so the embedding service can
+// throttle a noisy tenant, e.g., with a token bucket per namespace (see WithLimiter).
+type Limiter interface {
+	// Allow is called before a batch write with the batch's namespace, the write's op
+	// name and its number of entries. A non-nil error denies the write, which then fails
+	// with errors.ErrCodeRateLimited without touching the database.
+	Allow(ctx context.Context, namespace string, op string, rows int) error
+}
+
+// namespaceRows is the number of entries a batch holds for one namespace.
+type namespaceRows struct {
+	namespace string
+	rows      int
+}
+
+// batchNamespaces counts the entries of each namespace in a batch of n entries, in
+// first-seen order.
+func batchNamespaces(n int, namespaceOf func(i int) string) []namespaceRows {
+	var counts []namespaceRows
+	index := make(map[string]int)
+	for i := 0; i < n; i++ {
+		ns := namespaceOf(i)
+		j, ok := index[ns]
+		if !ok {
+			j = len(counts)
+			index[ns] = j
+			counts = append(counts, namespaceRows{namespace: ns})
+		}
+		counts[j].rows++
+	}
+	return counts
+}
+
+// admitBatch consults the limiter for a batch write of n entries and returns a function
+// that reports the write's cost when called. Without a limiter or reporter it does
+// nothing. With a limiter, a batch mixing namespaces is rejected because it cannot be
+// charged to a single tenant.
+func (r *PostgresGoalRepository) admitBatch(ctx context.Context, op string, n int, namespaceOf func(i int) string) (func(), error) {
+	if r.limiter == nil && r.costReporter == nil {
+		return func() {}, nil
+	}
+
+	counts := batchNamespaces(n, namespaceOf)
+	if r.limiter != nil {
+		if len(counts) > 1 {
+			names := make([]string, len(counts))
+			for i, c := range counts {
+				names[i] = "'" + c.namespace + "'"
+			}
+			return nil, errors.ErrValidationFailed("namespace", fmt.Sprintf("%s batch mixes namespaces %s", op, strings.Join(names, ", ")))
+		}
+		if err := r.limiter.Allow(ctx, counts[0].namespace, op, n); err != nil {
+			return nil, errors.ErrRateLimited(counts[0].namespace, op, err)
+		}
+	}
+
+	if r.costReporter == nil {
+		return func() {}, nil
+	}
+	start := time.Now()
+	return func() {
+		elapsed := time.Since(start)
+		for _, c := range counts {
+			r.costReporter.ReportCost(ctx, op, c.namespace, c.rows, elapsed*time.Duration(c.rows)/time.Duration(n))
+		}
+	}, nil
+}
+
+// admitBatch admits a batch write on the pool or in the caller's transaction, and takes
+// the shutdown gate for pool writes. The returned done releases the gate and reports the
+// write's cost; it must be called once the write finishes.
+func (e executor) admitBatch(ctx context.Context, op string, n int, namespaceOf func(i int) string) (func(), error) {
+	report, err := e.repo.admitBatch(ctx, op, n, namespaceOf)
+	if err != nil {
+		return nil, err
+	}
+
+	release, err := e.acquireGate()
+	if err != nil {
+		return nil, err
+	}
+
+	return func() {
+		release()
+		report()
+	}, nil
+}
@@ -0,0 +1,183 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/AccelByte/extend-challenge-common/pkg/domain"
+	customerrors "github.com/AccelByte/extend-challenge-common/pkg/errors"
+)
+
+// callLog records hook invocations in order.
+type callLog struct {
+	calls []string
+}
+
+type fakeLimiter struct {
+	log  *callLog
+	deny error
+}
+
+func (l *fakeLimiter) Allow(_ context.Context, namespace, op string, rows int) error {
+	l.log.calls = append(l.log.calls, fmt.Sprintf("allow %s %s %d", namespace, op, rows))
+	return l.deny
+}
+
+type fakeCostReporter struct {
+	log       *callLog
+	durations []time.Duration
+}
+
+func (r *fakeCostReporter) ReportCost(_ context.Context, op, namespace string, rows int, duration time.Duration) {
+	r.log.calls = append(r.log.calls, fmt.Sprintf("report %s %s %d", namespace, op, rows))
+	r.durations = append(r.durations, duration)
+}
+
+type fakeGate struct {
+	log *callLog
+}
+
+func (g *fakeGate) Acquire() error {
+	g.log.calls = append(g.log.calls, "acquire")
+	return nil
+}
+
+func (g *fakeGate) Release() {
+	g.log.calls = append(g.log.calls, "release")
+}
+
+func assertCalls(t *testing.T, got []string, want ...string) {
+	t.Helper()
+	if fmt.Sprint(got) != fmt.Sprint(want) {
+		t.Errorf("calls = %q, want %q", got, want)
+	}
+}
+
+func TestExecutor_AdmitBatch(t *testing.T) {
+	namespaces := func(ns ...string) func(int) string {
+		return func(i int) string { return ns[i] }
+	}
+
+	t.Run("limit, gate, write, report", func(t *testing.T) {
+		log := &callLog{}
+		reporter := &fakeCostReporter{log: log}
+		repo := NewPostgresGoalRepository(nil,
+			WithLimiter(&fakeLimiter{log: log}), WithCostReporter(reporter), WithGate(&fakeGate{log: log}))
+
+		done, err := repo.exec().admitBatch(context.Background(), "bulk insert", 2, namespaces("game-a", "game-a"))
+		if err != nil {
+			t.Fatalf("admitBatch failed: %v", err)
+		}
+		log.calls = append(log.calls, "write")
+		done()
+
+		assertCalls(t, log.calls, "allow game-a bulk insert 2", "acquire", "write", "release", "report game-a bulk insert 2")
+	})
+
+	t.Run("denied write takes no gate and reports nothing", func(t *testing.T) {
+		log := &callLog{}
+		reason := errors.New("bucket empty")
+		repo := NewPostgresGoalRepository(nil,
+			WithLimiter(&fakeLimiter{log: log, deny: reason}), WithCostReporter(&fakeCostReporter{log: log}), WithGate(&fakeGate{log: log}))
+
+		_, err := repo.exec().admitBatch(context.Background(), "bulk insert", 1, namespaces("game-a"))
+		assertErrorCode(t, err, customerrors.ErrCodeRateLimited)
+		if !errors.Is(err, reason) {
+			t.Errorf("Expected error to wrap the limiter's reason, got %v", err)
+		}
+		assertCalls(t, log.calls, "allow game-a bulk insert 1")
+	})
+
+	t.Run("mixed namespaces rejected with a limiter", func(t *testing.T) {
+		log := &callLog{}
+		repo := NewPostgresGoalRepository(nil, WithLimiter(&fakeLimiter{log: log}))
+
+		_, err := repo.exec().admitBatch(context.Background(), "bulk insert", 3, namespaces("game-a", "game-b", "game-a"))
+		assertErrorCode(t, err, customerrors.ErrCodeValidationFailed)
+		assertCalls(t, log.calls)
+	})
+
+	t.Run("mixed namespaces reported per namespace without a limiter", func(t *testing.T) {
+		log := &callLog{}
+		reporter := &fakeCostReporter{log: log}
+		repo := NewPostgresGoalRepository(nil, WithCostReporter(reporter))
+
+		done, err := repo.exec().admitBatch(context.Background(), "bulk insert", 4, namespaces("game-a", "game-b", "game-a", "game-a"))
+		if err != nil {
+			t.Fatalf("admitBatch failed: %v", err)
+		}
+		time.Sleep(time.Millisecond)
+		done()
+
+		assertCalls(t, log.calls, "report game-a bulk insert 3", "report game-b bulk insert 1")
+		if diff := reporter.durations[0] - 3*reporter.durations[1]; diff < 0 || diff > 3 {
+			t.Errorf("durations = %v, want split 3:1 by rows", reporter.durations)
+		}
+	})
+}
+
+func TestPostgresGoalRepository_LimiterShortCircuits(t *testing.T) {
+	// nil *sql.DB: any SQL would panic, so passing proves no query runs
+	log := &callLog{}
+	repo := NewPostgresGoalRepository(nil, WithLimiter(&fakeLimiter{log: log, deny: errors.New("over quota")}))
+	ctx := context.Background()
+
+	err := repo.BatchIncrementProgress(ctx, []ProgressIncrement{
+		{UserID: "user-1", GoalID: "kills", ChallengeID: "c1", Namespace: "game-a", Delta: 1, TargetValue: 10},
+	})
+	assertErrorCode(t, err, customerrors.ErrCodeRateLimited)
+
+	err = repo.BulkInsert(ctx, []*domain.UserGoalProgress{
+		{UserID: "user-1", GoalID: "kills", Namespace: "game-a"},
+		{UserID: "user-1", GoalID: "wins", Namespace: "game-b"},
+	})
+	assertErrorCode(t, err, customerrors.ErrCodeValidationFailed)
+
+	assertCalls(t, log.calls, "allow game-a batch increment progress 1")
+}
+
+func TestPostgresGoalRepository_CostReporter(t *testing.T) {
+	db := setupTestDB(t)
+	if db == nil {
+		return
+	}
+	defer cleanupTestDB(t, db)
+
+	log := &callLog{}
+	reporter := &fakeCostReporter{log: log}
+	repo := NewPostgresGoalRepository(db, WithLimiter(&fakeLimiter{log: log}), WithCostReporter(reporter))
+	ctx := context.Background()
+
+	if err := repo.BulkInsert(ctx, []*domain.UserGoalProgress{
+		{UserID: "cost-user", GoalID: "kills", ChallengeID: "c1", Namespace: "game-a", Status: domain.GoalStatusNotStarted, IsActive: true},
+	}); err != nil {
+		t.Fatalf("BulkInsert failed: %v", err)
+	}
+
+	tx, err := repo.BeginTx(ctx)
+	if err != nil {
+		t.Fatalf("BeginTx failed: %v", err)
+	}
+	defer func() { _ = tx.Rollback() }()
+	if err := tx.BatchIncrementProgress(ctx, []ProgressIncrement{
+		{UserID: "cost-user", GoalID: "kills", ChallengeID: "c1", Namespace: "game-a", Delta: 2, TargetValue: 10},
+	}); err != nil {
+		t.Fatalf("BatchIncrementProgress in transaction failed: %v", err)
+	}
+	if err := tx.Commit(); err != nil {
+		t.Fatalf("Commit failed: %v", err)
+	}
+
+	assertCalls(t, log.calls,
+		"allow game-a bulk insert 1", "report game-a bulk insert 1",
+		"allow game-a batch increment progress 1", "report game-a batch increment progress 1",
+	)
+	for i, d := range reporter.durations {
+		if d <= 0 {
+			t.Errorf("report %d duration = %v, want positive", i, d)
+		}
+	}
+}
@@ -0,0 +1,47 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/AccelByte/extend-challenge-common/pkg/domain"
+)
+
+// getDailyGoalsEligibleQuery selects a user's unclaimed daily rows last updated before $2.
+// Daily rows are recognized by last_daily_date, which every daily increment sets; the
+// table does not store the goal type. The column list and the active-only clause are
+// added at call time.
+var getDailyGoalsEligibleQuery = ` FROM user_goal_progress
+	WHERE user_id = $1
+	  AND last_daily_date IS NOT NULL
+	  AND ` + protectedStatusGuard("status") + `
+	  AND updated_at < $2`
+
+// dailyPeriodStart returns the start of the local day containing now in tz (nil = UTC).
+// It is the reset that NextDailyReset reports for any time on the previous day.
+func dailyPeriodStart(now time.Time, tz *time.Location) time.Time {
+	if tz == nil {
+		tz = time.UTC
+	}
+
+	year, month, day := now.In(tz).Date()
+	return domain.NextDailyReset(time.Date(year, month, day-1, 12, 0, 0, 0, tz), tz)
+}
+
+func (e executor) getDailyGoalsEligible(ctx context.Context, userID string, tz *time.Location) ([]*domain.UserGoalProgress, error) {
+	dayStart := dailyPeriodStart(time.Now(), tz).UTC() // updated_at is stored in UTC
+
+	query := "SELECT " + e.repo.progressColumns() + getDailyGoalsEligibleQuery + e.repo.activeOnlyClause() + " ORDER BY challenge_id, goal_id"
+
+	return e.queryProgress(ctx, e.op("get daily goals eligible"), query, userID, dayStart)
+}
+
+// GetDailyGoalsEligible returns the user's active daily goals not updated since the start of the current day in tz.
+func (r *PostgresGoalRepository) GetDailyGoalsEligible(ctx context.Context, userID string, tz *time.Location) ([]*domain.UserGoalProgress, error) {
+	return r.exec().getDailyGoalsEligible(ctx, userID, tz)
+}
+
+// GetDailyGoalsEligible returns the user's eligible daily goals within a transaction.
+func (r *PostgresTxRepository) GetDailyGoalsEligible(ctx context.Context, userID string, tz *time.Location) ([]*domain.UserGoalProgress, error) {
+	return r.exec().getDailyGoalsEligible(ctx, userID, tz)
+}
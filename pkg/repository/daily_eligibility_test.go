@@ -0,0 +1,125 @@
+package repository
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/AccelByte/extend-challenge-common/pkg/domain"
+)
+
+func TestDailyPeriodStart(t *testing.T) {
+	newYork, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Fatalf("LoadLocation failed: %v", err)
+	}
+	santiago, err := time.LoadLocation("America/Santiago")
+	if err != nil {
+		t.Fatalf("LoadLocation failed: %v", err)
+	}
+
+	tests := []struct {
+		name string
+		now  time.Time
+		tz   *time.Location
+		want time.Time
+	}{
+		{
+			name: "nil tz is UTC",
+			now:  time.Date(2025, 3, 10, 15, 30, 0, 0, time.UTC),
+			want: time.Date(2025, 3, 10, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			name: "local day differs from UTC day",
+			now:  time.Date(2025, 3, 11, 2, 0, 0, 0, time.UTC), // 22:00 on Mar 10 in New York
+			tz:   newYork,
+			want: time.Date(2025, 3, 10, 0, 0, 0, 0, newYork),
+		},
+		{
+			name: "exactly at local midnight",
+			now:  time.Date(2025, 11, 3, 0, 0, 0, 0, newYork),
+			tz:   newYork,
+			want: time.Date(2025, 11, 3, 0, 0, 0, 0, newYork),
+		},
+		{
+			name: "DST skips midnight",
+			now:  time.Date(2024, 9, 8, 12, 0, 0, 0, santiago),
+			tz:   santiago,
+			want: time.Date(2024, 9, 8, 4, 0, 0, 0, time.UTC), // 01:00 -03, first instant of Sep 8
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := dailyPeriodStart(tt.now, tt.tz); !got.Equal(tt.want) {
+				t.Errorf("dailyPeriodStart() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPostgresGoalRepository_GetDailyGoalsEligible(t *testing.T) {
+	db := setupTestDB(t)
+	if db == nil {
+		return
+	}
+	defer cleanupTestDB(t, db)
+
+	ctx := context.Background()
+	repo := NewPostgresGoalRepository(db)
+
+	err := repo.BulkInsert(ctx, []*domain.UserGoalProgress{
+		{UserID: "daily-user", GoalID: "counted-yesterday", ChallengeID: "c1", Namespace: "test", Status: domain.GoalStatusInProgress, IsActive: true},
+		{UserID: "daily-user", GoalID: "counted-today", ChallengeID: "c1", Namespace: "test", Status: domain.GoalStatusInProgress, IsActive: true},
+		{UserID: "daily-user", GoalID: "claimed", ChallengeID: "c1", Namespace: "test", Status: domain.GoalStatusClaimed, IsActive: true},
+		{UserID: "daily-user", GoalID: "inactive", ChallengeID: "c1", Namespace: "test", Status: domain.GoalStatusInProgress, IsActive: false},
+		{UserID: "daily-user", GoalID: "not-daily", ChallengeID: "c1", Namespace: "test", Status: domain.GoalStatusInProgress, IsActive: true},
+	})
+	if err != nil {
+		t.Fatalf("BulkInsert failed: %v", err)
+	}
+
+	// Backdate every row, then mark all but not-daily as counted by a daily increment
+	_, err = db.ExecContext(ctx, `
+		UPDATE user_goal_progress
+		SET updated_at = NOW() - INTERVAL '2 days',
+		    last_daily_date = CASE WHEN goal_id = 'not-daily' THEN NULL ELSE DATE(NOW() AT TIME ZONE 'UTC') - 2 END
+		WHERE user_id = 'daily-user'
+	`)
+	if err != nil {
+		t.Fatalf("Backdate failed: %v", err)
+	}
+	_, err = db.ExecContext(ctx, `
+		UPDATE user_goal_progress
+		SET updated_at = NOW(), last_daily_date = DATE(NOW() AT TIME ZONE 'UTC')
+		WHERE user_id = 'daily-user' AND goal_id = 'counted-today'
+	`)
+	if err != nil {
+		t.Fatalf("Update failed: %v", err)
+	}
+
+	eligible, err := repo.GetDailyGoalsEligible(ctx, "daily-user", time.UTC)
+	if err != nil {
+		t.Fatalf("GetDailyGoalsEligible failed: %v", err)
+	}
+	assertGoalIDs(t, eligible, []string{"counted-yesterday"})
+
+	// The read does not mutate
+	again, err := repo.GetDailyGoalsEligible(ctx, "daily-user", nil)
+	if err != nil {
+		t.Fatalf("GetDailyGoalsEligible failed: %v", err)
+	}
+	assertGoalIDs(t, again, []string{"counted-yesterday"})
+
+	tx, err := repo.BeginTx(ctx)
+	if err != nil {
+		t.Fatalf("BeginTx failed: %v", err)
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	inTx, err := tx.GetDailyGoalsEligible(ctx, "daily-user", time.UTC)
+	if err != nil {
+		t.Fatalf("GetDailyGoalsEligible in transaction failed: %v", err)
+	}
+	assertGoalIDs(t, inTx, []string{"counted-yesterday"})
+}
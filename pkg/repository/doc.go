@@ -0,0 +1,59 @@
+// Package repository persists user goal progress.
+//
+// Interfaces:
+//   - GoalRepository: progress reads and writes used by the event handler and the
+//     backend service. Implemented by PostgresGoalRepository (connection pool).
+//   - TxRepository: GoalRepository plus row locking, claim, and Commit/Rollback.
+//     Implemented by PostgresTxRepository, obtained from GoalRepository.BeginTx.
+//   - ProgressHistoryRepository: as-of reads over the optional history table.
+//     Implemented by PostgresGoalRepository only.
+//   - ProgressFeedRepository: namespace-wide scans for background jobs and admin
+//     tools ("changed since" feeds, goals nearing completion, challenge participants,
+//     assignment audits, overflowing counters, parameterized search).
+//     Implemented by PostgresGoalRepository only.
+//   - FlushPreviewRepository: read-only dry runs that classify a pending flush
+//     (would insert/update, blocked by claimed/claiming/inactive/expired rows).
+//     Implemented by PostgresGoalRepository only.
+//   - ClaimForfeitRepository: claim-deadline sweep that forfeits rewards left
+//     unclaimed past their goal's deadline. Implemented by PostgresGoalRepository only.
+//   - ClaimReservationSweeper: releases two-phase claim reservations (ReserveClaim)
+//     that were never confirmed or cancelled. Implemented by PostgresGoalRepository only.
+//   - RecurringGoalResetter: starts a new period for recurring goals by resetting their
+//     progress across all users. Implemented by PostgresGoalRepository only.
+//   - ProgressSampler: random recently updated rows for spot checks.
+//     Implemented by PostgresGoalRepository only.
+//   - LargeFlusher: parallel chunked COPY flushes for very large batches such as
+//     reconciliation jobs. Implemented by PostgresGoalRepository only.
+//   - CompletionReevaluator: completes in-progress rows that already meet a goal's
+//     lowered target after a config reload. Implemented by PostgresGoalRepository only.
+//
+// DualWriteGoalRepository decorates two GoalRepository backends for zero-downtime table
+// migrations: writes go to both, reads to the one selected by its DualWriteMode, and
+// VerifySample compares a sample of rows between them.
+//
+// Compile-time assertions for all of the above live next to the Postgres types in
+// postgres_goal_repository.go. PostgresGoalRepository is configured with functional
+// options (see options.go); transactions inherit the options of the repository that
+// started them.
+//
+// Queries shared by the pool and transaction repositories are implemented once in
+// executor.go; both types delegate to it, and TestRepositoryMethodSets keeps their
+// exported method sets aligned.
+//
+// Schema migrations: the repository reads and writes the columns added by the migrations
+// in migrations/. A required column is selected by every progress read, so its migration
+// must be applied before deploying the version that uses it (db.VerifySchema reports a
+// missing one); these breaking migrations are listed in CHANGELOG.md:
+//   - 008 forfeited_at: claim deadlines (MarkAsClaimedWithDeadline, ForfeitExpiredClaims).
+//   - 014 attempts: attempt counting; also written by every batch increment.
+//   - 017 order_index: display order of user and challenge reads; also written by the
+//     assignment inserts.
+//
+// Optional columns are only referenced when the matching option is set:
+//   - 007 config_checksum: WithConfigChecksum.
+//   - 018 completions: WithCompletions to read it; IncrementRepeatableProgress writes it.
+//   - 019 last_delta: WithLastDelta.
+//
+// Subpackage listen streams row changes published by the optional NOTIFY trigger
+// (migration 006) for consumers that would otherwise poll GetUserProgress.
+package repository
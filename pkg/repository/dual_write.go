@@ -0,0 +1,746 @@
+package repository
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/AccelByte/extend-challenge-common/pkg/domain"
+	"github.com/AccelByte/extend-challenge-common/pkg/errors"
+)
+
+var (
+	_ GoalRepository = (*DualWriteGoalRepository)(nil)
+	_ TxRepository   = (*dualWriteTx)(nil)
+)
+
+// DualWriteMode selects how DualWriteGoalRepository routes calls during a table migration
+// (e.g., from the monolithic user_goal_progress table to a partitioned layout).
+type DualWriteMode string
+
+const (
+	// WriteBothReadOld writes to both backends and reads from the old one. The old backend
+	// stays the source of truth while the new one is backfilled.
+	WriteBothReadOld DualWriteMode = "write_both_read_old"
+
+	// WriteBothReadNew writes to both backends and reads from the new one, falling back to
+	// the old one while the backfill is incomplete. The old backend is kept current so
+	// the migration can be rolled back.
+	WriteBothReadNew DualWriteMode = "write_both_read_new"
+
+	// WriteNewOnly routes everything to the new backend once the migration is complete.
+	WriteNewOnly DualWriteMode = "write_new_only"
+)
+
+// IsValid returns true if m is a known mode.
+func (m DualWriteMode) IsValid() bool {
+	switch m {
+	case WriteBothReadOld, WriteBothReadNew, WriteNewOnly:
+		return true
+	default:
+		return false
+	}
+}
+
+// DefaultVerifyWindow is the default age limit of the rows VerifySample picks.
+const DefaultVerifyWindow = time.Hour
+
+// DualWriteMetrics receives the events of a DualWriteGoalRepository worth alerting on
+// (see WithDualWriteMetrics). Calls are synchronous, so implementations should be cheap.
+type DualWriteMetrics interface {
+	// ShadowWriteFailed is called when a write succeeded on the primary backend but failed
+	// on the shadow one. op is the GoalRepository method name.
+	ShadowWriteFailed(op string, err error)
+
+	// ReadFallback is called when a read in WriteBothReadNew mode was served by the old
+	// backend because the new one failed or had no row.
+	ReadFallback(op string)
+}
+
+// DualWriteOption configures a DualWriteGoalRepository.
+type DualWriteOption func(*DualWriteGoalRepository)
+
+// WithDualWriteMetrics sets the hook that counts shadow-write failures and read fallbacks.
+func WithDualWriteMetrics(metrics DualWriteMetrics) DualWriteOption {
+	return func(d *DualWriteGoalRepository) {
+		d.metrics = metrics
+	}
+}
+
+// WithDualWriteLogger sets the logger for shadow-write failures. Defaults to slog.Default().
+func WithDualWriteLogger(logger *slog.Logger) DualWriteOption {
+	return func(d *DualWriteGoalRepository) {
+		if logger != nil {
+			d.logger = logger
+		}
+	}
+}
+
+// WithDualWriteVerifyWindow sets how recently a row must have been updated to be picked by
+// VerifySample. Defaults to DefaultVerifyWindow; non-positive values are ignored.
+func WithDualWriteVerifyWindow(window time.Duration) DualWriteOption {
+	return func(d *DualWriteGoalRepository) {
+		if window > 0 {
+			d.verifyWindow = window
+		}
+	}
+}
+
+// DualWriteGoalRepository is a GoalRepository decorator for zero-downtime table migrations.
+// It wraps the repository over the new layout and the one over the old layout and routes
+// each call according to its DualWriteMode.
+//
+// Writes go to the primary backend (the one reads are served from) first. A primary
+// failure is returned and the shadow backend is not written, so it never gets ahead of
+// the primary. A shadow failure is logged and counted (see DualWriteMetrics) but does not
+// fail the call; results are always the primary's. Transactions (BeginTx) open one
+// transaction per backend and commit the primary first.
+//
+// Non-idempotent writes (increments, claims) are applied to each backend independently, so
+// a shadow failure leaves the backends diverged until the row is rewritten or backfilled;
+// VerifySample estimates how far they have drifted.
+type DualWriteGoalRepository struct {
+	newRepo GoalRepository
+	oldRepo GoalRepository
+	mode    DualWriteMode
+
+	// Routing derived from mode: reads and writes go to primary, writes are mirrored to
+	// shadow (nil in WriteNewOnly) and failed reads retry on fallback (nil unless
+	// WriteBothReadNew)
+	primary  GoalRepository
+	shadow   GoalRepository
+	fallback GoalRepository
+
+	metrics      DualWriteMetrics
+	logger       *slog.Logger
+	verifyWindow time.Duration
+}
+
+// NewDualWriteGoalRepository creates a DualWriteGoalRepository over the repository for the
+// new layout (newRepo) and the one for the old layout (oldRepo).
+// Returns ErrValidationFailed if mode is unknown.
+func NewDualWriteGoalRepository(newRepo, oldRepo GoalRepository, mode DualWriteMode, opts ...DualWriteOption) (*DualWriteGoalRepository, error) {
+	if !mode.IsValid() {
+		return nil, errors.ErrValidationFailed("mode", "unknown dual-write mode '"+string(mode)+"'")
+	}
+
+	d := &DualWriteGoalRepository{
+		newRepo:      newRepo,
+		oldRepo:      oldRepo,
+		mode:         mode,
+		logger:       slog.Default(),
+		verifyWindow: DefaultVerifyWindow,
+	}
+	for _, opt := range opts {
+		opt(d)
+	}
+
+	switch mode {
+	case WriteBothReadOld:
+		d.primary, d.shadow = oldRepo, newRepo
+	case WriteBothReadNew:
+		d.primary, d.shadow, d.fallback = newRepo, oldRepo, oldRepo
+	case WriteNewOnly:
+		d.primary = newRepo
+	}
+
+	return d, nil
+}
+
+// Mode returns the routing mode the repository was created with.
+func (d *DualWriteGoalRepository) Mode() DualWriteMode {
+	return d.mode
+}
+
+// shadowFailed logs and counts a failed shadow write.
+func (d *DualWriteGoalRepository) shadowFailed(op string, err error) {
+	d.logger.Warn("Shadow write failed", "operation", op, "mode", string(d.mode), "error", err)
+	if d.metrics != nil {
+		d.metrics.ShadowWriteFailed(op, err)
+	}
+}
+
+// dualWrite applies fn to the primary backend, then mirrors it to the shadow backend if
+// the primary succeeded. The primary's result is returned.
+func dualWrite[T any](d *DualWriteGoalRepository, op string, fn func(GoalRepository) (T, error)) (T, error) {
+	result, err := fn(d.primary)
+	if err != nil || d.shadow == nil {
+		return result, err
+	}
+
+	if _, shadowErr := fn(d.shadow); shadowErr != nil {
+		d.shadowFailed(op, shadowErr)
+	}
+	return result, nil
+}
+
+// dualWriteErr is dualWrite for methods that return only an error.
+func dualWriteErr(d *DualWriteGoalRepository, op string, fn func(GoalRepository) error) error {
+	_, err := dualWrite(d, op, func(r GoalRepository) (struct{}, error) {
+		return struct{}{}, fn(r)
+	})
+	return err
+}
+
+// dualRead serves a read from the primary backend and, in WriteBothReadNew mode, retries it
+// on the old backend if the primary fails or missing reports its result as empty.
+// missing may be nil.
+func dualRead[T any](d *DualWriteGoalRepository, op string, fn func(GoalRepository) (T, error), missing func(T) bool) (T, error) {
+	result, err := fn(d.primary)
+	if d.fallback == nil || (err == nil && (missing == nil || !missing(result))) {
+		return result, err
+	}
+
+	if d.metrics != nil {
+		d.metrics.ReadFallback(op)
+	}
+	return fn(d.fallback)
+}
+
+// noRow reports a single-row read that found nothing.
+func noRow(p *domain.UserGoalProgress) bool {
+	return p == nil
+}
+
+// GetProgress reads from the primary backend; in WriteBothReadNew mode a row missing from
+// the new backend is read from the old one.
+func (d *DualWriteGoalRepository) GetProgress(ctx context.Context, userID, goalID string) (*domain.UserGoalProgress, error) {
+	return dualRead(d, "GetProgress", func(r GoalRepository) (*domain.UserGoalProgress, error) {
+		return r.GetProgress(ctx, userID, goalID)
+	}, noRow)
+}
+
+// GetProgressRequired reads like GetProgress, including its fallback, and fails with
+// errors.ErrProgressNotFound when neither backend has the row.
+func (d *DualWriteGoalRepository) GetProgressRequired(ctx context.Context, userID, goalID string) (*domain.UserGoalProgress, error) {
+	progress, err := d.GetProgress(ctx, userID, goalID)
+	if err != nil {
+		return nil, err
+	}
+	if progress == nil {
+		return nil, errors.ErrProgressNotFound(userID, goalID)
+	}
+	return progress, nil
+}
+
+// GetUserProgress reads from the primary backend.
+func (d *DualWriteGoalRepository) GetUserProgress(ctx context.Context, userID string, activeOnly bool) ([]*domain.UserGoalProgress, error) {
+	return dualRead(d, "GetUserProgress", func(r GoalRepository) ([]*domain.UserGoalProgress, error) {
+		return r.GetUserProgress(ctx, userID, activeOnly)
+	}, nil)
+}
+
+// GetUserProgressLite reads from the primary backend.
+func (d *DualWriteGoalRepository) GetUserProgressLite(ctx context.Context, userID string, activeOnly bool) ([]ProgressLite, error) {
+	return dualRead(d, "GetUserProgressLite", func(r GoalRepository) ([]ProgressLite, error) {
+		return r.GetUserProgressLite(ctx, userID, activeOnly)
+	}, nil)
+}
+
+// GetChallengeProgress reads from the primary backend.
+func (d *DualWriteGoalRepository) GetChallengeProgress(ctx context.Context, userID, challengeID string, activeOnly bool) ([]*domain.UserGoalProgress, error) {
+	return dualRead(d, "GetChallengeProgress", func(r GoalRepository) ([]*domain.UserGoalProgress, error) {
+		return r.GetChallengeProgress(ctx, userID, challengeID, activeOnly)
+	}, nil)
+}
+
+// StreamUserProgress reads from the primary backend. There is no fallback: rows may
+// already have been passed to fn when the primary fails.
+func (d *DualWriteGoalRepository) StreamUserProgress(ctx context.Context, userID string, activeOnly bool, fn func(*domain.UserGoalProgress) error, opts ...StreamOption) error {
+	return d.primary.StreamUserProgress(ctx, userID, activeOnly, fn, opts...)
+}
+
+// StreamChallengeProgress reads from the primary backend without fallback, like
+// StreamUserProgress.
+func (d *DualWriteGoalRepository) StreamChallengeProgress(ctx context.Context, userID, challengeID string, activeOnly bool, fn func(*domain.UserGoalProgress) error, opts ...StreamOption) error {
+	return d.primary.StreamChallengeProgress(ctx, userID, challengeID, activeOnly, fn, opts...)
+}
+
+// GetProgressForPairs reads from the primary backend.
+func (d *DualWriteGoalRepository) GetProgressForPairs(ctx context.Context, pairs []UserChallengePair, activeOnly bool) (map[UserChallengePair][]*domain.UserGoalProgress, error) {
+	return dualRead(d, "GetProgressForPairs", func(r GoalRepository) (map[UserChallengePair][]*domain.UserGoalProgress, error) {
+		return r.GetProgressForPairs(ctx, pairs, activeOnly)
+	}, nil)
+}
+
+// GetUserProgressPage reads from the primary backend.
+func (d *DualWriteGoalRepository) GetUserProgressPage(ctx context.Context, userID string, opts PageOptions) ([]*domain.UserGoalProgress, string, error) {
+	var next string
+	rows, err := dualRead(d, "GetUserProgressPage", func(r GoalRepository) ([]*domain.UserGoalProgress, error) {
+		var err error
+		var rows []*domain.UserGoalProgress
+		rows, next, err = r.GetUserProgressPage(ctx, userID, opts)
+		return rows, err
+	}, nil)
+	return rows, next, err
+}
+
+// GetChallengeProgressPage reads from the primary backend.
+func (d *DualWriteGoalRepository) GetChallengeProgressPage(ctx context.Context, userID, challengeID string, opts PageOptions) ([]*domain.UserGoalProgress, string, error) {
+	var next string
+	rows, err := dualRead(d, "GetChallengeProgressPage", func(r GoalRepository) ([]*domain.UserGoalProgress, error) {
+		var err error
+		var rows []*domain.UserGoalProgress
+		rows, next, err = r.GetChallengeProgressPage(ctx, userID, challengeID, opts)
+		return rows, err
+	}, nil)
+	return rows, next, err
+}
+
+// UpsertProgress writes to both backends (see DualWriteGoalRepository).
+func (d *DualWriteGoalRepository) UpsertProgress(ctx context.Context, progress *domain.UserGoalProgress) error {
+	return dualWriteErr(d, "UpsertProgress", func(r GoalRepository) error {
+		return r.UpsertProgress(ctx, progress)
+	})
+}
+
+// BatchUpsertProgress writes to both backends.
+func (d *DualWriteGoalRepository) BatchUpsertProgress(ctx context.Context, updates []*domain.UserGoalProgress) error {
+	return dualWriteErr(d, "BatchUpsertProgress", func(r GoalRepository) error {
+		return r.BatchUpsertProgress(ctx, updates)
+	})
+}
+
+// BatchUpsertProgressWithCOPY writes to both backends.
+func (d *DualWriteGoalRepository) BatchUpsertProgressWithCOPY(ctx context.Context, updates []*domain.UserGoalProgress) error {
+	return dualWriteErr(d, "BatchUpsertProgressWithCOPY", func(r GoalRepository) error {
+		return r.BatchUpsertProgressWithCOPY(ctx, updates)
+	})
+}
+
+// IncrementProgress writes to both backends.
+func (d *DualWriteGoalRepository) IncrementProgress(ctx context.Context, userID, goalID, challengeID, namespace string,
+	delta, targetValue int, isDailyIncrement bool) error {
+	return dualWriteErr(d, "IncrementProgress", func(r GoalRepository) error {
+		return r.IncrementProgress(ctx, userID, goalID, challengeID, namespace, delta, targetValue, isDailyIncrement)
+	})
+}
+
+// IncrementProgressWithCooldown writes to both backends.
+func (d *DualWriteGoalRepository) IncrementProgressWithCooldown(ctx context.Context, userID, goalID, challengeID, namespace string,
+	delta, targetValue int, cooldown time.Duration) error {
+	return dualWriteErr(d, "IncrementProgressWithCooldown", func(r GoalRepository) error {
+		return r.IncrementProgressWithCooldown(ctx, userID, goalID, challengeID, namespace, delta, targetValue, cooldown)
+	})
+}
+
+// IncrementRepeatableProgress writes to both backends. Returns the primary's result.
+func (d *DualWriteGoalRepository) IncrementRepeatableProgress(ctx context.Context, userID, goalID string, delta, targetValue, maxRepeats int) (RepeatableIncrementResult, error) {
+	return dualWrite(d, "IncrementRepeatableProgress", func(r GoalRepository) (RepeatableIncrementResult, error) {
+		return r.IncrementRepeatableProgress(ctx, userID, goalID, delta, targetValue, maxRepeats)
+	})
+}
+
+// IncrementProgressOnce writes to both backends; each records eventID in its own
+// processed_events table. Returns whether the primary applied the increment.
+func (d *DualWriteGoalRepository) IncrementProgressOnce(ctx context.Context, eventID string, inc ProgressIncrement) (bool, error) {
+	return dualWrite(d, "IncrementProgressOnce", func(r GoalRepository) (bool, error) {
+		return r.IncrementProgressOnce(ctx, eventID, inc)
+	})
+}
+
+// BatchIncrementProgressOnce writes to both backends. Returns the primary's applied count.
+func (d *DualWriteGoalRepository) BatchIncrementProgressOnce(ctx context.Context, events []EventIncrement) (int, error) {
+	return dualWrite(d, "BatchIncrementProgressOnce", func(r GoalRepository) (int, error) {
+		return r.BatchIncrementProgressOnce(ctx, events)
+	})
+}
+
+// BatchIncrementProgress writes to both backends.
+func (d *DualWriteGoalRepository) BatchIncrementProgress(ctx context.Context, increments []ProgressIncrement) error {
+	return dualWriteErr(d, "BatchIncrementProgress", func(r GoalRepository) error {
+		return r.BatchIncrementProgress(ctx, increments)
+	})
+}
+
+// BatchIncrementProgressReturning writes to both backends. Returns the primary's completions.
+func (d *DualWriteGoalRepository) BatchIncrementProgressReturning(ctx context.Context, increments []ProgressIncrement) ([]CompletionResult, error) {
+	return dualWrite(d, "BatchIncrementProgressReturning", func(r GoalRepository) ([]CompletionResult, error) {
+		return r.BatchIncrementProgressReturning(ctx, increments)
+	})
+}
+
+// BatchIncrementProgressWithResult writes to both backends. Returns the primary's result.
+func (d *DualWriteGoalRepository) BatchIncrementProgressWithResult(ctx context.Context, increments []ProgressIncrement) (BatchIncrementResult, error) {
+	return dualWrite(d, "BatchIncrementProgressWithResult", func(r GoalRepository) (BatchIncrementResult, error) {
+		return r.BatchIncrementProgressWithResult(ctx, increments)
+	})
+}
+
+// MarkAsClaimed writes to both backends.
+func (d *DualWriteGoalRepository) MarkAsClaimed(ctx context.Context, userID, goalID string) error {
+	return dualWriteErr(d, "MarkAsClaimed", func(r GoalRepository) error {
+		return r.MarkAsClaimed(ctx, userID, goalID)
+	})
+}
+
+// MarkAsClaimedWithDeadline writes to both backends.
+func (d *DualWriteGoalRepository) MarkAsClaimedWithDeadline(ctx context.Context, userID, goalID string, claimDeadline time.Duration) error {
+	return dualWriteErr(d, "MarkAsClaimedWithDeadline", func(r GoalRepository) error {
+		return r.MarkAsClaimedWithDeadline(ctx, userID, goalID, claimDeadline)
+	})
+}
+
+// ReserveClaim writes to both backends.
+func (d *DualWriteGoalRepository) ReserveClaim(ctx context.Context, userID, goalID string) error {
+	return dualWriteErr(d, "ReserveClaim", func(r GoalRepository) error {
+		return r.ReserveClaim(ctx, userID, goalID)
+	})
+}
+
+// ReserveClaimWithDeadline writes to both backends.
+func (d *DualWriteGoalRepository) ReserveClaimWithDeadline(ctx context.Context, userID, goalID string, claimDeadline time.Duration) error {
+	return dualWriteErr(d, "ReserveClaimWithDeadline", func(r GoalRepository) error {
+		return r.ReserveClaimWithDeadline(ctx, userID, goalID, claimDeadline)
+	})
+}
+
+// ConfirmClaim writes to both backends.
+func (d *DualWriteGoalRepository) ConfirmClaim(ctx context.Context, userID, goalID string) error {
+	return dualWriteErr(d, "ConfirmClaim", func(r GoalRepository) error {
+		return r.ConfirmClaim(ctx, userID, goalID)
+	})
+}
+
+// CancelClaim writes to both backends.
+func (d *DualWriteGoalRepository) CancelClaim(ctx context.Context, userID, goalID string) error {
+	return dualWriteErr(d, "CancelClaim", func(r GoalRepository) error {
+		return r.CancelClaim(ctx, userID, goalID)
+	})
+}
+
+// GetClaimEligibility reads from the primary backend.
+func (d *DualWriteGoalRepository) GetClaimEligibility(ctx context.Context, userID, goalID string, prerequisiteGoalIDs []string) (*ClaimEligibility, error) {
+	return dualRead(d, "GetClaimEligibility", func(r GoalRepository) (*ClaimEligibility, error) {
+		return r.GetClaimEligibility(ctx, userID, goalID, prerequisiteGoalIDs)
+	}, nil)
+}
+
+// BeginTx starts a transaction on the primary backend and, outside WriteNewOnly mode, one
+// on the shadow backend. If the shadow transaction cannot start, the failure is logged and
+// counted and the transaction runs on the primary only.
+func (d *DualWriteGoalRepository) BeginTx(ctx context.Context) (TxRepository, error) {
+	primaryTx, err := d.primary.BeginTx(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var shadowTx TxRepository
+	if d.shadow != nil {
+		shadowTx, err = d.shadow.BeginTx(ctx)
+		if err != nil {
+			d.shadowFailed("BeginTx", err)
+			shadowTx = nil
+		}
+	}
+
+	// Reads in a transaction stay on the primary (no fallback): the old backend's rows are
+	// outside the transaction
+	routing := &DualWriteGoalRepository{
+		newRepo:      d.newRepo,
+		oldRepo:      d.oldRepo,
+		mode:         d.mode,
+		primary:      primaryTx,
+		metrics:      d.metrics,
+		logger:       d.logger,
+		verifyWindow: d.verifyWindow,
+	}
+	if shadowTx != nil {
+		routing.shadow = shadowTx
+	}
+	return &dualWriteTx{DualWriteGoalRepository: routing, primaryTx: primaryTx, shadowTx: shadowTx}, nil
+}
+
+// GetGoalsByIDs reads from the primary backend.
+func (d *DualWriteGoalRepository) GetGoalsByIDs(ctx context.Context, userID string, goalIDs []string) ([]*domain.UserGoalProgress, error) {
+	return dualRead(d, "GetGoalsByIDs", func(r GoalRepository) ([]*domain.UserGoalProgress, error) {
+		return r.GetGoalsByIDs(ctx, userID, goalIDs)
+	}, nil)
+}
+
+// GetOrInitGoals reads like GetGoalsByIDs and fills the gaps with placeholders.
+func (d *DualWriteGoalRepository) GetOrInitGoals(ctx context.Context, userID string, goals []*domain.Goal) ([]*domain.UserGoalProgress, error) {
+	existing, err := d.GetGoalsByIDs(ctx, userID, goalIDsOf(goals))
+	if err != nil {
+		return nil, err
+	}
+	return fillMissingGoals(userID, goals, existing), nil
+}
+
+// GetGoalsByIDsFiltered reads from the primary backend.
+func (d *DualWriteGoalRepository) GetGoalsByIDsFiltered(ctx context.Context, userID string, goalIDs []string, filter ProgressFilter) ([]*domain.UserGoalProgress, error) {
+	return dualRead(d, "GetGoalsByIDsFiltered", func(r GoalRepository) ([]*domain.UserGoalProgress, error) {
+		return r.GetGoalsByIDsFiltered(ctx, userID, goalIDs, filter)
+	}, nil)
+}
+
+// BulkInsert writes to both backends.
+func (d *DualWriteGoalRepository) BulkInsert(ctx context.Context, progresses []*domain.UserGoalProgress) error {
+	return dualWriteErr(d, "BulkInsert", func(r GoalRepository) error {
+		return r.BulkInsert(ctx, progresses)
+	})
+}
+
+// BulkInsertWithPriority writes to both backends and reports the primary's result. The
+// shadow evaluates the cap against its own rows.
+func (d *DualWriteGoalRepository) BulkInsertWithPriority(ctx context.Context, progresses []*domain.UserGoalProgress, priorities []int, maxRowsForUser int) ([]string, []string, error) {
+	type result struct{ inserted, skipped []string }
+	r, err := dualWrite(d, "BulkInsertWithPriority", func(r GoalRepository) (result, error) {
+		inserted, skipped, err := r.BulkInsertWithPriority(ctx, progresses, priorities, maxRowsForUser)
+		return result{inserted, skipped}, err
+	})
+	return r.inserted, r.skipped, err
+}
+
+// BulkInsertWithCOPY writes to both backends.
+func (d *DualWriteGoalRepository) BulkInsertWithCOPY(ctx context.Context, progresses []*domain.UserGoalProgress) error {
+	return dualWriteErr(d, "BulkInsertWithCOPY", func(r GoalRepository) error {
+		return r.BulkInsertWithCOPY(ctx, progresses)
+	})
+}
+
+// UpsertGoalActive writes to both backends.
+func (d *DualWriteGoalRepository) UpsertGoalActive(ctx context.Context, progress *domain.UserGoalProgress) error {
+	return dualWriteErr(d, "UpsertGoalActive", func(r GoalRepository) error {
+		return r.UpsertGoalActive(ctx, progress)
+	})
+}
+
+// UpsertGoalActiveWithOptions writes to both backends.
+func (d *DualWriteGoalRepository) UpsertGoalActiveWithOptions(ctx context.Context, progress *domain.UserGoalProgress, opts ActivationOptions) error {
+	return dualWriteErr(d, "UpsertGoalActiveWithOptions", func(r GoalRepository) error {
+		return r.UpsertGoalActiveWithOptions(ctx, progress, opts)
+	})
+}
+
+// EnsureAssigned writes to both backends. Returns the primary's count.
+func (d *DualWriteGoalRepository) EnsureAssigned(ctx context.Context, progresses []*domain.UserGoalProgress) (int64, error) {
+	return dualWrite(d, "EnsureAssigned", func(r GoalRepository) (int64, error) {
+		return r.EnsureAssigned(ctx, progresses)
+	})
+}
+
+// BatchUpsertGoalActive writes to both backends.
+func (d *DualWriteGoalRepository) BatchUpsertGoalActive(ctx context.Context, progresses []*domain.UserGoalProgress) error {
+	return dualWriteErr(d, "BatchUpsertGoalActive", func(r GoalRepository) error {
+		return r.BatchUpsertGoalActive(ctx, progresses)
+	})
+}
+
+// DeactivateChallengeGoals writes to both backends. Returns the primary's count.
+func (d *DualWriteGoalRepository) DeactivateChallengeGoals(ctx context.Context, userID, challengeID string) (int64, error) {
+	return dualWrite(d, "DeactivateChallengeGoals", func(r GoalRepository) (int64, error) {
+		return r.DeactivateChallengeGoals(ctx, userID, challengeID)
+	})
+}
+
+// ReactivateChallengeGoals writes to both backends. Returns the primary's count.
+func (d *DualWriteGoalRepository) ReactivateChallengeGoals(ctx context.Context, userID, challengeID string) (int64, error) {
+	return dualWrite(d, "ReactivateChallengeGoals", func(r GoalRepository) (int64, error) {
+		return r.ReactivateChallengeGoals(ctx, userID, challengeID)
+	})
+}
+
+// DeleteChallengeProgress writes to both backends. Returns the primary's count.
+func (d *DualWriteGoalRepository) DeleteChallengeProgress(ctx context.Context, namespace, challengeID string) (int64, error) {
+	return dualWrite(d, "DeleteChallengeProgress", func(r GoalRepository) (int64, error) {
+		return r.DeleteChallengeProgress(ctx, namespace, challengeID)
+	})
+}
+
+// GetUserGoalCount reads from the primary backend.
+func (d *DualWriteGoalRepository) GetUserGoalCount(ctx context.Context, userID string) (int, error) {
+	return dualRead(d, "GetUserGoalCount", func(r GoalRepository) (int, error) {
+		return r.GetUserGoalCount(ctx, userID)
+	}, nil)
+}
+
+// GetActiveGoals reads from the primary backend.
+func (d *DualWriteGoalRepository) GetActiveGoals(ctx context.Context, userID string) ([]*domain.UserGoalProgress, error) {
+	return dualRead(d, "GetActiveGoals", func(r GoalRepository) ([]*domain.UserGoalProgress, error) {
+		return r.GetActiveGoals(ctx, userID)
+	}, nil)
+}
+
+// GetCompletedBetween reads from the primary backend.
+func (d *DualWriteGoalRepository) GetCompletedBetween(ctx context.Context, userID string, from, to time.Time) ([]*domain.UserGoalProgress, error) {
+	return dualRead(d, "GetCompletedBetween", func(r GoalRepository) ([]*domain.UserGoalProgress, error) {
+		return r.GetCompletedBetween(ctx, userID, from, to)
+	}, nil)
+}
+
+// GetDailyGoalsEligible reads from the primary backend.
+func (d *DualWriteGoalRepository) GetDailyGoalsEligible(ctx context.Context, userID string, tz *time.Location) ([]*domain.UserGoalProgress, error) {
+	return dualRead(d, "GetDailyGoalsEligible", func(r GoalRepository) ([]*domain.UserGoalProgress, error) {
+		return r.GetDailyGoalsEligible(ctx, userID, tz)
+	}, nil)
+}
+
+// SampleMismatch is a row that differs between the two backends of a
+// DualWriteGoalRepository (see VerifySample).
+type SampleMismatch struct {
+	UserID string
+	GoalID string
+
+	// Fields names the differing columns (e.g., "progress", "status"), or is ["missing"]
+	// when the other backend has no row
+	Fields []string
+
+	// Primary is the sampled row; Other is the other backend's row, nil if missing
+	Primary *domain.UserGoalProgress
+	Other   *domain.UserGoalProgress
+}
+
+// VerifyReport is the result of VerifySample.
+type VerifyReport struct {
+	// Sampled is the number of rows compared
+	Sampled int
+
+	// Mismatches lists the sampled rows that differ, in sample order
+	Mismatches []SampleMismatch
+}
+
+// VerifySample compares up to n random rows updated within the verify window (see
+// WithDualWriteVerifyWindow) between the two backends, for confidence before switching
+// modes. Rows are sampled from the primary backend, which must implement ProgressSampler,
+// and looked up in the other one (the old backend in WriteNewOnly mode).
+//
+// Timestamps stamped with NOW() by each backend (completed_at, claimed_at, forfeited_at)
+// are compared by presence only; updated_at and created_at are not compared. A row written
+// between the two reads can be reported, so re-check mismatches before acting on them.
+func (d *DualWriteGoalRepository) VerifySample(ctx context.Context, n int) (*VerifyReport, error) {
+	sampler, ok := d.primary.(ProgressSampler)
+	if !ok {
+		return nil, errors.ErrValidationFailed("primary", "backend does not implement ProgressSampler")
+	}
+
+	other := d.oldRepo
+	if d.mode == WriteBothReadOld {
+		other = d.newRepo
+	}
+
+	rows, err := sampler.SampleRecentProgress(ctx, time.Now().Add(-d.verifyWindow), n)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &VerifyReport{Sampled: len(rows), Mismatches: []SampleMismatch{}}
+	for _, row := range rows {
+		otherRow, err := other.GetProgress(ctx, row.UserID, row.GoalID)
+		if err != nil {
+			return nil, err
+		}
+
+		fields := []string{"missing"}
+		if otherRow != nil {
+			fields = diffProgressFields(row, otherRow)
+		}
+		if len(fields) > 0 {
+			report.Mismatches = append(report.Mismatches, SampleMismatch{
+				UserID: row.UserID, GoalID: row.GoalID, Fields: fields, Primary: row, Other: otherRow,
+			})
+		}
+	}
+
+	return report, nil
+}
+
+// diffProgressFields returns the columns that differ between two copies of a row.
+func diffProgressFields(a, b *domain.UserGoalProgress) []string {
+	var fields []string
+	check := func(name string, differs bool) {
+		if differs {
+			fields = append(fields, name)
+		}
+	}
+
+	check("challenge_id", a.ChallengeID != b.ChallengeID)
+	check("namespace", a.Namespace != b.Namespace)
+	check("progress", a.Progress != b.Progress)
+	check("status", a.Status != b.Status)
+	check("completed_at", (a.CompletedAt == nil) != (b.CompletedAt == nil))
+	check("claimed_at", (a.ClaimedAt == nil) != (b.ClaimedAt == nil))
+	check("is_active", a.IsActive != b.IsActive)
+	check("expires_at", !equalTimePtr(a.ExpiresAt, b.ExpiresAt))
+	check("forfeited_at", (a.ForfeitedAt == nil) != (b.ForfeitedAt == nil))
+	check("attempts", a.Attempts != b.Attempts)
+	check("order_index", a.OrderIndex != b.OrderIndex)
+	check("completions", a.Completions != b.Completions)
+
+	return fields
+}
+
+// equalTimePtr reports whether two optional times are both unset or the same instant.
+func equalTimePtr(a, b *time.Time) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return a.Equal(*b)
+}
+
+// dualWriteTx is the TxRepository returned by DualWriteGoalRepository.BeginTx. It routes
+// like its parent over one transaction per backend (shadowTx is nil when only the primary
+// transaction is open).
+type dualWriteTx struct {
+	*DualWriteGoalRepository
+
+	primaryTx TxRepository
+	shadowTx  TxRepository
+}
+
+// GetProgressForUpdate locks and reads the row in the primary transaction.
+func (t *dualWriteTx) GetProgressForUpdate(ctx context.Context, userID, goalID string) (*domain.UserGoalProgress, error) {
+	return t.primaryTx.GetProgressForUpdate(ctx, userID, goalID)
+}
+
+// ClaimAndRecordGrant claims in the primary transaction, then in the shadow one.
+func (t *dualWriteTx) ClaimAndRecordGrant(ctx context.Context, userID, goalID string, grant GrantRecord) error {
+	if err := t.primaryTx.ClaimAndRecordGrant(ctx, userID, goalID, grant); err != nil {
+		return err
+	}
+	if t.shadowTx != nil {
+		if err := t.shadowTx.ClaimAndRecordGrant(ctx, userID, goalID, grant); err != nil {
+			t.shadowFailed("ClaimAndRecordGrant", err)
+		}
+	}
+	return nil
+}
+
+// ClaimAllOrNothing claims in the primary transaction, then in the shadow one.
+func (t *dualWriteTx) ClaimAllOrNothing(ctx context.Context, userID string, goalIDs []string) error {
+	if err := t.primaryTx.ClaimAllOrNothing(ctx, userID, goalIDs); err != nil {
+		return err
+	}
+	if t.shadowTx != nil {
+		if err := t.shadowTx.ClaimAllOrNothing(ctx, userID, goalIDs); err != nil {
+			t.shadowFailed("ClaimAllOrNothing", err)
+		}
+	}
+	return nil
+}
+
+// Commit commits the primary transaction, then the shadow one. If the primary commit
+// fails, the shadow transaction is rolled back and the error returned; a failed shadow
+// commit is logged and counted.
+func (t *dualWriteTx) Commit() error {
+	if err := t.primaryTx.Commit(); err != nil {
+		if t.shadowTx != nil {
+			_ = t.shadowTx.Rollback()
+		}
+		return err
+	}
+	if t.shadowTx != nil {
+		if err := t.shadowTx.Commit(); err != nil {
+			t.shadowFailed("Commit", err)
+		}
+	}
+	return nil
+}
+
+// Rollback rolls back both transactions and returns the primary's error.
+func (t *dualWriteTx) Rollback() error {
+	if t.shadowTx != nil {
+		_ = t.shadowTx.Rollback()
+	}
+	return t.primaryTx.Rollback()
+}
@@ -0,0 +1,346 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/AccelByte/extend-challenge-common/pkg/domain"
+	customerrors "github.com/AccelByte/extend-challenge-common/pkg/errors"
+)
+
+// memRepo is an in-memory backend that records its calls in a shared log. Methods the
+// tests do not use fall through to the nil embedded interface and panic.
+type memRepo struct {
+	GoalRepository
+
+	name      string
+	log       *callLog
+	rows      map[snapshotKey]*domain.UserGoalProgress
+	failWrite error
+	failRead  error
+}
+
+func newMemRepo(name string, log *callLog) *memRepo {
+	return &memRepo{name: name, log: log, rows: make(map[snapshotKey]*domain.UserGoalProgress)}
+}
+
+func (m *memRepo) put(p domain.UserGoalProgress) {
+	m.rows[snapshotKey{userID: p.UserID, goalID: p.GoalID}] = &p
+}
+
+func (m *memRepo) GetProgress(_ context.Context, userID, goalID string) (*domain.UserGoalProgress, error) {
+	m.log.calls = append(m.log.calls, m.name+" GetProgress")
+	if m.failRead != nil {
+		return nil, m.failRead
+	}
+	return m.rows[snapshotKey{userID: userID, goalID: goalID}], nil
+}
+
+func (m *memRepo) UpsertProgress(_ context.Context, progress *domain.UserGoalProgress) error {
+	m.log.calls = append(m.log.calls, m.name+" UpsertProgress")
+	if m.failWrite != nil {
+		return m.failWrite
+	}
+	m.put(*progress)
+	return nil
+}
+
+func (m *memRepo) BatchIncrementProgressReturning(_ context.Context, increments []ProgressIncrement) ([]CompletionResult, error) {
+	m.log.calls = append(m.log.calls, m.name+" BatchIncrementProgressReturning")
+	if m.failWrite != nil {
+		return nil, m.failWrite
+	}
+	results := []CompletionResult{}
+	for _, inc := range increments {
+		row := m.rows[snapshotKey{userID: inc.UserID, goalID: inc.GoalID}]
+		row.Progress += int64(inc.Delta)
+		if row.Progress >= int64(inc.TargetValue) {
+			results = append(results, CompletionResult{UserID: inc.UserID, GoalID: inc.GoalID, Status: domain.GoalStatusCompleted})
+		}
+	}
+	return results, nil
+}
+
+func (m *memRepo) BeginTx(context.Context) (TxRepository, error) {
+	m.log.calls = append(m.log.calls, m.name+" BeginTx")
+	return &memTx{memRepo: m}, nil
+}
+
+func (m *memRepo) SampleRecentProgress(_ context.Context, _ time.Time, n int) ([]*domain.UserGoalProgress, error) {
+	rows := make([]*domain.UserGoalProgress, 0, len(m.rows))
+	for _, row := range m.rows {
+		rows = append(rows, row)
+	}
+	sort.Slice(rows, func(i, j int) bool { return rows[i].GoalID < rows[j].GoalID })
+	if len(rows) > n {
+		rows = rows[:n]
+	}
+	return rows, nil
+}
+
+// memTx writes straight through to its memRepo.
+type memTx struct {
+	*memRepo
+	failCommit error
+}
+
+func (t *memTx) GetProgressForUpdate(ctx context.Context, userID, goalID string) (*domain.UserGoalProgress, error) {
+	return t.GetProgress(ctx, userID, goalID)
+}
+
+func (t *memTx) ClaimAndRecordGrant(context.Context, string, string, GrantRecord) error {
+	t.log.calls = append(t.log.calls, t.name+" ClaimAndRecordGrant")
+	return t.failWrite
+}
+
+func (t *memTx) ClaimAllOrNothing(context.Context, string, []string) error {
+	t.log.calls = append(t.log.calls, t.name+" ClaimAllOrNothing")
+	return t.failWrite
+}
+
+func (t *memTx) Commit() error {
+	t.log.calls = append(t.log.calls, t.name+" Commit")
+	return t.failCommit
+}
+
+func (t *memTx) Rollback() error {
+	t.log.calls = append(t.log.calls, t.name+" Rollback")
+	return nil
+}
+
+type fakeDualWriteMetrics struct {
+	shadowFailures []string
+	fallbacks      []string
+}
+
+func (f *fakeDualWriteMetrics) ShadowWriteFailed(op string, _ error) {
+	f.shadowFailures = append(f.shadowFailures, op)
+}
+
+func (f *fakeDualWriteMetrics) ReadFallback(op string) {
+	f.fallbacks = append(f.fallbacks, op)
+}
+
+// newDualWriteFixture returns a dual-write repository over two empty in-memory backends.
+func newDualWriteFixture(t *testing.T, mode DualWriteMode) (*DualWriteGoalRepository, *memRepo, *memRepo, *fakeDualWriteMetrics, *callLog) {
+	t.Helper()
+
+	log := &callLog{}
+	newRepo, oldRepo := newMemRepo("new", log), newMemRepo("old", log)
+	metrics := &fakeDualWriteMetrics{}
+	repo, err := NewDualWriteGoalRepository(newRepo, oldRepo, mode,
+		WithDualWriteMetrics(metrics), WithDualWriteLogger(slog.New(slog.NewTextHandler(io.Discard, nil))))
+	if err != nil {
+		t.Fatalf("NewDualWriteGoalRepository failed: %v", err)
+	}
+	return repo, newRepo, oldRepo, metrics, log
+}
+
+func TestNewDualWriteGoalRepository_UnknownMode(t *testing.T) {
+	_, err := NewDualWriteGoalRepository(nil, nil, "write_old_only")
+	assertErrorCode(t, err, customerrors.ErrCodeValidationFailed)
+}
+
+func TestDualWriteGoalRepository_Routing(t *testing.T) {
+	tests := []struct {
+		mode DualWriteMode
+		want []string
+	}{
+		{WriteBothReadOld, []string{"old UpsertProgress", "new UpsertProgress", "old GetProgress"}},
+		{WriteBothReadNew, []string{"new UpsertProgress", "old UpsertProgress", "new GetProgress"}},
+		{WriteNewOnly, []string{"new UpsertProgress", "new GetProgress"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.mode), func(t *testing.T) {
+			repo, _, _, metrics, log := newDualWriteFixture(t, tt.mode)
+			ctx := context.Background()
+
+			if err := repo.UpsertProgress(ctx, &domain.UserGoalProgress{UserID: "u1", GoalID: "kills", Progress: 3}); err != nil {
+				t.Fatalf("UpsertProgress failed: %v", err)
+			}
+			got, err := repo.GetProgress(ctx, "u1", "kills")
+			if err != nil || got == nil || got.Progress != 3 {
+				t.Fatalf("GetProgress = %+v, %v", got, err)
+			}
+
+			assertCalls(t, log.calls, tt.want...)
+			if len(metrics.shadowFailures) != 0 || len(metrics.fallbacks) != 0 {
+				t.Errorf("Unexpected metrics: %+v", metrics)
+			}
+		})
+	}
+}
+
+func TestDualWriteGoalRepository_ReadFallback(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("row missing from the new backend", func(t *testing.T) {
+		repo, _, oldRepo, metrics, log := newDualWriteFixture(t, WriteBothReadNew)
+		oldRepo.put(domain.UserGoalProgress{UserID: "u1", GoalID: "kills", Progress: 7})
+
+		got, err := repo.GetProgress(ctx, "u1", "kills")
+		if err != nil || got == nil || got.Progress != 7 {
+			t.Fatalf("GetProgress = %+v, %v, want the old backend's row", got, err)
+		}
+		assertCalls(t, log.calls, "new GetProgress", "old GetProgress")
+		assertCalls(t, metrics.fallbacks, "GetProgress")
+	})
+
+	t.Run("new backend failing", func(t *testing.T) {
+		repo, newRepo, oldRepo, _, _ := newDualWriteFixture(t, WriteBothReadNew)
+		newRepo.failRead = errors.New("partition offline")
+		oldRepo.put(domain.UserGoalProgress{UserID: "u1", GoalID: "kills", Progress: 7})
+
+		if got, err := repo.GetProgress(ctx, "u1", "kills"); err != nil || got == nil {
+			t.Errorf("GetProgress = %+v, %v, want the old backend's row", got, err)
+		}
+	})
+
+	t.Run("no fallback once the migration is complete", func(t *testing.T) {
+		repo, _, oldRepo, metrics, log := newDualWriteFixture(t, WriteNewOnly)
+		oldRepo.put(domain.UserGoalProgress{UserID: "u1", GoalID: "kills", Progress: 7})
+
+		if got, err := repo.GetProgress(ctx, "u1", "kills"); err != nil || got != nil {
+			t.Errorf("GetProgress = %+v, %v, want nil", got, err)
+		}
+		assertCalls(t, log.calls, "new GetProgress")
+		assertCalls(t, metrics.fallbacks)
+	})
+}
+
+func TestDualWriteGoalRepository_ShadowFailure(t *testing.T) {
+	ctx := context.Background()
+	inc := []ProgressIncrement{{UserID: "u1", GoalID: "kills", Delta: 5, TargetValue: 5}}
+	seed := domain.UserGoalProgress{UserID: "u1", GoalID: "kills"}
+
+	t.Run("shadow failure is counted, not returned", func(t *testing.T) {
+		repo, newRepo, oldRepo, metrics, _ := newDualWriteFixture(t, WriteBothReadNew)
+		newRepo.put(seed)
+		oldRepo.put(seed)
+		oldRepo.failWrite = errors.New("old table locked")
+
+		results, err := repo.BatchIncrementProgressReturning(ctx, inc)
+		if err != nil {
+			t.Fatalf("BatchIncrementProgressReturning failed: %v", err)
+		}
+		if len(results) != 1 || results[0].GoalID != "kills" {
+			t.Errorf("Results = %+v, want the primary's completion", results)
+		}
+		assertCalls(t, metrics.shadowFailures, "BatchIncrementProgressReturning")
+	})
+
+	t.Run("primary failure is returned and skips the shadow", func(t *testing.T) {
+		repo, newRepo, _, metrics, log := newDualWriteFixture(t, WriteBothReadNew)
+		reason := errors.New("partition offline")
+		newRepo.failWrite = reason
+
+		err := repo.UpsertProgress(ctx, &seed)
+		if !errors.Is(err, reason) {
+			t.Errorf("UpsertProgress error = %v, want %v", err, reason)
+		}
+		assertCalls(t, log.calls, "new UpsertProgress")
+		assertCalls(t, metrics.shadowFailures)
+	})
+}
+
+func TestDualWriteGoalRepository_Transaction(t *testing.T) {
+	ctx := context.Background()
+	grant := GrantRecord{}
+
+	t.Run("commits primary then shadow", func(t *testing.T) {
+		repo, _, _, metrics, log := newDualWriteFixture(t, WriteBothReadOld)
+
+		tx, err := repo.BeginTx(ctx)
+		if err != nil {
+			t.Fatalf("BeginTx failed: %v", err)
+		}
+		if err := tx.ClaimAndRecordGrant(ctx, "u1", "kills", grant); err != nil {
+			t.Fatalf("ClaimAndRecordGrant failed: %v", err)
+		}
+		if err := tx.Commit(); err != nil {
+			t.Fatalf("Commit failed: %v", err)
+		}
+
+		assertCalls(t, log.calls, "old BeginTx", "new BeginTx", "old ClaimAndRecordGrant", "new ClaimAndRecordGrant", "old Commit", "new Commit")
+		assertCalls(t, metrics.shadowFailures)
+	})
+
+	t.Run("shadow commit failure is tolerated", func(t *testing.T) {
+		repo, _, _, metrics, _ := newDualWriteFixture(t, WriteBothReadNew)
+
+		tx, err := repo.BeginTx(ctx)
+		if err != nil {
+			t.Fatalf("BeginTx failed: %v", err)
+		}
+		tx.(*dualWriteTx).shadowTx.(*memTx).failCommit = errors.New("serialization failure")
+		if err := tx.Commit(); err != nil {
+			t.Errorf("Commit failed: %v", err)
+		}
+		assertCalls(t, metrics.shadowFailures, "Commit")
+	})
+
+	t.Run("primary commit failure rolls back the shadow", func(t *testing.T) {
+		repo, _, _, _, log := newDualWriteFixture(t, WriteBothReadNew)
+
+		tx, err := repo.BeginTx(ctx)
+		if err != nil {
+			t.Fatalf("BeginTx failed: %v", err)
+		}
+		tx.(*dualWriteTx).primaryTx.(*memTx).failCommit = errors.New("serialization failure")
+		if err := tx.Commit(); err == nil {
+			t.Error("Expected Commit to fail")
+		}
+		assertCalls(t, log.calls, "new BeginTx", "old BeginTx", "new Commit", "old Rollback")
+	})
+}
+
+func TestDualWriteGoalRepository_VerifySample(t *testing.T) {
+	ctx := context.Background()
+	repo, newRepo, oldRepo, _, _ := newDualWriteFixture(t, WriteBothReadNew)
+
+	completedAt := time.Now()
+	for _, row := range []domain.UserGoalProgress{
+		{UserID: "u1", GoalID: "a-same", Progress: 3, Status: domain.GoalStatusInProgress, IsActive: true},
+		{UserID: "u1", GoalID: "b-drifted", Progress: 10, Status: domain.GoalStatusCompleted, CompletedAt: &completedAt, IsActive: true, Attempts: 4},
+		{UserID: "u1", GoalID: "c-missing", Progress: 1, Status: domain.GoalStatusInProgress},
+	} {
+		newRepo.put(row)
+	}
+	// The same instant stamped separately by each backend is not a mismatch
+	oldCompletedAt := completedAt.Add(time.Millisecond)
+	oldRepo.put(domain.UserGoalProgress{UserID: "u1", GoalID: "a-same", Progress: 3, Status: domain.GoalStatusInProgress, IsActive: true})
+	oldRepo.put(domain.UserGoalProgress{UserID: "u1", GoalID: "b-drifted", Progress: 9, Status: domain.GoalStatusInProgress, CompletedAt: &oldCompletedAt, IsActive: true, Attempts: 4})
+
+	report, err := repo.VerifySample(ctx, 10)
+	if err != nil {
+		t.Fatalf("VerifySample failed: %v", err)
+	}
+	if report.Sampled != 3 || len(report.Mismatches) != 2 {
+		t.Fatalf("Report = %+v, want 3 sampled and 2 mismatches", report)
+	}
+	if m := report.Mismatches[0]; m.GoalID != "b-drifted" || m.Other == nil {
+		t.Errorf("Mismatch 0 = %+v, want b-drifted", m)
+	} else {
+		assertCalls(t, m.Fields, "progress", "status")
+	}
+	if m := report.Mismatches[1]; m.GoalID != "c-missing" || m.Other != nil {
+		t.Errorf("Mismatch 1 = %+v, want c-missing without another row", m)
+	} else {
+		assertCalls(t, m.Fields, "missing")
+	}
+
+	t.Run("primary without a sampler", func(t *testing.T) {
+		repo, err := NewDualWriteGoalRepository(struct{ GoalRepository }{}, oldRepo, WriteBothReadNew)
+		if err != nil {
+			t.Fatalf("NewDualWriteGoalRepository failed: %v", err)
+		}
+		_, err = repo.VerifySample(ctx, 10)
+		assertErrorCode(t, err, customerrors.ErrCodeValidationFailed)
+	})
+}
@@ -0,0 +1,1266 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/AccelByte/extend-challenge-common/pkg/domain"
+	"github.com/AccelByte/extend-challenge-common/pkg/errors"
+
+	"github.com/lib/pq"
+)
+
+// executor runs the progress queries shared by PostgresGoalRepository and
+// PostgresTxRepository. Each operation is implemented once here and runs against
+// either the connection pool or the caller's transaction; the repository types
+// only choose the executor and delegate.
+//
+// The pool and transaction variants still differ where callers depend on it:
+//   - Pool writes take the shutdown gate per call; a transaction holds it from
+//     BeginTx until Commit or Rollback.
+//   - COPY paths open and commit their own transaction on the pool and reuse the
+//     caller's transaction otherwise.
+//   - UpsertProgress, IncrementProgress and the COPY merge upsert rows inside a
+//     transaction but only update existing active rows on the pool (M3 Phase 9
+//     lazy materialization). Both statements are kept side by side below.
+//     With WithRequireAssignmentForIncrements, transactional increments use the pool's
+//     UPDATE-only statements too (see upsertsIncrements).
+type executor struct {
+	repo *PostgresGoalRepository
+	q    queryer
+	tx   *sql.Tx // Caller's transaction; nil on the pool
+}
+
+// exec returns an executor bound to the connection pool.
+func (r *PostgresGoalRepository) exec() executor {
+	return executor{repo: r, q: r.db}
+}
+
+// exec returns an executor bound to this transaction.
+func (r *PostgresTxRepository) exec() executor {
+	return executor{repo: r.parent, q: r.tx, tx: r.tx}
+}
+
+func (e executor) inTx() bool {
+	return e.tx != nil
+}
+
+// upsertsIncrements reports whether increments may insert missing rows: only inside a
+// transaction, and only unless WithRequireAssignmentForIncrements is set.
+func (e executor) upsertsIncrements() bool {
+	return e.inTx() && !e.repo.requireAssignment
+}
+
+// op names an operation for error reporting; transactional operations carry an
+// " in transaction" suffix.
+func (e executor) op(name string) string {
+	if e.inTx() {
+		return name + " in transaction"
+	}
+	return name
+}
+
+// acquireGate takes the shutdown gate for a pool write and returns its release.
+// Inside a transaction the gate is already held, so it is a no-op.
+func (e executor) acquireGate() (func(), error) {
+	if e.inTx() {
+		return func() {}, nil
+	}
+	if err := e.repo.acquireGate(); err != nil {
+		return nil, err
+	}
+	return e.repo.releaseGate, nil
+}
+
+// withTx runs fn in the caller's transaction, or in a new one on the pool that is
+// committed when fn succeeds. label names the new transaction in errors.
+func (e executor) withTx(ctx context.Context, label string, fn func(tx *sql.Tx) error) error {
+	if e.inTx() {
+		return fn(e.tx)
+	}
+
+	tx, err := e.repo.db.BeginTx(ctx, nil)
+	if err != nil {
+		return errors.ErrDatabaseError("begin transaction for "+label, err)
+	}
+	if err := fn(tx); err != nil {
+		_ = tx.Rollback()
+		return err
+	}
+	if err := tx.Commit(); err != nil {
+		return errors.ErrDatabaseError("commit "+label+" transaction", err)
+	}
+	return nil
+}
+
+// withUserLocks runs fn holding the per-user locks (see user_locking.go). On the pool
+// this may open a short transaction; inside a transaction the locks are held until it ends.
+func (e executor) withUserLocks(ctx context.Context, userIDs []string, fn func(q queryer) error) error {
+	if !e.inTx() {
+		return e.repo.withUserLocks(ctx, userIDs, fn)
+	}
+	if err := e.repo.lockUsers(ctx, e.tx, userIDs); err != nil {
+		return errors.ErrDatabaseError("lock users for batch increment in transaction", err)
+	}
+	return fn(e.tx)
+}
+
+// queryProgress runs a SELECT of progressColumns and scans every row.
+func (e executor) queryProgress(ctx context.Context, operation, query string, args ...interface{}) ([]*domain.UserGoalProgress, error) {
+	rows, err := e.q.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, errors.ErrDatabaseError(operation, err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	return e.repo.scanProgressRows(rows)
+}
+
+// execRowsAffected runs a statement and returns the number of rows it affected.
+func (e executor) execRowsAffected(ctx context.Context, operation, query string, args ...interface{}) (int64, error) {
+	result, err := e.q.ExecContext(ctx, query, args...)
+	if err != nil {
+		return 0, errors.ErrDatabaseError(operation, err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return 0, errors.ErrDatabaseError("check rows affected", err)
+	}
+
+	return rowsAffected, nil
+}
+
+// copyIn streams n rows into a temp table with COPY FROM STDIN. suffix qualifies the
+// error operations (e.g. " for BulkInsert").
+func (e executor) copyIn(ctx context.Context, tx *sql.Tx, suffix, copyStmt string, n int, row func(i int) []interface{}) error {
+	stmt, err := tx.PrepareContext(ctx, copyStmt)
+	if err != nil {
+		return errors.ErrDatabaseError(e.op("prepare COPY statement"+suffix), err)
+	}
+	defer func() { _ = stmt.Close() }()
+
+	for i := 0; i < n; i++ {
+		if _, err := stmt.ExecContext(ctx, row(i)...); err != nil {
+			return errors.ErrDatabaseError(e.op("execute COPY row"+suffix), err)
+		}
+	}
+
+	// Flush buffered rows to the temp table
+	if _, err := stmt.ExecContext(ctx); err != nil {
+		return errors.ErrDatabaseError(e.op("flush COPY to temp table"+suffix), err)
+	}
+
+	return nil
+}
+
+// Reads
+
+func (e executor) getProgress(ctx context.Context, userID, goalID string, forUpdate bool) (*domain.UserGoalProgress, error) {
+	columns := e.repo.progressColumns()
+	if e.repo.lastDelta {
+		columns += ", last_delta" // scanned into UserGoalProgress.LastDelta
+	}
+	query := "SELECT " + columns + " FROM user_goal_progress WHERE user_id = $1 AND goal_id = $2"
+	operation := e.op("get progress")
+	if forUpdate {
+		query += " FOR UPDATE"
+		operation = "get progress for update"
+	}
+
+	var progress domain.UserGoalProgress
+	dest := e.repo.progressScanDest(&progress)
+	if e.repo.lastDelta {
+		dest = append(dest, &progress.LastDelta)
+	}
+	err := e.q.QueryRowContext(ctx, query, userID, goalID).Scan(dest...)
+
+	if err == sql.ErrNoRows {
+		return nil, nil // No progress record exists (lazy initialization)
+	}
+
+	if err != nil {
+		return nil, errors.ErrDatabaseError(operation, err)
+	}
+
+	return &progress, nil
+}
+
+func (e executor) getProgressRequired(ctx context.Context, userID, goalID string) (*domain.UserGoalProgress, error) {
+	progress, err := e.getProgress(ctx, userID, goalID, false)
+	if err != nil {
+		return nil, err
+	}
+	if progress == nil {
+		return nil, errors.ErrProgressNotFound(userID, goalID)
+	}
+	return progress, nil
+}
+
+func (e executor) streamUserProgress(ctx context.Context, userID string, activeOnly bool, fn func(*domain.UserGoalProgress) error, opts []StreamOption) error {
+	query := "SELECT " + e.repo.progressColumns() + " FROM user_goal_progress WHERE user_id = $1"
+
+	// M3 Phase 4: Add is_active filter when activeOnly is true
+	if activeOnly {
+		query += e.repo.activeOnlyClause()
+	}
+
+	query += " ORDER BY order_index ASC, created_at ASC"
+
+	return e.streamProgress(ctx, e.op("get user progress"), query, []interface{}{userID}, fn, opts)
+}
+
+func (e executor) getUserProgress(ctx context.Context, userID string, activeOnly bool) ([]*domain.UserGoalProgress, error) {
+	var results []*domain.UserGoalProgress
+	if err := e.streamUserProgress(ctx, userID, activeOnly, collectProgress(&results), nil); err != nil {
+		return nil, err
+	}
+	return results, nil
+}
+
+func (e executor) streamChallengeProgress(ctx context.Context, userID, challengeID string, activeOnly bool, fn func(*domain.UserGoalProgress) error, opts []StreamOption) error {
+	query := "SELECT " + e.repo.progressColumns() + " FROM user_goal_progress WHERE user_id = $1 AND challenge_id = $2"
+
+	// M3 Phase 4: Add is_active filter when activeOnly is true
+	if activeOnly {
+		query += e.repo.activeOnlyClause()
+	}
+
+	query += " ORDER BY order_index ASC, created_at ASC"
+
+	return e.streamProgress(ctx, e.op("get challenge progress"), query, []interface{}{userID, challengeID}, fn, opts)
+}
+
+func (e executor) getChallengeProgress(ctx context.Context, userID, challengeID string, activeOnly bool) ([]*domain.UserGoalProgress, error) {
+	var results []*domain.UserGoalProgress
+	if err := e.streamChallengeProgress(ctx, userID, challengeID, activeOnly, collectProgress(&results), nil); err != nil {
+		return nil, err
+	}
+	return results, nil
+}
+
+func (e executor) getActiveGoals(ctx context.Context, userID string) ([]*domain.UserGoalProgress, error) {
+	query := "SELECT " + e.repo.progressColumns() + " FROM user_goal_progress WHERE user_id = $1" +
+		e.repo.activeOnlyClause() + " ORDER BY challenge_id, goal_id"
+
+	return e.queryProgress(ctx, e.op("get active goals"), query, userID)
+}
+
+func (e executor) getUserGoalCount(ctx context.Context, userID string) (int, error) {
+	query := `SELECT COUNT(*) FROM user_goal_progress WHERE user_id = $1`
+
+	var count int
+	if err := e.q.QueryRowContext(ctx, query, userID).Scan(&count); err != nil {
+		return 0, errors.ErrDatabaseError(e.op("get user goal count"), err)
+	}
+
+	return count, nil
+}
+
+// Progress writes
+
+// upsertProgressQuery is the pool UpsertProgress, including the M3 Phase 5
+// is_active, assigned_at and expires_at columns.
+var upsertProgressQuery = `
+	INSERT INTO user_goal_progress (
+		user_id, goal_id, challenge_id, namespace,
+		progress, status, completed_at, updated_at,
+		is_active, assigned_at, expires_at
+	) VALUES (
+		$1, $2, $3, $4, $5, $6, $7, NOW(), $8, $9, $10
+	)
+	ON CONFLICT (user_id, goal_id) DO UPDATE SET
+		progress = EXCLUDED.progress,
+		status = EXCLUDED.status,
+		completed_at = EXCLUDED.completed_at,
+		updated_at = NOW(),
+		is_active = EXCLUDED.is_active,
+		assigned_at = EXCLUDED.assigned_at,
+		expires_at = EXCLUDED.expires_at
+	WHERE ` + protectedStatusGuard("user_goal_progress.status") + `
+`
+
+// txUpsertProgressQuery is the transactional UpsertProgress. It leaves the assignment
+// columns untouched, so callers that never set IsActive do not deactivate goals.
+var txUpsertProgressQuery = `
+	INSERT INTO user_goal_progress (
+		user_id, goal_id, challenge_id, namespace,
+		progress, status, completed_at, updated_at
+	) VALUES (
+		$1, $2, $3, $4, $5, $6, $7, NOW()
+	)
+	ON CONFLICT (user_id, goal_id) DO UPDATE SET
+		progress = EXCLUDED.progress,
+		status = EXCLUDED.status,
+		completed_at = EXCLUDED.completed_at,
+		updated_at = NOW()
+	WHERE ` + protectedStatusGuard("user_goal_progress.status") + `
+`
+
+func (e executor) upsertProgress(ctx context.Context, progress *domain.UserGoalProgress) error {
+	query := txUpsertProgressQuery
+	args := []interface{}{
+		progress.UserID,
+		progress.GoalID,
+		progress.ChallengeID,
+		progress.Namespace,
+		progress.Progress,
+		progress.Status,
+		progress.CompletedAt,
+	}
+	if !e.inTx() {
+		query = upsertProgressQuery
+		args = append(args, progress.IsActive, progress.AssignedAt, progress.ExpiresAt)
+	}
+
+	if _, err := e.q.ExecContext(ctx, query, args...); err != nil {
+		return errors.ErrDatabaseError(e.op("upsert progress"), err)
+	}
+
+	return nil
+}
+
+// batchUpsertProgressQuery is filled with the VALUES placeholders. With
+// WithConfigChecksum, every row of batchUpsertProgressChecksumQuery shares a trailing
+// config checksum parameter. The pool variant appends activeOnlyUpsertPredicate.
+var batchUpsertProgressQuery = `
+	INSERT INTO user_goal_progress (
+		user_id, goal_id, challenge_id, namespace,
+		progress, status, completed_at, updated_at
+	) VALUES %s
+	ON CONFLICT (user_id, goal_id) DO UPDATE SET
+		progress = EXCLUDED.progress,
+		status = EXCLUDED.status,
+		completed_at = EXCLUDED.completed_at,
+		updated_at = NOW()
+	WHERE ` + protectedStatusGuard("user_goal_progress.status") + `
+`
+
+// activeOnlyUpsertPredicate restricts pool batch upserts to assigned goals (M3).
+const activeOnlyUpsertPredicate = `  AND user_goal_progress.is_active = true
+`
+
+func (e executor) batchUpsertProgress(ctx context.Context, updates []*domain.UserGoalProgress) error {
+	if len(updates) == 0 {
+		return nil
+	}
+	// ON CONFLICT DO UPDATE cannot affect a row twice in one statement
+	updates, _ = e.repo.mergeUpdates(e.op("batch upsert progress"), updates)
+
+	release, err := e.admitBatch(ctx, "batch upsert progress", len(updates), func(i int) string { return updates[i].Namespace })
+	if err != nil {
+		return err
+	}
+	defer release()
+
+	// Check PostgreSQL parameter limit (65,535 parameters)
+	// With 7 parameters per row, max is ~9,000 rows
+	if len(updates) > 9000 {
+		return fmt.Errorf("batch size exceeds PostgreSQL parameter limit: %d rows (max 9000)", len(updates))
+	}
+
+	// Build dynamic query with correct number of placeholders
+	baseQuery, checksum := batchUpsertProgressQuery, ""
+	if e.repo.configChecksum != nil {
+		baseQuery, checksum = batchUpsertProgressChecksumQuery, fmt.Sprintf(", $%d::VARCHAR(64)", len(updates)*7+1)
+	}
+	valueStrings := make([]string, 0, len(updates))
+	valueArgs := make([]interface{}, 0, len(updates)*7+1)
+
+	for i, update := range updates {
+		valueStrings = append(valueStrings, fmt.Sprintf(
+			"($%d, $%d, $%d, $%d, $%d, $%d, $%d, NOW()%s)",
+			i*7+1, i*7+2, i*7+3, i*7+4, i*7+5, i*7+6, i*7+7, checksum,
+		))
+		valueArgs = append(valueArgs,
+			update.UserID,
+			update.GoalID,
+			update.ChallengeID,
+			update.Namespace,
+			update.Progress,
+			update.Status,
+			update.CompletedAt,
+		)
+	}
+	if checksum != "" {
+		valueArgs = append(valueArgs, e.repo.configChecksumArg())
+	}
+
+	// Safe: fmt.Sprintf only builds the VALUES structure with placeholders ($1, $2, etc.)
+	// All actual values are passed via parameterized query (valueArgs), not string interpolation
+	// #nosec G201
+	query := fmt.Sprintf(baseQuery, strings.Join(valueStrings, ","))
+	if !e.inTx() {
+		query += activeOnlyUpsertPredicate
+	}
+
+	if _, err := e.q.ExecContext(ctx, query, valueArgs...); err != nil {
+		return errors.ErrDatabaseError(e.op("batch upsert progress"), err)
+	}
+
+	return nil
+}
+
+// createTempProgressTableQuery creates the session-local COPY target for progress upserts.
+// Its config_checksum column is only merged into user_goal_progress with
+// WithConfigChecksum (see mergeTempProgress).
+const createTempProgressTableQuery = `
+	CREATE TEMP TABLE IF NOT EXISTS temp_user_goal_progress (
+		user_id VARCHAR(100) NOT NULL,
+		goal_id VARCHAR(100) NOT NULL,
+		challenge_id VARCHAR(100) NOT NULL,
+		namespace VARCHAR(100) NOT NULL,
+		progress BIGINT NOT NULL,
+		status VARCHAR(20) NOT NULL,
+		completed_at TIMESTAMP NULL,
+		updated_at TIMESTAMP NOT NULL DEFAULT NOW(),
+		config_checksum VARCHAR(64) NULL
+	) ON COMMIT DROP
+`
+
+// DefaultMaxCopyBatchRows is the default number of rows BatchUpsertProgressWithCOPY stages
+// per COPY+merge cycle (see WithMaxCopyBatchRows).
+const DefaultMaxCopyBatchRows = 50_000
+
+// truncateTempProgressTableQuery empties the COPY target between cycles of one batch.
+const truncateTempProgressTableQuery = `TRUNCATE temp_user_goal_progress`
+
+// mergeTempProgressQuery is the pool COPY merge. M3 Phase 9: UPDATE-only so events for
+// unassigned goals are no-ops; only active, unclaimed rows are updated.
+var mergeTempProgressQuery = `
+	UPDATE user_goal_progress
+	SET
+		progress = temp.progress,
+		status = temp.status,
+		completed_at = temp.completed_at,
+		updated_at = NOW()
+	FROM temp_user_goal_progress AS temp
+	WHERE user_goal_progress.user_id = temp.user_id
+	  AND user_goal_progress.goal_id = temp.goal_id
+	  AND user_goal_progress.is_active = true
+	  AND ` + protectedStatusGuard("user_goal_progress.status") + `
+`
+
+// txMergeTempProgressQuery is the transactional COPY merge, an upsert that skips claimed rows.
+var txMergeTempProgressQuery = `
+	INSERT INTO user_goal_progress (
+		user_id, goal_id, challenge_id, namespace,
+		progress, status, completed_at, updated_at
+	)
+	SELECT
+		user_id, goal_id, challenge_id, namespace,
+		progress, status, completed_at, NOW()
+	FROM temp_user_goal_progress
+	ON CONFLICT (user_id, goal_id) DO UPDATE SET
+		progress = EXCLUDED.progress,
+		status = EXCLUDED.status,
+		completed_at = EXCLUDED.completed_at,
+		updated_at = NOW()
+	WHERE ` + protectedStatusGuard("user_goal_progress.status") + `
+`
+
+func (e executor) batchUpsertProgressWithCOPY(ctx context.Context, updates []*domain.UserGoalProgress) error {
+	if len(updates) == 0 {
+		return nil
+	}
+	// The merge from the temp table would apply an arbitrary one of several rows per key
+	updates, _ = e.repo.mergeUpdates(e.op("batch upsert progress with COPY"), updates)
+
+	release, err := e.admitBatch(ctx, "batch upsert progress with COPY", len(updates), func(i int) string { return updates[i].Namespace })
+	if err != nil {
+		return err
+	}
+	defer release()
+
+	return e.withTx(ctx, "COPY", func(tx *sql.Tx) error {
+		// Optional per-user serialization against concurrent increment batches
+		if err := e.repo.lockUsers(ctx, tx, progressUserIDs(updates)); err != nil {
+			return errors.ErrDatabaseError(e.op("lock users for COPY"), err)
+		}
+
+		if _, err := tx.ExecContext(ctx, createTempProgressTableQuery); err != nil {
+			return errors.ErrDatabaseError(e.op("create temp table for COPY"), err)
+		}
+
+		copyStmt := pq.CopyIn(
+			"temp_user_goal_progress",
+			"user_id", "goal_id", "challenge_id", "namespace",
+			"progress", "status", "completed_at", "updated_at", "config_checksum",
+		)
+		now := time.Now().UTC() // Always use UTC for consistency across timezones
+		checksum := e.repo.configChecksumArg()
+
+		// Batches above the row cap are staged and merged in several cycles; duplicates
+		// were merged above, so no key spans two cycles
+		for start := 0; start < len(updates); start += e.repo.maxCopyBatchRows {
+			if start > 0 {
+				if _, err := tx.ExecContext(ctx, truncateTempProgressTableQuery); err != nil {
+					return errors.ErrDatabaseError(e.op("truncate temp table for COPY"), err)
+				}
+			}
+
+			chunk := updates[start:min(start+e.repo.maxCopyBatchRows, len(updates))]
+			err := e.copyIn(ctx, tx, "", copyStmt, len(chunk), func(i int) []interface{} {
+				u := chunk[i]
+				return []interface{}{u.UserID, u.GoalID, u.ChallengeID, u.Namespace, u.Progress, u.Status, u.CompletedAt, now, checksum}
+			})
+			if err != nil {
+				return err
+			}
+
+			if err := e.mergeTempProgress(ctx, tx); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// mergeTempProgress merges the staged COPY rows into user_goal_progress.
+func (e executor) mergeTempProgress(ctx context.Context, tx *sql.Tx) error {
+	checksum := e.repo.configChecksum != nil
+	if e.inTx() {
+		query := txMergeTempProgressQuery
+		if checksum {
+			query = txMergeTempProgressChecksumQuery
+		}
+		if _, err := tx.ExecContext(ctx, query); err != nil {
+			return errors.ErrDatabaseError("merge temp table into user_goal_progress in transaction", err)
+		}
+		return nil
+	}
+
+	query := mergeTempProgressQuery
+	if checksum {
+		query = mergeTempProgressChecksumQuery
+	}
+	if _, err := tx.ExecContext(ctx, query); err != nil {
+		return errors.ErrDatabaseError("update user_goal_progress from temp table", err)
+	}
+	return nil
+}
+
+// incrementRegularQuery is the pool single increment.
+// M3 Phase 9: UPDATE-only for lazy materialization. Arguments: user, goal, delta, target,
+// progress cap (see OverflowPolicy; the column clamp when unbounded), progress ceiling.
+var incrementRegularQuery = `
+	UPDATE user_goal_progress
+	SET
+		progress = LEAST(progress::BIGINT + $3::BIGINT, GREATEST($5::BIGINT, progress)),
+		status = CASE
+			WHEN progress::BIGINT + $3::BIGINT >= $4::BIGINT THEN 'completed'
+			ELSE 'in_progress'
+		END,
+		completed_at = CASE
+			WHEN progress::BIGINT + $3::BIGINT >= $4::BIGINT AND completed_at IS NULL THEN NOW()
+			ELSE completed_at
+		END,
+		updated_at = NOW()
+	WHERE user_id = $1
+	  AND goal_id = $2
+	  AND is_active = true
+	  AND ` + protectedStatusGuard("status") + `
+	  AND ` + progressCeilingGuard("progress", "$3::BIGINT", "$6::BIGINT") + `
+`
+
+// incrementDailyQuery is the pool once-per-day increment, using timezone-safe (UTC) dates.
+// M3 Phase 9: UPDATE-only for lazy materialization. Arguments: user, goal, delta, target,
+// progress cap, progress ceiling.
+var incrementDailyQuery = `
+	UPDATE user_goal_progress
+	SET
+		progress = CASE
+			-- Same day (UTC): don't increment
+			WHEN COALESCE(last_daily_date, DATE(updated_at AT TIME ZONE 'UTC')) >= DATE(NOW() AT TIME ZONE 'UTC')
+				THEN progress
+			-- New day: increment by delta (capped, never lowering a row already above the cap)
+			ELSE LEAST(progress::BIGINT + $3::BIGINT, GREATEST($5::BIGINT, progress))
+		END,
+		status = CASE
+			-- Calculate new progress first, then check threshold
+			WHEN COALESCE(last_daily_date, DATE(updated_at AT TIME ZONE 'UTC')) >= DATE(NOW() AT TIME ZONE 'UTC') THEN
+				-- Same day, progress unchanged
+				CASE WHEN progress >= $4::BIGINT THEN 'completed' ELSE 'in_progress' END
+			ELSE
+				-- New day, check incremented progress
+				CASE WHEN progress::BIGINT + $3::BIGINT >= $4::BIGINT THEN 'completed' ELSE 'in_progress' END
+		END,
+		completed_at = CASE
+			WHEN COALESCE(last_daily_date, DATE(updated_at AT TIME ZONE 'UTC')) >= DATE(NOW() AT TIME ZONE 'UTC') THEN
+				completed_at  -- Same day, keep existing
+			WHEN progress::BIGINT + $3::BIGINT >= $4::BIGINT AND completed_at IS NULL THEN
+				NOW()  -- New day and just completed
+			ELSE
+				completed_at  -- Keep existing
+		END,
+		last_daily_date = DATE(NOW() AT TIME ZONE 'UTC'),  -- Today is counted either way
+		updated_at = NOW()  -- Always update timestamp (for daily tracking)
+	WHERE user_id = $1
+	  AND goal_id = $2
+	  AND is_active = true
+	  AND ` + protectedStatusGuard("status") + `
+	  AND ` + progressCeilingGuard("progress", "$3::BIGINT", "$6::BIGINT") + `
+`
+
+// txIncrementRegularQuery is the transactional single increment, an upsert.
+// Arguments: user, goal, challenge, namespace, delta, target, progress cap, progress
+// ceiling.
+var txIncrementRegularQuery = `
+	INSERT INTO user_goal_progress (
+		user_id,
+		goal_id,
+		challenge_id,
+		namespace,
+		progress,
+		status,
+		completed_at,
+		updated_at
+	) VALUES (
+		$1, $2, $3, $4, LEAST($5::BIGINT, $7::BIGINT),
+		CASE WHEN $5::BIGINT >= $6::BIGINT THEN 'completed' ELSE 'in_progress' END,
+		CASE WHEN $5::BIGINT >= $6::BIGINT THEN NOW() ELSE NULL END,
+		NOW()
+	)
+	ON CONFLICT (user_id, goal_id) DO UPDATE SET
+		progress = LEAST(user_goal_progress.progress::BIGINT + $5::BIGINT, GREATEST($7::BIGINT, user_goal_progress.progress)),
+		status = CASE
+			WHEN user_goal_progress.progress::BIGINT + $5::BIGINT >= $6::BIGINT THEN 'completed'
+			ELSE 'in_progress'
+		END,
+		completed_at = CASE
+			WHEN user_goal_progress.progress::BIGINT + $5::BIGINT >= $6::BIGINT AND user_goal_progress.completed_at IS NULL
+				THEN NOW()
+			ELSE user_goal_progress.completed_at
+		END,
+		updated_at = NOW()
+	WHERE ` + protectedStatusGuard("user_goal_progress.status") + `
+	  AND ` + progressCeilingGuard("user_goal_progress.progress", "$5::BIGINT", "$8::BIGINT") + `
+`
+
+// txIncrementDailyQuery is the transactional once-per-day increment, an upsert.
+// Arguments: user, goal, challenge, namespace, delta, target, progress cap, progress
+// ceiling.
+var txIncrementDailyQuery = `
+	INSERT INTO user_goal_progress (
+		user_id,
+		goal_id,
+		challenge_id,
+		namespace,
+		progress,
+		status,
+		completed_at,
+		last_daily_date,
+		updated_at
+	) VALUES (
+		$1, $2, $3, $4, 1,
+		CASE WHEN 1 >= $6::BIGINT THEN 'completed' ELSE 'in_progress' END,
+		CASE WHEN 1 >= $6::BIGINT THEN NOW() ELSE NULL END,
+		DATE(NOW() AT TIME ZONE 'UTC'),
+		NOW()
+	)
+	ON CONFLICT (user_id, goal_id) DO UPDATE SET
+		progress = CASE
+			WHEN COALESCE(user_goal_progress.last_daily_date, DATE(user_goal_progress.updated_at AT TIME ZONE 'UTC')) >= DATE(NOW() AT TIME ZONE 'UTC')
+				THEN user_goal_progress.progress
+			ELSE LEAST(user_goal_progress.progress::BIGINT + $5::BIGINT, GREATEST($7::BIGINT, user_goal_progress.progress))
+		END,
+		status = CASE
+			WHEN COALESCE(user_goal_progress.last_daily_date, DATE(user_goal_progress.updated_at AT TIME ZONE 'UTC')) >= DATE(NOW() AT TIME ZONE 'UTC') THEN
+				CASE WHEN user_goal_progress.progress >= $6::BIGINT THEN 'completed' ELSE 'in_progress' END
+			ELSE
+				CASE WHEN user_goal_progress.progress::BIGINT + $5::BIGINT >= $6::BIGINT THEN 'completed' ELSE 'in_progress' END
+		END,
+		completed_at = CASE
+			WHEN COALESCE(user_goal_progress.last_daily_date, DATE(user_goal_progress.updated_at AT TIME ZONE 'UTC')) >= DATE(NOW() AT TIME ZONE 'UTC') THEN
+				user_goal_progress.completed_at
+			WHEN user_goal_progress.progress::BIGINT + $5::BIGINT >= $6::BIGINT AND user_goal_progress.completed_at IS NULL THEN
+				NOW()
+			ELSE
+				user_goal_progress.completed_at
+		END,
+		last_daily_date = DATE(NOW() AT TIME ZONE 'UTC'),
+		updated_at = NOW()
+	WHERE ` + protectedStatusGuard("user_goal_progress.status") + `
+	  AND ` + progressCeilingGuard("user_goal_progress.progress", "$5::BIGINT", "$8::BIGINT") + `
+`
+
+func (e executor) incrementProgress(ctx context.Context, userID, goalID, challengeID, namespace string, delta, targetValue int, isDailyIncrement bool) error {
+	delta = capDelta(delta, e.repo.maxDeltaPerEvent)
+	if err := e.repo.validateIncrement(userID, goalID, delta, targetValue); err != nil {
+		return err
+	}
+	if _, skipped := e.repo.dropCeilingOverflows([]ProgressIncrement{{UserID: userID, GoalID: goalID, Delta: delta}}); skipped != nil {
+		return errors.ErrProgressOverflow(skipped)
+	}
+
+	kind, query, txQuery := "regular", incrementRegularQuery, txIncrementRegularQuery
+	if isDailyIncrement {
+		kind, query, txQuery = "daily", incrementDailyQuery, txIncrementDailyQuery
+	}
+	if e.repo.lastDelta {
+		query, txQuery = incrementRegularLastDeltaQuery, txIncrementRegularLastDeltaQuery
+		if isDailyIncrement {
+			query, txQuery = incrementDailyLastDeltaQuery, txIncrementDailyLastDeltaQuery
+		}
+	}
+
+	progressCap := e.repo.incrementCap(OverflowPolicy{}, targetValue)
+	ceiling := e.repo.progressCeiling
+	query = ceilingSkippedQuery(query, "$3", "$6")
+	args := []interface{}{userID, goalID, delta, targetValue, progressCap, ceiling}
+	if e.upsertsIncrements() {
+		query = ceilingSkippedQuery(txQuery, "$5", "$8")
+		args = []interface{}{userID, goalID, challengeID, namespace, delta, targetValue, progressCap, ceiling}
+	}
+
+	// A row comes back only when the ceiling guard left it unchanged
+	var progress int64
+	err := e.q.QueryRowContext(ctx, query, args...).Scan(&progress)
+	if err == sql.ErrNoRows {
+		return nil
+	}
+	if err != nil {
+		return errors.ErrDatabaseError(e.op("increment progress ("+kind+")"), err)
+	}
+
+	return errors.ErrProgressOverflow([]string{ceilingViolation(userID, goalID, progress, int64(delta), ceiling)})
+}
+
+// batchIncrementStatement returns the batch increment statement and its argument builder
+// (see batch_increment.go). The builder appends the per-entry progress caps, the progress
+// ceiling and, with WithConfigChecksum, the config checksum after the arrays. The
+// statement returns the entries its ceiling guard skipped (ceilingSkipsQuery) or, with
+// completions, the completed rows as well (completionReturningQuery).
+func (e executor) batchIncrementStatement(completions bool) (string, func([]ProgressIncrement) []interface{}) {
+	checksum := e.repo.configChecksum != nil
+	query, buildArgs := batchIncrementProgressQuery, batchIncrementArgs
+	deltaParam, ceilingParam := batchDeltaParam, batchCeilingParam
+	switch {
+	case checksum && e.repo.lastDelta:
+		query = batchIncrementProgressChecksumLastDeltaQuery
+	case checksum:
+		query = batchIncrementProgressChecksumQuery
+	case e.repo.lastDelta:
+		query = batchIncrementProgressLastDeltaQuery
+	}
+	if e.upsertsIncrements() {
+		buildArgs = txBatchIncrementArgs
+		deltaParam, ceilingParam = txBatchDeltaParam, txBatchCeilingParam
+		switch {
+		case checksum && e.repo.lastDelta:
+			query = txBatchIncrementProgressChecksumLastDeltaQuery
+		case checksum:
+			query = txBatchIncrementProgressChecksumQuery
+		case e.repo.lastDelta:
+			query = txBatchIncrementProgressLastDeltaQuery
+		default:
+			query = txBatchIncrementProgressQuery
+		}
+	}
+	if completions {
+		query = completionReturningQuery(query, deltaParam, ceilingParam)
+	} else {
+		query = ceilingSkipsQuery(query, deltaParam, ceilingParam)
+	}
+
+	checksumArg := e.repo.configChecksumArg()
+	return query, func(increments []ProgressIncrement) []interface{} {
+		caps := make([]int64, len(increments))
+		for i, inc := range increments {
+			caps[i] = e.repo.incrementCap(inc.OverflowPolicy, inc.TargetValue)
+		}
+		args := append(buildArgs(increments), pq.Array(caps), e.repo.progressCeiling)
+		if checksum {
+			args = append(args, checksumArg)
+		}
+		return args
+	}
+}
+
+// execBatchIncrement runs a statement from batchIncrementStatement(false), logs the entries
+// its ceiling guard skipped and returns them.
+func (e executor) execBatchIncrement(ctx context.Context, q queryer, operation, query string, args []interface{}) ([]ceilingSkip, error) {
+	rows, err := q.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, errors.ErrDatabaseError(operation, err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	skips, err := scanCeilingSkips(rows)
+	if err != nil {
+		return nil, err
+	}
+	e.repo.logCeilingSkips(operation, e.repo.describeCeilingSkips(skips))
+	return skips, nil
+}
+
+func (e executor) batchIncrementProgress(ctx context.Context, increments []ProgressIncrement) error {
+	operation := e.op("batch increment progress")
+	increments, _, err := e.repo.prepareIncrements(operation, increments)
+	if err != nil {
+		return err
+	}
+	if len(increments) == 0 {
+		return nil
+	}
+
+	release, err := e.admitBatch(ctx, "batch increment progress", len(increments), func(i int) string { return increments[i].Namespace })
+	if err != nil {
+		return err
+	}
+	defer release()
+
+	query, buildArgs := e.batchIncrementStatement(false)
+	var args []interface{}
+	var start time.Time
+	err = e.withUserLocks(ctx, incrementUserIDs(increments), func(q queryer) error {
+		args = buildArgs(increments)
+		start = time.Now()
+		_, err := e.execBatchIncrement(ctx, q, operation, query, args)
+		return err
+	})
+	if err != nil {
+		return err
+	}
+
+	if args != nil {
+		e.repo.explainIfSlow(ctx, operation, start, query, args)
+	}
+
+	return nil
+}
+
+func (e executor) batchIncrementProgressWithResult(ctx context.Context, increments []ProgressIncrement) (BatchIncrementResult, error) {
+	operation := e.op("batch increment progress returning")
+	increments, skipped, err := e.repo.prepareIncrements(operation, increments)
+	if err != nil {
+		return BatchIncrementResult{}, err
+	}
+	if len(increments) == 0 {
+		return BatchIncrementResult{Completions: []CompletionResult{}, SkippedLate: skipped}, nil
+	}
+
+	release, err := e.admitBatch(ctx, "batch increment progress returning", len(increments), func(i int) string { return increments[i].Namespace })
+	if err != nil {
+		return BatchIncrementResult{}, err
+	}
+	defer release()
+
+	query, buildArgs := e.batchIncrementStatement(true)
+	var args []interface{}
+	var start time.Time
+
+	results := []CompletionResult{}
+	unassigned := 0
+	err = e.withUserLocks(ctx, incrementUserIDs(increments), func(q queryer) error {
+		if e.repo.requireAssignment {
+			if unassigned, err = countUnassigned(ctx, q, operation, increments); err != nil {
+				return err
+			}
+		}
+
+		args = buildArgs(increments)
+		start = time.Now()
+		rows, err := q.QueryContext(ctx, query, args...)
+		if err != nil {
+			return errors.ErrDatabaseError(operation, err)
+		}
+		defer func() { _ = rows.Close() }()
+
+		var overCeiling []string
+		results, overCeiling, err = e.repo.scanCompletionResults(rows)
+		if err != nil {
+			return err
+		}
+		e.repo.logCeilingSkips(operation, overCeiling)
+		return nil
+	})
+	if err != nil {
+		return BatchIncrementResult{}, err
+	}
+
+	if args != nil {
+		e.repo.explainIfSlow(ctx, operation, start, query, args)
+	}
+	return BatchIncrementResult{Completions: results, SkippedLate: skipped, SkippedUnassigned: unassigned}, nil
+}
+
+func (e executor) incrementProgressWithCooldown(ctx context.Context, userID, goalID, challengeID, namespace string, delta, targetValue int, cooldown time.Duration) error {
+	return e.batchIncrementProgress(ctx, []ProgressIncrement{{
+		UserID:      userID,
+		GoalID:      goalID,
+		ChallengeID: challengeID,
+		Namespace:   namespace,
+		Delta:       delta,
+		TargetValue: targetValue,
+		Cooldown:    cooldown,
+	}})
+}
+
+func (e executor) markAsClaimed(ctx context.Context, userID, goalID string, claimDeadline time.Duration) error {
+	deadlineUs := claimDeadline.Microseconds()
+	rowsAffected, err := e.execRowsAffected(ctx, e.op("mark as claimed"), markAsClaimedQuery, userID, goalID, deadlineUs)
+	if err != nil {
+		return err
+	}
+
+	if rowsAffected == 0 {
+		// Tell an expired claim window apart from the other failures
+		var expired bool
+		err := e.q.QueryRowContext(ctx, claimWindowExpiredQuery, userID, goalID, deadlineUs).Scan(&expired)
+		if err != nil {
+			return errors.ErrDatabaseError(e.op("check claim window"), err)
+		}
+		if expired {
+			return errors.ErrClaimWindowExpired(goalID)
+		}
+
+		// No rows updated - goal either doesn't exist, not completed, or already claimed
+		// Caller should check progress status to determine specific error
+		return errors.ErrGoalNotCompleted(goalID)
+	}
+
+	return nil
+}
+
+// M3: Goal assignment control
+
+// bulkInsertQuery builds the INSERT used by BulkInsert (12 parameters per row).
+func bulkInsertQuery(progresses []*domain.UserGoalProgress) (string, []interface{}) {
+	valueStrings := make([]string, 0, len(progresses))
+	valueArgs := make([]interface{}, 0, len(progresses)*12)
+
+	for i, p := range progresses {
+		valueStrings = append(valueStrings, fmt.Sprintf(
+			"($%d, $%d, $%d, $%d, $%d, $%d, $%d, $%d, NOW(), NOW(), $%d, $%d, $%d, $%d)",
+			i*12+1, i*12+2, i*12+3, i*12+4, i*12+5, i*12+6, i*12+7, i*12+8, i*12+9, i*12+10, i*12+11, i*12+12,
+		))
+
+		valueArgs = append(valueArgs,
+			p.UserID,
+			p.GoalID,
+			p.ChallengeID,
+			p.Namespace,
+			p.Progress,
+			p.Status,
+			p.CompletedAt,
+			p.ClaimedAt,
+			p.IsActive,
+			p.AssignedAt,
+			p.ExpiresAt,
+			p.OrderIndex,
+		)
+	}
+
+	//nolint:gosec // Safe: valueStrings contains only parameterized placeholders like "($1, $2, $3)", not user input
+	query := fmt.Sprintf(`
+		INSERT INTO user_goal_progress (
+			user_id, goal_id, challenge_id, namespace,
+			progress, status, completed_at, claimed_at,
+			created_at, updated_at,
+			is_active, assigned_at, expires_at, order_index
+		) VALUES %s
+		ON CONFLICT (user_id, goal_id) DO NOTHING
+	`, strings.Join(valueStrings, ","))
+
+	return query, valueArgs
+}
+
+func (e executor) bulkInsert(ctx context.Context, progresses []*domain.UserGoalProgress) error {
+	if len(progresses) == 0 {
+		return nil
+	}
+
+	release, err := e.admitBatch(ctx, "bulk insert", len(progresses), func(i int) string { return progresses[i].Namespace })
+	if err != nil {
+		return err
+	}
+	defer release()
+
+	query, args := bulkInsertQuery(progresses)
+	if _, err := e.q.ExecContext(ctx, query, args...); err != nil {
+		return errors.ErrDatabaseError(e.op("bulk insert goals"), err)
+	}
+
+	return nil
+}
+
+// createTempBulkInsertTableQuery creates the session-local COPY target for BulkInsertWithCOPY.
+const createTempBulkInsertTableQuery = `
+	CREATE TEMP TABLE IF NOT EXISTS temp_bulk_insert (
+		user_id VARCHAR(100) NOT NULL,
+		goal_id VARCHAR(100) NOT NULL,
+		challenge_id VARCHAR(100) NOT NULL,
+		namespace VARCHAR(100) NOT NULL,
+		progress BIGINT NOT NULL,
+		status VARCHAR(20) NOT NULL,
+		completed_at TIMESTAMP NULL,
+		claimed_at TIMESTAMP NULL,
+		created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+		updated_at TIMESTAMP NOT NULL DEFAULT NOW(),
+		is_active BOOLEAN NOT NULL DEFAULT false,
+		assigned_at TIMESTAMP NULL,
+		expires_at TIMESTAMP NULL,
+		order_index INT NOT NULL DEFAULT 0
+	) ON COMMIT DROP
+`
+
+// insertFromTempBulkInsertQuery copies temp_bulk_insert into the main table, skipping existing rows.
+const insertFromTempBulkInsertQuery = `
+	INSERT INTO user_goal_progress (
+		user_id, goal_id, challenge_id, namespace,
+		progress, status, completed_at, claimed_at,
+		created_at, updated_at,
+		is_active, assigned_at, expires_at, order_index
+	)
+	SELECT
+		user_id, goal_id, challenge_id, namespace,
+		progress, status, completed_at, claimed_at,
+		created_at, updated_at,
+		is_active, assigned_at, expires_at, order_index
+	FROM temp_bulk_insert
+	ON CONFLICT (user_id, goal_id) DO NOTHING
+`
+
+func (e executor) bulkInsertWithCOPY(ctx context.Context, progresses []*domain.UserGoalProgress) error {
+	if len(progresses) == 0 {
+		return nil
+	}
+
+	release, err := e.admitBatch(ctx, "bulk insert with COPY", len(progresses), func(i int) string { return progresses[i].Namespace })
+	if err != nil {
+		return err
+	}
+	defer release()
+
+	return e.withTx(ctx, "BulkInsert COPY", func(tx *sql.Tx) error {
+		if _, err := tx.ExecContext(ctx, createTempBulkInsertTableQuery); err != nil {
+			return errors.ErrDatabaseError(e.op("create temp table for BulkInsert COPY"), err)
+		}
+
+		copyStmt := pq.CopyIn(
+			"temp_bulk_insert",
+			"user_id", "goal_id", "challenge_id", "namespace",
+			"progress", "status", "completed_at", "claimed_at",
+			"created_at", "updated_at",
+			"is_active", "assigned_at", "expires_at", "order_index",
+		)
+		now := time.Now().UTC() // Always use UTC for consistency across timezones
+		err := e.copyIn(ctx, tx, " for BulkInsert", copyStmt, len(progresses), func(i int) []interface{} {
+			p := progresses[i]
+			return []interface{}{
+				p.UserID, p.GoalID, p.ChallengeID, p.Namespace,
+				p.Progress, p.Status, p.CompletedAt, p.ClaimedAt,
+				now, now,
+				p.IsActive, p.AssignedAt, p.ExpiresAt, p.OrderIndex,
+			}
+		})
+		if err != nil {
+			return err
+		}
+
+		if _, err := tx.ExecContext(ctx, insertFromTempBulkInsertQuery); err != nil {
+			return errors.ErrDatabaseError(e.op("insert from temp table for BulkInsert"), err)
+		}
+		return nil
+	})
+}
+
+func (e executor) ensureAssigned(ctx context.Context, progresses []*domain.UserGoalProgress) (int64, error) {
+	if len(progresses) == 0 {
+		return 0, nil
+	}
+
+	query, args := ensureAssignedQuery(progresses)
+	return e.execRowsAffected(ctx, e.op("ensure assigned"), query, args...)
+}
+
+func (e executor) upsertGoalActive(ctx context.Context, progress *domain.UserGoalProgress, opts ActivationOptions) error {
+	// M3 Phase 5: UpsertGoalActive is designed to toggle is_active on existing rows.
+	// Use UPDATE instead of INSERT...ON CONFLICT to avoid check constraint violations
+	// when Status field is empty.
+	// Activation sets assigned_at when UpdateAssignedAt ($4) is set or none is recorded.
+	query := `
+		UPDATE user_goal_progress SET
+			is_active = $1,
+			assigned_at = CASE
+				WHEN $1 = true AND ($4::BOOLEAN OR assigned_at IS NULL) THEN NOW()
+				ELSE assigned_at
+			END,
+			updated_at = NOW()
+		WHERE user_id = $2
+		  AND goal_id = $3
+	`
+
+	rowsAffected, err := e.execRowsAffected(ctx, e.op("update goal active"), query,
+		progress.IsActive,
+		progress.UserID,
+		progress.GoalID,
+		opts.UpdateAssignedAt,
+	)
+	if err != nil {
+		return err
+	}
+
+	if rowsAffected == 0 {
+		// Row doesn't exist - insert with sensible defaults
+		insertQuery := `
+			INSERT INTO user_goal_progress (
+				user_id, goal_id, challenge_id, namespace,
+				progress, status, is_active, assigned_at,
+				created_at, updated_at, order_index
+			) VALUES (
+				$1, $2, $3, $4, 0, 'not_started', $5,
+				CASE WHEN $5 = true THEN NOW() ELSE NULL END,
+				NOW(), NOW(), $6
+			)
+		`
+
+		_, err = e.q.ExecContext(ctx, insertQuery,
+			progress.UserID,
+			progress.GoalID,
+			progress.ChallengeID,
+			progress.Namespace,
+			progress.IsActive,
+			progress.OrderIndex,
+		)
+
+		if err != nil {
+			return errors.ErrDatabaseError(e.op("insert goal active"), err)
+		}
+	}
+
+	return nil
+}
+
+func (e executor) batchUpsertGoalActive(ctx context.Context, progresses []*domain.UserGoalProgress) error {
+	if len(progresses) == 0 {
+		return nil
+	}
+
+	release, err := e.admitBatch(ctx, "batch upsert goal active", len(progresses), func(i int) string { return progresses[i].Namespace })
+	if err != nil {
+		return err
+	}
+	defer release()
+
+	// Extract goal IDs and is_active values
+	goalIDs := make([]string, len(progresses))
+	isActiveVals := make([]bool, len(progresses))
+	userID := progresses[0].UserID // All progresses should have the same user_id
+
+	for i, p := range progresses {
+		goalIDs[i] = p.GoalID
+		isActiveVals[i] = p.IsActive
+	}
+
+	// Step 1: Batch UPDATE existing rows using UNNEST to map each goal to its is_active value
+	updateQuery := `
+		UPDATE user_goal_progress SET
+			is_active = data.is_active,
+			assigned_at = CASE WHEN data.is_active THEN NOW() ELSE NULL END,
+			updated_at = NOW()
+		FROM (
+			SELECT UNNEST($2::text[]) AS goal_id, UNNEST($3::boolean[]) AS is_active
+		) AS data
+		WHERE user_goal_progress.user_id = $1
+		  AND user_goal_progress.goal_id = data.goal_id
+	`
+
+	result, err := e.q.ExecContext(ctx, updateQuery, userID, pq.Array(goalIDs), pq.Array(isActiveVals))
+	if err != nil {
+		return errors.ErrDatabaseError(e.op("batch update goal active"), err)
+	}
+
+	// Check how many rows were updated
+	rowsUpdated, err := result.RowsAffected()
+	if err != nil {
+		return errors.ErrDatabaseError(e.op("check rows affected"), err)
+	}
+
+	// If all rows were updated, we're done
+	if int(rowsUpdated) == len(progresses) {
+		return nil
+	}
+
+	// Step 2: Batch INSERT missing rows with actual is_active values
+	// Use ON CONFLICT DO UPDATE to handle race conditions
+	insertQuery := `
+		INSERT INTO user_goal_progress (
+			user_id, goal_id, challenge_id, namespace,
+			progress, status, is_active, assigned_at,
+			created_at, updated_at, order_index
+		) VALUES
+	`
+
+	values := make([]interface{}, 0, len(progresses)*6) // 6 actual values per row
+	valuePlaceholders := make([]string, 0, len(progresses))
+
+	for i, p := range progresses {
+		offset := i * 6
+		valuePlaceholders = append(valuePlaceholders, fmt.Sprintf(
+			"($%d, $%d, $%d, $%d, 0, 'not_started', $%d, NOW(), NOW(), NOW(), $%d)",
+			offset+1, offset+2, offset+3, offset+4, offset+5, offset+6,
+		))
+
+		values = append(values,
+			p.UserID,
+			p.GoalID,
+			p.ChallengeID,
+			p.Namespace,
+			p.IsActive, // Use actual is_active value
+			p.OrderIndex,
+		)
+	}
+
+	insertQuery += strings.Join(valuePlaceholders, ", ")
+	insertQuery += " ON CONFLICT (user_id, goal_id) DO UPDATE SET is_active = EXCLUDED.is_active, assigned_at = CASE WHEN EXCLUDED.is_active THEN NOW() ELSE NULL END, updated_at = NOW()"
+
+	if _, err := e.q.ExecContext(ctx, insertQuery, values...); err != nil {
+		return errors.ErrDatabaseError(e.op("batch insert goal active"), err)
+	}
+
+	return nil
+}
+
+// M3 Phase 9: Fast path optimization
+
+func (e executor) deactivateChallengeGoals(ctx context.Context, userID, challengeID string) (int64, error) {
+	query := `
+		UPDATE user_goal_progress SET
+			is_active = false,
+			updated_at = NOW()
+		WHERE user_id = $1
+		  AND challenge_id = $2
+		  AND is_active = true
+	`
+
+	return e.execRowsAffected(ctx, e.op("deactivate challenge goals"), query, userID, challengeID)
+}
+
+func (e executor) reactivateChallengeGoals(ctx context.Context, userID, challengeID string) (int64, error) {
+	query := `
+		UPDATE user_goal_progress SET
+			is_active = true,
+			assigned_at = NOW(),
+			updated_at = NOW()
+		WHERE user_id = $1
+		  AND challenge_id = $2
+		  AND is_active = false
+	`
+
+	return e.execRowsAffected(ctx, e.op("reactivate challenge goals"), query, userID, challengeID)
+}
+
+func (e executor) deleteChallengeProgress(ctx context.Context, namespace, challengeID string) (int64, error) {
+	if !e.repo.allowBulkDelete {
+		return 0, errors.ErrOperationNotAllowed("delete challenge progress", "bulk delete is disabled (see WithAllowBulkDelete)")
+	}
+
+	query := `DELETE FROM user_goal_progress WHERE namespace = $1 AND challenge_id = $2`
+
+	return e.execRowsAffected(ctx, e.op("delete challenge progress"), query, namespace, challengeID)
+}
@@ -0,0 +1,58 @@
+package repository
+
+import (
+	"context"
+	"strings"
+	"time"
+)
+
+// explainIfSlow logs the query plan when a query took longer than the configured
+// explain threshold. It is a no-op unless WithExplainOnSlow was set, so fast queries
+// only pay for a time.Since call.
+//
+// The EXPLAIN always runs on the pool (never inside the caller's transaction): a failed
+// EXPLAIN would otherwise abort the transaction and change the caller's results.
+// ANALYZE is off, so the statement is planned but not executed.
+func (r *PostgresGoalRepository) explainIfSlow(ctx context.Context, operation string, start time.Time, query string, args []interface{}) {
+	if r.explainThreshold <= 0 {
+		return
+	}
+
+	elapsed := time.Since(start)
+	if elapsed < r.explainThreshold {
+		return
+	}
+
+	logger := r.logger.With("operation", operation, "duration", elapsed, "threshold", r.explainThreshold)
+
+	plan, err := r.explainQuery(ctx, query, args)
+	if err != nil {
+		logger.Warn("Slow query; EXPLAIN failed", "error", err)
+		return
+	}
+
+	logger.Warn("Slow query plan", "plan", plan)
+}
+
+// explainQuery returns the text plan for query with the given args.
+func (r *PostgresGoalRepository) explainQuery(ctx context.Context, query string, args []interface{}) (string, error) {
+	rows, err := r.db.QueryContext(ctx, "EXPLAIN (ANALYZE false) "+query, args...)
+	if err != nil {
+		return "", err
+	}
+	defer func() { _ = rows.Close() }()
+
+	var lines []string
+	for rows.Next() {
+		var line string
+		if err := rows.Scan(&line); err != nil {
+			return "", err
+		}
+		lines = append(lines, line)
+	}
+	if err := rows.Err(); err != nil {
+		return "", err
+	}
+
+	return strings.Join(lines, "\n"), nil
+}
@@ -0,0 +1,81 @@
+package repository
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/AccelByte/extend-challenge-common/pkg/domain"
+)
+
+func TestExplainIfSlow_SkipsFastQueries(t *testing.T) {
+	var buf bytes.Buffer
+	logger := slog.New(slog.NewTextHandler(&buf, nil))
+
+	tests := []struct {
+		name      string
+		threshold time.Duration
+	}{
+		{name: "disabled by default", threshold: 0},
+		{name: "query under threshold", threshold: time.Hour},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// db is nil: any EXPLAIN attempt would panic
+			repo := NewPostgresGoalRepository(nil, WithLogger(logger), WithExplainOnSlow(tt.threshold))
+
+			repo.explainIfSlow(context.Background(), "test", time.Now(), "SELECT 1", nil)
+
+			if buf.Len() != 0 {
+				t.Errorf("Expected no log output, got %q", buf.String())
+			}
+		})
+	}
+}
+
+func TestPostgresGoalRepository_ExplainOnSlow(t *testing.T) {
+	db := setupTestDB(t)
+	if db == nil {
+		return
+	}
+	defer cleanupTestDB(t, db)
+
+	var buf bytes.Buffer
+	logger := slog.New(slog.NewTextHandler(&buf, nil))
+
+	// A 1ns threshold treats every query as slow
+	repo := NewPostgresGoalRepository(db, WithLogger(logger), WithExplainOnSlow(time.Nanosecond))
+	ctx := context.Background()
+
+	err := repo.BulkInsert(ctx, []*domain.UserGoalProgress{
+		{UserID: "explain-user", GoalID: "goal-1", ChallengeID: "c1", Namespace: "test", Status: domain.GoalStatusNotStarted, IsActive: true},
+	})
+	if err != nil {
+		t.Fatalf("BulkInsert failed: %v", err)
+	}
+
+	err = repo.BatchIncrementProgress(ctx, []ProgressIncrement{
+		{UserID: "explain-user", GoalID: "goal-1", Delta: 3, TargetValue: 10},
+	})
+	if err != nil {
+		t.Fatalf("BatchIncrementProgress failed: %v", err)
+	}
+
+	logs := buf.String()
+	if !strings.Contains(logs, "Slow query plan") || !strings.Contains(logs, "Update on user_goal_progress") {
+		t.Errorf("Expected plan to be logged, got %q", logs)
+	}
+
+	// EXPLAIN without ANALYZE must not apply the increment a second time
+	p, err := repo.GetProgress(ctx, "explain-user", "goal-1")
+	if err != nil {
+		t.Fatalf("GetProgress failed: %v", err)
+	}
+	if p.Progress != 3 {
+		t.Errorf("Expected progress 3, got %d", p.Progress)
+	}
+}
@@ -0,0 +1,105 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/AccelByte/extend-challenge-common/pkg/domain"
+	"github.com/AccelByte/extend-challenge-common/pkg/errors"
+
+	"github.com/lib/pq"
+)
+
+// FlushPreviewRepository reports what a pending flush would do against the current rows
+// without writing anything. Intended to be called on a sample of flushes to alert when the
+// blocked ratio spikes (a symptom of assignment bugs).
+type FlushPreviewRepository interface {
+	// PreviewBatchUpsert classifies the rows BatchUpsertProgress(WithCOPY) would touch.
+	PreviewBatchUpsert(ctx context.Context, updates []*domain.UserGoalProgress) (PreviewResult, error)
+
+	// PreviewBatchIncrement classifies the rows BatchIncrementProgress would touch.
+	PreviewBatchIncrement(ctx context.Context, increments []ProgressIncrement) (PreviewResult, error)
+}
+
+// PreviewResult counts batch entries by the state of their existing row.
+// Each entry lands in exactly one bucket; duplicate keys are counted once per entry.
+type PreviewResult struct {
+	// WouldInsert counts entries with no existing row. BatchUpsertProgress and transactional
+	// increments insert them; the COPY upsert and pool increments skip them (lazy materialization).
+	WouldInsert int
+
+	// WouldUpdate counts entries whose row is active, unclaimed, and not expired.
+	WouldUpdate int
+
+	// BlockedClaimed counts entries whose row is claimed or reserved for a claim (never overwritten).
+	BlockedClaimed int
+
+	// BlockedInactive counts entries whose unclaimed row is not assigned (is_active = false).
+	BlockedInactive int
+
+	// BlockedExpired counts entries whose active, unclaimed row is past expires_at.
+	// Writes do not enforce expiry yet (M5 rotation), so these rows are still updated;
+	// they are reported separately so drift shows up before rotation lands.
+	BlockedExpired int
+}
+
+// flushPreviewQuery classifies incoming (user_id, goal_id) keys against user_goal_progress.
+// Read-only: a single aggregate over a LEFT JOIN with the UNNEST keys.
+var flushPreviewQuery = `
+	SELECT
+		COUNT(*) FILTER (WHERE p.user_id IS NULL),
+		COUNT(*) FILTER (WHERE ` + protectedStatusGuard("p.status") + ` AND p.is_active
+		                   AND (p.expires_at IS NULL OR p.expires_at > NOW())),
+		COUNT(*) FILTER (WHERE ` + protectedStatusMatch("p.status") + `),
+		COUNT(*) FILTER (WHERE ` + protectedStatusGuard("p.status") + ` AND NOT p.is_active),
+		COUNT(*) FILTER (WHERE ` + protectedStatusGuard("p.status") + ` AND p.is_active AND p.expires_at <= NOW())
+	FROM UNNEST(
+		$1::VARCHAR(100)[],  -- user_ids
+		$2::VARCHAR(100)[]   -- goal_ids
+	) AS t(user_id, goal_id)
+	LEFT JOIN user_goal_progress p ON p.user_id = t.user_id AND p.goal_id = t.goal_id
+`
+
+// PreviewBatchUpsert classifies the rows a batch upsert would touch without writing.
+func (r *PostgresGoalRepository) PreviewBatchUpsert(ctx context.Context, updates []*domain.UserGoalProgress) (PreviewResult, error) {
+	userIDs := make([]string, len(updates))
+	goalIDs := make([]string, len(updates))
+	for i, u := range updates {
+		userIDs[i] = u.UserID
+		goalIDs[i] = u.GoalID
+	}
+
+	return r.previewFlush(ctx, "preview batch upsert", userIDs, goalIDs)
+}
+
+// PreviewBatchIncrement classifies the rows a batch increment would touch without writing.
+func (r *PostgresGoalRepository) PreviewBatchIncrement(ctx context.Context, increments []ProgressIncrement) (PreviewResult, error) {
+	userIDs := make([]string, len(increments))
+	goalIDs := make([]string, len(increments))
+	for i, inc := range increments {
+		userIDs[i] = inc.UserID
+		goalIDs[i] = inc.GoalID
+	}
+
+	return r.previewFlush(ctx, "preview batch increment", userIDs, goalIDs)
+}
+
+// previewFlush runs flushPreviewQuery for the given keys.
+func (r *PostgresGoalRepository) previewFlush(ctx context.Context, operation string, userIDs, goalIDs []string) (PreviewResult, error) {
+	var result PreviewResult
+	if len(userIDs) == 0 {
+		return result, nil
+	}
+
+	err := r.db.QueryRowContext(ctx, flushPreviewQuery, pq.Array(userIDs), pq.Array(goalIDs)).Scan(
+		&result.WouldInsert,
+		&result.WouldUpdate,
+		&result.BlockedClaimed,
+		&result.BlockedInactive,
+		&result.BlockedExpired,
+	)
+	if err != nil {
+		return PreviewResult{}, errors.ErrDatabaseError(operation, err)
+	}
+
+	return result, nil
+}
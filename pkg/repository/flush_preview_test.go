@@ -0,0 +1,103 @@
+package repository
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/AccelByte/extend-challenge-common/pkg/domain"
+)
+
+func TestPreviewBatch_EmptyBatch(t *testing.T) {
+	// nil *sql.DB: an empty batch must not query
+	repo := NewPostgresGoalRepository(nil)
+
+	result, err := repo.PreviewBatchUpsert(context.Background(), nil)
+	if err != nil || result != (PreviewResult{}) {
+		t.Errorf("Expected zero result, got %+v, err=%v", result, err)
+	}
+
+	result, err = repo.PreviewBatchIncrement(context.Background(), nil)
+	if err != nil || result != (PreviewResult{}) {
+		t.Errorf("Expected zero result, got %+v, err=%v", result, err)
+	}
+}
+
+func TestPostgresGoalRepository_PreviewBatch(t *testing.T) {
+	db := setupTestDB(t)
+	if db == nil {
+		return
+	}
+	defer cleanupTestDB(t, db)
+
+	repo := NewPostgresGoalRepository(db)
+	ctx := context.Background()
+
+	now := time.Now().UTC()
+	completedAt := now.Add(-time.Hour)
+	claimedAt := now.Add(-time.Minute)
+	expired := now.Add(-time.Hour)
+	future := now.Add(24 * time.Hour)
+
+	err := repo.BulkInsert(ctx, []*domain.UserGoalProgress{
+		{UserID: "preview-user", GoalID: "active", ChallengeID: "c1", Namespace: "test", Progress: 1, Status: domain.GoalStatusInProgress, IsActive: true},
+		{UserID: "preview-user", GoalID: "active-future-expiry", ChallengeID: "c1", Namespace: "test", Progress: 1, Status: domain.GoalStatusInProgress, IsActive: true, ExpiresAt: &future},
+		{UserID: "preview-user", GoalID: "claimed", ChallengeID: "c1", Namespace: "test", Progress: 10, Status: domain.GoalStatusClaimed, CompletedAt: &completedAt, ClaimedAt: &claimedAt, IsActive: true},
+		{UserID: "preview-user", GoalID: "inactive", ChallengeID: "c1", Namespace: "test", Progress: 1, Status: domain.GoalStatusInProgress, IsActive: false},
+		{UserID: "preview-user", GoalID: "expired", ChallengeID: "c1", Namespace: "test", Progress: 1, Status: domain.GoalStatusInProgress, IsActive: true, ExpiresAt: &expired},
+	})
+	if err != nil {
+		t.Fatalf("BulkInsert failed: %v", err)
+	}
+
+	goalIDs := []string{"missing", "active", "active-future-expiry", "claimed", "inactive", "expired"}
+	want := PreviewResult{WouldInsert: 1, WouldUpdate: 2, BlockedClaimed: 1, BlockedInactive: 1, BlockedExpired: 1}
+
+	t.Run("upsert", func(t *testing.T) {
+		updates := make([]*domain.UserGoalProgress, 0, len(goalIDs))
+		for _, goalID := range goalIDs {
+			updates = append(updates, &domain.UserGoalProgress{
+				UserID: "preview-user", GoalID: goalID, ChallengeID: "c1", Namespace: "test",
+				Progress: 5, Status: domain.GoalStatusInProgress,
+			})
+		}
+
+		result, err := repo.PreviewBatchUpsert(ctx, updates)
+		if err != nil {
+			t.Fatalf("PreviewBatchUpsert failed: %v", err)
+		}
+		if result != want {
+			t.Errorf("PreviewBatchUpsert = %+v, want %+v", result, want)
+		}
+	})
+
+	t.Run("increment", func(t *testing.T) {
+		increments := make([]ProgressIncrement, 0, len(goalIDs))
+		for _, goalID := range goalIDs {
+			increments = append(increments, ProgressIncrement{
+				UserID: "preview-user", GoalID: goalID, ChallengeID: "c1", Namespace: "test",
+				Delta: 1, TargetValue: 10,
+			})
+		}
+
+		result, err := repo.PreviewBatchIncrement(ctx, increments)
+		if err != nil {
+			t.Fatalf("PreviewBatchIncrement failed: %v", err)
+		}
+		if result != want {
+			t.Errorf("PreviewBatchIncrement = %+v, want %+v", result, want)
+		}
+	})
+
+	t.Run("nothing is written", func(t *testing.T) {
+		p, err := repo.GetProgress(ctx, "preview-user", "missing")
+		if err != nil || p != nil {
+			t.Errorf("Expected no row for missing goal, got %+v, err=%v", p, err)
+		}
+
+		p, _ = repo.GetProgress(ctx, "preview-user", "active")
+		if p.Progress != 1 {
+			t.Errorf("Expected progress unchanged, got %d", p.Progress)
+		}
+	})
+}
@@ -0,0 +1,26 @@
+package repository
+
+// Gate tracks in-flight work for graceful shutdown (implemented by db.Gate).
+type Gate interface {
+	// Acquire registers one unit of work, or returns an error once shutdown has begun.
+	Acquire() error
+
+	// Release marks work registered with Acquire as finished.
+	Release()
+}
+
+// acquireGate registers a unit of work with the configured gate (no-op without WithGate).
+// Every successful call must be paired with releaseGate.
+func (r *PostgresGoalRepository) acquireGate() error {
+	if r.gate == nil {
+		return nil
+	}
+	return r.gate.Acquire()
+}
+
+// releaseGate releases a unit of work registered with acquireGate.
+func (r *PostgresGoalRepository) releaseGate() {
+	if r.gate != nil {
+		r.gate.Release()
+	}
+}
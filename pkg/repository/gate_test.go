@@ -0,0 +1,97 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/AccelByte/extend-challenge-common/pkg/db"
+	"github.com/AccelByte/extend-challenge-common/pkg/domain"
+	customerrors "github.com/AccelByte/extend-challenge-common/pkg/errors"
+)
+
+// db.Gate is the Gate implementation used with WithGate.
+var _ Gate = (*db.Gate)(nil)
+
+func TestPostgresGoalRepository_GracefulShutdown(t *testing.T) {
+	testDB := setupTestDB(t)
+	if testDB == nil {
+		return
+	}
+	defer cleanupTestDB(t, testDB)
+
+	ctx := context.Background()
+
+	// The gated pool is separate from testDB because Shutdown closes it
+	sqlDB, err := sql.Open("postgres", testDSN)
+	if err != nil {
+		t.Fatalf("Open failed: %v", err)
+	}
+	gated := db.NewGatedDB(sqlDB)
+	repo := NewPostgresGoalRepository(gated.DB, WithGate(gated.Gate()))
+
+	err = repo.BulkInsert(ctx, []*domain.UserGoalProgress{
+		{UserID: "shutdown-user", GoalID: "goal-1", ChallengeID: "c1", Namespace: "test", Status: domain.GoalStatusNotStarted, IsActive: true},
+	})
+	if err != nil {
+		t.Fatalf("BulkInsert failed: %v", err)
+	}
+
+	// Hold the row lock on testDB for a while so the batch below is slow
+	blocker, err := testDB.BeginTx(ctx, nil)
+	if err != nil {
+		t.Fatalf("BeginTx failed: %v", err)
+	}
+	_, err = blocker.ExecContext(ctx, "SELECT 1 FROM user_goal_progress WHERE user_id = 'shutdown-user' FOR UPDATE")
+	if err != nil {
+		t.Fatalf("Lock failed: %v", err)
+	}
+	go func() {
+		_, _ = blocker.ExecContext(ctx, "SELECT pg_sleep(0.3)")
+		_ = blocker.Commit()
+	}()
+
+	batchDone := make(chan error, 1)
+	go func() {
+		batchDone <- repo.BatchIncrementProgress(ctx, []ProgressIncrement{
+			{UserID: "shutdown-user", GoalID: "goal-1", Delta: 2, TargetValue: 10},
+		})
+	}()
+
+	// Let the batch acquire the gate before shutdown begins
+	time.Sleep(50 * time.Millisecond)
+
+	shutdownDone := make(chan error, 1)
+	go func() { shutdownDone <- db.Shutdown(ctx, gated.DB) }()
+
+	time.Sleep(50 * time.Millisecond)
+
+	// A batch started after Shutdown began is rejected
+	err = repo.BatchIncrementProgress(ctx, []ProgressIncrement{
+		{UserID: "shutdown-user", GoalID: "goal-1", Delta: 1, TargetValue: 10},
+	})
+	var ce *customerrors.ChallengeError
+	if !errors.As(err, &ce) || ce.Code != customerrors.ErrCodeShuttingDown {
+		t.Errorf("Expected ErrCodeShuttingDown, got %v", err)
+	}
+
+	if err := <-batchDone; err != nil {
+		t.Errorf("Expected in-flight batch to complete, got %v", err)
+	}
+	if err := <-shutdownDone; err != nil {
+		t.Errorf("Shutdown failed: %v", err)
+	}
+	if err := gated.Ping(); err == nil {
+		t.Error("Expected pool to be closed after Shutdown")
+	}
+
+	p, err := NewPostgresGoalRepository(testDB).GetProgress(ctx, "shutdown-user", "goal-1")
+	if err != nil {
+		t.Fatalf("GetProgress failed: %v", err)
+	}
+	if p.Progress != 2 {
+		t.Errorf("Expected only the in-flight batch to apply (progress 2), got %d", p.Progress)
+	}
+}
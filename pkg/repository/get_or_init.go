@@ -0,0 +1,68 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/AccelByte/extend-challenge-common/pkg/domain"
+)
+
+// goalIDsOf returns the distinct IDs of goals, in order.
+func goalIDsOf(goals []*domain.Goal) []string {
+	ids := make([]string, 0, len(goals))
+	seen := make(map[string]bool, len(goals))
+	for _, goal := range goals {
+		if !seen[goal.ID] {
+			seen[goal.ID] = true
+			ids = append(ids, goal.ID)
+		}
+	}
+	return ids
+}
+
+// fillMissingGoals returns one row per goal, in the order of goals, taking rows from
+// existing and building a not_started placeholder for each goal without one.
+// Placeholders are inactive, have no timestamps and leave Namespace empty (goal configs
+// do not carry one); OrderIndex comes from Goal.Order as it would on assignment.
+func fillMissingGoals(userID string, goals []*domain.Goal, existing []*domain.UserGoalProgress) []*domain.UserGoalProgress {
+	byGoal := make(map[string]*domain.UserGoalProgress, len(existing))
+	for _, progress := range existing {
+		byGoal[progress.GoalID] = progress
+	}
+
+	results := make([]*domain.UserGoalProgress, 0, len(goals))
+	for _, goal := range goals {
+		if progress, ok := byGoal[goal.ID]; ok {
+			results = append(results, progress)
+			continue
+		}
+		results = append(results, &domain.UserGoalProgress{
+			UserID:      userID,
+			GoalID:      goal.ID,
+			ChallengeID: goal.ChallengeID,
+			Progress:    0,
+			Status:      domain.GoalStatusNotStarted,
+			IsActive:    false,
+			OrderIndex:  goal.Order,
+		})
+	}
+	return results
+}
+
+// GetOrInitGoals returns a user's progress for each goal, with in-memory placeholders for
+// goals that have no row. Nothing is written to the database.
+func (r *PostgresGoalRepository) GetOrInitGoals(ctx context.Context, userID string, goals []*domain.Goal) ([]*domain.UserGoalProgress, error) {
+	existing, err := r.GetGoalsByIDs(ctx, userID, goalIDsOf(goals))
+	if err != nil {
+		return nil, err
+	}
+	return fillMissingGoals(userID, goals, existing), nil
+}
+
+// GetOrInitGoals returns a user's progress for each goal within a transaction.
+func (r *PostgresTxRepository) GetOrInitGoals(ctx context.Context, userID string, goals []*domain.Goal) ([]*domain.UserGoalProgress, error) {
+	existing, err := r.GetGoalsByIDs(ctx, userID, goalIDsOf(goals))
+	if err != nil {
+		return nil, err
+	}
+	return fillMissingGoals(userID, goals, existing), nil
+}
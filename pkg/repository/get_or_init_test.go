@@ -0,0 +1,76 @@
+package repository
+
+import (
+	"context"
+	"testing"
+
+	"github.com/AccelByte/extend-challenge-common/pkg/domain"
+)
+
+func TestFillMissingGoals(t *testing.T) {
+	goals := []*domain.Goal{
+		{ID: "wins", ChallengeID: "c1", Order: 2},
+		{ID: "kills", ChallengeID: "c1", Order: 1},
+	}
+	existing := []*domain.UserGoalProgress{
+		{UserID: "u1", GoalID: "kills", ChallengeID: "c1", Progress: 9, Status: domain.GoalStatusInProgress, IsActive: true},
+	}
+
+	got := fillMissingGoals("u1", goals, existing)
+	if len(got) != 2 {
+		t.Fatalf("fillMissingGoals returned %d rows, want 2", len(got))
+	}
+
+	// Rows follow the order of goals, not of existing
+	placeholder, stored := got[0], got[1]
+	if stored != existing[0] {
+		t.Errorf("kills row = %+v, want the stored row", stored)
+	}
+	want := domain.UserGoalProgress{
+		UserID: "u1", GoalID: "wins", ChallengeID: "c1",
+		Status: domain.GoalStatusNotStarted, OrderIndex: 2,
+	}
+	if *placeholder != want {
+		t.Errorf("wins placeholder = %+v, want %+v", *placeholder, want)
+	}
+}
+
+func TestPostgresGoalRepository_GetOrInitGoals(t *testing.T) {
+	db := setupTestDB(t)
+	if db == nil {
+		return
+	}
+	defer cleanupTestDB(t, db)
+
+	repo := NewPostgresGoalRepository(db)
+	ctx := context.Background()
+
+	if err := repo.BulkInsert(ctx, []*domain.UserGoalProgress{
+		{UserID: "init-user", GoalID: "kills", ChallengeID: "c1", Namespace: "test", Progress: 4, Status: domain.GoalStatusInProgress, IsActive: true},
+	}); err != nil {
+		t.Fatalf("BulkInsert failed: %v", err)
+	}
+
+	goals := []*domain.Goal{{ID: "kills", ChallengeID: "c1"}, {ID: "wins", ChallengeID: "c1"}}
+	got, err := repo.GetOrInitGoals(ctx, "init-user", goals)
+	if err != nil {
+		t.Fatalf("GetOrInitGoals failed: %v", err)
+	}
+	if len(got) != 2 || got[0].Progress != 4 || got[1].Status != domain.GoalStatusNotStarted || got[1].IsActive {
+		t.Errorf("GetOrInitGoals = %+v, want the stored kills row and a wins placeholder", got)
+	}
+
+	// The placeholder is not persisted
+	if stored, err := repo.GetProgress(ctx, "init-user", "wins"); err != nil || stored != nil {
+		t.Errorf("GetProgress(wins) = %+v, %v, want no row", stored, err)
+	}
+
+	tx, err := repo.BeginTx(ctx)
+	if err != nil {
+		t.Fatalf("BeginTx failed: %v", err)
+	}
+	defer func() { _ = tx.Rollback() }()
+	if got, err := tx.GetOrInitGoals(ctx, "init-user", goals); err != nil || len(got) != 2 {
+		t.Errorf("GetOrInitGoals in transaction = %+v, %v", got, err)
+	}
+}
@@ -2,6 +2,7 @@ package repository
 
 import (
 	"context"
+	"time"
 
 	"github.com/AccelByte/extend-challenge-common/pkg/domain"
 )
@@ -15,7 +16,35 @@ type ProgressIncrement struct {
 	Namespace        string // Namespace
 	Delta            int    // Amount to increment progress by
 	TargetValue      int    // Target value for completion check
-	IsDailyIncrement bool   // If true, only increments once per day (based on updated_at date)
+	IsDailyIncrement bool   // If true, only increments once per UTC day of the event (see OccurredAt), tracked in last_daily_date
+
+	// Cooldown, when > 0, only applies Delta if at least this long has passed since the
+	// last applied increment (NOW() - updated_at >= Cooldown); otherwise the entry is a no-op
+	// and updated_at is left unchanged so the cooldown window is not extended.
+	// Rows still in 'not_started' status are not subject to the cooldown.
+	Cooldown time.Duration
+
+	// MaxDeltaPerEvent, when > 0, caps how far this single entry can advance progress:
+	// a larger Delta is clamped to MaxDeltaPerEvent before it is applied. 0 = unlimited
+	// (or the repository default set with WithMaxDeltaPerEvent). Negative deltas are not clamped.
+	MaxDeltaPerEvent int
+	// OccurredAt, when set, is the time the source event happened. Daily increments bucket
+	// by the UTC day of OccurredAt instead of NOW(), so a late-arriving event for yesterday
+	// counts for yesterday; updated_at still records the write time. Entries older than the
+	// repository's max lateness (see WithMaxEventLateness) are skipped. Future times are
+	// treated as NOW().
+	OccurredAt *time.Time
+
+	// OverflowPolicy, when set, bounds how far this entry can push progress past
+	// TargetValue (see CapAtTarget, CapAtMultiple). The zero value uses the repository
+	// policy set with WithOverflowPolicy.
+	OverflowPolicy OverflowPolicy
+
+	// AttemptDelta is added to the row's attempt counter (see UserGoalProgress.Attempts).
+	// Attempts are counted even when Delta is 0 or a daily entry was already counted today;
+	// an entry with Delta 0 leaves progress, status and completed_at unchanged. Rows that
+	// skip the entry (claimed, inactive, or inside the cooldown) count no attempt.
+	AttemptDelta int
 }
 
 // GoalRepository defines the interface for managing user goal progress in the database.
@@ -25,24 +54,66 @@ type GoalRepository interface {
 	// Returns nil if no progress record exists (lazy initialization).
 	GetProgress(ctx context.Context, userID, goalID string) (*domain.UserGoalProgress, error)
 
-	// GetUserProgress retrieves all goal progress records for a specific user.
+	// GetProgressRequired is GetProgress for call sites that expect the row to exist:
+	// a missing row returns errors.ErrProgressNotFound instead of (nil, nil).
+	GetProgressRequired(ctx context.Context, userID, goalID string) (*domain.UserGoalProgress, error)
+
+	// GetUserProgress retrieves all goal progress records for a specific user, ordered by
+	// order_index (the goal's designer-controlled Order) and then created_at.
 	// Returns empty slice if user has no progress records.
 	// M3 Phase 4: activeOnly parameter filters to only is_active = true goals.
+	// With WithLazyExpireOnRead, activeOnly also excludes goals past expires_at.
 	GetUserProgress(ctx context.Context, userID string, activeOnly bool) ([]*domain.UserGoalProgress, error)
 
-	// GetChallengeProgress retrieves all goal progress for a user within a specific challenge.
+	// GetUserProgressLite returns the goal ID, progress and status of the rows
+	// GetUserProgress would return, in the same order, for high-frequency board refreshes
+	// that do not need the full row. Use GetUserProgress for detail views.
+	GetUserProgressLite(ctx context.Context, userID string, activeOnly bool) ([]ProgressLite, error)
+
+	// GetChallengeProgress retrieves all goal progress for a user within a specific challenge,
+	// in the same order as GetUserProgress.
 	// Returns empty slice if user has no progress for this challenge.
 	// M3 Phase 4: activeOnly parameter filters to only is_active = true goals.
 	GetChallengeProgress(ctx context.Context, userID, challengeID string, activeOnly bool) ([]*domain.UserGoalProgress, error)
 
+	// StreamUserProgress calls fn for each row GetUserProgress would return, in the same
+	// order, scanning one row at a time instead of materializing the slice. Returning
+	// ErrStopIteration from fn stops the scan and StreamUserProgress returns nil; any
+	// other error from fn stops it and is returned as is. See WithReusedRow before
+	// retaining the rows passed to fn.
+	StreamUserProgress(ctx context.Context, userID string, activeOnly bool, fn func(*domain.UserGoalProgress) error, opts ...StreamOption) error
+
+	// StreamChallengeProgress is StreamUserProgress for the rows GetChallengeProgress
+	// would return.
+	StreamChallengeProgress(ctx context.Context, userID, challengeID string, activeOnly bool, fn func(*domain.UserGoalProgress) error, opts ...StreamOption) error
+
+	// GetProgressForPairs is GetChallengeProgress for many (user, challenge) pairs in one
+	// round trip, e.g. a GraphQL dataloader batch. Rows are grouped per pair in the
+	// order of GetChallengeProgress. Every input pair has an entry, an empty slice if it has no rows, so callers can
+	// cache negative results; duplicate pairs are read once.
+	GetProgressForPairs(ctx context.Context, pairs []UserChallengePair, activeOnly bool) (map[UserChallengePair][]*domain.UserGoalProgress, error)
+
+	// GetUserProgressPage retrieves one keyset-paginated page of a user's goal progress records.
+	// Returns the page and the cursor for the next page ("" when there are no more rows).
+	//
+	// opts.OrderBy selects the ordering (created_at ascending by default, expires_at ascending
+	// with NULLs last, or updated_at descending). The ordering is applied in SQL and encoded
+	// into the cursor, so a cursor is only valid with the ordering that produced it; a cursor
+	// from a different ordering returns an errors.ErrCodeInvalidCursor error.
+	GetUserProgressPage(ctx context.Context, userID string, opts PageOptions) ([]*domain.UserGoalProgress, string, error)
+
+	// GetChallengeProgressPage is the challenge-scoped counterpart of GetUserProgressPage.
+	GetChallengeProgressPage(ctx context.Context, userID, challengeID string, opts PageOptions) ([]*domain.UserGoalProgress, string, error)
+
 	// UpsertProgress creates or updates a single goal progress record.
 	// Uses INSERT ... ON CONFLICT (user_id, goal_id) DO UPDATE.
-	// Does NOT update if status is 'claimed' (protection against overwrites).
+	// Does NOT update if status is 'claimed' or 'claiming' (protection against overwrites).
 	UpsertProgress(ctx context.Context, progress *domain.UserGoalProgress) error
 
 	// BatchUpsertProgress performs batch upsert for multiple progress records in a single query.
 	// This is the key optimization for the buffered event processing (1,000,000x query reduction).
-	// Does NOT update records where status is 'claimed'.
+	// Does NOT update records where status is 'claimed' or 'claiming'. Several entries for
+	// the same user and goal are merged into one first (see MergeStrategy).
 	//
 	// DEPRECATED: Use BatchUpsertProgressWithCOPY for better performance (5-10x faster).
 	// This method is kept for backwards compatibility and testing.
@@ -53,7 +124,9 @@ type GoalRepository interface {
 
 	// BatchUpsertProgressWithCOPY performs batch upsert using PostgreSQL COPY protocol.
 	// This is 5-10x faster than BatchUpsertProgress (10-20ms vs 62-105ms for 1,000 records).
-	// Does NOT update records where status is 'claimed'.
+	// Does NOT update records where status is 'claimed' or 'claiming'. Several entries for
+	// the same user and goal are merged into one first (see MergeStrategy). Batches above
+	// WithMaxCopyBatchRows are written in several COPY+merge cycles within one transaction.
 	//
 	// USAGE: Use this for production workloads requiring high throughput (500+ EPS).
 	// This method solves the Phase 1 database bottleneck by reducing flush time from
@@ -69,9 +142,9 @@ type GoalRepository interface {
 	//   - Example: progress=5, delta=3 → progress=8
 	//
 	// For daily increment goals (isDailyIncrement=true):
-	//   - Only increments once per day (uses updated_at date from DB)
-	//   - If updated_at is today, this is a no-op (progress unchanged)
-	//   - If updated_at is before today, increments by delta and updates timestamp
+	//   - Only increments once per day (uses last_daily_date, or updated_at date for older rows)
+	//   - If today was already counted, this is a no-op (progress unchanged)
+	//   - Otherwise increments by delta, records today, and updates timestamp
 	//   - Example: Day 1 progress=3 → increment(1) → progress=4
 	//              Same day → increment(1) → progress=4 (no change)
 	//              Next day → increment(1) → progress=5
@@ -83,10 +156,75 @@ type GoalRepository interface {
 	// USAGE: Use this for single increment operations during event processing.
 	// For batch operations (flush), use BatchIncrementProgress instead for better performance.
 	//
-	// Does NOT update if status is 'claimed'.
+	// Does NOT update if status is 'claimed' or 'claiming'.
+	//
+	// On the pool only existing active rows are updated; in a TxRepository a missing row is
+	// inserted unless the repository has WithRequireAssignmentForIncrements, which makes
+	// every increment method update active rows only.
+	//
+	// Returns an ErrInvalidIncrement error without touching the database if targetValue <= 0
+	// or |delta| exceeds the configured cap. Returns an ErrProgressOverflow error, leaving the
+	// row unchanged, if the increment would push progress past the progress ceiling
+	// (see WithProgressCeiling).
+	// A positive delta is first clamped to the repository's WithMaxDeltaPerEvent cap, if set.
 	IncrementProgress(ctx context.Context, userID, goalID, challengeID, namespace string,
 		delta, targetValue int, isDailyIncrement bool) error
 
+	// IncrementProgressWithCooldown atomically increments progress only if at least cooldown
+	// has passed since the last applied increment (NOW() - updated_at >= cooldown).
+	// Used for repeatable goals gated by an arbitrary interval (e.g., "win a match, then wait
+	// an hour"), like the daily same-day check but with a configurable window.
+	//
+	// Inside the cooldown window the call is a no-op: progress, status and updated_at are left
+	// unchanged so ignored events do not extend the window. Rows still in 'not_started' status
+	// are not subject to the cooldown. A zero cooldown behaves like a regular increment.
+	//
+	// Does NOT update if status is 'claimed' or 'claiming'.
+	IncrementProgressWithCooldown(ctx context.Context, userID, goalID, challengeID, namespace string,
+		delta, targetValue int, cooldown time.Duration) error
+
+	// IncrementRepeatableProgress increments a repeatable goal (Goal.Repeatable), which
+	// completes again every time progress reaches targetValue. Each full targetValue in
+	// progress + delta adds one to the row's completions (migration 018; see WithCompletions
+	// for reading it back) and the
+	// remainder carries over, so the row is re-armed to 'in_progress' instead of waiting for
+	// a claim. Rewards are granted by the caller, once per RepeatableIncrementResult.NewCompletions.
+	//
+	// maxRepeats > 0 caps completions: the increment that reaches the cap completes only
+	// the repeats left, leaves progress at targetValue and moves the row to 'claimed', after
+	// which increments are no-ops. 0 means unlimited.
+	//
+	// Only existing active rows are updated, in a transaction too; a missing, inactive,
+	// 'claimed' or 'claiming' row returns a zero result without error. Returns an
+	// ErrInvalidIncrement error without touching the database if targetValue <= 0, delta is
+	// negative or exceeds the configured cap, or maxRepeats is negative. A positive delta is
+	// first clamped to the repository's WithMaxDeltaPerEvent cap, if set.
+	IncrementRepeatableProgress(ctx context.Context, userID, goalID string, delta, targetValue, maxRepeats int) (RepeatableIncrementResult, error)
+
+	// IncrementProgressOnce applies inc only if eventID has not been processed before, so
+	// redelivered events (at-least-once delivery) are not double-counted. The event ID is
+	// recorded in processed_events (optional migration 010) in the same transaction as the
+	// increment: on the pool in a transaction of its own, otherwise in the caller's.
+	//
+	// inc is applied like a one-entry BatchIncrementProgress. Returns applied=false without
+	// error when eventID was already recorded. An increment that is skipped before the
+	// event is recorded (a late OccurredAt, or an invalid entry in lenient mode) also
+	// returns false, and a later delivery is evaluated again. An increment skipped at the
+	// progress ceiling returns false too, but its event stays recorded. Errors roll the
+	// event ID back with the increment. An empty eventID fails with ErrValidationFailed.
+	IncrementProgressOnce(ctx context.Context, eventID string, inc ProgressIncrement) (applied bool, err error)
+
+	// BatchIncrementProgressOnce is the batch form of IncrementProgressOnce for buffered
+	// flushes. In one transaction it records the events' IDs (ON CONFLICT DO NOTHING
+	// RETURNING) and applies only the increments whose IDs were newly recorded; replayed
+	// IDs, and repeats of an ID within events, are skipped. Increments for the same goal
+	// are merged before they are applied (see CoalesceIncrements), so none are lost.
+	//
+	// Returns the number of events whose increments were applied. Events skipped after
+	// recording (late, invalid in lenient mode, or past the progress ceiling) stay
+	// recorded but are not counted. Any error rolls back every recorded ID.
+	BatchIncrementProgressOnce(ctx context.Context, events []EventIncrement) (appliedCount int, err error)
+
 	// BatchIncrementProgress performs batch atomic increment for multiple progress records.
 	// This is the key optimization for buffered increment event processing (50x better than individual calls).
 	//
@@ -95,23 +233,90 @@ type GoalRepository interface {
 	//   - Single SQL query using UNNEST for all increments
 	//
 	// For daily increment goals (IsDailyIncrement=true):
-	//   - Only increments if updated_at date is before today
-	//   - Uses DATE(updated_at AT TIME ZONE 'UTC') for timezone-safe comparison
+	//   - Only increments if the event's UTC day (OccurredAt, or today) is after the last
+	//     counted day (last_daily_date, falling back to DATE(updated_at) for older rows)
+	//   - Uses AT TIME ZONE 'UTC' for timezone-safe comparison
 	//   - Updates updated_at timestamp after increment
 	//
+	// For cooldown increments (Cooldown > 0):
+	//   - Only increments if NOW() - updated_at >= Cooldown (see IncrementProgressWithCooldown)
+	//
 	// USAGE: Use this during periodic flush to batch all accumulated increments.
 	// This reduces 1,000 individual queries to 1 single batch query (50x performance gain).
 	//
 	// Performance: 1,000 increments in ~20ms (vs 1,000ms for individual calls)
 	//
-	// Does NOT update if status is 'claimed'.
+	// Does NOT update if status is 'claimed' or 'claiming'.
+	//
+	// Entries are validated like IncrementProgress before any SQL runs. In strict mode
+	// (the default) one invalid entry rejects the batch; in lenient mode invalid entries are skipped.
+	// Each Delta is clamped to its MaxDeltaPerEvent (see ProgressIncrement) before validation.
+	// Entries that would push progress past the progress ceiling (see WithProgressCeiling)
+	// are skipped by the statement itself and logged, in either mode; the rest of the batch
+	// is applied.
 	BatchIncrementProgress(ctx context.Context, increments []ProgressIncrement) error
 
+	// BatchIncrementProgressReturning performs the same batch increment as BatchIncrementProgress
+	// and returns the rows that are in 'completed' status afterwards, in the same round trip.
+	//
+	// Each result reports NewlyCompleted=true when the row transitioned to 'completed' as part
+	// of this batch, so callers can fan out completion events (reward availability,
+	// notifications) without re-reading rows. Rows skipped by the claimed/is_active guards
+	// are never returned.
+	//
+	// Returns an empty slice when no increment left a row completed.
+	BatchIncrementProgressReturning(ctx context.Context, increments []ProgressIncrement) ([]CompletionResult, error)
+
+	// BatchIncrementProgressWithResult performs the same batch increment as
+	// BatchIncrementProgressReturning and also reports how many entries were skipped
+	// before any SQL ran because their OccurredAt was older than the max lateness, and, with
+	// WithRequireAssignmentForIncrements, how many were dropped for lack of an active row.
+	BatchIncrementProgressWithResult(ctx context.Context, increments []ProgressIncrement) (BatchIncrementResult, error)
+
 	// MarkAsClaimed updates a goal's status to 'claimed' and sets claimed_at timestamp.
 	// Used after successfully granting rewards via AGS Platform Service.
 	// Returns error if goal is not in 'completed' status or already claimed.
+	// A goal forfeited by ForfeitExpiredClaims fails with ErrClaimWindowExpired.
 	MarkAsClaimed(ctx context.Context, userID, goalID string) error
 
+	// MarkAsClaimedWithDeadline is MarkAsClaimed for goals with a claim deadline.
+	// claimDeadline is the goal's ClaimDeadline (0 = no deadline, same as MarkAsClaimed);
+	// the repository does not read config, so callers pass it in. A goal completed longer
+	// than claimDeadline ago fails with ErrClaimWindowExpired.
+	MarkAsClaimedWithDeadline(ctx context.Context, userID, goalID string, claimDeadline time.Duration) error
+
+	// ReserveClaim is the first phase of a two-phase claim for rewards granted by an
+	// external service. It moves a completed goal to 'claiming' for the repository's
+	// claim reservation TTL (see WithClaimReservationTTL); progress writes and other
+	// claims skip the row meanwhile. Fails with ErrClaimInProgress if another claim holds
+	// the goal, and with ErrClaimWindowExpired if the goal was forfeited.
+	//
+	// Grant the reward after ReserveClaim succeeds, then call ConfirmClaim on success or
+	// CancelClaim on failure. Reservations never finished are returned to 'completed'
+	// by ReleaseExpiredClaimReservations.
+	ReserveClaim(ctx context.Context, userID, goalID string) error
+
+	// ReserveClaimWithDeadline is ReserveClaim for goals with a claim deadline;
+	// claimDeadline is checked as in MarkAsClaimedWithDeadline.
+	ReserveClaimWithDeadline(ctx context.Context, userID, goalID string, claimDeadline time.Duration) error
+
+	// ConfirmClaim finalizes a reserved goal to 'claimed' and sets claimed_at. Fails with
+	// ErrClaimNotReserved if the goal holds no reservation, including one whose
+	// reservation TTL has passed but which the sweeper has not released yet: the row may
+	// be reserved again by another claim as soon as it is released, so a late confirm
+	// must not finalize it.
+	ConfirmClaim(ctx context.Context, userID, goalID string) error
+
+	// CancelClaim returns a reserved goal to 'completed' so it can be claimed again.
+	// Fails with ErrClaimNotReserved if the goal holds no reservation.
+	CancelClaim(ctx context.Context, userID, goalID string) error
+
+	// GetClaimEligibility fetches a goal and its prerequisites in a single query and reports
+	// whether the goal can be claimed, with a reason when it cannot.
+	// Prerequisites count as met when completed or claimed.
+	// In a TxRepository the fetched rows are locked (FOR UPDATE) for the claim that follows.
+	GetClaimEligibility(ctx context.Context, userID, goalID string, prerequisiteGoalIDs []string) (*ClaimEligibility, error)
+
 	// BeginTx starts a database transaction and returns a transactional repository.
 	// Used for claim flow to ensure atomicity (check status + mark claimed + verify).
 	BeginTx(ctx context.Context) (TxRepository, error)
@@ -123,6 +328,19 @@ type GoalRepository interface {
 	// Used by initialization endpoint to check which default goals already exist.
 	GetGoalsByIDs(ctx context.Context, userID string, goalIDs []string) ([]*domain.UserGoalProgress, error)
 
+	// GetGoalsByIDsFiltered is GetGoalsByIDs with the filter applied in SQL: ActiveOnly keeps
+	// is_active = true rows and a non-empty Statuses keeps rows in one of those statuses.
+	// An empty filter behaves like GetGoalsByIDs. An unknown status returns an
+	// errors.ErrCodeValidationFailed error without querying.
+	// Used by the M4 selection service and admin views to skip deactivated or finished goals.
+	GetGoalsByIDsFiltered(ctx context.Context, userID string, goalIDs []string, filter ProgressFilter) ([]*domain.UserGoalProgress, error)
+
+	// GetOrInitGoals returns exactly one row per goal, in the order of goals: the stored row
+	// from GetGoalsByIDs, or an in-memory not_started placeholder (progress 0, is_active
+	// false) for a goal without one. Placeholders are not written to the database.
+	// Used by event handlers that need current progress without special-casing nil.
+	GetOrInitGoals(ctx context.Context, userID string, goals []*domain.Goal) ([]*domain.UserGoalProgress, error)
+
 	// BulkInsert creates multiple goal progress records in a single parameterized INSERT query.
 	// Uses INSERT ... ON CONFLICT DO NOTHING for idempotency.
 	// Used by initialization endpoint to create default goal assignments.
@@ -144,6 +362,17 @@ type GoalRepository interface {
 	// For batches >= 1000 records, consider BulkInsertWithCOPY.
 	BulkInsert(ctx context.Context, progresses []*domain.UserGoalProgress) error
 
+	// BulkInsertWithPriority is BulkInsert for first-login default assignment under a
+	// per-user row cap. It counts the user's existing rows and inserts, in one statement,
+	// only as many of progresses as fit under maxRowsForUser, taking them in ascending
+	// priorities order (priorities[i] belongs to progresses[i]; lower is more important,
+	// ties keep input order). Returns the inserted and skipped goal IDs, both in priority
+	// order; goals the user already has are skipped without taking headroom.
+	// All rows must belong to one user and priorities must match progresses in length,
+	// else errors.ErrValidationFailed. With WithPerUserLocking the count and the insert
+	// hold the user's lock, so concurrent calls cannot overshoot the cap.
+	BulkInsertWithPriority(ctx context.Context, progresses []*domain.UserGoalProgress, priorities []int, maxRowsForUser int) (inserted, skipped []string, err error)
+
 	// BulkInsertWithCOPY creates multiple goal progress records using PostgreSQL COPY protocol.
 	//
 	// ⚠️  WARNING: DO NOT USE FOR SMALL BATCHES (< 1000 records)
@@ -168,8 +397,26 @@ type GoalRepository interface {
 	// If row doesn't exist, creates it with is_active and assigned_at fields.
 	// If row exists, updates is_active and assigned_at (only when activating).
 	// Used by manual activation/deactivation endpoint.
+	//
+	// Activating resets assigned_at to NOW() every time, including for a goal that is
+	// already active, so a repeated activation restarts the assignment window.
+	// Deactivating keeps assigned_at. It is equivalent to UpsertGoalActiveWithOptions
+	// with UpdateAssignedAt set.
 	UpsertGoalActive(ctx context.Context, progress *domain.UserGoalProgress) error
 
+	// UpsertGoalActiveWithOptions is UpsertGoalActive with explicit control over
+	// assigned_at (see ActivationOptions). With the zero options, activating an existing
+	// row keeps its assigned_at and only sets one when none is recorded.
+	UpsertGoalActiveWithOptions(ctx context.Context, progress *domain.UserGoalProgress, opts ActivationOptions) error
+
+	// EnsureAssigned is an idempotent assignment primitive for periodic assignment jobs.
+	// Missing rows are inserted active with status='not_started' and assigned_at=NOW();
+	// rows that already exist are left completely untouched (progress, status, is_active
+	// and assigned_at are preserved). Only UserID, GoalID, ChallengeID, Namespace and
+	// ExpiresAt are read from the input.
+	// Returns the number of newly assigned rows.
+	EnsureAssigned(ctx context.Context, progresses []*domain.UserGoalProgress) (int64, error)
+
 	// M4: Batch goal activation for random/batch selection
 
 	// BatchUpsertGoalActive activates multiple goals in a single database operation.
@@ -177,6 +424,7 @@ type GoalRepository interface {
 	//
 	// Behavior:
 	//   - If row exists: sets is_active=true, assigned_at=NOW(), updated_at=NOW()
+	//     (deactivating entries clear assigned_at to NULL, unlike UpsertGoalActive)
 	//   - If row doesn't exist: creates new row with is_active=true, status='not_started'
 	//
 	// Performance: ~10ms for 10 goals (vs ~20-50ms with individual UpsertGoalActive loop)
@@ -194,6 +442,23 @@ type GoalRepository interface {
 	// TxRepository inherits this method via embedding.
 	BatchUpsertGoalActive(ctx context.Context, progresses []*domain.UserGoalProgress) error
 
+	// DeactivateChallengeGoals deactivates all of a user's active goals in a challenge
+	// in a single UPDATE. Used when a user opts out of a challenge.
+	// assigned_at is preserved. Returns the number of rows deactivated.
+	DeactivateChallengeGoals(ctx context.Context, userID, challengeID string) (int64, error)
+
+	// ReactivateChallengeGoals reactivates all of a user's inactive goals in a challenge
+	// in a single UPDATE, setting assigned_at=NOW(). Counterpart of DeactivateChallengeGoals.
+	// Only existing rows are affected. Returns the number of rows reactivated.
+	ReactivateChallengeGoals(ctx context.Context, userID, challengeID string) (int64, error)
+
+	// DeleteChallengeProgress deletes all progress rows of a challenge in a namespace.
+	// Used by operators to purge a retired challenge. Returns the number of rows deleted.
+	//
+	// Destructive: returns ErrOperationNotAllowed unless the repository was constructed
+	// with WithAllowBulkDelete.
+	DeleteChallengeProgress(ctx context.Context, namespace, challengeID string) (int64, error)
+
 	// M3 Phase 9: Fast path optimization methods
 
 	// GetUserGoalCount returns the total number of goals for a user (active + inactive).
@@ -207,6 +472,22 @@ type GoalRepository interface {
 	// Used by initialization endpoint's fast path to avoid querying all 500 goal IDs.
 	// Performance: < 5ms using idx_user_goal_active_only partial index.
 	GetActiveGoals(ctx context.Context, userID string) ([]*domain.UserGoalProgress, error)
+
+	// GetCompletedBetween retrieves a user's completed or claimed goals whose completed_at
+	// falls within [from, to] (inclusive), newest first.
+	// Used by recap features (e.g., "goals completed in the last 7 days").
+	// Returns empty slice if no goals were completed in the window.
+	GetCompletedBetween(ctx context.Context, userID string, from, to time.Time) ([]*domain.UserGoalProgress, error)
+
+	// GetDailyGoalsEligible retrieves a user's active, unclaimed daily goals whose updated_at
+	// is before the start of the current day in tz (nil = UTC), i.e. goals a daily event
+	// would advance again. Used by schedulers to prompt players or pre-warm state; read-only.
+	//
+	// Daily goals are recognized by last_daily_date, so only rows counted by at least one
+	// daily increment are returned. Rows created by assignment but never incremented are not.
+	// With tz = UTC this matches the increment's same-day check for rows updated only by
+	// daily increments.
+	GetDailyGoalsEligible(ctx context.Context, userID string, tz *time.Location) ([]*domain.UserGoalProgress, error)
 }
 
 // TxRepository represents a transactional repository that supports commit/rollback.
@@ -218,6 +499,18 @@ type TxRepository interface {
 	// This prevents concurrent claim attempts for the same goal.
 	GetProgressForUpdate(ctx context.Context, userID, goalID string) (*domain.UserGoalProgress, error)
 
+	// ClaimAndRecordGrant marks a goal as claimed and records the reward grant intent in
+	// reward_grants as a single atomic statement within the transaction.
+	// Returns ErrGoalNotCompleted (and records nothing) if the goal is not completed or
+	// already claimed; if the grant cannot be recorded, the goal is left unclaimed.
+	ClaimAndRecordGrant(ctx context.Context, userID, goalID string, grant GrantRecord) error
+
+	// ClaimAllOrNothing claims every listed goal or none: each must be completed, unclaimed,
+	// active and not forfeited. Otherwise nothing is written and errors.ErrNotAllClaimable
+	// lists the offending goals; roll the transaction back as for any failed claim.
+	// Used for bundled rewards that require several goals together.
+	ClaimAllOrNothing(ctx context.Context, userID string, goalIDs []string) error
+
 	// Commit commits the transaction.
 	Commit() error
 
@@ -0,0 +1,106 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/AccelByte/extend-challenge-common/pkg/domain"
+)
+
+// BenchmarkIncrementProgress_SameRowContention fires N concurrent single-row increments
+// at the same (user, goal) per iteration, the pattern of a popular goal receiving a burst
+// of events, and compares it with coalescing the burst into one batch apply.
+// Reports increments/sec and the error rate (lock timeouts, deadlocks, pool exhaustion).
+func BenchmarkIncrementProgress_SameRowContention(b *testing.B) {
+	db := setupM3BenchDB(b)
+	if db == nil {
+		return
+	}
+	defer cleanupM3BenchDB(b, db)
+
+	repo := NewPostgresGoalRepository(db)
+	ctx := context.Background()
+
+	concurrencyLevels := []int{1, 8, 32, 128}
+
+	for _, n := range concurrencyLevels {
+		userID := fmt.Sprintf("contention-user-%d", n)
+		now := time.Now()
+		err := repo.BulkInsertWithCOPY(ctx, []*domain.UserGoalProgress{{
+			UserID:      userID,
+			GoalID:      "hot-goal",
+			ChallengeID: "contention-challenge",
+			Namespace:   "test",
+			Status:      domain.GoalStatusInProgress,
+			IsActive:    true,
+			AssignedAt:  &now,
+		}})
+		if err != nil {
+			b.Fatalf("Setup failed: %v", err)
+		}
+
+		b.Run(fmt.Sprintf("Concurrent%d", n), func(b *testing.B) {
+			var failures atomic.Int64
+
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				var wg sync.WaitGroup
+				for j := 0; j < n; j++ {
+					wg.Add(1)
+					go func() {
+						defer wg.Done()
+						err := repo.IncrementProgress(ctx, userID, "hot-goal", "contention-challenge", "test", 1, MaxProgress, false)
+						if err != nil {
+							failures.Add(1)
+						}
+					}()
+				}
+				wg.Wait()
+			}
+			b.StopTimer()
+
+			reportContentionMetrics(b, n, failures.Load())
+		})
+
+		b.Run(fmt.Sprintf("Coalesced%d", n), func(b *testing.B) {
+			burst := make([]ProgressIncrement, n)
+			for j := range burst {
+				burst[j] = ProgressIncrement{
+					UserID:      userID,
+					GoalID:      "hot-goal",
+					ChallengeID: "contention-challenge",
+					Namespace:   "test",
+					Delta:       1,
+					TargetValue: MaxProgress,
+				}
+			}
+			var failures int64
+
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				if err := repo.BatchIncrementProgress(ctx, CoalesceIncrements(burst)); err != nil {
+					failures += int64(n)
+				}
+			}
+			b.StopTimer()
+
+			reportContentionMetrics(b, n, failures)
+		})
+	}
+}
+
+// reportContentionMetrics reports throughput and error rate for perOp increments per iteration.
+func reportContentionMetrics(b *testing.B, perOp int, failures int64) {
+	b.Helper()
+
+	total := float64(perOp) * float64(b.N)
+	nsPerOp := float64(b.Elapsed().Nanoseconds()) / float64(b.N)
+
+	b.ReportMetric(nsPerOp/1000000, "ms/op")
+	b.ReportMetric(float64(perOp)*1000000000/nsPerOp, "increments/sec")
+	b.ReportMetric(float64(failures)/total*100, "error%")
+}
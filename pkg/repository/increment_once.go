@@ -0,0 +1,215 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/AccelByte/extend-challenge-common/pkg/errors"
+
+	"github.com/lib/pq"
+)
+
+// maxEventIDLength matches the processed_events.event_id column width.
+const maxEventIDLength = 255
+
+// recordProcessedEventQuery claims an event ID (migration 010). No row is inserted when
+// the ID was already recorded, which marks the event as a replay.
+const recordProcessedEventQuery = `
+	INSERT INTO processed_events (event_id, user_id, goal_id)
+	VALUES ($1, $2, $3)
+	ON CONFLICT (event_id) DO NOTHING
+`
+
+// incrementProgressOnce records eventID and applies inc in one transaction, skipping the
+// increment when eventID was recorded before. inc goes through the same preparation and
+// statement as a one-entry BatchIncrementProgress.
+func (e executor) incrementProgressOnce(ctx context.Context, eventID string, inc ProgressIncrement) (bool, error) {
+	if err := validateEventID(eventID); err != nil {
+		return false, err
+	}
+
+	operation := e.op("increment progress once")
+	increments, _, err := e.repo.prepareIncrements(operation, []ProgressIncrement{inc})
+	if err != nil {
+		return false, err
+	}
+	if len(increments) == 0 {
+		return false, nil
+	}
+
+	release, err := e.admitBatch(ctx, "increment progress once", 1, func(int) string { return inc.Namespace })
+	if err != nil {
+		return false, err
+	}
+	defer release()
+
+	query, buildArgs := e.batchIncrementStatement(false)
+	applied := false
+	err = e.withTx(ctx, "increment progress once", func(tx *sql.Tx) error {
+		if err := e.repo.lockUsers(ctx, tx, []string{inc.UserID}); err != nil {
+			return errors.ErrDatabaseError(e.op("lock user for increment once"), err)
+		}
+
+		result, err := tx.ExecContext(ctx, recordProcessedEventQuery, eventID, inc.UserID, inc.GoalID)
+		if err != nil {
+			return errors.ErrDatabaseError(e.op("record processed event"), err)
+		}
+		recorded, err := result.RowsAffected()
+		if err != nil {
+			return errors.ErrDatabaseError(e.op("record processed event"), err)
+		}
+		if recorded == 0 {
+			return nil // Replay: already applied
+		}
+
+		skipped, err := e.execBatchIncrement(ctx, tx, operation, query, buildArgs(increments))
+		if err != nil {
+			return err
+		}
+		applied = len(skipped) == 0
+		return nil
+	})
+	if err != nil {
+		return false, err
+	}
+
+	return applied, nil
+}
+
+// EventIncrement is a ProgressIncrement tagged with the ID of the event that produced it.
+// Used by BatchIncrementProgressOnce.
+type EventIncrement struct {
+	EventID string
+	ProgressIncrement
+}
+
+// recordProcessedEventsQuery claims a batch of event IDs and returns the ones that were
+// not recorded before. IDs repeated within the batch are returned once.
+const recordProcessedEventsQuery = `
+	INSERT INTO processed_events (event_id, user_id, goal_id)
+	SELECT e.event_id, e.user_id, e.goal_id
+	FROM UNNEST($1::VARCHAR(255)[], $2::VARCHAR(100)[], $3::VARCHAR(100)[]) AS e(event_id, user_id, goal_id)
+	ON CONFLICT (event_id) DO NOTHING
+	RETURNING event_id
+`
+
+// validateEventID rejects event IDs that cannot be recorded.
+func validateEventID(eventID string) error {
+	if eventID == "" {
+		return errors.ErrValidationFailed("event_id", "cannot be empty")
+	}
+	if len(eventID) > maxEventIDLength {
+		return errors.ErrValidationFailed("event_id", "longer than 255 characters")
+	}
+	return nil
+}
+
+// batchIncrementProgressOnce records the batch's event IDs and applies the increments of
+// the newly recorded ones in one transaction. Increments for the same goal are merged
+// (see CoalesceIncrements) so a batch statement does not drop any of them.
+func (e executor) batchIncrementProgressOnce(ctx context.Context, events []EventIncrement) (int, error) {
+	if len(events) == 0 {
+		return 0, nil
+	}
+
+	eventIDs := make([]string, len(events))
+	userIDs := make([]string, len(events))
+	goalIDs := make([]string, len(events))
+	for i, ev := range events {
+		if err := validateEventID(ev.EventID); err != nil {
+			return 0, err
+		}
+		eventIDs[i] = ev.EventID
+		userIDs[i] = ev.UserID
+		goalIDs[i] = ev.GoalID
+	}
+
+	release, err := e.admitBatch(ctx, "batch increment progress once", len(events), func(i int) string { return events[i].Namespace })
+	if err != nil {
+		return 0, err
+	}
+	defer release()
+
+	operation := e.op("batch increment progress once")
+	query, buildArgs := e.batchIncrementStatement(false)
+	applied := 0
+	err = e.withTx(ctx, "batch increment progress once", func(tx *sql.Tx) error {
+		if err := e.repo.lockUsers(ctx, tx, userIDs); err != nil {
+			return errors.ErrDatabaseError(e.op("lock users for batch increment once"), err)
+		}
+
+		rows, err := tx.QueryContext(ctx, recordProcessedEventsQuery, pq.Array(eventIDs), pq.Array(userIDs), pq.Array(goalIDs))
+		if err != nil {
+			return errors.ErrDatabaseError(e.op("record processed events"), err)
+		}
+		recorded := make(map[string]bool, len(events))
+		for rows.Next() {
+			var eventID string
+			if err := rows.Scan(&eventID); err != nil {
+				_ = rows.Close()
+				return errors.ErrDatabaseError(e.op("scan processed event"), err)
+			}
+			recorded[eventID] = true
+		}
+		_ = rows.Close()
+		if err := rows.Err(); err != nil {
+			return errors.ErrDatabaseError(e.op("iterate processed events"), err)
+		}
+
+		// First occurrence of each newly recorded ID; replays and in-batch repeats are skipped
+		increments := make([]ProgressIncrement, 0, len(recorded))
+		for _, ev := range events {
+			if recorded[ev.EventID] {
+				increments = append(increments, ev.ProgressIncrement)
+				delete(recorded, ev.EventID)
+			}
+		}
+
+		increments, _, err = e.repo.prepareIncrements(operation, increments)
+		if err != nil || len(increments) == 0 {
+			return err
+		}
+
+		skipped, err := e.execBatchIncrement(ctx, tx, operation, query, buildArgs(CoalesceIncrements(increments)))
+		if err != nil {
+			return err
+		}
+		overCeiling := make(map[[2]string]bool, len(skipped))
+		for _, s := range skipped {
+			overCeiling[[2]string{s.userID, s.goalID}] = true
+		}
+
+		// Merged entries share the fate of their goal's statement row
+		for _, inc := range increments {
+			if !overCeiling[[2]string{inc.UserID, inc.GoalID}] {
+				applied++
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	return applied, nil
+}
+
+// IncrementProgressOnce applies inc unless eventID was already processed.
+func (r *PostgresGoalRepository) IncrementProgressOnce(ctx context.Context, eventID string, inc ProgressIncrement) (bool, error) {
+	return r.exec().incrementProgressOnce(ctx, eventID, inc)
+}
+
+// IncrementProgressOnce applies inc within a transaction unless eventID was already processed.
+func (r *PostgresTxRepository) IncrementProgressOnce(ctx context.Context, eventID string, inc ProgressIncrement) (bool, error) {
+	return r.exec().incrementProgressOnce(ctx, eventID, inc)
+}
+
+// BatchIncrementProgressOnce applies the increments of events not processed before.
+func (r *PostgresGoalRepository) BatchIncrementProgressOnce(ctx context.Context, events []EventIncrement) (int, error) {
+	return r.exec().batchIncrementProgressOnce(ctx, events)
+}
+
+// BatchIncrementProgressOnce applies the increments of unprocessed events within a transaction.
+func (r *PostgresTxRepository) BatchIncrementProgressOnce(ctx context.Context, events []EventIncrement) (int, error) {
+	return r.exec().batchIncrementProgressOnce(ctx, events)
+}
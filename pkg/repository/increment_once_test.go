@@ -0,0 +1,261 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/AccelByte/extend-challenge-common/pkg/domain"
+	customerrors "github.com/AccelByte/extend-challenge-common/pkg/errors"
+)
+
+// installProcessedEvents applies the optional processed events migration and returns a
+// cleanup that removes it again.
+func installProcessedEvents(t *testing.T, db *sql.DB) func() {
+	t.Helper()
+
+	migration, err := os.ReadFile("../../migrations/010_create_processed_events.up.sql")
+	if err != nil {
+		t.Fatalf("Failed to read processed events migration: %v", err)
+	}
+
+	if _, err := db.Exec(string(migration)); err != nil {
+		t.Fatalf("Failed to apply processed events migration: %v", err)
+	}
+
+	return func() {
+		_, _ = db.Exec("DROP TABLE IF EXISTS processed_events")
+	}
+}
+
+func TestIncrementProgressOnce_Validation(t *testing.T) {
+	repo := NewPostgresGoalRepository(nil)
+	inc := ProgressIncrement{UserID: "u1", GoalID: "g1", Delta: 1, TargetValue: 10}
+
+	for _, eventID := range []string{"", strings.Repeat("e", maxEventIDLength+1)} {
+		_, err := repo.IncrementProgressOnce(context.Background(), eventID, inc)
+		var ce *customerrors.ChallengeError
+		if !errors.As(err, &ce) || ce.Code != customerrors.ErrCodeValidationFailed {
+			t.Errorf("event ID of length %d: expected ErrCodeValidationFailed, got %v", len(eventID), err)
+		}
+	}
+}
+
+func TestPostgresGoalRepository_IncrementProgressOnce(t *testing.T) {
+	db := setupTestDB(t)
+	if db == nil {
+		return
+	}
+	defer cleanupTestDB(t, db)
+	defer installProcessedEvents(t, db)()
+
+	ctx := context.Background()
+	repo := NewPostgresGoalRepository(db)
+
+	err := repo.BulkInsert(ctx, []*domain.UserGoalProgress{
+		{UserID: "once-user", GoalID: "once-goal", ChallengeID: "c1", Namespace: "test", Status: domain.GoalStatusNotStarted, IsActive: true},
+	})
+	if err != nil {
+		t.Fatalf("BulkInsert failed: %v", err)
+	}
+
+	inc := ProgressIncrement{UserID: "once-user", GoalID: "once-goal", ChallengeID: "c1", Namespace: "test", Delta: 2, TargetValue: 10}
+
+	assertProgress := func(want int64) {
+		t.Helper()
+		progress, err := repo.GetProgress(ctx, "once-user", "once-goal")
+		if err != nil {
+			t.Fatalf("GetProgress failed: %v", err)
+		}
+		if progress.Progress != want {
+			t.Errorf("Progress = %d, want %d", progress.Progress, want)
+		}
+	}
+
+	t.Run("first delivery applies", func(t *testing.T) {
+		applied, err := repo.IncrementProgressOnce(ctx, "evt-1", inc)
+		if err != nil {
+			t.Fatalf("IncrementProgressOnce failed: %v", err)
+		}
+		if !applied {
+			t.Error("applied = false, want true")
+		}
+		assertProgress(2)
+	})
+
+	t.Run("replay is skipped", func(t *testing.T) {
+		applied, err := repo.IncrementProgressOnce(ctx, "evt-1", inc)
+		if err != nil {
+			t.Fatalf("IncrementProgressOnce failed: %v", err)
+		}
+		if applied {
+			t.Error("applied = true for a replayed event, want false")
+		}
+		assertProgress(2)
+	})
+
+	t.Run("new event applies", func(t *testing.T) {
+		applied, err := repo.IncrementProgressOnce(ctx, "evt-2", inc)
+		if err != nil || !applied {
+			t.Fatalf("IncrementProgressOnce() = %v, %v; want true, nil", applied, err)
+		}
+		assertProgress(4)
+	})
+
+	t.Run("rolled back transaction forgets the event", func(t *testing.T) {
+		tx, err := repo.BeginTx(ctx)
+		if err != nil {
+			t.Fatalf("BeginTx failed: %v", err)
+		}
+		applied, err := tx.IncrementProgressOnce(ctx, "evt-3", inc)
+		if err != nil || !applied {
+			t.Fatalf("IncrementProgressOnce in transaction = %v, %v; want true, nil", applied, err)
+		}
+		if applied, err := tx.IncrementProgressOnce(ctx, "evt-3", inc); err != nil || applied {
+			t.Errorf("replay in transaction = %v, %v; want false, nil", applied, err)
+		}
+		if err := tx.Rollback(); err != nil {
+			t.Fatalf("Rollback failed: %v", err)
+		}
+		assertProgress(4)
+
+		applied, err = repo.IncrementProgressOnce(ctx, "evt-3", inc)
+		if err != nil || !applied {
+			t.Fatalf("IncrementProgressOnce after rollback = %v, %v; want true, nil", applied, err)
+		}
+		assertProgress(6)
+	})
+
+	t.Run("failed increment does not record the event", func(t *testing.T) {
+		invalid := inc
+		invalid.TargetValue = 0
+		if _, err := repo.IncrementProgressOnce(ctx, "evt-4", invalid); err == nil {
+			t.Fatal("Expected error for invalid increment")
+		}
+
+		applied, err := repo.IncrementProgressOnce(ctx, "evt-4", inc)
+		if err != nil || !applied {
+			t.Fatalf("IncrementProgressOnce after failure = %v, %v; want true, nil", applied, err)
+		}
+		assertProgress(8)
+	})
+}
+
+func TestBatchIncrementProgressOnce_Validation(t *testing.T) {
+	repo := NewPostgresGoalRepository(nil)
+	ctx := context.Background()
+
+	n, err := repo.BatchIncrementProgressOnce(ctx, nil)
+	if err != nil || n != 0 {
+		t.Errorf("BatchIncrementProgressOnce(nil) = %d, %v; want 0, nil", n, err)
+	}
+
+	_, err = repo.BatchIncrementProgressOnce(ctx, []EventIncrement{
+		{EventID: "evt-1", ProgressIncrement: ProgressIncrement{UserID: "u1", GoalID: "g1", Delta: 1, TargetValue: 10}},
+		{EventID: "", ProgressIncrement: ProgressIncrement{UserID: "u1", GoalID: "g1", Delta: 1, TargetValue: 10}},
+	})
+	var ce *customerrors.ChallengeError
+	if !errors.As(err, &ce) || ce.Code != customerrors.ErrCodeValidationFailed {
+		t.Errorf("Expected ErrCodeValidationFailed for empty event ID, got %v", err)
+	}
+}
+
+func TestPostgresGoalRepository_BatchIncrementProgressOnce(t *testing.T) {
+	db := setupTestDB(t)
+	if db == nil {
+		return
+	}
+	defer cleanupTestDB(t, db)
+	defer installProcessedEvents(t, db)()
+
+	ctx := context.Background()
+	repo := NewPostgresGoalRepository(db)
+
+	err := repo.BulkInsert(ctx, []*domain.UserGoalProgress{
+		{UserID: "batch-once", GoalID: "g1", ChallengeID: "c1", Namespace: "test", Status: domain.GoalStatusNotStarted, IsActive: true},
+		{UserID: "batch-once", GoalID: "g2", ChallengeID: "c1", Namespace: "test", Status: domain.GoalStatusNotStarted, IsActive: true},
+	})
+	if err != nil {
+		t.Fatalf("BulkInsert failed: %v", err)
+	}
+
+	event := func(eventID, goalID string, delta int) EventIncrement {
+		return EventIncrement{EventID: eventID, ProgressIncrement: ProgressIncrement{
+			UserID: "batch-once", GoalID: goalID, ChallengeID: "c1", Namespace: "test", Delta: delta, TargetValue: 100,
+		}}
+	}
+	assertProgress := func(goalID string, want int64) {
+		t.Helper()
+		progress, err := repo.GetProgress(ctx, "batch-once", goalID)
+		if err != nil {
+			t.Fatalf("GetProgress failed: %v", err)
+		}
+		if progress.Progress != want {
+			t.Errorf("%s Progress = %d, want %d", goalID, progress.Progress, want)
+		}
+	}
+
+	// Two events for g1 in one batch are both applied; evt-a repeated in the batch counts once
+	applied, err := repo.BatchIncrementProgressOnce(ctx, []EventIncrement{
+		event("evt-a", "g1", 1),
+		event("evt-b", "g1", 2),
+		event("evt-c", "g2", 5),
+		event("evt-a", "g1", 1),
+	})
+	if err != nil {
+		t.Fatalf("BatchIncrementProgressOnce failed: %v", err)
+	}
+	if applied != 3 {
+		t.Errorf("applied = %d, want 3", applied)
+	}
+	assertProgress("g1", 3)
+	assertProgress("g2", 5)
+
+	// Replayed flush overlapping the first: only evt-d is new
+	applied, err = repo.BatchIncrementProgressOnce(ctx, []EventIncrement{
+		event("evt-b", "g1", 2),
+		event("evt-c", "g2", 5),
+		event("evt-d", "g2", 10),
+	})
+	if err != nil {
+		t.Fatalf("BatchIncrementProgressOnce replay failed: %v", err)
+	}
+	if applied != 1 {
+		t.Errorf("applied = %d, want 1", applied)
+	}
+	assertProgress("g1", 3)
+	assertProgress("g2", 15)
+
+	// Full replay applies nothing
+	applied, err = repo.BatchIncrementProgressOnce(ctx, []EventIncrement{event("evt-a", "g1", 1), event("evt-d", "g2", 10)})
+	if err != nil || applied != 0 {
+		t.Errorf("full replay = %d, %v; want 0, nil", applied, err)
+	}
+
+	// Events recorded by IncrementProgressOnce are replays for the batch form too
+	if _, err := repo.IncrementProgressOnce(ctx, "evt-e", event("evt-e", "g1", 4).ProgressIncrement); err != nil {
+		t.Fatalf("IncrementProgressOnce failed: %v", err)
+	}
+	applied, err = repo.BatchIncrementProgressOnce(ctx, []EventIncrement{event("evt-e", "g1", 4)})
+	if err != nil || applied != 0 {
+		t.Errorf("replay of single event = %d, %v; want 0, nil", applied, err)
+	}
+	assertProgress("g1", 7)
+
+	t.Run("failed batch records nothing", func(t *testing.T) {
+		invalid := event("evt-f", "g1", 1)
+		invalid.TargetValue = 0
+		if _, err := repo.BatchIncrementProgressOnce(ctx, []EventIncrement{event("evt-g", "g2", 1), invalid}); err == nil {
+			t.Fatal("Expected error for invalid increment")
+		}
+
+		applied, err := repo.BatchIncrementProgressOnce(ctx, []EventIncrement{event("evt-g", "g2", 1)})
+		if err != nil || applied != 1 {
+			t.Errorf("retry after failure = %d, %v; want 1, nil", applied, err)
+		}
+		assertProgress("g2", 16)
+	})
+}
@@ -0,0 +1,164 @@
+package repository
+
+import (
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/AccelByte/extend-challenge-common/pkg/errors"
+)
+
+const (
+	// DefaultMaxIncrementDelta is the default cap on the magnitude of a single increment delta.
+	DefaultMaxIncrementDelta = 1_000_000
+
+	// MaxProgress is the highest value an INT progress column can hold (the default schema).
+	// Increments that would overflow are clamped to this value in SQL.
+	MaxProgress = math.MaxInt32
+
+	// MaxBigIntProgress is the clamp used for a BIGINT progress column (see
+	// WithBigIntProgress). It stays MaxInt32 below the BIGINT limit so progress + delta in
+	// the increment SQL cannot overflow before the clamp applies.
+	MaxBigIntProgress = math.MaxInt64 - math.MaxInt32
+
+	// DefaultMaxEventLateness is the default age after which an increment's OccurredAt
+	// is considered too late to apply.
+	DefaultMaxEventLateness = 48 * time.Hour
+)
+
+// incrementViolation returns a description of why an increment is invalid, or "" if it is valid.
+func (r *PostgresGoalRepository) incrementViolation(userID, goalID string, delta, targetValue int) string {
+	if targetValue <= 0 {
+		return fmt.Sprintf("%s/%s: target value must be positive (got %d)", userID, goalID, targetValue)
+	}
+	if delta > r.maxIncrementDelta || delta < -r.maxIncrementDelta {
+		return fmt.Sprintf("%s/%s: delta %d exceeds cap of %d", userID, goalID, delta, r.maxIncrementDelta)
+	}
+	return ""
+}
+
+// validateIncrement checks a single increment before any SQL runs.
+func (r *PostgresGoalRepository) validateIncrement(userID, goalID string, delta, targetValue int) error {
+	if violation := r.incrementViolation(userID, goalID, delta, targetValue); violation != "" {
+		return errors.ErrInvalidIncrement([]string{violation})
+	}
+	return nil
+}
+
+// capDelta clamps a positive delta to maxDeltaPerEvent. A cap <= 0 means unlimited.
+func capDelta(delta, maxDeltaPerEvent int) int {
+	if maxDeltaPerEvent > 0 && delta > maxDeltaPerEvent {
+		return maxDeltaPerEvent
+	}
+	return delta
+}
+
+// capIncrements applies per-event delta caps to a batch. Each entry uses its own
+// MaxDeltaPerEvent, falling back to the repository default. The caller's slice is
+// not modified; a copy is returned if any delta changes.
+func (r *PostgresGoalRepository) capIncrements(increments []ProgressIncrement) []ProgressIncrement {
+	var capped []ProgressIncrement
+
+	for i, inc := range increments {
+		maxDelta := inc.MaxDeltaPerEvent
+		if maxDelta <= 0 {
+			maxDelta = r.maxDeltaPerEvent
+		}
+
+		delta := capDelta(inc.Delta, maxDelta)
+		if delta == inc.Delta {
+			continue
+		}
+		if capped == nil {
+			capped = append([]ProgressIncrement(nil), increments...)
+		}
+		capped[i].Delta = delta
+	}
+
+	if capped == nil {
+		return increments
+	}
+	return capped
+}
+
+// filterIncrements caps and validates a batch before any SQL runs.
+// In strict mode any invalid entry rejects the batch; in lenient mode invalid entries
+// are dropped and the remaining entries are returned.
+func (r *PostgresGoalRepository) filterIncrements(increments []ProgressIncrement) ([]ProgressIncrement, error) {
+	increments = r.capIncrements(increments)
+
+	var violations []string
+	valid := increments[:0:0]
+
+	for _, inc := range increments {
+		if violation := r.incrementViolation(inc.UserID, inc.GoalID, inc.Delta, inc.TargetValue); violation != "" {
+			violations = append(violations, violation)
+			continue
+		}
+		if inc.AttemptDelta < 0 {
+			violations = append(violations, fmt.Sprintf("%s/%s: attempt delta must not be negative (got %d)", inc.UserID, inc.GoalID, inc.AttemptDelta))
+			continue
+		}
+		valid = append(valid, inc)
+	}
+
+	if len(violations) == 0 {
+		return increments, nil
+	}
+	if r.strictIncrementValidation {
+		return nil, errors.ErrInvalidIncrement(violations)
+	}
+	return valid, nil
+}
+
+// dropLateIncrements removes entries whose OccurredAt is older than the max lateness
+// relative to now, returning the remaining entries and the number skipped.
+// Entries without OccurredAt are never late.
+func (r *PostgresGoalRepository) dropLateIncrements(increments []ProgressIncrement, now time.Time) ([]ProgressIncrement, int) {
+	cutoff := now.Add(-r.maxEventLateness)
+
+	var kept []ProgressIncrement
+	skipped := 0
+	for i, inc := range increments {
+		if inc.OccurredAt == nil || !inc.OccurredAt.Before(cutoff) {
+			if kept != nil {
+				kept = append(kept, inc)
+			}
+			continue
+		}
+		if kept == nil {
+			kept = append(make([]ProgressIncrement, 0, len(increments)-1), increments[:i]...)
+		}
+		skipped++
+	}
+
+	if kept == nil {
+		return increments, 0
+	}
+	return kept, skipped
+}
+
+// prepareIncrements runs the pre-SQL steps shared by the batch increment methods:
+// caps and validation (filterIncrements), dropping entries whose delta alone exceeds the
+// progress ceiling, then dropping late entries. Skipped entries are logged so consumer
+// lag shows up in operations.
+func (r *PostgresGoalRepository) prepareIncrements(operation string, increments []ProgressIncrement) ([]ProgressIncrement, int, error) {
+	increments, err := r.filterIncrements(increments)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	increments, overCeiling := r.dropCeilingOverflows(increments)
+	r.logCeilingSkips(operation, overCeiling)
+
+	increments, skipped := r.dropLateIncrements(increments, time.Now())
+	if skipped > 0 {
+		r.logger.Warn("Skipped late increments",
+			"operation", operation,
+			"skipped", skipped,
+			"max_lateness", r.maxEventLateness,
+		)
+	}
+
+	return increments, skipped, nil
+}
@@ -0,0 +1,501 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/AccelByte/extend-challenge-common/pkg/domain"
+	customerrors "github.com/AccelByte/extend-challenge-common/pkg/errors"
+)
+
+// assertInvalidIncrement fails the test unless err is an INVALID_INCREMENT error.
+func assertInvalidIncrement(t *testing.T, err error) *customerrors.ChallengeError {
+	t.Helper()
+
+	var ce *customerrors.ChallengeError
+	if !errors.As(err, &ce) || ce.Code != customerrors.ErrCodeInvalidIncrement {
+		t.Fatalf("Expected INVALID_INCREMENT error, got %v", err)
+	}
+	return ce
+}
+
+func TestIncrementProgress_RejectedBeforeSQL(t *testing.T) {
+	// nil *sql.DB: any SQL would panic, so passing proves validation runs first
+	repo := NewPostgresGoalRepository(nil)
+	ctx := context.Background()
+
+	tests := []struct {
+		name        string
+		delta       int
+		targetValue int
+	}{
+		{"zero target value", 1, 0},
+		{"negative target value", 1, -5},
+		{"huge delta", DefaultMaxIncrementDelta + 1, 10},
+		{"huge negative delta", -DefaultMaxIncrementDelta - 1, 10},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := repo.IncrementProgress(ctx, "user1", "goal1", "challenge1", "test", tt.delta, tt.targetValue, false)
+			ce := assertInvalidIncrement(t, err)
+			if !strings.Contains(ce.Message, "user1/goal1") {
+				t.Errorf("Message should identify the offending entry, got %v", ce.Message)
+			}
+		})
+	}
+}
+
+func TestWithMaxIncrementDelta(t *testing.T) {
+	repo := NewPostgresGoalRepository(nil, WithMaxIncrementDelta(50))
+
+	err := repo.IncrementProgress(context.Background(), "user1", "goal1", "challenge1", "test", 51, 100, false)
+	assertInvalidIncrement(t, err)
+
+	if violation := repo.incrementViolation("user1", "goal1", 50, 100); violation != "" {
+		t.Errorf("Delta at the cap should be accepted, got %q", violation)
+	}
+
+	// Non-positive caps fall back to the default
+	repo = NewPostgresGoalRepository(nil, WithMaxIncrementDelta(0))
+	if repo.maxIncrementDelta != DefaultMaxIncrementDelta {
+		t.Errorf("maxIncrementDelta = %d, want %d", repo.maxIncrementDelta, DefaultMaxIncrementDelta)
+	}
+}
+
+func TestFilterIncrements_StrictVsLenient(t *testing.T) {
+	increments := []ProgressIncrement{
+		{UserID: "user1", GoalID: "goal-ok", Delta: 1, TargetValue: 10},
+		{UserID: "user1", GoalID: "goal-zero-target", Delta: 1, TargetValue: 0},
+		{UserID: "user2", GoalID: "goal-huge-delta", Delta: 5_000_000, TargetValue: 10},
+		{UserID: "user2", GoalID: "goal-ok-2", Delta: 3, TargetValue: 3},
+	}
+
+	t.Run("strict rejects whole batch listing every offender", func(t *testing.T) {
+		repo := NewPostgresGoalRepository(nil)
+
+		valid, err := repo.filterIncrements(increments)
+		ce := assertInvalidIncrement(t, err)
+		if valid != nil {
+			t.Errorf("Expected no increments in strict mode, got %d", len(valid))
+		}
+		for _, offender := range []string{"user1/goal-zero-target", "user2/goal-huge-delta"} {
+			if !strings.Contains(ce.Message, offender) {
+				t.Errorf("Message should contain %q, got %v", offender, ce.Message)
+			}
+		}
+
+		err = repo.BatchIncrementProgress(context.Background(), increments)
+		assertInvalidIncrement(t, err)
+	})
+
+	t.Run("lenient skips invalid entries", func(t *testing.T) {
+		repo := NewPostgresGoalRepository(nil, WithStrictIncrementValidation(false))
+
+		valid, err := repo.filterIncrements(increments)
+		if err != nil {
+			t.Fatalf("Expected no error in lenient mode, got %v", err)
+		}
+		if len(valid) != 2 || valid[0].GoalID != "goal-ok" || valid[1].GoalID != "goal-ok-2" {
+			t.Errorf("Expected only valid increments, got %+v", valid)
+		}
+	})
+
+	t.Run("lenient with only invalid entries is a no-op", func(t *testing.T) {
+		repo := NewPostgresGoalRepository(nil, WithStrictIncrementValidation(false))
+
+		results, err := repo.BatchIncrementProgressReturning(context.Background(), increments[1:3])
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if len(results) != 0 {
+			t.Errorf("Expected no results, got %d", len(results))
+		}
+	})
+
+	t.Run("valid batch is returned unchanged", func(t *testing.T) {
+		repo := NewPostgresGoalRepository(nil)
+
+		valid, err := repo.filterIncrements([]ProgressIncrement{increments[0], increments[3]})
+		if err != nil || len(valid) != 2 {
+			t.Errorf("Expected both increments to pass, got %d, err=%v", len(valid), err)
+		}
+	})
+}
+
+func TestCapIncrements(t *testing.T) {
+	tests := []struct {
+		name      string
+		repoCap   int
+		inc       ProgressIncrement
+		wantDelta int
+	}{
+		{"above entry cap is clamped", 0, ProgressIncrement{Delta: 50, MaxDeltaPerEvent: 10}, 10},
+		{"below entry cap is unchanged", 0, ProgressIncrement{Delta: 5, MaxDeltaPerEvent: 10}, 5},
+		{"at entry cap is unchanged", 0, ProgressIncrement{Delta: 10, MaxDeltaPerEvent: 10}, 10},
+		{"zero cap is unlimited", 0, ProgressIncrement{Delta: 500}, 500},
+		{"negative delta is not clamped", 0, ProgressIncrement{Delta: -50, MaxDeltaPerEvent: 10}, -50},
+		{"repository default applies", 20, ProgressIncrement{Delta: 50}, 20},
+		{"entry cap overrides repository default", 20, ProgressIncrement{Delta: 50, MaxDeltaPerEvent: 30}, 30},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := NewPostgresGoalRepository(nil, WithMaxDeltaPerEvent(tt.repoCap))
+
+			increments := []ProgressIncrement{tt.inc}
+			capped := repo.capIncrements(increments)
+			if capped[0].Delta != tt.wantDelta {
+				t.Errorf("Delta = %d, want %d", capped[0].Delta, tt.wantDelta)
+			}
+			if increments[0].Delta != tt.inc.Delta {
+				t.Error("capIncrements should not modify the caller's slice")
+			}
+		})
+	}
+}
+
+func TestFilterIncrements_CapsBeforeValidation(t *testing.T) {
+	repo := NewPostgresGoalRepository(nil)
+
+	// An inflated delta is contained by the per-event cap instead of rejecting the batch
+	valid, err := repo.filterIncrements([]ProgressIncrement{
+		{UserID: "user1", GoalID: "goal1", Delta: DefaultMaxIncrementDelta * 2, TargetValue: 10, MaxDeltaPerEvent: 5},
+	})
+	if err != nil {
+		t.Fatalf("Expected capped increment to pass validation, got %v", err)
+	}
+	if len(valid) != 1 || valid[0].Delta != 5 {
+		t.Errorf("Expected one increment with delta 5, got %+v", valid)
+	}
+}
+
+func TestPostgresGoalRepository_IncrementProgressMaxDeltaPerEvent(t *testing.T) {
+	db := setupTestDB(t)
+	if db == nil {
+		return
+	}
+	defer cleanupTestDB(t, db)
+
+	repo := NewPostgresGoalRepository(db, WithMaxDeltaPerEvent(10))
+	ctx := context.Background()
+
+	// Single increment: above the repository cap is clamped
+	if err := repo.IncrementProgress(ctx, "cap-user", "goal-single", "challenge1", "test", 50, 100, false); err != nil {
+		t.Fatalf("IncrementProgress failed: %v", err)
+	}
+
+	err := repo.BatchIncrementProgress(ctx, []ProgressIncrement{
+		{UserID: "cap-user", GoalID: "goal-above", ChallengeID: "challenge1", Namespace: "test", Delta: 50, TargetValue: 100, MaxDeltaPerEvent: 5},
+		{UserID: "cap-user", GoalID: "goal-below", ChallengeID: "challenge1", Namespace: "test", Delta: 3, TargetValue: 100, MaxDeltaPerEvent: 5},
+	})
+	if err != nil {
+		t.Fatalf("BatchIncrementProgress failed: %v", err)
+	}
+
+	want := map[string]int64{"goal-single": 10, "goal-above": 5, "goal-below": 3}
+	for goalID, progress := range want {
+		p, err := repo.GetProgress(ctx, "cap-user", goalID)
+		if err != nil || p == nil {
+			t.Fatalf("GetProgress(%s) failed: %v", goalID, err)
+		}
+		if p.Progress != progress {
+			t.Errorf("%s: progress = %d, want %d", goalID, p.Progress, progress)
+		}
+	}
+}
+
+// assertProgressOverflow fails the test unless err is a PROGRESS_OVERFLOW error naming goalID.
+func assertProgressOverflow(t *testing.T, err error, goalID string) {
+	t.Helper()
+
+	var ce *customerrors.ChallengeError
+	if !errors.As(err, &ce) || ce.Code != customerrors.ErrCodeProgressOverflow {
+		t.Fatalf("Expected PROGRESS_OVERFLOW error, got %v", err)
+	}
+	if !strings.Contains(ce.Message, goalID) {
+		t.Errorf("Message should name %s, got %v", goalID, ce.Message)
+	}
+}
+
+func TestWithProgressCeiling(t *testing.T) {
+	tests := []struct {
+		name    string
+		ceiling int64
+		want    int64
+	}{
+		{"custom ceiling", 1000, 1000},
+		{"zero falls back to default", 0, DefaultProgressCeiling},
+		{"negative falls back to default", -1, DefaultProgressCeiling},
+		{"max progress is allowed", MaxProgress, MaxProgress},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := NewPostgresGoalRepository(nil, WithProgressCeiling(tt.ceiling))
+			if repo.progressCeiling != tt.want {
+				t.Errorf("progressCeiling = %d, want %d", repo.progressCeiling, tt.want)
+			}
+		})
+	}
+}
+
+func TestIncrementQueriesGuardProgressCeiling(t *testing.T) {
+	queries := map[string]string{
+		"incrementRegularQuery":         progressCeilingGuard("progress", "$3::BIGINT", "$6::BIGINT"),
+		"incrementDailyQuery":           progressCeilingGuard("progress", "$3::BIGINT", "$6::BIGINT"),
+		"txIncrementRegularQuery":       progressCeilingGuard("user_goal_progress.progress", "$5::BIGINT", "$8::BIGINT"),
+		"txIncrementDailyQuery":         progressCeilingGuard("user_goal_progress.progress", "$5::BIGINT", "$8::BIGINT"),
+		"batchIncrementProgressQuery":   progressCeilingGuard("user_goal_progress.progress", "t.delta", "$10::BIGINT"),
+		"txBatchIncrementProgressQuery": "> $12::BIGINT)",
+	}
+	sources := map[string]string{
+		"incrementRegularQuery":         incrementRegularQuery,
+		"incrementDailyQuery":           incrementDailyQuery,
+		"txIncrementRegularQuery":       txIncrementRegularQuery,
+		"txIncrementDailyQuery":         txIncrementDailyQuery,
+		"batchIncrementProgressQuery":   batchIncrementProgressQuery,
+		"txBatchIncrementProgressQuery": txBatchIncrementProgressQuery,
+	}
+
+	for name, guard := range queries {
+		if !strings.Contains(sources[name], guard) {
+			t.Errorf("%s does not guard the progress ceiling with %q", name, guard)
+		}
+	}
+}
+
+func TestPostgresGoalRepository_IncrementProgressCeiling(t *testing.T) {
+	db := setupTestDB(t)
+	if db == nil {
+		return
+	}
+	defer cleanupTestDB(t, db)
+
+	repo := NewPostgresGoalRepository(db)
+	ctx := context.Background()
+	nearCeiling := int64(DefaultProgressCeiling - 10)
+
+	err := repo.BulkInsert(ctx, []*domain.UserGoalProgress{
+		{UserID: "overflow-user", GoalID: "goal-pool", ChallengeID: "challenge1", Namespace: "test", Progress: nearCeiling, Status: domain.GoalStatusInProgress, IsActive: true},
+		{UserID: "overflow-user", GoalID: "goal-batch", ChallengeID: "challenge1", Namespace: "test", Progress: nearCeiling, Status: domain.GoalStatusInProgress, IsActive: true},
+		{UserID: "overflow-user", GoalID: "goal-tx", ChallengeID: "challenge1", Namespace: "test", Progress: nearCeiling, Status: domain.GoalStatusInProgress, IsActive: true},
+		{UserID: "overflow-user", GoalID: "goal-ok", ChallengeID: "challenge1", Namespace: "test", Progress: 0, Status: domain.GoalStatusInProgress, IsActive: true},
+	})
+	if err != nil {
+		t.Fatalf("BulkInsert failed: %v", err)
+	}
+
+	assertProgress := func(goalID string, want int64) {
+		t.Helper()
+		p, err := repo.GetProgress(ctx, "overflow-user", goalID)
+		if err != nil || p == nil {
+			t.Fatalf("GetProgress(%s) failed: %v", goalID, err)
+		}
+		if p.Progress != want {
+			t.Errorf("%s: progress = %d, want %d", goalID, p.Progress, want)
+		}
+	}
+
+	t.Run("beyond the ceiling is rejected", func(t *testing.T) {
+		err := repo.IncrementProgress(ctx, "overflow-user", "goal-pool", "challenge1", "test", 11, MaxProgress, false)
+		assertProgressOverflow(t, err, "goal-pool")
+
+		tx, err := repo.BeginTx(ctx)
+		if err != nil {
+			t.Fatalf("BeginTx failed: %v", err)
+		}
+		err = tx.IncrementProgress(ctx, "overflow-user", "goal-tx", "challenge1", "test", 100, MaxProgress, false)
+		_ = tx.Rollback()
+		assertProgressOverflow(t, err, "goal-tx")
+
+		for _, goalID := range []string{"goal-pool", "goal-tx"} {
+			assertProgress(goalID, nearCeiling)
+		}
+	})
+
+	t.Run("batch skips only the overflowing entry", func(t *testing.T) {
+		batch := []ProgressIncrement{
+			{UserID: "overflow-user", GoalID: "goal-ok", ChallengeID: "challenge1", Namespace: "test", Delta: 1, TargetValue: 10},
+			{UserID: "overflow-user", GoalID: "goal-batch", ChallengeID: "challenge1", Namespace: "test", Delta: 100, TargetValue: MaxProgress},
+		}
+
+		// Strict and lenient validation handle the ceiling alike
+		for i, r := range []*PostgresGoalRepository{repo, NewPostgresGoalRepository(db, WithStrictIncrementValidation(false))} {
+			if err := r.BatchIncrementProgress(ctx, batch); err != nil {
+				t.Fatalf("BatchIncrementProgress failed: %v", err)
+			}
+			assertProgress("goal-ok", int64(i+1))
+			assertProgress("goal-batch", nearCeiling)
+		}
+
+		tx, err := repo.BeginTx(ctx)
+		if err != nil {
+			t.Fatalf("BeginTx failed: %v", err)
+		}
+		defer func() { _ = tx.Rollback() }()
+
+		result, err := tx.BatchIncrementProgressWithResult(ctx, []ProgressIncrement{
+			{UserID: "overflow-user", GoalID: "goal-ok", ChallengeID: "challenge1", Namespace: "test", Delta: 8, TargetValue: 10},
+			{UserID: "overflow-user", GoalID: "goal-batch", ChallengeID: "challenge1", Namespace: "test", Delta: 100, TargetValue: 10},
+		})
+		if err != nil {
+			t.Fatalf("BatchIncrementProgressWithResult in transaction failed: %v", err)
+		}
+		if len(result.Completions) != 1 || result.Completions[0].GoalID != "goal-ok" || !result.Completions[0].NewlyCompleted {
+			t.Errorf("Expected only goal-ok newly completed, got %+v", result.Completions)
+		}
+	})
+
+	t.Run("up to the ceiling is applied", func(t *testing.T) {
+		if err := repo.IncrementProgress(ctx, "overflow-user", "goal-pool", "challenge1", "test", 10, DefaultProgressCeiling, false); err != nil {
+			t.Fatalf("IncrementProgress at ceiling failed: %v", err)
+		}
+
+		err := repo.BatchIncrementProgress(ctx, []ProgressIncrement{
+			{UserID: "overflow-user", GoalID: "goal-batch", ChallengeID: "challenge1", Namespace: "test", Delta: 10, TargetValue: DefaultProgressCeiling},
+		})
+		if err != nil {
+			t.Fatalf("BatchIncrementProgress at ceiling failed: %v", err)
+		}
+
+		tx, err := repo.BeginTx(ctx)
+		if err != nil {
+			t.Fatalf("BeginTx failed: %v", err)
+		}
+		if err := tx.IncrementProgress(ctx, "overflow-user", "goal-tx", "challenge1", "test", 10, DefaultProgressCeiling, false); err != nil {
+			_ = tx.Rollback()
+			t.Fatalf("tx IncrementProgress at ceiling failed: %v", err)
+		}
+		if err := tx.Commit(); err != nil {
+			t.Fatalf("Commit failed: %v", err)
+		}
+
+		for _, goalID := range []string{"goal-pool", "goal-batch", "goal-tx"} {
+			assertProgress(goalID, DefaultProgressCeiling)
+		}
+	})
+}
+
+func TestDropLateIncrements(t *testing.T) {
+	repo := NewPostgresGoalRepository(nil)
+	now := time.Now()
+	at := func(age time.Duration) *time.Time {
+		ts := now.Add(-age)
+		return &ts
+	}
+
+	increments := []ProgressIncrement{
+		{GoalID: "no-occurred-at"},
+		{GoalID: "yesterday", OccurredAt: at(24 * time.Hour)},
+		{GoalID: "five-days", OccurredAt: at(5 * 24 * time.Hour)},
+		{GoalID: "at-limit", OccurredAt: at(DefaultMaxEventLateness)},
+		{GoalID: "future", OccurredAt: at(-time.Hour)},
+	}
+
+	kept, skipped := repo.dropLateIncrements(increments, now)
+	if skipped != 1 {
+		t.Errorf("skipped = %d, want 1", skipped)
+	}
+	if len(kept) != 4 {
+		t.Fatalf("Expected 4 increments kept, got %+v", kept)
+	}
+	for _, inc := range kept {
+		if inc.GoalID == "five-days" {
+			t.Error("Expected the 5-day-old increment to be dropped")
+		}
+	}
+
+	t.Run("no late entries returns input unchanged", func(t *testing.T) {
+		kept, skipped := repo.dropLateIncrements(increments[:2], now)
+		if skipped != 0 || len(kept) != 2 {
+			t.Errorf("Expected 2 kept and 0 skipped, got %d kept, %d skipped", len(kept), skipped)
+		}
+	})
+
+	t.Run("custom lateness", func(t *testing.T) {
+		repo := NewPostgresGoalRepository(nil, WithMaxEventLateness(time.Hour))
+		_, skipped := repo.dropLateIncrements(increments, now)
+		if skipped != 3 {
+			t.Errorf("skipped = %d, want 3", skipped)
+		}
+	})
+}
+
+func TestPostgresGoalRepository_BatchIncrementLateDailyEvents(t *testing.T) {
+	db := setupTestDB(t)
+	if db == nil {
+		return
+	}
+	defer cleanupTestDB(t, db)
+
+	repo := NewPostgresGoalRepository(db)
+	ctx := context.Background()
+
+	// Last counted day: two days ago
+	twoDaysAgo := time.Now().UTC().Add(-48 * time.Hour)
+	_, err := db.ExecContext(ctx, `
+		INSERT INTO user_goal_progress (
+			user_id, goal_id, challenge_id, namespace,
+			progress, status, created_at, updated_at, is_active
+		) VALUES ('late-user', 'daily-login', 'challenge1', 'test', 2, 'in_progress', $1, $1, true)
+	`, twoDaysAgo)
+	if err != nil {
+		t.Fatalf("Setup insert failed: %v", err)
+	}
+
+	yesterday := time.Now().UTC().Add(-24 * time.Hour)
+	fiveDaysAgo := time.Now().UTC().Add(-5 * 24 * time.Hour)
+	increment := func(occurredAt *time.Time) BatchIncrementResult {
+		t.Helper()
+		result, err := repo.BatchIncrementProgressWithResult(ctx, []ProgressIncrement{{
+			UserID: "late-user", GoalID: "daily-login", ChallengeID: "challenge1", Namespace: "test",
+			Delta: 1, TargetValue: 10, IsDailyIncrement: true, OccurredAt: occurredAt,
+		}})
+		if err != nil {
+			t.Fatalf("BatchIncrementProgressWithResult failed: %v", err)
+		}
+		return result
+	}
+	assertProgress := func(want int64) {
+		t.Helper()
+		p, err := repo.GetProgress(ctx, "late-user", "daily-login")
+		if err != nil || p == nil {
+			t.Fatalf("GetProgress failed: %v", err)
+		}
+		if p.Progress != want {
+			t.Errorf("progress = %d, want %d", p.Progress, want)
+		}
+	}
+
+	t.Run("late event counts for yesterday", func(t *testing.T) {
+		increment(&yesterday)
+		assertProgress(3)
+
+		p, _ := repo.GetProgress(ctx, "late-user", "daily-login")
+		if time.Since(p.UpdatedAt) > time.Hour {
+			t.Errorf("Expected updated_at to record the write time, got %v", p.UpdatedAt)
+		}
+	})
+
+	t.Run("second event for yesterday is a no-op", func(t *testing.T) {
+		increment(&yesterday)
+		assertProgress(3)
+	})
+
+	t.Run("event older than max lateness is skipped", func(t *testing.T) {
+		result := increment(&fiveDaysAgo)
+		if result.SkippedLate != 1 {
+			t.Errorf("SkippedLate = %d, want 1", result.SkippedLate)
+		}
+		assertProgress(3)
+	})
+
+	t.Run("today still counts after a late event", func(t *testing.T) {
+		increment(nil)
+		assertProgress(4)
+	})
+}
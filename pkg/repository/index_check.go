@@ -0,0 +1,125 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/AccelByte/extend-challenge-common/pkg/errors"
+
+	"github.com/lib/pq"
+)
+
+// startupCheckTimeout bounds the index check run by WithStartupChecks.
+const startupCheckTimeout = 5 * time.Second
+
+// RequiredIndex describes an index the repository's queries rely on.
+type RequiredIndex struct {
+	Table string
+	Name  string
+}
+
+// MissingIndex is a required index that was not found in the database.
+type MissingIndex struct {
+	RequiredIndex
+
+	// Migration is the migration file that creates the index
+	Migration string
+}
+
+// requiredIndexes lists the indexes created by the migrations directory that queries
+// depend on. Keep it in sync when a migration adds an index; optional migrations
+// (e.g., 002 progress history) are not listed.
+var requiredIndexes = []struct {
+	RequiredIndex
+	migration string
+}{
+	{RequiredIndex{"user_goal_progress", "user_goal_progress_pkey"}, "001_create_user_goal_progress.up.sql"},
+	{RequiredIndex{"user_goal_progress", "idx_user_goal_progress_user_challenge"}, "001_create_user_goal_progress.up.sql"},
+	{RequiredIndex{"user_goal_progress", "idx_user_goal_progress_user_active"}, "001_create_user_goal_progress.up.sql"},
+	{RequiredIndex{"user_goal_progress", "idx_user_goal_count"}, "001_create_user_goal_progress.up.sql"},
+	{RequiredIndex{"user_goal_progress", "idx_user_goal_lookup"}, "001_create_user_goal_progress.up.sql"},
+	{RequiredIndex{"user_goal_progress", "idx_user_goal_active_only"}, "001_create_user_goal_progress.up.sql"},
+	{RequiredIndex{"reward_grants", "reward_grants_pkey"}, "003_create_reward_grants.up.sql"},
+	{RequiredIndex{"reward_grants", "idx_reward_grants_user_goal"}, "003_create_reward_grants.up.sql"},
+	{RequiredIndex{"user_goal_progress", "idx_user_goal_progress_challenge_participants"}, "005_add_challenge_participants_index.up.sql"},
+	{RequiredIndex{"user_goal_progress", "idx_user_goal_progress_claim_deadline"}, "008_add_forfeited_at.up.sql"},
+	{RequiredIndex{"user_goal_progress", "idx_user_goal_progress_feed"}, "011_add_progress_feed_index.up.sql"},
+	{RequiredIndex{"user_goal_progress", "idx_user_goal_progress_claim_reservation"}, "012_add_claim_reservation.up.sql"},
+	{RequiredIndex{"user_goal_progress", "idx_user_goal_progress_challenge_status"}, "015_add_challenge_status_index.up.sql"},
+	{RequiredIndex{"user_goal_progress", "idx_user_goal_progress_assigned"}, "016_add_assigned_at_index.up.sql"},
+}
+
+// RequiredIndexes returns the indexes checked by VerifyIndexes.
+func RequiredIndexes() []RequiredIndex {
+	indexes := make([]RequiredIndex, len(requiredIndexes))
+	for i, idx := range requiredIndexes {
+		indexes[i] = idx.RequiredIndex
+	}
+	return indexes
+}
+
+// VerifyIndexes reports which required indexes are missing from the database.
+// Only schemas on the search_path are considered. A nil slice means all indexes exist.
+func (r *PostgresGoalRepository) VerifyIndexes(ctx context.Context) ([]MissingIndex, error) {
+	tables := make([]string, 0, len(requiredIndexes))
+	for _, idx := range requiredIndexes {
+		tables = append(tables, idx.Table)
+	}
+
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT tablename, indexname
+		FROM pg_indexes
+		WHERE schemaname = ANY(current_schemas(false))
+		  AND tablename = ANY($1)
+	`, pq.Array(tables))
+	if err != nil {
+		return nil, errors.ErrDatabaseError("verify indexes", err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	existing := make(map[RequiredIndex]bool)
+	for rows.Next() {
+		var idx RequiredIndex
+		if err := rows.Scan(&idx.Table, &idx.Name); err != nil {
+			return nil, errors.ErrDatabaseError("scan index", err)
+		}
+		existing[idx] = true
+	}
+	if err := rows.Err(); err != nil {
+		return nil, errors.ErrDatabaseError("iterate indexes", err)
+	}
+
+	var missing []MissingIndex
+	for _, idx := range requiredIndexes {
+		if !existing[idx.RequiredIndex] {
+			missing = append(missing, MissingIndex{RequiredIndex: idx.RequiredIndex, Migration: idx.migration})
+		}
+	}
+
+	return missing, nil
+}
+
+// runStartupChecks runs VerifyIndexes and logs the results as warnings.
+// Failures are advisory: the repository is usable either way.
+func (r *PostgresGoalRepository) runStartupChecks() {
+	if r.db == nil {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), startupCheckTimeout)
+	defer cancel()
+
+	missing, err := r.VerifyIndexes(ctx)
+	if err != nil {
+		r.logger.Warn("Startup index check failed", "error", err)
+		return
+	}
+
+	for _, idx := range missing {
+		r.logger.Warn("Required index missing",
+			"table", idx.Table,
+			"index", idx.Name,
+			"migration", idx.Migration,
+		)
+	}
+}
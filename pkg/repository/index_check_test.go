@@ -0,0 +1,99 @@
+package repository
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"strings"
+	"testing"
+)
+
+func TestRequiredIndexes(t *testing.T) {
+	indexes := RequiredIndexes()
+
+	for _, want := range []RequiredIndex{
+		{Table: "user_goal_progress", Name: "user_goal_progress_pkey"},
+		{Table: "user_goal_progress", Name: "idx_user_goal_progress_user_challenge"},
+	} {
+		found := false
+		for _, idx := range indexes {
+			if idx == want {
+				found = true
+			}
+		}
+		if !found {
+			t.Errorf("Expected %+v in required indexes", want)
+		}
+	}
+
+	// The returned slice is a copy
+	indexes[0].Name = "changed"
+	if RequiredIndexes()[0].Name == "changed" {
+		t.Error("RequiredIndexes should return a copy")
+	}
+}
+
+func TestPostgresGoalRepository_VerifyIndexes(t *testing.T) {
+	db := setupTestDB(t)
+	if db == nil {
+		return
+	}
+	defer cleanupTestDB(t, db)
+
+	repo := NewPostgresGoalRepository(db)
+	ctx := context.Background()
+
+	missing, err := repo.VerifyIndexes(ctx)
+	if err != nil {
+		t.Fatalf("VerifyIndexes failed: %v", err)
+	}
+	if len(missing) != 0 {
+		t.Fatalf("Expected no missing indexes, got %+v", missing)
+	}
+
+	if _, err := db.Exec("DROP INDEX idx_user_goal_progress_user_challenge"); err != nil {
+		t.Fatalf("Failed to drop index: %v", err)
+	}
+	defer func() {
+		_, _ = db.Exec("CREATE INDEX IF NOT EXISTS idx_user_goal_progress_user_challenge ON user_goal_progress(user_id, challenge_id)")
+	}()
+
+	t.Run("dropped index is reported", func(t *testing.T) {
+		missing, err := repo.VerifyIndexes(ctx)
+		if err != nil {
+			t.Fatalf("VerifyIndexes failed: %v", err)
+		}
+		if len(missing) != 1 || missing[0].Name != "idx_user_goal_progress_user_challenge" {
+			t.Fatalf("Expected the user+challenge index to be missing, got %+v", missing)
+		}
+		if missing[0].Migration != "001_create_user_goal_progress.up.sql" {
+			t.Errorf("Expected migration 001, got %q", missing[0].Migration)
+		}
+	})
+
+	t.Run("startup checks log a warning", func(t *testing.T) {
+		var buf bytes.Buffer
+		logger := slog.New(slog.NewTextHandler(&buf, nil))
+
+		NewPostgresGoalRepository(db, WithLogger(logger), WithStartupChecks())
+
+		if !strings.Contains(buf.String(), "Required index missing") ||
+			!strings.Contains(buf.String(), "idx_user_goal_progress_user_challenge") {
+			t.Errorf("Expected a missing index warning, got %q", buf.String())
+		}
+	})
+
+	t.Run("recreated index is clean", func(t *testing.T) {
+		if _, err := db.Exec("CREATE INDEX idx_user_goal_progress_user_challenge ON user_goal_progress(user_id, challenge_id)"); err != nil {
+			t.Fatalf("Failed to recreate index: %v", err)
+		}
+
+		missing, err := repo.VerifyIndexes(ctx)
+		if err != nil {
+			t.Fatalf("VerifyIndexes failed: %v", err)
+		}
+		if len(missing) != 0 {
+			t.Errorf("Expected no missing indexes, got %+v", missing)
+		}
+	})
+}
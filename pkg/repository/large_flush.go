@@ -0,0 +1,146 @@
+package repository
+
+import (
+	"context"
+	"hash/fnv"
+	"sync"
+
+	"golang.org/x/sync/errgroup"
+
+	"github.com/AccelByte/extend-challenge-common/pkg/domain"
+	"github.com/AccelByte/extend-challenge-common/pkg/errors"
+)
+
+const (
+	// DefaultLargeFlushChunkSize is the number of rows FlushLarge sends per COPY flush.
+	DefaultLargeFlushChunkSize = 5000
+
+	// DefaultLargeFlushParallelism is the number of chunks FlushLarge flushes concurrently.
+	DefaultLargeFlushParallelism = 4
+)
+
+// LargeFlushOptions configures FlushLarge. Zero values use the defaults.
+type LargeFlushOptions struct {
+	ChunkSize   int // Rows per COPY flush (default DefaultLargeFlushChunkSize)
+	Parallelism int // Chunks flushed concurrently (default DefaultLargeFlushParallelism)
+}
+
+// BatchResult reports how much of a FlushLarge call reached the database.
+type BatchResult struct {
+	// Rows is the number of rows in chunks that committed, after duplicates were merged.
+	// Those rows are durable even when FlushLarge returns an error. As with
+	// BatchUpsertProgressWithCOPY, rows for unassigned or claimed goals are part of a
+	// committed chunk but leave the table unchanged.
+	Rows int
+
+	// Chunks is the number of chunks that committed.
+	Chunks int
+
+	// Duplicates is the number of input rows dropped because another row for the same
+	// user and goal was kept (see MergeStrategy). A complete flush has
+	// Rows + Duplicates == len(updates).
+	Duplicates int
+}
+
+// LargeFlusher writes very large batches, such as full reconciliation jobs, over several
+// connections at once. It is only available on the connection pool: a transaction runs
+// on a single connection.
+type LargeFlusher interface {
+	// FlushLarge writes updates like BatchUpsertProgressWithCOPY, after merging duplicate
+	// entries across the whole batch (see MergeStrategy), split into chunks of
+	// opts.ChunkSize rows of which up to opts.Parallelism are flushed concurrently, each
+	// in its own transaction.
+	//
+	// Rows are assigned to parallel lanes by a hash of their user ID, and each lane
+	// flushes its chunks one after another in input order. A user's rows therefore never
+	// land in two concurrent chunks and are written in the order given.
+	//
+	// The price of that ordering is that a lane is the unit of parallelism: a skewed batch
+	// (one user with most of the rows, or heavy users hashing to the same lane) flushes
+	// the crowded lane's chunks one at a time while the other lanes finish early, so the
+	// flush takes as long as its largest lane.
+	//
+	// The first failed chunk cancels the chunks not yet started and in flight, and its
+	// error is returned. Chunks committed before that stay written; the returned
+	// BatchResult counts them, so a retry can tell how far the flush got. Returns
+	// ErrValidationFailed for a negative ChunkSize or Parallelism.
+	FlushLarge(ctx context.Context, updates []*domain.UserGoalProgress, opts LargeFlushOptions) (BatchResult, error)
+}
+
+// FlushLarge writes a large batch of progress updates in parallel COPY chunks.
+func (r *PostgresGoalRepository) FlushLarge(ctx context.Context, updates []*domain.UserGoalProgress, opts LargeFlushOptions) (BatchResult, error) {
+	// Merge across the whole batch: duplicates in different chunks would otherwise be
+	// resolved by chunk order instead of the merge strategy
+	updates, duplicates := r.mergeUpdates("flush large batch", updates)
+	result, err := flushLarge(ctx, updates, opts, r.BatchUpsertProgressWithCOPY)
+	result.Duplicates = duplicates
+	return result, err
+}
+
+// flushLarge partitions updates into per-user lanes and runs flush on each lane's chunks,
+// at most opts.Parallelism lanes at a time. flush must write a chunk atomically.
+func flushLarge(ctx context.Context, updates []*domain.UserGoalProgress, opts LargeFlushOptions,
+	flush func(ctx context.Context, chunk []*domain.UserGoalProgress) error) (BatchResult, error) {
+	if opts.ChunkSize < 0 {
+		return BatchResult{}, errors.ErrValidationFailed("chunkSize", "cannot be negative")
+	}
+	if opts.Parallelism < 0 {
+		return BatchResult{}, errors.ErrValidationFailed("parallelism", "cannot be negative")
+	}
+	if opts.ChunkSize == 0 {
+		opts.ChunkSize = DefaultLargeFlushChunkSize
+	}
+	if opts.Parallelism == 0 {
+		opts.Parallelism = DefaultLargeFlushParallelism
+	}
+	if len(updates) == 0 {
+		return BatchResult{}, nil
+	}
+
+	var (
+		mu     sync.Mutex
+		result BatchResult
+	)
+	g, laneCtx := errgroup.WithContext(ctx)
+	g.SetLimit(opts.Parallelism)
+	for _, lane := range partitionByUser(updates, opts.Parallelism) {
+		if len(lane) == 0 {
+			continue
+		}
+
+		g.Go(func() error {
+			for start := 0; start < len(lane); start += opts.ChunkSize {
+				if laneCtx.Err() != nil {
+					// Another lane failed (its error wins) or the caller's context ended
+					return errors.ErrDatabaseError("flush large batch", context.Cause(laneCtx))
+				}
+
+				chunk := lane[start:min(start+opts.ChunkSize, len(lane))]
+				if err := flush(laneCtx, chunk); err != nil {
+					return err // Cancels the other lanes
+				}
+
+				mu.Lock()
+				result.Rows += len(chunk)
+				result.Chunks++
+				mu.Unlock()
+			}
+			return nil
+		})
+	}
+	err := g.Wait()
+	return result, err
+}
+
+// partitionByUser splits updates into n lanes by a hash of the user ID, keeping the
+// input order within each lane.
+func partitionByUser(updates []*domain.UserGoalProgress, n int) [][]*domain.UserGoalProgress {
+	lanes := make([][]*domain.UserGoalProgress, n)
+	for _, u := range updates {
+		h := fnv.New32a()
+		_, _ = h.Write([]byte(u.UserID))
+		i := int(h.Sum32() % uint32(n))
+		lanes[i] = append(lanes[i], u)
+	}
+	return lanes
+}
@@ -0,0 +1,292 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/AccelByte/extend-challenge-common/pkg/domain"
+	customerrors "github.com/AccelByte/extend-challenge-common/pkg/errors"
+)
+
+// syntheticUpdates returns n rows spread round-robin over users. Progress numbers each
+// user's rows in input order, so flush order can be checked per user.
+func syntheticUpdates(n, users int) []*domain.UserGoalProgress {
+	updates := make([]*domain.UserGoalProgress, n)
+	for i := range updates {
+		updates[i] = &domain.UserGoalProgress{
+			UserID:   fmt.Sprintf("user-%d", i%users),
+			GoalID:   fmt.Sprintf("goal-%d", i),
+			Progress: int64(i / users),
+			Status:   domain.GoalStatusInProgress,
+		}
+	}
+	return updates
+}
+
+// chunkRecorder is a flush func that records chunks and checks that no user is in two
+// chunks at the same time.
+type chunkRecorder struct {
+	mu       sync.Mutex
+	active   map[string]int // user -> chunks in flight
+	inFlight int
+	peak     int
+	flushed  []*domain.UserGoalProgress
+	chunks   []int
+	overlaps []string
+	hold     time.Duration
+}
+
+func newChunkRecorder(hold time.Duration) *chunkRecorder {
+	return &chunkRecorder{active: make(map[string]int), hold: hold}
+}
+
+func (r *chunkRecorder) flush(_ context.Context, chunk []*domain.UserGoalProgress) error {
+	users := make(map[string]bool)
+	for _, u := range chunk {
+		users[u.UserID] = true
+	}
+
+	r.mu.Lock()
+	for user := range users {
+		if r.active[user] > 0 {
+			r.overlaps = append(r.overlaps, user)
+		}
+		r.active[user]++
+	}
+	r.inFlight++
+	r.peak = max(r.peak, r.inFlight)
+	r.mu.Unlock()
+
+	time.Sleep(r.hold)
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for user := range users {
+		r.active[user]--
+	}
+	r.inFlight--
+	r.flushed = append(r.flushed, chunk...)
+	r.chunks = append(r.chunks, len(chunk))
+	return nil
+}
+
+func TestFlushLarge_WritesEveryRowOnce(t *testing.T) {
+	updates := syntheticUpdates(50_000, 997)
+	recorder := newChunkRecorder(0)
+
+	result, err := flushLarge(context.Background(), updates, LargeFlushOptions{ChunkSize: 1000, Parallelism: 8}, recorder.flush)
+	if err != nil {
+		t.Fatalf("flushLarge failed: %v", err)
+	}
+
+	if result.Rows != len(updates) || result.Chunks != len(recorder.chunks) {
+		t.Errorf("result = %+v, want %d rows in %d chunks", result, len(updates), len(recorder.chunks))
+	}
+	for _, size := range recorder.chunks {
+		if size > 1000 {
+			t.Errorf("chunk of %d rows exceeds ChunkSize 1000", size)
+		}
+	}
+
+	seen := make(map[string]bool, len(updates))
+	next := make(map[string]int64)
+	for _, u := range recorder.flushed {
+		if seen[u.GoalID] {
+			t.Fatalf("row %s flushed twice", u.GoalID)
+		}
+		seen[u.GoalID] = true
+
+		if u.Progress != next[u.UserID] {
+			t.Fatalf("%s: row %d flushed before row %d", u.UserID, u.Progress, next[u.UserID])
+		}
+		next[u.UserID]++
+	}
+	if len(seen) != len(updates) {
+		t.Errorf("flushed %d distinct rows, want %d", len(seen), len(updates))
+	}
+}
+
+func TestFlushLarge_UserAffinity(t *testing.T) {
+	// Few users and small chunks, so a user's rows span many chunks
+	updates := syntheticUpdates(5_000, 40)
+	recorder := newChunkRecorder(time.Millisecond)
+
+	if _, err := flushLarge(context.Background(), updates, LargeFlushOptions{ChunkSize: 50, Parallelism: 4}, recorder.flush); err != nil {
+		t.Fatalf("flushLarge failed: %v", err)
+	}
+
+	if len(recorder.overlaps) > 0 {
+		t.Errorf("users in two concurrent chunks: %v", recorder.overlaps)
+	}
+	if recorder.peak > 4 {
+		t.Errorf("peak concurrency = %d, want at most Parallelism 4", recorder.peak)
+	}
+}
+
+func TestFlushLarge_SkewedUsers(t *testing.T) {
+	// One hot user with 90% of the rows: its lane flushes serially, the rest in parallel
+	hot := syntheticUpdates(9_000, 1)
+	updates := append(hot, syntheticUpdates(1_000, 100)...)
+	for i, u := range updates[len(hot):] {
+		u.UserID, u.GoalID = "cold-"+u.UserID, fmt.Sprintf("cold-%d", i)
+	}
+	recorder := newChunkRecorder(100 * time.Microsecond)
+
+	result, err := flushLarge(context.Background(), updates, LargeFlushOptions{ChunkSize: 100, Parallelism: 4}, recorder.flush)
+	if err != nil {
+		t.Fatalf("flushLarge failed: %v", err)
+	}
+
+	if result.Rows != len(updates) {
+		t.Errorf("result.Rows = %d, want %d", result.Rows, len(updates))
+	}
+	if len(recorder.overlaps) > 0 {
+		t.Errorf("users in two concurrent chunks: %v", recorder.overlaps)
+	}
+	if recorder.peak > 4 {
+		t.Errorf("peak concurrency = %d, want at most Parallelism 4", recorder.peak)
+	}
+
+	next := make(map[string]int64)
+	for _, u := range recorder.flushed {
+		if u.Progress != next[u.UserID] {
+			t.Fatalf("%s: row %d flushed before row %d", u.UserID, u.Progress, next[u.UserID])
+		}
+		next[u.UserID]++
+	}
+	if got := next["user-0"]; got != int64(len(hot)) {
+		t.Errorf("hot user flushed %d rows, want %d", got, len(hot))
+	}
+}
+
+func TestFlushLarge_CancelsOnError(t *testing.T) {
+	updates := syntheticUpdates(20_000, 500)
+	errFlush := errors.New("connection reset")
+
+	var mu sync.Mutex
+	calls, written := 0, 0
+	flush := func(ctx context.Context, chunk []*domain.UserGoalProgress) error {
+		mu.Lock()
+		calls++
+		call := calls
+		mu.Unlock()
+
+		if call == 6 {
+			return errFlush
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err() // In flight when the failure cancelled the flush: rolled back
+		case <-time.After(2 * time.Millisecond):
+		}
+
+		mu.Lock()
+		written += len(chunk)
+		mu.Unlock()
+		return nil
+	}
+
+	result, err := flushLarge(context.Background(), updates, LargeFlushOptions{ChunkSize: 500, Parallelism: 4}, flush)
+	if !errors.Is(err, errFlush) {
+		t.Fatalf("err = %v, want the failing chunk's error", err)
+	}
+	if result.Rows != written {
+		t.Errorf("result.Rows = %d, want %d rows from committed chunks", result.Rows, written)
+	}
+	if result.Rows >= len(updates) {
+		t.Errorf("result.Rows = %d, want the flush stopped before all %d rows", result.Rows, len(updates))
+	}
+	if total := (len(updates) + 499) / 500; calls >= total {
+		t.Errorf("%d chunk flushes started, want remaining chunks skipped after the failure", calls)
+	}
+}
+
+func TestFlushLarge_Options(t *testing.T) {
+	noFlush := func(context.Context, []*domain.UserGoalProgress) error {
+		t.Fatal("flush called")
+		return nil
+	}
+
+	t.Run("negative options are rejected", func(t *testing.T) {
+		for _, opts := range []LargeFlushOptions{{ChunkSize: -1}, {Parallelism: -1}} {
+			_, err := flushLarge(context.Background(), syntheticUpdates(10, 2), opts, noFlush)
+			var ce *customerrors.ChallengeError
+			if !errors.As(err, &ce) || ce.Code != customerrors.ErrCodeValidationFailed {
+				t.Errorf("opts %+v: err = %v, want VALIDATION_FAILED", opts, err)
+			}
+		}
+	})
+
+	t.Run("empty input is a no-op", func(t *testing.T) {
+		result, err := flushLarge(context.Background(), nil, LargeFlushOptions{}, noFlush)
+		if err != nil || result != (BatchResult{}) {
+			t.Errorf("result = %+v, err = %v, want zero result", result, err)
+		}
+	})
+
+	t.Run("zero options use the defaults", func(t *testing.T) {
+		recorder := newChunkRecorder(0)
+		result, err := flushLarge(context.Background(), syntheticUpdates(DefaultLargeFlushChunkSize*3, 1), LargeFlushOptions{}, recorder.flush)
+		if err != nil {
+			t.Fatalf("flushLarge failed: %v", err)
+		}
+		// A single user stays in one lane, so chunks are exactly the default size
+		if result.Chunks != 3 {
+			t.Errorf("result.Chunks = %d, want 3 chunks of %d rows", result.Chunks, DefaultLargeFlushChunkSize)
+		}
+	})
+
+	t.Run("cancelled context stops the flush", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		result, err := flushLarge(ctx, syntheticUpdates(10, 2), LargeFlushOptions{}, noFlush)
+		if err == nil || result.Rows != 0 {
+			t.Errorf("result = %+v, err = %v, want an error and no rows", result, err)
+		}
+	})
+}
+
+func TestPostgresGoalRepository_FlushLarge(t *testing.T) {
+	db := setupTestDB(t)
+	if db == nil {
+		return
+	}
+	defer cleanupTestDB(t, db)
+
+	ctx := context.Background()
+	repo := NewPostgresGoalRepository(db)
+
+	// The pool COPY merge only updates assigned rows, so assign every goal first
+	updates := syntheticUpdates(200, 20)
+	assigned := make([]*domain.UserGoalProgress, len(updates))
+	for i, u := range updates {
+		u.ChallengeID, u.Namespace = "c1", "test"
+		assigned[i] = &domain.UserGoalProgress{UserID: u.UserID, GoalID: u.GoalID, ChallengeID: "c1", Namespace: "test", Status: domain.GoalStatusNotStarted, IsActive: true}
+		u.Progress++ // Non-zero so the update is visible
+	}
+	if err := repo.BulkInsert(ctx, assigned); err != nil {
+		t.Fatalf("BulkInsert failed: %v", err)
+	}
+
+	result, err := repo.FlushLarge(ctx, updates, LargeFlushOptions{ChunkSize: 30, Parallelism: 3})
+	if err != nil {
+		t.Fatalf("FlushLarge failed: %v", err)
+	}
+	if result.Rows != len(updates) {
+		t.Errorf("result.Rows = %d, want %d", result.Rows, len(updates))
+	}
+
+	for _, u := range updates {
+		row, err := repo.GetProgressRequired(ctx, u.UserID, u.GoalID)
+		if err != nil {
+			t.Fatalf("GetProgressRequired failed: %v", err)
+		}
+		if row.Progress != u.Progress {
+			t.Errorf("%s/%s progress = %d, want %d", u.UserID, u.GoalID, row.Progress, u.Progress)
+		}
+	}
+}
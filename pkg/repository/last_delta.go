@@ -0,0 +1,70 @@
+package repository
+
+import "strings"
+
+// The last_delta column (migration 019) records the delta of the most recent increment
+// applied to a row, next to the updated_at that increment stamped. It is optional: the
+// queries below are only used with WithLastDelta, so schemas without the column keep
+// using the plain increment and read queries.
+//
+// Only increments that change progress record their delta: same-day daily increments and
+// attempt-only batch entries keep the previous value. The stored delta is the one applied
+// after WithMaxDeltaPerEvent capping; a progress cap (OverflowPolicy, progress ceiling)
+// can make the actual jump smaller. A row inserted by an upserting (transactional)
+// increment starts without a last delta; its progress is that first delta.
+
+// withLastDelta adds "last_delta = expr" to the SET list of an increment query, next to
+// the updated_at stamp of its UPDATE (for upserts, the ON CONFLICT branch). It panics if
+// the query has no such stamp, so a reworded query fails at package initialization
+// rather than silently dropping the column.
+func withLastDelta(query, expr string) string {
+	return withAssignment(query, "last_delta = "+expr)
+}
+
+// withAssignment adds assignment to the SET list of a write query, before the updated_at
+// stamp of its UPDATE (for upserts, the ON CONFLICT branch). It panics if the query has
+// no such stamp.
+func withAssignment(query, assignment string) string {
+	set := strings.Index(query, "SET")
+	stamp := strings.Index(query[max(set, 0):], "updated_at = NOW()")
+	if set < 0 || stamp < 0 {
+		panic("repository: write query has no updated_at stamp for " + assignment)
+	}
+	at := set + stamp
+	return query[:at] + assignment + ", " + query[at:]
+}
+
+// dailyCountedToday matches a row whose UTC day was already counted by a daily increment,
+// the case in which the single daily increments leave progress unchanged.
+const dailyCountedToday = `COALESCE(user_goal_progress.last_daily_date, DATE(user_goal_progress.updated_at AT TIME ZONE 'UTC')) >= DATE(NOW() AT TIME ZONE 'UTC')`
+
+// Increment queries that also record last_delta, selected with WithLastDelta.
+var (
+	incrementRegularLastDeltaQuery = withLastDelta(incrementRegularQuery, "$3::INT")
+	incrementDailyLastDeltaQuery   = withLastDelta(incrementDailyQuery,
+		"CASE WHEN "+dailyCountedToday+" THEN user_goal_progress.last_delta ELSE $3::INT END")
+	txIncrementRegularLastDeltaQuery = withLastDelta(txIncrementRegularQuery, "$5::INT")
+	txIncrementDailyLastDeltaQuery   = withLastDelta(txIncrementDailyQuery,
+		"CASE WHEN "+dailyCountedToday+" THEN user_goal_progress.last_delta ELSE $5::INT END")
+
+	batchIncrementProgressLastDeltaQuery   = withLastDelta(batchIncrementProgressQuery, batchIncrementLastDelta)
+	txBatchIncrementProgressLastDeltaQuery = withLastDelta(txBatchIncrementProgressQuery, txBatchIncrementLastDelta)
+)
+
+// batchIncrementLastDelta is the last_delta expression of batchIncrementProgressQuery.
+const batchIncrementLastDelta = `CASE
+				WHEN t.delta = 0 THEN user_goal_progress.last_delta  -- Attempt only
+				WHEN t.is_daily = true
+				     AND COALESCE(user_goal_progress.last_daily_date, DATE(user_goal_progress.updated_at AT TIME ZONE 'UTC')) >= DATE(t.event_at AT TIME ZONE 'UTC')
+					THEN user_goal_progress.last_delta  -- Same day, not counted
+				ELSE t.delta::INT
+			END`
+
+// txBatchIncrementLastDelta is the last_delta expression of txBatchIncrementProgressQuery.
+// EXCLUDED.last_daily_date is NULL for non-daily entries, so only a daily entry for a
+// counted day matches the first branch.
+var txBatchIncrementLastDelta = `CASE
+				WHEN COALESCE(user_goal_progress.last_daily_date, DATE(user_goal_progress.updated_at AT TIME ZONE 'UTC')) >= EXCLUDED.last_daily_date
+					THEN user_goal_progress.last_delta
+				ELSE COALESCE(NULLIF(` + txBatchEntry("delta", "$5::BIGINT[]") + `, 0)::INT, user_goal_progress.last_delta)
+			END`
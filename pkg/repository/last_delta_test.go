@@ -0,0 +1,152 @@
+package repository
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/AccelByte/extend-challenge-common/pkg/domain"
+)
+
+func TestWithLastDelta(t *testing.T) {
+	variants := map[string]struct{ plain, withDelta string }{
+		"regular":    {incrementRegularQuery, incrementRegularLastDeltaQuery},
+		"daily":      {incrementDailyQuery, incrementDailyLastDeltaQuery},
+		"tx regular": {txIncrementRegularQuery, txIncrementRegularLastDeltaQuery},
+		"tx daily":   {txIncrementDailyQuery, txIncrementDailyLastDeltaQuery},
+		"batch":      {batchIncrementProgressQuery, batchIncrementProgressLastDeltaQuery},
+		"tx batch":   {txBatchIncrementProgressQuery, txBatchIncrementProgressLastDeltaQuery},
+	}
+	for name, v := range variants {
+		if strings.Contains(v.plain, "last_delta") {
+			t.Errorf("%s: plain query references last_delta", name)
+		}
+		if !strings.Contains(v.withDelta, "last_delta") {
+			t.Errorf("%s: last_delta variant does not reference last_delta", name)
+		}
+	}
+
+	// The column is set in the UPDATE branch, never in an INSERT column list
+	for _, query := range []string{txIncrementRegularLastDeltaQuery, txBatchIncrementProgressLastDeltaQuery} {
+		if set := strings.Index(query, "DO UPDATE SET"); strings.Index(query, "last_delta =") < set {
+			t.Errorf("last_delta assigned before the ON CONFLICT branch:\n%s", query)
+		}
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Error("withLastDelta on a query without an updated_at stamp did not panic")
+		}
+	}()
+	withLastDelta("UPDATE user_goal_progress SET progress = 0", "$3::INT")
+}
+
+func TestPostgresGoalRepository_LastDelta(t *testing.T) {
+	db := setupTestDB(t)
+	if db == nil {
+		return
+	}
+	defer cleanupTestDB(t, db)
+
+	ctx := context.Background()
+	repo := NewPostgresGoalRepository(db, WithLastDelta(true))
+
+	if err := repo.BulkInsert(ctx, []*domain.UserGoalProgress{
+		{UserID: "delta-user", GoalID: "kills", ChallengeID: "c1", Namespace: "test", Status: domain.GoalStatusNotStarted, IsActive: true},
+		{UserID: "delta-user", GoalID: "logins", ChallengeID: "c1", Namespace: "test", Status: domain.GoalStatusNotStarted, IsActive: true},
+	}); err != nil {
+		t.Fatalf("BulkInsert failed: %v", err)
+	}
+	// Daily increments count from the day after the row was last written
+	if _, err := db.ExecContext(ctx, `
+		UPDATE user_goal_progress SET updated_at = NOW() - INTERVAL '1 day' WHERE user_id = 'delta-user' AND goal_id = 'logins'
+	`); err != nil {
+		t.Fatalf("Backdate failed: %v", err)
+	}
+
+	assertLastDelta := func(t *testing.T, goalID string, want *int) {
+		t.Helper()
+		progress, err := repo.GetProgressRequired(ctx, "delta-user", goalID)
+		if err != nil {
+			t.Fatalf("GetProgressRequired failed: %v", err)
+		}
+		switch {
+		case want == nil && progress.LastDelta != nil:
+			t.Errorf("%s LastDelta = %d, want nil", goalID, *progress.LastDelta)
+		case want != nil && (progress.LastDelta == nil || *progress.LastDelta != *want):
+			t.Errorf("%s LastDelta = %v, want %d", goalID, progress.LastDelta, *want)
+		}
+	}
+	intPtr := func(v int) *int { return &v }
+
+	assertLastDelta(t, "kills", nil)
+
+	if err := repo.IncrementProgress(ctx, "delta-user", "kills", "c1", "test", 5, 100, false); err != nil {
+		t.Fatalf("IncrementProgress failed: %v", err)
+	}
+	assertLastDelta(t, "kills", intPtr(5))
+
+	t.Run("batch increment records the latest delta", func(t *testing.T) {
+		if err := repo.BatchIncrementProgress(ctx, []ProgressIncrement{
+			{UserID: "delta-user", GoalID: "kills", ChallengeID: "c1", Namespace: "test", Delta: 50, TargetValue: 100},
+		}); err != nil {
+			t.Fatalf("BatchIncrementProgress failed: %v", err)
+		}
+		assertLastDelta(t, "kills", intPtr(50))
+	})
+
+	t.Run("attempt-only entry keeps the last delta", func(t *testing.T) {
+		if err := repo.BatchIncrementProgress(ctx, []ProgressIncrement{
+			{UserID: "delta-user", GoalID: "kills", ChallengeID: "c1", Namespace: "test", Delta: 0, AttemptDelta: 1, TargetValue: 100},
+		}); err != nil {
+			t.Fatalf("BatchIncrementProgress failed: %v", err)
+		}
+		assertLastDelta(t, "kills", intPtr(50))
+	})
+
+	t.Run("same-day daily increment keeps the last delta", func(t *testing.T) {
+		for range 2 {
+			if err := repo.IncrementProgress(ctx, "delta-user", "logins", "c1", "test", 1, 7, true); err != nil {
+				t.Fatalf("IncrementProgress failed: %v", err)
+			}
+		}
+		assertLastDelta(t, "logins", intPtr(1))
+	})
+
+	t.Run("in transaction", func(t *testing.T) {
+		tx, err := repo.BeginTx(ctx)
+		if err != nil {
+			t.Fatalf("BeginTx failed: %v", err)
+		}
+		defer func() { _ = tx.Rollback() }()
+
+		if err := tx.IncrementProgress(ctx, "delta-user", "kills", "c1", "test", 3, 100, false); err != nil {
+			t.Fatalf("IncrementProgress failed: %v", err)
+		}
+		if err := tx.BatchIncrementProgress(ctx, []ProgressIncrement{
+			{UserID: "delta-user", GoalID: "logins", ChallengeID: "c1", Namespace: "test", Delta: 4, TargetValue: 100},
+		}); err != nil {
+			t.Fatalf("BatchIncrementProgress failed: %v", err)
+		}
+
+		for goalID, want := range map[string]int{"kills": 3, "logins": 4} {
+			progress, err := tx.GetProgress(ctx, "delta-user", goalID)
+			if err != nil {
+				t.Fatalf("GetProgress failed: %v", err)
+			}
+			if progress.LastDelta == nil || *progress.LastDelta != want {
+				t.Errorf("%s LastDelta = %v, want %d", goalID, progress.LastDelta, want)
+			}
+		}
+	})
+
+	t.Run("disabled repository does not read the column", func(t *testing.T) {
+		progress, err := NewPostgresGoalRepository(db).GetProgressRequired(ctx, "delta-user", "kills")
+		if err != nil {
+			t.Fatalf("GetProgressRequired failed: %v", err)
+		}
+		if progress.LastDelta != nil {
+			t.Errorf("LastDelta = %d, want nil without WithLastDelta", *progress.LastDelta)
+		}
+	})
+}
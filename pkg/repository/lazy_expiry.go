@@ -0,0 +1,33 @@
+package repository
+
+// Lazy expiry (see WithLazyExpireOnRead)
+//
+// A goal past its expires_at keeps is_active = true until something flips it. Two
+// strategies are supported:
+//
+//   - Explicit expiry: a scheduled job deactivates expired rows. Reads stay as cheap as
+//     the is_active indexes allow, but a goal remains visible as active between its
+//     expiry and the next job run.
+//   - Lazy expiry (WithLazyExpireOnRead): active-only reads also filter on expires_at,
+//     so an expired goal disappears the moment it is read, with no job to operate.
+//     The extra predicate is evaluated per row (no index covers expires_at), and
+//     is_active is never flipped, so writes and scans that check only is_active
+//     (increments, GetProgressAboveThreshold, counts) still see the row as active.
+//
+// The two can be combined: lazy expiry for immediate visibility, and a periodic job to
+// keep is_active accurate for everything else.
+
+// activeOnlyPredicate is the filter applied by active-only reads.
+const activeOnlyPredicate = " AND is_active = true"
+
+// unexpiredPredicate additionally excludes rows past expires_at; NULL means no expiry.
+const unexpiredPredicate = " AND (expires_at IS NULL OR expires_at > NOW())"
+
+// activeOnlyClause returns the WHERE predicate for an active-only read, including the
+// expiry check when lazy expiry is enabled.
+func (r *PostgresGoalRepository) activeOnlyClause() string {
+	if r.lazyExpireOnRead {
+		return activeOnlyPredicate + unexpiredPredicate
+	}
+	return activeOnlyPredicate
+}
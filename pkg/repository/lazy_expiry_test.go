@@ -0,0 +1,109 @@
+package repository
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/AccelByte/extend-challenge-common/pkg/domain"
+)
+
+func TestActiveOnlyClause(t *testing.T) {
+	if got := NewPostgresGoalRepository(nil).activeOnlyClause(); got != activeOnlyPredicate {
+		t.Errorf("Default clause = %q, want %q", got, activeOnlyPredicate)
+	}
+
+	lazy := NewPostgresGoalRepository(nil, WithLazyExpireOnRead(true))
+	if got := lazy.activeOnlyClause(); got != activeOnlyPredicate+unexpiredPredicate {
+		t.Errorf("Lazy expiry clause = %q", got)
+	}
+}
+
+func TestPostgresGoalRepository_LazyExpireOnRead(t *testing.T) {
+	db := setupTestDB(t)
+	if db == nil {
+		return
+	}
+	defer cleanupTestDB(t, db)
+
+	ctx := context.Background()
+	expired := time.Now().UTC().Add(-time.Hour)
+	future := time.Now().UTC().Add(24 * time.Hour)
+
+	err := NewPostgresGoalRepository(db).BulkInsert(ctx, []*domain.UserGoalProgress{
+		{UserID: "expiry-user", GoalID: "permanent", ChallengeID: "c1", Namespace: "test", Status: domain.GoalStatusInProgress, IsActive: true},
+		{UserID: "expiry-user", GoalID: "future", ChallengeID: "c1", Namespace: "test", Status: domain.GoalStatusInProgress, IsActive: true, ExpiresAt: &future},
+		{UserID: "expiry-user", GoalID: "expired", ChallengeID: "c1", Namespace: "test", Status: domain.GoalStatusInProgress, IsActive: true, ExpiresAt: &expired},
+		{UserID: "expiry-user", GoalID: "inactive", ChallengeID: "c1", Namespace: "test", Status: domain.GoalStatusInProgress, IsActive: false},
+	})
+	if err != nil {
+		t.Fatalf("BulkInsert failed: %v", err)
+	}
+
+	allGoals := []string{"permanent", "future", "expired", "inactive"}
+
+	tests := []struct {
+		name       string
+		lazy       bool
+		wantActive []string
+	}{
+		{"explicit expiry returns expired active rows", false, []string{"permanent", "future", "expired"}},
+		{"lazy expiry excludes expired rows", true, []string{"permanent", "future"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := NewPostgresGoalRepository(db, WithLazyExpireOnRead(tt.lazy))
+
+			userProgress, err := repo.GetUserProgress(ctx, "expiry-user", true)
+			if err != nil {
+				t.Fatalf("GetUserProgress failed: %v", err)
+			}
+			assertGoalIDs(t, userProgress, tt.wantActive)
+
+			challengeProgress, err := repo.GetChallengeProgress(ctx, "expiry-user", "c1", true)
+			if err != nil {
+				t.Fatalf("GetChallengeProgress failed: %v", err)
+			}
+			assertGoalIDs(t, challengeProgress, tt.wantActive)
+
+			active, err := repo.GetActiveGoals(ctx, "expiry-user")
+			if err != nil {
+				t.Fatalf("GetActiveGoals failed: %v", err)
+			}
+			assertGoalIDs(t, active, tt.wantActive)
+
+			page, _, err := repo.GetUserProgressPage(ctx, "expiry-user", PageOptions{ActiveOnly: true})
+			if err != nil {
+				t.Fatalf("GetUserProgressPage failed: %v", err)
+			}
+			assertGoalIDs(t, page, tt.wantActive)
+
+			filtered, err := repo.GetGoalsByIDsFiltered(ctx, "expiry-user", allGoals, ProgressFilter{ActiveOnly: true})
+			if err != nil {
+				t.Fatalf("GetGoalsByIDsFiltered failed: %v", err)
+			}
+			assertGoalIDs(t, filtered, tt.wantActive)
+
+			// Transactions inherit the option
+			tx, err := repo.BeginTx(ctx)
+			if err != nil {
+				t.Fatalf("BeginTx failed: %v", err)
+			}
+			defer func() { _ = tx.Rollback() }()
+
+			inTx, err := tx.GetActiveGoals(ctx, "expiry-user")
+			if err != nil {
+				t.Fatalf("tx GetActiveGoals failed: %v", err)
+			}
+			assertGoalIDs(t, inTx, tt.wantActive)
+
+			// Reads without activeOnly are unaffected
+			all, err := repo.GetUserProgress(ctx, "expiry-user", false)
+			if err != nil {
+				t.Fatalf("GetUserProgress failed: %v", err)
+			}
+			assertGoalIDs(t, all, allGoals)
+		})
+	}
+}
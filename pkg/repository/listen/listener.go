@@ -0,0 +1,200 @@
+// Package listen delivers user_goal_progress change notifications published by the
+// optional NOTIFY trigger (migrations/006_add_progress_notify_trigger.up.sql), so
+// consumers such as websocket gateways can push progress updates instead of polling
+// GetUserProgress.
+//
+// Notifications are best effort: PostgreSQL drops them while the listener is
+// disconnected, and the listener drops them when the handler falls behind. Both cases
+// are reported to the handler as a Resync notification, after which consumers should
+// re-fetch the state they track.
+package listen
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/AccelByte/extend-challenge-common/pkg/domain"
+	"github.com/AccelByte/extend-challenge-common/pkg/errors"
+
+	"github.com/lib/pq"
+)
+
+const (
+	// Channel is the NOTIFY channel the progress trigger publishes to.
+	Channel = "goal_progress"
+
+	// BacklogSize bounds the notifications waiting for the handler. When the backlog is
+	// full, further notifications are dropped until the handler has received a Resync.
+	BacklogSize = 1024
+
+	// Reconnect backoff bounds passed to pq.Listener (doubling between attempts)
+	minReconnectInterval = 100 * time.Millisecond
+	maxReconnectInterval = 30 * time.Second
+
+	// pingInterval detects dead connections while no notifications arrive
+	pingInterval = 90 * time.Second
+)
+
+// ProgressNotification describes one change to a user_goal_progress row.
+type ProgressNotification struct {
+	UserID   string            `json:"user_id"`
+	GoalID   string            `json:"goal_id"`
+	Progress int64             `json:"progress"`
+	Status   domain.GoalStatus `json:"status"`
+
+	// Truncated is set when the row did not fit in a NOTIFY payload and only its keys
+	// were sent; Progress and Status are zero and the row must be re-fetched.
+	Truncated bool `json:"truncated"`
+
+	// Resync is set when notifications may have been lost (the listener reconnected or
+	// the backlog overflowed). UserID and GoalID are empty; re-fetch all tracked state.
+	Resync bool `json:"-"`
+}
+
+// decodeNotification parses a trigger payload.
+func decodeNotification(payload string) (ProgressNotification, error) {
+	var n ProgressNotification
+	if err := json.Unmarshal([]byte(payload), &n); err != nil {
+		return ProgressNotification{}, err
+	}
+	if n.UserID == "" || n.GoalID == "" {
+		return ProgressNotification{}, fmt.Errorf("payload missing user_id or goal_id")
+	}
+	return n, nil
+}
+
+// StartProgressListener connects to dsn, listens on Channel and calls handler for every
+// notification, one at a time, from a single goroutine. It returns once the first
+// connection succeeds; later disconnects are retried with backoff and reported to
+// handler as a Resync notification after reconnecting.
+//
+// The listener runs until ctx is cancelled or stop is called. stop waits for an
+// in-flight handler call to return, so it must not be called from handler.
+func StartProgressListener(ctx context.Context, dsn string, handler func(ProgressNotification)) (stop func(), err error) {
+	if handler == nil {
+		return nil, errors.ErrValidationFailed("handler", "progress listener handler cannot be nil")
+	}
+
+	logger := slog.Default()
+
+	// The first connection outcome decides whether the listener starts
+	connected := make(chan error, 1)
+	var first sync.Once
+	listener := pq.NewListener(dsn, minReconnectInterval, maxReconnectInterval, func(event pq.ListenerEventType, err error) {
+		switch event {
+		case pq.ListenerEventConnected:
+			first.Do(func() { connected <- nil })
+		case pq.ListenerEventConnectionAttemptFailed:
+			first.Do(func() { connected <- err })
+		case pq.ListenerEventDisconnected:
+			logger.Warn("Progress listener disconnected", "error", err)
+		case pq.ListenerEventReconnected:
+			logger.Info("Progress listener reconnected")
+		}
+	})
+
+	select {
+	case err := <-connected:
+		if err != nil {
+			_ = listener.Close()
+			return nil, errors.ErrDatabaseError("connect progress listener", err)
+		}
+	case <-ctx.Done():
+		_ = listener.Close()
+		return nil, ctx.Err()
+	}
+
+	if err := listener.Listen(Channel); err != nil {
+		_ = listener.Close()
+		return nil, errors.ErrDatabaseError("listen on "+Channel, err)
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	backlog := make(chan ProgressNotification, BacklogSize)
+
+	var wg sync.WaitGroup
+	wg.Add(2)
+	go func() {
+		defer wg.Done()
+		defer func() { _ = listener.Close() }()
+		forward(ctx, listener.Notify, backlog, listener.Ping, logger)
+	}()
+	go func() {
+		defer wg.Done()
+		deliver(ctx, backlog, handler)
+	}()
+
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			cancel()
+			wg.Wait()
+		})
+	}, nil
+}
+
+// forward decodes notifications into the backlog until ctx is done or notify is closed.
+// A nil notification (pq's signal that the connection was re-established) and a full
+// backlog both schedule a Resync; notifications are dropped until it is queued, since
+// the consumer's re-fetch will cover them.
+func forward(ctx context.Context, notify <-chan *pq.Notification, backlog chan<- ProgressNotification, ping func() error, logger *slog.Logger) {
+	ticker := time.NewTicker(pingInterval)
+	defer ticker.Stop()
+
+	var resync chan<- ProgressNotification // Non-nil while a Resync is pending
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+
+		case resync <- ProgressNotification{Resync: true}:
+			resync = nil
+
+		case n, ok := <-notify:
+			if !ok {
+				return
+			}
+			if n == nil {
+				resync = backlog
+				continue
+			}
+			if resync != nil {
+				continue
+			}
+
+			pn, err := decodeNotification(n.Extra)
+			if err != nil {
+				logger.Warn("Ignoring malformed progress notification", "payload", n.Extra, "error", err)
+				continue
+			}
+
+			select {
+			case backlog <- pn:
+			default:
+				logger.Warn("Progress notification backlog full, dropping until resync", "backlog_size", cap(backlog))
+				resync = backlog
+			}
+
+		case <-ticker.C:
+			// Ping in the background; a failure makes pq reconnect and emit a nil notification
+			go func() { _ = ping() }()
+		}
+	}
+}
+
+// deliver calls handler for each backlog entry until ctx is done.
+func deliver(ctx context.Context, backlog <-chan ProgressNotification, handler func(ProgressNotification)) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case n := <-backlog:
+			handler(n)
+		}
+	}
+}
@@ -615,7 +615,7 @@ func BenchmarkBatchUpsertGoalActive_ProgressPreservation(b *testing.B) {
 			GoalID:      fmt.Sprintf("goal-%d", j),
 			ChallengeID: "m4-challenge",
 			Namespace:   "test",
-			Progress:    7 + j, // Different progress for each goal
+			Progress:    int64(7 + j), // Different progress for each goal
 			Status:      domain.GoalStatusInProgress,
 			IsActive:    true,
 			AssignedAt:  &now,
@@ -674,7 +674,7 @@ func BenchmarkBatchUpsertGoalActive_ProgressPreservation(b *testing.B) {
 			if err != nil {
 				b.Fatalf("GetProgress failed: %v", err)
 			}
-			expectedProgress := 7 + j
+			expectedProgress := int64(7 + j)
 			if result.Progress != expectedProgress {
 				// Note: Progress preservation works via UPDATE path.
 				// If INSERT path is hit, progress resets to 0 (normal behavior for new records).
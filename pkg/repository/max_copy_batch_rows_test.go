@@ -0,0 +1,102 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/AccelByte/extend-challenge-common/pkg/domain"
+)
+
+func TestWithMaxCopyBatchRows(t *testing.T) {
+	if got := NewPostgresGoalRepository(nil).maxCopyBatchRows; got != DefaultMaxCopyBatchRows {
+		t.Errorf("default maxCopyBatchRows = %d, want %d", got, DefaultMaxCopyBatchRows)
+	}
+	if got := NewPostgresGoalRepository(nil, WithMaxCopyBatchRows(0)).maxCopyBatchRows; got != DefaultMaxCopyBatchRows {
+		t.Errorf("WithMaxCopyBatchRows(0) = %d, want the default", got)
+	}
+	if got := NewPostgresGoalRepository(nil, WithMaxCopyBatchRows(3)).maxCopyBatchRows; got != 3 {
+		t.Errorf("WithMaxCopyBatchRows(3) = %d, want 3", got)
+	}
+}
+
+func TestPostgresGoalRepository_BatchUpsertProgressWithCOPY_Chunked(t *testing.T) {
+	db := setupTestDB(t)
+	if db == nil {
+		return
+	}
+	defer cleanupTestDB(t, db)
+
+	ctx := context.Background()
+	repo := NewPostgresGoalRepository(db, WithMaxCopyBatchRows(3))
+
+	batch := func(userID string, rows int) []*domain.UserGoalProgress {
+		updates := make([]*domain.UserGoalProgress, rows)
+		for i := range updates {
+			updates[i] = &domain.UserGoalProgress{
+				UserID: userID, GoalID: fmt.Sprintf("goal-%d", i), ChallengeID: "c1", Namespace: "test",
+				Progress: int64(i + 1), Status: domain.GoalStatusInProgress,
+			}
+		}
+		return updates
+	}
+
+	assertProgress := func(t *testing.T, userID string, want []*domain.UserGoalProgress) {
+		t.Helper()
+		progress, err := repo.GetUserProgress(ctx, userID, false)
+		if err != nil {
+			t.Fatalf("GetUserProgress failed: %v", err)
+		}
+		if len(progress) != len(want) {
+			t.Fatalf("got %d rows, want %d", len(progress), len(want))
+		}
+		byGoal := make(map[string]int64, len(progress))
+		for _, p := range progress {
+			byGoal[p.GoalID] = p.Progress
+		}
+		for _, w := range want {
+			if byGoal[w.GoalID] != w.Progress {
+				t.Errorf("%s progress = %d, want %d", w.GoalID, byGoal[w.GoalID], w.Progress)
+			}
+		}
+	}
+
+	// Below, at, just above and at twice the cap plus one
+	for _, rows := range []int{2, 3, 4, 7} {
+		t.Run(fmt.Sprintf("%d rows", rows), func(t *testing.T) {
+			userID := fmt.Sprintf("copy-chunk-%d", rows)
+
+			// The pool upsert only updates assigned rows
+			assigned := batch(userID, rows)
+			for _, u := range assigned {
+				u.Progress, u.Status, u.IsActive = 0, domain.GoalStatusNotStarted, true
+			}
+			if err := repo.BulkInsert(ctx, assigned); err != nil {
+				t.Fatalf("BulkInsert failed: %v", err)
+			}
+
+			updates := batch(userID, rows)
+			if err := repo.BatchUpsertProgressWithCOPY(ctx, updates); err != nil {
+				t.Fatalf("BatchUpsertProgressWithCOPY failed: %v", err)
+			}
+			assertProgress(t, userID, updates)
+
+			// Later cycles of a transactional flush must not re-merge earlier chunks
+			tx, err := repo.BeginTx(ctx)
+			if err != nil {
+				t.Fatalf("BeginTx failed: %v", err)
+			}
+			for _, u := range updates {
+				u.Progress += 100
+			}
+			if err := tx.BatchUpsertProgressWithCOPY(ctx, updates); err != nil {
+				_ = tx.Rollback()
+				t.Fatalf("transactional BatchUpsertProgressWithCOPY failed: %v", err)
+			}
+			if err := tx.Commit(); err != nil {
+				t.Fatalf("Commit failed: %v", err)
+			}
+			assertProgress(t, userID, updates)
+		})
+	}
+}
@@ -0,0 +1,102 @@
+package repository
+
+import (
+	"github.com/AccelByte/extend-challenge-common/pkg/domain"
+)
+
+// MergeStrategy selects which entry a batch upsert keeps when it receives several entries
+// for the same (UserID, GoalID), e.g. a stat snapshot and a correction landing in the same
+// flush window. A single statement can write a row only once, so the batch upserts
+// collapse duplicates before any SQL runs (see WithMergeStrategy).
+type MergeStrategy int
+
+const (
+	// MergeHighestProgress keeps the entry with the highest Progress. On a tie the entry
+	// with the latest CompletedAt wins (a completed entry beats an uncompleted one), and
+	// on a full tie the later entry in the batch. This is the default: absolute progress
+	// only moves forward, so the highest snapshot is the most recent one.
+	MergeHighestProgress MergeStrategy = iota
+
+	// MergeLastWins keeps the last entry in batch order, for callers that order their
+	// batch and may lower progress on purpose (corrections).
+	MergeLastWins
+)
+
+// String returns the strategy name used in logs.
+func (s MergeStrategy) String() string {
+	switch s {
+	case MergeHighestProgress:
+		return "highest_progress"
+	case MergeLastWins:
+		return "last_wins"
+	default:
+		return "unknown"
+	}
+}
+
+// prefers reports whether candidate, which comes after current in the batch, replaces it.
+func (s MergeStrategy) prefers(current, candidate *domain.UserGoalProgress) bool {
+	if s == MergeLastWins {
+		return true
+	}
+
+	if candidate.Progress != current.Progress {
+		return candidate.Progress > current.Progress
+	}
+	switch {
+	case candidate.CompletedAt == nil:
+		return current.CompletedAt == nil
+	case current.CompletedAt == nil:
+		return true
+	default:
+		return !candidate.CompletedAt.Before(*current.CompletedAt)
+	}
+}
+
+// mergeDuplicateUpdates returns updates with one entry per (UserID, GoalID), chosen by
+// strategy and kept at the position of the key's first entry, and the number of entries
+// dropped. Returns updates itself when there are no duplicates; the caller's slice is
+// never modified.
+func mergeDuplicateUpdates(updates []*domain.UserGoalProgress, strategy MergeStrategy) ([]*domain.UserGoalProgress, int) {
+	type key struct{ userID, goalID string }
+
+	positions := make(map[key]int, len(updates))
+	var merged []*domain.UserGoalProgress // Allocated at the first duplicate
+	for i, u := range updates {
+		k := key{u.UserID, u.GoalID}
+		pos, seen := positions[k]
+		if !seen {
+			positions[k] = len(positions)
+			if merged != nil {
+				merged = append(merged, u)
+			}
+			continue
+		}
+
+		if merged == nil {
+			merged = append(make([]*domain.UserGoalProgress, 0, len(updates)-1), updates[:i]...)
+		}
+		if strategy.prefers(merged[pos], u) {
+			merged[pos] = u
+		}
+	}
+
+	if merged == nil {
+		return updates, 0
+	}
+	return merged, len(updates) - len(merged)
+}
+
+// mergeUpdates collapses duplicate entries of a batch upsert with the repository's merge
+// strategy and logs how many were dropped.
+func (r *PostgresGoalRepository) mergeUpdates(operation string, updates []*domain.UserGoalProgress) ([]*domain.UserGoalProgress, int) {
+	updates, duplicates := mergeDuplicateUpdates(updates, r.mergeStrategy)
+	if duplicates > 0 {
+		r.logger.Info("Merged duplicate progress updates",
+			"operation", operation,
+			"duplicates", duplicates,
+			"strategy", r.mergeStrategy.String(),
+		)
+	}
+	return updates, duplicates
+}
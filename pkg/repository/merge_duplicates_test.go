@@ -0,0 +1,184 @@
+package repository
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/AccelByte/extend-challenge-common/pkg/domain"
+)
+
+func TestMergeDuplicateUpdates(t *testing.T) {
+	earlier := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	later := earlier.Add(time.Minute)
+	row := func(userID, goalID string, progress int64, completedAt *time.Time) *domain.UserGoalProgress {
+		return &domain.UserGoalProgress{UserID: userID, GoalID: goalID, Progress: progress, CompletedAt: completedAt}
+	}
+
+	t.Run("no duplicates returns the input", func(t *testing.T) {
+		updates := []*domain.UserGoalProgress{row("u1", "g1", 1, nil), row("u1", "g2", 2, nil), row("u2", "g1", 3, nil)}
+		merged, duplicates := mergeDuplicateUpdates(updates, MergeHighestProgress)
+		if duplicates != 0 || len(merged) != 3 || &merged[0] != &updates[0] {
+			t.Errorf("merged = %d rows, %d duplicates; want the input slice unchanged", len(merged), duplicates)
+		}
+	})
+
+	snapshot := row("u1", "g1", 10, nil)
+	correction := row("u1", "g1", 7, nil)
+	completedLate := row("u1", "g2", 5, &later)
+	completedEarly := row("u1", "g2", 5, &earlier)
+	uncompleted := row("u1", "g2", 5, nil)
+	other := row("u2", "g1", 1, nil)
+	updates := []*domain.UserGoalProgress{snapshot, completedLate, other, correction, uncompleted, completedEarly}
+
+	tests := []struct {
+		name     string
+		strategy MergeStrategy
+		want     []*domain.UserGoalProgress
+	}{
+		{
+			name:     "highest progress, then latest completion",
+			strategy: MergeHighestProgress,
+			want:     []*domain.UserGoalProgress{snapshot, completedLate, other},
+		},
+		{
+			name:     "last wins",
+			strategy: MergeLastWins,
+			want:     []*domain.UserGoalProgress{correction, completedEarly, other},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			input := append([]*domain.UserGoalProgress(nil), updates...)
+			merged, duplicates := mergeDuplicateUpdates(input, tt.strategy)
+
+			if duplicates != 3 {
+				t.Errorf("duplicates = %d, want 3", duplicates)
+			}
+			if len(merged) != len(tt.want) {
+				t.Fatalf("merged = %d rows, want %d", len(merged), len(tt.want))
+			}
+			for i := range tt.want {
+				if merged[i] != tt.want[i] {
+					t.Errorf("merged[%d] = %+v, want %+v", i, merged[i], tt.want[i])
+				}
+			}
+			for i := range updates {
+				if input[i] != updates[i] {
+					t.Fatal("caller's slice was modified")
+				}
+			}
+		})
+	}
+
+	t.Run("full tie keeps the later entry", func(t *testing.T) {
+		first, second := row("u1", "g1", 3, nil), row("u1", "g1", 3, nil)
+		merged, _ := mergeDuplicateUpdates([]*domain.UserGoalProgress{first, second}, MergeHighestProgress)
+		if merged[0] != second {
+			t.Error("full tie kept the earlier entry, want the later one")
+		}
+	})
+}
+
+func TestPostgresGoalRepository_BatchUpsert_MergesDuplicates(t *testing.T) {
+	db := setupTestDB(t)
+	if db == nil {
+		return
+	}
+	defer cleanupTestDB(t, db)
+
+	ctx := context.Background()
+
+	// A snapshot and a lower correction for the same row in one batch
+	batch := func(userID string) []*domain.UserGoalProgress {
+		return []*domain.UserGoalProgress{
+			{UserID: userID, GoalID: "kills", ChallengeID: "c1", Namespace: "test", Progress: 10, Status: domain.GoalStatusInProgress},
+			{UserID: userID, GoalID: "wins", ChallengeID: "c1", Namespace: "test", Progress: 1, Status: domain.GoalStatusInProgress},
+			{UserID: userID, GoalID: "kills", ChallengeID: "c1", Namespace: "test", Progress: 7, Status: domain.GoalStatusInProgress},
+		}
+	}
+
+	paths := []struct {
+		name  string
+		flush func(repo *PostgresGoalRepository, updates []*domain.UserGoalProgress) error
+	}{
+		{"multi-VALUES", func(repo *PostgresGoalRepository, updates []*domain.UserGoalProgress) error {
+			return repo.BatchUpsertProgress(ctx, updates)
+		}},
+		{"COPY", func(repo *PostgresGoalRepository, updates []*domain.UserGoalProgress) error {
+			return repo.BatchUpsertProgressWithCOPY(ctx, updates)
+		}},
+		{"transactional COPY", func(repo *PostgresGoalRepository, updates []*domain.UserGoalProgress) error {
+			tx, err := repo.BeginTx(ctx)
+			if err != nil {
+				return err
+			}
+			if err := tx.BatchUpsertProgressWithCOPY(ctx, updates); err != nil {
+				_ = tx.Rollback()
+				return err
+			}
+			return tx.Commit()
+		}},
+	}
+	strategies := []struct {
+		strategy     MergeStrategy
+		wantProgress int64
+	}{
+		{MergeHighestProgress, 10},
+		{MergeLastWins, 7},
+	}
+
+	for _, path := range paths {
+		for _, s := range strategies {
+			t.Run(path.name+"/"+s.strategy.String(), func(t *testing.T) {
+				repo := NewPostgresGoalRepository(db, WithMergeStrategy(s.strategy))
+				userID := "merge-" + path.name + "-" + s.strategy.String()
+
+				// The pool upserts only update assigned rows
+				assigned := batch(userID)[:2]
+				for _, u := range assigned {
+					u.Progress, u.Status, u.IsActive = 0, domain.GoalStatusNotStarted, true
+				}
+				if err := repo.BulkInsert(ctx, assigned); err != nil {
+					t.Fatalf("BulkInsert failed: %v", err)
+				}
+
+				if err := path.flush(repo, batch(userID)); err != nil {
+					t.Fatalf("flush with duplicates failed: %v", err)
+				}
+
+				progress, err := repo.GetProgressRequired(ctx, userID, "kills")
+				if err != nil {
+					t.Fatalf("GetProgressRequired failed: %v", err)
+				}
+				if progress.Progress != s.wantProgress {
+					t.Errorf("progress = %d, want %d", progress.Progress, s.wantProgress)
+				}
+			})
+		}
+	}
+
+	t.Run("FlushLarge counts duplicates", func(t *testing.T) {
+		repo := NewPostgresGoalRepository(db)
+		updates := batch("merge-large")
+		assigned := batch("merge-large")[:2]
+		for _, u := range assigned {
+			u.Progress, u.Status, u.IsActive = 0, domain.GoalStatusNotStarted, true
+		}
+		if err := repo.BulkInsert(ctx, assigned); err != nil {
+			t.Fatalf("BulkInsert failed: %v", err)
+		}
+
+		result, err := repo.FlushLarge(ctx, updates, LargeFlushOptions{ChunkSize: 1})
+		if err != nil {
+			t.Fatalf("FlushLarge failed: %v", err)
+		}
+		if result.Rows != 2 || result.Duplicates != 1 {
+			t.Errorf("result = %+v, want 2 rows and 1 duplicate", result)
+		}
+		progress, _ := repo.GetProgress(ctx, "merge-large", "kills")
+		if progress == nil || progress.Progress != 10 {
+			t.Errorf("progress = %+v, want the highest entry (10) across chunks", progress)
+		}
+	})
+}
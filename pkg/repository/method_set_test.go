@@ -0,0 +1,102 @@
+package repository
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// TestRepositoryMethodSets guards against the pool and transaction repositories drifting
+// apart: both must expose the same exported methods, apart from the known exceptions below.
+func TestRepositoryMethodSets(t *testing.T) {
+	// Cross-user and diagnostic reads are pool-only by design
+	poolOnly := interfaceMethods(
+		reflect.TypeOf((*ProgressHistoryRepository)(nil)).Elem(),
+		reflect.TypeOf((*ProgressFeedRepository)(nil)).Elem(),
+		reflect.TypeOf((*FlushPreviewRepository)(nil)).Elem(),
+		reflect.TypeOf((*ClaimForfeitRepository)(nil)).Elem(),
+		reflect.TypeOf((*ClaimReservationSweeper)(nil)).Elem(),
+		reflect.TypeOf((*RecurringGoalResetter)(nil)).Elem(),
+		reflect.TypeOf((*ProgressSampler)(nil)).Elem(),
+		reflect.TypeOf((*LargeFlusher)(nil)).Elem(),
+		reflect.TypeOf((*CompletionReevaluator)(nil)).Elem(),
+	)
+	poolOnly["VerifyIndexes"] = true
+
+	// TxRepository methods beyond GoalRepository only make sense inside a transaction
+	txOnly := interfaceMethods(reflect.TypeOf((*TxRepository)(nil)).Elem())
+	for name := range interfaceMethods(reflect.TypeOf((*GoalRepository)(nil)).Elem()) {
+		delete(txOnly, name)
+	}
+
+	poolType := reflect.TypeOf((*PostgresGoalRepository)(nil))
+	txType := reflect.TypeOf((*PostgresTxRepository)(nil))
+	pool := exportedMethods(poolType)
+	tx := exportedMethods(txType)
+
+	for name, m := range pool {
+		if poolOnly[name] {
+			if _, ok := tx[name]; ok {
+				t.Errorf("%s is listed as pool-only but PostgresTxRepository implements it", name)
+			}
+			continue
+		}
+		txMethod, ok := tx[name]
+		if !ok {
+			t.Errorf("PostgresTxRepository is missing %s", name)
+			continue
+		}
+		// Compare signatures without the receiver
+		if got, want := methodSignature(txMethod.Type), methodSignature(m.Type); got != want {
+			t.Errorf("%s signature mismatch: tx %s, pool %s", name, got, want)
+		}
+	}
+
+	for name := range tx {
+		if _, ok := pool[name]; !ok && !txOnly[name] {
+			t.Errorf("PostgresGoalRepository is missing %s", name)
+		}
+	}
+
+	for _, names := range []map[string]bool{poolOnly, txOnly} {
+		for name := range names {
+			if _, inPool := pool[name]; !inPool {
+				if _, inTx := tx[name]; !inTx {
+					t.Errorf("%s is listed as an exception but neither repository implements it", name)
+				}
+			}
+		}
+	}
+}
+
+func interfaceMethods(types ...reflect.Type) map[string]bool {
+	names := make(map[string]bool)
+	for _, typ := range types {
+		for i := 0; i < typ.NumMethod(); i++ {
+			names[typ.Method(i).Name] = true
+		}
+	}
+	return names
+}
+
+func exportedMethods(typ reflect.Type) map[string]reflect.Method {
+	methods := make(map[string]reflect.Method, typ.NumMethod())
+	for i := 0; i < typ.NumMethod(); i++ {
+		m := typ.Method(i)
+		methods[m.Name] = m
+	}
+	return methods
+}
+
+// methodSignature renders a method type without its receiver.
+func methodSignature(fn reflect.Type) string {
+	in := make([]string, 0, fn.NumIn()-1)
+	for i := 1; i < fn.NumIn(); i++ {
+		in = append(in, fn.In(i).String())
+	}
+	out := make([]string, 0, fn.NumOut())
+	for i := 0; i < fn.NumOut(); i++ {
+		out = append(out, fn.Out(i).String())
+	}
+	return "(" + strings.Join(in, ", ") + ") (" + strings.Join(out, ", ") + ")"
+}
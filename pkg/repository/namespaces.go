@@ -0,0 +1,35 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/AccelByte/extend-challenge-common/pkg/errors"
+)
+
+// getNamespacesQuery lists every namespace with progress rows.
+const getNamespacesQuery = `SELECT DISTINCT namespace FROM user_goal_progress ORDER BY namespace`
+
+// GetNamespaces returns the distinct namespaces present in user_goal_progress. The DISTINCT
+// is backed by the leading namespace column of idx_user_goal_progress_feed (migration 011);
+// migration 009 adds no index of its own.
+func (r *PostgresGoalRepository) GetNamespaces(ctx context.Context) ([]string, error) {
+	rows, err := r.db.QueryContext(ctx, getNamespacesQuery)
+	if err != nil {
+		return nil, errors.ErrDatabaseError("get namespaces", err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	namespaces := []string{}
+	for rows.Next() {
+		var namespace string
+		if err := rows.Scan(&namespace); err != nil {
+			return nil, errors.ErrDatabaseError("scan namespace", err)
+		}
+		namespaces = append(namespaces, namespace)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, errors.ErrDatabaseError("iterate namespaces", err)
+	}
+
+	return namespaces, nil
+}
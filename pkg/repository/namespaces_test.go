@@ -0,0 +1,45 @@
+package repository
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/AccelByte/extend-challenge-common/pkg/domain"
+)
+
+func TestPostgresGoalRepository_GetNamespaces(t *testing.T) {
+	db := setupTestDB(t)
+	if db == nil {
+		return
+	}
+	defer cleanupTestDB(t, db)
+
+	ctx := context.Background()
+	repo := NewPostgresGoalRepository(db)
+
+	namespaces, err := repo.GetNamespaces(ctx)
+	if err != nil {
+		t.Fatalf("GetNamespaces failed: %v", err)
+	}
+	if namespaces == nil || len(namespaces) != 0 {
+		t.Errorf("GetNamespaces() on empty table = %v, want empty slice", namespaces)
+	}
+
+	err = repo.BulkInsert(ctx, []*domain.UserGoalProgress{
+		{UserID: "ns-user-1", GoalID: "g1", ChallengeID: "c1", Namespace: "tenant-b", Status: domain.GoalStatusNotStarted, IsActive: true},
+		{UserID: "ns-user-2", GoalID: "g1", ChallengeID: "c1", Namespace: "tenant-a", Status: domain.GoalStatusNotStarted, IsActive: true},
+		{UserID: "ns-user-2", GoalID: "g2", ChallengeID: "c1", Namespace: "tenant-a", Status: domain.GoalStatusNotStarted, IsActive: false},
+	})
+	if err != nil {
+		t.Fatalf("BulkInsert failed: %v", err)
+	}
+
+	namespaces, err = repo.GetNamespaces(ctx)
+	if err != nil {
+		t.Fatalf("GetNamespaces failed: %v", err)
+	}
+	if want := []string{"tenant-a", "tenant-b"}; !reflect.DeepEqual(namespaces, want) {
+		t.Errorf("GetNamespaces() = %v, want %v", namespaces, want)
+	}
+}
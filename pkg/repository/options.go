@@ -0,0 +1,267 @@
+package repository
+
+import (
+	"log/slog"
+	"time"
+)
+
+// Option configures a PostgresGoalRepository.
+// Options are passed to NewPostgresGoalRepository; transactions inherit the parent's options.
+type Option func(*PostgresGoalRepository)
+
+// WithMaxIncrementDelta sets the cap on |delta| accepted by the increment methods.
+// Values <= 0 fall back to DefaultMaxIncrementDelta.
+func WithMaxIncrementDelta(maxDelta int) Option {
+	return func(r *PostgresGoalRepository) {
+		if maxDelta > 0 {
+			r.maxIncrementDelta = maxDelta
+		}
+	}
+}
+
+// WithProgressCeiling sets the highest progress an increment may produce, so counters stop
+// short of the limit of the progress column. IncrementProgress fails with
+// errors.ErrCodeProgressOverflow when it would push a row past the ceiling; the batch
+// increments skip and log only the offending entries and apply the rest. Values <= 0 or
+// above the column's clamp (MaxProgress, or MaxBigIntProgress with WithBigIntProgress)
+// fall back to DefaultProgressCeiling or DefaultBigIntProgressCeiling.
+func WithProgressCeiling(ceiling int64) Option {
+	return func(r *PostgresGoalRepository) {
+		r.progressCeiling = ceiling
+	}
+}
+
+// WithBigIntProgress declares that the progress column is BIGINT (migration 013), raising
+// the SQL clamp from MaxProgress to MaxBigIntProgress and the default progress ceiling to
+// DefaultBigIntProgressCeiling. Leave it off (the default) for schemas that still use INT:
+// the queries work with either column type, but progress above MaxProgress fails to store
+// in an INT column.
+func WithBigIntProgress(enabled bool) Option {
+	return func(r *PostgresGoalRepository) {
+		r.bigIntProgress = enabled
+	}
+}
+
+// WithOverflowPolicy sets how far increments may push progress past a goal's target
+// (see OverflowPolicy). Applies to IncrementProgress and to batch entries whose
+// ProgressIncrement.OverflowPolicy is the zero value. The zero value keeps the default,
+// AllowUnbounded.
+func WithOverflowPolicy(policy OverflowPolicy) Option {
+	return func(r *PostgresGoalRepository) {
+		if policy.multiple != 0 {
+			r.overflowPolicy = policy
+		}
+	}
+}
+
+// WithMergeStrategy sets which entry BatchUpsertProgress, BatchUpsertProgressWithCOPY and
+// FlushLarge keep when a batch has several entries for the same user and goal. The default
+// is MergeHighestProgress. Collapsed entries are logged, and counted by FlushLarge in
+// BatchResult.Duplicates.
+func WithMergeStrategy(strategy MergeStrategy) Option {
+	return func(r *PostgresGoalRepository) {
+		r.mergeStrategy = strategy
+	}
+}
+
+// WithMaxCopyBatchRows caps the rows BatchUpsertProgressWithCOPY stages in its temp table
+// at once. Larger batches are written in several COPY+merge cycles of at most maxRows
+// rows within the same transaction, so the whole batch still commits or rolls back
+// together while the temp table and each merge statement stay bounded. Values <= 0 fall
+// back to DefaultMaxCopyBatchRows.
+func WithMaxCopyBatchRows(maxRows int) Option {
+	return func(r *PostgresGoalRepository) {
+		if maxRows > 0 {
+			r.maxCopyBatchRows = maxRows
+		}
+	}
+}
+
+// WithClaimReservationTTL sets how long ReserveClaim holds a goal before
+// ReleaseExpiredClaimReservations may return it to 'completed'. Set it above the reward
+// grant timeout so a slow grant is not released while it is still running.
+// Values <= 0 fall back to DefaultClaimReservationTTL.
+func WithClaimReservationTTL(ttl time.Duration) Option {
+	return func(r *PostgresGoalRepository) {
+		if ttl > 0 {
+			r.claimReservationTTL = ttl
+		}
+	}
+}
+
+// WithMaxDeltaPerEvent sets the default per-event cap on positive increment deltas.
+// A larger delta is clamped to maxDelta (not rejected) before it is applied, which
+// contains exploits that report inflated deltas. Applies to IncrementProgress and to
+// batch entries whose ProgressIncrement.MaxDeltaPerEvent is 0. Values <= 0 disable it
+// (the default).
+func WithMaxDeltaPerEvent(maxDelta int) Option {
+	return func(r *PostgresGoalRepository) {
+		r.maxDeltaPerEvent = maxDelta
+	}
+}
+
+// WithRequireAssignmentForIncrements makes every increment UPDATE-only and gated on
+// is_active = true, in transactions as well as on the pool, so an event for a goal the
+// user was never assigned (or was unassigned from) creates no row (M3 assignment
+// semantics). BatchIncrementProgressWithResult reports the dropped entries in
+// BatchIncrementResult.SkippedUnassigned.
+//
+// Disabled by default for compatibility: pool increments are already UPDATE-only, but
+// transactional increments insert a missing row and update inactive ones.
+func WithRequireAssignmentForIncrements(enabled bool) Option {
+	return func(r *PostgresGoalRepository) {
+		r.requireAssignment = enabled
+	}
+}
+
+// WithMaxEventLateness sets how old a batch entry's OccurredAt may be before the entry
+// is skipped. Values <= 0 fall back to DefaultMaxEventLateness.
+func WithMaxEventLateness(maxLateness time.Duration) Option {
+	return func(r *PostgresGoalRepository) {
+		if maxLateness > 0 {
+			r.maxEventLateness = maxLateness
+		}
+	}
+}
+
+// WithStrictIncrementValidation controls how batch increments handle invalid entries.
+// Strict (the default) rejects the whole batch with ErrInvalidIncrement; lenient
+// drops invalid entries and applies the rest.
+// Single increments (IncrementProgress) are always rejected when invalid.
+func WithStrictIncrementValidation(strict bool) Option {
+	return func(r *PostgresGoalRepository) {
+		r.strictIncrementValidation = strict
+	}
+}
+
+// WithLazyExpireOnRead makes active-only reads also exclude rows past expires_at, so an
+// expired goal stops being returned as active the moment it is read, before anything
+// flips its is_active flag. Applies to GetUserProgress and GetChallengeProgress with
+// activeOnly, GetActiveGoals, page reads with PageOptions.ActiveOnly and
+// GetGoalsByIDsFiltered with ProgressFilter.ActiveOnly. Disabled by default; see
+// lazy_expiry.go for the trade-off versus an explicit expiry job.
+func WithLazyExpireOnRead(enabled bool) Option {
+	return func(r *PostgresGoalRepository) {
+		r.lazyExpireOnRead = enabled
+	}
+}
+
+// WithAllowBulkDelete enables destructive bulk deletes such as DeleteChallengeProgress.
+// Disabled by default so service code cannot purge progress by accident; enable it only
+// in operator tooling.
+func WithAllowBulkDelete(enabled bool) Option {
+	return func(r *PostgresGoalRepository) {
+		r.allowBulkDelete = enabled
+	}
+}
+
+// WithLogger sets the logger used for repository diagnostics (e.g., slow query plans).
+// Defaults to slog.Default().
+func WithLogger(logger *slog.Logger) Option {
+	return func(r *PostgresGoalRepository) {
+		if logger != nil {
+			r.logger = logger
+		}
+	}
+}
+
+// WithExplainOnSlow logs the EXPLAIN plan of batch increment queries that take longer
+// than threshold. Intended for diagnosing the occasional slow BatchIncrementProgress;
+// it adds an extra round trip only for queries that were already slow. A threshold <= 0
+// disables it (the default).
+func WithExplainOnSlow(threshold time.Duration) Option {
+	return func(r *PostgresGoalRepository) {
+		r.explainThreshold = threshold
+	}
+}
+
+// WithLastDelta declares that the last_delta column exists (migration 019) and makes the
+// increments record the delta they applied in it, next to updated_at. GetProgress,
+// GetProgressRequired and GetProgressForUpdate then return it in
+// UserGoalProgress.LastDelta; other reads leave LastDelta nil. Leave it off (the default)
+// for schemas without the column. See last_delta.go for which increments record a delta.
+func WithLastDelta(enabled bool) Option {
+	return func(r *PostgresGoalRepository) {
+		r.lastDelta = enabled
+	}
+}
+
+// WithCompletions declares that the completions column exists (migration 018), so progress
+// reads return it in UserGoalProgress.Completions. Without it (the default) reads leave
+// Completions 0 and do not reference the column, so schemas without migration 018 keep
+// working. IncrementRepeatableProgress always uses the column: apply the migration before
+// configuring repeatable goals.
+func WithCompletions(enabled bool) Option {
+	return func(r *PostgresGoalRepository) {
+		r.completions = enabled
+	}
+}
+
+// WithPerUserLocking makes the batch flush methods (BatchUpsertProgressWithCOPY,
+// BatchIncrementProgress, BatchIncrementProgressReturning) take a transaction-scoped
+// advisory lock per user before writing. Concurrent batches that touch the same user
+// then run one after another, so an absolute snapshot merge and an increment cannot
+// interleave; batches over disjoint users still run in parallel.
+//
+// Tradeoff: every batch pays one extra round trip for the locks, pool increments run
+// in an explicit transaction instead of a single statement, and hot users (present in
+// most batches) effectively serialize flushes across consumers. Disabled by default.
+func WithPerUserLocking(enabled bool) Option {
+	return func(r *PostgresGoalRepository) {
+		r.perUserLocking = enabled
+	}
+}
+
+// WithGate registers batch writes and transactions with gate so a shutdown can drain
+// them before closing the pool (see db.Shutdown). Batch methods hold the gate for the
+// duration of the call; transactions hold it from BeginTx until Commit or Rollback.
+// Once the gate starts draining these calls fail with errors.ErrCodeShuttingDown.
+func WithGate(gate Gate) Option {
+	return func(r *PostgresGoalRepository) {
+		r.gate = gate
+	}
+}
+
+// WithCostReporter reports the namespace, size and duration of every batch write
+// (the batch upserts, inserts and increments) to reporter after it finishes, for
+// per-tenant cost attribution. Transactional batch writes are reported too.
+func WithCostReporter(reporter CostReporter) Option {
+	return func(r *PostgresGoalRepository) {
+		r.costReporter = reporter
+	}
+}
+
+// WithLimiter consults limiter before every batch write (the batch upserts, inserts and
+// increments, on the pool or in a transaction). A denied write fails with
+// errors.ErrCodeRateLimited before any SQL runs. The namespace is taken from the batch
+// entries, so with a limiter a batch must not mix namespaces; such a batch fails with
+// errors.ErrCodeValidationFailed. Reads and single-row writes are not limited.
+func WithLimiter(limiter Limiter) Option {
+	return func(r *PostgresGoalRepository) {
+		r.limiter = limiter
+	}
+}
+
+// WithStartupChecks runs VerifyIndexes when the repository is constructed and logs each
+// missing index as a warning through the configured logger. Startup never fails because
+// of it; a missing index only shows up as latency, so the warning is the point.
+func WithStartupChecks() Option {
+	return func(r *PostgresGoalRepository) {
+		r.startupChecks = true
+	}
+}
+
+// WithConfigChecksum stamps the batch write paths (BatchUpsertProgress,
+// BatchUpsertProgressWithCOPY and the batch increments) with checksum() in the
+// config_checksum column, typically wired to InMemoryGoalCache.ConfigChecksum so rows
+// record which config wrote them across reloads. checksum is called once per batch.
+// Rows keep their previous value when it returns "".
+//
+// The option also declares that the column exists (migration 007): progress reads return
+// it in UserGoalProgress.ConfigChecksum. Without it (the default) no query references the
+// column, so schemas without migration 007 keep working and ConfigChecksum stays nil.
+func WithConfigChecksum(checksum func() string) Option {
+	return func(r *PostgresGoalRepository) {
+		r.configChecksum = checksum
+	}
+}
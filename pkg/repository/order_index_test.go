@@ -0,0 +1,83 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/AccelByte/extend-challenge-common/pkg/domain"
+)
+
+func TestPostgresGoalRepository_OrderIndex(t *testing.T) {
+	db := setupTestDB(t)
+	if db == nil {
+		return
+	}
+	defer cleanupTestDB(t, db)
+
+	repo := NewPostgresGoalRepository(db)
+	ctx := context.Background()
+
+	assign := func(goalID string, order int) *domain.UserGoalProgress {
+		return &domain.UserGoalProgress{UserID: "order-user", GoalID: goalID, ChallengeID: "c1", Namespace: "test",
+			Status: domain.GoalStatusNotStarted, IsActive: true, OrderIndex: order}
+	}
+
+	// Each assignment path carries the order; rows are created out of order on purpose
+	if err := repo.BulkInsert(ctx, []*domain.UserGoalProgress{assign("third", 30), assign("first", 10)}); err != nil {
+		t.Fatalf("BulkInsert failed: %v", err)
+	}
+	if _, err := repo.EnsureAssigned(ctx, []*domain.UserGoalProgress{assign("unordered", 0), assign("fourth", 40)}); err != nil {
+		t.Fatalf("EnsureAssigned failed: %v", err)
+	}
+	if err := repo.UpsertGoalActive(ctx, assign("second", 20)); err != nil {
+		t.Fatalf("UpsertGoalActive failed: %v", err)
+	}
+	if err := repo.BatchUpsertGoalActive(ctx, []*domain.UserGoalProgress{assign("fifth", 50)}); err != nil {
+		t.Fatalf("BatchUpsertGoalActive failed: %v", err)
+	}
+
+	want := []string{"unordered", "first", "second", "third", "fourth", "fifth"}
+	goalIDs := func(rows []*domain.UserGoalProgress) []string {
+		ids := make([]string, len(rows))
+		for i, p := range rows {
+			ids[i] = p.GoalID
+		}
+		return ids
+	}
+
+	t.Run("user progress in ascending order", func(t *testing.T) {
+		rows, err := repo.GetUserProgress(ctx, "order-user", false)
+		if err != nil {
+			t.Fatalf("GetUserProgress failed: %v", err)
+		}
+		if got := goalIDs(rows); fmt.Sprint(got) != fmt.Sprint(want) {
+			t.Errorf("GetUserProgress order = %v, want %v", got, want)
+		}
+		for _, p := range rows {
+			if p.GoalID == "third" && p.OrderIndex != 30 {
+				t.Errorf("third OrderIndex = %d, want 30", p.OrderIndex)
+			}
+		}
+	})
+
+	t.Run("challenge progress in ascending order", func(t *testing.T) {
+		rows, err := repo.GetChallengeProgress(ctx, "order-user", "c1", true)
+		if err != nil {
+			t.Fatalf("GetChallengeProgress failed: %v", err)
+		}
+		if got := goalIDs(rows); fmt.Sprint(got) != fmt.Sprint(want) {
+			t.Errorf("GetChallengeProgress order = %v, want %v", got, want)
+		}
+	})
+
+	t.Run("bulk insert with COPY carries the order", func(t *testing.T) {
+		if err := repo.BulkInsertWithCOPY(ctx, []*domain.UserGoalProgress{assign("copied", 5)}); err != nil {
+			t.Fatalf("BulkInsertWithCOPY failed: %v", err)
+		}
+		got, err := repo.GetProgress(ctx, "order-user", "copied")
+		if err != nil || got == nil || got.OrderIndex != 5 {
+			t.Errorf("GetProgress = %+v, %v, want OrderIndex 5", got, err)
+		}
+	})
+}
@@ -0,0 +1,296 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/AccelByte/extend-challenge-common/pkg/domain"
+	"github.com/AccelByte/extend-challenge-common/pkg/errors"
+)
+
+// Page size limits for paginated reads.
+const (
+	// DefaultPageLimit is used when PageOptions.Limit is zero or negative.
+	DefaultPageLimit = 100
+
+	// MaxPageLimit caps PageOptions.Limit to keep a single page bounded.
+	MaxPageLimit = 1000
+)
+
+// progressBaseColumns is the part of the scanned column list every schema has (see
+// progressColumns for the optional columns).
+const progressBaseColumns = `user_id, goal_id, challenge_id, namespace, progress, status,
+		       completed_at, claimed_at, created_at, updated_at,
+		       is_active, assigned_at, expires_at, forfeited_at, attempts, order_index`
+
+// progressColumns returns the column list scanned by scanProgressRows: progressBaseColumns
+// followed by the optional columns the repository was configured to read, in the order of
+// progressScanDest.
+func (r *PostgresGoalRepository) progressColumns() string {
+	columns := progressBaseColumns
+	if r.configChecksum != nil {
+		columns += ", config_checksum"
+	}
+	if r.completions {
+		columns += ", completions"
+	}
+	return columns
+}
+
+// ProgressOrder selects the ordering of a paginated progress read.
+type ProgressOrder string
+
+const (
+	// OrderCreatedAtAsc orders by created_at ascending (the order used by GetUserProgress).
+	// This is the default when PageOptions.OrderBy is empty.
+	OrderCreatedAtAsc ProgressOrder = "created_at_asc"
+
+	// OrderExpiresAtAsc orders by expires_at ascending with NULLs (permanent goals) last.
+	// Used by the quest log to show goals expiring soonest first.
+	OrderExpiresAtAsc ProgressOrder = "expires_at_asc"
+
+	// OrderUpdatedAtDesc orders by updated_at descending (most recently touched first).
+	OrderUpdatedAtDesc ProgressOrder = "updated_at_desc"
+)
+
+// IsValid returns true if the ordering is a supported value.
+func (o ProgressOrder) IsValid() bool {
+	switch o {
+	case OrderCreatedAtAsc, OrderExpiresAtAsc, OrderUpdatedAtDesc:
+		return true
+	default:
+		return false
+	}
+}
+
+// orderOrDefault resolves the empty ordering to OrderCreatedAtAsc.
+func (o ProgressOrder) orderOrDefault() ProgressOrder {
+	if o == "" {
+		return OrderCreatedAtAsc
+	}
+	return o
+}
+
+// PageOptions controls a paginated progress read.
+//
+// Cursors are opaque and encode the sort key of the last row returned, so they are only
+// valid for the ordering that produced them. Changing OrderBy invalidates an existing
+// cursor; passing a cursor produced under a different ordering returns an
+// errors.ErrCodeInvalidCursor error instead of silently returning a wrong page.
+type PageOptions struct {
+	Limit      int           // Page size (default DefaultPageLimit, capped at MaxPageLimit)
+	Cursor     string        // Cursor returned by the previous page ("" for the first page)
+	OrderBy    ProgressOrder // Sort order (default OrderCreatedAtAsc)
+	ActiveOnly bool          // If true, only is_active = true (and, with lazy expiry, unexpired) rows are returned
+}
+
+// pageCursor is the decoded form of a page cursor.
+// The sort key is nil for OrderExpiresAtAsc rows without expiry.
+type pageCursor struct {
+	Order   ProgressOrder `json:"o"`
+	SortKey *time.Time    `json:"k,omitempty"`
+	GoalID  string        `json:"g"`
+}
+
+// encodePageCursor builds the opaque cursor for the last row of a page.
+func encodePageCursor(order ProgressOrder, last *domain.UserGoalProgress) string {
+	c := pageCursor{Order: order, GoalID: last.GoalID}
+
+	switch order {
+	case OrderExpiresAtAsc:
+		c.SortKey = last.ExpiresAt
+	case OrderUpdatedAtDesc:
+		updatedAt := last.UpdatedAt
+		c.SortKey = &updatedAt
+	default:
+		createdAt := last.CreatedAt
+		c.SortKey = &createdAt
+	}
+
+	return encodeCursor(c)
+}
+
+// decodePageCursor parses a cursor and verifies it was produced under the requested ordering.
+func decodePageCursor(cursor string, order ProgressOrder) (*pageCursor, error) {
+	c, err := decodeCursor[pageCursor](cursor)
+	if err != nil {
+		return nil, err
+	}
+
+	if c.Order != order {
+		return nil, errors.ErrInvalidCursor(fmt.Sprintf("cursor was created for ordering '%s', not '%s'", c.Order, order))
+	}
+
+	if c.SortKey == nil && order != OrderExpiresAtAsc {
+		return nil, errors.ErrInvalidCursor("missing sort key")
+	}
+
+	return &c, nil
+}
+
+// keysetClause returns the ORDER BY clause and, when a cursor is present, the keyset
+// predicate that continues after it. Placeholders start at argOffset+1.
+func keysetClause(order ProgressOrder, c *pageCursor, argOffset int) (predicate string, args []interface{}, orderBy string) {
+	switch order {
+	case OrderExpiresAtAsc:
+		orderBy = " ORDER BY expires_at ASC NULLS LAST, goal_id ASC"
+		if c == nil {
+			return "", nil, orderBy
+		}
+		if c.SortKey == nil {
+			// Already inside the NULL tail: only later NULL rows remain
+			predicate = fmt.Sprintf(" AND expires_at IS NULL AND goal_id > $%d", argOffset+1)
+			return predicate, []interface{}{c.GoalID}, orderBy
+		}
+		predicate = fmt.Sprintf(
+			" AND (expires_at > $%[1]d OR (expires_at = $%[1]d AND goal_id > $%[2]d) OR expires_at IS NULL)",
+			argOffset+1, argOffset+2,
+		)
+		return predicate, []interface{}{*c.SortKey, c.GoalID}, orderBy
+
+	case OrderUpdatedAtDesc:
+		orderBy = " ORDER BY updated_at DESC, goal_id DESC"
+		if c == nil {
+			return "", nil, orderBy
+		}
+		predicate = fmt.Sprintf(" AND (updated_at, goal_id) < ($%d, $%d)", argOffset+1, argOffset+2)
+		return predicate, []interface{}{*c.SortKey, c.GoalID}, orderBy
+
+	default:
+		orderBy = " ORDER BY created_at ASC, goal_id ASC"
+		if c == nil {
+			return "", nil, orderBy
+		}
+		predicate = fmt.Sprintf(" AND (created_at, goal_id) > ($%d, $%d)", argOffset+1, argOffset+2)
+		return predicate, []interface{}{*c.SortKey, c.GoalID}, orderBy
+	}
+}
+
+// pageLimit resolves the effective page size.
+func pageLimit(limit int) int {
+	if limit <= 0 {
+		return DefaultPageLimit
+	}
+	if limit > MaxPageLimit {
+		return MaxPageLimit
+	}
+	return limit
+}
+
+// encodeCursor builds an opaque, URL-safe cursor from a keyset position.
+func encodeCursor[T any](c T) string {
+	data, _ := json.Marshal(c) // Marshal of a plain struct cannot fail
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+// decodeCursor parses a cursor produced by encodeCursor. Garbage fails with an
+// errors.ErrCodeInvalidCursor error; callers check the decoded key fields.
+func decodeCursor[T any](cursor string) (T, error) {
+	var c T
+
+	data, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return c, errors.ErrInvalidCursor("malformed encoding")
+	}
+	if err := json.Unmarshal(data, &c); err != nil {
+		return c, errors.ErrInvalidCursor("malformed payload")
+	}
+
+	return c, nil
+}
+
+// pageTail finishes a page read with limit+1 rows: it trims the extra row and returns the
+// cursor encode builds from the last kept row, or "" when there is no further page.
+// A page without rows is returned as an empty slice, not nil.
+func pageTail(results []*domain.UserGoalProgress, limit int, encode func(last *domain.UserGoalProgress) string) ([]*domain.UserGoalProgress, string) {
+	if len(results) <= limit {
+		if results == nil {
+			results = []*domain.UserGoalProgress{}
+		}
+		return results, ""
+	}
+
+	results = results[:limit]
+	return results, encode(results[limit-1])
+}
+
+// queryer is the subset of *sql.DB and *sql.Tx used by queries shared between
+// PostgresGoalRepository and PostgresTxRepository.
+type queryer interface {
+	ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error)
+	QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error)
+	QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row
+}
+
+// getProgressPage runs a keyset-paginated progress read scoped to a single user.
+// where is the base WHERE clause using placeholders $1..$len(args).
+func (r *PostgresGoalRepository) getProgressPage(ctx context.Context, q queryer, operation, where string, args []interface{}, opts PageOptions) ([]*domain.UserGoalProgress, string, error) {
+	order := opts.OrderBy.orderOrDefault()
+	if !order.IsValid() {
+		return nil, "", errors.ErrValidationFailed("order_by", fmt.Sprintf("unsupported ordering '%s'", opts.OrderBy))
+	}
+
+	var cursor *pageCursor
+	if opts.Cursor != "" {
+		var err error
+		cursor, err = decodePageCursor(opts.Cursor, order)
+		if err != nil {
+			return nil, "", err
+		}
+	}
+
+	limit := pageLimit(opts.Limit)
+
+	query := "SELECT " + r.progressColumns() + " FROM user_goal_progress WHERE " + where
+	if opts.ActiveOnly {
+		query += r.activeOnlyClause()
+	}
+
+	predicate, cursorArgs, orderBy := keysetClause(order, cursor, len(args))
+	query += predicate + orderBy
+	args = append(args, cursorArgs...)
+
+	// Fetch one extra row to know whether another page exists
+	query += fmt.Sprintf(" LIMIT $%d", len(args)+1)
+	args = append(args, limit+1)
+
+	rows, err := q.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, "", errors.ErrDatabaseError(operation, err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	results, err := r.scanProgressRows(rows)
+	if err != nil {
+		return nil, "", err
+	}
+
+	results, next := pageTail(results, limit, func(last *domain.UserGoalProgress) string {
+		return encodePageCursor(order, last)
+	})
+	return results, next, nil
+}
+
+// GetUserProgressPage retrieves one page of a user's goal progress records.
+func (r *PostgresGoalRepository) GetUserProgressPage(ctx context.Context, userID string, opts PageOptions) ([]*domain.UserGoalProgress, string, error) {
+	return r.getProgressPage(ctx, r.db, "get user progress page", "user_id = $1", []interface{}{userID}, opts)
+}
+
+// GetChallengeProgressPage retrieves one page of a user's goal progress within a challenge.
+func (r *PostgresGoalRepository) GetChallengeProgressPage(ctx context.Context, userID, challengeID string, opts PageOptions) ([]*domain.UserGoalProgress, string, error) {
+	return r.getProgressPage(ctx, r.db, "get challenge progress page", "user_id = $1 AND challenge_id = $2", []interface{}{userID, challengeID}, opts)
+}
+
+// GetUserProgressPage retrieves one page of a user's goal progress records within a transaction.
+func (r *PostgresTxRepository) GetUserProgressPage(ctx context.Context, userID string, opts PageOptions) ([]*domain.UserGoalProgress, string, error) {
+	return r.parent.getProgressPage(ctx, r.tx, "get user progress page in transaction", "user_id = $1", []interface{}{userID}, opts)
+}
+
+// GetChallengeProgressPage retrieves one page of a user's challenge progress within a transaction.
+func (r *PostgresTxRepository) GetChallengeProgressPage(ctx context.Context, userID, challengeID string, opts PageOptions) ([]*domain.UserGoalProgress, string, error) {
+	return r.parent.getProgressPage(ctx, r.tx, "get challenge progress page in transaction", "user_id = $1 AND challenge_id = $2", []interface{}{userID, challengeID}, opts)
+}
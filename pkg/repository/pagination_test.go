@@ -0,0 +1,207 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/AccelByte/extend-challenge-common/pkg/domain"
+	customerrors "github.com/AccelByte/extend-challenge-common/pkg/errors"
+)
+
+func TestPageCursor_RoundTrip(t *testing.T) {
+	now := time.Date(2025, 11, 11, 10, 30, 0, 123456000, time.UTC)
+	row := &domain.UserGoalProgress{GoalID: "goal-7", CreatedAt: now, UpdatedAt: now.Add(time.Hour)}
+
+	for _, order := range []ProgressOrder{OrderCreatedAtAsc, OrderUpdatedAtDesc, OrderExpiresAtAsc} {
+		t.Run(string(order), func(t *testing.T) {
+			cursor := encodePageCursor(order, row)
+
+			decoded, err := decodePageCursor(cursor, order)
+			if err != nil {
+				t.Fatalf("decodePageCursor failed: %v", err)
+			}
+			if decoded.GoalID != "goal-7" {
+				t.Errorf("GoalID = %q, want goal-7", decoded.GoalID)
+			}
+		})
+	}
+}
+
+func TestPageCursor_OrderingMismatch(t *testing.T) {
+	row := &domain.UserGoalProgress{GoalID: "goal-1", CreatedAt: time.Now().UTC()}
+	cursor := encodePageCursor(OrderCreatedAtAsc, row)
+
+	_, err := decodePageCursor(cursor, OrderUpdatedAtDesc)
+
+	var ce *customerrors.ChallengeError
+	if !errors.As(err, &ce) || ce.Code != customerrors.ErrCodeInvalidCursor {
+		t.Fatalf("Expected INVALID_CURSOR error, got %v", err)
+	}
+}
+
+func TestPageCursor_Garbage(t *testing.T) {
+	for _, cursor := range []string{"%%%", "bm90LWpzb24"} {
+		_, err := decodePageCursor(cursor, OrderCreatedAtAsc)
+
+		var ce *customerrors.ChallengeError
+		if !errors.As(err, &ce) || ce.Code != customerrors.ErrCodeInvalidCursor {
+			t.Errorf("Expected INVALID_CURSOR error for %q, got %v", cursor, err)
+		}
+	}
+}
+
+func TestPageLimit(t *testing.T) {
+	if got := pageLimit(0); got != DefaultPageLimit {
+		t.Errorf("pageLimit(0) = %d, want %d", got, DefaultPageLimit)
+	}
+	if got := pageLimit(MaxPageLimit + 1); got != MaxPageLimit {
+		t.Errorf("pageLimit(max+1) = %d, want %d", got, MaxPageLimit)
+	}
+	if got := pageLimit(25); got != 25 {
+		t.Errorf("pageLimit(25) = %d, want 25", got)
+	}
+}
+
+func TestPageTail(t *testing.T) {
+	rows := []*domain.UserGoalProgress{{GoalID: "goal-1"}, {GoalID: "goal-2"}, {GoalID: "goal-3"}}
+	encode := func(last *domain.UserGoalProgress) string { return "after-" + last.GoalID }
+
+	page, next := pageTail(rows, 2, encode)
+	if len(page) != 2 || next != "after-goal-2" {
+		t.Errorf("pageTail(3 rows, 2) = %d rows, cursor %q; want 2 rows, cursor after-goal-2", len(page), next)
+	}
+
+	page, next = pageTail(rows, 3, encode)
+	if len(page) != 3 || next != "" {
+		t.Errorf("pageTail(3 rows, 3) = %d rows, cursor %q; want 3 rows, no cursor", len(page), next)
+	}
+
+	page, next = pageTail(nil, 3, encode)
+	if page == nil || next != "" {
+		t.Errorf("pageTail(nil, 3) = %v, cursor %q; want empty slice, no cursor", page, next)
+	}
+}
+
+// collectPages walks every page for the given ordering and returns goal IDs in order.
+func collectPages(t *testing.T, repo GoalRepository, userID string, order ProgressOrder, limit int) []string {
+	t.Helper()
+
+	var goalIDs []string
+	cursor := ""
+	for i := 0; i < 100; i++ {
+		page, next, err := repo.GetUserProgressPage(context.Background(), userID, PageOptions{
+			Limit:   limit,
+			Cursor:  cursor,
+			OrderBy: order,
+		})
+		if err != nil {
+			t.Fatalf("GetUserProgressPage failed: %v", err)
+		}
+		for _, p := range page {
+			goalIDs = append(goalIDs, p.GoalID)
+		}
+		if next == "" {
+			return goalIDs
+		}
+		cursor = next
+	}
+
+	t.Fatal("pagination did not terminate")
+	return nil
+}
+
+func TestPostgresGoalRepository_GetUserProgressPage(t *testing.T) {
+	db := setupTestDB(t)
+	if db == nil {
+		return
+	}
+	defer cleanupTestDB(t, db)
+
+	repo := NewPostgresGoalRepository(db)
+	ctx := context.Background()
+
+	// Seed 5 rows with controlled timestamps; goal-c and goal-e never expire
+	base := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	seed := []struct {
+		goalID    string
+		created   time.Time
+		updated   time.Time
+		expiresAt *time.Time
+	}{
+		{"goal-a", base, base.Add(5 * time.Hour), ptrTime(base.Add(72 * time.Hour))},
+		{"goal-b", base.Add(time.Hour), base.Add(4 * time.Hour), ptrTime(base.Add(24 * time.Hour))},
+		{"goal-c", base.Add(2 * time.Hour), base.Add(3 * time.Hour), nil},
+		{"goal-d", base.Add(3 * time.Hour), base.Add(2 * time.Hour), ptrTime(base.Add(48 * time.Hour))},
+		{"goal-e", base.Add(4 * time.Hour), base.Add(time.Hour), nil},
+	}
+	for _, s := range seed {
+		_, err := db.ExecContext(ctx, `
+			INSERT INTO user_goal_progress (
+				user_id, goal_id, challenge_id, namespace, progress, status,
+				created_at, updated_at, is_active, expires_at
+			) VALUES ('page-user', $1, 'challenge1', 'test', 0, 'not_started', $2, $3, true, $4)
+		`, s.goalID, s.created, s.updated, s.expiresAt)
+		if err != nil {
+			t.Fatalf("Seed insert failed: %v", err)
+		}
+	}
+
+	tests := []struct {
+		order ProgressOrder
+		want  []string
+	}{
+		{OrderCreatedAtAsc, []string{"goal-a", "goal-b", "goal-c", "goal-d", "goal-e"}},
+		{OrderExpiresAtAsc, []string{"goal-b", "goal-d", "goal-a", "goal-c", "goal-e"}},
+		{OrderUpdatedAtDesc, []string{"goal-a", "goal-b", "goal-c", "goal-d", "goal-e"}},
+	}
+
+	for _, tt := range tests {
+		for _, limit := range []int{1, 2, 10} {
+			got := collectPages(t, repo, "page-user", tt.order, limit)
+			if len(got) != len(tt.want) {
+				t.Fatalf("%s/limit=%d: got %v, want %v", tt.order, limit, got, tt.want)
+			}
+			for i := range got {
+				if got[i] != tt.want[i] {
+					t.Errorf("%s/limit=%d: got %v, want %v", tt.order, limit, got, tt.want)
+					break
+				}
+			}
+		}
+	}
+
+	t.Run("cursor from another ordering is rejected", func(t *testing.T) {
+		_, next, err := repo.GetUserProgressPage(ctx, "page-user", PageOptions{Limit: 2, OrderBy: OrderExpiresAtAsc})
+		if err != nil || next == "" {
+			t.Fatalf("Expected first page with cursor, got err=%v cursor=%q", err, next)
+		}
+
+		_, _, err = repo.GetUserProgressPage(ctx, "page-user", PageOptions{Limit: 2, Cursor: next, OrderBy: OrderUpdatedAtDesc})
+		var ce *customerrors.ChallengeError
+		if !errors.As(err, &ce) || ce.Code != customerrors.ErrCodeInvalidCursor {
+			t.Errorf("Expected INVALID_CURSOR error, got %v", err)
+		}
+	})
+
+	t.Run("challenge page in transaction", func(t *testing.T) {
+		tx, err := repo.BeginTx(ctx)
+		if err != nil {
+			t.Fatalf("BeginTx failed: %v", err)
+		}
+		defer func() { _ = tx.Rollback() }()
+
+		page, next, err := tx.GetChallengeProgressPage(ctx, "page-user", "challenge1", PageOptions{Limit: 3})
+		if err != nil {
+			t.Fatalf("GetChallengeProgressPage failed: %v", err)
+		}
+		if len(page) != 3 || next == "" {
+			t.Errorf("Expected 3 rows and a next cursor, got %d rows cursor=%q", len(page), next)
+		}
+	})
+}
+
+func ptrTime(t time.Time) *time.Time {
+	return &t
+}
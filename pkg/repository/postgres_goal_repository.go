@@ -4,159 +4,148 @@ import (
 	"context"
 	"database/sql"
 	"fmt"
-	"strings"
+	"log/slog"
+	"sync"
 	"time"
 
+	"github.com/AccelByte/extend-challenge-common/pkg/db"
 	"github.com/AccelByte/extend-challenge-common/pkg/domain"
 	"github.com/AccelByte/extend-challenge-common/pkg/errors"
 
-	"github.com/lib/pq" // PostgreSQL driver and array support
+	_ "github.com/lib/pq" // PostgreSQL driver
+)
+
+// Compile-time checks that the Postgres repositories implement the full interfaces.
+// A method added to GoalRepository must be implemented by both the pool and the
+// transaction variant; these assertions fail the build if either falls behind.
+var (
+	_ GoalRepository            = (*PostgresGoalRepository)(nil)
+	_ TxRepository              = (*PostgresTxRepository)(nil)
+	_ ProgressHistoryRepository = (*PostgresGoalRepository)(nil)
+	_ ProgressFeedRepository    = (*PostgresGoalRepository)(nil)
+	_ FlushPreviewRepository    = (*PostgresGoalRepository)(nil)
+	_ ClaimForfeitRepository    = (*PostgresGoalRepository)(nil)
+	_ ClaimReservationSweeper   = (*PostgresGoalRepository)(nil)
+	_ RecurringGoalResetter     = (*PostgresGoalRepository)(nil)
+	_ ProgressSampler           = (*PostgresGoalRepository)(nil)
+	_ LargeFlusher              = (*PostgresGoalRepository)(nil)
+	_ CompletionReevaluator     = (*PostgresGoalRepository)(nil)
+
+	// Shared query helpers run against both the pool and a transaction
+	_ queryer = (*sql.DB)(nil)
+	_ queryer = (*sql.Tx)(nil)
 )
 
 // PostgresGoalRepository implements GoalRepository interface using PostgreSQL.
 type PostgresGoalRepository struct {
 	db *sql.DB
+
+	// Increment guardrails (see increment_validation.go)
+	maxIncrementDelta         int
+	strictIncrementValidation bool
+	maxDeltaPerEvent          int
+	maxEventLateness          time.Duration
+	progressCeiling           int64
+	bigIntProgress            bool
+	overflowPolicy            OverflowPolicy
+	requireAssignment         bool
+
+	// Duplicate entries within a batch upsert (see merge_duplicates.go)
+	mergeStrategy MergeStrategy
+
+	// Rows per COPY+merge cycle of BatchUpsertProgressWithCOPY (see WithMaxCopyBatchRows)
+	maxCopyBatchRows int
+
+	// Two-phase claim reservations (see claim_reservation.go)
+	claimReservationTTL time.Duration
+
+	// Destructive operations gate (see WithAllowBulkDelete)
+	allowBulkDelete bool
+
+	// Serialize batch flushes per user (see user_locking.go)
+	perUserLocking bool
+
+	// Optional graceful-shutdown gate (see WithGate)
+	gate Gate
+
+	// Optional per-namespace cost accounting and throttling (see cost_accounting.go)
+	costReporter CostReporter
+	limiter      Limiter
+
+	// Diagnostics (see explain.go and last_delta.go)
+	logger           *slog.Logger
+	explainThreshold time.Duration
+	lastDelta        bool
+
+	// Read the repeatable-goal completions column (see WithCompletions)
+	completions bool
+
+	// Verify required indexes at construction (see index_check.go)
+	startupChecks bool
+
+	// Exclude expired rows from active-only reads (see lazy_expiry.go)
+	lazyExpireOnRead bool
+
+	// Config checksum stamped by batch writes (see WithConfigChecksum)
+	configChecksum func() string
 }
 
 // NewPostgresGoalRepository creates a new PostgreSQL-backed goal repository.
-func NewPostgresGoalRepository(db *sql.DB) *PostgresGoalRepository {
-	return &PostgresGoalRepository{
-		db: db,
+func NewPostgresGoalRepository(db *sql.DB, opts ...Option) *PostgresGoalRepository {
+	r := &PostgresGoalRepository{
+		db:                        db,
+		maxIncrementDelta:         DefaultMaxIncrementDelta,
+		strictIncrementValidation: true,
+		maxEventLateness:          DefaultMaxEventLateness,
+		overflowPolicy:            AllowUnbounded,
+		claimReservationTTL:       DefaultClaimReservationTTL,
+		maxCopyBatchRows:          DefaultMaxCopyBatchRows,
+		logger:                    slog.Default(),
+	}
+	for _, opt := range opts {
+		opt(r)
+	}
+	if r.progressCeiling <= 0 || r.progressCeiling > r.maxProgress() {
+		r.progressCeiling = r.maxProgress() - DefaultMaxIncrementDelta
 	}
+	if r.startupChecks {
+		r.runStartupChecks()
+	}
+	return r
 }
 
 // GetProgress retrieves a single user's progress for a specific goal.
 func (r *PostgresGoalRepository) GetProgress(ctx context.Context, userID, goalID string) (*domain.UserGoalProgress, error) {
-	query := `
-		SELECT user_id, goal_id, challenge_id, namespace, progress, status,
-		       completed_at, claimed_at, created_at, updated_at,
-		       is_active, assigned_at, expires_at
-		FROM user_goal_progress
-		WHERE user_id = $1 AND goal_id = $2
-	`
-
-	var progress domain.UserGoalProgress
-	err := r.db.QueryRowContext(ctx, query, userID, goalID).Scan(
-		&progress.UserID,
-		&progress.GoalID,
-		&progress.ChallengeID,
-		&progress.Namespace,
-		&progress.Progress,
-		&progress.Status,
-		&progress.CompletedAt,
-		&progress.ClaimedAt,
-		&progress.CreatedAt,
-		&progress.UpdatedAt,
-		&progress.IsActive,
-		&progress.AssignedAt,
-		&progress.ExpiresAt,
-	)
-
-	if err == sql.ErrNoRows {
-		return nil, nil // No progress record exists (lazy initialization)
-	}
-
-	if err != nil {
-		return nil, errors.ErrDatabaseError("get progress", err)
-	}
+	return r.exec().getProgress(ctx, userID, goalID, false)
+}
 
-	return &progress, nil
+// GetProgressRequired retrieves a single user's progress for a goal, failing with
+// errors.ErrProgressNotFound when no row exists.
+func (r *PostgresGoalRepository) GetProgressRequired(ctx context.Context, userID, goalID string) (*domain.UserGoalProgress, error) {
+	return r.exec().getProgressRequired(ctx, userID, goalID)
 }
 
 // GetUserProgress retrieves all goal progress records for a specific user.
 // M3 Phase 4: activeOnly parameter filters to only is_active = true goals.
 func (r *PostgresGoalRepository) GetUserProgress(ctx context.Context, userID string, activeOnly bool) ([]*domain.UserGoalProgress, error) {
-	query := `
-		SELECT user_id, goal_id, challenge_id, namespace, progress, status,
-		       completed_at, claimed_at, created_at, updated_at,
-		       is_active, assigned_at, expires_at
-		FROM user_goal_progress
-		WHERE user_id = $1
-	`
-
-	// M3 Phase 4: Add is_active filter when activeOnly is true
-	if activeOnly {
-		query += " AND is_active = true"
-	}
-
-	query += " ORDER BY created_at ASC"
-
-	rows, err := r.db.QueryContext(ctx, query, userID)
-	if err != nil {
-		return nil, errors.ErrDatabaseError("get user progress", err)
-	}
-	defer func() { _ = rows.Close() }()
+	return r.exec().getUserProgress(ctx, userID, activeOnly)
+}
 
-	return r.scanProgressRows(rows)
+// GetUserProgressLite retrieves the goal ID, progress and status of a user's goals.
+func (r *PostgresGoalRepository) GetUserProgressLite(ctx context.Context, userID string, activeOnly bool) ([]ProgressLite, error) {
+	return r.exec().getUserProgressLite(ctx, userID, activeOnly)
 }
 
 // GetChallengeProgress retrieves all goal progress for a user within a specific challenge.
 // M3 Phase 4: activeOnly parameter filters to only is_active = true goals.
 func (r *PostgresGoalRepository) GetChallengeProgress(ctx context.Context, userID, challengeID string, activeOnly bool) ([]*domain.UserGoalProgress, error) {
-	query := `
-		SELECT user_id, goal_id, challenge_id, namespace, progress, status,
-		       completed_at, claimed_at, created_at, updated_at,
-		       is_active, assigned_at, expires_at
-		FROM user_goal_progress
-		WHERE user_id = $1 AND challenge_id = $2
-	`
-
-	// M3 Phase 4: Add is_active filter when activeOnly is true
-	if activeOnly {
-		query += " AND is_active = true"
-	}
-
-	query += " ORDER BY created_at ASC"
-
-	rows, err := r.db.QueryContext(ctx, query, userID, challengeID)
-	if err != nil {
-		return nil, errors.ErrDatabaseError("get challenge progress", err)
-	}
-	defer func() { _ = rows.Close() }()
-
-	return r.scanProgressRows(rows)
+	return r.exec().getChallengeProgress(ctx, userID, challengeID, activeOnly)
 }
 
 // UpsertProgress creates or updates a single goal progress record.
+// M3 Phase 5: Includes is_active, assigned_at and expires_at.
 func (r *PostgresGoalRepository) UpsertProgress(ctx context.Context, progress *domain.UserGoalProgress) error {
-	// M3 Phase 5: Include is_active, assigned_at, expires_at fields
-	query := `
-		INSERT INTO user_goal_progress (
-			user_id, goal_id, challenge_id, namespace,
-			progress, status, completed_at, updated_at,
-			is_active, assigned_at, expires_at
-		) VALUES (
-			$1, $2, $3, $4, $5, $6, $7, NOW(), $8, $9, $10
-		)
-		ON CONFLICT (user_id, goal_id) DO UPDATE SET
-			progress = EXCLUDED.progress,
-			status = EXCLUDED.status,
-			completed_at = EXCLUDED.completed_at,
-			updated_at = NOW(),
-			is_active = EXCLUDED.is_active,
-			assigned_at = EXCLUDED.assigned_at,
-			expires_at = EXCLUDED.expires_at
-		WHERE user_goal_progress.status != 'claimed'
-	`
-
-	_, err := r.db.ExecContext(ctx, query,
-		progress.UserID,
-		progress.GoalID,
-		progress.ChallengeID,
-		progress.Namespace,
-		progress.Progress,
-		progress.Status,
-		progress.CompletedAt,
-		progress.IsActive,
-		progress.AssignedAt,
-		progress.ExpiresAt,
-	)
-
-	if err != nil {
-		return errors.ErrDatabaseError("upsert progress", err)
-	}
-
-	return nil
+	return r.exec().upsertProgress(ctx, progress)
 }
 
 // BatchUpsertProgress performs batch upsert for multiple progress records in a single query.
@@ -168,59 +157,7 @@ func (r *PostgresGoalRepository) UpsertProgress(ctx context.Context, progress *d
 // M3: Added is_active = true check in WHERE clause for assignment control.
 // Only updates assigned goals (is_active = true), skipping unassigned goals.
 func (r *PostgresGoalRepository) BatchUpsertProgress(ctx context.Context, updates []*domain.UserGoalProgress) error {
-	if len(updates) == 0 {
-		return nil
-	}
-
-	// Check PostgreSQL parameter limit (65,535 parameters)
-	// With 7 parameters per row, max is ~9,000 rows
-	if len(updates) > 9000 {
-		return fmt.Errorf("batch size exceeds PostgreSQL parameter limit: %d rows (max 9000)", len(updates))
-	}
-
-	// Build dynamic query with correct number of placeholders
-	valueStrings := make([]string, 0, len(updates))
-	valueArgs := make([]interface{}, 0, len(updates)*7)
-
-	for i, update := range updates {
-		valueStrings = append(valueStrings, fmt.Sprintf(
-			"($%d, $%d, $%d, $%d, $%d, $%d, $%d, NOW())",
-			i*7+1, i*7+2, i*7+3, i*7+4, i*7+5, i*7+6, i*7+7,
-		))
-		valueArgs = append(valueArgs,
-			update.UserID,
-			update.GoalID,
-			update.ChallengeID,
-			update.Namespace,
-			update.Progress,
-			update.Status,
-			update.CompletedAt,
-		)
-	}
-
-	// Safe: fmt.Sprintf only builds the VALUES structure with placeholders ($1, $2, etc.)
-	// All actual values are passed via parameterized query (valueArgs), not string interpolation
-	// #nosec G201
-	query := fmt.Sprintf(`
-		INSERT INTO user_goal_progress (
-			user_id, goal_id, challenge_id, namespace,
-			progress, status, completed_at, updated_at
-		) VALUES %s
-		ON CONFLICT (user_id, goal_id) DO UPDATE SET
-			progress = EXCLUDED.progress,
-			status = EXCLUDED.status,
-			completed_at = EXCLUDED.completed_at,
-			updated_at = NOW()
-		WHERE user_goal_progress.status != 'claimed'
-		  AND user_goal_progress.is_active = true
-	`, strings.Join(valueStrings, ","))
-
-	_, err := r.db.ExecContext(ctx, query, valueArgs...)
-	if err != nil {
-		return errors.ErrDatabaseError("batch upsert progress", err)
-	}
-
-	return nil
+	return r.exec().batchUpsertProgress(ctx, updates)
 }
 
 // BatchUpsertProgressWithCOPY performs batch upsert using PostgreSQL COPY protocol.
@@ -229,341 +166,64 @@ func (r *PostgresGoalRepository) BatchUpsertProgress(ctx context.Context, update
 // Implementation:
 // 1. Creates temporary table (session-local, auto-dropped)
 // 2. Uses COPY FROM STDIN to bulk load data (bypasses query parser)
-// 3. Merges temp table into main table using INSERT ... SELECT with ON CONFLICT
+// 3. Merges temp table into main table with an UPDATE of existing active rows
 // 4. Maintains claimed protection logic (does not update claimed goals)
 //
 // This method solves the Phase 1 database bottleneck by reducing flush time from
 // 62-105ms to 10-20ms, allowing the system to handle 500+ EPS with <1% data loss.
 func (r *PostgresGoalRepository) BatchUpsertProgressWithCOPY(ctx context.Context, updates []*domain.UserGoalProgress) error {
-	if len(updates) == 0 {
-		return nil
-	}
-
-	// Start transaction for temp table + merge operation
-	tx, err := r.db.BeginTx(ctx, nil)
-	if err != nil {
-		return errors.ErrDatabaseError("begin transaction for COPY", err)
-	}
-	defer func() {
-		if err != nil {
-			_ = tx.Rollback()
-		}
-	}()
-
-	// Step 1: Create temporary table (session-local, automatically dropped at end of session)
-	_, err = tx.ExecContext(ctx, `
-		CREATE TEMP TABLE IF NOT EXISTS temp_user_goal_progress (
-			user_id VARCHAR(100) NOT NULL,
-			goal_id VARCHAR(100) NOT NULL,
-			challenge_id VARCHAR(100) NOT NULL,
-			namespace VARCHAR(100) NOT NULL,
-			progress INT NOT NULL,
-			status VARCHAR(20) NOT NULL,
-			completed_at TIMESTAMP NULL,
-			updated_at TIMESTAMP NOT NULL DEFAULT NOW()
-		) ON COMMIT DROP
-	`)
-	if err != nil {
-		return errors.ErrDatabaseError("create temp table for COPY", err)
-	}
-
-	// Step 2: Prepare COPY statement
-	stmt, err := tx.PrepareContext(ctx, pq.CopyIn(
-		"temp_user_goal_progress",
-		"user_id", "goal_id", "challenge_id", "namespace",
-		"progress", "status", "completed_at", "updated_at",
-	))
-	if err != nil {
-		return errors.ErrDatabaseError("prepare COPY statement", err)
-	}
-	defer func() { _ = stmt.Close() }()
-
-	// Step 3: Bulk load data into temp table using COPY
-	now := time.Now().UTC() // Always use UTC for consistency across timezones
-	for _, update := range updates {
-		_, err = stmt.ExecContext(ctx,
-			update.UserID,
-			update.GoalID,
-			update.ChallengeID,
-			update.Namespace,
-			update.Progress,
-			update.Status,
-			update.CompletedAt,
-			now,
-		)
-		if err != nil {
-			return errors.ErrDatabaseError("execute COPY row", err)
-		}
-	}
-
-	// Step 4: Execute COPY (flush buffered rows to temp table)
-	_, err = stmt.ExecContext(ctx)
-	if err != nil {
-		return errors.ErrDatabaseError("flush COPY to temp table", err)
-	}
-
-	// Step 5: Merge temp table into main table using UPDATE-only (M3 Phase 9: Lazy Materialization)
-	// Changed from UPSERT to pure UPDATE to prevent row creation for unassigned goals.
-	// Events for unassigned goals become true no-ops (no row exists, UPDATE does nothing).
-	// Only updates existing rows where is_active = true and status != 'claimed'.
-	_, err = tx.ExecContext(ctx, `
-		UPDATE user_goal_progress
-		SET
-			progress = temp.progress,
-			status = temp.status,
-			completed_at = temp.completed_at,
-			updated_at = NOW()
-		FROM temp_user_goal_progress AS temp
-		WHERE user_goal_progress.user_id = temp.user_id
-		  AND user_goal_progress.goal_id = temp.goal_id
-		  AND user_goal_progress.is_active = true
-		  AND user_goal_progress.status != 'claimed'
-	`)
-	if err != nil {
-		return errors.ErrDatabaseError("update user_goal_progress from temp table", err)
-	}
-
-	// Step 6: Commit transaction (temp table automatically dropped)
-	err = tx.Commit()
-	if err != nil {
-		return errors.ErrDatabaseError("commit COPY transaction", err)
-	}
-
-	return nil
+	return r.exec().batchUpsertProgressWithCOPY(ctx, updates)
 }
 
 // IncrementProgress atomically increments a user's progress by a delta value.
+// M3 Phase 9: Only existing active rows are updated (lazy materialization).
 func (r *PostgresGoalRepository) IncrementProgress(ctx context.Context, userID, goalID, challengeID, namespace string, delta, targetValue int, isDailyIncrement bool) error {
-	if isDailyIncrement {
-		return r.incrementProgressDaily(ctx, userID, goalID, challengeID, namespace, delta, targetValue)
-	}
-	return r.incrementProgressRegular(ctx, userID, goalID, challengeID, namespace, delta, targetValue)
-}
-
-// incrementProgressRegular handles regular increments (always adds delta)
-// M3 Phase 9: Changed from UPSERT to UPDATE-only for lazy materialization
-func (r *PostgresGoalRepository) incrementProgressRegular(ctx context.Context, userID, goalID, challengeID, namespace string, delta, targetValue int) error {
-	query := `
-		UPDATE user_goal_progress
-		SET
-			progress = progress + $3::INT,
-			status = CASE
-				WHEN progress + $3::INT >= $4::INT THEN 'completed'
-				ELSE 'in_progress'
-			END,
-			completed_at = CASE
-				WHEN progress + $3::INT >= $4::INT AND completed_at IS NULL THEN NOW()
-				ELSE completed_at
-			END,
-			updated_at = NOW()
-		WHERE user_id = $1
-		  AND goal_id = $2
-		  AND is_active = true
-		  AND status != 'claimed'
-	`
-
-	_, err := r.db.ExecContext(ctx, query, userID, goalID, delta, targetValue)
-	if err != nil {
-		return errors.ErrDatabaseError("increment progress (regular)", err)
-	}
-
-	return nil
+	return r.exec().incrementProgress(ctx, userID, goalID, challengeID, namespace, delta, targetValue, isDailyIncrement)
 }
 
-// incrementProgressDaily handles daily increments (only once per day)
-// Uses timezone-safe date comparison to prevent timezone-related bugs
-// M3 Phase 9: Changed from UPSERT to UPDATE-only for lazy materialization
-func (r *PostgresGoalRepository) incrementProgressDaily(ctx context.Context, userID, goalID, challengeID, namespace string, delta, targetValue int) error {
-	query := `
-		UPDATE user_goal_progress
-		SET
-			progress = CASE
-				-- Same day (UTC): don't increment
-				WHEN DATE(updated_at AT TIME ZONE 'UTC') = DATE(NOW() AT TIME ZONE 'UTC')
-					THEN progress
-				-- New day: increment by delta
-				ELSE progress + $3::INT
-			END,
-			status = CASE
-				-- Calculate new progress first, then check threshold
-				WHEN DATE(updated_at AT TIME ZONE 'UTC') = DATE(NOW() AT TIME ZONE 'UTC') THEN
-					-- Same day, progress unchanged
-					CASE WHEN progress >= $4::INT THEN 'completed' ELSE 'in_progress' END
-				ELSE
-					-- New day, check incremented progress
-					CASE WHEN progress + $3::INT >= $4::INT THEN 'completed' ELSE 'in_progress' END
-			END,
-			completed_at = CASE
-				WHEN DATE(updated_at AT TIME ZONE 'UTC') = DATE(NOW() AT TIME ZONE 'UTC') THEN
-					completed_at  -- Same day, keep existing
-				WHEN progress + $3::INT >= $4::INT AND completed_at IS NULL THEN
-					NOW()  -- New day and just completed
-				ELSE
-					completed_at  -- Keep existing
-			END,
-			updated_at = NOW()  -- Always update timestamp (for daily tracking)
-		WHERE user_id = $1
-		  AND goal_id = $2
-		  AND is_active = true
-		  AND status != 'claimed'
-	`
-
-	_, err := r.db.ExecContext(ctx, query, userID, goalID, delta, targetValue)
-	if err != nil {
-		return errors.ErrDatabaseError("increment progress (daily)", err)
-	}
-
-	return nil
+// IncrementProgressWithCooldown atomically increments progress unless the row is inside its cooldown window.
+// Delegates to BatchIncrementProgress so the cooldown predicate lives in a single query.
+func (r *PostgresGoalRepository) IncrementProgressWithCooldown(ctx context.Context, userID, goalID, challengeID, namespace string, delta, targetValue int, cooldown time.Duration) error {
+	return r.exec().incrementProgressWithCooldown(ctx, userID, goalID, challengeID, namespace, delta, targetValue, cooldown)
 }
 
 // BatchIncrementProgress performs batch atomic increment for multiple progress records.
 // Uses PostgreSQL UNNEST for efficient batch processing (50x faster than individual calls).
 func (r *PostgresGoalRepository) BatchIncrementProgress(ctx context.Context, increments []ProgressIncrement) error {
-	if len(increments) == 0 {
-		return nil
-	}
-
-	// Build arrays for UNNEST
-	userIDs := make([]string, len(increments))
-	goalIDs := make([]string, len(increments))
-	deltas := make([]int, len(increments))
-	targetValues := make([]int, len(increments))
-	isDailyFlags := make([]bool, len(increments))
-
-	for i, inc := range increments {
-		userIDs[i] = inc.UserID
-		goalIDs[i] = inc.GoalID
-		deltas[i] = inc.Delta
-		targetValues[i] = inc.TargetValue
-		isDailyFlags[i] = inc.IsDailyIncrement
-	}
-
-	// Complex query using UNNEST for batch operations with daily increment support
-	// Uses timezone-safe date comparison (AT TIME ZONE 'UTC') to prevent timezone bugs
-	// M3 Phase 9: Changed from UPSERT to UPDATE-only for lazy materialization
-	query := `
-		UPDATE user_goal_progress
-		SET
-			progress = CASE
-				-- Daily increment: check if same day (UTC)
-				WHEN t.is_daily = true
-				     AND DATE(user_goal_progress.updated_at AT TIME ZONE 'UTC') = DATE(NOW() AT TIME ZONE 'UTC')
-					THEN user_goal_progress.progress  -- Same day, no increment
-				ELSE
-					user_goal_progress.progress + t.delta  -- Different day or regular increment
-			END,
-			status = CASE
-				-- Calculate based on new progress value
-				WHEN t.is_daily = true
-				     AND DATE(user_goal_progress.updated_at AT TIME ZONE 'UTC') = DATE(NOW() AT TIME ZONE 'UTC') THEN
-					-- Same day: status based on current progress
-					CASE WHEN user_goal_progress.progress >= t.target_value THEN 'completed' ELSE 'in_progress' END
-				ELSE
-					-- New day or regular: status based on incremented progress
-					CASE WHEN user_goal_progress.progress + t.delta >= t.target_value THEN 'completed' ELSE 'in_progress' END
-			END,
-			completed_at = CASE
-				WHEN t.is_daily = true
-				     AND DATE(user_goal_progress.updated_at AT TIME ZONE 'UTC') = DATE(NOW() AT TIME ZONE 'UTC') THEN
-					user_goal_progress.completed_at  -- Same day, keep existing
-				WHEN user_goal_progress.progress + t.delta >= t.target_value
-				     AND user_goal_progress.completed_at IS NULL THEN
-					NOW()  -- Just completed
-				ELSE
-					user_goal_progress.completed_at  -- Keep existing
-			END,
-			updated_at = NOW()
-		FROM (
-			SELECT
-				user_id,
-				goal_id,
-				delta,
-				target_value,
-				is_daily
-			FROM UNNEST(
-				$1::VARCHAR(100)[],  -- user_ids
-				$2::VARCHAR(100)[],  -- goal_ids
-				$3::INT[],           -- deltas
-				$4::INT[],           -- target_values
-				$5::BOOLEAN[]        -- is_daily_increment flags
-			) AS t(user_id, goal_id, delta, target_value, is_daily)
-		) AS t
-		WHERE user_goal_progress.user_id = t.user_id
-		  AND user_goal_progress.goal_id = t.goal_id
-		  AND user_goal_progress.is_active = true
-		  AND user_goal_progress.status != 'claimed'
-	`
-
-	_, err := r.db.ExecContext(ctx, query,
-		pq.Array(userIDs),
-		pq.Array(goalIDs),
-		pq.Array(deltas),
-		pq.Array(targetValues),
-		pq.Array(isDailyFlags),
-	)
+	return r.exec().batchIncrementProgress(ctx, increments)
+}
 
+// BatchIncrementProgressReturning performs batch atomic increment and returns rows left in 'completed' status.
+// Uses the same UNNEST UPDATE as BatchIncrementProgress wrapped in a CTE with RETURNING.
+func (r *PostgresGoalRepository) BatchIncrementProgressReturning(ctx context.Context, increments []ProgressIncrement) ([]CompletionResult, error) {
+	result, err := r.BatchIncrementProgressWithResult(ctx, increments)
 	if err != nil {
-		return errors.ErrDatabaseError("batch increment progress", err)
+		return nil, err
 	}
+	return result.Completions, nil
+}
 
-	return nil
+// BatchIncrementProgressWithResult performs batch atomic increment and returns rows left in
+// 'completed' status along with the number of late entries skipped.
+func (r *PostgresGoalRepository) BatchIncrementProgressWithResult(ctx context.Context, increments []ProgressIncrement) (BatchIncrementResult, error) {
+	return r.exec().batchIncrementProgressWithResult(ctx, increments)
 }
 
 // MarkAsClaimed updates a goal's status to 'claimed' and sets claimed_at timestamp.
 func (r *PostgresGoalRepository) MarkAsClaimed(ctx context.Context, userID, goalID string) error {
-	query := `
-		UPDATE user_goal_progress
-		SET status = 'claimed',
-			claimed_at = NOW(),
-			updated_at = NOW()
-		WHERE user_id = $1 AND goal_id = $2
-		AND status = 'completed'
-		AND claimed_at IS NULL
-	`
-
-	result, err := r.db.ExecContext(ctx, query, userID, goalID)
-	if err != nil {
-		return errors.ErrDatabaseError("mark as claimed", err)
-	}
-
-	rowsAffected, err := result.RowsAffected()
-	if err != nil {
-		return errors.ErrDatabaseError("check rows affected", err)
-	}
-
-	if rowsAffected == 0 {
-		// No rows updated - goal either doesn't exist, not completed, or already claimed
-		// Caller should check progress status to determine specific error
-		return errors.ErrGoalNotCompleted(goalID)
-	}
+	return r.exec().markAsClaimed(ctx, userID, goalID, 0)
+}
 
-	return nil
+// MarkAsClaimedWithDeadline claims a goal completed within claimDeadline.
+func (r *PostgresGoalRepository) MarkAsClaimedWithDeadline(ctx context.Context, userID, goalID string, claimDeadline time.Duration) error {
+	return r.exec().markAsClaimed(ctx, userID, goalID, claimDeadline)
 }
 
 // M3: Goal assignment control methods
 
 // GetGoalsByIDs retrieves goal progress records for a user across multiple goal IDs.
 func (r *PostgresGoalRepository) GetGoalsByIDs(ctx context.Context, userID string, goalIDs []string) ([]*domain.UserGoalProgress, error) {
-	if len(goalIDs) == 0 {
-		return []*domain.UserGoalProgress{}, nil
-	}
-
-	query := `
-		SELECT user_id, goal_id, challenge_id, namespace, progress, status,
-		       completed_at, claimed_at, created_at, updated_at,
-		       is_active, assigned_at, expires_at
-		FROM user_goal_progress
-		WHERE user_id = $1 AND goal_id = ANY($2)
-		ORDER BY created_at ASC
-	`
-
-	rows, err := r.db.QueryContext(ctx, query, userID, pq.Array(goalIDs))
-	if err != nil {
-		return nil, errors.ErrDatabaseError("get goals by IDs", err)
-	}
-	defer func() { _ = rows.Close() }()
-
-	return r.scanProgressRows(rows)
+	return r.getGoalsByIDs(ctx, r.db, "get goals by IDs", userID, goalIDs, ProgressFilter{})
 }
 
 // BulkInsert creates multiple goal progress records in a single query.
@@ -571,52 +231,12 @@ func (r *PostgresGoalRepository) GetGoalsByIDs(ctx context.Context, userID strin
 // DEPRECATED: Use BulkInsertWithCOPY for better performance (3-5x faster).
 // This method is kept for backwards compatibility and testing.
 func (r *PostgresGoalRepository) BulkInsert(ctx context.Context, progresses []*domain.UserGoalProgress) error {
-	if len(progresses) == 0 {
-		return nil
-	}
-
-	// Build values for bulk insert (11 parameters per row)
-	valueStrings := make([]string, 0, len(progresses))
-	valueArgs := make([]interface{}, 0, len(progresses)*11)
-
-	for i, p := range progresses {
-		valueStrings = append(valueStrings, fmt.Sprintf(
-			"($%d, $%d, $%d, $%d, $%d, $%d, $%d, $%d, NOW(), NOW(), $%d, $%d, $%d)",
-			i*11+1, i*11+2, i*11+3, i*11+4, i*11+5, i*11+6, i*11+7, i*11+8, i*11+9, i*11+10, i*11+11,
-		))
-
-		valueArgs = append(valueArgs,
-			p.UserID,
-			p.GoalID,
-			p.ChallengeID,
-			p.Namespace,
-			p.Progress,
-			p.Status,
-			p.CompletedAt,
-			p.ClaimedAt,
-			p.IsActive,
-			p.AssignedAt,
-			p.ExpiresAt,
-		)
-	}
-
-	//nolint:gosec // Safe: valueStrings contains only parameterized placeholders like "($1, $2, $3)", not user input
-	query := fmt.Sprintf(`
-		INSERT INTO user_goal_progress (
-			user_id, goal_id, challenge_id, namespace,
-			progress, status, completed_at, claimed_at,
-			created_at, updated_at,
-			is_active, assigned_at, expires_at
-		) VALUES %s
-		ON CONFLICT (user_id, goal_id) DO NOTHING
-	`, strings.Join(valueStrings, ","))
-
-	_, err := r.db.ExecContext(ctx, query, valueArgs...)
-	if err != nil {
-		return errors.ErrDatabaseError("bulk insert goals", err)
-	}
+	return r.exec().bulkInsert(ctx, progresses)
+}
 
-	return nil
+// BulkInsertWithPriority inserts the highest-priority rows that fit under the user's row cap.
+func (r *PostgresGoalRepository) BulkInsertWithPriority(ctx context.Context, progresses []*domain.UserGoalProgress, priorities []int, maxRowsForUser int) ([]string, []string, error) {
+	return r.exec().bulkInsertWithPriority(ctx, progresses, priorities, maxRowsForUser)
 }
 
 // BulkInsertWithCOPY creates multiple goal progress records using PostgreSQL COPY protocol.
@@ -647,175 +267,13 @@ func (r *PostgresGoalRepository) BulkInsert(ctx context.Context, progresses []*d
 // 2. Uses COPY FROM STDIN to bulk load data (bypasses query parser)
 // 3. Inserts from temp table to main table with ON CONFLICT DO NOTHING
 func (r *PostgresGoalRepository) BulkInsertWithCOPY(ctx context.Context, progresses []*domain.UserGoalProgress) error {
-	if len(progresses) == 0 {
-		return nil
-	}
-
-	// Start transaction for temp table + insert operation
-	tx, err := r.db.BeginTx(ctx, nil)
-	if err != nil {
-		return errors.ErrDatabaseError("begin transaction for BulkInsert COPY", err)
-	}
-	defer func() {
-		if err != nil {
-			_ = tx.Rollback()
-		}
-	}()
-
-	// Step 1: Create temporary table (session-local, automatically dropped at end of session)
-	_, err = tx.ExecContext(ctx, `
-		CREATE TEMP TABLE IF NOT EXISTS temp_bulk_insert (
-			user_id VARCHAR(100) NOT NULL,
-			goal_id VARCHAR(100) NOT NULL,
-			challenge_id VARCHAR(100) NOT NULL,
-			namespace VARCHAR(100) NOT NULL,
-			progress INT NOT NULL,
-			status VARCHAR(20) NOT NULL,
-			completed_at TIMESTAMP NULL,
-			claimed_at TIMESTAMP NULL,
-			created_at TIMESTAMP NOT NULL DEFAULT NOW(),
-			updated_at TIMESTAMP NOT NULL DEFAULT NOW(),
-			is_active BOOLEAN NOT NULL DEFAULT false,
-			assigned_at TIMESTAMP NULL,
-			expires_at TIMESTAMP NULL
-		) ON COMMIT DROP
-	`)
-	if err != nil {
-		return errors.ErrDatabaseError("create temp table for BulkInsert COPY", err)
-	}
-
-	// Step 2: Prepare COPY statement
-	stmt, err := tx.PrepareContext(ctx, pq.CopyIn(
-		"temp_bulk_insert",
-		"user_id", "goal_id", "challenge_id", "namespace",
-		"progress", "status", "completed_at", "claimed_at",
-		"created_at", "updated_at",
-		"is_active", "assigned_at", "expires_at",
-	))
-	if err != nil {
-		return errors.ErrDatabaseError("prepare COPY statement for BulkInsert", err)
-	}
-	defer func() { _ = stmt.Close() }()
-
-	// Step 3: Bulk load data into temp table using COPY
-	now := time.Now().UTC() // Always use UTC for consistency across timezones
-	for _, p := range progresses {
-		_, err = stmt.ExecContext(ctx,
-			p.UserID,
-			p.GoalID,
-			p.ChallengeID,
-			p.Namespace,
-			p.Progress,
-			p.Status,
-			p.CompletedAt,
-			p.ClaimedAt,
-			now,
-			now,
-			p.IsActive,
-			p.AssignedAt,
-			p.ExpiresAt,
-		)
-		if err != nil {
-			return errors.ErrDatabaseError("execute COPY row for BulkInsert", err)
-		}
-	}
-
-	// Step 4: Execute COPY (flush buffered rows to temp table)
-	_, err = stmt.ExecContext(ctx)
-	if err != nil {
-		return errors.ErrDatabaseError("flush COPY to temp table for BulkInsert", err)
-	}
-
-	// Step 5: Insert from temp table to main table with conflict handling
-	_, err = tx.ExecContext(ctx, `
-		INSERT INTO user_goal_progress (
-			user_id, goal_id, challenge_id, namespace,
-			progress, status, completed_at, claimed_at,
-			created_at, updated_at,
-			is_active, assigned_at, expires_at
-		)
-		SELECT
-			user_id, goal_id, challenge_id, namespace,
-			progress, status, completed_at, claimed_at,
-			created_at, updated_at,
-			is_active, assigned_at, expires_at
-		FROM temp_bulk_insert
-		ON CONFLICT (user_id, goal_id) DO NOTHING
-	`)
-	if err != nil {
-		return errors.ErrDatabaseError("insert from temp table for BulkInsert", err)
-	}
-
-	// Step 6: Commit transaction (temp table automatically dropped)
-	err = tx.Commit()
-	if err != nil {
-		return errors.ErrDatabaseError("commit BulkInsert COPY transaction", err)
-	}
-
-	return nil
+	return r.exec().bulkInsertWithCOPY(ctx, progresses)
 }
 
 // UpsertGoalActive creates or updates a goal's is_active status.
+// Activation always resets assigned_at to NOW().
 func (r *PostgresGoalRepository) UpsertGoalActive(ctx context.Context, progress *domain.UserGoalProgress) error {
-	// M3 Phase 5: UpsertGoalActive is designed to toggle is_active on existing rows.
-	// Use UPDATE instead of INSERT...ON CONFLICT to avoid check constraint violations
-	// when Status field is empty.
-	query := `
-		UPDATE user_goal_progress SET
-			is_active = $1,
-			assigned_at = CASE
-				WHEN $1 = true THEN NOW()
-				ELSE assigned_at
-			END,
-			updated_at = NOW()
-		WHERE user_id = $2
-		  AND goal_id = $3
-	`
-
-	result, err := r.db.ExecContext(ctx, query,
-		progress.IsActive,
-		progress.UserID,
-		progress.GoalID,
-	)
-
-	if err != nil {
-		return errors.ErrDatabaseError("update goal active", err)
-	}
-
-	// Check if the row was actually updated
-	rowsAffected, err := result.RowsAffected()
-	if err != nil {
-		return errors.ErrDatabaseError("check rows affected", err)
-	}
-
-	if rowsAffected == 0 {
-		// Row doesn't exist - insert with sensible defaults
-		insertQuery := `
-			INSERT INTO user_goal_progress (
-				user_id, goal_id, challenge_id, namespace,
-				progress, status, is_active, assigned_at,
-				created_at, updated_at
-			) VALUES (
-				$1, $2, $3, $4, 0, 'not_started', $5,
-				CASE WHEN $5 = true THEN NOW() ELSE NULL END,
-				NOW(), NOW()
-			)
-		`
-
-		_, err = r.db.ExecContext(ctx, insertQuery,
-			progress.UserID,
-			progress.GoalID,
-			progress.ChallengeID,
-			progress.Namespace,
-			progress.IsActive,
-		)
-
-		if err != nil {
-			return errors.ErrDatabaseError("insert goal active", err)
-		}
-	}
-
-	return nil
+	return r.exec().upsertGoalActive(ctx, progress, ActivationOptions{UpdateAssignedAt: true})
 }
 
 // BatchUpsertGoalActive updates is_active status for multiple goals in a single database operation (M4).
@@ -830,191 +288,60 @@ func (r *PostgresGoalRepository) UpsertGoalActive(ctx context.Context, progress
 //
 // Performance: ~10ms for 10 goals (vs ~20-50ms with individual UpsertGoalActive loop)
 func (r *PostgresGoalRepository) BatchUpsertGoalActive(ctx context.Context, progresses []*domain.UserGoalProgress) error {
-	if len(progresses) == 0 {
-		return nil
-	}
-
-	// Extract goal IDs and is_active values
-	goalIDs := make([]string, len(progresses))
-	isActiveVals := make([]bool, len(progresses))
-	userID := progresses[0].UserID // All progresses should have the same user_id
-
-	for i, p := range progresses {
-		goalIDs[i] = p.GoalID
-		isActiveVals[i] = p.IsActive
-	}
-
-	// Step 1: Batch UPDATE existing rows using UNNEST to map each goal to its is_active value
-	updateQuery := `
-		UPDATE user_goal_progress SET
-			is_active = data.is_active,
-			assigned_at = CASE WHEN data.is_active THEN NOW() ELSE NULL END,
-			updated_at = NOW()
-		FROM (
-			SELECT UNNEST($2::text[]) AS goal_id, UNNEST($3::boolean[]) AS is_active
-		) AS data
-		WHERE user_goal_progress.user_id = $1
-		  AND user_goal_progress.goal_id = data.goal_id
-	`
-
-	result, err := r.db.ExecContext(ctx, updateQuery, userID, pq.Array(goalIDs), pq.Array(isActiveVals))
-	if err != nil {
-		return errors.ErrDatabaseError("batch update goal active", err)
-	}
-
-	// Check how many rows were updated
-	rowsUpdated, err := result.RowsAffected()
-	if err != nil {
-		return errors.ErrDatabaseError("check rows affected", err)
-	}
-
-	// If all rows were updated, we're done
-	if int(rowsUpdated) == len(progresses) {
-		return nil
-	}
-
-	// Step 2: Batch INSERT missing rows with actual is_active values
-	// Use ON CONFLICT DO UPDATE to handle race conditions
-	insertQuery := `
-		INSERT INTO user_goal_progress (
-			user_id, goal_id, challenge_id, namespace,
-			progress, status, is_active, assigned_at,
-			created_at, updated_at
-		) VALUES
-	`
-
-	values := make([]interface{}, 0, len(progresses)*5) // 5 actual values per row
-	valuePlaceholders := make([]string, 0, len(progresses))
-
-	for i, p := range progresses {
-		offset := i * 5
-		valuePlaceholders = append(valuePlaceholders, fmt.Sprintf(
-			"($%d, $%d, $%d, $%d, 0, 'not_started', $%d, NOW(), NOW(), NOW())",
-			offset+1, offset+2, offset+3, offset+4, offset+5,
-		))
-
-		values = append(values,
-			p.UserID,
-			p.GoalID,
-			p.ChallengeID,
-			p.Namespace,
-			p.IsActive, // Use actual is_active value
-		)
-	}
+	return r.exec().batchUpsertGoalActive(ctx, progresses)
+}
 
-	insertQuery += strings.Join(valuePlaceholders, ", ")
-	insertQuery += " ON CONFLICT (user_id, goal_id) DO UPDATE SET is_active = EXCLUDED.is_active, assigned_at = CASE WHEN EXCLUDED.is_active THEN NOW() ELSE NULL END, updated_at = NOW()"
+// M3 Phase 9: Fast path optimization methods
 
-	_, err = r.db.ExecContext(ctx, insertQuery, values...)
-	if err != nil {
-		return errors.ErrDatabaseError("batch insert goal active", err)
-	}
+// DeactivateChallengeGoals deactivates all of a user's active goals in a challenge.
+func (r *PostgresGoalRepository) DeactivateChallengeGoals(ctx context.Context, userID, challengeID string) (int64, error) {
+	return r.exec().deactivateChallengeGoals(ctx, userID, challengeID)
+}
 
-	return nil
+// ReactivateChallengeGoals reactivates all of a user's inactive goals in a challenge.
+func (r *PostgresGoalRepository) ReactivateChallengeGoals(ctx context.Context, userID, challengeID string) (int64, error) {
+	return r.exec().reactivateChallengeGoals(ctx, userID, challengeID)
 }
 
-// M3 Phase 9: Fast path optimization methods
+// DeleteChallengeProgress deletes all progress rows of a challenge in a namespace.
+// Requires WithAllowBulkDelete.
+func (r *PostgresGoalRepository) DeleteChallengeProgress(ctx context.Context, namespace, challengeID string) (int64, error) {
+	return r.exec().deleteChallengeProgress(ctx, namespace, challengeID)
+}
 
 // GetUserGoalCount returns the total number of goals for a user (active + inactive).
 func (r *PostgresGoalRepository) GetUserGoalCount(ctx context.Context, userID string) (int, error) {
-	query := `SELECT COUNT(*) FROM user_goal_progress WHERE user_id = $1`
-
-	var count int
-	err := r.db.QueryRowContext(ctx, query, userID).Scan(&count)
-	if err != nil {
-		return 0, errors.ErrDatabaseError("get user goal count", err)
-	}
-
-	return count, nil
+	return r.exec().getUserGoalCount(ctx, userID)
 }
 
 // GetActiveGoals retrieves only active goal progress records for a user.
 func (r *PostgresGoalRepository) GetActiveGoals(ctx context.Context, userID string) ([]*domain.UserGoalProgress, error) {
-	query := `
-		SELECT user_id, goal_id, challenge_id, namespace, progress, status,
-		       completed_at, claimed_at, created_at, updated_at,
-		       is_active, assigned_at, expires_at
-		FROM user_goal_progress
-		WHERE user_id = $1 AND is_active = true
-		ORDER BY challenge_id, goal_id
-	`
-
-	rows, err := r.db.QueryContext(ctx, query, userID)
-	if err != nil {
-		return nil, errors.ErrDatabaseError("get active goals", err)
-	}
-	defer func() {
-		_ = rows.Close()
-	}()
-
-	return r.scanProgressRows(rows)
+	return r.exec().getActiveGoals(ctx, userID)
 }
 
 // BeginTx starts a database transaction and returns a transactional repository.
+// With WithGate, the transaction holds the gate until Commit or Rollback.
 func (r *PostgresGoalRepository) BeginTx(ctx context.Context) (TxRepository, error) {
+	if err := r.acquireGate(); err != nil {
+		return nil, err
+	}
+
 	tx, err := r.db.BeginTx(ctx, nil)
 	if err != nil {
+		r.releaseGate()
 		return nil, errors.ErrDatabaseError("begin transaction", err)
 	}
 
 	return &PostgresTxRepository{
-		tx:     tx,
-		parent: r,
+		tx:      tx,
+		parent:  r,
+		release: sync.OnceFunc(r.releaseGate),
 	}, nil
 }
 
-// scanProgressRows is a helper to scan multiple progress rows.
-func (r *PostgresGoalRepository) scanProgressRows(rows *sql.Rows) ([]*domain.UserGoalProgress, error) {
-	var results []*domain.UserGoalProgress
-
-	for rows.Next() {
-		var progress domain.UserGoalProgress
-		err := rows.Scan(
-			&progress.UserID,
-			&progress.GoalID,
-			&progress.ChallengeID,
-			&progress.Namespace,
-			&progress.Progress,
-			&progress.Status,
-			&progress.CompletedAt,
-			&progress.ClaimedAt,
-			&progress.CreatedAt,
-			&progress.UpdatedAt,
-			&progress.IsActive,
-			&progress.AssignedAt,
-			&progress.ExpiresAt,
-		)
-		if err != nil {
-			return nil, errors.ErrDatabaseError("scan progress row", err)
-		}
-		results = append(results, &progress)
-	}
-
-	if err := rows.Err(); err != nil {
-		return nil, errors.ErrDatabaseError("iterate progress rows", err)
-	}
-
-	return results, nil
-}
-
-// PostgresTxRepository implements TxRepository interface for transactional operations.
-type PostgresTxRepository struct {
-	tx     *sql.Tx
-	parent *PostgresGoalRepository
-}
-
-// GetProgress retrieves progress within a transaction.
-func (r *PostgresTxRepository) GetProgress(ctx context.Context, userID, goalID string) (*domain.UserGoalProgress, error) {
-	query := `
-		SELECT user_id, goal_id, challenge_id, namespace, progress, status,
-		       completed_at, claimed_at, created_at, updated_at,
-		       is_active, assigned_at, expires_at
-		FROM user_goal_progress
-		WHERE user_id = $1 AND goal_id = $2
-	`
-
-	var progress domain.UserGoalProgress
-	err := r.tx.QueryRowContext(ctx, query, userID, goalID).Scan(
+// progressScanDest returns the scan destinations for progressColumns.
+func (r *PostgresGoalRepository) progressScanDest(progress *domain.UserGoalProgress) []interface{} {
+	dest := []interface{}{
 		&progress.UserID,
 		&progress.GoalID,
 		&progress.ChallengeID,
@@ -1028,563 +355,144 @@ func (r *PostgresTxRepository) GetProgress(ctx context.Context, userID, goalID s
 		&progress.IsActive,
 		&progress.AssignedAt,
 		&progress.ExpiresAt,
-	)
-
-	if err == sql.ErrNoRows {
-		return nil, nil
+		&progress.ForfeitedAt,
+		&progress.Attempts,
+		&progress.OrderIndex,
+	}
+	if r.configChecksum != nil {
+		dest = append(dest, &progress.ConfigChecksum)
+	}
+	if r.completions {
+		dest = append(dest, &progress.Completions)
 	}
+	return dest
+}
 
-	if err != nil {
-		return nil, errors.ErrDatabaseError("get progress in transaction", err)
+// scanProgressRows is a helper to scan multiple progress rows.
+func (r *PostgresGoalRepository) scanProgressRows(rows *sql.Rows) ([]*domain.UserGoalProgress, error) {
+	var results []*domain.UserGoalProgress
+	if err := r.streamProgressRows(rows, collectProgress(&results)); err != nil {
+		return nil, err
 	}
+	return results, nil
+}
 
-	return &progress, nil
+// collectProgress returns a stream callback that appends every row to results.
+func collectProgress(results *[]*domain.UserGoalProgress) func(*domain.UserGoalProgress) error {
+	return func(p *domain.UserGoalProgress) error {
+		*results = append(*results, p)
+		return nil
+	}
 }
 
-// GetProgressForUpdate retrieves progress with SELECT ... FOR UPDATE (row-level lock).
-func (r *PostgresTxRepository) GetProgressForUpdate(ctx context.Context, userID, goalID string) (*domain.UserGoalProgress, error) {
-	query := `
-		SELECT user_id, goal_id, challenge_id, namespace, progress, status,
-		       completed_at, claimed_at, created_at, updated_at,
-		       is_active, assigned_at, expires_at
-		FROM user_goal_progress
-		WHERE user_id = $1 AND goal_id = $2
-		FOR UPDATE
-	`
-
-	var progress domain.UserGoalProgress
-	err := r.tx.QueryRowContext(ctx, query, userID, goalID).Scan(
-		&progress.UserID,
-		&progress.GoalID,
-		&progress.ChallengeID,
-		&progress.Namespace,
-		&progress.Progress,
-		&progress.Status,
-		&progress.CompletedAt,
-		&progress.ClaimedAt,
-		&progress.CreatedAt,
-		&progress.UpdatedAt,
-		&progress.IsActive,
-		&progress.AssignedAt,
-		&progress.ExpiresAt,
-	)
+// PostgresTxRepository implements TxRepository interface for transactional operations.
+// Queries are shared with PostgresGoalRepository (see executor.go).
+type PostgresTxRepository struct {
+	tx      *sql.Tx
+	parent  *PostgresGoalRepository
+	release func() // Releases the parent's gate once the transaction ends
+}
 
-	if err == sql.ErrNoRows {
-		return nil, nil
-	}
+// GetProgress retrieves progress within a transaction.
+func (r *PostgresTxRepository) GetProgress(ctx context.Context, userID, goalID string) (*domain.UserGoalProgress, error) {
+	return r.exec().getProgress(ctx, userID, goalID, false)
+}
 
-	if err != nil {
-		return nil, errors.ErrDatabaseError("get progress for update", err)
-	}
+// GetProgressRequired retrieves progress within a transaction, failing with
+// errors.ErrProgressNotFound when no row exists.
+func (r *PostgresTxRepository) GetProgressRequired(ctx context.Context, userID, goalID string) (*domain.UserGoalProgress, error) {
+	return r.exec().getProgressRequired(ctx, userID, goalID)
+}
 
-	return &progress, nil
+// GetProgressForUpdate retrieves progress with SELECT ... FOR UPDATE (row-level lock).
+func (r *PostgresTxRepository) GetProgressForUpdate(ctx context.Context, userID, goalID string) (*domain.UserGoalProgress, error) {
+	return r.exec().getProgress(ctx, userID, goalID, true)
 }
 
 // GetUserProgress retrieves all user progress within a transaction.
 // M3 Phase 4: activeOnly parameter filters to only is_active = true goals.
 func (r *PostgresTxRepository) GetUserProgress(ctx context.Context, userID string, activeOnly bool) ([]*domain.UserGoalProgress, error) {
-	query := `
-		SELECT user_id, goal_id, challenge_id, namespace, progress, status,
-		       completed_at, claimed_at, created_at, updated_at,
-		       is_active, assigned_at, expires_at
-		FROM user_goal_progress
-		WHERE user_id = $1
-	`
-
-	// M3 Phase 4: Add is_active filter when activeOnly is true
-	if activeOnly {
-		query += " AND is_active = true"
-	}
-
-	query += " ORDER BY created_at ASC"
-
-	rows, err := r.tx.QueryContext(ctx, query, userID)
-	if err != nil {
-		return nil, errors.ErrDatabaseError("get user progress in transaction", err)
-	}
-	defer func() { _ = rows.Close() }()
+	return r.exec().getUserProgress(ctx, userID, activeOnly)
+}
 
-	return r.parent.scanProgressRows(rows)
+// GetUserProgressLite retrieves the goal ID, progress and status of a user's goals
+// within a transaction.
+func (r *PostgresTxRepository) GetUserProgressLite(ctx context.Context, userID string, activeOnly bool) ([]ProgressLite, error) {
+	return r.exec().getUserProgressLite(ctx, userID, activeOnly)
 }
 
 // GetChallengeProgress retrieves challenge progress within a transaction.
 // M3 Phase 4: activeOnly parameter filters to only is_active = true goals.
 func (r *PostgresTxRepository) GetChallengeProgress(ctx context.Context, userID, challengeID string, activeOnly bool) ([]*domain.UserGoalProgress, error) {
-	query := `
-		SELECT user_id, goal_id, challenge_id, namespace, progress, status,
-		       completed_at, claimed_at, created_at, updated_at,
-		       is_active, assigned_at, expires_at
-		FROM user_goal_progress
-		WHERE user_id = $1 AND challenge_id = $2
-	`
-
-	// M3 Phase 4: Add is_active filter when activeOnly is true
-	if activeOnly {
-		query += " AND is_active = true"
-	}
-
-	query += " ORDER BY created_at ASC"
-
-	rows, err := r.tx.QueryContext(ctx, query, userID, challengeID)
-	if err != nil {
-		return nil, errors.ErrDatabaseError("get challenge progress in transaction", err)
-	}
-	defer func() { _ = rows.Close() }()
-
-	return r.parent.scanProgressRows(rows)
+	return r.exec().getChallengeProgress(ctx, userID, challengeID, activeOnly)
 }
 
 // UpsertProgress upserts progress within a transaction.
+// Unlike the pool variant, is_active, assigned_at and expires_at are left untouched.
 func (r *PostgresTxRepository) UpsertProgress(ctx context.Context, progress *domain.UserGoalProgress) error {
-	query := `
-		INSERT INTO user_goal_progress (
-			user_id, goal_id, challenge_id, namespace,
-			progress, status, completed_at, updated_at
-		) VALUES (
-			$1, $2, $3, $4, $5, $6, $7, NOW()
-		)
-		ON CONFLICT (user_id, goal_id) DO UPDATE SET
-			progress = EXCLUDED.progress,
-			status = EXCLUDED.status,
-			completed_at = EXCLUDED.completed_at,
-			updated_at = NOW()
-		WHERE user_goal_progress.status != 'claimed'
-	`
-
-	_, err := r.tx.ExecContext(ctx, query,
-		progress.UserID,
-		progress.GoalID,
-		progress.ChallengeID,
-		progress.Namespace,
-		progress.Progress,
-		progress.Status,
-		progress.CompletedAt,
-	)
-
-	if err != nil {
-		return errors.ErrDatabaseError("upsert progress in transaction", err)
-	}
-
-	return nil
+	return r.exec().upsertProgress(ctx, progress)
 }
 
 // BatchUpsertProgress batch upserts within a transaction.
 // DEPRECATED: Use BatchUpsertProgressWithCOPY for better performance.
 func (r *PostgresTxRepository) BatchUpsertProgress(ctx context.Context, updates []*domain.UserGoalProgress) error {
-	if len(updates) == 0 {
-		return nil
-	}
-
-	if len(updates) > 9000 {
-		return fmt.Errorf("batch size exceeds PostgreSQL parameter limit: %d rows (max 9000)", len(updates))
-	}
-
-	valueStrings := make([]string, 0, len(updates))
-	valueArgs := make([]interface{}, 0, len(updates)*7)
-
-	for i, update := range updates {
-		valueStrings = append(valueStrings, fmt.Sprintf(
-			"($%d, $%d, $%d, $%d, $%d, $%d, $%d, NOW())",
-			i*7+1, i*7+2, i*7+3, i*7+4, i*7+5, i*7+6, i*7+7,
-		))
-		valueArgs = append(valueArgs,
-			update.UserID,
-			update.GoalID,
-			update.ChallengeID,
-			update.Namespace,
-			update.Progress,
-			update.Status,
-			update.CompletedAt,
-		)
-	}
-
-	// Safe: fmt.Sprintf only builds the VALUES structure with placeholders ($1, $2, etc.)
-	// All actual values are passed via parameterized query (valueArgs), not string interpolation
-	// #nosec G201
-	query := fmt.Sprintf(`
-		INSERT INTO user_goal_progress (
-			user_id, goal_id, challenge_id, namespace,
-			progress, status, completed_at, updated_at
-		) VALUES %s
-		ON CONFLICT (user_id, goal_id) DO UPDATE SET
-			progress = EXCLUDED.progress,
-			status = EXCLUDED.status,
-			completed_at = EXCLUDED.completed_at,
-			updated_at = NOW()
-		WHERE user_goal_progress.status != 'claimed'
-	`, strings.Join(valueStrings, ","))
-
-	_, err := r.tx.ExecContext(ctx, query, valueArgs...)
-	if err != nil {
-		return errors.ErrDatabaseError("batch upsert progress in transaction", err)
-	}
-
-	return nil
+	return r.exec().batchUpsertProgress(ctx, updates)
 }
 
 // BatchUpsertProgressWithCOPY performs batch upsert using COPY protocol within a transaction.
-// This is 5-10x faster than BatchUpsertProgress.
+// This is 5-10x faster than BatchUpsertProgress. The temp table is dropped when the
+// transaction commits or rolls back.
 func (r *PostgresTxRepository) BatchUpsertProgressWithCOPY(ctx context.Context, updates []*domain.UserGoalProgress) error {
-	if len(updates) == 0 {
-		return nil
-	}
-
-	// Note: We're already in a transaction (r.tx), so we don't need to BEGIN/COMMIT
-	// The temp table will be dropped when the parent transaction commits/rollbacks
-
-	// Step 1: Create temporary table
-	_, err := r.tx.ExecContext(ctx, `
-		CREATE TEMP TABLE IF NOT EXISTS temp_user_goal_progress (
-			user_id VARCHAR(100) NOT NULL,
-			goal_id VARCHAR(100) NOT NULL,
-			challenge_id VARCHAR(100) NOT NULL,
-			namespace VARCHAR(100) NOT NULL,
-			progress INT NOT NULL,
-			status VARCHAR(20) NOT NULL,
-			completed_at TIMESTAMP NULL,
-			updated_at TIMESTAMP NOT NULL DEFAULT NOW()
-		) ON COMMIT DROP
-	`)
-	if err != nil {
-		return errors.ErrDatabaseError("create temp table for COPY in transaction", err)
-	}
-
-	// Step 2: Prepare COPY statement
-	stmt, err := r.tx.PrepareContext(ctx, pq.CopyIn(
-		"temp_user_goal_progress",
-		"user_id", "goal_id", "challenge_id", "namespace",
-		"progress", "status", "completed_at", "updated_at",
-	))
-	if err != nil {
-		return errors.ErrDatabaseError("prepare COPY statement in transaction", err)
-	}
-	defer func() { _ = stmt.Close() }()
-
-	// Step 3: Bulk load data
-	now := time.Now().UTC() // Always use UTC for consistency across timezones
-	for _, update := range updates {
-		_, err = stmt.ExecContext(ctx,
-			update.UserID,
-			update.GoalID,
-			update.ChallengeID,
-			update.Namespace,
-			update.Progress,
-			update.Status,
-			update.CompletedAt,
-			now,
-		)
-		if err != nil {
-			return errors.ErrDatabaseError("execute COPY row in transaction", err)
-		}
-	}
-
-	// Step 4: Execute COPY
-	_, err = stmt.ExecContext(ctx)
-	if err != nil {
-		return errors.ErrDatabaseError("flush COPY to temp table in transaction", err)
-	}
-
-	// Step 5: Merge temp table into main table
-	_, err = r.tx.ExecContext(ctx, `
-		INSERT INTO user_goal_progress (
-			user_id, goal_id, challenge_id, namespace,
-			progress, status, completed_at, updated_at
-		)
-		SELECT
-			user_id, goal_id, challenge_id, namespace,
-			progress, status, completed_at, NOW()
-		FROM temp_user_goal_progress
-		ON CONFLICT (user_id, goal_id) DO UPDATE SET
-			progress = EXCLUDED.progress,
-			status = EXCLUDED.status,
-			completed_at = EXCLUDED.completed_at,
-			updated_at = NOW()
-		WHERE user_goal_progress.status != 'claimed'
-	`)
-	if err != nil {
-		return errors.ErrDatabaseError("merge temp table into user_goal_progress in transaction", err)
-	}
-
-	return nil
+	return r.exec().batchUpsertProgressWithCOPY(ctx, updates)
 }
 
-// IncrementProgress atomically increments progress within a transaction.
+// IncrementProgress atomically increments progress within a transaction, creating the row if missing.
 func (r *PostgresTxRepository) IncrementProgress(ctx context.Context, userID, goalID, challengeID, namespace string, delta, targetValue int, isDailyIncrement bool) error {
-	if isDailyIncrement {
-		return r.incrementProgressDaily(ctx, userID, goalID, challengeID, namespace, delta, targetValue)
-	}
-	return r.incrementProgressRegular(ctx, userID, goalID, challengeID, namespace, delta, targetValue)
-}
-
-// incrementProgressRegular handles regular increments within a transaction
-func (r *PostgresTxRepository) incrementProgressRegular(ctx context.Context, userID, goalID, challengeID, namespace string, delta, targetValue int) error {
-	query := `
-		INSERT INTO user_goal_progress (
-			user_id,
-			goal_id,
-			challenge_id,
-			namespace,
-			progress,
-			status,
-			completed_at,
-			updated_at
-		) VALUES (
-			$1, $2, $3, $4, $5::INT,
-			CASE WHEN $5::INT >= $6::INT THEN 'completed' ELSE 'in_progress' END,
-			CASE WHEN $5::INT >= $6::INT THEN NOW() ELSE NULL END,
-			NOW()
-		)
-		ON CONFLICT (user_id, goal_id) DO UPDATE SET
-			progress = user_goal_progress.progress + $5::INT,
-			status = CASE
-				WHEN user_goal_progress.progress + $5::INT >= $6::INT THEN 'completed'
-				ELSE 'in_progress'
-			END,
-			completed_at = CASE
-				WHEN user_goal_progress.progress + $5::INT >= $6::INT AND user_goal_progress.completed_at IS NULL
-					THEN NOW()
-				ELSE user_goal_progress.completed_at
-			END,
-			updated_at = NOW()
-		WHERE user_goal_progress.status != 'claimed'
-	`
-
-	_, err := r.tx.ExecContext(ctx, query, userID, goalID, challengeID, namespace, delta, targetValue)
-	if err != nil {
-		return errors.ErrDatabaseError("increment progress (regular) in transaction", err)
-	}
-
-	return nil
+	return r.exec().incrementProgress(ctx, userID, goalID, challengeID, namespace, delta, targetValue, isDailyIncrement)
 }
 
-// incrementProgressDaily handles daily increments within a transaction
-func (r *PostgresTxRepository) incrementProgressDaily(ctx context.Context, userID, goalID, challengeID, namespace string, delta, targetValue int) error {
-	query := `
-		INSERT INTO user_goal_progress (
-			user_id,
-			goal_id,
-			challenge_id,
-			namespace,
-			progress,
-			status,
-			completed_at,
-			updated_at
-		) VALUES (
-			$1, $2, $3, $4, 1,
-			CASE WHEN 1 >= $6::INT THEN 'completed' ELSE 'in_progress' END,
-			CASE WHEN 1 >= $6::INT THEN NOW() ELSE NULL END,
-			NOW()
-		)
-		ON CONFLICT (user_id, goal_id) DO UPDATE SET
-			progress = CASE
-				WHEN DATE(user_goal_progress.updated_at AT TIME ZONE 'UTC') = DATE(NOW() AT TIME ZONE 'UTC')
-					THEN user_goal_progress.progress
-				ELSE user_goal_progress.progress + $5::INT
-			END,
-			status = CASE
-				WHEN DATE(user_goal_progress.updated_at AT TIME ZONE 'UTC') = DATE(NOW() AT TIME ZONE 'UTC') THEN
-					CASE WHEN user_goal_progress.progress >= $6::INT THEN 'completed' ELSE 'in_progress' END
-				ELSE
-					CASE WHEN user_goal_progress.progress + $5::INT >= $6::INT THEN 'completed' ELSE 'in_progress' END
-			END,
-			completed_at = CASE
-				WHEN DATE(user_goal_progress.updated_at AT TIME ZONE 'UTC') = DATE(NOW() AT TIME ZONE 'UTC') THEN
-					user_goal_progress.completed_at
-				WHEN user_goal_progress.progress + $5::INT >= $6::INT AND user_goal_progress.completed_at IS NULL THEN
-					NOW()
-				ELSE
-					user_goal_progress.completed_at
-			END,
-			updated_at = NOW()
-		WHERE user_goal_progress.status != 'claimed'
-	`
-
-	_, err := r.tx.ExecContext(ctx, query, userID, goalID, challengeID, namespace, delta, targetValue)
-	if err != nil {
-		return errors.ErrDatabaseError("increment progress (daily) in transaction", err)
-	}
-
-	return nil
+// IncrementProgressWithCooldown atomically increments progress within a transaction
+// unless the row is inside its cooldown window.
+func (r *PostgresTxRepository) IncrementProgressWithCooldown(ctx context.Context, userID, goalID, challengeID, namespace string, delta, targetValue int, cooldown time.Duration) error {
+	return r.exec().incrementProgressWithCooldown(ctx, userID, goalID, challengeID, namespace, delta, targetValue, cooldown)
 }
 
 // BatchIncrementProgress performs batch atomic increment within a transaction.
 func (r *PostgresTxRepository) BatchIncrementProgress(ctx context.Context, increments []ProgressIncrement) error {
-	if len(increments) == 0 {
-		return nil
-	}
-
-	// Build arrays for UNNEST
-	userIDs := make([]string, len(increments))
-	goalIDs := make([]string, len(increments))
-	challengeIDs := make([]string, len(increments))
-	namespaces := make([]string, len(increments))
-	deltas := make([]int, len(increments))
-	targetValues := make([]int, len(increments))
-	isDailyFlags := make([]bool, len(increments))
-
-	for i, inc := range increments {
-		userIDs[i] = inc.UserID
-		goalIDs[i] = inc.GoalID
-		challengeIDs[i] = inc.ChallengeID
-		namespaces[i] = inc.Namespace
-		deltas[i] = inc.Delta
-		targetValues[i] = inc.TargetValue
-		isDailyFlags[i] = inc.IsDailyIncrement
-	}
-
-	query := `
-		INSERT INTO user_goal_progress (
-			user_id,
-			goal_id,
-			challenge_id,
-			namespace,
-			progress,
-			status,
-			completed_at,
-			updated_at
-		)
-		SELECT
-			t.user_id,
-			t.goal_id,
-			t.challenge_id,
-			t.namespace,
-			t.delta,
-			initial.status,
-			initial.completed_at,
-			NOW()
-		FROM UNNEST(
-			$1::VARCHAR(100)[],
-			$2::VARCHAR(100)[],
-			$3::VARCHAR(100)[],
-			$4::VARCHAR(100)[],
-			$5::INT[],
-			$6::INT[],
-			$7::BOOLEAN[]
-		) AS t(user_id, goal_id, challenge_id, namespace, delta, target_value, is_daily)
-		CROSS JOIN LATERAL (
-			SELECT
-				CASE WHEN t.delta >= t.target_value THEN 'completed' ELSE 'in_progress' END as status,
-				CASE WHEN t.delta >= t.target_value THEN NOW() ELSE NULL END as completed_at
-		) AS initial
-		ON CONFLICT (user_id, goal_id) DO UPDATE SET
-			progress = CASE
-				WHEN (SELECT is_daily FROM UNNEST($7::BOOLEAN[], $2::VARCHAR(100)[]) AS u(is_daily, gid)
-				      WHERE u.gid = user_goal_progress.goal_id LIMIT 1) = true
-				     AND DATE(user_goal_progress.updated_at AT TIME ZONE 'UTC') = DATE(NOW() AT TIME ZONE 'UTC')
-					THEN user_goal_progress.progress
-				ELSE
-					user_goal_progress.progress + (
-						SELECT delta FROM UNNEST($5::INT[], $2::VARCHAR(100)[]) AS u(delta, gid)
-						WHERE u.gid = user_goal_progress.goal_id LIMIT 1
-					)
-			END,
-			status = CASE
-				WHEN (SELECT is_daily FROM UNNEST($7::BOOLEAN[], $2::VARCHAR(100)[]) AS u(is_daily, gid)
-				      WHERE u.gid = user_goal_progress.goal_id LIMIT 1) = true
-				     AND DATE(user_goal_progress.updated_at AT TIME ZONE 'UTC') = DATE(NOW() AT TIME ZONE 'UTC') THEN
-					CASE WHEN user_goal_progress.progress >= (
-						SELECT target_value FROM UNNEST($6::INT[], $2::VARCHAR(100)[]) AS u(target_value, gid)
-						WHERE u.gid = user_goal_progress.goal_id LIMIT 1
-					) THEN 'completed' ELSE 'in_progress' END
-				ELSE
-					CASE WHEN user_goal_progress.progress + (
-						SELECT delta FROM UNNEST($5::INT[], $2::VARCHAR(100)[]) AS u(delta, gid)
-						WHERE u.gid = user_goal_progress.goal_id LIMIT 1
-					) >= (
-						SELECT target_value FROM UNNEST($6::INT[], $2::VARCHAR(100)[]) AS u(target_value, gid)
-						WHERE u.gid = user_goal_progress.goal_id LIMIT 1
-					) THEN 'completed' ELSE 'in_progress' END
-			END,
-			completed_at = CASE
-				WHEN (SELECT is_daily FROM UNNEST($7::BOOLEAN[], $2::VARCHAR(100)[]) AS u(is_daily, gid)
-				      WHERE u.gid = user_goal_progress.goal_id LIMIT 1) = true
-				     AND DATE(user_goal_progress.updated_at AT TIME ZONE 'UTC') = DATE(NOW() AT TIME ZONE 'UTC') THEN
-					user_goal_progress.completed_at
-				WHEN user_goal_progress.progress + (
-					SELECT delta FROM UNNEST($5::INT[], $2::VARCHAR(100)[]) AS u(delta, gid)
-					WHERE u.gid = user_goal_progress.goal_id LIMIT 1
-				) >= (
-					SELECT target_value FROM UNNEST($6::INT[], $2::VARCHAR(100)[]) AS u(target_value, gid)
-					WHERE u.gid = user_goal_progress.goal_id LIMIT 1
-				) AND user_goal_progress.completed_at IS NULL THEN
-					NOW()
-				ELSE
-					user_goal_progress.completed_at
-			END,
-			updated_at = NOW()
-		WHERE user_goal_progress.status != 'claimed'
-	`
-
-	_, err := r.tx.ExecContext(ctx, query,
-		pq.Array(userIDs),
-		pq.Array(goalIDs),
-		pq.Array(challengeIDs),
-		pq.Array(namespaces),
-		pq.Array(deltas),
-		pq.Array(targetValues),
-		pq.Array(isDailyFlags),
-	)
+	return r.exec().batchIncrementProgress(ctx, increments)
+}
 
+// BatchIncrementProgressReturning performs batch atomic increment within a transaction
+// and returns rows left in 'completed' status.
+func (r *PostgresTxRepository) BatchIncrementProgressReturning(ctx context.Context, increments []ProgressIncrement) ([]CompletionResult, error) {
+	result, err := r.BatchIncrementProgressWithResult(ctx, increments)
 	if err != nil {
-		return errors.ErrDatabaseError("batch increment progress in transaction", err)
+		return nil, err
 	}
+	return result.Completions, nil
+}
 
-	return nil
+// BatchIncrementProgressWithResult performs batch atomic increment within a transaction and
+// returns rows left in 'completed' status along with the number of late entries skipped.
+func (r *PostgresTxRepository) BatchIncrementProgressWithResult(ctx context.Context, increments []ProgressIncrement) (BatchIncrementResult, error) {
+	return r.exec().batchIncrementProgressWithResult(ctx, increments)
 }
 
 // MarkAsClaimed marks a goal as claimed within a transaction.
 func (r *PostgresTxRepository) MarkAsClaimed(ctx context.Context, userID, goalID string) error {
-	query := `
-		UPDATE user_goal_progress
-		SET status = 'claimed',
-			claimed_at = NOW(),
-			updated_at = NOW()
-		WHERE user_id = $1 AND goal_id = $2
-		AND status = 'completed'
-		AND claimed_at IS NULL
-	`
-
-	result, err := r.tx.ExecContext(ctx, query, userID, goalID)
-	if err != nil {
-		return errors.ErrDatabaseError("mark as claimed in transaction", err)
-	}
-
-	rowsAffected, err := result.RowsAffected()
-	if err != nil {
-		return errors.ErrDatabaseError("check rows affected", err)
-	}
-
-	if rowsAffected == 0 {
-		return errors.ErrGoalNotCompleted(goalID)
-	}
+	return r.exec().markAsClaimed(ctx, userID, goalID, 0)
+}
 
-	return nil
+// MarkAsClaimedWithDeadline claims a goal completed within claimDeadline within a transaction.
+func (r *PostgresTxRepository) MarkAsClaimedWithDeadline(ctx context.Context, userID, goalID string, claimDeadline time.Duration) error {
+	return r.exec().markAsClaimed(ctx, userID, goalID, claimDeadline)
 }
 
 // M3: Goal assignment control methods
 
 // GetGoalsByIDs retrieves goal progress records within a transaction.
 func (r *PostgresTxRepository) GetGoalsByIDs(ctx context.Context, userID string, goalIDs []string) ([]*domain.UserGoalProgress, error) {
-	if len(goalIDs) == 0 {
-		return []*domain.UserGoalProgress{}, nil
-	}
-
-	query := `
-		SELECT user_id, goal_id, challenge_id, namespace, progress, status,
-		       completed_at, claimed_at, created_at, updated_at,
-		       is_active, assigned_at, expires_at
-		FROM user_goal_progress
-		WHERE user_id = $1 AND goal_id = ANY($2)
-		ORDER BY created_at ASC
-	`
-
-	rows, err := r.tx.QueryContext(ctx, query, userID, pq.Array(goalIDs))
-	if err != nil {
-		return nil, errors.ErrDatabaseError("get goals by IDs in transaction", err)
-	}
-	defer func() { _ = rows.Close() }()
-
-	return r.parent.scanProgressRows(rows)
+	return r.parent.getGoalsByIDs(ctx, r.tx, "get goals by IDs in transaction", userID, goalIDs, ProgressFilter{})
 }
 
 // BulkInsert creates multiple goal progress records within a transaction.
@@ -1592,52 +500,13 @@ func (r *PostgresTxRepository) GetGoalsByIDs(ctx context.Context, userID string,
 // DEPRECATED: Use BulkInsertWithCOPY for better performance (3-5x faster).
 // This method is kept for backwards compatibility and testing.
 func (r *PostgresTxRepository) BulkInsert(ctx context.Context, progresses []*domain.UserGoalProgress) error {
-	if len(progresses) == 0 {
-		return nil
-	}
-
-	// Build values for bulk insert (11 parameters per row)
-	valueStrings := make([]string, 0, len(progresses))
-	valueArgs := make([]interface{}, 0, len(progresses)*11)
-
-	for i, p := range progresses {
-		valueStrings = append(valueStrings, fmt.Sprintf(
-			"($%d, $%d, $%d, $%d, $%d, $%d, $%d, $%d, NOW(), NOW(), $%d, $%d, $%d)",
-			i*11+1, i*11+2, i*11+3, i*11+4, i*11+5, i*11+6, i*11+7, i*11+8, i*11+9, i*11+10, i*11+11,
-		))
-
-		valueArgs = append(valueArgs,
-			p.UserID,
-			p.GoalID,
-			p.ChallengeID,
-			p.Namespace,
-			p.Progress,
-			p.Status,
-			p.CompletedAt,
-			p.ClaimedAt,
-			p.IsActive,
-			p.AssignedAt,
-			p.ExpiresAt,
-		)
-	}
-
-	//nolint:gosec // Safe: valueStrings contains only parameterized placeholders like "($1, $2, $3)", not user input
-	query := fmt.Sprintf(`
-		INSERT INTO user_goal_progress (
-			user_id, goal_id, challenge_id, namespace,
-			progress, status, completed_at, claimed_at,
-			created_at, updated_at,
-			is_active, assigned_at, expires_at
-		) VALUES %s
-		ON CONFLICT (user_id, goal_id) DO NOTHING
-	`, strings.Join(valueStrings, ","))
-
-	_, err := r.tx.ExecContext(ctx, query, valueArgs...)
-	if err != nil {
-		return errors.ErrDatabaseError("bulk insert goals in transaction", err)
-	}
+	return r.exec().bulkInsert(ctx, progresses)
+}
 
-	return nil
+// BulkInsertWithPriority inserts the highest-priority rows that fit under the user's row
+// cap within a transaction.
+func (r *PostgresTxRepository) BulkInsertWithPriority(ctx context.Context, progresses []*domain.UserGoalProgress, priorities []int, maxRowsForUser int) ([]string, []string, error) {
+	return r.exec().bulkInsertWithPriority(ctx, progresses, priorities, maxRowsForUser)
 }
 
 // BulkInsertWithCOPY creates multiple goal progress records using COPY protocol within a transaction.
@@ -1652,293 +521,47 @@ func (r *PostgresTxRepository) BulkInsert(ctx context.Context, progresses []*dom
 // See PostgresGoalRepository.BulkInsertWithCOPY for detailed benchmark results and usage guidelines.
 // For small batches (< 1000 records), use BulkInsert() instead.
 func (r *PostgresTxRepository) BulkInsertWithCOPY(ctx context.Context, progresses []*domain.UserGoalProgress) error {
-	if len(progresses) == 0 {
-		return nil
-	}
-
-	// Note: We're already in a transaction (r.tx), so we don't need to BEGIN/COMMIT
-	// The temp table will be dropped when the parent transaction commits/rollbacks
-
-	// Step 1: Create temporary table
-	_, err := r.tx.ExecContext(ctx, `
-		CREATE TEMP TABLE IF NOT EXISTS temp_bulk_insert (
-			user_id VARCHAR(100) NOT NULL,
-			goal_id VARCHAR(100) NOT NULL,
-			challenge_id VARCHAR(100) NOT NULL,
-			namespace VARCHAR(100) NOT NULL,
-			progress INT NOT NULL,
-			status VARCHAR(20) NOT NULL,
-			completed_at TIMESTAMP NULL,
-			claimed_at TIMESTAMP NULL,
-			created_at TIMESTAMP NOT NULL DEFAULT NOW(),
-			updated_at TIMESTAMP NOT NULL DEFAULT NOW(),
-			is_active BOOLEAN NOT NULL DEFAULT false,
-			assigned_at TIMESTAMP NULL,
-			expires_at TIMESTAMP NULL
-		) ON COMMIT DROP
-	`)
-	if err != nil {
-		return errors.ErrDatabaseError("create temp table for BulkInsert COPY in transaction", err)
-	}
-
-	// Step 2: Prepare COPY statement
-	stmt, err := r.tx.PrepareContext(ctx, pq.CopyIn(
-		"temp_bulk_insert",
-		"user_id", "goal_id", "challenge_id", "namespace",
-		"progress", "status", "completed_at", "claimed_at",
-		"created_at", "updated_at",
-		"is_active", "assigned_at", "expires_at",
-	))
-	if err != nil {
-		return errors.ErrDatabaseError("prepare COPY statement for BulkInsert in transaction", err)
-	}
-	defer func() { _ = stmt.Close() }()
-
-	// Step 3: Bulk load data
-	now := time.Now().UTC() // Always use UTC for consistency across timezones
-	for _, p := range progresses {
-		_, err = stmt.ExecContext(ctx,
-			p.UserID,
-			p.GoalID,
-			p.ChallengeID,
-			p.Namespace,
-			p.Progress,
-			p.Status,
-			p.CompletedAt,
-			p.ClaimedAt,
-			now,
-			now,
-			p.IsActive,
-			p.AssignedAt,
-			p.ExpiresAt,
-		)
-		if err != nil {
-			return errors.ErrDatabaseError("execute COPY row for BulkInsert in transaction", err)
-		}
-	}
-
-	// Step 4: Execute COPY
-	_, err = stmt.ExecContext(ctx)
-	if err != nil {
-		return errors.ErrDatabaseError("flush COPY to temp table for BulkInsert in transaction", err)
-	}
-
-	// Step 5: Insert from temp table to main table
-	_, err = r.tx.ExecContext(ctx, `
-		INSERT INTO user_goal_progress (
-			user_id, goal_id, challenge_id, namespace,
-			progress, status, completed_at, claimed_at,
-			created_at, updated_at,
-			is_active, assigned_at, expires_at
-		)
-		SELECT
-			user_id, goal_id, challenge_id, namespace,
-			progress, status, completed_at, claimed_at,
-			created_at, updated_at,
-			is_active, assigned_at, expires_at
-		FROM temp_bulk_insert
-		ON CONFLICT (user_id, goal_id) DO NOTHING
-	`)
-	if err != nil {
-		return errors.ErrDatabaseError("insert from temp table for BulkInsert in transaction", err)
-	}
-
-	return nil
+	return r.exec().bulkInsertWithCOPY(ctx, progresses)
 }
 
 // UpsertGoalActive creates or updates a goal's is_active status within a transaction.
+// Activation always resets assigned_at to NOW().
 func (r *PostgresTxRepository) UpsertGoalActive(ctx context.Context, progress *domain.UserGoalProgress) error {
-	// M3 Phase 5: UpsertGoalActive is designed to toggle is_active on existing rows.
-	// Use UPDATE instead of INSERT...ON CONFLICT to avoid check constraint violations
-	// when Status field is empty.
-	query := `
-		UPDATE user_goal_progress SET
-			is_active = $1,
-			assigned_at = CASE
-				WHEN $1 = true THEN NOW()
-				ELSE assigned_at
-			END,
-			updated_at = NOW()
-		WHERE user_id = $2
-		  AND goal_id = $3
-	`
-
-	result, err := r.tx.ExecContext(ctx, query,
-		progress.IsActive,
-		progress.UserID,
-		progress.GoalID,
-	)
-
-	if err != nil {
-		return errors.ErrDatabaseError("update goal active in transaction", err)
-	}
-
-	// Check if the row was actually updated
-	rowsAffected, err := result.RowsAffected()
-	if err != nil {
-		return errors.ErrDatabaseError("check rows affected", err)
-	}
-
-	if rowsAffected == 0 {
-		// Row doesn't exist - insert with sensible defaults
-		insertQuery := `
-			INSERT INTO user_goal_progress (
-				user_id, goal_id, challenge_id, namespace,
-				progress, status, is_active, assigned_at,
-				created_at, updated_at
-			) VALUES (
-				$1, $2, $3, $4, 0, 'not_started', $5,
-				CASE WHEN $5 = true THEN NOW() ELSE NULL END,
-				NOW(), NOW()
-			)
-		`
-
-		_, err = r.tx.ExecContext(ctx, insertQuery,
-			progress.UserID,
-			progress.GoalID,
-			progress.ChallengeID,
-			progress.Namespace,
-			progress.IsActive,
-		)
-
-		if err != nil {
-			return errors.ErrDatabaseError("insert goal active in transaction", err)
-		}
-	}
-
-	return nil
+	return r.exec().upsertGoalActive(ctx, progress, ActivationOptions{UpdateAssignedAt: true})
 }
 
 // BatchUpsertGoalActive updates is_active status for multiple goals in a single database operation within a transaction (M4).
-// Transaction version of BatchUpsertGoalActive - uses r.tx instead of r.db.
-//
-// Supports both activation (is_active=true) and deactivation (is_active=false) through the same method.
-// This flexibility is required for M4's replace mode, where existing goals are deactivated before selecting new ones.
-//
-// Implementation uses UNNEST to map each goal to its specific is_active value:
-// 1. UPDATE existing rows: SET is_active = data.is_active FROM (UNNEST(goal_ids, is_active_vals))
-// 2. INSERT missing rows: INSERT ... ON CONFLICT DO UPDATE SET is_active = EXCLUDED.is_active
-//
-// Performance: ~10ms for 10 goals (vs ~20-50ms with individual UpsertGoalActive loop)
+// See PostgresGoalRepository.BatchUpsertGoalActive.
 func (r *PostgresTxRepository) BatchUpsertGoalActive(ctx context.Context, progresses []*domain.UserGoalProgress) error {
-	if len(progresses) == 0 {
-		return nil
-	}
-
-	// Extract goal IDs and is_active values
-	goalIDs := make([]string, len(progresses))
-	isActiveVals := make([]bool, len(progresses))
-	userID := progresses[0].UserID // All progresses should have the same user_id
-
-	for i, p := range progresses {
-		goalIDs[i] = p.GoalID
-		isActiveVals[i] = p.IsActive
-	}
-
-	// Step 1: Batch UPDATE existing rows using UNNEST to map each goal to its is_active value
-	updateQuery := `
-		UPDATE user_goal_progress SET
-			is_active = data.is_active,
-			assigned_at = CASE WHEN data.is_active THEN NOW() ELSE NULL END,
-			updated_at = NOW()
-		FROM (
-			SELECT UNNEST($2::text[]) AS goal_id, UNNEST($3::boolean[]) AS is_active
-		) AS data
-		WHERE user_goal_progress.user_id = $1
-		  AND user_goal_progress.goal_id = data.goal_id
-	`
-
-	result, err := r.tx.ExecContext(ctx, updateQuery, userID, pq.Array(goalIDs), pq.Array(isActiveVals))
-	if err != nil {
-		return errors.ErrDatabaseError("batch update goal active in transaction", err)
-	}
-
-	// Check how many rows were updated
-	rowsUpdated, err := result.RowsAffected()
-	if err != nil {
-		return errors.ErrDatabaseError("check rows affected in transaction", err)
-	}
-
-	// If all rows were updated, we're done
-	if int(rowsUpdated) == len(progresses) {
-		return nil
-	}
-
-	// Step 2: Batch INSERT missing rows with actual is_active values
-	// Use ON CONFLICT DO UPDATE to handle race conditions
-	insertQuery := `
-		INSERT INTO user_goal_progress (
-			user_id, goal_id, challenge_id, namespace,
-			progress, status, is_active, assigned_at,
-			created_at, updated_at
-		) VALUES
-	`
-
-	values := make([]interface{}, 0, len(progresses)*5) // 5 actual values per row
-	valuePlaceholders := make([]string, 0, len(progresses))
-
-	for i, p := range progresses {
-		offset := i * 5
-		valuePlaceholders = append(valuePlaceholders, fmt.Sprintf(
-			"($%d, $%d, $%d, $%d, 0, 'not_started', $%d, NOW(), NOW(), NOW())",
-			offset+1, offset+2, offset+3, offset+4, offset+5,
-		))
-
-		values = append(values,
-			p.UserID,
-			p.GoalID,
-			p.ChallengeID,
-			p.Namespace,
-			p.IsActive, // Use actual is_active value
-		)
-	}
+	return r.exec().batchUpsertGoalActive(ctx, progresses)
+}
 
-	insertQuery += strings.Join(valuePlaceholders, ", ")
-	insertQuery += " ON CONFLICT (user_id, goal_id) DO UPDATE SET is_active = EXCLUDED.is_active, assigned_at = CASE WHEN EXCLUDED.is_active THEN NOW() ELSE NULL END, updated_at = NOW()"
+// M3 Phase 9: Fast path optimization methods
 
-	_, err = r.tx.ExecContext(ctx, insertQuery, values...)
-	if err != nil {
-		return errors.ErrDatabaseError("batch insert goal active in transaction", err)
-	}
+// DeactivateChallengeGoals deactivates all of a user's active goals in a challenge within a transaction.
+func (r *PostgresTxRepository) DeactivateChallengeGoals(ctx context.Context, userID, challengeID string) (int64, error) {
+	return r.exec().deactivateChallengeGoals(ctx, userID, challengeID)
+}
 
-	return nil
+// ReactivateChallengeGoals reactivates all of a user's inactive goals in a challenge within a transaction.
+func (r *PostgresTxRepository) ReactivateChallengeGoals(ctx context.Context, userID, challengeID string) (int64, error) {
+	return r.exec().reactivateChallengeGoals(ctx, userID, challengeID)
 }
 
-// M3 Phase 9: Fast path optimization methods
+// DeleteChallengeProgress deletes all progress rows of a challenge in a namespace within a transaction.
+// Requires WithAllowBulkDelete.
+func (r *PostgresTxRepository) DeleteChallengeProgress(ctx context.Context, namespace, challengeID string) (int64, error) {
+	return r.exec().deleteChallengeProgress(ctx, namespace, challengeID)
+}
 
 // GetUserGoalCount returns the total number of goals for a user (active + inactive) within a transaction.
 func (r *PostgresTxRepository) GetUserGoalCount(ctx context.Context, userID string) (int, error) {
-	query := `SELECT COUNT(*) FROM user_goal_progress WHERE user_id = $1`
-
-	var count int
-	err := r.tx.QueryRowContext(ctx, query, userID).Scan(&count)
-	if err != nil {
-		return 0, errors.ErrDatabaseError("get user goal count in transaction", err)
-	}
-
-	return count, nil
+	return r.exec().getUserGoalCount(ctx, userID)
 }
 
 // GetActiveGoals retrieves only active goal progress records for a user within a transaction.
 func (r *PostgresTxRepository) GetActiveGoals(ctx context.Context, userID string) ([]*domain.UserGoalProgress, error) {
-	query := `
-		SELECT user_id, goal_id, challenge_id, namespace, progress, status,
-		       completed_at, claimed_at, created_at, updated_at,
-		       is_active, assigned_at, expires_at
-		FROM user_goal_progress
-		WHERE user_id = $1 AND is_active = true
-		ORDER BY challenge_id, goal_id
-	`
-
-	rows, err := r.tx.QueryContext(ctx, query, userID)
-	if err != nil {
-		return nil, errors.ErrDatabaseError("get active goals in transaction", err)
-	}
-	defer func() {
-		_ = rows.Close()
-	}()
-
-	return r.parent.scanProgressRows(rows)
+	return r.exec().getActiveGoals(ctx, userID)
 }
 
 // BeginTx is not supported within a transaction.
@@ -1948,6 +571,8 @@ func (r *PostgresTxRepository) BeginTx(ctx context.Context) (TxRepository, error
 
 // Commit commits the transaction.
 func (r *PostgresTxRepository) Commit() error {
+	defer r.release()
+
 	err := r.tx.Commit()
 	if err != nil {
 		return errors.ErrDatabaseError("commit transaction", err)
@@ -1957,6 +582,8 @@ func (r *PostgresTxRepository) Commit() error {
 
 // Rollback rolls back the transaction.
 func (r *PostgresTxRepository) Rollback() error {
+	defer r.release()
+
 	err := r.tx.Rollback()
 	if err != nil {
 		return errors.ErrDatabaseError("rollback transaction", err)
@@ -1964,8 +591,38 @@ func (r *PostgresTxRepository) Rollback() error {
 	return nil
 }
 
+// DefaultConnMaxLifetime is the connection lifetime set by ConfigureDB.
+const DefaultConnMaxLifetime = 30 * time.Minute
+
+// PoolOption adjusts the pool settings applied by ConfigureDB.
+type PoolOption func(*poolSettings)
+
+// poolSettings holds the adjustable ConfigureDB settings.
+type poolSettings struct {
+	connMaxLifetimeJitter time.Duration
+}
+
+// WithConnMaxLifetimeJitter makes ConfigureDB set the connection lifetime to a random value
+// within DefaultConnMaxLifetime ±jitter, chosen once per call (see db.JitterLifetime).
+// Pods deployed together then recycle their connections at different times instead of
+// reconnecting all at once every DefaultConnMaxLifetime.
+func WithConnMaxLifetimeJitter(jitter time.Duration) PoolOption {
+	return func(s *poolSettings) {
+		s.connMaxLifetimeJitter = jitter
+	}
+}
+
+// connMaxLifetime returns the lifetime ConfigureDB applies for the given options.
+func connMaxLifetime(opts ...PoolOption) time.Duration {
+	var settings poolSettings
+	for _, opt := range opts {
+		opt(&settings)
+	}
+	return db.JitterLifetime(DefaultConnMaxLifetime, settings.connMaxLifetimeJitter)
+}
+
 // ConfigureDB configures database connection pool settings.
-func ConfigureDB(db *sql.DB) {
+func ConfigureDB(db *sql.DB, opts ...PoolOption) {
 	// Maximum open connections (includes idle + in-use)
 	db.SetMaxOpenConns(50)
 
@@ -1973,7 +630,7 @@ func ConfigureDB(db *sql.DB) {
 	db.SetMaxIdleConns(10)
 
 	// Maximum lifetime of connection
-	db.SetConnMaxLifetime(30 * time.Minute)
+	db.SetConnMaxLifetime(connMaxLifetime(opts...))
 
 	// Maximum idle time for connection
 	db.SetConnMaxIdleTime(5 * time.Minute)
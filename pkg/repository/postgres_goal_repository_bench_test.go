@@ -169,7 +169,7 @@ func BenchmarkBatchIncrementProgress_AssignmentControl(b *testing.B) {
 		// Note: nestif disabled for benchmark verification code to keep readable
 		if i == 0 { //nolint:nestif
 			var activeIncremented, inactiveNotIncremented int
-			expectedActiveProgress := 5 + 3 // Initial 5 + first increment of 3
+			expectedActiveProgress := int64(5 + 3) // Initial 5 + first increment of 3
 			for j := 0; j < 1000; j++ {
 				result, err := repo.GetProgress(ctx, fmt.Sprintf("bench-user-%d", j), fmt.Sprintf("bench-goal-%d", j))
 				if err != nil {
@@ -268,7 +268,7 @@ func BenchmarkIncrementProgress_AssignmentControl(b *testing.B) {
 		if err != nil {
 			b.Fatalf("GetProgress failed: %v", err)
 		}
-		if result.Progress != b.N {
+		if result.Progress != int64(b.N) {
 			b.Errorf("Active goal progress = %d, want %d", result.Progress, b.N)
 		}
 
@@ -53,7 +53,7 @@ func setupTestDB(t *testing.T) *sql.DB {
 			assigned_at TIMESTAMP NULL,
 			expires_at TIMESTAMP NULL,
 			PRIMARY KEY (user_id, goal_id),
-			CONSTRAINT check_status CHECK (status IN ('not_started', 'in_progress', 'completed', 'claimed')),
+			CONSTRAINT check_status CHECK (status IN ('not_started', 'in_progress', 'completed', 'claiming', 'claimed')),
 			CONSTRAINT check_progress_non_negative CHECK (progress >= 0),
 			CONSTRAINT check_claimed_implies_completed CHECK (claimed_at IS NULL OR completed_at IS NOT NULL)
 		)
@@ -62,15 +62,170 @@ func setupTestDB(t *testing.T) *sql.DB {
 		t.Fatalf("Failed to create table: %v", err)
 	}
 
-	// Create index
+	// Add daily bucket column (migration 004)
+	_, err = db.Exec(`ALTER TABLE user_goal_progress ADD COLUMN IF NOT EXISTS last_daily_date DATE NULL`)
+	if err != nil {
+		t.Fatalf("Failed to add last_daily_date column: %v", err)
+	}
+
+	// Add config checksum column (migration 007)
+	_, err = db.Exec(`ALTER TABLE user_goal_progress ADD COLUMN IF NOT EXISTS config_checksum VARCHAR(64) NULL`)
+	if err != nil {
+		t.Fatalf("Failed to add config_checksum column: %v", err)
+	}
+
+	// Add forfeiture column (migration 008)
+	_, err = db.Exec(`ALTER TABLE user_goal_progress ADD COLUMN IF NOT EXISTS forfeited_at TIMESTAMP NULL`)
+	if err != nil {
+		t.Fatalf("Failed to add forfeited_at column: %v", err)
+	}
+
+	// Add claim reservations (migration 012). Tables created before it keep the old
+	// status constraint, so it is replaced here as well.
+	_, err = db.Exec(`
+		ALTER TABLE user_goal_progress ADD COLUMN IF NOT EXISTS reserved_until TIMESTAMP NULL;
+		ALTER TABLE user_goal_progress DROP CONSTRAINT IF EXISTS check_status;
+		ALTER TABLE user_goal_progress ADD CONSTRAINT check_status
+			CHECK (status IN ('not_started', 'in_progress', 'completed', 'claiming', 'claimed'))
+	`)
+	if err != nil {
+		t.Fatalf("Failed to add claim reservation columns: %v", err)
+	}
+
+	// Store progress as BIGINT (migration 013)
+	_, err = db.Exec(`ALTER TABLE user_goal_progress ALTER COLUMN progress TYPE BIGINT`)
+	if err != nil {
+		t.Fatalf("Failed to widen progress column: %v", err)
+	}
+
+	// Add attempt counter (migration 014)
+	_, err = db.Exec(`ALTER TABLE user_goal_progress ADD COLUMN IF NOT EXISTS attempts INT NOT NULL DEFAULT 0`)
+	if err != nil {
+		t.Fatalf("Failed to add attempts column: %v", err)
+	}
+
+	// Add display order (migration 017)
+	_, err = db.Exec(`ALTER TABLE user_goal_progress ADD COLUMN IF NOT EXISTS order_index INT NOT NULL DEFAULT 0`)
+	if err != nil {
+		t.Fatalf("Failed to add order_index column: %v", err)
+	}
+
+	// Add repeatable completion counter (migration 018)
+	_, err = db.Exec(`
+		ALTER TABLE user_goal_progress ADD COLUMN IF NOT EXISTS completions INT NOT NULL DEFAULT 0;
+		ALTER TABLE user_goal_progress DROP CONSTRAINT IF EXISTS check_completions_non_negative;
+		ALTER TABLE user_goal_progress ADD CONSTRAINT check_completions_non_negative CHECK (completions >= 0)
+	`)
+	if err != nil {
+		t.Fatalf("Failed to add completions column: %v", err)
+	}
+
+	// Add optional last increment delta (migration 019)
+	_, err = db.Exec(`ALTER TABLE user_goal_progress ADD COLUMN IF NOT EXISTS last_delta INT`)
+	if err != nil {
+		t.Fatalf("Failed to add last_delta column: %v", err)
+	}
+
+	// Create indexes (migration 001)
 	_, err = db.Exec(`
 		CREATE INDEX IF NOT EXISTS idx_user_goal_progress_user_challenge
-		ON user_goal_progress(user_id, challenge_id)
+		ON user_goal_progress(user_id, challenge_id);
+		CREATE INDEX IF NOT EXISTS idx_user_goal_progress_user_active
+		ON user_goal_progress(user_id, is_active) WHERE is_active = true;
+		CREATE INDEX IF NOT EXISTS idx_user_goal_count ON user_goal_progress(user_id);
+		CREATE INDEX IF NOT EXISTS idx_user_goal_lookup ON user_goal_progress(user_id, goal_id);
+		CREATE INDEX IF NOT EXISTS idx_user_goal_active_only
+		ON user_goal_progress(user_id) WHERE is_active = true
 	`)
 	if err != nil {
 		t.Fatalf("Failed to create index: %v", err)
 	}
 
+	// Create reward grants table (migration 003)
+	_, err = db.Exec(`
+		CREATE TABLE IF NOT EXISTS reward_grants (
+			grant_id BIGSERIAL PRIMARY KEY,
+			user_id VARCHAR(100) NOT NULL,
+			goal_id VARCHAR(100) NOT NULL,
+			namespace VARCHAR(100) NOT NULL,
+			reward_type VARCHAR(20) NOT NULL,
+			reward_id VARCHAR(100) NOT NULL,
+			quantity INT NOT NULL,
+			status VARCHAR(20) NOT NULL DEFAULT 'pending',
+			created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+			updated_at TIMESTAMP NOT NULL DEFAULT NOW(),
+			CONSTRAINT check_grant_status CHECK (status IN ('pending', 'granted', 'failed')),
+			CONSTRAINT check_grant_quantity_positive CHECK (quantity > 0)
+		)
+	`)
+	if err != nil {
+		t.Fatalf("Failed to create reward_grants table: %v", err)
+	}
+
+	_, err = db.Exec(`
+		CREATE INDEX IF NOT EXISTS idx_reward_grants_user_goal ON reward_grants(user_id, goal_id)
+	`)
+	if err != nil {
+		t.Fatalf("Failed to create reward_grants index: %v", err)
+	}
+
+	// Create challenge participants index (migration 005)
+	_, err = db.Exec(`
+		CREATE INDEX IF NOT EXISTS idx_user_goal_progress_challenge_participants
+		ON user_goal_progress(challenge_id, user_id, goal_id)
+	`)
+	if err != nil {
+		t.Fatalf("Failed to create challenge participants index: %v", err)
+	}
+
+	// Create claim deadline index (migration 008)
+	_, err = db.Exec(`
+		CREATE INDEX IF NOT EXISTS idx_user_goal_progress_claim_deadline
+		ON user_goal_progress(goal_id, completed_at)
+		WHERE status = 'completed' AND forfeited_at IS NULL
+	`)
+	if err != nil {
+		t.Fatalf("Failed to create claim deadline index: %v", err)
+	}
+
+	// Create claim reservation index (migration 012)
+	_, err = db.Exec(`
+		CREATE INDEX IF NOT EXISTS idx_user_goal_progress_claim_reservation
+		ON user_goal_progress(reserved_until)
+		WHERE status = 'claiming'
+	`)
+	if err != nil {
+		t.Fatalf("Failed to create claim reservation index: %v", err)
+	}
+
+	// Create progress feed index (migration 011)
+	_, err = db.Exec(`
+		CREATE INDEX IF NOT EXISTS idx_user_goal_progress_feed
+		ON user_goal_progress(namespace, updated_at, user_id, goal_id)
+	`)
+	if err != nil {
+		t.Fatalf("Failed to create progress feed index: %v", err)
+	}
+
+	// Create challenge status index (migration 015)
+	_, err = db.Exec(`
+		CREATE INDEX IF NOT EXISTS idx_user_goal_progress_challenge_status
+		ON user_goal_progress(namespace, challenge_id, status, user_id, goal_id)
+	`)
+	if err != nil {
+		t.Fatalf("Failed to create challenge status index: %v", err)
+	}
+
+	// Create assigned_at index (migration 016)
+	_, err = db.Exec(`
+		CREATE INDEX IF NOT EXISTS idx_user_goal_progress_assigned
+		ON user_goal_progress(namespace, assigned_at, user_id, goal_id)
+		WHERE assigned_at IS NOT NULL
+	`)
+	if err != nil {
+		t.Fatalf("Failed to create assigned_at index: %v", err)
+	}
+
 	return db
 }
 
@@ -83,7 +238,7 @@ func cleanupTestDB(t *testing.T, db *sql.DB) {
 	}
 
 	// Clean up data
-	_, err := db.Exec("TRUNCATE TABLE user_goal_progress")
+	_, err := db.Exec("TRUNCATE TABLE user_goal_progress, reward_grants")
 	if err != nil {
 		t.Logf("Warning: failed to truncate table: %v", err)
 	}
@@ -2094,6 +2249,8 @@ func TestPostgresTxRepository_IncrementProgress(t *testing.T) {
 	}
 	defer cleanupTestDB(t, db)
 
+	// Legacy mode (the default): increments in a transaction insert missing rows.
+	// See require_assignment_test.go for WithRequireAssignmentForIncrements.
 	repo := NewPostgresGoalRepository(db)
 	ctx := context.Background()
 
@@ -2174,6 +2331,7 @@ func TestPostgresTxRepository_BatchIncrementProgress(t *testing.T) {
 	}
 	defer cleanupTestDB(t, db)
 
+	// Legacy mode (the default): batch increments in a transaction insert missing rows
 	repo := NewPostgresGoalRepository(db)
 	ctx := context.Background()
 
@@ -2267,6 +2425,20 @@ func TestConfigureDB(t *testing.T) {
 	}
 }
 
+func TestConnMaxLifetime_Jitter(t *testing.T) {
+	if got := connMaxLifetime(); got != DefaultConnMaxLifetime {
+		t.Errorf("connMaxLifetime() = %v, want %v", got, DefaultConnMaxLifetime)
+	}
+
+	jitter := 3 * time.Minute
+	for i := 0; i < 1000; i++ {
+		got := connMaxLifetime(WithConnMaxLifetimeJitter(jitter))
+		if got < DefaultConnMaxLifetime-jitter || got > DefaultConnMaxLifetime+jitter {
+			t.Fatalf("connMaxLifetime() = %v, want within %v ± %v", got, DefaultConnMaxLifetime, jitter)
+		}
+	}
+}
+
 // M3 Phase 4: Test activeOnly filtering
 
 func TestPostgresGoalRepository_GetUserProgress_ActiveOnly(t *testing.T) {
@@ -2554,7 +2726,7 @@ func TestPostgresGoalRepository_GetGoalsByIDs(t *testing.T) {
 		}
 
 		// Verify progress values match
-		progressMap := make(map[string]int)
+		progressMap := make(map[string]int64)
 		for _, g := range result {
 			progressMap[g.GoalID] = g.Progress
 		}
@@ -2670,7 +2842,7 @@ func TestPostgresGoalRepository_BulkInsert(t *testing.T) {
 				GoalID:      fmt.Sprintf("bulk-goal-%d", i),
 				ChallengeID: "bulk-challenge-1",
 				Namespace:   "test",
-				Progress:    i * 10,
+				Progress:    int64(i * 10),
 				Status:      domain.GoalStatusInProgress,
 				IsActive:    true,
 				AssignedAt:  &now,
@@ -2772,7 +2944,7 @@ func TestPostgresGoalRepository_BulkInsert(t *testing.T) {
 				GoalID:      fmt.Sprintf("large-goal-%d", i),
 				ChallengeID: "challenge-1",
 				Namespace:   "test",
-				Progress:    i,
+				Progress:    int64(i),
 				Status:      domain.GoalStatusInProgress,
 				IsActive:    true,
 				AssignedAt:  &now,
@@ -3445,7 +3617,7 @@ func TestPostgresTxRepository_BulkInsert(t *testing.T) {
 				GoalID:      fmt.Sprintf("tx-bulk-goal-%d", i),
 				ChallengeID: "challenge-1",
 				Namespace:   "test",
-				Progress:    i,
+				Progress:    int64(i),
 				Status:      domain.GoalStatusInProgress,
 				IsActive:    true,
 				AssignedAt:  &now,
@@ -3500,7 +3672,7 @@ func TestPostgresTxRepository_BulkInsert(t *testing.T) {
 				GoalID:      fmt.Sprintf("tx-rollback-goal-%d", i),
 				ChallengeID: "challenge-1",
 				Namespace:   "test",
-				Progress:    i,
+				Progress:    int64(i),
 				Status:      domain.GoalStatusInProgress,
 				IsActive:    true,
 				AssignedAt:  &now,
@@ -3551,7 +3723,7 @@ func TestPostgresGoalRepository_BulkInsertWithCOPY(t *testing.T) {
 				GoalID:      fmt.Sprintf("copy-goal-%d", i),
 				ChallengeID: "copy-challenge-1",
 				Namespace:   "test",
-				Progress:    i * 10,
+				Progress:    int64(i * 10),
 				Status:      domain.GoalStatusInProgress,
 				IsActive:    true,
 				AssignedAt:  &now,
@@ -3577,7 +3749,7 @@ func TestPostgresGoalRepository_BulkInsertWithCOPY(t *testing.T) {
 				continue
 			}
 
-			if result.Progress != i*10 {
+			if result.Progress != int64(i*10) {
 				t.Errorf("Goal %d: expected progress %d, got %d", i, i*10, result.Progress)
 			}
 
@@ -3731,7 +3903,7 @@ func TestPostgresGoalRepository_BulkInsertWithCOPY(t *testing.T) {
 				GoalID:      "copy-large-goal",
 				ChallengeID: "copy-large-challenge",
 				Namespace:   "test",
-				Progress:    i,
+				Progress:    int64(i),
 				Status:      domain.GoalStatusInProgress,
 				IsActive:    true,
 				AssignedAt:  &now,
@@ -3768,7 +3940,7 @@ func TestPostgresGoalRepository_BulkInsertWithCOPY(t *testing.T) {
 				continue
 			}
 
-			if result.Progress != idx {
+			if result.Progress != int64(idx) {
 				t.Errorf("User %d: expected progress %d, got %d", idx, idx, result.Progress)
 			}
 		}
@@ -3802,7 +3974,7 @@ func TestPostgresTxRepository_BulkInsertWithCOPY(t *testing.T) {
 				GoalID:      fmt.Sprintf("tx-copy-goal-%d", i),
 				ChallengeID: "tx-copy-challenge-1",
 				Namespace:   "test",
-				Progress:    i,
+				Progress:    int64(i),
 				Status:      domain.GoalStatusInProgress,
 				IsActive:    true,
 				AssignedAt:  &now,
@@ -3834,7 +4006,7 @@ func TestPostgresTxRepository_BulkInsertWithCOPY(t *testing.T) {
 				continue
 			}
 
-			if result.Progress != i {
+			if result.Progress != int64(i) {
 				t.Errorf("Goal %d: expected progress %d, got %d", i, i, result.Progress)
 			}
 		}
@@ -3856,7 +4028,7 @@ func TestPostgresTxRepository_BulkInsertWithCOPY(t *testing.T) {
 				GoalID:      fmt.Sprintf("tx-copy-rollback-goal-%d", i),
 				ChallengeID: "tx-copy-challenge-1",
 				Namespace:   "test",
-				Progress:    i,
+				Progress:    int64(i),
 				Status:      domain.GoalStatusInProgress,
 				IsActive:    true,
 				AssignedAt:  &now,
@@ -4356,7 +4528,7 @@ func TestPostgresTxRepository_BatchUpsertProgressWithCOPY(t *testing.T) {
 				GoalID:      "copy-goal-1",
 				ChallengeID: "copy-challenge-1",
 				Namespace:   "test",
-				Progress:    10 + i,
+				Progress:    int64(10 + i),
 				Status:      domain.GoalStatusInProgress,
 				IsActive:    true,
 			}
@@ -4388,7 +4560,7 @@ func TestPostgresTxRepository_BatchUpsertProgressWithCOPY(t *testing.T) {
 				continue
 			}
 
-			if result.Progress != 10+i {
+			if result.Progress != int64(10+i) {
 				t.Errorf("User %d: expected progress %d, got %d", i, 10+i, result.Progress)
 			}
 		}
@@ -4403,7 +4575,7 @@ func TestPostgresTxRepository_BatchUpsertProgressWithCOPY(t *testing.T) {
 				GoalID:      "copy-rollback-goal-1",
 				ChallengeID: "copy-rollback-challenge-1",
 				Namespace:   "test",
-				Progress:    20 + i,
+				Progress:    int64(20 + i),
 				Status:      domain.GoalStatusInProgress,
 				IsActive:    true,
 			}
@@ -4449,7 +4621,7 @@ func TestPostgresTxRepository_BatchUpsertProgressWithCOPY(t *testing.T) {
 				GoalID:      "copy-large-goal-1",
 				ChallengeID: "copy-large-challenge-1",
 				Namespace:   "test",
-				Progress:    i,
+				Progress:    int64(i),
 				Status:      domain.GoalStatusInProgress,
 				IsActive:    true,
 			}
@@ -4489,7 +4661,7 @@ func TestPostgresTxRepository_BatchUpsertProgressWithCOPY(t *testing.T) {
 				continue
 			}
 
-			if result.Progress != idx {
+			if result.Progress != int64(idx) {
 				t.Errorf("User %d: expected progress %d, got %d", idx, idx, result.Progress)
 			}
 		}
@@ -4720,7 +4892,7 @@ func TestPostgresTxRepository_BeginTx_CommitRollback(t *testing.T) {
 		results := []struct {
 			userID   string
 			goalID   string
-			expected int
+			expected int64
 		}{
 			{"multi-user-1", "multi-goal-1", 10},
 			{"multi-user-1", "multi-goal-2", 20},
@@ -5900,3 +6072,524 @@ func TestPostgresTxRepository_BatchUpsertGoalActive(t *testing.T) {
 		}
 	})
 }
+
+func TestPostgresGoalRepository_BatchIncrementProgressReturning(t *testing.T) {
+	db := setupTestDB(t)
+	if db == nil {
+		return
+	}
+	defer cleanupTestDB(t, db)
+
+	repo := NewPostgresGoalRepository(db)
+	ctx := context.Background()
+
+	t.Run("empty slice returns no results", func(t *testing.T) {
+		results, err := repo.BatchIncrementProgressReturning(ctx, []ProgressIncrement{})
+		if err != nil {
+			t.Fatalf("Empty BatchIncrementProgressReturning should not error: %v", err)
+		}
+		if len(results) != 0 {
+			t.Errorf("Expected 0 results, got %d", len(results))
+		}
+	})
+
+	t.Run("returns only completed rows and flags new completions", func(t *testing.T) {
+		completedAt := time.Now().UTC().Add(-time.Hour)
+		initial := []*domain.UserGoalProgress{
+			{UserID: "ret-user", GoalID: "goal-complete", ChallengeID: "challenge1", Namespace: "test", Status: domain.GoalStatusNotStarted, IsActive: true},
+			{UserID: "ret-user", GoalID: "goal-partial", ChallengeID: "challenge1", Namespace: "test", Status: domain.GoalStatusNotStarted, IsActive: true},
+			{UserID: "ret-user", GoalID: "goal-already", ChallengeID: "challenge1", Namespace: "test", Progress: 10, Status: domain.GoalStatusCompleted, CompletedAt: &completedAt, IsActive: true},
+			{UserID: "ret-user", GoalID: "goal-claimed", ChallengeID: "challenge1", Namespace: "test", Progress: 10, Status: domain.GoalStatusClaimed, CompletedAt: &completedAt, ClaimedAt: &completedAt, IsActive: true},
+		}
+		if err := repo.BulkInsert(ctx, initial); err != nil {
+			t.Fatalf("BulkInsert failed: %v", err)
+		}
+
+		increments := []ProgressIncrement{
+			{UserID: "ret-user", GoalID: "goal-complete", ChallengeID: "challenge1", Namespace: "test", Delta: 5, TargetValue: 5},
+			{UserID: "ret-user", GoalID: "goal-partial", ChallengeID: "challenge1", Namespace: "test", Delta: 1, TargetValue: 5},
+			{UserID: "ret-user", GoalID: "goal-already", ChallengeID: "challenge1", Namespace: "test", Delta: 1, TargetValue: 10},
+			{UserID: "ret-user", GoalID: "goal-claimed", ChallengeID: "challenge1", Namespace: "test", Delta: 1, TargetValue: 10},
+		}
+
+		results, err := repo.BatchIncrementProgressReturning(ctx, increments)
+		if err != nil {
+			t.Fatalf("BatchIncrementProgressReturning failed: %v", err)
+		}
+
+		byGoal := make(map[string]CompletionResult)
+		for _, r := range results {
+			byGoal[r.GoalID] = r
+		}
+
+		if len(byGoal) != 2 {
+			t.Fatalf("Expected 2 completed results, got %d: %+v", len(byGoal), results)
+		}
+		if r, ok := byGoal["goal-complete"]; !ok || !r.NewlyCompleted || r.Status != domain.GoalStatusCompleted {
+			t.Errorf("goal-complete should be newly completed, got %+v", r)
+		}
+		if r, ok := byGoal["goal-already"]; !ok || r.NewlyCompleted {
+			t.Errorf("goal-already should be completed but not newly completed, got %+v", r)
+		}
+		if _, ok := byGoal["goal-partial"]; ok {
+			t.Error("goal-partial should not be returned")
+		}
+		if _, ok := byGoal["goal-claimed"]; ok {
+			t.Error("goal-claimed should not be returned")
+		}
+
+		// The increments must still have been applied
+		p, _ := repo.GetProgress(ctx, "ret-user", "goal-partial")
+		if p == nil || p.Progress != 1 {
+			t.Errorf("goal-partial should have progress 1, got %+v", p)
+		}
+	})
+}
+
+func TestPostgresTxRepository_BatchIncrementProgressReturning(t *testing.T) {
+	db := setupTestDB(t)
+	if db == nil {
+		return
+	}
+	defer cleanupTestDB(t, db)
+
+	repo := NewPostgresGoalRepository(db)
+	ctx := context.Background()
+
+	tx, err := repo.BeginTx(ctx)
+	if err != nil {
+		t.Fatalf("BeginTx failed: %v", err)
+	}
+
+	increments := []ProgressIncrement{
+		{UserID: "txret-user", GoalID: "goal1", ChallengeID: "challenge1", Namespace: "test", Delta: 3, TargetValue: 3},
+		{UserID: "txret-user", GoalID: "goal2", ChallengeID: "challenge1", Namespace: "test", Delta: 1, TargetValue: 3},
+	}
+
+	results, err := tx.BatchIncrementProgressReturning(ctx, increments)
+	if err != nil {
+		_ = tx.Rollback()
+		t.Fatalf("BatchIncrementProgressReturning in tx failed: %v", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		t.Fatalf("Commit failed: %v", err)
+	}
+
+	if len(results) != 1 {
+		t.Fatalf("Expected 1 completed result, got %d", len(results))
+	}
+	if results[0].GoalID != "goal1" || !results[0].NewlyCompleted {
+		t.Errorf("Expected goal1 newly completed, got %+v", results[0])
+	}
+}
+
+func TestPostgresTxRepository_BatchIncrementProgressReturning_RepeatedInTransaction(t *testing.T) {
+	db := setupTestDB(t)
+	if db == nil {
+		return
+	}
+	defer cleanupTestDB(t, db)
+
+	repo := NewPostgresGoalRepository(db)
+	ctx := context.Background()
+
+	tx, err := repo.BeginTx(ctx)
+	if err != nil {
+		t.Fatalf("BeginTx failed: %v", err)
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	increment := []ProgressIncrement{
+		{UserID: "txrepeat-user", GoalID: "goal1", ChallengeID: "challenge1", Namespace: "test", Delta: 3, TargetValue: 3},
+	}
+
+	// NOW() is the same for both statements, so completed_at alone cannot tell them apart
+	for i, wantNew := range []bool{true, false} {
+		results, err := tx.BatchIncrementProgressReturning(ctx, increment)
+		if err != nil {
+			t.Fatalf("increment %d: BatchIncrementProgressReturning failed: %v", i+1, err)
+		}
+		if len(results) != 1 {
+			t.Fatalf("increment %d: expected 1 completed result, got %d", i+1, len(results))
+		}
+		if results[0].NewlyCompleted != wantNew {
+			t.Errorf("increment %d: NewlyCompleted = %v, want %v", i+1, results[0].NewlyCompleted, wantNew)
+		}
+	}
+}
+
+func TestPostgresGoalRepository_IncrementProgressWithCooldown(t *testing.T) {
+	db := setupTestDB(t)
+	if db == nil {
+		return
+	}
+	defer cleanupTestDB(t, db)
+
+	repo := NewPostgresGoalRepository(db)
+	ctx := context.Background()
+
+	// seedCooldownRow inserts an in-progress row last updated at the given time.
+	seedCooldownRow := func(t *testing.T, goalID string, updatedAt time.Time) {
+		t.Helper()
+		_, err := db.ExecContext(ctx, `
+			INSERT INTO user_goal_progress (
+				user_id, goal_id, challenge_id, namespace,
+				progress, status, created_at, updated_at, is_active
+			) VALUES ('cooldown-user', $1, 'challenge1', 'test', 1, 'in_progress', $2, $2, true)
+		`, goalID, updatedAt)
+		if err != nil {
+			t.Fatalf("Direct insert failed: %v", err)
+		}
+	}
+
+	t.Run("cooldown elapsed - increment applied", func(t *testing.T) {
+		seedCooldownRow(t, "goal-elapsed", time.Now().Add(-2*time.Hour))
+
+		err := repo.IncrementProgressWithCooldown(ctx, "cooldown-user", "goal-elapsed", "challenge1", "test", 1, 3, time.Hour)
+		if err != nil {
+			t.Fatalf("IncrementProgressWithCooldown failed: %v", err)
+		}
+
+		p, _ := repo.GetProgress(ctx, "cooldown-user", "goal-elapsed")
+		if p == nil || p.Progress != 2 {
+			t.Errorf("Expected progress 2 after cooldown elapsed, got %+v", p)
+		}
+	})
+
+	t.Run("inside cooldown - no-op without extending window", func(t *testing.T) {
+		lastUpdate := time.Now().Add(-10 * time.Minute)
+		seedCooldownRow(t, "goal-cooling", lastUpdate)
+
+		err := repo.IncrementProgressWithCooldown(ctx, "cooldown-user", "goal-cooling", "challenge1", "test", 1, 3, time.Hour)
+		if err != nil {
+			t.Fatalf("IncrementProgressWithCooldown failed: %v", err)
+		}
+
+		p, _ := repo.GetProgress(ctx, "cooldown-user", "goal-cooling")
+		if p == nil || p.Progress != 1 {
+			t.Fatalf("Expected progress unchanged at 1 inside cooldown, got %+v", p)
+		}
+		if p.UpdatedAt.After(lastUpdate.Add(time.Second)) {
+			t.Errorf("updated_at should not move inside cooldown, was %v now %v", lastUpdate, p.UpdatedAt)
+		}
+	})
+
+	t.Run("not started row is not subject to cooldown", func(t *testing.T) {
+		err := repo.BulkInsert(ctx, []*domain.UserGoalProgress{
+			{UserID: "cooldown-user", GoalID: "goal-fresh", ChallengeID: "challenge1", Namespace: "test", Status: domain.GoalStatusNotStarted, IsActive: true},
+		})
+		if err != nil {
+			t.Fatalf("BulkInsert failed: %v", err)
+		}
+
+		err = repo.BatchIncrementProgress(ctx, []ProgressIncrement{
+			{UserID: "cooldown-user", GoalID: "goal-fresh", ChallengeID: "challenge1", Namespace: "test", Delta: 1, TargetValue: 3, Cooldown: time.Hour},
+		})
+		if err != nil {
+			t.Fatalf("BatchIncrementProgress failed: %v", err)
+		}
+
+		p, _ := repo.GetProgress(ctx, "cooldown-user", "goal-fresh")
+		if p == nil || p.Progress != 1 {
+			t.Errorf("Expected first increment to apply, got %+v", p)
+		}
+	})
+
+	t.Run("transaction batch applies each user's own cooldown", func(t *testing.T) {
+		lastUpdate := time.Now().Add(-10 * time.Minute)
+		for _, userID := range []string{"cooldown-long", "cooldown-short"} {
+			_, err := db.ExecContext(ctx, `
+				INSERT INTO user_goal_progress (
+					user_id, goal_id, challenge_id, namespace,
+					progress, status, created_at, updated_at, is_active
+				) VALUES ($1, 'goal-shared', 'challenge1', 'test', 1, 'in_progress', $2, $2, true)
+			`, userID, lastUpdate)
+			if err != nil {
+				t.Fatalf("Direct insert failed: %v", err)
+			}
+		}
+
+		tx, err := repo.BeginTx(ctx)
+		if err != nil {
+			t.Fatalf("BeginTx failed: %v", err)
+		}
+		defer func() { _ = tx.Rollback() }()
+
+		// The long cooldown comes first so a goal-only lookup would apply it to both users
+		err = tx.BatchIncrementProgress(ctx, []ProgressIncrement{
+			{UserID: "cooldown-long", GoalID: "goal-shared", ChallengeID: "challenge1", Namespace: "test", Delta: 1, TargetValue: 3, Cooldown: time.Hour},
+			{UserID: "cooldown-short", GoalID: "goal-shared", ChallengeID: "challenge1", Namespace: "test", Delta: 1, TargetValue: 3, Cooldown: 5 * time.Minute},
+		})
+		if err != nil {
+			t.Fatalf("BatchIncrementProgress in transaction failed: %v", err)
+		}
+
+		long, _ := tx.GetProgress(ctx, "cooldown-long", "goal-shared")
+		if long == nil || long.Progress != 1 {
+			t.Errorf("Expected progress unchanged at 1 inside the 1h cooldown, got %+v", long)
+		}
+		short, _ := tx.GetProgress(ctx, "cooldown-short", "goal-shared")
+		if short == nil || short.Progress != 2 {
+			t.Errorf("Expected progress 2 after the 5m cooldown elapsed, got %+v", short)
+		}
+	})
+
+	t.Run("transaction batch applies each user's own delta, target and cap", func(t *testing.T) {
+		for _, userID := range []string{"entry-capped", "entry-open"} {
+			_, err := db.ExecContext(ctx, `
+				INSERT INTO user_goal_progress (
+					user_id, goal_id, challenge_id, namespace,
+					progress, status, created_at, updated_at, is_active
+				) VALUES ($1, 'goal-entries', 'challenge1', 'test', 4, 'in_progress', NOW(), NOW(), true)
+			`, userID)
+			if err != nil {
+				t.Fatalf("Direct insert failed: %v", err)
+			}
+		}
+
+		tx, err := repo.BeginTx(ctx)
+		if err != nil {
+			t.Fatalf("BeginTx failed: %v", err)
+		}
+		defer func() { _ = tx.Rollback() }()
+
+		// The capped entry comes first so a goal-only lookup would apply its delta, target
+		// and cap to both users
+		err = tx.BatchIncrementProgress(ctx, []ProgressIncrement{
+			{UserID: "entry-capped", GoalID: "goal-entries", ChallengeID: "challenge1", Namespace: "test", Delta: 3, TargetValue: 5, OverflowPolicy: CapAtTarget},
+			{UserID: "entry-open", GoalID: "goal-entries", ChallengeID: "challenge1", Namespace: "test", Delta: 2, TargetValue: 10, OverflowPolicy: AllowUnbounded},
+		})
+		if err != nil {
+			t.Fatalf("BatchIncrementProgress in transaction failed: %v", err)
+		}
+
+		capped, _ := tx.GetProgress(ctx, "entry-capped", "goal-entries")
+		if capped == nil || capped.Progress != 5 || capped.Status != domain.GoalStatusCompleted {
+			t.Errorf("Expected progress capped at 5 and completed, got %+v", capped)
+		}
+		open, _ := tx.GetProgress(ctx, "entry-open", "goal-entries")
+		if open == nil || open.Progress != 6 || open.Status != domain.GoalStatusInProgress {
+			t.Errorf("Expected progress 6 and in progress, got %+v", open)
+		}
+	})
+}
+
+func TestPostgresGoalRepository_DeactivateReactivateChallengeGoals(t *testing.T) {
+	db := setupTestDB(t)
+	if db == nil {
+		return
+	}
+	defer cleanupTestDB(t, db)
+
+	repo := NewPostgresGoalRepository(db)
+	ctx := context.Background()
+
+	assignedAt := time.Now().Add(-24 * time.Hour).UTC().Truncate(time.Microsecond)
+	goals := []*domain.UserGoalProgress{
+		{UserID: "opt-out-user", GoalID: "goal-1", ChallengeID: "c1", Namespace: "test", IsActive: true, AssignedAt: &assignedAt, Status: domain.GoalStatusNotStarted},
+		{UserID: "opt-out-user", GoalID: "goal-2", ChallengeID: "c1", Namespace: "test", IsActive: true, AssignedAt: &assignedAt, Status: domain.GoalStatusNotStarted},
+		{UserID: "opt-out-user", GoalID: "goal-3", ChallengeID: "c1", Namespace: "test", IsActive: false, Status: domain.GoalStatusNotStarted},
+		{UserID: "opt-out-user", GoalID: "goal-4", ChallengeID: "c2", Namespace: "test", IsActive: true, AssignedAt: &assignedAt, Status: domain.GoalStatusNotStarted},
+		{UserID: "other-user", GoalID: "goal-1", ChallengeID: "c1", Namespace: "test", IsActive: true, AssignedAt: &assignedAt, Status: domain.GoalStatusNotStarted},
+	}
+	if err := repo.BulkInsert(ctx, goals); err != nil {
+		t.Fatalf("BulkInsert failed: %v", err)
+	}
+
+	t.Run("deactivates only active goals in the challenge", func(t *testing.T) {
+		count, err := repo.DeactivateChallengeGoals(ctx, "opt-out-user", "c1")
+		if err != nil {
+			t.Fatalf("DeactivateChallengeGoals failed: %v", err)
+		}
+		if count != 2 {
+			t.Errorf("Expected 2 rows deactivated, got %d", count)
+		}
+
+		p, _ := repo.GetProgress(ctx, "opt-out-user", "goal-1")
+		if p.IsActive {
+			t.Error("Expected goal-1 to be inactive")
+		}
+		if p.AssignedAt == nil || !p.AssignedAt.Equal(assignedAt) {
+			t.Errorf("Expected assigned_at preserved as %v, got %v", assignedAt, p.AssignedAt)
+		}
+
+		other, _ := repo.GetProgress(ctx, "opt-out-user", "goal-4")
+		if !other.IsActive {
+			t.Error("Goal in another challenge should remain active")
+		}
+		otherUser, _ := repo.GetProgress(ctx, "other-user", "goal-1")
+		if !otherUser.IsActive {
+			t.Error("Another user's goal should remain active")
+		}
+	})
+
+	t.Run("deactivating again is a no-op", func(t *testing.T) {
+		count, err := repo.DeactivateChallengeGoals(ctx, "opt-out-user", "c1")
+		if err != nil {
+			t.Fatalf("DeactivateChallengeGoals failed: %v", err)
+		}
+		if count != 0 {
+			t.Errorf("Expected 0 rows deactivated, got %d", count)
+		}
+	})
+
+	t.Run("reactivates inactive goals and stamps assigned_at", func(t *testing.T) {
+		count, err := repo.ReactivateChallengeGoals(ctx, "opt-out-user", "c1")
+		if err != nil {
+			t.Fatalf("ReactivateChallengeGoals failed: %v", err)
+		}
+		if count != 3 {
+			t.Errorf("Expected 3 rows reactivated, got %d", count)
+		}
+
+		p, _ := repo.GetProgress(ctx, "opt-out-user", "goal-3")
+		if !p.IsActive {
+			t.Error("Expected goal-3 to be active")
+		}
+		if p.AssignedAt == nil || time.Since(*p.AssignedAt) > time.Minute {
+			t.Errorf("Expected assigned_at to be refreshed, got %v", p.AssignedAt)
+		}
+	})
+
+	t.Run("within transaction", func(t *testing.T) {
+		tx, err := repo.BeginTx(ctx)
+		if err != nil {
+			t.Fatalf("BeginTx failed: %v", err)
+		}
+		defer func() { _ = tx.Rollback() }()
+
+		count, err := tx.DeactivateChallengeGoals(ctx, "opt-out-user", "c2")
+		if err != nil {
+			t.Fatalf("DeactivateChallengeGoals in transaction failed: %v", err)
+		}
+		if count != 1 {
+			t.Errorf("Expected 1 row deactivated, got %d", count)
+		}
+	})
+}
+
+// TestGoalRepositoryInterface_BulkMethods exercises the M4 bulk methods through the
+// GoalRepository and TxRepository interface types only (no concrete type assertions).
+func TestGoalRepositoryInterface_BulkMethods(t *testing.T) {
+	db := setupTestDB(t)
+	if db == nil {
+		return
+	}
+	defer cleanupTestDB(t, db)
+
+	var repo GoalRepository = NewPostgresGoalRepository(db)
+	ctx := context.Background()
+
+	newGoals := func(userID string, n int, active bool) []*domain.UserGoalProgress {
+		goals := make([]*domain.UserGoalProgress, n)
+		for i := range goals {
+			goals[i] = &domain.UserGoalProgress{
+				UserID:      userID,
+				GoalID:      fmt.Sprintf("goal-%d", i),
+				ChallengeID: "challenge1",
+				Namespace:   "test",
+				Status:      domain.GoalStatusNotStarted,
+				IsActive:    active,
+			}
+		}
+		return goals
+	}
+
+	t.Run("BulkInsertWithCOPY via GoalRepository", func(t *testing.T) {
+		if err := repo.BulkInsertWithCOPY(ctx, newGoals("iface-copy-user", 3, true)); err != nil {
+			t.Fatalf("BulkInsertWithCOPY failed: %v", err)
+		}
+
+		count, err := repo.GetUserGoalCount(ctx, "iface-copy-user")
+		if err != nil || count != 3 {
+			t.Errorf("Expected 3 goals, got %d (err=%v)", count, err)
+		}
+	})
+
+	t.Run("BatchUpsertGoalActive via GoalRepository", func(t *testing.T) {
+		if err := repo.BatchUpsertGoalActive(ctx, newGoals("iface-active-user", 2, true)); err != nil {
+			t.Fatalf("BatchUpsertGoalActive failed: %v", err)
+		}
+
+		active, err := repo.GetActiveGoals(ctx, "iface-active-user")
+		if err != nil || len(active) != 2 {
+			t.Errorf("Expected 2 active goals, got %d (err=%v)", len(active), err)
+		}
+	})
+
+	t.Run("BulkInsertWithCOPY and BatchUpsertGoalActive via TxRepository", func(t *testing.T) {
+		tx, err := repo.BeginTx(ctx)
+		if err != nil {
+			t.Fatalf("BeginTx failed: %v", err)
+		}
+		defer func() { _ = tx.Rollback() }()
+
+		if err := tx.BulkInsertWithCOPY(ctx, newGoals("iface-tx-user", 2, false)); err != nil {
+			t.Fatalf("tx BulkInsertWithCOPY failed: %v", err)
+		}
+		if err := tx.BatchUpsertGoalActive(ctx, newGoals("iface-tx-user", 2, true)); err != nil {
+			t.Fatalf("tx BatchUpsertGoalActive failed: %v", err)
+		}
+
+		active, err := tx.GetActiveGoals(ctx, "iface-tx-user")
+		if err != nil || len(active) != 2 {
+			t.Errorf("Expected 2 active goals in transaction, got %d (err=%v)", len(active), err)
+		}
+	})
+}
+
+func TestPostgresGoalRepository_DeleteChallengeProgress_Disabled(t *testing.T) {
+	// No database needed: the gate is checked before any SQL runs
+	repo := NewPostgresGoalRepository(nil)
+
+	count, err := repo.DeleteChallengeProgress(context.Background(), "test", "retired")
+
+	var ce *customerrors.ChallengeError
+	if !errors.As(err, &ce) || ce.Code != customerrors.ErrCodeOperationNotAllowed {
+		t.Errorf("Expected OPERATION_NOT_ALLOWED error, got %v", err)
+	}
+	if count != 0 {
+		t.Errorf("Expected 0 rows, got %d", count)
+	}
+}
+
+func TestPostgresGoalRepository_DeleteChallengeProgress(t *testing.T) {
+	db := setupTestDB(t)
+	if db == nil {
+		return
+	}
+	defer cleanupTestDB(t, db)
+
+	repo := NewPostgresGoalRepository(db, WithAllowBulkDelete(true))
+	ctx := context.Background()
+
+	err := repo.BulkInsert(ctx, []*domain.UserGoalProgress{
+		{UserID: "user1", GoalID: "retired-goal-1", ChallengeID: "retired", Namespace: "test", Status: domain.GoalStatusNotStarted, IsActive: true},
+		{UserID: "user2", GoalID: "retired-goal-1", ChallengeID: "retired", Namespace: "test", Status: domain.GoalStatusNotStarted, IsActive: true},
+		{UserID: "user1", GoalID: "retired-goal-2", ChallengeID: "retired", Namespace: "test", Status: domain.GoalStatusNotStarted, IsActive: false},
+		{UserID: "user1", GoalID: "live-goal", ChallengeID: "live", Namespace: "test", Status: domain.GoalStatusNotStarted, IsActive: true},
+		{UserID: "user3", GoalID: "retired-goal-1", ChallengeID: "retired", Namespace: "other-ns", Status: domain.GoalStatusNotStarted, IsActive: true},
+	})
+	if err != nil {
+		t.Fatalf("BulkInsert failed: %v", err)
+	}
+
+	count, err := repo.DeleteChallengeProgress(ctx, "test", "retired")
+	if err != nil {
+		t.Fatalf("DeleteChallengeProgress failed: %v", err)
+	}
+	if count != 3 {
+		t.Errorf("Expected 3 rows deleted, got %d", count)
+	}
+
+	if p, _ := repo.GetProgress(ctx, "user1", "retired-goal-1"); p != nil {
+		t.Error("Expected retired challenge row to be deleted")
+	}
+	if p, _ := repo.GetProgress(ctx, "user1", "live-goal"); p == nil {
+		t.Error("Other challenge's row should be untouched")
+	}
+	if p, _ := repo.GetProgress(ctx, "user3", "retired-goal-1"); p == nil {
+		t.Error("Same challenge in another namespace should be untouched")
+	}
+}
@@ -0,0 +1,114 @@
+package repository
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"slices"
+
+	"github.com/AccelByte/extend-challenge-common/pkg/domain"
+	"github.com/AccelByte/extend-challenge-common/pkg/errors"
+
+	"github.com/lib/pq"
+)
+
+// userHeadroomQuery counts a user's rows and returns which of the candidate goal IDs
+// ($2) the user already has.
+const userHeadroomQuery = `
+	SELECT COUNT(*), COALESCE(ARRAY_AGG(goal_id) FILTER (WHERE goal_id = ANY($2::TEXT[])), '{}')
+	FROM user_goal_progress
+	WHERE user_id = $1
+`
+
+func (e executor) bulkInsertWithPriority(ctx context.Context, progresses []*domain.UserGoalProgress, priorities []int, maxRowsForUser int) (inserted, skipped []string, err error) {
+	if len(priorities) != len(progresses) {
+		return nil, nil, errors.ErrValidationFailed("priorities", fmt.Sprintf("got %d priorities for %d rows", len(priorities), len(progresses)))
+	}
+	if maxRowsForUser < 0 {
+		return nil, nil, errors.ErrValidationFailed("maxRowsForUser", "cannot be negative")
+	}
+	if len(progresses) == 0 {
+		return nil, nil, nil
+	}
+	userID := progresses[0].UserID
+	for _, p := range progresses[1:] {
+		if p.UserID != userID {
+			return nil, nil, errors.ErrValidationFailed("progresses", fmt.Sprintf("rows belong to users '%s' and '%s', want a single user", userID, p.UserID))
+		}
+	}
+
+	// Highest priority (lowest value) first; the stable sort keeps input order on ties
+	order := make([]int, len(progresses))
+	for i := range order {
+		order[i] = i
+	}
+	slices.SortStableFunc(order, func(a, b int) int { return cmp.Compare(priorities[a], priorities[b]) })
+
+	release, err := e.admitBatch(ctx, "bulk insert with priority", len(progresses), func(i int) string { return progresses[i].Namespace })
+	if err != nil {
+		return nil, nil, err
+	}
+	defer release()
+
+	goalIDs := make([]string, len(progresses))
+	for i, p := range progresses {
+		goalIDs[i] = p.GoalID
+	}
+
+	// The count and the insert run under the user's lock (WithPerUserLocking), so
+	// concurrent assignments for the same user cannot both take the last slots
+	insertedSet := make(map[string]bool)
+	err = e.withUserLocks(ctx, []string{userID}, func(q queryer) error {
+		var count int
+		var existing pq.StringArray
+		if err := q.QueryRowContext(ctx, userHeadroomQuery, userID, pq.Array(goalIDs)).Scan(&count, &existing); err != nil {
+			return errors.ErrDatabaseError(e.op("count user rows for bulk insert"), err)
+		}
+
+		// Goals the user already has take no headroom and are reported as skipped
+		headroom := max(maxRowsForUser-count, 0)
+		var candidates []*domain.UserGoalProgress
+		for _, i := range order {
+			if len(candidates) < headroom && !slices.Contains(existing, progresses[i].GoalID) {
+				candidates = append(candidates, progresses[i])
+			}
+		}
+		if len(candidates) == 0 {
+			return nil
+		}
+
+		query, args := bulkInsertQuery(candidates)
+		rows, err := q.QueryContext(ctx, query+" RETURNING goal_id", args...)
+		if err != nil {
+			return errors.ErrDatabaseError(e.op("bulk insert goals with priority"), err)
+		}
+		defer func() { _ = rows.Close() }()
+
+		for rows.Next() {
+			var goalID string
+			if err := rows.Scan(&goalID); err != nil {
+				return errors.ErrDatabaseError(e.op("scan inserted goal"), err)
+			}
+			insertedSet[goalID] = true
+		}
+		if err := rows.Err(); err != nil {
+			return errors.ErrDatabaseError(e.op("bulk insert goals with priority"), err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// Both lists follow priority order; a goal ID repeated in the input is inserted once
+	for _, i := range order {
+		goalID := progresses[i].GoalID
+		if insertedSet[goalID] {
+			inserted = append(inserted, goalID)
+			delete(insertedSet, goalID)
+		} else {
+			skipped = append(skipped, goalID)
+		}
+	}
+	return inserted, skipped, nil
+}
@@ -0,0 +1,151 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+
+	"github.com/AccelByte/extend-challenge-common/pkg/domain"
+	customerrors "github.com/AccelByte/extend-challenge-common/pkg/errors"
+)
+
+func TestBulkInsertWithPriority_Validation(t *testing.T) {
+	repo := NewPostgresGoalRepository(nil)
+	ctx := context.Background()
+
+	row := func(userID, goalID string) *domain.UserGoalProgress {
+		return &domain.UserGoalProgress{UserID: userID, GoalID: goalID, ChallengeID: "c1", Namespace: "test", Status: domain.GoalStatusNotStarted}
+	}
+
+	tests := []struct {
+		name       string
+		progresses []*domain.UserGoalProgress
+		priorities []int
+		maxRows    int
+	}{
+		{"rows of two users", []*domain.UserGoalProgress{row("user-1", "g1"), row("user-2", "g2")}, []int{0, 1}, 10},
+		{"priorities length mismatch", []*domain.UserGoalProgress{row("user-1", "g1")}, []int{0, 1}, 10},
+		{"negative cap", []*domain.UserGoalProgress{row("user-1", "g1")}, []int{0}, -1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			inserted, skipped, err := repo.BulkInsertWithPriority(ctx, tt.progresses, tt.priorities, tt.maxRows)
+			var ce *customerrors.ChallengeError
+			if !errors.As(err, &ce) || ce.Code != customerrors.ErrCodeValidationFailed {
+				t.Fatalf("err = %v, want a validation error", err)
+			}
+			if inserted != nil || skipped != nil {
+				t.Errorf("got inserted %v, skipped %v with an error", inserted, skipped)
+			}
+		})
+	}
+}
+
+func TestPostgresGoalRepository_BulkInsertWithPriority(t *testing.T) {
+	db := setupTestDB(t)
+	if db == nil {
+		return
+	}
+	defer cleanupTestDB(t, db)
+
+	ctx := context.Background()
+	repo := NewPostgresGoalRepository(db, WithPerUserLocking(true))
+
+	candidates := func(userID string) []*domain.UserGoalProgress {
+		var rows []*domain.UserGoalProgress
+		for _, goalID := range []string{"low", "top", "mid", "also-top"} {
+			rows = append(rows, &domain.UserGoalProgress{
+				UserID: userID, GoalID: goalID, ChallengeID: "c1", Namespace: "test",
+				Status: domain.GoalStatusNotStarted, IsActive: true,
+			})
+		}
+		return rows
+	}
+	priorities := []int{3, 1, 2, 1}
+
+	existing := func(userID string, goalIDs ...string) {
+		t.Helper()
+		var rows []*domain.UserGoalProgress
+		for _, goalID := range goalIDs {
+			rows = append(rows, &domain.UserGoalProgress{UserID: userID, GoalID: goalID, ChallengeID: "c0", Namespace: "test", Status: domain.GoalStatusInProgress})
+		}
+		if err := repo.BulkInsert(ctx, rows); err != nil {
+			t.Fatalf("BulkInsert failed: %v", err)
+		}
+	}
+	assertRows := func(t *testing.T, userID string, want int) {
+		t.Helper()
+		count, err := repo.GetUserGoalCount(ctx, userID)
+		if err != nil {
+			t.Fatalf("GetUserGoalCount failed: %v", err)
+		}
+		if count != want {
+			t.Errorf("user has %d rows, want %d", count, want)
+		}
+	}
+
+	t.Run("headroom smaller than the candidates respects priority", func(t *testing.T) {
+		existing("prio-user-1", "old-1", "old-2")
+
+		inserted, skipped, err := repo.BulkInsertWithPriority(ctx, candidates("prio-user-1"), priorities, 5)
+		if err != nil {
+			t.Fatalf("BulkInsertWithPriority failed: %v", err)
+		}
+		if !slices.Equal(inserted, []string{"top", "also-top", "mid"}) {
+			t.Errorf("inserted = %v, want [top also-top mid]", inserted)
+		}
+		if !slices.Equal(skipped, []string{"low"}) {
+			t.Errorf("skipped = %v, want [low]", skipped)
+		}
+		assertRows(t, "prio-user-1", 5)
+	})
+
+	t.Run("zero headroom inserts nothing", func(t *testing.T) {
+		existing("prio-user-2", "old-1", "old-2")
+
+		inserted, skipped, err := repo.BulkInsertWithPriority(ctx, candidates("prio-user-2"), priorities, 2)
+		if err != nil {
+			t.Fatalf("BulkInsertWithPriority failed: %v", err)
+		}
+		if inserted != nil {
+			t.Errorf("inserted = %v, want none", inserted)
+		}
+		if !slices.Equal(skipped, []string{"top", "also-top", "mid", "low"}) {
+			t.Errorf("skipped = %v, want every candidate in priority order", skipped)
+		}
+		assertRows(t, "prio-user-2", 2)
+	})
+
+	t.Run("goals the user already has take no headroom", func(t *testing.T) {
+		existing("prio-user-3", "top")
+
+		inserted, skipped, err := repo.BulkInsertWithPriority(ctx, candidates("prio-user-3"), priorities, 3)
+		if err != nil {
+			t.Fatalf("BulkInsertWithPriority failed: %v", err)
+		}
+		if !slices.Equal(inserted, []string{"also-top", "mid"}) {
+			t.Errorf("inserted = %v, want [also-top mid]", inserted)
+		}
+		if !slices.Equal(skipped, []string{"top", "low"}) {
+			t.Errorf("skipped = %v, want [top low]", skipped)
+		}
+		assertRows(t, "prio-user-3", 3)
+	})
+
+	t.Run("in transaction", func(t *testing.T) {
+		tx, err := repo.BeginTx(ctx)
+		if err != nil {
+			t.Fatalf("BeginTx failed: %v", err)
+		}
+		defer func() { _ = tx.Rollback() }()
+
+		inserted, _, err := tx.BulkInsertWithPriority(ctx, candidates("prio-user-4"), priorities, 1)
+		if err != nil {
+			t.Fatalf("BulkInsertWithPriority failed: %v", err)
+		}
+		if !slices.Equal(inserted, []string{"top"}) {
+			t.Errorf("inserted = %v, want [top]", inserted)
+		}
+	})
+}
@@ -0,0 +1,150 @@
+package repository
+
+import (
+	"context"
+	"math"
+	"testing"
+
+	"github.com/AccelByte/extend-challenge-common/pkg/domain"
+)
+
+// aboveInt32 is a progress value an INT column cannot hold.
+const aboveInt32 = int64(math.MaxInt32) + 1_000
+
+func TestWithBigIntProgress(t *testing.T) {
+	tests := []struct {
+		name        string
+		opts        []Option
+		wantMax     int64
+		wantCeiling int64
+	}{
+		{"INT by default", nil, MaxProgress, DefaultProgressCeiling},
+		{"BIGINT", []Option{WithBigIntProgress(true)}, MaxBigIntProgress, DefaultBigIntProgressCeiling},
+		{"ceiling above INT needs BIGINT", []Option{WithProgressCeiling(aboveInt32)}, MaxProgress, DefaultProgressCeiling},
+		{"ceiling above INT with BIGINT", []Option{WithProgressCeiling(aboveInt32), WithBigIntProgress(true)}, MaxBigIntProgress, aboveInt32},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := NewPostgresGoalRepository(nil, tt.opts...)
+			if got := repo.maxProgress(); got != tt.wantMax {
+				t.Errorf("maxProgress() = %d, want %d", got, tt.wantMax)
+			}
+			if repo.progressCeiling != tt.wantCeiling {
+				t.Errorf("progressCeiling = %d, want %d", repo.progressCeiling, tt.wantCeiling)
+			}
+			if got := AllowUnbounded.progressCap(10, AllowUnbounded, repo.maxProgress()); got != tt.wantMax {
+				t.Errorf("unbounded progressCap() = %d, want %d", got, tt.wantMax)
+			}
+		})
+	}
+}
+
+func TestPostgresGoalRepository_BigIntProgress(t *testing.T) {
+	db := setupTestDB(t)
+	if db == nil {
+		return
+	}
+	defer cleanupTestDB(t, db)
+
+	ctx := context.Background()
+	repo := NewPostgresGoalRepository(db, WithBigIntProgress(true))
+	target := int(aboveInt32 + 100)
+
+	goalIDs := []string{"damage-pool", "damage-batch", "damage-tx", "damage-copy"}
+	rows := make([]*domain.UserGoalProgress, len(goalIDs))
+	for i, goalID := range goalIDs {
+		rows[i] = &domain.UserGoalProgress{UserID: "big-user", GoalID: goalID, ChallengeID: "c1", Namespace: "test", Progress: aboveInt32, Status: domain.GoalStatusInProgress, IsActive: true}
+	}
+	if err := repo.BulkInsert(ctx, rows); err != nil {
+		t.Fatalf("BulkInsert failed: %v", err)
+	}
+	assertStatus(t, repo, "big-user", "damage-pool", domain.GoalStatusInProgress)
+
+	if err := repo.IncrementProgress(ctx, "big-user", "damage-pool", "c1", "test", 100, target, false); err != nil {
+		t.Fatalf("IncrementProgress failed: %v", err)
+	}
+	if err := repo.BatchIncrementProgress(ctx, []ProgressIncrement{
+		{UserID: "big-user", GoalID: "damage-batch", ChallengeID: "c1", Namespace: "test", Delta: 100, TargetValue: target},
+	}); err != nil {
+		t.Fatalf("BatchIncrementProgress failed: %v", err)
+	}
+
+	tx, err := repo.BeginTx(ctx)
+	if err != nil {
+		t.Fatalf("BeginTx failed: %v", err)
+	}
+	defer func() { _ = tx.Rollback() }()
+	if err := tx.BatchIncrementProgress(ctx, []ProgressIncrement{
+		{UserID: "big-user", GoalID: "damage-tx", ChallengeID: "c1", Namespace: "test", Delta: 100, TargetValue: target},
+	}); err != nil {
+		t.Fatalf("BatchIncrementProgress in transaction failed: %v", err)
+	}
+	if err := tx.Commit(); err != nil {
+		t.Fatalf("Commit failed: %v", err)
+	}
+
+	if err := repo.BatchUpsertProgressWithCOPY(ctx, []*domain.UserGoalProgress{
+		{UserID: "big-user", GoalID: "damage-copy", ChallengeID: "c1", Namespace: "test", Progress: aboveInt32 + 100, Status: domain.GoalStatusCompleted},
+	}); err != nil {
+		t.Fatalf("BatchUpsertProgressWithCOPY failed: %v", err)
+	}
+
+	for _, goalID := range goalIDs {
+		progress := assertStatus(t, repo, "big-user", goalID, domain.GoalStatusCompleted)
+		if progress.Progress != aboveInt32+100 {
+			t.Errorf("%s progress = %d, want %d", goalID, progress.Progress, aboveInt32+100)
+		}
+	}
+
+	progress, err := repo.GetUserProgress(ctx, "big-user", false)
+	if err != nil {
+		t.Fatalf("GetUserProgress failed: %v", err)
+	}
+	for _, p := range progress {
+		if p.Progress <= math.MaxInt32 {
+			t.Errorf("%s progress = %d, want above MaxInt32", p.GoalID, p.Progress)
+		}
+	}
+}
+
+func TestPostgresGoalRepository_IntProgressColumn(t *testing.T) {
+	db := setupTestDB(t)
+	if db == nil {
+		return
+	}
+	defer cleanupTestDB(t, db)
+
+	// Schemas without migration 013 keep working with the default (INT) repository
+	if _, err := db.Exec(`ALTER TABLE user_goal_progress ALTER COLUMN progress TYPE INT`); err != nil {
+		t.Fatalf("Failed to narrow progress column: %v", err)
+	}
+
+	ctx := context.Background()
+	repo := NewPostgresGoalRepository(db)
+	if err := repo.BulkInsert(ctx, []*domain.UserGoalProgress{
+		{UserID: "int-user", GoalID: "kills", ChallengeID: "c1", Namespace: "test", Progress: MaxProgress - 5, Status: domain.GoalStatusInProgress, IsActive: true},
+		{UserID: "int-user", GoalID: "wins", ChallengeID: "c1", Namespace: "test", Progress: 0, Status: domain.GoalStatusInProgress, IsActive: true},
+	}); err != nil {
+		t.Fatalf("BulkInsert failed: %v", err)
+	}
+
+	// Progress can still reach the INT limit
+	repo = NewPostgresGoalRepository(db, WithProgressCeiling(MaxProgress))
+	if err := repo.BatchIncrementProgress(ctx, []ProgressIncrement{
+		{UserID: "int-user", GoalID: "kills", ChallengeID: "c1", Namespace: "test", Delta: 5, TargetValue: 10},
+		{UserID: "int-user", GoalID: "wins", ChallengeID: "c1", Namespace: "test", Delta: 3, TargetValue: 10},
+	}); err != nil {
+		t.Fatalf("BatchIncrementProgress failed: %v", err)
+	}
+	if err := repo.IncrementProgress(ctx, "int-user", "wins", "c1", "test", 2, 10, false); err != nil {
+		t.Fatalf("IncrementProgress failed: %v", err)
+	}
+
+	if kills := assertStatus(t, repo, "int-user", "kills", domain.GoalStatusCompleted); kills.Progress != MaxProgress {
+		t.Errorf("kills progress = %d, want %d", kills.Progress, int64(MaxProgress))
+	}
+	if wins := assertStatus(t, repo, "int-user", "wins", domain.GoalStatusInProgress); wins.Progress != 5 {
+		t.Errorf("wins progress = %d, want 5", wins.Progress)
+	}
+}
@@ -0,0 +1,149 @@
+package repository
+
+import (
+	"database/sql"
+	"fmt"
+
+	"github.com/AccelByte/extend-challenge-common/pkg/errors"
+)
+
+// DefaultProgressCeiling is the default highest progress an increment may produce,
+// DefaultMaxIncrementDelta below the MaxProgress clamp of the increment SQL.
+const DefaultProgressCeiling = MaxProgress - DefaultMaxIncrementDelta
+
+// DefaultBigIntProgressCeiling is the default progress ceiling with WithBigIntProgress.
+const DefaultBigIntProgressCeiling = MaxBigIntProgress - DefaultMaxIncrementDelta
+
+// progressCeilingGuard returns a predicate that holds unless a positive delta would push
+// progress past the ceiling. The increment statements add it to their WHERE clause, so
+// the row is left untouched by the same statement that checks it and other entries of a
+// batch still apply.
+func progressCeilingGuard(progress, delta, ceiling string) string {
+	return "NOT (" + delta + " > 0 AND " + progress + "::BIGINT + " + delta + " > " + ceiling + ")"
+}
+
+// ceilingSkippedQuery wraps a single increment statement so it returns the progress of
+// the row when the progress ceiling guard skipped it, and no row otherwise. The statement
+// takes the user ID in $1 and the goal ID in $2; the row is read from the statement's
+// snapshot, so no separate query runs before the increment.
+func ceilingSkippedQuery(incrementQuery, deltaParam, ceilingParam string) string {
+	return `
+		WITH incremented AS (` + incrementQuery + `
+			RETURNING user_goal_progress.user_id
+		)
+		SELECT p.progress
+		FROM user_goal_progress AS p
+		WHERE p.user_id = $1
+		  AND p.goal_id = $2
+		  AND ` + protectedStatusGuard("p.status") + `
+		  AND NOT ` + progressCeilingGuard("p.progress", deltaParam+"::BIGINT", ceilingParam+"::BIGINT") + `
+		  AND NOT EXISTS (SELECT 1 FROM incremented)
+	`
+}
+
+// ceilingSkips selects the batch entries whose rows the incremented CTE skipped because
+// the entry's delta would push progress past the ceiling, as (user_id, goal_id, progress,
+// delta). source holds the rows as they were before the statement. Both batch increments
+// take user IDs in $1 and goal IDs in $2.
+func ceilingSkips(source, deltaParam, ceilingParam string) string {
+	return `
+		SELECT t.user_id, t.goal_id, s.progress::BIGINT, t.delta
+		FROM UNNEST($1::VARCHAR(100)[], $2::VARCHAR(100)[], ` + deltaParam + `::BIGINT[]) AS t(user_id, goal_id, delta)
+		JOIN ` + source + ` AS s ON s.user_id = t.user_id AND s.goal_id = t.goal_id
+		WHERE ` + protectedStatusGuard("s.status") + `
+		  AND NOT ` + progressCeilingGuard("s.progress", "t.delta", ceilingParam+"::BIGINT") + `
+		  AND NOT EXISTS (
+			SELECT 1 FROM incremented AS i WHERE i.user_id = t.user_id AND i.goal_id = t.goal_id
+		  )`
+}
+
+// ceilingSkipsQuery wraps a batch increment statement so it returns the entries its
+// progress ceiling guard skipped (see ceilingSkips), in the same round trip.
+func ceilingSkipsQuery(incrementQuery, deltaParam, ceilingParam string) string {
+	return `
+		WITH incremented AS (` + incrementQuery + `
+			RETURNING user_goal_progress.user_id, user_goal_progress.goal_id
+		)` + ceilingSkips("user_goal_progress", deltaParam, ceilingParam)
+}
+
+// ceilingSkip is an increment entry the progress ceiling guard skipped, with the row's
+// progress before the statement.
+type ceilingSkip struct {
+	userID   string
+	goalID   string
+	progress int64
+	delta    int64
+}
+
+// ceilingViolation describes an increment the progress ceiling guard skipped.
+func ceilingViolation(userID, goalID string, progress, delta, ceiling int64) string {
+	return fmt.Sprintf("%s/%s: progress %d + delta %d exceeds ceiling %d", userID, goalID, progress, delta, ceiling)
+}
+
+// describeCeilingSkips returns a violation description for each skipped entry.
+func (r *PostgresGoalRepository) describeCeilingSkips(skips []ceilingSkip) []string {
+	violations := make([]string, len(skips))
+	for i, s := range skips {
+		violations[i] = ceilingViolation(s.userID, s.goalID, s.progress, s.delta, r.progressCeiling)
+	}
+	return violations
+}
+
+// scanCeilingSkips scans rows produced by ceilingSkipsQuery.
+func scanCeilingSkips(rows *sql.Rows) ([]ceilingSkip, error) {
+	var skips []ceilingSkip
+
+	for rows.Next() {
+		var s ceilingSkip
+		if err := rows.Scan(&s.userID, &s.goalID, &s.progress, &s.delta); err != nil {
+			return nil, errors.ErrDatabaseError("scan progress ceiling skip", err)
+		}
+		skips = append(skips, s)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, errors.ErrDatabaseError("iterate progress ceiling skips", err)
+	}
+
+	return skips, nil
+}
+
+// dropCeilingOverflows removes entries whose delta alone exceeds the progress ceiling, so
+// they cannot create a row past it, returning the remaining entries and a description of
+// each dropped one. Entries pushing an existing row past the ceiling are skipped by the
+// increment statement itself.
+func (r *PostgresGoalRepository) dropCeilingOverflows(increments []ProgressIncrement) ([]ProgressIncrement, []string) {
+	var kept []ProgressIncrement
+	var skipped []string
+	for i, inc := range increments {
+		if int64(inc.Delta) <= r.progressCeiling {
+			if kept != nil {
+				kept = append(kept, inc)
+			}
+			continue
+		}
+		if kept == nil {
+			kept = append(make([]ProgressIncrement, 0, len(increments)-1), increments[:i]...)
+		}
+		skipped = append(skipped, fmt.Sprintf("%s/%s: delta %d exceeds ceiling %d", inc.UserID, inc.GoalID, inc.Delta, r.progressCeiling))
+	}
+
+	if kept == nil {
+		return increments, nil
+	}
+	return kept, skipped
+}
+
+// logCeilingSkips logs batch entries skipped because they would push progress past the
+// ceiling. Only the offending entries are skipped; the rest of the batch is applied.
+func (r *PostgresGoalRepository) logCeilingSkips(operation string, skipped []string) {
+	if len(skipped) == 0 {
+		return
+	}
+	r.logger.Warn("Skipped increments past progress ceiling",
+		"operation", operation,
+		"skipped", len(skipped),
+		"ceiling", r.progressCeiling,
+		"entries", skipped,
+	)
+}
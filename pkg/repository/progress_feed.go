@@ -0,0 +1,184 @@
+package repository
+
+import (
+	"context"
+	"hash/crc32"
+	"strconv"
+	"time"
+
+	"github.com/AccelByte/extend-challenge-common/pkg/domain"
+	"github.com/AccelByte/extend-challenge-common/pkg/errors"
+)
+
+// ProgressFeedRepository provides namespace-wide scans over user_goal_progress for
+// background jobs (sync/export, engagement nudges); per-user reads should use
+// GetUserProgressPage. The "changed since" scan is ordered by the keyset
+// (updated_at, user_id, goal_id).
+//
+// Positions are exchanged as opaque tokens (see EncodeCursor). Clients pass back the
+// token they received to continue where the previous call stopped.
+type ProgressFeedRepository interface {
+	// GetProgressUpdatedSince returns up to limit rows in the namespace with
+	// updated_at >= since, continuing after cursor ("" to start at since).
+	// The returned token points at the last row of the page, or is "" when the page is
+	// empty (caught up; keep polling with the previous token).
+	GetProgressUpdatedSince(ctx context.Context, namespace string, since time.Time, limit int, cursor string) ([]*domain.UserGoalProgress, string, error)
+
+	// IterateProgress calls fn for every row GetProgressUpdatedSince would return,
+	// fetching pageSize rows at a time. It returns the token of the last row passed to
+	// fn successfully (or cursor if none), so a later call can resume from there.
+	// Iteration stops at the first error from fn, which is returned.
+	IterateProgress(ctx context.Context, namespace string, since time.Time, pageSize int, cursor string, fn func(*domain.UserGoalProgress) error) (string, error)
+
+	// GetProgressAboveThreshold returns active in_progress rows whose progress is at least
+	// the MinProgress of their goal's threshold (e.g., players close to completing a goal),
+	// keyset-paginated by (goal_id, user_id). Goals without a threshold are not returned.
+	// The returned cursor is "" on the last page.
+	GetProgressAboveThreshold(ctx context.Context, namespace string, thresholds []GoalThreshold, limit int, cursor string) ([]*domain.UserGoalProgress, string, error)
+
+	// GetChallengeParticipants returns every user's rows for a challenge, keyset-paginated
+	// by (user_id, goal_id). It is the cross-user counterpart of GetChallengeProgress,
+	// used by challenge-wide analytics and reporting. Inactive rows are included.
+	// The returned cursor is "" on the last page.
+	GetChallengeParticipants(ctx context.Context, challengeID string, limit int, cursor string) ([]*domain.UserGoalProgress, string, error)
+
+	// GetByChallengeAndStatus returns a challenge's rows in one status (e.g., every claimed
+	// row, for reward reconciliation), keyset-paginated by (user_id, goal_id) so scans of
+	// millions of rows do not degrade like OFFSET. Inactive rows are included. An unknown
+	// status fails with ErrValidationFailed. The returned cursor is "" on the last page.
+	GetByChallengeAndStatus(ctx context.Context, namespace, challengeID string, status domain.GoalStatus, limit int, cursor string) ([]*domain.UserGoalProgress, string, error)
+
+	// GetAssignedBetween returns the namespace's rows whose assigned_at falls within
+	// [from, to] (e.g., every goal assigned in the last hour, to verify the onboarding
+	// job ran), keyset-paginated by (assigned_at, user_id, goal_id). Rows never assigned
+	// are excluded. The returned cursor is "" on the last page.
+	GetAssignedBetween(ctx context.Context, namespace string, from, to time.Time, limit int, cursor string) ([]*domain.UserGoalProgress, string, error)
+
+	// GetOverflowingProgress returns up to limit rows whose progress exceeds factor times
+	// their goal's target, highest progress first, to find hot counters before they reach
+	// MaxProgress. goalTargets maps goal IDs to target values (from the goal cache);
+	// goals missing from it or with a target <= 0 are not checked. factor and limit must
+	// be positive.
+	GetOverflowingProgress(ctx context.Context, goalTargets map[string]int, factor, limit int) ([]*domain.UserGoalProgress, error)
+
+	// SearchProgress returns rows matching an admin search (see ProgressQuery),
+	// keyset-paginated by (goal_id, user_id). limit defaults to DefaultPageLimit and is
+	// capped at MaxPageLimit. Contradictory filters fail with ErrValidationFailed.
+	// The returned cursor is "" on the last page.
+	SearchProgress(ctx context.Context, q ProgressQuery, limit int, cursor string) ([]*domain.UserGoalProgress, string, error)
+
+	// GetNamespaces returns the distinct namespaces with progress rows, sorted, so
+	// maintenance jobs (expiry, export) can iterate per tenant without a separate
+	// registry. Returns an empty slice when the table is empty.
+	GetNamespaces(ctx context.Context) ([]string, error)
+}
+
+// ProgressCursor is the last-seen keyset position of a progress feed scan.
+type ProgressCursor struct {
+	UpdatedAt time.Time
+	UserID    string
+	GoalID    string
+}
+
+// progressCursorToken is the encoded form of a ProgressCursor.
+// Sum is a CRC-32 over the other fields so edited or truncated tokens are rejected.
+// It detects tampering by well-meaning clients, not by attackers; it is not a signature.
+type progressCursorToken struct {
+	UpdatedAt time.Time `json:"t"`
+	UserID    string    `json:"u"`
+	GoalID    string    `json:"g"`
+	Sum       uint32    `json:"s"`
+}
+
+// checksum returns the CRC-32 of the token's key fields.
+func (t progressCursorToken) checksum() uint32 {
+	data := strconv.FormatInt(t.UpdatedAt.UnixMicro(), 10) + "\x00" + t.UserID + "\x00" + t.GoalID
+	return crc32.ChecksumIEEE([]byte(data))
+}
+
+// EncodeCursor encodes a feed position into an opaque, URL-safe token.
+func EncodeCursor(c ProgressCursor) string {
+	t := progressCursorToken{UpdatedAt: c.UpdatedAt.UTC(), UserID: c.UserID, GoalID: c.GoalID}
+	t.Sum = t.checksum()
+	return encodeCursor(t)
+}
+
+// DecodeCursor parses a token produced by EncodeCursor.
+// Garbage or modified tokens return an errors.ErrCodeInvalidCursor error.
+func DecodeCursor(token string) (ProgressCursor, error) {
+	t, err := decodeCursor[progressCursorToken](token)
+	if err != nil {
+		return ProgressCursor{}, err
+	}
+
+	if t.UpdatedAt.IsZero() || t.UserID == "" || t.GoalID == "" {
+		return ProgressCursor{}, errors.ErrInvalidCursor("missing key fields")
+	}
+	if t.Sum != t.checksum() {
+		return ProgressCursor{}, errors.ErrInvalidCursor("checksum mismatch")
+	}
+
+	return ProgressCursor{UpdatedAt: t.UpdatedAt, UserID: t.UserID, GoalID: t.GoalID}, nil
+}
+
+// GetProgressUpdatedSince returns one page of rows in the namespace changed at or after since.
+// since is inclusive so a caller can subtract a clock-skew margin and re-read the boundary;
+// the (updated_at, user_id, goal_id) cursor keeps rows sharing an updated_at from being
+// skipped or repeated across pages. Served by idx_user_goal_progress_feed (migration 011).
+func (r *PostgresGoalRepository) GetProgressUpdatedSince(ctx context.Context, namespace string, since time.Time, limit int, cursor string) ([]*domain.UserGoalProgress, string, error) {
+	query := "SELECT " + r.progressColumns() + " FROM user_goal_progress WHERE namespace = $1 AND updated_at >= $2"
+	args := []interface{}{namespace, since}
+
+	if cursor != "" {
+		c, err := DecodeCursor(cursor)
+		if err != nil {
+			return nil, "", err
+		}
+		query += " AND (updated_at, user_id, goal_id) > ($3, $4, $5)"
+		args = append(args, c.UpdatedAt, c.UserID, c.GoalID)
+	}
+
+	query += " ORDER BY updated_at ASC, user_id ASC, goal_id ASC LIMIT $" + strconv.Itoa(len(args)+1)
+	args = append(args, pageLimit(limit))
+
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, "", errors.ErrDatabaseError("get progress updated since", err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	results, err := r.scanProgressRows(rows)
+	if err != nil {
+		return nil, "", err
+	}
+
+	if len(results) == 0 {
+		return []*domain.UserGoalProgress{}, "", nil
+	}
+	return results, progressCursorFor(results[len(results)-1]), nil
+}
+
+// IterateProgress walks every row changed at or after since, one page at a time.
+func (r *PostgresGoalRepository) IterateProgress(ctx context.Context, namespace string, since time.Time, pageSize int, cursor string, fn func(*domain.UserGoalProgress) error) (string, error) {
+	for {
+		page, _, err := r.GetProgressUpdatedSince(ctx, namespace, since, pageSize, cursor)
+		if err != nil {
+			return cursor, err
+		}
+		if len(page) == 0 {
+			return cursor, nil
+		}
+
+		for _, p := range page {
+			if err := fn(p); err != nil {
+				return cursor, err
+			}
+			cursor = progressCursorFor(p)
+		}
+	}
+}
+
+// progressCursorFor returns the feed token positioned at p.
+func progressCursorFor(p *domain.UserGoalProgress) string {
+	return EncodeCursor(ProgressCursor{UpdatedAt: p.UpdatedAt, UserID: p.UserID, GoalID: p.GoalID})
+}
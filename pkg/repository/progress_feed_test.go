@@ -0,0 +1,224 @@
+package repository
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/AccelByte/extend-challenge-common/pkg/domain"
+	customerrors "github.com/AccelByte/extend-challenge-common/pkg/errors"
+)
+
+func TestEncodeDecodeCursor(t *testing.T) {
+	want := ProgressCursor{
+		UpdatedAt: time.Date(2026, 3, 1, 12, 30, 45, 123456000, time.UTC),
+		UserID:    "user-1",
+		GoalID:    "goal-1",
+	}
+
+	got, err := DecodeCursor(EncodeCursor(want))
+	if err != nil {
+		t.Fatalf("DecodeCursor failed: %v", err)
+	}
+	if !got.UpdatedAt.Equal(want.UpdatedAt) || got.UserID != want.UserID || got.GoalID != want.GoalID {
+		t.Errorf("Round trip = %+v, want %+v", got, want)
+	}
+}
+
+func TestDecodeCursor_Rejects(t *testing.T) {
+	valid := EncodeCursor(ProgressCursor{UpdatedAt: time.Now(), UserID: "user-1", GoalID: "goal-1"})
+
+	tampered := func() string {
+		data, _ := base64.RawURLEncoding.DecodeString(valid)
+		var tok progressCursorToken
+		_ = json.Unmarshal(data, &tok)
+		tok.UserID = "user-2" // Checksum no longer matches
+		data, _ = json.Marshal(tok)
+		return base64.RawURLEncoding.EncodeToString(data)
+	}()
+
+	tests := []struct {
+		name  string
+		token string
+	}{
+		{name: "garbage", token: "not a cursor!"},
+		{name: "valid base64, not JSON", token: base64.RawURLEncoding.EncodeToString([]byte("hello"))},
+		{name: "missing fields", token: base64.RawURLEncoding.EncodeToString([]byte(`{"u":"user-1"}`))},
+		{name: "tampered user ID", token: tampered},
+		{name: "truncated", token: valid[:len(valid)/2]},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := DecodeCursor(tt.token)
+
+			var challengeErr *customerrors.ChallengeError
+			if !errors.As(err, &challengeErr) || challengeErr.Code != customerrors.ErrCodeInvalidCursor {
+				t.Errorf("Expected ErrCodeInvalidCursor, got %v", err)
+			}
+		})
+	}
+}
+
+func TestPostgresGoalRepository_ProgressFeed(t *testing.T) {
+	db := setupTestDB(t)
+	if db == nil {
+		return
+	}
+	defer cleanupTestDB(t, db)
+
+	repo := NewPostgresGoalRepository(db)
+	ctx := context.Background()
+
+	// Five rows sharing one updated_at exercise the (user_id, goal_id) tie-breaker
+	base := time.Now().UTC().Truncate(time.Microsecond).Add(-time.Hour)
+	for _, row := range []struct {
+		userID, goalID string
+		updatedAt      time.Time
+	}{
+		{"feed-a", "g1", base},
+		{"feed-a", "g2", base},
+		{"feed-b", "g1", base},
+		{"feed-b", "g2", base.Add(time.Minute)},
+		{"feed-c", "g1", base.Add(2 * time.Minute)},
+		{"feed-old", "g1", base.Add(-24 * time.Hour)},
+	} {
+		_, err := db.ExecContext(ctx, `
+			INSERT INTO user_goal_progress (user_id, goal_id, challenge_id, namespace, progress, status, updated_at, is_active)
+			VALUES ($1, $2, 'c1', 'test', 1, 'in_progress', $3, true)
+		`, row.userID, row.goalID, row.updatedAt)
+		if err != nil {
+			t.Fatalf("Insert failed: %v", err)
+		}
+	}
+
+	t.Run("pages with tokens", func(t *testing.T) {
+		var seen []string
+		cursor := ""
+		for i := 0; i < 10; i++ {
+			page, next, err := repo.GetProgressUpdatedSince(ctx, "test", base, 2, cursor)
+			if err != nil {
+				t.Fatalf("GetProgressUpdatedSince failed: %v", err)
+			}
+			if len(page) == 0 {
+				if next != "" {
+					t.Errorf("Expected empty token for empty page, got %q", next)
+				}
+				break
+			}
+			for _, p := range page {
+				seen = append(seen, p.UserID+"/"+p.GoalID)
+			}
+			cursor = next
+		}
+
+		want := []string{"feed-a/g1", "feed-a/g2", "feed-b/g1", "feed-b/g2", "feed-c/g1"}
+		if len(seen) != len(want) {
+			t.Fatalf("Expected %v, got %v", want, seen)
+		}
+		for i := range want {
+			if seen[i] != want[i] {
+				t.Errorf("Row %d = %s, want %s", i, seen[i], want[i])
+			}
+		}
+	})
+
+	t.Run("iterate resumes from returned token", func(t *testing.T) {
+		stop := errors.New("stop")
+		count := 0
+		token, err := repo.IterateProgress(ctx, "test", base, 2, "", func(p *domain.UserGoalProgress) error {
+			if count == 3 {
+				return stop
+			}
+			count++
+			return nil
+		})
+		if !errors.Is(err, stop) {
+			t.Fatalf("Expected callback error, got %v", err)
+		}
+
+		rest := 0
+		_, err = repo.IterateProgress(ctx, "test", base, 2, token, func(p *domain.UserGoalProgress) error {
+			rest++
+			return nil
+		})
+		if err != nil {
+			t.Fatalf("IterateProgress failed: %v", err)
+		}
+		if count+rest != 5 {
+			t.Errorf("Expected 5 rows across both runs, got %d + %d", count, rest)
+		}
+	})
+
+	t.Run("tampered token rejected", func(t *testing.T) {
+		_, _, err := repo.GetProgressUpdatedSince(ctx, "test", base, 2, "garbage!")
+		var challengeErr *customerrors.ChallengeError
+		if !errors.As(err, &challengeErr) || challengeErr.Code != customerrors.ErrCodeInvalidCursor {
+			t.Errorf("Expected ErrCodeInvalidCursor, got %v", err)
+		}
+	})
+}
+
+func TestPostgresGoalRepository_ProgressFeed_SharedTimestamps(t *testing.T) {
+	db := setupTestDB(t)
+	if db == nil {
+		return
+	}
+	defer cleanupTestDB(t, db)
+
+	repo := NewPostgresGoalRepository(db)
+	ctx := context.Background()
+
+	// Seven rows share one updated_at, so pages of three split the tie twice
+	shared := time.Now().UTC().Truncate(time.Microsecond).Add(-time.Hour)
+	want := make(map[string]bool)
+	for i := 0; i < 7; i++ {
+		userID := fmt.Sprintf("tie-user-%d", i%3)
+		goalID := fmt.Sprintf("tie-goal-%d", i)
+		_, err := db.ExecContext(ctx, `
+			INSERT INTO user_goal_progress (user_id, goal_id, challenge_id, namespace, progress, status, updated_at, is_active)
+			VALUES ($1, $2, 'c1', 'test', 1, 'in_progress', $3, true)
+		`, userID, goalID, shared)
+		if err != nil {
+			t.Fatalf("Insert failed: %v", err)
+		}
+		want[userID+"/"+goalID] = true
+	}
+
+	// since equal to the shared timestamp is inclusive
+	seen := make(map[string]bool)
+	cursor := ""
+	for pages := 0; ; pages++ {
+		if pages > 10 {
+			t.Fatal("Iteration did not terminate")
+		}
+		page, next, err := repo.GetProgressUpdatedSince(ctx, "test", shared, 3, cursor)
+		if err != nil {
+			t.Fatalf("GetProgressUpdatedSince failed: %v", err)
+		}
+		if len(page) == 0 {
+			break
+		}
+		for _, p := range page {
+			key := p.UserID + "/" + p.GoalID
+			if seen[key] {
+				t.Errorf("Row %s returned twice", key)
+			}
+			seen[key] = true
+		}
+		cursor = next
+	}
+
+	if len(seen) != len(want) {
+		t.Errorf("Iterated %d rows, want %d: %v", len(seen), len(want), seen)
+	}
+	for key := range want {
+		if !seen[key] {
+			t.Errorf("Row %s skipped", key)
+		}
+	}
+}
@@ -0,0 +1,82 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/AccelByte/extend-challenge-common/pkg/domain"
+	"github.com/AccelByte/extend-challenge-common/pkg/errors"
+
+	"github.com/lib/pq"
+)
+
+// ProgressFilter narrows a progress read in SQL. The zero value matches every row.
+type ProgressFilter struct {
+	ActiveOnly bool                // If true, only is_active = true (and, with lazy expiry, unexpired) rows are returned
+	Statuses   []domain.GoalStatus // If non-empty, only rows in one of these statuses are returned
+}
+
+// clause returns the SQL predicates for the filter, with placeholders starting at argOffset+1.
+// activeOnly is the predicate used for ActiveOnly (see activeOnlyClause).
+// An unknown status is rejected so a typo cannot silently return an empty result.
+func (f ProgressFilter) clause(argOffset int, activeOnly string) (string, []interface{}, error) {
+	var predicate string
+	var args []interface{}
+
+	if f.ActiveOnly {
+		predicate += activeOnly
+	}
+
+	if len(f.Statuses) > 0 {
+		statuses := make([]string, len(f.Statuses))
+		for i, s := range f.Statuses {
+			if !s.IsValid() {
+				return "", nil, errors.ErrValidationFailed("statuses", fmt.Sprintf("unsupported status '%s'", s))
+			}
+			statuses[i] = string(s)
+		}
+		predicate += fmt.Sprintf(" AND status = ANY($%d)", argOffset+1)
+		args = append(args, pq.Array(statuses))
+	}
+
+	return predicate, args, nil
+}
+
+// getGoalsByIDs reads a user's rows for the given goal IDs, narrowed by filter.
+// Shared by the pool and transaction implementations of GetGoalsByIDs and GetGoalsByIDsFiltered.
+func (r *PostgresGoalRepository) getGoalsByIDs(ctx context.Context, q queryer, operation, userID string, goalIDs []string, filter ProgressFilter) ([]*domain.UserGoalProgress, error) {
+	if len(goalIDs) == 0 {
+		return []*domain.UserGoalProgress{}, nil
+	}
+
+	args := []interface{}{userID, pq.Array(goalIDs)}
+	predicate, filterArgs, err := filter.clause(len(args), r.activeOnlyClause())
+	if err != nil {
+		return nil, err
+	}
+	args = append(args, filterArgs...)
+
+	query := "SELECT " + r.progressColumns() + `
+		FROM user_goal_progress
+		WHERE user_id = $1 AND goal_id = ANY($2)` + predicate + `
+		ORDER BY created_at ASC`
+
+	rows, err := q.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, errors.ErrDatabaseError(operation, err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	return r.scanProgressRows(rows)
+}
+
+// GetGoalsByIDsFiltered retrieves a user's goal progress records for the given goal IDs,
+// keeping only rows that match filter.
+func (r *PostgresGoalRepository) GetGoalsByIDsFiltered(ctx context.Context, userID string, goalIDs []string, filter ProgressFilter) ([]*domain.UserGoalProgress, error) {
+	return r.getGoalsByIDs(ctx, r.db, "get goals by IDs filtered", userID, goalIDs, filter)
+}
+
+// GetGoalsByIDsFiltered retrieves filtered goal progress records within a transaction.
+func (r *PostgresTxRepository) GetGoalsByIDsFiltered(ctx context.Context, userID string, goalIDs []string, filter ProgressFilter) ([]*domain.UserGoalProgress, error) {
+	return r.parent.getGoalsByIDs(ctx, r.tx, "get goals by IDs filtered in transaction", userID, goalIDs, filter)
+}
@@ -0,0 +1,165 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"testing"
+
+	"github.com/AccelByte/extend-challenge-common/pkg/domain"
+	customerrors "github.com/AccelByte/extend-challenge-common/pkg/errors"
+)
+
+func TestProgressFilter_Clause(t *testing.T) {
+	tests := []struct {
+		name          string
+		filter        ProgressFilter
+		wantPredicate string
+		wantArgs      int
+	}{
+		{"empty filter", ProgressFilter{}, "", 0},
+		{"active only", ProgressFilter{ActiveOnly: true}, " AND is_active = true", 0},
+		{"empty statuses means any", ProgressFilter{Statuses: []domain.GoalStatus{}}, "", 0},
+		{"statuses", ProgressFilter{Statuses: []domain.GoalStatus{domain.GoalStatusCompleted}}, " AND status = ANY($3)", 1},
+		{
+			"both",
+			ProgressFilter{ActiveOnly: true, Statuses: []domain.GoalStatus{domain.GoalStatusInProgress, domain.GoalStatusNotStarted}},
+			" AND is_active = true AND status = ANY($3)",
+			1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			predicate, args, err := tt.filter.clause(2, activeOnlyPredicate)
+			if err != nil {
+				t.Fatalf("clause() unexpected error = %v", err)
+			}
+			if predicate != tt.wantPredicate {
+				t.Errorf("predicate = %q, want %q", predicate, tt.wantPredicate)
+			}
+			if len(args) != tt.wantArgs {
+				t.Errorf("args = %d, want %d", len(args), tt.wantArgs)
+			}
+		})
+	}
+}
+
+func TestGetGoalsByIDsFiltered_InvalidStatus(t *testing.T) {
+	// nil *sql.DB: any SQL would panic, so passing proves validation runs first
+	repo := NewPostgresGoalRepository(nil)
+
+	_, err := repo.GetGoalsByIDsFiltered(context.Background(), "user1", []string{"goal1"},
+		ProgressFilter{Statuses: []domain.GoalStatus{"finished"}})
+
+	var ce *customerrors.ChallengeError
+	if !errors.As(err, &ce) || ce.Code != customerrors.ErrCodeValidationFailed {
+		t.Errorf("Expected VALIDATION_FAILED error, got %v", err)
+	}
+}
+
+func TestPostgresGoalRepository_GetGoalsByIDsFiltered(t *testing.T) {
+	db := setupTestDB(t)
+	if db == nil {
+		return
+	}
+	defer cleanupTestDB(t, db)
+
+	repo := NewPostgresGoalRepository(db)
+	ctx := context.Background()
+
+	row := func(goalID string, status domain.GoalStatus, active bool) *domain.UserGoalProgress {
+		return &domain.UserGoalProgress{UserID: "filter-user", GoalID: goalID, ChallengeID: "c1", Namespace: "test", Status: status, IsActive: active}
+	}
+	seed := []*domain.UserGoalProgress{
+		row("active-not-started", domain.GoalStatusNotStarted, true),
+		row("active-in-progress", domain.GoalStatusInProgress, true),
+		row("active-completed", domain.GoalStatusCompleted, true),
+		row("inactive-in-progress", domain.GoalStatusInProgress, false),
+		row("inactive-claimed", domain.GoalStatusClaimed, false),
+	}
+	if err := repo.BulkInsert(ctx, seed); err != nil {
+		t.Fatalf("BulkInsert failed: %v", err)
+	}
+
+	goalIDs := make([]string, len(seed))
+	for i, p := range seed {
+		goalIDs[i] = p.GoalID
+	}
+
+	tests := []struct {
+		name   string
+		filter ProgressFilter
+		want   []string
+	}{
+		{
+			name:   "empty filter returns every row",
+			filter: ProgressFilter{},
+			want:   goalIDs,
+		},
+		{
+			name:   "empty statuses means any status",
+			filter: ProgressFilter{ActiveOnly: true, Statuses: []domain.GoalStatus{}},
+			want:   []string{"active-not-started", "active-in-progress", "active-completed"},
+		},
+		{
+			name:   "active only",
+			filter: ProgressFilter{ActiveOnly: true},
+			want:   []string{"active-not-started", "active-in-progress", "active-completed"},
+		},
+		{
+			name:   "statuses only",
+			filter: ProgressFilter{Statuses: []domain.GoalStatus{domain.GoalStatusInProgress, domain.GoalStatusClaimed}},
+			want:   []string{"active-in-progress", "inactive-in-progress", "inactive-claimed"},
+		},
+		{
+			name:   "active and statuses",
+			filter: ProgressFilter{ActiveOnly: true, Statuses: []domain.GoalStatus{domain.GoalStatusInProgress, domain.GoalStatusClaimed}},
+			want:   []string{"active-in-progress"},
+		},
+		{
+			name:   "no match",
+			filter: ProgressFilter{ActiveOnly: true, Statuses: []domain.GoalStatus{domain.GoalStatusClaimed}},
+			want:   []string{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pool, err := repo.GetGoalsByIDsFiltered(ctx, "filter-user", goalIDs, tt.filter)
+			if err != nil {
+				t.Fatalf("GetGoalsByIDsFiltered failed: %v", err)
+			}
+			assertGoalIDs(t, pool, tt.want)
+
+			tx, err := repo.BeginTx(ctx)
+			if err != nil {
+				t.Fatalf("BeginTx failed: %v", err)
+			}
+			defer func() { _ = tx.Rollback() }()
+
+			inTx, err := tx.GetGoalsByIDsFiltered(ctx, "filter-user", goalIDs, tt.filter)
+			if err != nil {
+				t.Fatalf("tx GetGoalsByIDsFiltered failed: %v", err)
+			}
+			assertGoalIDs(t, inTx, tt.want)
+		})
+	}
+}
+
+// assertGoalIDs compares the goal IDs of rows with want, ignoring order.
+func assertGoalIDs(t *testing.T, rows []*domain.UserGoalProgress, want []string) {
+	t.Helper()
+
+	got := make([]string, len(rows))
+	for i, p := range rows {
+		got[i] = p.GoalID
+	}
+	want = append([]string(nil), want...)
+	sort.Strings(got)
+	sort.Strings(want)
+	if fmt.Sprint(got) != fmt.Sprint(want) {
+		t.Errorf("Goal IDs = %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,109 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/AccelByte/extend-challenge-common/pkg/domain"
+	"github.com/AccelByte/extend-challenge-common/pkg/errors"
+)
+
+// ProgressHistoryRepository provides time-travel reads over the optional
+// user_goal_progress_history table (migrations/002_create_user_goal_progress_history.up.sql).
+//
+// History is opt-in: the table and its trigger exist only in deployments that applied the
+// migration. The trigger records a snapshot on every write to user_goal_progress, so all
+// GoalRepository write paths are captured without dual-writes.
+type ProgressHistoryRepository interface {
+	// GetProgressAsOf reconstructs a user's progress for a goal as it was at the given time.
+	// Returns nil if the row did not exist at that time (never created, or deleted before it).
+	// Reads older than the retention window return the oldest retained snapshot state or nil.
+	GetProgressAsOf(ctx context.Context, userID, goalID string, at time.Time) (*domain.UserGoalProgress, error)
+
+	// PruneProgressHistory deletes history entries that are no longer needed to answer
+	// as-of reads at or after olderThan. The latest snapshot before olderThan is kept for
+	// each row (it is still the row's state at olderThan) unless it records a delete.
+	// Returns the number of entries removed.
+	PruneProgressHistory(ctx context.Context, olderThan time.Time) (int64, error)
+}
+
+// GetProgressAsOf reconstructs a user's progress for a goal as it was at the given time.
+func (r *PostgresGoalRepository) GetProgressAsOf(ctx context.Context, userID, goalID string, at time.Time) (*domain.UserGoalProgress, error) {
+	query := `
+		SELECT user_id, goal_id, challenge_id, namespace, progress, status,
+		       completed_at, claimed_at, created_at, updated_at,
+		       is_active, assigned_at, expires_at, operation
+		FROM user_goal_progress_history
+		WHERE user_id = $1 AND goal_id = $2 AND valid_from <= $3
+		ORDER BY valid_from DESC, history_id DESC
+		LIMIT 1
+	`
+
+	var progress domain.UserGoalProgress
+	var operation string
+	err := r.db.QueryRowContext(ctx, query, userID, goalID, at).Scan(
+		&progress.UserID,
+		&progress.GoalID,
+		&progress.ChallengeID,
+		&progress.Namespace,
+		&progress.Progress,
+		&progress.Status,
+		&progress.CompletedAt,
+		&progress.ClaimedAt,
+		&progress.CreatedAt,
+		&progress.UpdatedAt,
+		&progress.IsActive,
+		&progress.AssignedAt,
+		&progress.ExpiresAt,
+		&operation,
+	)
+
+	if err == sql.ErrNoRows {
+		return nil, nil // No snapshot at or before the requested time
+	}
+
+	if err != nil {
+		return nil, errors.ErrDatabaseError("get progress as of", err)
+	}
+
+	if operation == "D" {
+		return nil, nil // Row was deleted before the requested time
+	}
+
+	return &progress, nil
+}
+
+// PruneProgressHistory deletes history entries superseded before olderThan.
+func (r *PostgresGoalRepository) PruneProgressHistory(ctx context.Context, olderThan time.Time) (int64, error) {
+	// An entry before the cutoff can go if a later entry (also at or before the cutoff)
+	// supersedes it, or if it is a delete marker: either way it cannot affect as-of reads
+	// at or after the cutoff. The subquery sees the pre-DELETE snapshot, so a delete marker
+	// and the entries it supersedes are removed together.
+	query := `
+		DELETE FROM user_goal_progress_history h
+		WHERE h.valid_from < $1
+		  AND (
+			h.operation = 'D'
+			OR EXISTS (
+				SELECT 1 FROM user_goal_progress_history newer
+				WHERE newer.user_id = h.user_id
+				  AND newer.goal_id = h.goal_id
+				  AND newer.valid_from <= $1
+				  AND (newer.valid_from, newer.history_id) > (h.valid_from, h.history_id)
+			)
+		  )
+	`
+
+	result, err := r.db.ExecContext(ctx, query, olderThan)
+	if err != nil {
+		return 0, errors.ErrDatabaseError("prune progress history", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return 0, errors.ErrDatabaseError("check rows affected", err)
+	}
+
+	return rowsAffected, nil
+}
@@ -0,0 +1,160 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"os"
+	"testing"
+	"time"
+)
+
+// Compile-time check that the Postgres repository supports history reads
+// installProgressHistory applies the optional history migration and returns a cleanup
+// that removes it again, so other tests run without the trigger.
+func installProgressHistory(t *testing.T, db *sql.DB) func() {
+	t.Helper()
+
+	migration, err := os.ReadFile("../../migrations/002_create_user_goal_progress_history.up.sql")
+	if err != nil {
+		t.Fatalf("Failed to read history migration: %v", err)
+	}
+
+	if _, err := db.Exec(string(migration)); err != nil {
+		t.Fatalf("Failed to apply history migration: %v", err)
+	}
+
+	return func() {
+		_, _ = db.Exec("DROP TRIGGER IF EXISTS trg_user_goal_progress_history ON user_goal_progress")
+		_, _ = db.Exec("DROP FUNCTION IF EXISTS record_user_goal_progress_history()")
+		_, _ = db.Exec("DROP TABLE IF EXISTS user_goal_progress_history")
+	}
+}
+
+func TestPostgresGoalRepository_ProgressHistory(t *testing.T) {
+	db := setupTestDB(t)
+	if db == nil {
+		return
+	}
+	defer cleanupTestDB(t, db)
+	defer installProgressHistory(t, db)()
+
+	repo := NewPostgresGoalRepository(db)
+	ctx := context.Background()
+
+	// Write a sequence of states with controlled timestamps:
+	//   t1: inserted with progress 1
+	//   t2: progress 5
+	//   t3: completed with progress 10
+	//   t4: row deleted (valid_from = NOW())
+	base := time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC)
+	t1, t2, t3 := base, base.Add(time.Hour), base.Add(2*time.Hour)
+
+	_, err := db.ExecContext(ctx, `
+		INSERT INTO user_goal_progress (user_id, goal_id, challenge_id, namespace, progress, status, created_at, updated_at)
+		VALUES ('history-user', 'goal-1', 'challenge1', 'test', 1, 'in_progress', $1, $1)
+	`, t1)
+	if err != nil {
+		t.Fatalf("Insert failed: %v", err)
+	}
+
+	_, err = db.ExecContext(ctx, `
+		UPDATE user_goal_progress SET progress = 5, updated_at = $1
+		WHERE user_id = 'history-user' AND goal_id = 'goal-1'
+	`, t2)
+	if err != nil {
+		t.Fatalf("Update failed: %v", err)
+	}
+
+	_, err = db.ExecContext(ctx, `
+		UPDATE user_goal_progress SET progress = 10, status = 'completed', completed_at = $1, updated_at = $1
+		WHERE user_id = 'history-user' AND goal_id = 'goal-1'
+	`, t3)
+	if err != nil {
+		t.Fatalf("Update failed: %v", err)
+	}
+
+	_, err = db.ExecContext(ctx, `DELETE FROM user_goal_progress WHERE user_id = 'history-user' AND goal_id = 'goal-1'`)
+	if err != nil {
+		t.Fatalf("Delete failed: %v", err)
+	}
+
+	t.Run("as-of reads between updates", func(t *testing.T) {
+		tests := []struct {
+			name       string
+			at         time.Time
+			wantNil    bool
+			wantValue  int64
+			wantStatus string
+		}{
+			{name: "before creation", at: t1.Add(-time.Minute), wantNil: true},
+			{name: "at creation", at: t1, wantValue: 1, wantStatus: "in_progress"},
+			{name: "between t1 and t2", at: t1.Add(30 * time.Minute), wantValue: 1, wantStatus: "in_progress"},
+			{name: "between t2 and t3", at: t2.Add(30 * time.Minute), wantValue: 5, wantStatus: "in_progress"},
+			{name: "after completion", at: t3.Add(time.Minute), wantValue: 10, wantStatus: "completed"},
+			{name: "after delete", at: time.Now().Add(time.Hour), wantNil: true},
+		}
+
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				p, err := repo.GetProgressAsOf(ctx, "history-user", "goal-1", tt.at)
+				if err != nil {
+					t.Fatalf("GetProgressAsOf failed: %v", err)
+				}
+				if tt.wantNil {
+					if p != nil {
+						t.Errorf("Expected nil, got %+v", p)
+					}
+					return
+				}
+				if p == nil {
+					t.Fatal("Expected progress, got nil")
+				}
+				if p.Progress != tt.wantValue || string(p.Status) != tt.wantStatus {
+					t.Errorf("Expected progress=%d status=%s, got progress=%d status=%s",
+						tt.wantValue, tt.wantStatus, p.Progress, p.Status)
+				}
+			})
+		}
+	})
+
+	t.Run("pruning removes only superseded old entries", func(t *testing.T) {
+		// Cut off between t2 and t3: the t1 snapshot is superseded by t2, t2 is still the state at the cutoff
+		cutoff := t2.Add(30 * time.Minute)
+
+		removed, err := repo.PruneProgressHistory(ctx, cutoff)
+		if err != nil {
+			t.Fatalf("PruneProgressHistory failed: %v", err)
+		}
+		if removed != 1 {
+			t.Errorf("Expected 1 entry pruned, got %d", removed)
+		}
+
+		p, err := repo.GetProgressAsOf(ctx, "history-user", "goal-1", cutoff)
+		if err != nil || p == nil || p.Progress != 5 {
+			t.Errorf("Expected state at cutoff to survive pruning, got %+v err=%v", p, err)
+		}
+
+		p, err = repo.GetProgressAsOf(ctx, "history-user", "goal-1", t3.Add(time.Minute))
+		if err != nil || p == nil || p.Progress != 10 {
+			t.Errorf("Expected newer entries to survive pruning, got %+v err=%v", p, err)
+		}
+
+		var remaining int
+		if err := db.QueryRowContext(ctx, `SELECT COUNT(*) FROM user_goal_progress_history WHERE user_id = 'history-user'`).Scan(&remaining); err != nil {
+			t.Fatalf("Count failed: %v", err)
+		}
+		if remaining != 3 {
+			t.Errorf("Expected 3 entries remaining (t2, t3, delete), got %d", remaining)
+		}
+	})
+
+	t.Run("pruning past a delete removes the whole row history", func(t *testing.T) {
+		removed, err := repo.PruneProgressHistory(ctx, time.Now().Add(time.Hour))
+		if err != nil {
+			t.Fatalf("PruneProgressHistory failed: %v", err)
+		}
+		if removed != 3 {
+			t.Errorf("Expected 3 entries pruned, got %d", removed)
+		}
+	})
+}
@@ -0,0 +1,43 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/AccelByte/extend-challenge-common/pkg/domain"
+	"github.com/AccelByte/extend-challenge-common/pkg/errors"
+)
+
+// ProgressLite is the part of a progress row a board refresh needs: which goal, how far
+// along and in what state. See GetUserProgressLite.
+type ProgressLite struct {
+	GoalID   string            `json:"goalId"`
+	Progress int64             `json:"progress"`
+	Status   domain.GoalStatus `json:"status"`
+}
+
+func (e executor) getUserProgressLite(ctx context.Context, userID string, activeOnly bool) ([]ProgressLite, error) {
+	query := "SELECT goal_id, progress, status FROM user_goal_progress WHERE user_id = $1"
+	if activeOnly {
+		query += e.repo.activeOnlyClause()
+	}
+	query += " ORDER BY order_index ASC, created_at ASC"
+
+	rows, err := e.q.QueryContext(ctx, query, userID)
+	if err != nil {
+		return nil, errors.ErrDatabaseError(e.op("get user progress lite"), err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	var results []ProgressLite
+	for rows.Next() {
+		var p ProgressLite
+		if err := rows.Scan(&p.GoalID, &p.Progress, &p.Status); err != nil {
+			return nil, errors.ErrDatabaseError("scan progress row", err)
+		}
+		results = append(results, p)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, errors.ErrDatabaseError("iterate progress rows", err)
+	}
+	return results, nil
+}
@@ -0,0 +1,73 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/AccelByte/extend-challenge-common/pkg/domain"
+)
+
+// BenchmarkGetUserProgress_FullVsLite reads a 50-goal board with the full-row and the
+// lightweight read. Reports the JSON payload a board refresh would send per read next to
+// the read latency.
+func BenchmarkGetUserProgress_FullVsLite(b *testing.B) {
+	db := setupM3BenchDB(b)
+	if db == nil {
+		return
+	}
+	defer cleanupM3BenchDB(b, db)
+
+	repo := NewPostgresGoalRepository(db)
+	ctx := context.Background()
+
+	const goals = 50
+	now := time.Now()
+	rows := make([]*domain.UserGoalProgress, goals)
+	for i := range rows {
+		rows[i] = &domain.UserGoalProgress{
+			UserID:      "lite-bench-user",
+			GoalID:      fmt.Sprintf("goal-%d", i),
+			ChallengeID: "lite-bench-challenge",
+			Namespace:   "test",
+			Progress:    int64(i),
+			Status:      domain.GoalStatusInProgress,
+			IsActive:    true,
+			AssignedAt:  &now,
+		}
+	}
+	if err := repo.BulkInsertWithCOPY(ctx, rows); err != nil {
+		b.Fatalf("Setup failed: %v", err)
+	}
+
+	reads := []struct {
+		name string
+		read func() (interface{}, error)
+	}{
+		{"Full", func() (interface{}, error) { return repo.GetUserProgress(ctx, "lite-bench-user", true) }},
+		{"Lite", func() (interface{}, error) { return repo.GetUserProgressLite(ctx, "lite-bench-user", true) }},
+	}
+
+	for _, r := range reads {
+		b.Run(r.name, func(b *testing.B) {
+			var result interface{}
+			var err error
+
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				if result, err = r.read(); err != nil {
+					b.Fatalf("Read failed: %v", err)
+				}
+			}
+			b.StopTimer()
+
+			payload, err := json.Marshal(result)
+			if err != nil {
+				b.Fatalf("Marshal failed: %v", err)
+			}
+			b.ReportMetric(float64(len(payload)), "payload-bytes")
+		})
+	}
+}
@@ -0,0 +1,67 @@
+package repository
+
+import (
+	"context"
+	"testing"
+
+	"github.com/AccelByte/extend-challenge-common/pkg/domain"
+)
+
+func TestPostgresGoalRepository_GetUserProgressLite(t *testing.T) {
+	db := setupTestDB(t)
+	if db == nil {
+		return
+	}
+	defer cleanupTestDB(t, db)
+
+	ctx := context.Background()
+	repo := NewPostgresGoalRepository(db)
+
+	if err := repo.BulkInsert(ctx, []*domain.UserGoalProgress{
+		{UserID: "lite-user", GoalID: "kills", ChallengeID: "c1", Namespace: "test", Progress: 4, Status: domain.GoalStatusInProgress, IsActive: true},
+		{UserID: "lite-user", GoalID: "wins", ChallengeID: "c1", Namespace: "test", Status: domain.GoalStatusNotStarted, IsActive: false},
+		{UserID: "lite-other", GoalID: "kills", ChallengeID: "c1", Namespace: "test", Progress: 9, Status: domain.GoalStatusInProgress, IsActive: true},
+	}); err != nil {
+		t.Fatalf("BulkInsert failed: %v", err)
+	}
+
+	for _, activeOnly := range []bool{false, true} {
+		full, err := repo.GetUserProgress(ctx, "lite-user", activeOnly)
+		if err != nil {
+			t.Fatalf("GetUserProgress failed: %v", err)
+		}
+		lite, err := repo.GetUserProgressLite(ctx, "lite-user", activeOnly)
+		if err != nil {
+			t.Fatalf("GetUserProgressLite failed: %v", err)
+		}
+
+		// Same rows in the same order as the full read
+		if len(lite) != len(full) {
+			t.Fatalf("activeOnly=%v: got %d rows, want %d", activeOnly, len(lite), len(full))
+		}
+		for i, p := range full {
+			if want := (ProgressLite{GoalID: p.GoalID, Progress: p.Progress, Status: p.Status}); lite[i] != want {
+				t.Errorf("activeOnly=%v: row %d = %+v, want %+v", activeOnly, i, lite[i], want)
+			}
+		}
+	}
+
+	t.Run("in transaction", func(t *testing.T) {
+		tx, err := repo.BeginTx(ctx)
+		if err != nil {
+			t.Fatalf("BeginTx failed: %v", err)
+		}
+		defer func() { _ = tx.Rollback() }()
+
+		if err := tx.IncrementProgress(ctx, "lite-user", "kills", "c1", "test", 2, 100, false); err != nil {
+			t.Fatalf("IncrementProgress failed: %v", err)
+		}
+		lite, err := tx.GetUserProgressLite(ctx, "lite-user", true)
+		if err != nil {
+			t.Fatalf("GetUserProgressLite failed: %v", err)
+		}
+		if len(lite) != 1 || lite[0].Progress != 6 {
+			t.Errorf("lite = %+v, want kills at the uncommitted progress 6", lite)
+		}
+	})
+}
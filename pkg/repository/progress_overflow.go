@@ -0,0 +1,112 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/AccelByte/extend-challenge-common/pkg/domain"
+	"github.com/AccelByte/extend-challenge-common/pkg/errors"
+
+	"github.com/lib/pq"
+)
+
+// OverflowPolicy bounds how far increments can push progress past a goal's target.
+// Completed goals keep counting by default, so long-lived rows of high-frequency goals
+// grow toward the INT limit of the progress column.
+//
+// A capped increment stops at the cap (LEAST in the increment SQL). Rows already above
+// the cap are not lowered, and negative deltas are applied as usual. The zero value
+// defers to the repository policy (see WithOverflowPolicy).
+type OverflowPolicy struct {
+	multiple int // 0 = repository default, < 0 = unbounded, n > 0 = cap at n × target
+}
+
+var (
+	// AllowUnbounded lets progress grow up to the column clamp (the default).
+	AllowUnbounded = OverflowPolicy{multiple: -1}
+
+	// CapAtTarget stops progress at the goal's target value.
+	CapAtTarget = OverflowPolicy{multiple: 1}
+)
+
+// CapAtMultiple stops progress at n times the goal's target value.
+// n <= 0 returns AllowUnbounded.
+func CapAtMultiple(n int) OverflowPolicy {
+	if n <= 0 {
+		return AllowUnbounded
+	}
+	return OverflowPolicy{multiple: n}
+}
+
+// progressCap returns the highest progress an increment may produce for targetValue,
+// falling back to fallback when the policy is the zero value. maxProgress is the clamp
+// of the progress column.
+func (p OverflowPolicy) progressCap(targetValue int, fallback OverflowPolicy, maxProgress int64) int64 {
+	if p.multiple == 0 {
+		p = fallback
+	}
+	if p.multiple <= 0 || targetValue <= 0 {
+		return maxProgress
+	}
+	return min(int64(targetValue)*int64(p.multiple), maxProgress)
+}
+
+// incrementCap returns the progress cap for one increment under the repository policy.
+func (r *PostgresGoalRepository) incrementCap(policy OverflowPolicy, targetValue int) int64 {
+	return policy.progressCap(targetValue, r.overflowPolicy, r.maxProgress())
+}
+
+// maxProgress returns the clamp for the configured progress column type.
+func (r *PostgresGoalRepository) maxProgress() int64 {
+	if r.bigIntProgress {
+		return MaxBigIntProgress
+	}
+	return MaxProgress
+}
+
+// getOverflowingProgressQuery joins the caller's goal targets as an UNNEST table; the
+// column list is added at call time. Its columns are renamed to keep progressColumns
+// unambiguous.
+const getOverflowingProgressQuery = `
+	FROM user_goal_progress
+	JOIN UNNEST($1::VARCHAR(100)[], $2::BIGINT[]) AS t(target_goal_id, target_value)
+	  ON user_goal_progress.goal_id = t.target_goal_id
+	WHERE progress::BIGINT > t.target_value::BIGINT * $3
+	ORDER BY progress DESC, user_id ASC, goal_id ASC
+	LIMIT $4`
+
+// GetOverflowingProgress returns rows whose progress exceeds factor × their goal's target.
+func (r *PostgresGoalRepository) GetOverflowingProgress(ctx context.Context, goalTargets map[string]int, factor, limit int) ([]*domain.UserGoalProgress, error) {
+	if factor <= 0 {
+		return nil, errors.ErrValidationFailed("factor", "must be positive")
+	}
+	if limit <= 0 {
+		return nil, errors.ErrValidationFailed("limit", "must be positive")
+	}
+
+	goalIDs := make([]string, 0, len(goalTargets))
+	targets := make([]int, 0, len(goalTargets))
+	for goalID, target := range goalTargets {
+		if target > 0 {
+			goalIDs = append(goalIDs, goalID)
+			targets = append(targets, target)
+		}
+	}
+	if len(goalIDs) == 0 {
+		return []*domain.UserGoalProgress{}, nil
+	}
+
+	rows, err := r.db.QueryContext(ctx, "SELECT "+r.progressColumns()+getOverflowingProgressQuery, pq.Array(goalIDs), pq.Array(targets), factor, limit)
+	if err != nil {
+		return nil, errors.ErrDatabaseError("get overflowing progress", err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	results, err := r.scanProgressRows(rows)
+	if err != nil {
+		return nil, err
+	}
+	if results == nil {
+		results = []*domain.UserGoalProgress{}
+	}
+	return results, nil
+}
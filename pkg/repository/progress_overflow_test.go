@@ -0,0 +1,253 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/AccelByte/extend-challenge-common/pkg/domain"
+	customerrors "github.com/AccelByte/extend-challenge-common/pkg/errors"
+)
+
+func TestOverflowPolicy_ProgressCap(t *testing.T) {
+	tests := []struct {
+		name     string
+		policy   OverflowPolicy
+		fallback OverflowPolicy
+		target   int
+		want     int64
+	}{
+		{"unbounded", AllowUnbounded, CapAtTarget, 5, MaxProgress},
+		{"cap at target", CapAtTarget, AllowUnbounded, 5, 5},
+		{"cap at multiple", CapAtMultiple(10), AllowUnbounded, 5, 50},
+		{"non-positive multiple is unbounded", CapAtMultiple(0), CapAtTarget, 5, MaxProgress},
+		{"zero value uses fallback", OverflowPolicy{}, CapAtMultiple(3), 5, 15},
+		{"zero value with unbounded fallback", OverflowPolicy{}, AllowUnbounded, 5, MaxProgress},
+		{"cap limited to MaxProgress", CapAtMultiple(1000), AllowUnbounded, MaxProgress / 2, MaxProgress},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.policy.progressCap(tt.target, tt.fallback, MaxProgress); got != tt.want {
+				t.Errorf("progressCap() = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestWithOverflowPolicy(t *testing.T) {
+	if got := NewPostgresGoalRepository(nil).overflowPolicy; got != AllowUnbounded {
+		t.Errorf("default overflowPolicy = %+v, want AllowUnbounded", got)
+	}
+	if got := NewPostgresGoalRepository(nil, WithOverflowPolicy(CapAtTarget)).overflowPolicy; got != CapAtTarget {
+		t.Errorf("overflowPolicy = %+v, want CapAtTarget", got)
+	}
+	if got := NewPostgresGoalRepository(nil, WithOverflowPolicy(OverflowPolicy{})).overflowPolicy; got != AllowUnbounded {
+		t.Errorf("zero value overflowPolicy = %+v, want AllowUnbounded", got)
+	}
+}
+
+// incrementTwice applies two increments of 4 toward a target of 5 and returns the final progress.
+func incrementTwice(t *testing.T, ctx context.Context, repo *PostgresGoalRepository, userID, goalID string, policy OverflowPolicy) int64 {
+	t.Helper()
+
+	err := repo.BulkInsert(ctx, []*domain.UserGoalProgress{
+		{UserID: userID, GoalID: goalID, ChallengeID: "c1", Namespace: "test", Status: domain.GoalStatusNotStarted, IsActive: true},
+	})
+	if err != nil {
+		t.Fatalf("BulkInsert failed: %v", err)
+	}
+
+	inc := ProgressIncrement{UserID: userID, GoalID: goalID, ChallengeID: "c1", Namespace: "test", Delta: 4, TargetValue: 5, OverflowPolicy: policy}
+	for i := 0; i < 2; i++ {
+		if err := repo.BatchIncrementProgress(ctx, []ProgressIncrement{inc}); err != nil {
+			t.Fatalf("BatchIncrementProgress failed: %v", err)
+		}
+	}
+
+	progress, err := repo.GetProgress(ctx, userID, goalID)
+	if err != nil {
+		t.Fatalf("GetProgress failed: %v", err)
+	}
+	return progress.Progress
+}
+
+func TestPostgresGoalRepository_OverflowPolicy(t *testing.T) {
+	db := setupTestDB(t)
+	if db == nil {
+		return
+	}
+	defer cleanupTestDB(t, db)
+
+	ctx := context.Background()
+
+	t.Run("default unchanged", func(t *testing.T) {
+		repo := NewPostgresGoalRepository(db)
+		if got := incrementTwice(t, ctx, repo, "overflow-user", "unbounded", OverflowPolicy{}); got != 8 {
+			t.Errorf("Progress = %d, want 8", got)
+		}
+	})
+
+	t.Run("CapAtTarget stops at target", func(t *testing.T) {
+		repo := NewPostgresGoalRepository(db)
+		if got := incrementTwice(t, ctx, repo, "overflow-user", "cap-target", CapAtTarget); got != 5 {
+			t.Errorf("Progress = %d, want 5", got)
+		}
+
+		progress, err := repo.GetProgress(ctx, "overflow-user", "cap-target")
+		if err != nil {
+			t.Fatalf("GetProgress failed: %v", err)
+		}
+		if progress.Status != domain.GoalStatusCompleted {
+			t.Errorf("Status = %s, want completed", progress.Status)
+		}
+	})
+
+	t.Run("CapAtMultiple stops at multiple of target", func(t *testing.T) {
+		repo := NewPostgresGoalRepository(db, WithOverflowPolicy(CapAtMultiple(10)))
+		err := repo.BulkInsert(ctx, []*domain.UserGoalProgress{
+			{UserID: "overflow-user", GoalID: "cap-multiple", ChallengeID: "c1", Namespace: "test", Status: domain.GoalStatusNotStarted, IsActive: true},
+		})
+		if err != nil {
+			t.Fatalf("BulkInsert failed: %v", err)
+		}
+
+		for i := 0; i < 3; i++ {
+			if err := repo.IncrementProgress(ctx, "overflow-user", "cap-multiple", "c1", "test", 20, 5, false); err != nil {
+				t.Fatalf("IncrementProgress failed: %v", err)
+			}
+		}
+
+		progress, err := repo.GetProgress(ctx, "overflow-user", "cap-multiple")
+		if err != nil {
+			t.Fatalf("GetProgress failed: %v", err)
+		}
+		if progress.Progress != 50 {
+			t.Errorf("Progress = %d, want 50", progress.Progress)
+		}
+	})
+
+	t.Run("per-call policy overrides repository", func(t *testing.T) {
+		repo := NewPostgresGoalRepository(db, WithOverflowPolicy(CapAtTarget))
+		if got := incrementTwice(t, ctx, repo, "overflow-user", "override", AllowUnbounded); got != 8 {
+			t.Errorf("Progress = %d, want 8", got)
+		}
+	})
+
+	t.Run("row above cap is not lowered", func(t *testing.T) {
+		repo := NewPostgresGoalRepository(db)
+		if got := incrementTwice(t, ctx, repo, "overflow-user", "legacy", AllowUnbounded); got != 8 {
+			t.Fatalf("Progress = %d, want 8", got)
+		}
+
+		capped := NewPostgresGoalRepository(db, WithOverflowPolicy(CapAtTarget))
+		if err := capped.IncrementProgress(ctx, "overflow-user", "legacy", "c1", "test", 1, 5, false); err != nil {
+			t.Fatalf("IncrementProgress failed: %v", err)
+		}
+		progress, err := capped.GetProgress(ctx, "overflow-user", "legacy")
+		if err != nil {
+			t.Fatalf("GetProgress failed: %v", err)
+		}
+		if progress.Progress != 8 {
+			t.Errorf("Progress = %d, want 8", progress.Progress)
+		}
+	})
+
+	t.Run("transaction", func(t *testing.T) {
+		repo := NewPostgresGoalRepository(db, WithOverflowPolicy(CapAtTarget))
+		tx, err := repo.BeginTx(ctx)
+		if err != nil {
+			t.Fatalf("BeginTx failed: %v", err)
+		}
+		defer func() { _ = tx.Rollback() }()
+
+		// Inserted and updated rows are both capped
+		for i := 0; i < 2; i++ {
+			err := tx.BatchIncrementProgress(ctx, []ProgressIncrement{
+				{UserID: "overflow-user", GoalID: "tx-batch", ChallengeID: "c1", Namespace: "test", Delta: 7, TargetValue: 5},
+			})
+			if err != nil {
+				t.Fatalf("BatchIncrementProgress in transaction failed: %v", err)
+			}
+			if err := tx.IncrementProgress(ctx, "overflow-user", "tx-single", "c1", "test", 7, 5, false); err != nil {
+				t.Fatalf("IncrementProgress in transaction failed: %v", err)
+			}
+		}
+
+		for _, goalID := range []string{"tx-batch", "tx-single"} {
+			progress, err := tx.GetProgress(ctx, "overflow-user", goalID)
+			if err != nil {
+				t.Fatalf("GetProgress failed: %v", err)
+			}
+			if progress.Progress != 5 {
+				t.Errorf("%s Progress = %d, want 5", goalID, progress.Progress)
+			}
+		}
+	})
+}
+
+func TestPostgresGoalRepository_GetOverflowingProgress(t *testing.T) {
+	db := setupTestDB(t)
+	if db == nil {
+		return
+	}
+	defer cleanupTestDB(t, db)
+
+	ctx := context.Background()
+	repo := NewPostgresGoalRepository(db)
+
+	err := repo.BulkInsert(ctx, []*domain.UserGoalProgress{
+		{UserID: "hot-1", GoalID: "kills", ChallengeID: "c1", Namespace: "test", Progress: 500, Status: domain.GoalStatusCompleted, IsActive: true},
+		{UserID: "hot-2", GoalID: "kills", ChallengeID: "c1", Namespace: "test", Progress: 101, Status: domain.GoalStatusCompleted, IsActive: true},
+		{UserID: "hot-3", GoalID: "kills", ChallengeID: "c1", Namespace: "test", Progress: 100, Status: domain.GoalStatusCompleted, IsActive: true},
+		{UserID: "hot-1", GoalID: "wins", ChallengeID: "c1", Namespace: "test", Progress: 40, Status: domain.GoalStatusCompleted, IsActive: true},
+		{UserID: "hot-1", GoalID: "unknown", ChallengeID: "c1", Namespace: "test", Progress: 9999, Status: domain.GoalStatusCompleted, IsActive: true},
+	})
+	if err != nil {
+		t.Fatalf("BulkInsert failed: %v", err)
+	}
+
+	targets := map[string]int{"kills": 10, "wins": 5}
+
+	rows, err := repo.GetOverflowingProgress(ctx, targets, 10, 10)
+	if err != nil {
+		t.Fatalf("GetOverflowingProgress failed: %v", err)
+	}
+	want := []string{"hot-1/kills", "hot-2/kills"}
+	if len(rows) != len(want) {
+		t.Fatalf("GetOverflowingProgress returned %d rows, want %d", len(rows), len(want))
+	}
+	for i, row := range rows {
+		if got := row.UserID + "/" + row.GoalID; got != want[i] {
+			t.Errorf("row %d = %s, want %s", i, got, want[i])
+		}
+	}
+
+	// A lower factor also flags wins (40 > 5×5); the limit keeps the highest progress
+	rows, err = repo.GetOverflowingProgress(ctx, targets, 5, 1)
+	if err != nil {
+		t.Fatalf("GetOverflowingProgress failed: %v", err)
+	}
+	if len(rows) != 1 || rows[0].UserID != "hot-1" || rows[0].GoalID != "kills" {
+		t.Errorf("GetOverflowingProgress with limit 1 = %+v, want hot-1/kills", rows)
+	}
+}
+
+func TestPostgresGoalRepository_GetOverflowingProgress_Validation(t *testing.T) {
+	repo := NewPostgresGoalRepository(nil)
+	ctx := context.Background()
+
+	for _, tc := range []struct{ factor, limit int }{{0, 10}, {10, 0}} {
+		_, err := repo.GetOverflowingProgress(ctx, map[string]int{"g": 5}, tc.factor, tc.limit)
+		var ce *customerrors.ChallengeError
+		if !errors.As(err, &ce) || ce.Code != customerrors.ErrCodeValidationFailed {
+			t.Errorf("factor=%d limit=%d: expected ErrCodeValidationFailed, got %v", tc.factor, tc.limit, err)
+		}
+	}
+
+	// No goal has a positive target: nothing to check, and no database access
+	rows, err := repo.GetOverflowingProgress(ctx, map[string]int{"g": 0}, 10, 10)
+	if err != nil || len(rows) != 0 {
+		t.Errorf("GetOverflowingProgress() = %v, %v; want empty, nil", rows, err)
+	}
+}
@@ -0,0 +1,67 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/lib/pq"
+
+	"github.com/AccelByte/extend-challenge-common/pkg/domain"
+)
+
+// UserChallengePair identifies one user's progress within one challenge. It is comparable,
+// so it can key the map returned by GetProgressForPairs.
+type UserChallengePair struct {
+	UserID      string
+	ChallengeID string
+}
+
+// getProgressForPairs reads the progress of every distinct pair in one round trip by joining
+// the table with the two parallel arrays.
+func (e executor) getProgressForPairs(ctx context.Context, pairs []UserChallengePair, activeOnly bool) (map[UserChallengePair][]*domain.UserGoalProgress, error) {
+	// Every requested pair gets an entry, so callers can cache pairs without rows
+	result := make(map[UserChallengePair][]*domain.UserGoalProgress, len(pairs))
+	userIDs := make([]string, 0, len(pairs))
+	challengeIDs := make([]string, 0, len(pairs))
+	for _, pair := range pairs {
+		if _, seen := result[pair]; seen {
+			continue
+		}
+		result[pair] = []*domain.UserGoalProgress{}
+		userIDs = append(userIDs, pair.UserID)
+		challengeIDs = append(challengeIDs, pair.ChallengeID)
+	}
+	if len(userIDs) == 0 {
+		return result, nil
+	}
+
+	query := "SELECT " + e.repo.progressColumns() + ` FROM user_goal_progress
+		JOIN UNNEST($1::TEXT[], $2::TEXT[]) AS pairs(pair_user_id, pair_challenge_id)
+		  ON user_id = pair_user_id AND challenge_id = pair_challenge_id`
+	if activeOnly {
+		// An inner join, so the predicate can extend the join condition
+		query += e.repo.activeOnlyClause()
+	}
+	query += " ORDER BY user_id, challenge_id, order_index ASC, created_at ASC"
+
+	rows, err := e.queryProgress(ctx, e.op("get progress for pairs"), query, pq.Array(userIDs), pq.Array(challengeIDs))
+	if err != nil {
+		return nil, err
+	}
+
+	for _, row := range rows {
+		pair := UserChallengePair{UserID: row.UserID, ChallengeID: row.ChallengeID}
+		result[pair] = append(result[pair], row)
+	}
+
+	return result, nil
+}
+
+// GetProgressForPairs retrieves the goal progress of many (user, challenge) pairs in one query.
+func (r *PostgresGoalRepository) GetProgressForPairs(ctx context.Context, pairs []UserChallengePair, activeOnly bool) (map[UserChallengePair][]*domain.UserGoalProgress, error) {
+	return r.exec().getProgressForPairs(ctx, pairs, activeOnly)
+}
+
+// GetProgressForPairs retrieves the goal progress of many (user, challenge) pairs within a transaction.
+func (r *PostgresTxRepository) GetProgressForPairs(ctx context.Context, pairs []UserChallengePair, activeOnly bool) (map[UserChallengePair][]*domain.UserGoalProgress, error) {
+	return r.exec().getProgressForPairs(ctx, pairs, activeOnly)
+}
@@ -0,0 +1,127 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/AccelByte/extend-challenge-common/pkg/domain"
+)
+
+func TestGetProgressForPairs_EmptyInput(t *testing.T) {
+	// nil *sql.DB: any SQL would panic, so an empty batch must not query
+	repo := NewPostgresGoalRepository(nil)
+
+	result, err := repo.GetProgressForPairs(context.Background(), nil, false)
+	if err != nil || result == nil || len(result) != 0 {
+		t.Errorf("GetProgressForPairs(nil) = %v, %v, want empty map", result, err)
+	}
+}
+
+func TestPostgresGoalRepository_GetProgressForPairs(t *testing.T) {
+	db := setupTestDB(t)
+	if db == nil {
+		return
+	}
+	defer cleanupTestDB(t, db)
+
+	ctx := context.Background()
+	repo := NewPostgresGoalRepository(db)
+
+	// 50 users with two goals in "c1" and one inactive goal in "c2"; "c3" has no rows
+	var rows []*domain.UserGoalProgress
+	for u := 0; u < 50; u++ {
+		userID := fmt.Sprintf("pair-user-%02d", u)
+		rows = append(rows,
+			&domain.UserGoalProgress{UserID: userID, GoalID: "kills", ChallengeID: "c1", Namespace: "test", Progress: int64(u), Status: domain.GoalStatusInProgress, IsActive: true},
+			&domain.UserGoalProgress{UserID: userID, GoalID: "wins", ChallengeID: "c1", Namespace: "test", Progress: 1, Status: domain.GoalStatusInProgress, IsActive: true},
+			&domain.UserGoalProgress{UserID: userID, GoalID: "logins", ChallengeID: "c2", Namespace: "test", Progress: 1, Status: domain.GoalStatusInProgress, IsActive: false},
+		)
+	}
+	if err := repo.BulkInsert(ctx, rows); err != nil {
+		t.Fatalf("BulkInsert failed: %v", err)
+	}
+
+	// 200 pairs: every user in c1, c2 and c3, plus 50 duplicates of c1 pairs and pairs
+	// for users without any rows
+	var pairs []UserChallengePair
+	for u := 0; u < 50; u++ {
+		userID := fmt.Sprintf("pair-user-%02d", u)
+		pairs = append(pairs,
+			UserChallengePair{UserID: userID, ChallengeID: "c1"},
+			UserChallengePair{UserID: userID, ChallengeID: "c2"},
+			UserChallengePair{UserID: userID, ChallengeID: "c3"},
+		)
+	}
+	for u := 0; u < 40; u++ {
+		pairs = append(pairs, UserChallengePair{UserID: fmt.Sprintf("pair-user-%02d", u), ChallengeID: "c1"})
+	}
+	for u := 0; u < 10; u++ {
+		pairs = append(pairs, UserChallengePair{UserID: fmt.Sprintf("pair-stranger-%02d", u), ChallengeID: "c1"})
+	}
+
+	tests := []struct {
+		name       string
+		activeOnly bool
+		wantC2     int
+	}{
+		{name: "all rows", wantC2: 1},
+		{name: "active only", activeOnly: true, wantC2: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := repo.GetProgressForPairs(ctx, pairs, tt.activeOnly)
+			if err != nil {
+				t.Fatalf("GetProgressForPairs failed: %v", err)
+			}
+
+			for _, pair := range pairs {
+				got, ok := result[pair]
+				if !ok || got == nil {
+					t.Fatalf("pair %+v missing from result", pair)
+				}
+
+				want := 0
+				switch {
+				case strings.HasPrefix(pair.UserID, "pair-stranger"):
+				case pair.ChallengeID == "c1":
+					want = 2
+				case pair.ChallengeID == "c2":
+					want = tt.wantC2
+				}
+				if len(got) != want {
+					t.Errorf("pair %+v has %d rows, want %d", pair, len(got), want)
+				}
+				for i, row := range got {
+					if row.UserID != pair.UserID || row.ChallengeID != pair.ChallengeID {
+						t.Errorf("pair %+v holds row for (%s, %s)", pair, row.UserID, row.ChallengeID)
+					}
+					if i > 0 && row.CreatedAt.Before(got[i-1].CreatedAt) {
+						t.Errorf("pair %+v rows are not in created_at order", pair)
+					}
+				}
+			}
+			if len(result) != 160 {
+				t.Errorf("result has %d pairs, want 160 distinct pairs", len(result))
+			}
+		})
+	}
+
+	t.Run("in transaction", func(t *testing.T) {
+		tx, err := repo.BeginTx(ctx)
+		if err != nil {
+			t.Fatalf("BeginTx failed: %v", err)
+		}
+		defer func() { _ = tx.Rollback() }()
+
+		result, err := tx.GetProgressForPairs(ctx, pairs[:3], false)
+		if err != nil {
+			t.Fatalf("GetProgressForPairs in transaction failed: %v", err)
+		}
+		if len(result[pairs[0]]) != 2 || len(result[pairs[1]]) != 1 || len(result[pairs[2]]) != 0 {
+			t.Errorf("GetProgressForPairs in transaction = %v, want 2/1/0 rows", result)
+		}
+	})
+}
@@ -0,0 +1,69 @@
+package repository
+
+import (
+	"context"
+	"testing"
+
+	"github.com/AccelByte/extend-challenge-common/pkg/domain"
+	customerrors "github.com/AccelByte/extend-challenge-common/pkg/errors"
+)
+
+func TestPostgresGoalRepository_GetProgressRequired(t *testing.T) {
+	db := setupTestDB(t)
+	if db == nil {
+		return
+	}
+	defer cleanupTestDB(t, db)
+
+	repo := NewPostgresGoalRepository(db)
+	ctx := context.Background()
+
+	if err := repo.BulkInsert(ctx, []*domain.UserGoalProgress{
+		{UserID: "required-user", GoalID: "kills", ChallengeID: "c1", Namespace: "test", Progress: 4, Status: domain.GoalStatusInProgress, IsActive: true},
+	}); err != nil {
+		t.Fatalf("BulkInsert failed: %v", err)
+	}
+
+	t.Run("existing row", func(t *testing.T) {
+		got, err := repo.GetProgressRequired(ctx, "required-user", "kills")
+		if err != nil || got == nil || got.Progress != 4 {
+			t.Errorf("GetProgressRequired = %+v, %v", got, err)
+		}
+	})
+
+	t.Run("missing row", func(t *testing.T) {
+		got, err := repo.GetProgressRequired(ctx, "required-user", "wins")
+		assertErrorCode(t, err, customerrors.ErrCodeProgressNotFound)
+		if got != nil {
+			t.Errorf("GetProgressRequired = %+v, want nil", got)
+		}
+	})
+
+	t.Run("in transaction", func(t *testing.T) {
+		tx, err := repo.BeginTx(ctx)
+		if err != nil {
+			t.Fatalf("BeginTx failed: %v", err)
+		}
+		defer func() { _ = tx.Rollback() }()
+
+		if _, err := tx.GetProgressRequired(ctx, "required-user", "kills"); err != nil {
+			t.Errorf("GetProgressRequired in transaction failed: %v", err)
+		}
+		_, err = tx.GetProgressRequired(ctx, "other-user", "kills")
+		assertErrorCode(t, err, customerrors.ErrCodeProgressNotFound)
+	})
+}
+
+func TestDualWriteGoalRepository_GetProgressRequired(t *testing.T) {
+	ctx := context.Background()
+	repo, _, oldRepo, _, _ := newDualWriteFixture(t, WriteBothReadNew)
+	oldRepo.put(domain.UserGoalProgress{UserID: "u1", GoalID: "kills", Progress: 7})
+
+	// The read falls back to the old backend before reporting the row missing
+	if got, err := repo.GetProgressRequired(ctx, "u1", "kills"); err != nil || got == nil || got.Progress != 7 {
+		t.Errorf("GetProgressRequired = %+v, %v, want the old backend's row", got, err)
+	}
+
+	_, err := repo.GetProgressRequired(ctx, "u1", "wins")
+	assertErrorCode(t, err, customerrors.ErrCodeProgressNotFound)
+}
@@ -0,0 +1,34 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/AccelByte/extend-challenge-common/pkg/domain"
+	"github.com/AccelByte/extend-challenge-common/pkg/errors"
+)
+
+// ProgressSampler picks rows for spot checks, e.g., comparing two backends during a table
+// migration (see DualWriteGoalRepository.VerifySample).
+type ProgressSampler interface {
+	// SampleRecentProgress returns up to n rows picked at random among the rows updated at
+	// or after since. n must be positive.
+	SampleRecentProgress(ctx context.Context, since time.Time, n int) ([]*domain.UserGoalProgress, error)
+}
+
+// sampleRecentProgressQuery sorts the window's rows by random(), so it reads every row
+// updated since the start of the window; keep the window short on busy tables. The
+// column list is added at call time.
+const sampleRecentProgressQuery = `
+	FROM user_goal_progress
+	WHERE updated_at >= $1
+	ORDER BY random()
+	LIMIT $2`
+
+// SampleRecentProgress returns up to n random rows updated at or after since.
+func (r *PostgresGoalRepository) SampleRecentProgress(ctx context.Context, since time.Time, n int) ([]*domain.UserGoalProgress, error) {
+	if n <= 0 {
+		return nil, errors.ErrValidationFailed("n", "must be positive")
+	}
+	return r.exec().queryProgress(ctx, "sample recent progress", "SELECT "+r.progressColumns()+sampleRecentProgressQuery, since, n)
+}
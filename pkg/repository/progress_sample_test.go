@@ -0,0 +1,51 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/AccelByte/extend-challenge-common/pkg/domain"
+	customerrors "github.com/AccelByte/extend-challenge-common/pkg/errors"
+)
+
+func TestSampleRecentProgress_InvalidN(t *testing.T) {
+	// nil *sql.DB: any SQL would panic, so an invalid n must be rejected first
+	repo := NewPostgresGoalRepository(nil)
+
+	_, err := repo.SampleRecentProgress(context.Background(), time.Now(), 0)
+	assertErrorCode(t, err, customerrors.ErrCodeValidationFailed)
+}
+
+func TestPostgresGoalRepository_SampleRecentProgress(t *testing.T) {
+	db := setupTestDB(t)
+	if db == nil {
+		return
+	}
+	defer cleanupTestDB(t, db)
+
+	repo := NewPostgresGoalRepository(db)
+	ctx := context.Background()
+
+	var rows []*domain.UserGoalProgress
+	for i := 0; i < 20; i++ {
+		rows = append(rows, &domain.UserGoalProgress{UserID: fmt.Sprintf("sample-user-%02d", i), GoalID: "kills", ChallengeID: "c1", Namespace: "test", Status: domain.GoalStatusInProgress, IsActive: true})
+	}
+	if err := repo.BulkInsert(ctx, rows); err != nil {
+		t.Fatalf("BulkInsert failed: %v", err)
+	}
+
+	sample, err := repo.SampleRecentProgress(ctx, time.Now().Add(-time.Hour), 5)
+	if err != nil {
+		t.Fatalf("SampleRecentProgress failed: %v", err)
+	}
+	if len(sample) != 5 {
+		t.Errorf("Sampled %d rows, want 5", len(sample))
+	}
+
+	sample, err = repo.SampleRecentProgress(ctx, time.Now().Add(time.Hour), 5)
+	if err != nil || len(sample) != 0 {
+		t.Errorf("SampleRecentProgress outside the window = %d rows, %v, want none", len(sample), err)
+	}
+}
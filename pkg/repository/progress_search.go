@@ -0,0 +1,132 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/AccelByte/extend-challenge-common/pkg/domain"
+	"github.com/AccelByte/extend-challenge-common/pkg/errors"
+
+	"github.com/lib/pq"
+)
+
+// maxGoalIDPrefixLength matches the goal_id column width.
+const maxGoalIDPrefixLength = 100
+
+// ProgressQuery is a typed search over user_goal_progress for admin tools.
+// Every field is optional; set fields are combined with AND. The zero value matches
+// every row. Values are always sent as query parameters, never spliced into SQL.
+type ProgressQuery struct {
+	Namespace     string              // If set, only rows in this namespace
+	Statuses      []domain.GoalStatus // If non-empty, only rows in one of these statuses
+	MinProgress   *int                // If set, only rows with progress >= MinProgress
+	MaxProgress   *int                // If set, only rows with progress <= MaxProgress
+	UpdatedAfter  *time.Time          // If set, only rows with updated_at >= UpdatedAfter
+	UpdatedBefore *time.Time          // If set, only rows with updated_at < UpdatedBefore
+	IsActive      *bool               // If set, only rows with this is_active value
+	GoalIDPrefix  string              // If set, only goal IDs starting with this literal prefix
+}
+
+// escapeLike escapes the LIKE metacharacters in s so it matches literally (ESCAPE '\').
+func escapeLike(s string) string {
+	return strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`).Replace(s)
+}
+
+// where returns the WHERE predicates for the query, with placeholders starting at $1.
+// Contradictory or out-of-range fields are rejected with ErrValidationFailed instead of
+// building a query that can never match.
+func (q ProgressQuery) where() (string, []interface{}, error) {
+	predicates := []string{"TRUE"}
+	var args []interface{}
+	add := func(format string, arg interface{}) {
+		args = append(args, arg)
+		predicates = append(predicates, fmt.Sprintf(format, len(args)))
+	}
+
+	if q.Namespace != "" {
+		add("namespace = $%d", q.Namespace)
+	}
+
+	if len(q.Statuses) > 0 {
+		statuses := make([]string, len(q.Statuses))
+		for i, s := range q.Statuses {
+			if !s.IsValid() {
+				return "", nil, errors.ErrValidationFailed("statuses", fmt.Sprintf("unsupported status '%s'", s))
+			}
+			statuses[i] = string(s)
+		}
+		add("status = ANY($%d)", pq.Array(statuses))
+	}
+
+	if q.MinProgress != nil && q.MaxProgress != nil && *q.MinProgress > *q.MaxProgress {
+		return "", nil, errors.ErrValidationFailed("progress", fmt.Sprintf("min %d is greater than max %d", *q.MinProgress, *q.MaxProgress))
+	}
+	if q.MinProgress != nil {
+		add("progress >= $%d", *q.MinProgress)
+	}
+	if q.MaxProgress != nil {
+		add("progress <= $%d", *q.MaxProgress)
+	}
+
+	if q.UpdatedAfter != nil && q.UpdatedBefore != nil && !q.UpdatedAfter.Before(*q.UpdatedBefore) {
+		return "", nil, errors.ErrValidationFailed("updated", "updated_after must be before updated_before")
+	}
+	if q.UpdatedAfter != nil {
+		add("updated_at >= $%d", *q.UpdatedAfter)
+	}
+	if q.UpdatedBefore != nil {
+		add("updated_at < $%d", *q.UpdatedBefore)
+	}
+
+	if q.IsActive != nil {
+		add("is_active = $%d", *q.IsActive)
+	}
+
+	if q.GoalIDPrefix != "" {
+		if len(q.GoalIDPrefix) > maxGoalIDPrefixLength {
+			return "", nil, errors.ErrValidationFailed("goal_id_prefix", fmt.Sprintf("longer than %d characters", maxGoalIDPrefixLength))
+		}
+		add(`goal_id LIKE $%d ESCAPE '\'`, escapeLike(q.GoalIDPrefix)+"%")
+	}
+
+	return strings.Join(predicates, " AND "), args, nil
+}
+
+// SearchProgress returns one page of rows matching q, keyset-paginated by (goal_id, user_id).
+func (r *PostgresGoalRepository) SearchProgress(ctx context.Context, q ProgressQuery, limit int, cursor string) ([]*domain.UserGoalProgress, string, error) {
+	where, args, err := q.where()
+	if err != nil {
+		return nil, "", err
+	}
+
+	if cursor != "" {
+		c, err := decodeThresholdCursor(cursor)
+		if err != nil {
+			return nil, "", err
+		}
+		where += fmt.Sprintf(" AND (goal_id, user_id) > ($%d, $%d)", len(args)+1, len(args)+2)
+		args = append(args, c.GoalID, c.UserID)
+	}
+
+	// Fetch one extra row to know whether another page exists
+	limit = pageLimit(limit)
+	query := "SELECT " + r.progressColumns() + " FROM user_goal_progress WHERE " + where +
+		fmt.Sprintf(" ORDER BY goal_id ASC, user_id ASC LIMIT $%d", len(args)+1)
+	args = append(args, limit+1)
+
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, "", errors.ErrDatabaseError("search progress", err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	results, err := r.scanProgressRows(rows)
+	if err != nil {
+		return nil, "", err
+	}
+
+	results, next := pageTail(results, limit, encodeThresholdCursor)
+	return results, next, nil
+}
@@ -0,0 +1,215 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/AccelByte/extend-challenge-common/pkg/domain"
+	customerrors "github.com/AccelByte/extend-challenge-common/pkg/errors"
+
+	"github.com/lib/pq"
+)
+
+func intPtr(v int) *int { return &v }
+
+func TestProgressQuery_Where(t *testing.T) {
+	after := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	before := after.Add(24 * time.Hour)
+	active := true
+
+	tests := []struct {
+		name      string
+		q         ProgressQuery
+		wantWhere string
+		wantArgs  []interface{}
+	}{
+		{"zero value", ProgressQuery{}, "TRUE", nil},
+		{"namespace", ProgressQuery{Namespace: "ns"}, "TRUE AND namespace = $1", []interface{}{"ns"}},
+		{
+			"statuses",
+			ProgressQuery{Statuses: []domain.GoalStatus{domain.GoalStatusCompleted, domain.GoalStatusClaimed}},
+			"TRUE AND status = ANY($1)",
+			[]interface{}{pq.Array([]string{"completed", "claimed"})},
+		},
+		{"min progress", ProgressQuery{MinProgress: intPtr(5)}, "TRUE AND progress >= $1", []interface{}{5}},
+		{"max progress", ProgressQuery{MaxProgress: intPtr(9)}, "TRUE AND progress <= $1", []interface{}{9}},
+		{"updated after", ProgressQuery{UpdatedAfter: &after}, "TRUE AND updated_at >= $1", []interface{}{after}},
+		{"updated before", ProgressQuery{UpdatedBefore: &before}, "TRUE AND updated_at < $1", []interface{}{before}},
+		{"active flag", ProgressQuery{IsActive: &active}, "TRUE AND is_active = $1", []interface{}{true}},
+		{"goal prefix", ProgressQuery{GoalIDPrefix: "daily-"}, `TRUE AND goal_id LIKE $1 ESCAPE '\'`, []interface{}{"daily-%"}},
+		{
+			"metacharacters in goal prefix are escaped",
+			ProgressQuery{GoalIDPrefix: `50%_off\'; DROP TABLE user_goal_progress; --`},
+			`TRUE AND goal_id LIKE $1 ESCAPE '\'`,
+			[]interface{}{`50\%\_off\\'; DROP TABLE user\_goal\_progress; --%`},
+		},
+		{
+			"combination",
+			ProgressQuery{Namespace: "ns", MinProgress: intPtr(1), MaxProgress: intPtr(1), IsActive: &active, GoalIDPrefix: "g"},
+			`TRUE AND namespace = $1 AND progress >= $2 AND progress <= $3 AND is_active = $4 AND goal_id LIKE $5 ESCAPE '\'`,
+			[]interface{}{"ns", 1, 1, true, "g%"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			where, args, err := tt.q.where()
+			if err != nil {
+				t.Fatalf("where() error = %v", err)
+			}
+			if where != tt.wantWhere {
+				t.Errorf("where() = %q, want %q", where, tt.wantWhere)
+			}
+			if !reflect.DeepEqual(args, tt.wantArgs) {
+				t.Errorf("where() args = %#v, want %#v", args, tt.wantArgs)
+			}
+		})
+	}
+}
+
+func TestProgressQuery_Where_Invalid(t *testing.T) {
+	after := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name string
+		q    ProgressQuery
+	}{
+		{"unknown status", ProgressQuery{Statuses: []domain.GoalStatus{"done"}}},
+		{"min above max", ProgressQuery{MinProgress: intPtr(10), MaxProgress: intPtr(5)}},
+		{"empty updated window", ProgressQuery{UpdatedAfter: &after, UpdatedBefore: &after}},
+		{"goal prefix too long", ProgressQuery{GoalIDPrefix: strings.Repeat("g", maxGoalIDPrefixLength+1)}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, _, err := tt.q.where()
+			var ce *customerrors.ChallengeError
+			if !errors.As(err, &ce) || ce.Code != customerrors.ErrCodeValidationFailed {
+				t.Errorf("Expected ErrCodeValidationFailed, got %v", err)
+			}
+		})
+	}
+
+	// SearchProgress validates before touching the database
+	_, _, err := NewPostgresGoalRepository(nil).SearchProgress(context.Background(), tests[0].q, 10, "")
+	var ce *customerrors.ChallengeError
+	if !errors.As(err, &ce) || ce.Code != customerrors.ErrCodeValidationFailed {
+		t.Errorf("SearchProgress: expected ErrCodeValidationFailed, got %v", err)
+	}
+}
+
+func TestPostgresGoalRepository_SearchProgress(t *testing.T) {
+	db := setupTestDB(t)
+	if db == nil {
+		return
+	}
+	defer cleanupTestDB(t, db)
+
+	ctx := context.Background()
+	repo := NewPostgresGoalRepository(db)
+
+	err := repo.BulkInsert(ctx, []*domain.UserGoalProgress{
+		{UserID: "search-1", GoalID: "daily-kills", ChallengeID: "c1", Namespace: "ns-a", Progress: 3, Status: domain.GoalStatusInProgress, IsActive: true},
+		{UserID: "search-2", GoalID: "daily-kills", ChallengeID: "c1", Namespace: "ns-a", Progress: 10, Status: domain.GoalStatusCompleted, IsActive: true},
+		{UserID: "search-1", GoalID: "daily_wins", ChallengeID: "c1", Namespace: "ns-a", Progress: 7, Status: domain.GoalStatusInProgress, IsActive: false},
+		{UserID: "search-1", GoalID: "dailyXwins", ChallengeID: "c1", Namespace: "ns-b", Progress: 1, Status: domain.GoalStatusInProgress, IsActive: true},
+		{UserID: "search-3", GoalID: "50%_off", ChallengeID: "c1", Namespace: "ns-b", Progress: 0, Status: domain.GoalStatusNotStarted, IsActive: true},
+		{UserID: "search-3", GoalID: "50X_offer", ChallengeID: "c1", Namespace: "ns-b", Progress: 0, Status: domain.GoalStatusNotStarted, IsActive: true},
+	})
+	if err != nil {
+		t.Fatalf("BulkInsert failed: %v", err)
+	}
+	if _, err := db.ExecContext(ctx, `UPDATE user_goal_progress SET updated_at = NOW() - INTERVAL '2 days' WHERE goal_id = 'daily-kills'`); err != nil {
+		t.Fatalf("Backdate failed: %v", err)
+	}
+
+	inactive := false
+	dayAgo := time.Now().Add(-24 * time.Hour)
+
+	tests := []struct {
+		name string
+		q    ProgressQuery
+		want []string
+	}{
+		{"namespace", ProgressQuery{Namespace: "ns-b"}, []string{"50%_off/search-3", "50X_offer/search-3", "dailyXwins/search-1"}},
+		{"statuses", ProgressQuery{Statuses: []domain.GoalStatus{domain.GoalStatusCompleted}}, []string{"daily-kills/search-2"}},
+		{"min progress", ProgressQuery{MinProgress: intPtr(7)}, []string{"daily-kills/search-2", "daily_wins/search-1"}},
+		{"max progress", ProgressQuery{MaxProgress: intPtr(0)}, []string{"50%_off/search-3", "50X_offer/search-3"}},
+		{"updated window", ProgressQuery{UpdatedBefore: &dayAgo}, []string{"daily-kills/search-1", "daily-kills/search-2"}},
+		{"updated after", ProgressQuery{UpdatedAfter: &dayAgo, Namespace: "ns-a"}, []string{"daily_wins/search-1"}},
+		{"active flag", ProgressQuery{IsActive: &inactive}, []string{"daily_wins/search-1"}},
+		{"goal prefix", ProgressQuery{GoalIDPrefix: "daily"}, []string{"daily-kills/search-1", "daily-kills/search-2", "daily_wins/search-1", "dailyXwins/search-1"}},
+		{"underscore matches literally", ProgressQuery{GoalIDPrefix: "daily_"}, []string{"daily_wins/search-1"}},
+		{"percent matches literally", ProgressQuery{GoalIDPrefix: "50%"}, []string{"50%_off/search-3"}},
+		{"injection attempt is a literal", ProgressQuery{GoalIDPrefix: "' OR '1'='1"}, nil},
+		{
+			"combination",
+			ProgressQuery{Namespace: "ns-a", GoalIDPrefix: "daily-", MinProgress: intPtr(1), MaxProgress: intPtr(5)},
+			[]string{"daily-kills/search-1"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rows, cursor, err := repo.SearchProgress(ctx, tt.q, 100, "")
+			if err != nil {
+				t.Fatalf("SearchProgress failed: %v", err)
+			}
+			if cursor != "" {
+				t.Errorf("cursor = %q, want \"\"", cursor)
+			}
+			got := make([]string, len(rows))
+			for i, row := range rows {
+				got[i] = row.GoalID + "/" + row.UserID
+			}
+			if len(got) != len(tt.want) || (len(got) > 0 && !reflect.DeepEqual(got, tt.want)) {
+				t.Errorf("SearchProgress() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	t.Run("pagination", func(t *testing.T) {
+		var pages []string
+		cursor := ""
+		for {
+			rows, next, err := repo.SearchProgress(ctx, ProgressQuery{}, 4, cursor)
+			if err != nil {
+				t.Fatalf("SearchProgress failed: %v", err)
+			}
+			for _, row := range rows {
+				pages = append(pages, row.GoalID+"/"+row.UserID)
+			}
+			if next == "" {
+				break
+			}
+			cursor = next
+
+			// A row inserted before the cursor does not shift later pages
+			if err := repo.BulkInsert(ctx, []*domain.UserGoalProgress{
+				{UserID: "search-0", GoalID: "0-early", ChallengeID: "c1", Namespace: "ns-a", Status: domain.GoalStatusNotStarted, IsActive: true},
+			}); err != nil {
+				t.Fatalf("BulkInsert failed: %v", err)
+			}
+		}
+
+		all, _, err := repo.SearchProgress(ctx, ProgressQuery{Namespace: "ns-a"}, 0, "")
+		if err != nil {
+			t.Fatalf("SearchProgress failed: %v", err)
+		}
+		if len(all) != 4 {
+			t.Errorf("ns-a rows = %d, want 4 after insert", len(all))
+		}
+
+		want := []string{
+			"50%_off/search-3", "50X_offer/search-3", "daily-kills/search-1", "daily-kills/search-2",
+			"daily_wins/search-1", "dailyXwins/search-1",
+		}
+		if !reflect.DeepEqual(pages, want) {
+			t.Errorf("paged rows = %v, want %v", pages, want)
+		}
+	})
+}
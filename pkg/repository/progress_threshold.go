@@ -0,0 +1,99 @@
+package repository
+
+import (
+	"context"
+	"strconv"
+
+	"github.com/AccelByte/extend-challenge-common/pkg/domain"
+	"github.com/AccelByte/extend-challenge-common/pkg/errors"
+
+	"github.com/lib/pq"
+)
+
+// GoalThreshold is the minimum progress at which a goal's in-progress rows are returned
+// by GetProgressAboveThreshold. Callers derive MinProgress from the goal's target
+// (e.g., 80% of Requirement.TargetValue). Each goal ID should appear at most once;
+// a duplicated goal returns its matching rows once per entry.
+type GoalThreshold struct {
+	GoalID      string
+	MinProgress int
+}
+
+// thresholdCursor is the decoded form of a GetProgressAboveThreshold cursor.
+type thresholdCursor struct {
+	GoalID string `json:"g"`
+	UserID string `json:"u"`
+}
+
+// encodeThresholdCursor builds the opaque cursor for the last row of a page.
+func encodeThresholdCursor(last *domain.UserGoalProgress) string {
+	return encodeCursor(thresholdCursor{GoalID: last.GoalID, UserID: last.UserID})
+}
+
+// decodeThresholdCursor parses a cursor produced by encodeThresholdCursor.
+func decodeThresholdCursor(cursor string) (thresholdCursor, error) {
+	c, err := decodeCursor[thresholdCursor](cursor)
+	if err != nil {
+		return c, err
+	}
+	if c.GoalID == "" || c.UserID == "" {
+		return c, errors.ErrInvalidCursor("missing key fields")
+	}
+
+	return c, nil
+}
+
+// GetProgressAboveThreshold returns active in_progress rows in the namespace whose progress
+// is at least the per-goal minimum. Completed and claimed rows are excluded.
+func (r *PostgresGoalRepository) GetProgressAboveThreshold(ctx context.Context, namespace string, thresholds []GoalThreshold, limit int, cursor string) ([]*domain.UserGoalProgress, string, error) {
+	if len(thresholds) == 0 {
+		return []*domain.UserGoalProgress{}, "", nil
+	}
+
+	goalIDs := make([]string, len(thresholds))
+	minProgress := make([]int64, len(thresholds))
+	for i, th := range thresholds {
+		goalIDs[i] = th.GoalID
+		minProgress[i] = int64(th.MinProgress)
+	}
+
+	// The thresholds are joined as an UNNEST table so one query covers every goal.
+	// Its columns are renamed to keep progressColumns unambiguous.
+	query := "SELECT " + r.progressColumns() + `
+		FROM user_goal_progress
+		JOIN UNNEST($2::VARCHAR(100)[], $3::BIGINT[]) AS t(threshold_goal_id, min_progress)
+		  ON user_goal_progress.goal_id = t.threshold_goal_id
+		WHERE namespace = $1
+		  AND status = 'in_progress'
+		  AND is_active = true
+		  AND progress >= t.min_progress`
+	args := []interface{}{namespace, pq.Array(goalIDs), pq.Array(minProgress)}
+
+	if cursor != "" {
+		c, err := decodeThresholdCursor(cursor)
+		if err != nil {
+			return nil, "", err
+		}
+		query += " AND (goal_id, user_id) > ($4, $5)"
+		args = append(args, c.GoalID, c.UserID)
+	}
+
+	// Fetch one extra row to know whether another page exists
+	limit = pageLimit(limit)
+	query += " ORDER BY goal_id ASC, user_id ASC LIMIT $" + strconv.Itoa(len(args)+1)
+	args = append(args, limit+1)
+
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, "", errors.ErrDatabaseError("get progress above threshold", err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	results, err := r.scanProgressRows(rows)
+	if err != nil {
+		return nil, "", err
+	}
+
+	results, next := pageTail(results, limit, encodeThresholdCursor)
+	return results, next, nil
+}
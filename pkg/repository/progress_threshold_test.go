@@ -0,0 +1,125 @@
+package repository
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/AccelByte/extend-challenge-common/pkg/domain"
+	customerrors "github.com/AccelByte/extend-challenge-common/pkg/errors"
+)
+
+func TestDecodeThresholdCursor(t *testing.T) {
+	valid := encodeThresholdCursor(&domain.UserGoalProgress{GoalID: "goal-1", UserID: "user-1"})
+
+	c, err := decodeThresholdCursor(valid)
+	if err != nil {
+		t.Fatalf("decodeThresholdCursor failed: %v", err)
+	}
+	if c.GoalID != "goal-1" || c.UserID != "user-1" {
+		t.Errorf("Round trip = %+v", c)
+	}
+
+	for _, bad := range []string{"!!!", base64.RawURLEncoding.EncodeToString([]byte(`{"g":"goal-1"}`))} {
+		_, err := decodeThresholdCursor(bad)
+		var ce *customerrors.ChallengeError
+		if !errors.As(err, &ce) || ce.Code != customerrors.ErrCodeInvalidCursor {
+			t.Errorf("Expected ErrCodeInvalidCursor for %q, got %v", bad, err)
+		}
+	}
+}
+
+func TestPostgresGoalRepository_GetProgressAboveThreshold(t *testing.T) {
+	db := setupTestDB(t)
+	if db == nil {
+		return
+	}
+	defer cleanupTestDB(t, db)
+
+	repo := NewPostgresGoalRepository(db)
+	ctx := context.Background()
+
+	// Targets: goal-a 10, goal-b 5, goal-c 100 (thresholds at 80%)
+	thresholds := []GoalThreshold{
+		{GoalID: "goal-a", MinProgress: 8},
+		{GoalID: "goal-b", MinProgress: 4},
+		{GoalID: "goal-c", MinProgress: 80},
+	}
+
+	row := func(userID, goalID string, progress int64, status domain.GoalStatus) *domain.UserGoalProgress {
+		return &domain.UserGoalProgress{UserID: userID, GoalID: goalID, ChallengeID: "c1", Namespace: "test", Progress: progress, Status: status, IsActive: true}
+	}
+	seed := []*domain.UserGoalProgress{
+		row("u1", "goal-a", 9, domain.GoalStatusInProgress),  // match
+		row("u2", "goal-a", 7, domain.GoalStatusInProgress),  // below threshold
+		row("u3", "goal-a", 8, domain.GoalStatusInProgress),  // match (boundary)
+		row("u4", "goal-a", 10, domain.GoalStatusCompleted),  // completed
+		row("u1", "goal-b", 4, domain.GoalStatusInProgress),  // match
+		row("u2", "goal-b", 5, domain.GoalStatusClaimed),     // claimed
+		row("u5", "goal-b", 1, domain.GoalStatusInProgress),  // below threshold
+		row("u1", "goal-c", 95, domain.GoalStatusInProgress), // match
+		row("u2", "goal-c", 50, domain.GoalStatusInProgress), // below threshold
+		row("u1", "goal-d", 99, domain.GoalStatusInProgress), // no threshold
+	}
+	other := row("u9", "goal-a", 9, domain.GoalStatusInProgress)
+	other.Namespace = "other" // different namespace
+	seed = append(seed, other)
+
+	if err := repo.BulkInsert(ctx, seed); err != nil {
+		t.Fatalf("BulkInsert failed: %v", err)
+	}
+
+	want := []string{"goal-a/u1", "goal-a/u3", "goal-b/u1", "goal-c/u1"}
+
+	t.Run("single page", func(t *testing.T) {
+		page, next, err := repo.GetProgressAboveThreshold(ctx, "test", thresholds, 100, "")
+		if err != nil {
+			t.Fatalf("GetProgressAboveThreshold failed: %v", err)
+		}
+		if next != "" {
+			t.Errorf("Expected no next cursor, got %q", next)
+		}
+		assertThresholdRows(t, page, want)
+	})
+
+	t.Run("stable pagination", func(t *testing.T) {
+		var all []*domain.UserGoalProgress
+		cursor := ""
+		for pages := 0; ; pages++ {
+			if pages > len(want) {
+				t.Fatal("Pagination did not terminate")
+			}
+			page, next, err := repo.GetProgressAboveThreshold(ctx, "test", thresholds, 1, cursor)
+			if err != nil {
+				t.Fatalf("GetProgressAboveThreshold failed: %v", err)
+			}
+			all = append(all, page...)
+			if next == "" {
+				break
+			}
+			cursor = next
+		}
+		assertThresholdRows(t, all, want)
+	})
+
+	t.Run("no thresholds", func(t *testing.T) {
+		page, _, err := repo.GetProgressAboveThreshold(ctx, "test", nil, 100, "")
+		if err != nil || len(page) != 0 {
+			t.Errorf("Expected empty result, got %d rows, err %v", len(page), err)
+		}
+	})
+}
+
+func assertThresholdRows(t *testing.T, rows []*domain.UserGoalProgress, want []string) {
+	t.Helper()
+
+	got := make([]string, len(rows))
+	for i, p := range rows {
+		got[i] = p.GoalID + "/" + p.UserID
+	}
+	if fmt.Sprint(got) != fmt.Sprint(want) {
+		t.Errorf("Rows = %v, want %v", got, want)
+	}
+}
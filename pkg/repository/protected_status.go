@@ -0,0 +1,38 @@
+package repository
+
+import (
+	"strings"
+
+	"github.com/lib/pq"
+
+	"github.com/AccelByte/extend-challenge-common/pkg/domain"
+)
+
+// protectedStatuses are the statuses progress writes never overwrite: 'claimed' is final
+// and 'claiming' is held by an in-flight claim (see ReserveClaim). The write guards of the
+// upsert, increment and ceiling queries are built from this set when the package is
+// initialized, so protecting another terminal status (e.g., a future 'archived') is a
+// change to this list only. Claim transitions and the recurring reset name their statuses
+// explicitly and are not affected.
+var protectedStatuses = []domain.GoalStatus{domain.GoalStatusClaimed, domain.GoalStatusClaiming}
+
+// protectedStatusList renders statuses as a quoted SQL list, e.g. 'claimed', 'claiming'.
+func protectedStatusList(statuses []domain.GoalStatus) string {
+	quoted := make([]string, len(statuses))
+	for i, s := range statuses {
+		quoted[i] = pq.QuoteLiteral(string(s))
+	}
+	return strings.Join(quoted, ", ")
+}
+
+// protectedStatusGuard returns the predicate that keeps writes off protected rows,
+// e.g. "user_goal_progress.status NOT IN ('claimed', 'claiming')".
+func protectedStatusGuard(column string) string {
+	return column + " NOT IN (" + protectedStatusList(protectedStatuses) + ")"
+}
+
+// protectedStatusMatch returns the predicate matching protected rows, the complement of
+// protectedStatusGuard.
+func protectedStatusMatch(column string) string {
+	return column + " IN (" + protectedStatusList(protectedStatuses) + ")"
+}
@@ -0,0 +1,42 @@
+package repository
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestProtectedStatusPredicates(t *testing.T) {
+	// The guards are baked into the query strings at init, so check the built queries
+	guard := "status NOT IN ('claimed', 'claiming')"
+	if !strings.Contains(incrementRegularQuery, guard) {
+		t.Errorf("incrementRegularQuery does not contain %q", guard)
+	}
+	if match := "p.status IN ('claimed', 'claiming')"; !strings.Contains(flushPreviewQuery, match) {
+		t.Errorf("flushPreviewQuery does not contain %q", match)
+	}
+}
+
+func TestWriteQueriesGuardProtectedStatuses(t *testing.T) {
+	queries := map[string]string{
+		"batchIncrementProgressQuery":   batchIncrementProgressQuery,
+		"txBatchIncrementProgressQuery": txBatchIncrementProgressQuery,
+		"upsertProgressQuery":           upsertProgressQuery,
+		"txUpsertProgressQuery":         txUpsertProgressQuery,
+		"batchUpsertProgressQuery":      batchUpsertProgressQuery,
+		"mergeTempProgressQuery":        mergeTempProgressQuery,
+		"txMergeTempProgressQuery":      txMergeTempProgressQuery,
+		"incrementRegularQuery":         incrementRegularQuery,
+		"incrementDailyQuery":           incrementDailyQuery,
+		"txIncrementRegularQuery":       txIncrementRegularQuery,
+		"txIncrementDailyQuery":         txIncrementDailyQuery,
+		"getDailyGoalsEligibleQuery":    getDailyGoalsEligibleQuery,
+		"flushPreviewQuery":             flushPreviewQuery,
+	}
+
+	list := "(" + protectedStatusList(protectedStatuses) + ")"
+	for name, query := range queries {
+		if !strings.Contains(query, "NOT IN "+list) {
+			t.Errorf("%s does not guard the protected statuses %s", name, list)
+		}
+	}
+}
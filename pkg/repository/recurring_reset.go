@@ -0,0 +1,108 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/lib/pq"
+
+	"github.com/AccelByte/extend-challenge-common/pkg/errors"
+)
+
+// resetRecurringGoalsQuery resets the next $4 rows of the goals in $1 after the keyset
+// cursor ($2, $3), in primary key order, and returns the reset count, the batch size and
+// the last key of the batch. The row filter mirrors domain.UserGoalProgress.CanReset:
+// 'claiming' rows are never reset, 'claimed' rows only when $5 (preserveClaimed) is false.
+// Rows already at their reset state are locked but not rewritten.
+const resetRecurringGoalsQuery = `
+	WITH batch AS (
+		SELECT user_id, goal_id
+		FROM user_goal_progress
+		WHERE goal_id = ANY($1)
+		  AND (user_id, goal_id) > ($2, $3)
+		ORDER BY user_id, goal_id
+		LIMIT $4
+		FOR UPDATE
+	), reset AS (
+		UPDATE user_goal_progress
+		SET progress = 0,
+			status = 'not_started',
+			completed_at = NULL,
+			claimed_at = NULL,
+			forfeited_at = NULL,
+			updated_at = NOW()
+		FROM batch
+		WHERE user_goal_progress.user_id = batch.user_id
+		  AND user_goal_progress.goal_id = batch.goal_id
+		  AND user_goal_progress.status <> 'claiming'
+		  AND (user_goal_progress.status <> 'claimed' OR NOT $5)
+		  AND (user_goal_progress.progress <> 0
+		       OR user_goal_progress.status <> 'not_started'
+		       OR user_goal_progress.completed_at IS NOT NULL
+		       OR user_goal_progress.forfeited_at IS NOT NULL)
+		RETURNING 1
+	)
+	SELECT
+		(SELECT COUNT(*) FROM reset),
+		(SELECT COUNT(*) FROM batch),
+		COALESCE((SELECT user_id FROM batch ORDER BY user_id DESC, goal_id DESC LIMIT 1), ''),
+		COALESCE((SELECT goal_id FROM batch ORDER BY user_id DESC, goal_id DESC LIMIT 1), '')
+`
+
+// RecurringGoalResetter starts a new period for recurring goals (e.g. weekly quests that
+// keep their goal ID) across all users. It is a scheduled-job API and is only available
+// on the connection pool.
+type RecurringGoalResetter interface {
+	// ResetRecurringGoals sets progress to 0 and status to 'not_started', and clears
+	// completed_at and forfeited_at, on every row of goalIDs. Rows are reset in primary key
+	// order, batchSize rows per statement, so no statement holds more than batchSize row
+	// locks. Returns the number of rows reset; re-running is a no-op for rows that received
+	// no progress since.
+	//
+	// preserveClaimed (the usual choice) skips claimed rows so claim history is kept and the
+	// reward cannot be earned again. With preserveClaimed false, claimed rows are reset too
+	// and claimed_at is cleared, for rewards that can be earned every period. Rows reserved
+	// by an unfinished claim ('claiming') are always skipped; run the reset again after
+	// ReleaseExpiredClaimReservations to include lapsed reservations.
+	//
+	// It is safe to run while event flushes are ongoing. Each batch locks its rows, so an
+	// increment to a row either commits before the row's batch (and is cleared with the
+	// previous period's progress) or applies after it, starting from 0. The keyset cursor
+	// never revisits a reset row, so increments landing after a row's batch are kept.
+	// Claimed rows that survive the reset stay closed to increments through the existing
+	// claimed/claiming guards, and inactive rows stay closed through the is_active guard.
+	ResetRecurringGoals(ctx context.Context, goalIDs []string, preserveClaimed bool, batchSize int) (int64, error)
+}
+
+// ResetRecurringGoals resets the progress of goalIDs for all users in keyset batches.
+func (r *PostgresGoalRepository) ResetRecurringGoals(ctx context.Context, goalIDs []string, preserveClaimed bool, batchSize int) (int64, error) {
+	if batchSize <= 0 {
+		return 0, errors.ErrValidationFailed("batchSize", "must be positive")
+	}
+	if len(goalIDs) == 0 {
+		return 0, nil
+	}
+
+	if err := r.acquireGate(); err != nil {
+		return 0, err
+	}
+	defer r.releaseGate()
+
+	var (
+		total                int64
+		lastUser, lastGoalID string
+	)
+	for {
+		var reset, scanned int64
+		err := r.db.QueryRowContext(ctx, resetRecurringGoalsQuery,
+			pq.Array(goalIDs), lastUser, lastGoalID, batchSize, preserveClaimed,
+		).Scan(&reset, &scanned, &lastUser, &lastGoalID)
+		if err != nil {
+			return total, errors.ErrDatabaseError("reset recurring goals", err)
+		}
+
+		total += reset
+		if scanned < int64(batchSize) {
+			return total, nil
+		}
+	}
+}
@@ -0,0 +1,232 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/AccelByte/extend-challenge-common/pkg/domain"
+	customerrors "github.com/AccelByte/extend-challenge-common/pkg/errors"
+)
+
+// seedRecurringRows inserts a completed row per user for each goal, claiming every goal in claimed.
+func seedRecurringRows(t *testing.T, db *sql.DB, repo *PostgresGoalRepository, users, goalIDs []string, claimed ...string) {
+	t.Helper()
+	for _, userID := range users {
+		insertCompletedAgo(t, db, repo, userID, time.Hour, goalIDs...)
+		for _, goalID := range claimed {
+			if err := repo.MarkAsClaimed(context.Background(), userID, goalID); err != nil {
+				t.Fatalf("MarkAsClaimed(%s, %s) failed: %v", userID, goalID, err)
+			}
+		}
+	}
+}
+
+func TestPostgresGoalRepository_ResetRecurringGoals(t *testing.T) {
+	users := []string{"reset-user-1", "reset-user-2", "reset-user-3"}
+	goalIDs := []string{"weekly-kills", "weekly-wins"}
+
+	tests := []struct {
+		name            string
+		preserveClaimed bool
+		wantReset       int64
+		wantWinsStatus  domain.GoalStatus
+	}{
+		{name: "claimed rows preserved", preserveClaimed: true, wantReset: 3, wantWinsStatus: domain.GoalStatusClaimed},
+		{name: "repeatable rewards", preserveClaimed: false, wantReset: 6, wantWinsStatus: domain.GoalStatusNotStarted},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db := setupTestDB(t)
+			if db == nil {
+				return
+			}
+			defer cleanupTestDB(t, db)
+
+			ctx := context.Background()
+			repo := NewPostgresGoalRepository(db)
+			seedRecurringRows(t, db, repo, users, goalIDs, "weekly-wins")
+			insertCompletedAgo(t, db, repo, "reset-user-1", time.Hour, "other-goal")
+
+			reset, err := repo.ResetRecurringGoals(ctx, goalIDs, tt.preserveClaimed, 2)
+			if err != nil {
+				t.Fatalf("ResetRecurringGoals failed: %v", err)
+			}
+			if reset != tt.wantReset {
+				t.Errorf("Reset %d rows, want %d", reset, tt.wantReset)
+			}
+
+			for _, userID := range users {
+				kills := assertStatus(t, repo, userID, "weekly-kills", domain.GoalStatusNotStarted)
+				if kills.Progress != 0 || kills.CompletedAt != nil {
+					t.Errorf("%s weekly-kills = progress %d, completed_at %v, want 0/nil", userID, kills.Progress, kills.CompletedAt)
+				}
+
+				wins := assertStatus(t, repo, userID, "weekly-wins", tt.wantWinsStatus)
+				if tt.preserveClaimed && (wins.Progress != 10 || wins.ClaimedAt == nil) {
+					t.Errorf("%s weekly-wins lost its claim history: %+v", userID, wins)
+				}
+				if !tt.preserveClaimed && wins.ClaimedAt != nil {
+					t.Errorf("%s weekly-wins kept claimed_at after a repeatable reset", userID)
+				}
+			}
+
+			// Goals outside the set are untouched
+			assertStatus(t, repo, "reset-user-1", "other-goal", domain.GoalStatusCompleted)
+
+			again, err := repo.ResetRecurringGoals(ctx, goalIDs, tt.preserveClaimed, 2)
+			if err != nil {
+				t.Fatalf("ResetRecurringGoals rerun failed: %v", err)
+			}
+			if again != 0 {
+				t.Errorf("Rerun reset %d rows, want 0", again)
+			}
+		})
+	}
+}
+
+func TestPostgresGoalRepository_ResetRecurringGoals_SkipsClaimingRows(t *testing.T) {
+	db := setupTestDB(t)
+	if db == nil {
+		return
+	}
+	defer cleanupTestDB(t, db)
+
+	ctx := context.Background()
+	repo := NewPostgresGoalRepository(db)
+	insertCompletedAgo(t, db, repo, "claiming-user", time.Hour, "weekly-kills")
+	if err := repo.ReserveClaim(ctx, "claiming-user", "weekly-kills"); err != nil {
+		t.Fatalf("ReserveClaim failed: %v", err)
+	}
+
+	reset, err := repo.ResetRecurringGoals(ctx, []string{"weekly-kills"}, false, 10)
+	if err != nil {
+		t.Fatalf("ResetRecurringGoals failed: %v", err)
+	}
+	if reset != 0 {
+		t.Errorf("Reset %d rows, want 0", reset)
+	}
+	assertStatus(t, repo, "claiming-user", "weekly-kills", domain.GoalStatusClaiming)
+
+	_, err = repo.ResetRecurringGoals(ctx, []string{"weekly-kills"}, true, 0)
+	assertErrorCode(t, err, customerrors.ErrCodeValidationFailed)
+}
+
+func TestPostgresGoalRepository_ResetRecurringGoals_Batching(t *testing.T) {
+	db := setupTestDB(t)
+	if db == nil {
+		return
+	}
+	defer cleanupTestDB(t, db)
+
+	ctx := context.Background()
+	repo := NewPostgresGoalRepository(db)
+
+	const userCount = 1500
+	users := make([]string, userCount)
+	for i := range users {
+		users[i] = fmt.Sprintf("batch-user-%05d", i)
+	}
+	goalIDs := []string{"weekly-kills", "weekly-wins"}
+	var rows []*domain.UserGoalProgress
+	for _, userID := range users {
+		for _, goalID := range goalIDs {
+			rows = append(rows, &domain.UserGoalProgress{
+				UserID: userID, GoalID: goalID, ChallengeID: "weekly", Namespace: "test",
+				Progress: 3, Status: domain.GoalStatusInProgress, IsActive: true,
+			})
+		}
+	}
+	if err := repo.BulkInsertWithCOPY(ctx, rows); err != nil {
+		t.Fatalf("BulkInsertWithCOPY failed: %v", err)
+	}
+
+	// 3,000 rows in batches of 256 span a dozen statements, ending on a partial batch
+	reset, err := repo.ResetRecurringGoals(ctx, goalIDs, true, 256)
+	if err != nil {
+		t.Fatalf("ResetRecurringGoals failed: %v", err)
+	}
+	if reset != 2*userCount {
+		t.Errorf("Reset %d rows, want %d", reset, 2*userCount)
+	}
+
+	var remaining int
+	if err := db.QueryRowContext(ctx, `
+		SELECT COUNT(*) FROM user_goal_progress WHERE progress <> 0 OR status <> 'not_started'
+	`).Scan(&remaining); err != nil {
+		t.Fatalf("Count failed: %v", err)
+	}
+	if remaining != 0 {
+		t.Errorf("%d rows were not reset", remaining)
+	}
+}
+
+func TestPostgresGoalRepository_ResetRecurringGoals_ConcurrentEvents(t *testing.T) {
+	db := setupTestDB(t)
+	if db == nil {
+		return
+	}
+	defer cleanupTestDB(t, db)
+
+	ctx := context.Background()
+	repo := NewPostgresGoalRepository(db)
+	if err := repo.BulkInsert(ctx, []*domain.UserGoalProgress{
+		{UserID: "mid-a", GoalID: "weekly-kills", ChallengeID: "weekly", Namespace: "test", Progress: 7, Status: domain.GoalStatusInProgress, IsActive: true},
+		{UserID: "mid-c", GoalID: "weekly-kills", ChallengeID: "weekly", Namespace: "test", Progress: 7, Status: domain.GoalStatusInProgress, IsActive: true},
+	}); err != nil {
+		t.Fatalf("BulkInsert failed: %v", err)
+	}
+
+	// A flush holds mid-c's row lock, so the reset finishes mid-a and then waits on mid-c
+	tx, err := repo.BeginTx(ctx)
+	if err != nil {
+		t.Fatalf("BeginTx failed: %v", err)
+	}
+	defer func() { _ = tx.Rollback() }()
+	if err := tx.IncrementProgress(ctx, "mid-c", "weekly-kills", "weekly", "test", 1, 10, false); err != nil {
+		t.Fatalf("IncrementProgress in transaction failed: %v", err)
+	}
+
+	done := make(chan error, 1)
+	go func() {
+		_, err := repo.ResetRecurringGoals(ctx, []string{"weekly-kills"}, true, 1)
+		done <- err
+	}()
+
+	deadline := time.Now().Add(10 * time.Second)
+	for {
+		progress, err := repo.GetProgress(ctx, "mid-a", "weekly-kills")
+		if err != nil {
+			t.Fatalf("GetProgress failed: %v", err)
+		}
+		if progress.Progress == 0 {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("Reset never reached mid-a")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	// An event for the new period lands on the already-reset row while the reset is running
+	if err := repo.IncrementProgress(ctx, "mid-a", "weekly-kills", "weekly", "test", 2, 10, false); err != nil {
+		t.Fatalf("IncrementProgress failed: %v", err)
+	}
+	if err := tx.Commit(); err != nil {
+		t.Fatalf("Commit failed: %v", err)
+	}
+	if err := <-done; err != nil {
+		t.Fatalf("ResetRecurringGoals failed: %v", err)
+	}
+
+	// mid-a keeps the new event; mid-c's pre-reset progress (7 + 1) does not survive
+	if a := assertStatus(t, repo, "mid-a", "weekly-kills", domain.GoalStatusInProgress); a.Progress != 2 {
+		t.Errorf("mid-a progress = %d, want 2", a.Progress)
+	}
+	if c := assertStatus(t, repo, "mid-c", "weekly-kills", domain.GoalStatusNotStarted); c.Progress != 0 {
+		t.Errorf("mid-c progress = %d, want 0", c.Progress)
+	}
+}
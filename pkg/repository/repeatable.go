@@ -0,0 +1,109 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	stderrors "errors"
+	"fmt"
+
+	"github.com/AccelByte/extend-challenge-common/pkg/errors"
+)
+
+// RepeatableIncrementResult reports the effect of IncrementRepeatableProgress on a row.
+type RepeatableIncrementResult struct {
+	// NewCompletions is the number of repetitions the increment completed. The caller
+	// grants the goal's rewards once per new completion.
+	NewCompletions int
+
+	// Completions is the row's completion count after the increment.
+	Completions int
+
+	// Exhausted is true when the row has reached maxRepeats and takes no more progress.
+	Exhausted bool
+}
+
+// incrementRepeatableQuery is the repeatable increment (migration 018), UPDATE-only like
+// the pool increments. Arguments: user, goal, delta, target, max repeats (0 = unlimited).
+//
+// Every full multiple of the target in progress + delta counts a completion, up to the
+// repeats left; the remainder stays in progress and the row is re-armed to 'in_progress'.
+// A row that reaches max repeats keeps progress at the target and moves to 'claimed': its
+// rewards were handed to the caller completion by completion, so nothing is left to claim.
+// completed_at records the latest completion.
+var incrementRepeatableQuery = `
+	WITH current AS (
+		SELECT user_id, goal_id, completions, progress::BIGINT + $3::BIGINT AS total
+		FROM user_goal_progress
+		WHERE user_id = $1
+		  AND goal_id = $2
+		  AND is_active = true
+		  AND ` + protectedStatusGuard("status") + `
+		FOR UPDATE
+	), earned AS (
+		SELECT user_id, goal_id, total, completions + n AS completions_after, n,
+		       $5::INT > 0 AND completions + n >= $5::INT AS exhausted
+		FROM current
+		CROSS JOIN LATERAL (
+			SELECT CASE
+				WHEN $5::INT > 0 THEN LEAST(total / $4::BIGINT, GREATEST($5::INT - completions, 0))
+				ELSE total / $4::BIGINT
+			END::INT AS n
+		) AS counted
+	)
+	UPDATE user_goal_progress
+	SET
+		completions = e.completions_after,
+		progress = CASE WHEN e.exhausted THEN $4::BIGINT ELSE e.total - e.n * $4::BIGINT END,
+		status = CASE WHEN e.exhausted THEN 'claimed' ELSE 'in_progress' END,
+		completed_at = CASE
+			WHEN e.n > 0 THEN NOW()
+			WHEN e.exhausted THEN COALESCE(user_goal_progress.completed_at, NOW())
+			ELSE user_goal_progress.completed_at
+		END,
+		claimed_at = CASE WHEN e.exhausted THEN NOW() ELSE user_goal_progress.claimed_at END,
+		updated_at = NOW()
+	FROM earned AS e
+	WHERE user_goal_progress.user_id = e.user_id
+	  AND user_goal_progress.goal_id = e.goal_id
+	RETURNING e.n, e.completions_after, e.exhausted
+`
+
+func (e executor) incrementRepeatableProgress(ctx context.Context, userID, goalID string, delta, targetValue, maxRepeats int) (RepeatableIncrementResult, error) {
+	delta = capDelta(delta, e.repo.maxDeltaPerEvent)
+	if err := e.repo.validateIncrement(userID, goalID, delta, targetValue); err != nil {
+		return RepeatableIncrementResult{}, err
+	}
+	if delta < 0 {
+		return RepeatableIncrementResult{}, errors.ErrInvalidIncrement([]string{
+			fmt.Sprintf("%s/%s: repeatable goals cannot take a negative delta (got %d)", userID, goalID, delta),
+		})
+	}
+	if maxRepeats < 0 {
+		return RepeatableIncrementResult{}, errors.ErrInvalidIncrement([]string{
+			fmt.Sprintf("%s/%s: max repeats cannot be negative (got %d)", userID, goalID, maxRepeats),
+		})
+	}
+
+	var result RepeatableIncrementResult
+	err := e.q.QueryRowContext(ctx, incrementRepeatableQuery, userID, goalID, delta, targetValue, maxRepeats).
+		Scan(&result.NewCompletions, &result.Completions, &result.Exhausted)
+	if stderrors.Is(err, sql.ErrNoRows) {
+		return RepeatableIncrementResult{}, nil
+	}
+	if err != nil {
+		return RepeatableIncrementResult{}, errors.ErrDatabaseError(e.op("increment repeatable progress"), err)
+	}
+
+	return result, nil
+}
+
+// IncrementRepeatableProgress adds delta to a repeatable goal and counts the completions it
+// earns, carrying the overflow into the next repetition.
+func (r *PostgresGoalRepository) IncrementRepeatableProgress(ctx context.Context, userID, goalID string, delta, targetValue, maxRepeats int) (RepeatableIncrementResult, error) {
+	return r.exec().incrementRepeatableProgress(ctx, userID, goalID, delta, targetValue, maxRepeats)
+}
+
+// IncrementRepeatableProgress increments a repeatable goal within a transaction.
+func (r *PostgresTxRepository) IncrementRepeatableProgress(ctx context.Context, userID, goalID string, delta, targetValue, maxRepeats int) (RepeatableIncrementResult, error) {
+	return r.exec().incrementRepeatableProgress(ctx, userID, goalID, delta, targetValue, maxRepeats)
+}
@@ -0,0 +1,150 @@
+package repository
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/AccelByte/extend-challenge-common/pkg/domain"
+)
+
+func TestIncrementRepeatableProgress_RejectedBeforeSQL(t *testing.T) {
+	// nil *sql.DB: any SQL would panic, so passing proves validation runs first
+	repo := NewPostgresGoalRepository(nil)
+	ctx := context.Background()
+
+	tests := []struct {
+		name        string
+		delta       int
+		targetValue int
+		maxRepeats  int
+	}{
+		{"zero target value", 1, 0, 0},
+		{"negative delta", -1, 3, 0},
+		{"huge delta", DefaultMaxIncrementDelta + 1, 3, 0},
+		{"negative max repeats", 1, 3, -1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := repo.IncrementRepeatableProgress(ctx, "user1", "goal1", tt.delta, tt.targetValue, tt.maxRepeats)
+			assertInvalidIncrement(t, err)
+		})
+	}
+}
+
+func TestCompletions_OptionalColumn(t *testing.T) {
+	for _, enabled := range []bool{false, true} {
+		repo := NewPostgresGoalRepository(nil, WithCompletions(enabled), WithConfigChecksum(func() string { return "" }))
+
+		columns := repo.progressColumns()
+		if reads := strings.Contains(columns, "completions"); reads != enabled {
+			t.Errorf("WithCompletions(%v): progressColumns() reads completions = %v", enabled, reads)
+		}
+
+		progress := &domain.UserGoalProgress{}
+		dest := repo.progressScanDest(progress)
+		if got, want := len(dest), len(strings.Split(columns, ",")); got != want {
+			t.Fatalf("WithCompletions(%v): progressScanDest() has %d destinations for %d columns", enabled, got, want)
+		}
+		if enabled && dest[len(dest)-1] != &progress.Completions {
+			t.Error("completions is not scanned into the last destination")
+		}
+	}
+}
+
+func TestPostgresGoalRepository_IncrementRepeatableProgress(t *testing.T) {
+	db := setupTestDB(t)
+	if db == nil {
+		return
+	}
+	defer cleanupTestDB(t, db)
+
+	ctx := context.Background()
+	repo := NewPostgresGoalRepository(db, WithCompletions(true))
+
+	assign := func(t *testing.T, goalID string) {
+		t.Helper()
+		if err := repo.BulkInsert(ctx, []*domain.UserGoalProgress{
+			{UserID: "repeat-user", GoalID: goalID, ChallengeID: "c1", Namespace: "test", Status: domain.GoalStatusNotStarted, IsActive: true},
+		}); err != nil {
+			t.Fatalf("BulkInsert failed: %v", err)
+		}
+	}
+	increment := func(t *testing.T, goalID string, delta, maxRepeats int) RepeatableIncrementResult {
+		t.Helper()
+		result, err := repo.IncrementRepeatableProgress(ctx, "repeat-user", goalID, delta, 3, maxRepeats)
+		if err != nil {
+			t.Fatalf("IncrementRepeatableProgress failed: %v", err)
+		}
+		return result
+	}
+	assertRow := func(t *testing.T, goalID string, progress int64, completions int, status domain.GoalStatus) {
+		t.Helper()
+		row, err := repo.GetProgressRequired(ctx, "repeat-user", goalID)
+		if err != nil {
+			t.Fatalf("GetProgressRequired failed: %v", err)
+		}
+		if row.Progress != progress || row.Completions != completions || row.Status != status {
+			t.Errorf("row = progress %d, completions %d, status %s; want %d, %d, %s",
+				row.Progress, row.Completions, row.Status, progress, completions, status)
+		}
+	}
+
+	t.Run("reaching the target completes and re-arms", func(t *testing.T) {
+		assign(t, "win-3")
+
+		if got := increment(t, "win-3", 2, 0); got.NewCompletions != 0 {
+			t.Errorf("NewCompletions = %d, want 0 below the target", got.NewCompletions)
+		}
+		got := increment(t, "win-3", 2, 0)
+		if got.NewCompletions != 1 || got.Completions != 1 || got.Exhausted {
+			t.Errorf("result = %+v, want one new completion", got)
+		}
+		// 2 + 2 = 4: one completion, the extra win carries over
+		assertRow(t, "win-3", 1, 1, domain.GoalStatusInProgress)
+
+		row, _ := repo.GetProgress(ctx, "repeat-user", "win-3")
+		if row.CompletedAt == nil {
+			t.Error("CompletedAt = nil, want the completion time")
+		}
+	})
+
+	t.Run("one delta can complete several repetitions", func(t *testing.T) {
+		assign(t, "win-3-burst")
+
+		got := increment(t, "win-3-burst", 7, 0)
+		if got.NewCompletions != 2 || got.Completions != 2 {
+			t.Errorf("result = %+v, want two new completions", got)
+		}
+		assertRow(t, "win-3-burst", 1, 2, domain.GoalStatusInProgress)
+	})
+
+	t.Run("max repeats caps completions", func(t *testing.T) {
+		assign(t, "win-3-twice")
+
+		if got := increment(t, "win-3-twice", 3, 2); got.NewCompletions != 1 || got.Exhausted {
+			t.Errorf("first result = %+v, want one completion, not exhausted", got)
+		}
+		// 10 wins would be three more completions; only one repeat is left
+		got := increment(t, "win-3-twice", 10, 2)
+		if got.NewCompletions != 1 || got.Completions != 2 || !got.Exhausted {
+			t.Errorf("second result = %+v, want the last completion and exhausted", got)
+		}
+		assertRow(t, "win-3-twice", 3, 2, domain.GoalStatusClaimed)
+
+		if got := increment(t, "win-3-twice", 3, 2); got != (RepeatableIncrementResult{}) {
+			t.Errorf("result after the cap = %+v, want a no-op", got)
+		}
+		assertRow(t, "win-3-twice", 3, 2, domain.GoalStatusClaimed)
+	})
+
+	t.Run("unassigned goal is a no-op", func(t *testing.T) {
+		if got := increment(t, "never-assigned", 3, 0); got != (RepeatableIncrementResult{}) {
+			t.Errorf("result = %+v, want a no-op", got)
+		}
+		if row, _ := repo.GetProgress(ctx, "repeat-user", "never-assigned"); row != nil {
+			t.Errorf("row = %+v, want none created", row)
+		}
+	})
+}
@@ -0,0 +1,40 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/AccelByte/extend-challenge-common/pkg/errors"
+
+	"github.com/lib/pq"
+)
+
+// countUnassignedQuery counts the increment entries without an active row to update.
+// Repeated (user, goal) pairs count once per entry.
+const countUnassignedQuery = `
+	SELECT COUNT(*)
+	FROM UNNEST($1::VARCHAR(100)[], $2::VARCHAR(100)[]) AS t(user_id, goal_id)
+	WHERE NOT EXISTS (
+		SELECT 1 FROM user_goal_progress p
+		WHERE p.user_id = t.user_id
+		  AND p.goal_id = t.goal_id
+		  AND p.is_active = true
+	)
+`
+
+// countUnassigned returns how many increments an UPDATE-only statement will drop for lack
+// of an assigned (active) row. Run it with the same queryer as the increment, so per-user
+// locks (see WithPerUserLocking) cover both.
+func countUnassigned(ctx context.Context, q queryer, operation string, increments []ProgressIncrement) (int, error) {
+	userIDs := make([]string, len(increments))
+	goalIDs := make([]string, len(increments))
+	for i, inc := range increments {
+		userIDs[i] = inc.UserID
+		goalIDs[i] = inc.GoalID
+	}
+
+	var count int
+	if err := q.QueryRowContext(ctx, countUnassignedQuery, pq.Array(userIDs), pq.Array(goalIDs)).Scan(&count); err != nil {
+		return 0, errors.ErrDatabaseError(operation+" (count unassigned)", err)
+	}
+	return count, nil
+}
@@ -0,0 +1,156 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+
+	"github.com/AccelByte/extend-challenge-common/pkg/domain"
+)
+
+func TestExecutor_UpsertsIncrements(t *testing.T) {
+	tests := []struct {
+		name              string
+		inTx              bool
+		requireAssignment bool
+		want              bool
+	}{
+		{"pool, legacy", false, false, false},
+		{"pool, strict", false, true, false},
+		{"transaction, legacy", true, false, true},
+		{"transaction, strict", true, true, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := NewPostgresGoalRepository(nil, WithRequireAssignmentForIncrements(tt.requireAssignment))
+			e := executor{repo: repo}
+			if tt.inTx {
+				e.tx = &sql.Tx{}
+			}
+
+			if got := e.upsertsIncrements(); got != tt.want {
+				t.Errorf("upsertsIncrements() = %v, want %v", got, tt.want)
+			}
+
+			wantQuery := ceilingSkipsQuery(batchIncrementProgressQuery, batchDeltaParam, batchCeilingParam)
+			if tt.want {
+				wantQuery = ceilingSkipsQuery(txBatchIncrementProgressQuery, txBatchDeltaParam, txBatchCeilingParam)
+			}
+			if query, _ := e.batchIncrementStatement(false); query != wantQuery {
+				t.Error("batchIncrementStatement() chose the wrong statement")
+			}
+		})
+	}
+}
+
+func TestPostgresTxRepository_RequireAssignmentForIncrements(t *testing.T) {
+	db := setupTestDB(t)
+	if db == nil {
+		return
+	}
+	defer cleanupTestDB(t, db)
+
+	ctx := context.Background()
+	strict := NewPostgresGoalRepository(db, WithRequireAssignmentForIncrements(true))
+	legacy := NewPostgresGoalRepository(db)
+
+	// One active and one unassigned (inactive) row; "never" goals have no row at all
+	if err := legacy.BulkInsert(ctx, []*domain.UserGoalProgress{
+		{UserID: "assign-user", GoalID: "active", ChallengeID: "c1", Namespace: "test", Status: domain.GoalStatusNotStarted, IsActive: true},
+		{UserID: "assign-user", GoalID: "inactive", ChallengeID: "c1", Namespace: "test", Status: domain.GoalStatusNotStarted, IsActive: false},
+	}); err != nil {
+		t.Fatalf("BulkInsert failed: %v", err)
+	}
+
+	inTx := func(t *testing.T, repo *PostgresGoalRepository, fn func(tx TxRepository) error) {
+		t.Helper()
+		tx, err := repo.BeginTx(ctx)
+		if err != nil {
+			t.Fatalf("BeginTx failed: %v", err)
+		}
+		if err := fn(tx); err != nil {
+			_ = tx.Rollback()
+			t.Fatalf("increment failed: %v", err)
+		}
+		if err := tx.Commit(); err != nil {
+			t.Fatalf("Commit failed: %v", err)
+		}
+	}
+
+	t.Run("strict: never-assigned goal creates no row", func(t *testing.T) {
+		inTx(t, strict, func(tx TxRepository) error {
+			if err := tx.IncrementProgress(ctx, "assign-user", "never-single", "c1", "test", 3, 10, false); err != nil {
+				return err
+			}
+			if err := tx.IncrementProgress(ctx, "assign-user", "never-daily", "c1", "test", 1, 7, true); err != nil {
+				return err
+			}
+			return tx.BatchIncrementProgress(ctx, []ProgressIncrement{
+				{UserID: "assign-user", GoalID: "never-batch", ChallengeID: "c1", Namespace: "test", Delta: 2, TargetValue: 10},
+			})
+		})
+
+		for _, goalID := range []string{"never-single", "never-daily", "never-batch"} {
+			if progress, err := strict.GetProgress(ctx, "assign-user", goalID); err != nil || progress != nil {
+				t.Errorf("GetProgress(%s) = %+v, %v, want no row", goalID, progress, err)
+			}
+		}
+	})
+
+	t.Run("strict: only active rows are updated and drops are counted", func(t *testing.T) {
+		var result BatchIncrementResult
+		inTx(t, strict, func(tx TxRepository) error {
+			var err error
+			result, err = tx.BatchIncrementProgressWithResult(ctx, []ProgressIncrement{
+				{UserID: "assign-user", GoalID: "active", ChallengeID: "c1", Namespace: "test", Delta: 4, TargetValue: 10},
+				{UserID: "assign-user", GoalID: "inactive", ChallengeID: "c1", Namespace: "test", Delta: 4, TargetValue: 10},
+				{UserID: "assign-user", GoalID: "never-result", ChallengeID: "c1", Namespace: "test", Delta: 4, TargetValue: 10},
+			})
+			return err
+		})
+
+		if result.SkippedUnassigned != 2 {
+			t.Errorf("SkippedUnassigned = %d, want 2", result.SkippedUnassigned)
+		}
+		if progress, _ := strict.GetProgress(ctx, "assign-user", "active"); progress == nil || progress.Progress != 4 {
+			t.Errorf("active row = %+v, want progress 4", progress)
+		}
+		if progress, _ := strict.GetProgress(ctx, "assign-user", "inactive"); progress == nil || progress.Progress != 0 {
+			t.Errorf("inactive row = %+v, want it untouched", progress)
+		}
+		if progress, _ := strict.GetProgress(ctx, "assign-user", "never-result"); progress != nil {
+			t.Errorf("never-result row = %+v, want no row", progress)
+		}
+	})
+
+	t.Run("strict: pool batch counts drops", func(t *testing.T) {
+		result, err := strict.BatchIncrementProgressWithResult(ctx, []ProgressIncrement{
+			{UserID: "assign-user", GoalID: "never-pool", ChallengeID: "c1", Namespace: "test", Delta: 1, TargetValue: 10},
+		})
+		if err != nil {
+			t.Fatalf("BatchIncrementProgressWithResult failed: %v", err)
+		}
+		if result.SkippedUnassigned != 1 {
+			t.Errorf("SkippedUnassigned = %d, want 1", result.SkippedUnassigned)
+		}
+	})
+
+	t.Run("legacy: never-assigned goal still creates a row", func(t *testing.T) {
+		inTx(t, legacy, func(tx TxRepository) error {
+			if err := tx.IncrementProgress(ctx, "assign-user", "legacy-single", "c1", "test", 3, 10, false); err != nil {
+				return err
+			}
+			return tx.BatchIncrementProgress(ctx, []ProgressIncrement{
+				{UserID: "assign-user", GoalID: "legacy-batch", ChallengeID: "c1", Namespace: "test", Delta: 2, TargetValue: 10},
+			})
+		})
+
+		if progress, _ := legacy.GetProgress(ctx, "assign-user", "legacy-single"); progress == nil || progress.Progress != 3 {
+			t.Errorf("legacy-single row = %+v, want progress 3", progress)
+		}
+		if progress, _ := legacy.GetProgress(ctx, "assign-user", "legacy-batch"); progress == nil || progress.Progress != 2 {
+			t.Errorf("legacy-batch row = %+v, want progress 2", progress)
+		}
+	})
+}
@@ -0,0 +1,58 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/AccelByte/extend-challenge-common/pkg/errors"
+)
+
+// GrantRecord describes the reward grant intent recorded by ClaimAndRecordGrant.
+// The user and goal are taken from the claim itself.
+type GrantRecord struct {
+	Namespace  string
+	RewardType string // "ITEM" or "WALLET"
+	RewardID   string // Item code or currency code
+	Quantity   int
+}
+
+// ClaimAndRecordGrant marks a goal as claimed and records the grant intent within a transaction.
+// The claim UPDATE and the reward_grants INSERT run as one statement, so either both apply
+// or neither does, without relying on the caller to roll back.
+func (r *PostgresTxRepository) ClaimAndRecordGrant(ctx context.Context, userID, goalID string, grant GrantRecord) error {
+	query := `
+		WITH claimed AS (
+			UPDATE user_goal_progress
+			SET status = 'claimed',
+				claimed_at = NOW(),
+				updated_at = NOW()
+			WHERE user_id = $1 AND goal_id = $2
+			AND status = 'completed'
+			AND claimed_at IS NULL
+			RETURNING user_id, goal_id
+		)
+		INSERT INTO reward_grants (user_id, goal_id, namespace, reward_type, reward_id, quantity)
+		SELECT user_id, goal_id, $3, $4, $5, $6
+		FROM claimed
+	`
+
+	result, err := r.tx.ExecContext(ctx, query, userID, goalID,
+		grant.Namespace,
+		grant.RewardType,
+		grant.RewardID,
+		grant.Quantity,
+	)
+	if err != nil {
+		return errors.ErrDatabaseError("claim and record grant in transaction", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return errors.ErrDatabaseError("check rows affected", err)
+	}
+
+	if rowsAffected == 0 {
+		return errors.ErrGoalNotCompleted(goalID)
+	}
+
+	return nil
+}
@@ -0,0 +1,132 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+
+	"github.com/AccelByte/extend-challenge-common/pkg/domain"
+	customerrors "github.com/AccelByte/extend-challenge-common/pkg/errors"
+)
+
+// countGrants returns the number of reward_grants rows for a user's goal.
+func countGrants(t *testing.T, db *sql.DB, userID, goalID string) int {
+	t.Helper()
+
+	var count int
+	err := db.QueryRow(`SELECT COUNT(*) FROM reward_grants WHERE user_id = $1 AND goal_id = $2`, userID, goalID).Scan(&count)
+	if err != nil {
+		t.Fatalf("Count grants failed: %v", err)
+	}
+	return count
+}
+
+func TestPostgresTxRepository_ClaimAndRecordGrant(t *testing.T) {
+	db := setupTestDB(t)
+	if db == nil {
+		return
+	}
+	defer cleanupTestDB(t, db)
+
+	repo := NewPostgresGoalRepository(db)
+	ctx := context.Background()
+
+	grant := GrantRecord{Namespace: "test", RewardType: "ITEM", RewardID: "winter_sword", Quantity: 1}
+
+	err := repo.BulkInsert(ctx, []*domain.UserGoalProgress{
+		{UserID: "grant-user", GoalID: "goal-done", ChallengeID: "challenge1", Namespace: "test", Progress: 10, Status: domain.GoalStatusCompleted, IsActive: true},
+		{UserID: "grant-user", GoalID: "goal-open", ChallengeID: "challenge1", Namespace: "test", Progress: 3, Status: domain.GoalStatusInProgress, IsActive: true},
+		{UserID: "grant-user", GoalID: "goal-bad-grant", ChallengeID: "challenge1", Namespace: "test", Progress: 10, Status: domain.GoalStatusCompleted, IsActive: true},
+	})
+	if err != nil {
+		t.Fatalf("BulkInsert failed: %v", err)
+	}
+	_, err = db.Exec(`UPDATE user_goal_progress SET completed_at = NOW() WHERE status = 'completed'`)
+	if err != nil {
+		t.Fatalf("Set completed_at failed: %v", err)
+	}
+
+	t.Run("claims and records grant atomically", func(t *testing.T) {
+		tx, err := repo.BeginTx(ctx)
+		if err != nil {
+			t.Fatalf("BeginTx failed: %v", err)
+		}
+
+		if err := tx.ClaimAndRecordGrant(ctx, "grant-user", "goal-done", grant); err != nil {
+			_ = tx.Rollback()
+			t.Fatalf("ClaimAndRecordGrant failed: %v", err)
+		}
+		if err := tx.Commit(); err != nil {
+			t.Fatalf("Commit failed: %v", err)
+		}
+
+		p, _ := repo.GetProgress(ctx, "grant-user", "goal-done")
+		if p == nil || p.Status != domain.GoalStatusClaimed || p.ClaimedAt == nil {
+			t.Errorf("Expected goal to be claimed, got %+v", p)
+		}
+		if got := countGrants(t, db, "grant-user", "goal-done"); got != 1 {
+			t.Errorf("Expected 1 grant record, got %d", got)
+		}
+	})
+
+	t.Run("already claimed records nothing", func(t *testing.T) {
+		tx, err := repo.BeginTx(ctx)
+		if err != nil {
+			t.Fatalf("BeginTx failed: %v", err)
+		}
+		defer func() { _ = tx.Rollback() }()
+
+		err = tx.ClaimAndRecordGrant(ctx, "grant-user", "goal-done", grant)
+
+		var ce *customerrors.ChallengeError
+		if !errors.As(err, &ce) || ce.Code != customerrors.ErrCodeGoalNotCompleted {
+			t.Errorf("Expected GOAL_NOT_COMPLETED error, got %v", err)
+		}
+		if got := countGrants(t, db, "grant-user", "goal-done"); got != 1 {
+			t.Errorf("Expected grant count to stay at 1, got %d", got)
+		}
+	})
+
+	t.Run("incomplete goal records nothing", func(t *testing.T) {
+		tx, err := repo.BeginTx(ctx)
+		if err != nil {
+			t.Fatalf("BeginTx failed: %v", err)
+		}
+		defer func() { _ = tx.Rollback() }()
+
+		err = tx.ClaimAndRecordGrant(ctx, "grant-user", "goal-open", grant)
+
+		var ce *customerrors.ChallengeError
+		if !errors.As(err, &ce) || ce.Code != customerrors.ErrCodeGoalNotCompleted {
+			t.Errorf("Expected GOAL_NOT_COMPLETED error, got %v", err)
+		}
+	})
+
+	t.Run("grant insert failure leaves goal unclaimed", func(t *testing.T) {
+		tx, err := repo.BeginTx(ctx)
+		if err != nil {
+			t.Fatalf("BeginTx failed: %v", err)
+		}
+
+		// Quantity 0 violates check_grant_quantity_positive
+		badGrant := grant
+		badGrant.Quantity = 0
+
+		err = tx.ClaimAndRecordGrant(ctx, "grant-user", "goal-bad-grant", badGrant)
+		_ = tx.Rollback()
+
+		var ce *customerrors.ChallengeError
+		if !errors.As(err, &ce) || ce.Code != customerrors.ErrCodeDatabaseError {
+			t.Fatalf("Expected DATABASE_ERROR, got %v", err)
+		}
+
+		p, _ := repo.GetProgress(ctx, "grant-user", "goal-bad-grant")
+		if p == nil || p.Status != domain.GoalStatusCompleted || p.ClaimedAt != nil {
+			t.Errorf("Expected goal to remain completed and unclaimed, got %+v", p)
+		}
+		if got := countGrants(t, db, "grant-user", "goal-bad-grant"); got != 0 {
+			t.Errorf("Expected no grant record, got %d", got)
+		}
+	})
+}
@@ -0,0 +1,108 @@
+package repository
+
+import (
+	"sort"
+
+	"github.com/AccelByte/extend-challenge-common/pkg/domain"
+)
+
+// DeltaKind classifies a row in a snapshot diff.
+type DeltaKind string
+
+const (
+	// DeltaAdded is a row present only in the newer snapshot (first progress on a goal).
+	DeltaAdded DeltaKind = "added"
+
+	// DeltaRemoved is a row present only in the older snapshot (e.g., deleted challenge progress).
+	DeltaRemoved DeltaKind = "removed"
+
+	// DeltaChanged is a row in both snapshots whose progress or status differs.
+	DeltaChanged DeltaKind = "changed"
+)
+
+// ProgressDelta describes how one (user, goal) row moved between two snapshots.
+// For added rows the old fields are zero ("" status, 0 progress); for removed rows the
+// new fields are.
+type ProgressDelta struct {
+	UserID      string
+	GoalID      string
+	ChallengeID string
+	Kind        DeltaKind
+
+	OldProgress   int64
+	NewProgress   int64
+	DeltaProgress int64 // NewProgress - OldProgress; negative after a reset
+
+	OldStatus domain.GoalStatus
+	NewStatus domain.GoalStatus
+}
+
+// StatusChanged reports whether the row moved to a different status.
+func (d ProgressDelta) StatusChanged() bool {
+	return d.OldStatus != d.NewStatus
+}
+
+// snapshotKey identifies a row across snapshots.
+type snapshotKey struct {
+	userID string
+	goalID string
+}
+
+// DiffSnapshots compares two full snapshots of progress rows (e.g., periodic exports taken
+// with IterateProgress) and returns the rows that were added, removed, or changed in
+// progress or status, ordered by (user_id, goal_id). Rows are
+// matched by (user_id, goal_id); if a snapshot holds a key twice, the later row wins.
+// Rows that did not change are omitted. Nil rows are ignored.
+func DiffSnapshots(older, newer []*domain.UserGoalProgress) []ProgressDelta {
+	oldRows := indexSnapshot(older)
+	newRows := indexSnapshot(newer)
+
+	deltas := make([]ProgressDelta, 0)
+	for key, n := range newRows {
+		o, ok := oldRows[key]
+		if !ok {
+			deltas = append(deltas, ProgressDelta{
+				UserID: n.UserID, GoalID: n.GoalID, ChallengeID: n.ChallengeID, Kind: DeltaAdded,
+				NewProgress: n.Progress, DeltaProgress: n.Progress, NewStatus: n.Status,
+			})
+			continue
+		}
+		if o.Progress == n.Progress && o.Status == n.Status {
+			continue
+		}
+		deltas = append(deltas, ProgressDelta{
+			UserID: n.UserID, GoalID: n.GoalID, ChallengeID: n.ChallengeID, Kind: DeltaChanged,
+			OldProgress: o.Progress, NewProgress: n.Progress, DeltaProgress: n.Progress - o.Progress,
+			OldStatus: o.Status, NewStatus: n.Status,
+		})
+	}
+	for key, o := range oldRows {
+		if _, ok := newRows[key]; ok {
+			continue
+		}
+		deltas = append(deltas, ProgressDelta{
+			UserID: o.UserID, GoalID: o.GoalID, ChallengeID: o.ChallengeID, Kind: DeltaRemoved,
+			OldProgress: o.Progress, DeltaProgress: -o.Progress, OldStatus: o.Status,
+		})
+	}
+
+	sort.Slice(deltas, func(i, j int) bool {
+		if deltas[i].UserID != deltas[j].UserID {
+			return deltas[i].UserID < deltas[j].UserID
+		}
+		return deltas[i].GoalID < deltas[j].GoalID
+	})
+
+	return deltas
+}
+
+// indexSnapshot maps a snapshot's rows by (user_id, goal_id).
+func indexSnapshot(rows []*domain.UserGoalProgress) map[snapshotKey]*domain.UserGoalProgress {
+	index := make(map[snapshotKey]*domain.UserGoalProgress, len(rows))
+	for _, row := range rows {
+		if row != nil {
+			index[snapshotKey{userID: row.UserID, goalID: row.GoalID}] = row
+		}
+	}
+	return index
+}
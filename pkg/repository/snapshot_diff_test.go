@@ -0,0 +1,59 @@
+package repository
+
+import (
+	"testing"
+
+	"github.com/AccelByte/extend-challenge-common/pkg/domain"
+)
+
+func TestDiffSnapshots(t *testing.T) {
+	row := func(userID, goalID string, progress int64, status domain.GoalStatus) *domain.UserGoalProgress {
+		return &domain.UserGoalProgress{UserID: userID, GoalID: goalID, ChallengeID: "c1", Progress: progress, Status: status}
+	}
+
+	older := []*domain.UserGoalProgress{
+		row("user-1", "kills", 3, domain.GoalStatusInProgress),
+		row("user-1", "wins", 10, domain.GoalStatusCompleted),
+		row("user-2", "kills", 5, domain.GoalStatusInProgress),
+		row("user-2", "retired", 2, domain.GoalStatusInProgress),
+		nil,
+	}
+	newer := []*domain.UserGoalProgress{
+		row("user-2", "kills", 5, domain.GoalStatusInProgress), // unchanged
+		row("user-1", "wins", 10, domain.GoalStatusClaimed),
+		row("user-1", "kills", 8, domain.GoalStatusInProgress),
+		row("user-3", "kills", 1, domain.GoalStatusInProgress),
+	}
+
+	want := []ProgressDelta{
+		{UserID: "user-1", GoalID: "kills", ChallengeID: "c1", Kind: DeltaChanged, OldProgress: 3, NewProgress: 8, DeltaProgress: 5, OldStatus: domain.GoalStatusInProgress, NewStatus: domain.GoalStatusInProgress},
+		{UserID: "user-1", GoalID: "wins", ChallengeID: "c1", Kind: DeltaChanged, OldProgress: 10, NewProgress: 10, OldStatus: domain.GoalStatusCompleted, NewStatus: domain.GoalStatusClaimed},
+		{UserID: "user-2", GoalID: "retired", ChallengeID: "c1", Kind: DeltaRemoved, OldProgress: 2, DeltaProgress: -2, OldStatus: domain.GoalStatusInProgress},
+		{UserID: "user-3", GoalID: "kills", ChallengeID: "c1", Kind: DeltaAdded, NewProgress: 1, DeltaProgress: 1, NewStatus: domain.GoalStatusInProgress},
+	}
+
+	got := DiffSnapshots(older, newer)
+	if len(got) != len(want) {
+		t.Fatalf("DiffSnapshots() returned %d deltas, want %d: %+v", len(got), len(want), got)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("delta %d = %+v, want %+v", i, got[i], want[i])
+		}
+	}
+
+	if got[0].StatusChanged() || !got[1].StatusChanged() {
+		t.Errorf("StatusChanged() = %v/%v, want false/true", got[0].StatusChanged(), got[1].StatusChanged())
+	}
+}
+
+func TestDiffSnapshots_Empty(t *testing.T) {
+	if got := DiffSnapshots(nil, nil); got == nil || len(got) != 0 {
+		t.Errorf("DiffSnapshots(nil, nil) = %v, want empty slice", got)
+	}
+
+	rows := []*domain.UserGoalProgress{{UserID: "user-1", GoalID: "kills", Progress: 4}}
+	if got := DiffSnapshots(rows, rows); len(got) != 0 {
+		t.Errorf("DiffSnapshots(same, same) = %+v, want no deltas", got)
+	}
+}
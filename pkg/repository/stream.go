@@ -0,0 +1,104 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	stderrors "errors"
+
+	"github.com/AccelByte/extend-challenge-common/pkg/domain"
+	"github.com/AccelByte/extend-challenge-common/pkg/errors"
+)
+
+// ErrStopIteration is returned by a stream callback to stop the scan early. The Stream
+// methods close the result set and return nil instead of the sentinel.
+var ErrStopIteration = stderrors.New("stop iteration")
+
+// StreamOption configures StreamUserProgress and StreamChallengeProgress.
+type StreamOption func(*streamOptions)
+
+type streamOptions struct {
+	reuseRow bool
+}
+
+// WithReusedRow scans every row into the same UserGoalProgress instead of allocating one
+// per row, so a stream over thousands of rows allocates once.
+//
+// The pointer passed to the callback is only valid until the callback returns: the next
+// row overwrites it. Callers that keep a row (append it to a slice, send it on a channel,
+// store it in a map) must copy the struct value first; a value copy is enough, since the
+// struct is cleared before each row and the timestamps of earlier rows are not reused.
+func WithReusedRow() StreamOption {
+	return func(o *streamOptions) {
+		o.reuseRow = true
+	}
+}
+
+// streamProgressRows scans rows of progressColumns and calls fn for each. It stops at
+// the first error from fn, returning nil for ErrStopIteration and the error otherwise.
+// The caller closes rows.
+func (r *PostgresGoalRepository) streamProgressRows(rows *sql.Rows, fn func(*domain.UserGoalProgress) error, opts ...StreamOption) error {
+	var o streamOptions
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	var shared domain.UserGoalProgress
+	for rows.Next() {
+		progress := &shared
+		if o.reuseRow {
+			shared = domain.UserGoalProgress{}
+		} else {
+			progress = &domain.UserGoalProgress{}
+		}
+
+		if err := rows.Scan(r.progressScanDest(progress)...); err != nil {
+			return errors.ErrDatabaseError("scan progress row", err)
+		}
+		if err := fn(progress); err != nil {
+			if stderrors.Is(err, ErrStopIteration) {
+				return nil
+			}
+			return err
+		}
+	}
+
+	if err := rows.Err(); err != nil {
+		return errors.ErrDatabaseError("iterate progress rows", err)
+	}
+
+	return nil
+}
+
+// streamProgress runs a SELECT of progressColumns and calls fn for each row.
+// Returning early closes the rows, which releases the connection to the pool.
+func (e executor) streamProgress(ctx context.Context, operation, query string, args []interface{}, fn func(*domain.UserGoalProgress) error, opts []StreamOption) error {
+	rows, err := e.q.QueryContext(ctx, query, args...)
+	if err != nil {
+		return errors.ErrDatabaseError(operation, err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	return e.repo.streamProgressRows(rows, fn, opts...)
+}
+
+// StreamUserProgress calls fn for each of a user's progress rows in the order of
+// GetUserProgress (order_index, then created_at), without holding them all in memory.
+func (r *PostgresGoalRepository) StreamUserProgress(ctx context.Context, userID string, activeOnly bool, fn func(*domain.UserGoalProgress) error, opts ...StreamOption) error {
+	return r.exec().streamUserProgress(ctx, userID, activeOnly, fn, opts)
+}
+
+// StreamChallengeProgress calls fn for each of a user's progress rows in a challenge,
+// the rows GetChallengeProgress returns, without holding them all in memory.
+func (r *PostgresGoalRepository) StreamChallengeProgress(ctx context.Context, userID, challengeID string, activeOnly bool, fn func(*domain.UserGoalProgress) error, opts ...StreamOption) error {
+	return r.exec().streamChallengeProgress(ctx, userID, challengeID, activeOnly, fn, opts)
+}
+
+// StreamUserProgress streams a user's progress rows within a transaction.
+func (r *PostgresTxRepository) StreamUserProgress(ctx context.Context, userID string, activeOnly bool, fn func(*domain.UserGoalProgress) error, opts ...StreamOption) error {
+	return r.exec().streamUserProgress(ctx, userID, activeOnly, fn, opts)
+}
+
+// StreamChallengeProgress streams a user's progress rows in a challenge within a transaction.
+func (r *PostgresTxRepository) StreamChallengeProgress(ctx context.Context, userID, challengeID string, activeOnly bool, fn func(*domain.UserGoalProgress) error, opts ...StreamOption) error {
+	return r.exec().streamChallengeProgress(ctx, userID, challengeID, activeOnly, fn, opts)
+}
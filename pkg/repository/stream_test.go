@@ -0,0 +1,151 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"testing"
+
+	"github.com/AccelByte/extend-challenge-common/pkg/domain"
+)
+
+func TestPostgresGoalRepository_StreamProgress(t *testing.T) {
+	db := setupTestDB(t)
+	if db == nil {
+		return
+	}
+	defer cleanupTestDB(t, db)
+
+	repo := NewPostgresGoalRepository(db)
+	ctx := context.Background()
+
+	var seed []*domain.UserGoalProgress
+	for i := 0; i < 6; i++ {
+		seed = append(seed, &domain.UserGoalProgress{
+			UserID: "stream-user", GoalID: fmt.Sprintf("goal-%d", i), ChallengeID: fmt.Sprintf("c%d", i%2), Namespace: "test",
+			Progress: int64(i), Status: domain.GoalStatusInProgress, IsActive: i != 5,
+		})
+	}
+	if err := repo.BulkInsert(ctx, seed); err != nil {
+		t.Fatalf("BulkInsert failed: %v", err)
+	}
+
+	assertNoConnectionsInUse := func(t *testing.T) {
+		t.Helper()
+		if inUse := db.Stats().InUse; inUse != 0 {
+			t.Errorf("db.Stats().InUse = %d, want 0 (rows left open)", inUse)
+		}
+	}
+
+	t.Run("stop iteration after N rows", func(t *testing.T) {
+		seen := 0
+		err := repo.StreamUserProgress(ctx, "stream-user", false, func(*domain.UserGoalProgress) error {
+			seen++
+			if seen == 2 {
+				return ErrStopIteration
+			}
+			return nil
+		})
+		if err != nil {
+			t.Fatalf("StreamUserProgress failed: %v", err)
+		}
+		if seen != 2 {
+			t.Errorf("callback called %d times, want 2", seen)
+		}
+		assertNoConnectionsInUse(t)
+	})
+
+	t.Run("callback error is returned", func(t *testing.T) {
+		boom := errors.New("export sink closed")
+		seen := 0
+		err := repo.StreamChallengeProgress(ctx, "stream-user", "c0", false, func(*domain.UserGoalProgress) error {
+			seen++
+			return boom
+		})
+		if !errors.Is(err, boom) {
+			t.Errorf("StreamChallengeProgress error = %v, want %v", err, boom)
+		}
+		if seen != 1 {
+			t.Errorf("callback called %d times, want 1", seen)
+		}
+		assertNoConnectionsInUse(t)
+	})
+
+	t.Run("matches the slice variants", func(t *testing.T) {
+		for _, activeOnly := range []bool{false, true} {
+			want, err := repo.GetUserProgress(ctx, "stream-user", activeOnly)
+			if err != nil {
+				t.Fatalf("GetUserProgress failed: %v", err)
+			}
+			var got []*domain.UserGoalProgress
+			if err := repo.StreamUserProgress(ctx, "stream-user", activeOnly, collectProgress(&got)); err != nil {
+				t.Fatalf("StreamUserProgress failed: %v", err)
+			}
+			if !reflect.DeepEqual(got, want) {
+				t.Errorf("activeOnly=%v: streamed %d rows differ from GetUserProgress's %d", activeOnly, len(got), len(want))
+			}
+
+			wantChallenge, err := repo.GetChallengeProgress(ctx, "stream-user", "c1", activeOnly)
+			if err != nil {
+				t.Fatalf("GetChallengeProgress failed: %v", err)
+			}
+			var gotChallenge []*domain.UserGoalProgress
+			if err := repo.StreamChallengeProgress(ctx, "stream-user", "c1", activeOnly, collectProgress(&gotChallenge)); err != nil {
+				t.Fatalf("StreamChallengeProgress failed: %v", err)
+			}
+			if !reflect.DeepEqual(gotChallenge, wantChallenge) {
+				t.Errorf("activeOnly=%v: streamed challenge rows differ from GetChallengeProgress", activeOnly)
+			}
+		}
+	})
+
+	t.Run("reused row", func(t *testing.T) {
+		want, err := repo.GetUserProgress(ctx, "stream-user", false)
+		if err != nil {
+			t.Fatalf("GetUserProgress failed: %v", err)
+		}
+
+		var pointers []*domain.UserGoalProgress
+		var copies []domain.UserGoalProgress
+		err = repo.StreamUserProgress(ctx, "stream-user", false, func(p *domain.UserGoalProgress) error {
+			pointers = append(pointers, p)
+			copies = append(copies, *p)
+			return nil
+		}, WithReusedRow())
+		if err != nil {
+			t.Fatalf("StreamUserProgress failed: %v", err)
+		}
+
+		if len(copies) != len(want) {
+			t.Fatalf("streamed %d rows, want %d", len(copies), len(want))
+		}
+		for i := range copies {
+			if !reflect.DeepEqual(copies[i], *want[i]) {
+				t.Errorf("row %d = %+v, want %+v", i, copies[i], *want[i])
+			}
+			if pointers[i] != pointers[0] {
+				t.Errorf("row %d was scanned into a new struct", i)
+			}
+		}
+	})
+
+	t.Run("in transaction", func(t *testing.T) {
+		tx, err := repo.BeginTx(ctx)
+		if err != nil {
+			t.Fatalf("BeginTx failed: %v", err)
+		}
+		defer func() { _ = tx.Rollback() }()
+
+		seen := 0
+		if err := tx.StreamUserProgress(ctx, "stream-user", true, func(*domain.UserGoalProgress) error {
+			seen++
+			return nil
+		}); err != nil {
+			t.Fatalf("StreamUserProgress in transaction failed: %v", err)
+		}
+		if seen != 5 {
+			t.Errorf("streamed %d active rows, want 5", seen)
+		}
+	})
+}
@@ -0,0 +1,93 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/AccelByte/extend-challenge-common/pkg/domain"
+	"github.com/AccelByte/extend-challenge-common/pkg/errors"
+
+	"github.com/lib/pq"
+)
+
+// lockUsersQuery takes one transaction-scoped advisory lock per distinct user.
+// Locks are acquired in ascending key order so two batches with overlapping users
+// cannot deadlock. Keys are ordered by hash (not user ID) because distinct users can
+// share a hashtext() value; ordering by user ID could then acquire the same keys in
+// different orders.
+const lockUsersQuery = `
+	SELECT pg_advisory_xact_lock(k)
+	FROM (
+		SELECT DISTINCT hashtext(u) AS k
+		FROM UNNEST($1::TEXT[]) AS u
+		ORDER BY k
+	) AS keys
+`
+
+// lockUsers serializes this transaction against other batches touching the same users.
+// No-op unless WithPerUserLocking is set. q must be a transaction: the locks are
+// released when it commits or rolls back.
+func (r *PostgresGoalRepository) lockUsers(ctx context.Context, q queryer, userIDs []string) error {
+	if !r.perUserLocking || len(userIDs) == 0 {
+		return nil
+	}
+
+	rows, err := q.QueryContext(ctx, lockUsersQuery, pq.Array(userIDs))
+	if err != nil {
+		return err
+	}
+	defer func() { _ = rows.Close() }()
+
+	// Drain the result so every lock is acquired before returning
+	for rows.Next() {
+	}
+	return rows.Err()
+}
+
+// withUserLocks runs fn on the pool, or inside a short transaction holding the per-user
+// locks when WithPerUserLocking is set. Used by pool methods that otherwise run as a
+// single auto-committed statement. Errors from fn are returned unchanged.
+func (r *PostgresGoalRepository) withUserLocks(ctx context.Context, userIDs []string, fn func(q queryer) error) (err error) {
+	if !r.perUserLocking {
+		return fn(r.db)
+	}
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return errors.ErrDatabaseError("begin transaction for user locks", err)
+	}
+	defer func() {
+		if err != nil {
+			_ = tx.Rollback()
+		}
+	}()
+
+	if err = r.lockUsers(ctx, tx, userIDs); err != nil {
+		return errors.ErrDatabaseError("lock users", err)
+	}
+	if err = fn(tx); err != nil {
+		return err
+	}
+
+	if err = tx.Commit(); err != nil {
+		return errors.ErrDatabaseError("commit user lock transaction", err)
+	}
+	return nil
+}
+
+// incrementUserIDs returns the user IDs of a batch of increments (duplicates allowed).
+func incrementUserIDs(increments []ProgressIncrement) []string {
+	userIDs := make([]string, len(increments))
+	for i, inc := range increments {
+		userIDs[i] = inc.UserID
+	}
+	return userIDs
+}
+
+// progressUserIDs returns the user IDs of a batch of progress rows (duplicates allowed).
+func progressUserIDs(progresses []*domain.UserGoalProgress) []string {
+	userIDs := make([]string, len(progresses))
+	for i, p := range progresses {
+		userIDs[i] = p.UserID
+	}
+	return userIDs
+}
@@ -0,0 +1,154 @@
+package repository
+
+import (
+	"context"
+	"sync"
+	"testing"
+
+	"github.com/AccelByte/extend-challenge-common/pkg/domain"
+)
+
+func TestWithUserLocks_DisabledRunsOnPool(t *testing.T) {
+	// db is nil: with locking disabled no transaction may be started
+	repo := NewPostgresGoalRepository(nil)
+
+	called := false
+	err := repo.withUserLocks(context.Background(), []string{"user-1"}, func(q queryer) error {
+		called = true
+		return nil
+	})
+
+	if err != nil {
+		t.Errorf("Expected no error, got %v", err)
+	}
+	if !called {
+		t.Error("Expected fn to be called")
+	}
+}
+
+func TestPostgresGoalRepository_PerUserLocking_Stress(t *testing.T) {
+	db := setupTestDB(t)
+	if db == nil {
+		return
+	}
+	defer cleanupTestDB(t, db)
+
+	repo := NewPostgresGoalRepository(db, WithPerUserLocking(true))
+	ctx := context.Background()
+
+	// "login" is increment-managed, "kills" is snapshot-managed (absolute stat)
+	err := repo.BulkInsert(ctx, []*domain.UserGoalProgress{
+		{UserID: "lock-user", GoalID: "login", ChallengeID: "c1", Namespace: "test", Status: domain.GoalStatusNotStarted, IsActive: true},
+		{UserID: "lock-user", GoalID: "kills", ChallengeID: "c1", Namespace: "test", Status: domain.GoalStatusNotStarted, IsActive: true},
+	})
+	if err != nil {
+		t.Fatalf("BulkInsert failed: %v", err)
+	}
+
+	const iterations = 50
+	var wg sync.WaitGroup
+	errs := make(chan error, 2*iterations)
+
+	wg.Add(2)
+	go func() {
+		defer wg.Done()
+		for i := 0; i < iterations; i++ {
+			errs <- repo.BatchIncrementProgress(ctx, []ProgressIncrement{
+				{UserID: "lock-user", GoalID: "login", ChallengeID: "c1", Namespace: "test", Delta: 1, TargetValue: 1000},
+			})
+		}
+	}()
+	go func() {
+		defer wg.Done()
+		for i := 1; i <= iterations; i++ {
+			errs <- repo.BatchUpsertProgressWithCOPY(ctx, []*domain.UserGoalProgress{
+				{UserID: "lock-user", GoalID: "kills", ChallengeID: "c1", Namespace: "test", Progress: int64(i), Status: domain.GoalStatusInProgress},
+			})
+		}
+	}()
+	wg.Wait()
+	close(errs)
+
+	for err := range errs {
+		if err != nil {
+			t.Fatalf("Concurrent flush failed: %v", err)
+		}
+	}
+
+	login, err := repo.GetProgress(ctx, "lock-user", "login")
+	if err != nil {
+		t.Fatalf("GetProgress failed: %v", err)
+	}
+	if login.Progress != iterations {
+		t.Errorf("Expected login progress %d (sum of increments), got %d", iterations, login.Progress)
+	}
+
+	kills, err := repo.GetProgress(ctx, "lock-user", "kills")
+	if err != nil {
+		t.Fatalf("GetProgress failed: %v", err)
+	}
+	if kills.Progress != iterations {
+		t.Errorf("Expected kills progress %d (last snapshot), got %d", iterations, kills.Progress)
+	}
+}
+
+func TestPostgresGoalRepository_PerUserLocking_NoDeadlock(t *testing.T) {
+	db := setupTestDB(t)
+	if db == nil {
+		return
+	}
+	defer cleanupTestDB(t, db)
+
+	repo := NewPostgresGoalRepository(db, WithPerUserLocking(true))
+	ctx := context.Background()
+
+	users := []string{"lock-a", "lock-b", "lock-c"}
+	var seed []*domain.UserGoalProgress
+	for _, u := range users {
+		seed = append(seed, &domain.UserGoalProgress{UserID: u, GoalID: "goal", ChallengeID: "c1", Namespace: "test", Status: domain.GoalStatusNotStarted, IsActive: true})
+	}
+	if err := repo.BulkInsert(ctx, seed); err != nil {
+		t.Fatalf("BulkInsert failed: %v", err)
+	}
+
+	// Overlapping user sets in opposite orders must not deadlock
+	batch := func(order []string) []ProgressIncrement {
+		increments := make([]ProgressIncrement, len(order))
+		for i, u := range order {
+			increments[i] = ProgressIncrement{UserID: u, GoalID: "goal", ChallengeID: "c1", Namespace: "test", Delta: 1, TargetValue: 1000}
+		}
+		return increments
+	}
+
+	const iterations = 25
+	var wg sync.WaitGroup
+	errs := make(chan error, 2*iterations)
+
+	for _, order := range [][]string{{"lock-a", "lock-b", "lock-c"}, {"lock-c", "lock-b", "lock-a"}} {
+		wg.Add(1)
+		go func(increments []ProgressIncrement) {
+			defer wg.Done()
+			for i := 0; i < iterations; i++ {
+				errs <- repo.BatchIncrementProgress(ctx, increments)
+			}
+		}(batch(order))
+	}
+	wg.Wait()
+	close(errs)
+
+	for err := range errs {
+		if err != nil {
+			t.Fatalf("Concurrent batch failed: %v", err)
+		}
+	}
+
+	for _, u := range users {
+		p, err := repo.GetProgress(ctx, u, "goal")
+		if err != nil {
+			t.Fatalf("GetProgress failed: %v", err)
+		}
+		if p.Progress != 2*iterations {
+			t.Errorf("Expected %s progress %d, got %d", u, 2*iterations, p.Progress)
+		}
+	}
+}
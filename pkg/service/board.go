@@ -0,0 +1,68 @@
+package service
+
+import (
+	"context"
+
+	"github.com/AccelByte/extend-challenge-common/pkg/domain"
+)
+
+// Board is a user's view of every configured challenge, in config order.
+type Board struct {
+	Challenges []*ChallengeBoard
+}
+
+// ChallengeBoard is one challenge on a Board.
+type ChallengeBoard struct {
+	Challenge *domain.Challenge
+	Goals     []*GoalBoard
+}
+
+// GoalBoard pairs a goal definition with the user's progress on it.
+type GoalBoard struct {
+	Goal *domain.Goal
+
+	// Progress is the user's progress row, or nil if the user has none
+	Progress *domain.UserGoalProgress
+
+	// Status is the progress status, or not_started when Progress is nil
+	Status domain.GoalStatus
+
+	// Claimable is true when the reward can be claimed now (see UserGoalProgress.CanClaim)
+	Claimable bool
+}
+
+// GetBoard returns every challenge with the user's progress on each goal. Disabled goals
+// are included only when the user already has progress on them.
+func (s *Service) GetBoard(ctx context.Context, userID string) (*Board, error) {
+	rows, err := s.repo.GetUserProgress(ctx, userID, false)
+	if err != nil {
+		return nil, err
+	}
+
+	progressByGoal := make(map[string]*domain.UserGoalProgress, len(rows))
+	for _, row := range rows {
+		progressByGoal[row.GoalID] = row
+	}
+
+	challenges := s.goals.GetAllChallenges()
+	board := &Board{Challenges: make([]*ChallengeBoard, 0, len(challenges))}
+	for _, challenge := range challenges {
+		entry := &ChallengeBoard{Challenge: challenge, Goals: make([]*GoalBoard, 0, len(challenge.Goals))}
+		for _, goal := range challenge.Goals {
+			progress := progressByGoal[goal.ID]
+			if progress == nil && !goal.IsEnabled() {
+				continue
+			}
+
+			goalEntry := &GoalBoard{Goal: goal, Progress: progress, Status: domain.GoalStatusNotStarted}
+			if progress != nil {
+				goalEntry.Status = progress.Status
+				goalEntry.Claimable = progress.CanClaim()
+			}
+			entry.Goals = append(entry.Goals, goalEntry)
+		}
+		board.Challenges = append(board.Challenges, entry)
+	}
+
+	return board, nil
+}
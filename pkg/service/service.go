@@ -0,0 +1,119 @@
+// Package service is the high-level entry point for challenge consumers.
+//
+// Service composes the goal configuration (a cache.GoalCache, which is the goal definition
+// provider: target values, goal types, prerequisites, rewards) with a
+// repository.GoalRepository, so handlers pass events and IDs instead of resolving goal
+// definitions themselves. The repository stays public for operations the facade does not
+// cover.
+package service
+
+import (
+	"context"
+
+	"github.com/AccelByte/extend-challenge-common/pkg/cache"
+	"github.com/AccelByte/extend-challenge-common/pkg/client"
+	"github.com/AccelByte/extend-challenge-common/pkg/domain"
+	"github.com/AccelByte/extend-challenge-common/pkg/errors"
+	"github.com/AccelByte/extend-challenge-common/pkg/mapper"
+	"github.com/AccelByte/extend-challenge-common/pkg/repository"
+)
+
+// Service applies events, claims rewards and builds challenge boards for users.
+// It is safe for concurrent use when its dependencies are.
+type Service struct {
+	repo    repository.GoalRepository
+	goals   cache.GoalCache
+	rewards client.RewardClient
+}
+
+// NewService creates a Service backed by repo for progress, goals for goal definitions
+// and rewards for granting claimed rewards.
+func NewService(repo repository.GoalRepository, goals cache.GoalCache, rewards client.RewardClient) *Service {
+	return &Service{repo: repo, goals: goals, rewards: rewards}
+}
+
+// Repository returns the underlying repository for operations the Service does not cover.
+func (s *Service) Repository() repository.GoalRepository {
+	return s.repo
+}
+
+// ApplyStatisticEvent increments every enabled increment-like goal tracking the event's
+// stat code and returns the goals the event completed. Absolute goals are not updated:
+// they mirror a stat value, which the event does not carry (see mapper.BuildIncrements).
+func (s *Service) ApplyStatisticEvent(ctx context.Context, event mapper.StatEvent) ([]repository.CompletionResult, error) {
+	return s.applyIncrements(ctx, mapper.BuildIncrements(s.goals, event))
+}
+
+// ApplyLoginEvent increments every enabled login goal and returns the goals the login completed.
+func (s *Service) ApplyLoginEvent(ctx context.Context, event mapper.LoginEvent) ([]repository.CompletionResult, error) {
+	return s.applyIncrements(ctx, mapper.BuildLoginIncrements(s.goals, event))
+}
+
+func (s *Service) applyIncrements(ctx context.Context, increments []repository.ProgressIncrement) ([]repository.CompletionResult, error) {
+	if len(increments) == 0 {
+		return nil, nil
+	}
+	return s.repo.BatchIncrementProgressReturning(ctx, increments)
+}
+
+// Claim grants the rewards of a completed goal to the user and marks it claimed.
+//
+// The claim is two-phase: the goal is reserved, every reward is granted, and the
+// reservation is then confirmed. If a grant fails the reservation is cancelled so the
+// claim can be retried; rewards granted before the failure are granted again on retry,
+// so multi-reward goals rely on the reward client being idempotent.
+//
+// Returns ErrGoalNotFound for unknown goals, ErrGoalNotCompleted when the goal or one of
+// its prerequisites is not completed, ErrGoalAlreadyClaimed, ErrClaimInProgress,
+// ErrClaimWindowExpired, or ErrRewardGrantFailed.
+func (s *Service) Claim(ctx context.Context, userID, goalID string) error {
+	goal := s.goals.GetGoalByID(goalID)
+	if goal == nil {
+		return errors.ErrGoalNotFound(goalID)
+	}
+
+	eligibility, err := s.repo.GetClaimEligibility(ctx, userID, goalID, goal.Prerequisites)
+	if err != nil {
+		return err
+	}
+	if !eligibility.Eligible {
+		return ineligibleError(goal, eligibility)
+	}
+
+	if err := s.repo.ReserveClaimWithDeadline(ctx, userID, goalID, goal.ClaimDeadline.Std()); err != nil {
+		return err
+	}
+
+	namespace := eligibility.Progress.Namespace
+	for _, reward := range goal.AllRewards() {
+		if err := s.rewards.GrantReward(ctx, namespace, userID, reward); err != nil {
+			// Keep the grant error; a failed cancel is released by the reservation sweeper
+			_ = s.repo.CancelClaim(ctx, userID, goalID)
+			return errors.ErrRewardGrantFailed(reward.Type, reward.RewardID, err)
+		}
+	}
+
+	return s.repo.ConfirmClaim(ctx, userID, goalID)
+}
+
+// ineligibleError converts an ineligible claim into the error Claim returns for it.
+func ineligibleError(goal *domain.Goal, eligibility *repository.ClaimEligibility) error {
+	switch eligibility.Reason {
+	case repository.ClaimReasonAlreadyClaimed:
+		return errors.ErrGoalAlreadyClaimed(goal.ID)
+	case repository.ClaimReasonClaimInProgress:
+		return errors.ErrClaimInProgress(goal.ID)
+	case repository.ClaimReasonForfeited:
+		return errors.ErrClaimWindowExpired(goal.ID)
+	case repository.ClaimReasonPrereqIncomplete:
+		for _, prereqID := range goal.Prerequisites {
+			if status := eligibility.PrerequisiteStatuses[prereqID]; status != domain.GoalStatusCompleted &&
+				status != domain.GoalStatusClaiming && status != domain.GoalStatusClaimed {
+				return errors.ErrGoalNotCompleted(prereqID)
+			}
+		}
+		return errors.ErrGoalNotCompleted(goal.ID)
+	default:
+		return errors.ErrGoalNotCompleted(goal.ID)
+	}
+}
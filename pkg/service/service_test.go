@@ -0,0 +1,222 @@
+package service
+
+import (
+	"context"
+	stderrors "errors"
+	"io"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/mock"
+
+	"github.com/AccelByte/extend-challenge-common/pkg/cache"
+	"github.com/AccelByte/extend-challenge-common/pkg/client"
+	"github.com/AccelByte/extend-challenge-common/pkg/config"
+	"github.com/AccelByte/extend-challenge-common/pkg/domain"
+	"github.com/AccelByte/extend-challenge-common/pkg/errors"
+	"github.com/AccelByte/extend-challenge-common/pkg/mapper"
+	"github.com/AccelByte/extend-challenge-common/pkg/repository"
+)
+
+// stubRepository records the calls the Service makes. Methods the Service does not use
+// fall through to the nil embedded interface and panic.
+type stubRepository struct {
+	repository.GoalRepository
+
+	increments  []repository.ProgressIncrement
+	progress    []*domain.UserGoalProgress
+	eligibility *repository.ClaimEligibility
+	reserveErr  error
+	calls       []string
+}
+
+func (r *stubRepository) BatchIncrementProgressReturning(_ context.Context, increments []repository.ProgressIncrement) ([]repository.CompletionResult, error) {
+	r.increments = append(r.increments, increments...)
+	return []repository.CompletionResult{{UserID: increments[0].UserID, GoalID: increments[0].GoalID}}, nil
+}
+
+func (r *stubRepository) GetUserProgress(context.Context, string, bool) ([]*domain.UserGoalProgress, error) {
+	return r.progress, nil
+}
+
+func (r *stubRepository) GetClaimEligibility(context.Context, string, string, []string) (*repository.ClaimEligibility, error) {
+	return r.eligibility, nil
+}
+
+func (r *stubRepository) ReserveClaimWithDeadline(context.Context, string, string, time.Duration) error {
+	r.calls = append(r.calls, "reserve")
+	return r.reserveErr
+}
+
+func (r *stubRepository) ConfirmClaim(context.Context, string, string) error {
+	r.calls = append(r.calls, "confirm")
+	return nil
+}
+
+func (r *stubRepository) CancelClaim(context.Context, string, string) error {
+	r.calls = append(r.calls, "cancel")
+	return nil
+}
+
+func newTestService(repo *stubRepository, rewards client.RewardClient) *Service {
+	disabled := false
+	cfg := &config.Config{Challenges: []*domain.Challenge{{
+		ID: "challenge-1",
+		Goals: []*domain.Goal{
+			{
+				ID: "kills", ChallengeID: "challenge-1", Type: domain.GoalTypeIncrement, EventSource: domain.EventSourceStatistic,
+				Requirement: domain.Requirement{StatCode: "kills", Operator: ">=", TargetValue: 10},
+				Rewards: []domain.Reward{
+					{Type: "ITEM", RewardID: "sword", Quantity: 1},
+					{Type: "WALLET", RewardID: "GOLD", Quantity: 50},
+				},
+			},
+			{
+				ID: "logins", ChallengeID: "challenge-1", Type: domain.GoalTypeIncrement, EventSource: domain.EventSourceLogin,
+				Requirement:   domain.Requirement{Operator: ">=", TargetValue: 3},
+				Prerequisites: []string{"kills"},
+			},
+			{
+				ID: "retired", ChallengeID: "challenge-1", Type: domain.GoalTypeIncrement, EventSource: domain.EventSourceStatistic,
+				Requirement: domain.Requirement{StatCode: "kills", Operator: ">=", TargetValue: 1},
+				Enabled:     &disabled,
+			},
+		},
+	}}}
+	goals := cache.NewInMemoryGoalCache(cfg, "", slog.New(slog.NewTextHandler(io.Discard, nil)))
+	return NewService(repo, goals, rewards)
+}
+
+func assertErrorCode(t *testing.T, err error, code errors.Code) {
+	t.Helper()
+	var ce *errors.ChallengeError
+	if !stderrors.As(err, &ce) || ce.Code != code {
+		t.Errorf("Expected %s, got %v", code, err)
+	}
+}
+
+func TestService_ApplyEvents(t *testing.T) {
+	repo := &stubRepository{}
+	svc := newTestService(repo, client.NewMockRewardClient())
+	ctx := context.Background()
+
+	completed, err := svc.ApplyStatisticEvent(ctx, mapper.StatEvent{UserID: "user-1", Namespace: "ns", StatCode: "kills", Delta: 2})
+	if err != nil {
+		t.Fatalf("ApplyStatisticEvent failed: %v", err)
+	}
+	if len(completed) != 1 || len(repo.increments) != 1 {
+		t.Fatalf("ApplyStatisticEvent wrote %d increments, returned %d completions, want 1/1", len(repo.increments), len(completed))
+	}
+	if inc := repo.increments[0]; inc.GoalID != "kills" || inc.Delta != 2 || inc.TargetValue != 10 {
+		t.Errorf("increment = %+v, want kills +2 toward 10", inc)
+	}
+
+	if _, err := svc.ApplyLoginEvent(ctx, mapper.LoginEvent{UserID: "user-1", Namespace: "ns"}); err != nil {
+		t.Fatalf("ApplyLoginEvent failed: %v", err)
+	}
+	if inc := repo.increments[1]; inc.GoalID != "logins" || inc.TargetValue != 3 {
+		t.Errorf("login increment = %+v, want logins toward 3", inc)
+	}
+
+	// Events no goal tracks never reach the repository
+	completed, err = svc.ApplyStatisticEvent(ctx, mapper.StatEvent{UserID: "user-1", Namespace: "ns", StatCode: "unknown", Delta: 1})
+	if err != nil || completed != nil || len(repo.increments) != 2 {
+		t.Errorf("untracked stat: completed=%v err=%v increments=%d", completed, err, len(repo.increments))
+	}
+}
+
+func TestService_Claim(t *testing.T) {
+	ctx := context.Background()
+	eligible := &repository.ClaimEligibility{
+		Progress: &domain.UserGoalProgress{UserID: "user-1", GoalID: "kills", Namespace: "ns", Status: domain.GoalStatusCompleted},
+		Eligible: true,
+	}
+
+	t.Run("grants every reward then confirms", func(t *testing.T) {
+		repo := &stubRepository{eligibility: eligible}
+		rewards := client.NewMockRewardClient()
+		rewards.On("GrantReward", mock.Anything, "ns", "user-1", mock.Anything).Return(nil)
+
+		if err := newTestService(repo, rewards).Claim(ctx, "user-1", "kills"); err != nil {
+			t.Fatalf("Claim failed: %v", err)
+		}
+		rewards.AssertNumberOfCalls(t, "GrantReward", 2)
+		if len(repo.calls) != 2 || repo.calls[0] != "reserve" || repo.calls[1] != "confirm" {
+			t.Errorf("calls = %v, want [reserve confirm]", repo.calls)
+		}
+	})
+
+	t.Run("cancels the reservation when a grant fails", func(t *testing.T) {
+		repo := &stubRepository{eligibility: eligible}
+		rewards := client.NewMockRewardClient()
+		rewards.On("GrantReward", mock.Anything, "ns", "user-1", mock.Anything).Return(stderrors.New("platform unavailable"))
+
+		err := newTestService(repo, rewards).Claim(ctx, "user-1", "kills")
+		assertErrorCode(t, err, errors.ErrCodeRewardGrantFailed)
+		if len(repo.calls) != 2 || repo.calls[1] != "cancel" {
+			t.Errorf("calls = %v, want [reserve cancel]", repo.calls)
+		}
+	})
+
+	t.Run("rejections", func(t *testing.T) {
+		tests := []struct {
+			name        string
+			goalID      string
+			eligibility *repository.ClaimEligibility
+			reserveErr  error
+			wantCode    errors.Code
+		}{
+			{name: "unknown goal", goalID: "missing", wantCode: errors.ErrCodeGoalNotFound},
+			{name: "not completed", goalID: "kills", eligibility: &repository.ClaimEligibility{Reason: repository.ClaimReasonNotCompleted}, wantCode: errors.ErrCodeGoalNotCompleted},
+			{name: "already claimed", goalID: "kills", eligibility: &repository.ClaimEligibility{Reason: repository.ClaimReasonAlreadyClaimed}, wantCode: errors.ErrCodeGoalAlreadyClaimed},
+			{name: "claim in progress", goalID: "kills", eligibility: &repository.ClaimEligibility{Reason: repository.ClaimReasonClaimInProgress}, wantCode: errors.ErrCodeClaimInProgress},
+			{name: "forfeited", goalID: "kills", eligibility: &repository.ClaimEligibility{Reason: repository.ClaimReasonForfeited}, wantCode: errors.ErrCodeClaimWindowExpired},
+			{
+				name:   "prerequisite incomplete",
+				goalID: "logins",
+				eligibility: &repository.ClaimEligibility{
+					Reason:               repository.ClaimReasonPrereqIncomplete,
+					PrerequisiteStatuses: map[string]domain.GoalStatus{"kills": domain.GoalStatusInProgress},
+				},
+				wantCode: errors.ErrCodeGoalNotCompleted,
+			},
+			{name: "lost reservation race", goalID: "kills", eligibility: eligible, reserveErr: errors.ErrClaimInProgress("kills"), wantCode: errors.ErrCodeClaimInProgress},
+		}
+
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				repo := &stubRepository{eligibility: tt.eligibility, reserveErr: tt.reserveErr}
+				rewards := client.NewMockRewardClient()
+
+				assertErrorCode(t, newTestService(repo, rewards).Claim(ctx, "user-1", tt.goalID), tt.wantCode)
+				rewards.AssertNotCalled(t, "GrantReward", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+			})
+		}
+	})
+}
+
+func TestService_GetBoard(t *testing.T) {
+	repo := &stubRepository{progress: []*domain.UserGoalProgress{
+		{UserID: "user-1", GoalID: "kills", Progress: 10, Status: domain.GoalStatusCompleted, IsActive: true},
+	}}
+
+	board, err := newTestService(repo, client.NewMockRewardClient()).GetBoard(context.Background(), "user-1")
+	if err != nil {
+		t.Fatalf("GetBoard failed: %v", err)
+	}
+	if len(board.Challenges) != 1 {
+		t.Fatalf("GetBoard returned %d challenges, want 1", len(board.Challenges))
+	}
+
+	goals := board.Challenges[0].Goals
+	if len(goals) != 2 {
+		t.Fatalf("GetBoard returned %d goals, want 2 (disabled goal without progress hidden)", len(goals))
+	}
+	if goals[0].Goal.ID != "kills" || goals[0].Status != domain.GoalStatusCompleted || !goals[0].Claimable {
+		t.Errorf("kills entry = %+v, want completed and claimable", goals[0])
+	}
+	if goals[1].Goal.ID != "logins" || goals[1].Progress != nil || goals[1].Status != domain.GoalStatusNotStarted || goals[1].Claimable {
+		t.Errorf("logins entry = %+v, want not started", goals[1])
+	}
+}